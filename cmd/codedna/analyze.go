@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"codedna/internal/core/analysis/coverage"
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/dna"
+	goparser "codedna/internal/core/parser/golang"
+	"codedna/internal/external/filesystem"
+	"codedna/internal/external/store"
+)
+
+// Metrics printed in summaries and deltas
+var summaryMetrics = []gostructure.MetricType{
+	gostructure.MetricPackages, gostructure.MetricInterfaces, gostructure.MetricTypes,
+	gostructure.MetricFunctions, gostructure.MetricMethods, gostructure.MetricVariables,
+	gostructure.MetricImplements, gostructure.MetricEmbeds, gostructure.MetricReferences,
+}
+
+// Analyzes the project and prints a summary, optionally re-analyzing on changes
+func runAnalyze(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	watch := flags.Bool("watch", false, "watch the project and print the delta after each change, re-analyzing the changed packages")
+	debounce := flags.Duration("debounce", filesystem.DefaultDebounce, "quiet period before re-analyzing in watch mode")
+	save := flags.Bool("save", false, "save the analysis to the project store under "+store.DefaultDir)
+	coverProfile := flags.String("coverprofile", "", "attach the statement coverage of a go test -coverprofile file to the initial analysis")
+	generatedFlag := flags.String("generated", string(gostructure.GeneratedInclude), "treatment of generated code in metrics and DNA (include, exclude or separate)")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	generated, err := structure.ParseGeneratedCode(*generatedFlag)
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna analyze: %v\n", err)
+		return exitError
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+
+	start := time.Now()
+	workspace, err := loadWorkspace(root, commandLogger("analyze", stderr))
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna analyze: %v\n", err)
+		return exitError
+	}
+	if *coverProfile != "" {
+		profile, err := coverage.ParseFile(*coverProfile)
+		if err != nil {
+			fmt.Fprintf(stderr, "codedna analyze: %v\n", err)
+			return exitError
+		}
+		module, err := coverage.ReadModule(root)
+		if err != nil {
+			fmt.Fprintf(stderr, "codedna analyze: %v\n", err)
+			return exitError
+		}
+		coverage.Apply(workspace.Analysis().Structure, module, profile)
+	}
+	printSummary(stdout, workspace.Analysis(), generated)
+	if *save {
+		run, err := saveRun(root, workspace.Analysis(), generated, time.Since(start))
+		if err != nil {
+			fmt.Fprintf(stderr, "codedna analyze: %v\n", err)
+			return exitError
+		}
+		fmt.Fprintf(stdout, "Saved run %d\n", run.ID)
+	}
+	if !*watch {
+		return exitOK
+	}
+
+	watcher, err := filesystem.NewWatcher(root, goparser.New().FileExtensions(), *debounce)
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna analyze: %v\n", err)
+		return exitError
+	}
+	defer watcher.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(stdout, "Watching %s for changes (Ctrl+C to stop)\n", root)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return exitOK
+
+		case err := <-watcher.Errors():
+			fmt.Fprintf(stderr, "codedna analyze: watch error: %v\n", err)
+
+		case dirs := <-watcher.Changes():
+			before := workspace.Analysis()
+			start := time.Now()
+			after, err := workspace.Update(dirs)
+			fmt.Fprintf(stdout, "\n[%s] changed: %s\n", start.Format(time.TimeOnly), strings.Join(dirs, ", "))
+			if err != nil {
+				// Keep watching: the file is likely mid-edit
+				fmt.Fprintf(stdout, "  %v\n", err)
+				continue
+			}
+			printDelta(stdout, before, after, generated)
+			fmt.Fprintf(stdout, "  (%s)\n", time.Since(start).Round(time.Millisecond))
+		}
+	}
+}
+
+// Saves an analysis with its DNA profile to the project store, attached to the current git commit if any
+func saveRun(root string, analysis *gostructure.Analysis, generated structure.GeneratedCode, duration time.Duration) (*store.Run, error) {
+	analyzer := dna.NewGoAnalyzer()
+	analyzer.SetGenerated(generated)
+	profile, err := analyzer.Analyze(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build DNA profile: %w", err)
+	}
+
+	s, err := store.OpenProject(root)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	run := &store.Run{Root: root, Duration: duration, Generated: generated}
+	if commit := headCommit(root); commit != nil {
+		if err := s.SaveCommit(commit); err != nil {
+			return nil, err
+		}
+		run.Commit = commit.Hash
+	}
+	if err := s.SaveRun(run, analysis, profile); err != nil {
+		return nil, fmt.Errorf("failed to save run: %w", err)
+	}
+	return run, nil
+}
+
+// Returns the git HEAD commit of the repository containing root, or nil outside a repository
+func headCommit(root string) *store.Commit {
+	out, err := exec.Command("git", "-C", root, "log", "-1", "--format=%H%x00%an%x00%aI%x00%s").Output()
+	if err != nil {
+		return nil
+	}
+	fields := strings.SplitN(strings.TrimSpace(string(out)), "\x00", 4)
+	if len(fields) != 4 {
+		return nil
+	}
+	commit := &store.Commit{Hash: fields[0], Author: fields[1], Message: fields[3]}
+	commit.Time, _ = time.Parse(time.RFC3339, fields[2])
+	return commit
+}
+
+// Prints the element, relationship and metric counts of an analysis, with those of generated
+// code on their own line when it is reported separately, and the statement coverage if a
+// coverage profile was applied
+func printSummary(w io.Writer, analysis *gostructure.Analysis, generated structure.GeneratedCode) {
+	collector := gostructure.NewMetricsCollector()
+	collector.SetGenerated(generated)
+	collector.CollectMetrics(analysis.Structure)
+
+	fmt.Fprintf(w, "%d elements, %d relationships\n", len(analysis.Structure.Elements), len(analysis.Structure.Relationships))
+	fmt.Fprintf(w, "%s\n", formatMetrics(collector.Metric))
+	if generated == structure.GeneratedSeparate {
+		fmt.Fprintf(w, "generated: %s\n", formatMetrics(collector.GeneratedMetric))
+	}
+	if statements := collector.Metric(gostructure.MetricStatements); statements > 0 {
+		counts := coverage.Counts{Statements: statements, Covered: collector.Metric(gostructure.MetricCoveredStatements)}
+		fmt.Fprintf(w, "coverage: %s\n", formatCounts(counts))
+	}
+}
+
+// Formats the summary metrics as "name=value" pairs
+func formatMetrics(value func(gostructure.MetricType) int) string {
+	parts := make([]string, 0, len(summaryMetrics))
+	for _, metric := range summaryMetrics {
+		parts = append(parts, fmt.Sprintf("%s=%d", metric, value(metric)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Prints the elements, relationships and metrics that changed between two analyses
+func printDelta(w io.Writer, before, after *gostructure.Analysis, generated structure.GeneratedCode) {
+	diff := gostructure.DiffStructures(before.Structure, after.Structure)
+	for _, elem := range diff.AddedElements {
+		fmt.Fprintf(w, "  + %s %s (%s)\n", elem.Type, elem.Name, elem.Position)
+	}
+	for _, elem := range diff.RemovedElements {
+		fmt.Fprintf(w, "  - %s %s (%s)\n", elem.Type, elem.Name, elem.Position)
+	}
+	for _, rel := range diff.AddedRelationships {
+		fmt.Fprintf(w, "  + %s -[%s]-> %s\n", rel.Source.Name, rel.Type, rel.Target.Name)
+	}
+	for _, rel := range diff.RemovedRelationships {
+		fmt.Fprintf(w, "  - %s -[%s]-> %s\n", rel.Source.Name, rel.Type, rel.Target.Name)
+	}
+
+	old, current := gostructure.NewMetricsCollector(), gostructure.NewMetricsCollector()
+	old.SetGenerated(generated)
+	current.SetGenerated(generated)
+	old.CollectMetrics(before.Structure)
+	current.CollectMetrics(after.Structure)
+	var changes []string
+	for _, metric := range summaryMetrics {
+		if a, b := old.Metric(metric), current.Metric(metric); a != b {
+			changes = append(changes, fmt.Sprintf("%s %d -> %d", metric, a, b))
+		}
+	}
+	if len(changes) > 0 {
+		fmt.Fprintf(w, "  metrics: %s\n", strings.Join(changes, ", "))
+	}
+	if diff.Empty() && len(changes) == 0 {
+		fmt.Fprintln(w, "  no structural changes")
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"codedna/internal/core/analysis/boundary"
+	"codedna/internal/core/analysis/structure"
+)
+
+// Lists the boundaries between the languages and services of the project
+func runBoundaries(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("boundaries", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", formatTable, "output format (table or json)")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != formatTable && *format != formatJSON {
+		fmt.Fprintf(stderr, "codedna boundaries: unknown format %q\n", *format)
+		return exitError
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	project, err := analyzeProjectGraph(root, commandLogger("boundaries", stderr))
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna boundaries: %v\n", err)
+		return exitError
+	}
+	warnParseErrors(stderr, "boundaries", project)
+
+	detector := boundary.NewDetector()
+	contracts := append(boundary.ProtobufContracts(project), boundary.OpenAPIContracts(project)...)
+	for _, contract := range contracts {
+		detector.AddContract(contract)
+	}
+	result := detector.Detect(project)
+	if *format == formatJSON {
+		err = writeBoundariesJSON(stdout, result)
+	} else {
+		err = writeBoundariesTable(stdout, result)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna boundaries: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// Kinds shown for requests to endpoints the project does not serve, contract operations without
+// handlers and endpoints missing from the OpenAPI specifications
+const (
+	externalKind      = "external"
+	unimplementedKind = "unimplemented"
+	undocumentedKind  = "undocumented"
+)
+
+// Returns the name of a contract operation, as its endpoint when it is exposed over HTTP
+func operationName(op *boundary.Operation) string {
+	if op.Path == "" {
+		return op.Name
+	}
+	return fmt.Sprintf("%s %s (%s)", op.Method, op.Path, op.Name)
+}
+
+// Writes the boundaries as an aligned table, followed by the unmatched requests, unimplemented
+// operations and undocumented endpoints
+func writeBoundariesTable(w io.Writer, result *boundary.Result) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAME\tLANGUAGES\tELEMENTS")
+	for _, b := range result.Boundaries {
+		elements := make([]string, len(b.Elements))
+		for i, elem := range b.Elements {
+			elements[i] = fmt.Sprintf("%s (%s)", elem.Name, elem.Position)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", b.Kind, b.Name, strings.Join(b.Languages, ", "), strings.Join(elements, ", "))
+	}
+	for _, call := range result.Unmatched {
+		fmt.Fprintf(tw, "%s\t%s %s\t%s\t%s (%s)\n", externalKind, call.Method, call.URL, call.Caller.Language, call.Caller.Name, call.Position)
+	}
+	for _, match := range result.Operations {
+		if len(match.Handlers) == 0 {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", unimplementedKind, operationName(match.Operation), match.Contract.Kind, match.Contract.File)
+		}
+	}
+	for _, endpoint := range result.Undocumented {
+		fmt.Fprintf(tw, "%s\t%s %s\t%s\t%s (%s)\n", undocumentedKind, endpoint.Method, endpoint.Path, endpoint.Handler.Language, endpoint.Handler.Name, endpoint.Position)
+	}
+	return tw.Flush()
+}
+
+// A boundary in JSON output
+type jsonBoundary struct {
+	Kind      string        `json:"kind"`
+	Name      string        `json:"name"`
+	Languages []string      `json:"languages"`
+	Elements  []jsonElement `json:"elements"`
+	Calls     []jsonCall    `json:"calls,omitempty"`
+}
+
+// A request in JSON output
+type jsonCall struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Client   string      `json:"client"`
+	Caller   jsonElement `json:"caller"`
+	Position string      `json:"position"`
+}
+
+// Converts a request for JSON output
+func newJSONCall(call *boundary.Call) jsonCall {
+	return jsonCall{Method: call.Method, URL: call.URL, Client: call.Client, Caller: newJSONElement(call.Caller), Position: call.Position.String()}
+}
+
+// A contract operation in JSON output
+type jsonOperation struct {
+	Contract string        `json:"contract"`
+	Kind     string        `json:"kind"`
+	Name     string        `json:"name"`
+	Method   string        `json:"method,omitempty"`
+	Path     string        `json:"path,omitempty"`
+	Handlers []jsonElement `json:"handlers"`
+	Types    []jsonElement `json:"types"`
+}
+
+// An endpoint in JSON output
+type jsonEndpoint struct {
+	Method   string      `json:"method"`
+	Path     string      `json:"path"`
+	Handler  jsonElement `json:"handler"`
+	Position string      `json:"position"`
+}
+
+// Writes the boundaries, unmatched requests, contract operations and undocumented endpoints as JSON
+func writeBoundariesJSON(w io.Writer, result *boundary.Result) error {
+	output := struct {
+		Boundaries   []jsonBoundary  `json:"boundaries"`
+		Unmatched    []jsonCall      `json:"unmatched"`
+		Operations   []jsonOperation `json:"operations"`
+		Undocumented []jsonEndpoint  `json:"undocumented"`
+	}{
+		Boundaries:   make([]jsonBoundary, 0, len(result.Boundaries)),
+		Unmatched:    make([]jsonCall, 0, len(result.Unmatched)),
+		Operations:   make([]jsonOperation, 0, len(result.Operations)),
+		Undocumented: make([]jsonEndpoint, 0, len(result.Undocumented)),
+	}
+	for _, b := range result.Boundaries {
+		jb := jsonBoundary{Kind: string(b.Kind), Name: b.Name, Languages: b.Languages, Elements: jsonElements(b.Elements)}
+		for _, call := range b.Calls {
+			jb.Calls = append(jb.Calls, newJSONCall(call))
+		}
+		output.Boundaries = append(output.Boundaries, jb)
+	}
+	for _, call := range result.Unmatched {
+		output.Unmatched = append(output.Unmatched, newJSONCall(call))
+	}
+	for _, match := range result.Operations {
+		op := match.Operation
+		output.Operations = append(output.Operations, jsonOperation{
+			Contract: match.Contract.Name, Kind: match.Contract.Kind, Name: op.Name, Method: op.Method, Path: op.Path,
+			Handlers: jsonElements(match.Handlers), Types: jsonElements(match.Types),
+		})
+	}
+	for _, endpoint := range result.Undocumented {
+		output.Undocumented = append(output.Undocumented, jsonEndpoint{
+			Method: endpoint.Method, Path: endpoint.Path, Handler: newJSONElement(endpoint.Handler), Position: endpoint.Position.String(),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output)
+}
+
+// Converts elements for JSON output
+func jsonElements(elements []*structure.Element) []jsonElement {
+	result := make([]jsonElement, len(elements))
+	for i, elem := range elements {
+		result[i] = newJSONElement(elem)
+	}
+	return result
+}
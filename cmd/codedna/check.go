@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"codedna/internal/core/config"
+	"codedna/internal/core/report"
+	"codedna/internal/core/rules"
+)
+
+// Checks the project against the rules file, exiting non-zero on violations
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	rulesFile := flags.String("rules", "", "path to the rules file (defaults to "+config.DefaultRulesFile+" in the project directory)")
+	format := flags.String("format", formatText, "output format (text or sarif)")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != formatText && *format != formatSARIF {
+		fmt.Fprintf(stderr, "codedna check: unknown format %q\n", *format)
+		return exitError
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	if *rulesFile == "" {
+		*rulesFile = filepath.Join(root, config.DefaultRulesFile)
+	}
+
+	ruleSet, err := config.LoadRules(*rulesFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna check: %v\n", err)
+		return exitError
+	}
+	engine, err := rules.NewEngine(ruleSet)
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna check: invalid rules: %v\n", err)
+		return exitError
+	}
+
+	analysis, err := analyzeProject(root, commandLogger("check", stderr))
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna check: %v\n", err)
+		return exitError
+	}
+
+	violations := engine.Check(analysis.Structure, root)
+	if *format == formatSARIF {
+		reporter := report.NewSARIFReporter(toolName, toolVersion, root)
+		reporter.SetInformationURI(informationURI)
+		ruleList, findings := report.FromViolations(violations)
+		if err := reporter.Write(stdout, ruleList, findings); err != nil {
+			fmt.Fprintf(stderr, "codedna check: %v\n", err)
+			return exitError
+		}
+	} else {
+		for _, v := range violations {
+			fmt.Fprintf(stdout, "%s: [%s] %s\n", v.Position, v.Rule, v.Message)
+		}
+		if len(violations) > 0 {
+			fmt.Fprintf(stdout, "%d violation(s)\n", len(violations))
+		}
+	}
+	if len(violations) > 0 {
+		return exitViolations
+	}
+	return exitOK
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+
+	"codedna/internal/core/analysis/coverage"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/external/store"
+)
+
+// Reports the statement coverage of the project from a coverage profile, or its trend over the
+// saved runs
+func runCoverage(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	profilePath := flags.String("profile", "", "coverage profile written by go test -coverprofile")
+	history := flags.Bool("history", false, "show the coverage of the runs saved with analyze -save -coverprofile instead")
+	format := flags.String("format", formatTable, "output format (table or json)")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != formatTable && *format != formatJSON {
+		fmt.Fprintf(stderr, "codedna coverage: unknown format %q\n", *format)
+		return exitError
+	}
+	if (*profilePath == "") == !*history {
+		fmt.Fprintln(stderr, "Usage: codedna coverage [-format table|json] (-profile <file> | -history) [dir]")
+		return exitError
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+
+	var err error
+	if *history {
+		err = writeCoverageHistory(stdout, root, *format)
+	} else {
+		err = writeCoverage(stdout, commandLogger("coverage", stderr), root, *profilePath, *format)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna coverage: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// Applies a coverage profile to the project and writes the coverage report
+func writeCoverage(w io.Writer, log *zap.Logger, root, path, format string) error {
+	profile, err := coverage.ParseFile(path)
+	if err != nil {
+		return err
+	}
+	module, err := coverage.ReadModule(root)
+	if err != nil {
+		return err
+	}
+	analysis, err := analyzeProject(root, log)
+	if err != nil {
+		return err
+	}
+	report := coverage.Apply(analysis.Structure, module, profile)
+	if format == formatJSON {
+		return writeCoverageJSON(w, report)
+	}
+	return writeCoverageTable(w, report)
+}
+
+// Formats statement counts as a percentage with the counts
+func formatCounts(counts coverage.Counts) string {
+	return fmt.Sprintf("%.1f%% (%d/%d)", 100*counts.Ratio(), counts.Covered, counts.Statements)
+}
+
+// Writes the package coverage as an aligned table, followed by the untested exported functions,
+// untested interface implementations and the profile files outside the project
+func writeCoverageTable(w io.Writer, report *coverage.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tDIR\tCOVERAGE")
+	for _, pkg := range report.Packages {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", pkg.Name, pkg.Dir, formatCounts(pkg.Counts))
+	}
+	fmt.Fprintf(tw, "total\t\t%s\n", formatCounts(report.Total))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(report.UntestedExported) > 0 {
+		fmt.Fprintln(w, "\nUntested exported functions and methods:")
+		for _, fn := range report.UntestedExported {
+			fmt.Fprintf(w, "  %s (%s)\n", fn.Name(), fn.Element.Position)
+		}
+	}
+	if len(report.UntestedImplementations) > 0 {
+		fmt.Fprintln(w, "\nUntested interface implementations:")
+		for _, impl := range report.UntestedImplementations {
+			fmt.Fprintf(w, "  %s implements %s (%s)\n", impl.Method.Name(), impl.Interface.Name, impl.Method.Element.Position)
+		}
+	}
+	if len(report.Unmatched) > 0 {
+		fmt.Fprintln(w, "\nProfile files outside the project:")
+		for _, name := range report.Unmatched {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+	return nil
+}
+
+// Package coverage in JSON output
+type jsonPackageCoverage struct {
+	Name string `json:"name"`
+	Dir  string `json:"dir"`
+	coverage.Counts
+}
+
+// A function or method coverage in JSON output
+type jsonFunctionCoverage struct {
+	Name      string      `json:"name"`
+	Element   jsonElement `json:"element"`
+	Interface string      `json:"interface,omitempty"` // The implemented interface
+	coverage.Counts
+}
+
+// Writes the coverage report as JSON
+func writeCoverageJSON(w io.Writer, report *coverage.Report) error {
+	output := struct {
+		Mode                    string                 `json:"mode"`
+		Total                   coverage.Counts        `json:"total"`
+		Packages                []jsonPackageCoverage  `json:"packages"`
+		UntestedExported        []jsonFunctionCoverage `json:"untested_exported"`
+		UntestedImplementations []jsonFunctionCoverage `json:"untested_implementations"`
+		Unmatched               []string               `json:"unmatched"`
+	}{
+		Mode:                    report.Mode,
+		Total:                   report.Total,
+		Packages:                make([]jsonPackageCoverage, 0, len(report.Packages)),
+		UntestedExported:        make([]jsonFunctionCoverage, 0, len(report.UntestedExported)),
+		UntestedImplementations: make([]jsonFunctionCoverage, 0, len(report.UntestedImplementations)),
+		Unmatched:               append(make([]string, 0, len(report.Unmatched)), report.Unmatched...),
+	}
+	for _, pkg := range report.Packages {
+		output.Packages = append(output.Packages, jsonPackageCoverage{Name: pkg.Name, Dir: pkg.Dir, Counts: pkg.Counts})
+	}
+	for _, fn := range report.UntestedExported {
+		output.UntestedExported = append(output.UntestedExported, jsonFunctionCoverage{
+			Name: fn.Name(), Element: newJSONElement(fn.Element), Counts: fn.Counts,
+		})
+	}
+	for _, impl := range report.UntestedImplementations {
+		output.UntestedImplementations = append(output.UntestedImplementations, jsonFunctionCoverage{
+			Name: impl.Method.Name(), Element: newJSONElement(impl.Method.Element), Interface: impl.Interface.Name, Counts: impl.Method.Counts,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output)
+}
+
+// The coverage of a saved run
+type coveragePoint struct {
+	RunID  uint64    `json:"run_id"`
+	Commit string    `json:"commit,omitempty"`
+	Time   time.Time `json:"time"`
+	coverage.Counts
+}
+
+// Writes the coverage of the saved runs that have it, oldest first
+func writeCoverageHistory(w io.Writer, root, format string) error {
+	s, err := store.OpenProject(root)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	statements, err := s.MetricHistory(gostructure.MetricStatements)
+	if err != nil {
+		return err
+	}
+	covered, err := s.MetricHistory(gostructure.MetricCoveredStatements)
+	if err != nil {
+		return err
+	}
+	points := make([]coveragePoint, 0, len(statements))
+	for i, point := range statements {
+		if point.Value == 0 {
+			continue // Saved without a coverage profile
+		}
+		points = append(points, coveragePoint{
+			RunID:  point.RunID,
+			Commit: point.Commit,
+			Time:   point.Time,
+			Counts: coverage.Counts{Statements: point.Value, Covered: covered[i].Value},
+		})
+	}
+
+	if format == formatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(points)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN\tCOMMIT\tTIME\tCOVERAGE")
+	for _, point := range points {
+		commit := point.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", point.RunID, commit, point.Time.Format(time.DateTime), formatCounts(point.Counts))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"codedna/internal/core/analysis/debt"
+	"codedna/internal/core/config"
+	"codedna/internal/core/report"
+)
+
+// Scores the technical debt of the project and prints the elements and packages carrying the most
+func runAnalyzeDebt(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("analyze-debt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", formatText, "output format (text, json or sarif)")
+	top := flags.Int("top", 20, "number of elements and packages to list in text output (0 for all)")
+	weights := flags.String("weights", "", "comma-separated signal weights overriding the config (e.g. complexity=2,missing_docs=0)")
+	since := flags.String("churn-since", "6 months ago", "count the commits changing each file since this date (empty to skip churn)")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != formatText && *format != formatJSON && *format != formatSARIF {
+		fmt.Fprintf(stderr, "codedna analyze-debt: unknown format %q\n", *format)
+		return exitError
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+
+	debtConfig, err := loadDebtConfig(*weights)
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna analyze-debt: %v\n", err)
+		return exitError
+	}
+	analysis, err := analyzeProject(root, commandLogger("analyze-debt", stderr))
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna analyze-debt: %v\n", err)
+		return exitError
+	}
+
+	scorer := debt.NewScorer(debtConfig)
+	if *since != "" {
+		scorer.SetChurn(gitChurn(root, *since))
+	}
+	result := scorer.Score(analysis.Structure)
+
+	switch *format {
+	case formatJSON:
+		err = writeDebtJSON(stdout, result)
+	case formatSARIF:
+		reporter := report.NewSARIFReporter(toolName, toolVersion, root)
+		reporter.SetInformationURI(informationURI)
+		ruleList, findings := report.FromDebt(result)
+		err = reporter.Write(stdout, ruleList, findings)
+	default:
+		err = writeDebtText(stdout, result, *top)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna analyze-debt: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// Returns the debt configuration: the defaults, overridden by the analysis.debt config section
+// and then by the weights flag
+func loadDebtConfig(weights string) (*debt.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	debtConfig := debt.DefaultConfig()
+	if err := debtConfig.Configure(cfg.Analysis.Debt.Weights, cfg.Analysis.Debt.Thresholds); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	overrides := make(map[string]float64)
+	for _, pair := range strings.Split(weights, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid weight %q, expected signal=value", pair)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight %q: %w", pair, err)
+		}
+		overrides[name] = weight
+	}
+	if err := debtConfig.Configure(overrides, nil); err != nil {
+		return nil, err
+	}
+	return debtConfig, nil
+}
+
+// Returns the number of commits changing each file of the git repository containing root since a
+// date, keyed by absolute path. Outside a repository there is no churn.
+func gitChurn(root, since string) map[string]int {
+	churn := make(map[string]int)
+	top, err := exec.Command("git", "-C", root, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return churn
+	}
+	toplevel := strings.TrimSpace(string(top))
+	out, err := exec.Command("git", "-C", root, "log", "--since="+since, "--name-only", "--format=").Output()
+	if err != nil {
+		return churn
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			churn[filepath.Join(toplevel, filepath.FromSlash(name))]++
+		}
+	}
+	return churn
+}
+
+// Writes the total debt and the highest scoring packages and elements, with the explanation of
+// each element's score
+func writeDebtText(w io.Writer, result *debt.Report, top int) error {
+	fmt.Fprintf(w, "Technical debt: %.2f points in %d elements of %d packages\n", result.Score, len(result.Elements), len(result.Packages))
+	if len(result.Elements) == 0 {
+		return nil
+	}
+
+	packages, elements := result.Packages, result.Elements
+	if top > 0 {
+		packages, elements = packages[:min(top, len(packages))], elements[:min(top, len(elements))]
+	}
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCORE\tPACKAGE\tDIR\tELEMENTS\tTOP SIGNAL")
+	for _, pkg := range packages {
+		fmt.Fprintf(tw, "%.2f\t%s\t%s\t%d\t%s\n", pkg.Score, pkg.Name, pkg.Dir, pkg.Elements, topSignal(pkg.Signals))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCORE\tELEMENT\tLOCATION")
+	for _, elem := range elements {
+		fmt.Fprintf(tw, "%.2f\t%s %s\t%s\n", elem.Score, elem.Element.Type, elem.Name, elem.Element.Position)
+		for _, item := range elem.Items {
+			fmt.Fprintf(tw, "\t  +%.2f %s\t%s\n", item.Points, item.Signal, item.Reason)
+		}
+	}
+	return tw.Flush()
+}
+
+// Returns the signal adding the most points, in signal order on ties
+func topSignal(points map[debt.Signal]float64) debt.Signal {
+	var best debt.Signal
+	for _, signal := range debt.Signals {
+		if points[signal] > points[best] {
+			best = signal
+		}
+	}
+	return best
+}
+
+// A debt item in JSON output
+type jsonDebtItem struct {
+	Signal    debt.Signal `json:"signal"`
+	Value     int         `json:"value"`
+	Threshold int         `json:"threshold"`
+	Points    float64     `json:"points"`
+	Reason    string      `json:"reason"`
+}
+
+// An element's debt in JSON output
+type jsonElementDebt struct {
+	Name    string         `json:"name"`
+	Element jsonElement    `json:"element"`
+	Dir     string         `json:"dir"`
+	Score   float64        `json:"score"`
+	Items   []jsonDebtItem `json:"items"`
+}
+
+// A package's debt in JSON output
+type jsonPackageDebt struct {
+	Name     string                  `json:"name"`
+	Dir      string                  `json:"dir"`
+	Score    float64                 `json:"score"`
+	Elements int                     `json:"elements"`
+	Signals  map[debt.Signal]float64 `json:"signals"`
+}
+
+// Writes the debt of every element and package as JSON, highest score first
+func writeDebtJSON(w io.Writer, result *debt.Report) error {
+	output := struct {
+		Score    float64           `json:"score"`
+		Packages []jsonPackageDebt `json:"packages"`
+		Elements []jsonElementDebt `json:"elements"`
+	}{
+		Score:    result.Score,
+		Packages: make([]jsonPackageDebt, 0, len(result.Packages)),
+		Elements: make([]jsonElementDebt, 0, len(result.Elements)),
+	}
+	for _, pkg := range result.Packages {
+		output.Packages = append(output.Packages, jsonPackageDebt{
+			Name: pkg.Name, Dir: pkg.Dir, Score: pkg.Score, Elements: pkg.Elements, Signals: pkg.Signals,
+		})
+	}
+	for _, elem := range result.Elements {
+		items := make([]jsonDebtItem, 0, len(elem.Items))
+		for _, item := range elem.Items {
+			items = append(items, jsonDebtItem(*item))
+		}
+		output.Elements = append(output.Elements, jsonElementDebt{
+			Name: elem.Name, Element: newJSONElement(elem.Element), Dir: elem.Dir, Score: elem.Score, Items: items,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/logger"
+	"codedna/internal/external/lsp"
+)
+
+// Runs the language server over stdin and stdout
+func runLSP(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lsp", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	logFile := flags.String("log-file", "", "write debug logs to this file (stdout carries the protocol)")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	cfg := logger.Config{Level: "error", Format: "console", Component: "lsp", Output: "file", File: os.DevNull}
+	if *logFile != "" {
+		cfg.Level, cfg.File = "debug", *logFile
+	}
+	log, err := logger.New(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna lsp: %v\n", err)
+		return exitError
+	}
+	defer log.Sync()
+
+	load := func(root string) (*gostructure.Workspace, error) { return loadWorkspace(root, log) }
+	if err := lsp.New(load, log).Run(os.Stdin, stdout); err != nil {
+		fmt.Fprintf(stderr, "codedna lsp: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
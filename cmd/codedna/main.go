@@ -2,8 +2,82 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
 )
 
+// Exit codes
+const (
+	exitOK         = 0
+	exitViolations = 1 // The check found violations
+	exitError      = 2 // The command could not run
+)
+
+// Tool metadata reported in machine-readable output
+const (
+	toolName       = "codedna"
+	toolVersion    = "0.1.0"
+	informationURI = "https://github.com/thread-koder/codedna"
+)
+
+// Output formats
+const (
+	formatText  = "text"
+	formatTable = "table"
+	formatJSON  = "json"
+	formatSARIF = "sarif"
+)
+
+// A CLI subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+// Returns the available subcommands
+func commands() []*command {
+	return []*command{
+		{name: "analyze", summary: "Analyze the project structure, optionally watching for changes", run: runAnalyze},
+		{name: "analyze-debt", summary: "Score technical debt per element and package, highest first", run: runAnalyzeDebt},
+		{name: "check", summary: "Check the project against architecture rules", run: runCheck},
+		{name: "query", summary: "Query the code structure graph", run: runQuery},
+		{name: "boundaries", summary: "List HTTP, contract and shape boundaries between languages", run: runBoundaries},
+		{name: "coverage", summary: "Report test coverage from a coverage profile, or its history", run: runCoverage},
+		{name: "report", summary: "Generate an HTML report of the project DNA", run: runReport},
+		{name: "serve", summary: "Serve analyses over a local HTTP JSON API", run: runServe},
+		{name: "lsp", summary: "Run a language server over stdio for editor integration", run: runLSP},
+	}
+}
+
 func main() {
-	fmt.Println("Starting CodeDNA analysis...")
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// Runs the CLI with the given arguments, returning the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+	usage(stderr)
+	return exitError
+}
+
+// Prints the CLI usage
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: codedna <command> [flags] [dir]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-13s %s\n", cmd.name, cmd.summary)
+	}
 }
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	openapistructure "codedna/internal/core/analysis/structure/openapi"
+	protostructure "codedna/internal/core/analysis/structure/proto"
+	pystructure "codedna/internal/core/analysis/structure/python"
+	tsstructure "codedna/internal/core/analysis/structure/typescript"
+	"codedna/internal/core/config"
+	"codedna/internal/core/logger"
+	"codedna/internal/core/parser"
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+	openapiparser "codedna/internal/core/parser/openapi"
+	protoparser "codedna/internal/core/parser/proto"
+	pyparser "codedna/internal/core/parser/python"
+	tsparser "codedna/internal/core/parser/typescript"
+	"codedna/internal/external/filesystem"
+)
+
+// Parses and analyzes all Go packages under root into a single analysis, reporting detector
+// runs and configuration warnings to log
+func analyzeProject(root string, log *zap.Logger) (*gostructure.Analysis, error) {
+	workspace, err := loadWorkspace(root, log)
+	if err != nil {
+		return nil, err
+	}
+	return workspace.Analysis(), nil
+}
+
+// Parses and analyzes all Go packages under root into a workspace that can be updated. The
+// detectors are enabled and disabled by the analysis.detectors config section.
+func loadWorkspace(root string, log *zap.Logger) (*gostructure.Workspace, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	analyzer := gostructure.NewAnalyzer()
+	analyzer.SetLogger(log)
+	if !goparser.StdlibTypes() {
+		log.Warn("Go toolchain not found, standard library calls are analyzed without types")
+	}
+	if err := analyzer.Registry().Configure(cfg.Analysis.Detectors); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	dirs, err := filesystem.NewScanner().Dirs(root, goparser.New().FileExtensions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	workspace := gostructure.NewWorkspace(analyzer)
+	if _, err := workspace.Update(dirs); err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// Analyzes the parsed modules of a language analyzed alongside Go
+type moduleAnalyzer struct {
+	language string   // Language of the parser producing the modules
+	skipped  []string // Names of directories holding build output rather than sources
+	analyze  func(modules []ast.Node) (structure.Analysis, error)
+}
+
+// Analyzers of the languages analyzed alongside Go, in the order they are merged
+var moduleAnalyzers = []moduleAnalyzer{
+	{language: pyparser.New().Language(), analyze: func(modules []ast.Node) (structure.Analysis, error) {
+		nodes := make([]structure.Node, 0, len(modules))
+		for _, module := range modules {
+			nodes = append(nodes, pystructure.NewNode(module))
+		}
+		return pystructure.NewAnalyzer().AnalyzeAll(nodes)
+	}},
+	{language: tsparser.New().Language(), skipped: []string{"dist", "build"}, analyze: func(modules []ast.Node) (structure.Analysis, error) {
+		nodes := make([]structure.Node, 0, len(modules))
+		for _, module := range modules {
+			nodes = append(nodes, tsstructure.NewNode(module))
+		}
+		return tsstructure.NewAnalyzer().AnalyzeAll(nodes)
+	}},
+	{language: protoparser.New().Language(), analyze: func(modules []ast.Node) (structure.Analysis, error) {
+		nodes := make([]structure.Node, 0, len(modules))
+		for _, module := range modules {
+			nodes = append(nodes, protostructure.NewNode(module))
+		}
+		return protostructure.NewAnalyzer().AnalyzeAll(nodes)
+	}},
+	{language: openapiparser.New().Language(), analyze: func(modules []ast.Node) (structure.Analysis, error) {
+		nodes := make([]structure.Node, 0, len(modules))
+		for _, module := range modules {
+			nodes = append(nodes, openapistructure.NewNode(module))
+		}
+		return openapistructure.NewAnalyzer().AnalyzeAll(nodes)
+	}},
+}
+
+// Returns the parsers of the languages analyzed alongside Go
+func newParserRegistry() *parser.Registry {
+	registry := parser.NewRegistry()
+	registry.Register(pyparser.New())
+	registry.Register(tsparser.New())
+	registry.Register(protoparser.New())
+	registry.Register(openapiparser.New())
+	return registry
+}
+
+// Parses and analyzes the Go packages and the modules of the other supported languages
+// (including OpenAPI specifications) under root, merging them into a single project-wide graph.
+// Go code generated from or implementing .proto files is linked to their definitions. Modules
+// that fail to parse are left out and recorded in the project's parse errors.
+func analyzeProjectGraph(root string, log *zap.Logger) (*structure.Project, error) {
+	analysis, err := analyzeProject(root, log)
+	if err != nil {
+		return nil, err
+	}
+	project := structure.NewProject()
+	if err := project.Add(analysis); err != nil {
+		return nil, err
+	}
+
+	registry := newParserRegistry()
+	scanner := filesystem.NewScanner()
+	for _, analyzer := range moduleAnalyzers {
+		p, ok := registry.Get(analyzer.language)
+		if !ok {
+			return nil, fmt.Errorf("no parser registered for %s", analyzer.language)
+		}
+		dirs, err := scanner.Dirs(root, p.FileExtensions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+		if len(dirs) == 0 {
+			continue
+		}
+
+		var modules []ast.Node
+		for _, dir := range dirs {
+			if buildOutput(root, dir, analyzer.skipped) {
+				continue
+			}
+			parsed, errs, err := parseModules(p, dir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", dir, err)
+			}
+			modules = append(modules, parsed...)
+			project.ParseErrors = append(project.ParseErrors, errs...)
+		}
+		languageAnalysis, err := analyzer.analyze(modules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze %s code: %w", analyzer.language, err)
+		}
+		if err := project.Add(languageAnalysis); err != nil {
+			return nil, err
+		}
+	}
+	protostructure.Link(project)
+	return project, nil
+}
+
+// Parses the modules of a directory one file at a time, returning the errors of the files that
+// could not be parsed apart from the error reading the directory
+func parseModules(p parser.Parser, dir string) ([]ast.Node, []error, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var modules []ast.Node
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(p.FileExtensions(), filepath.Ext(entry.Name())) {
+			continue
+		}
+		module, err := p.ParseFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		modules = append(modules, module)
+	}
+	return modules, errs, nil
+}
+
+// Checks if a directory under root is inside a directory with one of the skipped names
+func buildOutput(root, dir string, skipped []string) bool {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if slices.Contains(skipped, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the logger of an analysis command, at the level and format of the log.global config
+// section. It writes to stderr, since stdout carries the command's results.
+func commandLogger(command string, stderr io.Writer) *zap.Logger {
+	cfg := logger.Config{Component: command}
+	if c, err := config.Load(); err == nil {
+		cfg.Level, cfg.Format = c.Log.Global.Level, c.Log.Global.Format
+	}
+	return logger.NewWriter(cfg, stderr)
+}
+
+// Reports the modules left out of a project graph because they could not be parsed
+func warnParseErrors(stderr io.Writer, command string, project *structure.Project) {
+	for _, err := range project.ParseErrors {
+		fmt.Fprintf(stderr, "codedna %s: skipped unparsable file: %v\n", command, err)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"codedna/internal/core/analysis/structure"
+	"codedna/internal/core/query"
+)
+
+// Runs a structure query against the project
+func runQuery(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", formatTable, "output format (table or json)")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != formatTable && *format != formatJSON {
+		fmt.Fprintf(stderr, "codedna query: unknown format %q\n", *format)
+		return exitError
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "Usage: codedna query [-format table|json] <query> [dir]")
+		return exitError
+	}
+
+	q, err := query.Parse(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna query: %v\n", err)
+		return exitError
+	}
+
+	root := "."
+	if flags.NArg() > 1 {
+		root = flags.Arg(1)
+	}
+	project, err := analyzeProjectGraph(root, commandLogger("query", stderr))
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna query: %v\n", err)
+		return exitError
+	}
+	warnParseErrors(stderr, "query", project)
+
+	result := q.Evaluate(project.Structure)
+	if *format == formatJSON {
+		err = writeQueryJSON(stdout, result)
+	} else {
+		err = writeQueryTable(stdout, result)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna query: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// Writes the result as an aligned table
+func writeQueryTable(w io.Writer, result *query.Result) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(result.Columns, "\t")))
+	for _, row := range result.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = query.FormatCell(cell)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// A result element in JSON output
+type jsonElement struct {
+	ID       string `json:"id"`
+	Language string `json:"language"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Position string `json:"position"`
+}
+
+// Converts an element for JSON output
+func newJSONElement(elem *structure.Element) jsonElement {
+	return jsonElement{ID: string(elem.ID()), Language: elem.Language, Type: string(elem.Type), Name: elem.Name, Position: elem.Position.String()}
+}
+
+// Writes the result as a JSON array of objects keyed by column
+func writeQueryJSON(w io.Writer, result *query.Result) error {
+	rows := make([]map[string]any, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		obj := make(map[string]any, len(row))
+		for i, cell := range row {
+			switch v := cell.(type) {
+			case *structure.Element:
+				obj[result.Columns[i]] = newJSONElement(v)
+			case fmt.Stringer:
+				obj[result.Columns[i]] = v.String()
+			default:
+				obj[result.Columns[i]] = v
+			}
+		}
+		rows = append(rows, obj)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
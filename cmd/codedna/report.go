@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"codedna/internal/core/dna"
+	"codedna/internal/core/report"
+)
+
+// Generates reports of the project's DNA
+func runReport(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	htmlDir := flags.String("html", "", "write a static HTML site into this directory")
+	title := flags.String("title", "", "title of the report (defaults to the project directory name)")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *htmlDir == "" {
+		fmt.Fprintln(stderr, "Usage: codedna report -html <dir> [-title <title>] [dir]")
+		return exitError
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	if *title == "" {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			abs = root
+		}
+		*title = "CodeDNA: " + filepath.Base(abs)
+	}
+
+	analysis, err := analyzeProject(root, commandLogger("report", stderr))
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna report: %v\n", err)
+		return exitError
+	}
+	profile, err := dna.NewGoAnalyzer().Analyze(analysis)
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna report: %v\n", err)
+		return exitError
+	}
+
+	if err := report.NewHTMLReporter(*title, root).Write(*htmlDir, analysis, profile); err != nil {
+		fmt.Fprintf(stderr, "codedna report: %v\n", err)
+		return exitError
+	}
+	fmt.Fprintf(stdout, "Report written to %s\n", filepath.Join(*htmlDir, "index.html"))
+	return exitOK
+}
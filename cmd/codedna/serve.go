@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/external/server"
+)
+
+// Default address of the API server; local only
+const defaultServeAddr = "127.0.0.1:7420"
+
+// Runs the local HTTP API server until interrupted
+func runServe(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", defaultServeAddr, "address to listen on (requests must still be addressed to a loopback host)")
+	maxAnalyses := flags.Int("max-analyses", server.DefaultMaxAnalyses, "number of analyses kept in memory before the oldest are dropped")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	log := commandLogger("serve", stderr)
+	defer log.Sync()
+
+	// Detector runs are logged alongside the requests
+	analyze := func(root string) (*gostructure.Analysis, error) { return analyzeProject(root, log) }
+	srv := server.New(analyze, log)
+	srv.SetMaxAnalyses(*maxAnalyses)
+	// Analyze the given directories up front so they are available right away
+	for _, dir := range flags.Args() {
+		id, err := srv.Analyze(dir)
+		if err != nil {
+			fmt.Fprintf(stderr, "codedna serve: %v\n", err)
+			return exitError
+		}
+		fmt.Fprintf(stdout, "Analyzed %s as analysis %s\n", dir, id)
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "codedna serve: %v\n", err)
+		return exitError
+	}
+	httpServer := &http.Server{Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(stdout, "Serving the CodeDNA API on http://%s/api/analyses\n", listener.Addr())
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "codedna serve: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
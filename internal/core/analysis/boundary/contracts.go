@@ -0,0 +1,106 @@
+package boundary
+
+import (
+	"codedna/internal/core/analysis/structure"
+	openapiparser "codedna/internal/core/parser/openapi"
+	protoparser "codedna/internal/core/parser/proto"
+)
+
+// Returns a contract per protobuf package of the project, with its RPCs as operations (exposed
+// over HTTP when they have a google.api.http option) and its messages with their JSON names
+func ProtobufContracts(project *structure.Project) []*Contract {
+	byFile := make(map[string]*Contract)
+	byPackage := make(map[string]*Contract)
+	var result []*Contract
+	for _, elem := range project.Structure.Elements {
+		if elem.Language != "proto" {
+			continue
+		}
+		if elem.Type == structure.ElementPackage {
+			if contract, ok := byPackage[elem.Name]; ok {
+				byFile[elem.Scope] = contract
+				continue
+			}
+			contract := &Contract{Kind: ContractProtobuf, Name: elem.Name, File: elem.Position.Filename}
+			byPackage[elem.Name] = contract
+			byFile[elem.Scope] = contract
+			result = append(result, contract)
+			continue
+		}
+
+		contract, ok := byFile[elem.Scope]
+		if !ok {
+			continue
+		}
+		switch elem.Attributes["kind"] {
+		case protoparser.KindMessage:
+			message := &Message{Name: elem.Name}
+			fields, _ := elem.Attributes["fields"].([]map[string]any)
+			for _, field := range fields {
+				if name, ok := field["json_name"].(string); ok {
+					message.Fields = append(message.Fields, name)
+				}
+			}
+			contract.Messages = append(contract.Messages, message)
+		case protoparser.KindRPC:
+			op := &Operation{Name: elem.Name}
+			for _, key := range []string{"request_type", "response_type"} {
+				if typ, ok := elem.Attributes[key].(*protoparser.TypeInfo); ok {
+					op.Messages = append(op.Messages, typ.BaseName())
+				}
+			}
+			if rule, ok := elem.Attributes["http"].(*protoparser.HTTPRule); ok && rule != nil {
+				op.Method, op.Path = rule.Method, rule.Path
+			}
+			contract.Operations = append(contract.Operations, op)
+		}
+	}
+	return result
+}
+
+// Returns a contract per OpenAPI specification of the project, with its operations and schemas
+func OpenAPIContracts(project *structure.Project) []*Contract {
+	byFile := make(map[string]*Contract)
+	var result []*Contract
+	for _, elem := range project.Structure.Elements {
+		if elem.Language != "openapi" {
+			continue
+		}
+		if elem.Type == structure.ElementPackage {
+			basePath, _ := elem.Attributes["base_path"].(string)
+			contract := &Contract{Kind: ContractOpenAPI, Name: elem.Name, File: elem.Position.Filename, BasePath: basePath}
+			byFile[elem.Scope] = contract
+			result = append(result, contract)
+			continue
+		}
+
+		contract, ok := byFile[elem.Scope]
+		if !ok {
+			continue
+		}
+		switch elem.Attributes["kind"] {
+		case openapiparser.KindSchema:
+			message := &Message{Name: elem.Name}
+			fields, _ := elem.Attributes["fields"].([]map[string]any)
+			for _, field := range fields {
+				if name, ok := field["name"].(string); ok {
+					message.Fields = append(message.Fields, name)
+				}
+			}
+			contract.Messages = append(contract.Messages, message)
+		case openapiparser.KindOperation:
+			op := &Operation{Name: elem.Name}
+			op.Method, _ = elem.Attributes["method"].(string)
+			op.Path, _ = elem.Attributes["path"].(string)
+			types, _ := elem.Attributes["response_types"].([]*openapiparser.TypeInfo)
+			if request, ok := elem.Attributes["request_type"].(*openapiparser.TypeInfo); ok {
+				types = append([]*openapiparser.TypeInfo{request}, types...)
+			}
+			for _, typ := range types {
+				op.Messages = append(op.Messages, typ.Schemas()...)
+			}
+			contract.Operations = append(contract.Operations, op)
+		}
+	}
+	return result
+}
@@ -0,0 +1,429 @@
+package boundary
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"codedna/internal/core/analysis/structure"
+	goparser "codedna/internal/core/parser/golang"
+	pyparser "codedna/internal/core/parser/python"
+	tsparser "codedna/internal/core/parser/typescript"
+)
+
+// Finds the boundaries of a project: HTTP endpoints served by Go handlers and requested from
+// other languages, contracts implemented or used in the project, and types sharing a JSON shape
+type Detector struct {
+	contracts      []*Contract
+	minShapeFields int
+}
+
+// Creates a detector. Types share a shape when they have at least two fields in common.
+func NewDetector() *Detector {
+	return &Detector{minShapeFields: 2}
+}
+
+// Adds a contract to correlate with the project
+func (d *Detector) AddContract(contract *Contract) {
+	d.contracts = append(d.contracts, contract)
+}
+
+// Sets the number of JSON fields types need to be compared by shape
+func (d *Detector) SetMinShapeFields(n int) {
+	d.minShapeFields = n
+}
+
+// Detects the boundaries of the project. Requests matched to endpoints and types sharing a
+// shape are also added to the project graph, as requests and shares_shape relationships.
+func (d *Detector) Detect(project *structure.Project) *Result {
+	result := &Result{
+		Boundaries:   make([]*Boundary, 0),
+		Endpoints:    make([]*Endpoint, 0),
+		Unmatched:    make([]*Call, 0),
+		Operations:   make([]*OperationMatch, 0),
+		Undocumented: make([]*Endpoint, 0),
+	}
+	elements := project.Structure.Elements
+	result.Endpoints = endpoints(elements)
+	calls := httpCalls(elements)
+
+	result.Boundaries = append(result.Boundaries, d.detectHTTP(project, result, calls)...)
+	result.Boundaries = append(result.Boundaries, d.detectContracts(result, elements, calls)...)
+	result.Boundaries = append(result.Boundaries, d.detectShapes(project)...)
+	return result
+}
+
+// Matches requests to the endpoints serving them
+func (d *Detector) detectHTTP(project *structure.Project, result *Result, calls []*Call) []*Boundary {
+	byEndpoint := make(map[*Endpoint]*Boundary)
+	var boundaries []*Boundary
+	for _, call := range calls {
+		endpoint := matchEndpoint(result.Endpoints, call.Method, parseURL(call.URL))
+		if endpoint == nil {
+			result.Unmatched = append(result.Unmatched, call)
+			continue
+		}
+		b, ok := byEndpoint[endpoint]
+		if !ok {
+			b = &Boundary{Kind: KindHTTP, Name: endpointName(endpoint), Endpoint: endpoint}
+			b.add(endpoint.Handler)
+			byEndpoint[endpoint] = b
+			boundaries = append(boundaries, b)
+		}
+		b.Calls = append(b.Calls, call)
+		b.add(call.Caller)
+		project.Relate(structure.RelationRequests, call.Caller, endpoint.Handler)
+	}
+	sort.SliceStable(boundaries, func(i, j int) bool {
+		a, b := boundaries[i].Endpoint, boundaries[j].Endpoint
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return boundaries
+}
+
+// Finds the elements implementing or using each contract: types matching its messages,
+// handlers and callers of its HTTP operations, and functions named after its operations. The
+// Go handlers and types of each operation are added to the result, and when a contract is an
+// OpenAPI document, so are the endpoints no OpenAPI operation describes.
+func (d *Detector) detectContracts(result *Result, elements []*structure.Element, calls []*Call) []*Boundary {
+	var boundaries []*Boundary
+	documented := make(map[*Endpoint]bool)
+	hasOpenAPI := false
+	for _, contract := range d.contracts {
+		hasOpenAPI = hasOpenAPI || contract.Kind == ContractOpenAPI
+		b := &Boundary{Kind: KindContract, Name: contract.Name, Contract: contract}
+		types := make(map[string][]*structure.Element) // Message name -> matching types
+		for _, message := range contract.Messages {
+			for _, elem := range elements {
+				if d.matchesMessage(elem, message) {
+					types[message.Name] = append(types[message.Name], elem)
+					b.add(elem)
+				}
+			}
+		}
+
+		for _, op := range contract.Operations {
+			match := &OperationMatch{Contract: contract, Operation: op}
+			if op.Path != "" {
+				routes := operationRoutes(contract, op)
+				for _, endpoint := range result.Endpoints {
+					route := parseRoute(endpoint.Path)
+					if methodMatches(endpoint.Method, op.Method) && slices.ContainsFunc(routes, route.equal) {
+						documented[endpoint] = true
+						match.Handlers = appendElement(match.Handlers, endpoint.Handler)
+						b.add(endpoint.Handler)
+					}
+				}
+				for _, call := range calls {
+					url := parseURL(call.URL)
+					if methodMatches(op.Method, call.Method) && slices.ContainsFunc(routes, func(route pathPattern) bool { return url.score(route) >= 0 }) {
+						b.add(call.Caller)
+					}
+				}
+			}
+			if op.Name != "" {
+				for _, elem := range elements {
+					if (elem.Type == structure.ElementMethod || elem.Type == structure.ElementFunction) && strings.EqualFold(elem.Name, op.Name) {
+						b.add(elem)
+						if elem.Language == "go" {
+							match.Handlers = appendElement(match.Handlers, elem)
+						}
+					}
+				}
+			}
+			for _, message := range op.Messages {
+				for _, elem := range types[message] {
+					if elem.Language == "go" {
+						match.Types = appendElement(match.Types, elem)
+					}
+				}
+			}
+			result.Operations = append(result.Operations, match)
+		}
+		if len(b.Elements) > 0 {
+			boundaries = append(boundaries, b)
+		}
+	}
+
+	if hasOpenAPI {
+		for _, endpoint := range result.Endpoints {
+			if !documented[endpoint] {
+				result.Undocumented = append(result.Undocumented, endpoint)
+			}
+		}
+	}
+	return boundaries
+}
+
+// Checks if a type matches a contract message: it has the message's name and fields in common
+// with it, or the same JSON fields under another name
+func (d *Detector) matchesMessage(elem *structure.Element, message *Message) bool {
+	if !isShapeType(elem) {
+		return false
+	}
+	fields := jsonFields(elem)
+	if strings.EqualFold(elem.Name, message.Name) {
+		return sharesFields(fields, message.Fields)
+	}
+	return len(fields) >= d.minShapeFields && slices.Equal(fields, normalizeFields(message.Fields))
+}
+
+// Returns the routes an operation may be registered under: its path, and its path without the
+// contract's base path
+func operationRoutes(contract *Contract, op *Operation) []pathPattern {
+	routes := []pathPattern{parseRoute(op.Path)}
+	if rest, ok := strings.CutPrefix(op.Path, contract.BasePath); ok && contract.BasePath != "" {
+		routes = append(routes, parseRoute(rest))
+	}
+	return routes
+}
+
+// Appends an element unless it is already in the list
+func appendElement(elements []*structure.Element, elem *structure.Element) []*structure.Element {
+	if slices.Contains(elements, elem) {
+		return elements
+	}
+	return append(elements, elem)
+}
+
+// Groups types of different languages whose JSON fields are the same, or which have the same
+// name and the fields of one include those of the other
+func (d *Detector) detectShapes(project *structure.Project) []*Boundary {
+	type shaped struct {
+		elem   *structure.Element
+		fields []string
+	}
+	var types []shaped
+	for _, elem := range project.Structure.Elements {
+		if !isShapeType(elem) {
+			continue
+		}
+		if fields := jsonFields(elem); len(fields) >= d.minShapeFields {
+			types = append(types, shaped{elem: elem, fields: fields})
+		}
+	}
+
+	// Union types sharing a shape, keeping the first type of each group as its root
+	parent := make([]int, len(types))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range types {
+		for j := i + 1; j < len(types); j++ {
+			a, b := types[i], types[j]
+			if a.elem.Language == b.elem.Language {
+				continue
+			}
+			same := slices.Equal(a.fields, b.fields)
+			related := strings.EqualFold(a.elem.Name, b.elem.Name) && (isSubset(a.fields, b.fields) || isSubset(b.fields, a.fields))
+			if same || related {
+				if ri, rj := find(i), find(j); ri != rj {
+					parent[max(ri, rj)] = min(ri, rj)
+				}
+			}
+		}
+	}
+
+	groups := make(map[int]*Boundary)
+	var boundaries []*Boundary
+	for i, t := range types {
+		root := find(i)
+		if root == i {
+			continue
+		}
+		b, ok := groups[root]
+		if !ok {
+			b = &Boundary{Kind: KindShape, Name: types[root].elem.Name}
+			b.add(types[root].elem)
+			groups[root] = b
+			boundaries = append(boundaries, b)
+		}
+		b.add(t.elem)
+		project.Relate(structure.RelationSharesShape, t.elem, types[root].elem)
+	}
+	return boundaries
+}
+
+// Returns the endpoints registered by the Go functions and methods
+func endpoints(elements []*structure.Element) []*Endpoint {
+	handlers := make(map[string][]*structure.Element)
+	for _, elem := range elements {
+		if elem.Language == "go" && (elem.Type == structure.ElementFunction || elem.Type == structure.ElementMethod) {
+			handlers[elem.Name] = append(handlers[elem.Name], elem)
+		}
+	}
+
+	result := make([]*Endpoint, 0)
+	for _, elem := range elements {
+		routes, _ := elem.Attributes["http_routes"].([]*goparser.HTTPRoute)
+		for _, route := range routes {
+			result = append(result, &Endpoint{
+				Method:    route.Method,
+				Path:      route.Path,
+				Framework: route.Framework,
+				Handler:   resolveHandler(handlers, route.Handler, elem),
+				Position:  route.Position,
+			})
+		}
+	}
+	return result
+}
+
+// Resolves a handler expression (e.g. "h.getUser", "http.HandlerFunc(h.deleteUser)") to the
+// function or method it names, preferring the package registering the route. Handlers that
+// cannot be resolved (e.g. function literals) are attributed to the registering function.
+func resolveHandler(handlers map[string][]*structure.Element, expr string, registrar *structure.Element) *structure.Element {
+	expr = strings.TrimRight(expr, ")")
+	expr = expr[strings.LastIndex(expr, "(")+1:]
+	name := expr[strings.LastIndex(expr, ".")+1:]
+	candidates := handlers[name]
+	for _, candidate := range candidates {
+		if candidate.Scope == registrar.Scope {
+			return candidate
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+	return registrar
+}
+
+// Returns the HTTP requests made by the functions and methods of other languages
+func httpCalls(elements []*structure.Element) []*Call {
+	result := make([]*Call, 0)
+	for _, elem := range elements {
+		switch calls := elem.Attributes["http_calls"].(type) {
+		case []*pyparser.HTTPCall:
+			for _, c := range calls {
+				result = append(result, &Call{Method: c.Method, URL: c.URL, Client: c.Client, Caller: elem, Position: c.Position})
+			}
+		case []*tsparser.HTTPCall:
+			for _, c := range calls {
+				result = append(result, &Call{Method: c.Method, URL: c.URL, Client: c.Client, Caller: elem, Position: c.Position})
+			}
+		}
+	}
+	return result
+}
+
+// Returns the endpoint best matching a request, or nil
+func matchEndpoint(endpoints []*Endpoint, method string, path pathPattern) *Endpoint {
+	var best *Endpoint
+	bestScore := -1
+	for _, endpoint := range endpoints {
+		if !methodMatches(endpoint.Method, method) {
+			continue
+		}
+		score := path.score(parseRoute(endpoint.Path))
+		if score > bestScore || score == bestScore && score >= 0 && best.Method == "" {
+			best, bestScore = endpoint, score
+		}
+	}
+	return best
+}
+
+// Checks if an endpoint or operation accepting a method serves a request with another.
+// An empty method accepts any method.
+func methodMatches(accepted, method string) bool {
+	return accepted == "" || method == "" || strings.EqualFold(accepted, method)
+}
+
+// Returns the name of an endpoint (e.g. "GET /api/users/{id}", "* /health")
+func endpointName(endpoint *Endpoint) string {
+	method := endpoint.Method
+	if method == "" {
+		method = "*"
+	}
+	return method + " " + endpoint.Path
+}
+
+// Checks if an element is a type whose fields can describe a JSON shape
+func isShapeType(elem *structure.Element) bool {
+	return elem.Type == structure.ElementTypeDecl || elem.Type == structure.ElementInterface
+}
+
+// Returns the sorted JSON field names of a type, normalized so that naming conventions of
+// different languages compare equal (user_id, userId and UserID all become userid). Go structs
+// only have a JSON shape if one of their fields has a json tag.
+func jsonFields(elem *structure.Element) []string {
+	fields, _ := elem.Attributes["fields"].([]map[string]any)
+	names := make([]string, 0, len(fields))
+	tagged := false
+	for _, field := range fields {
+		name, _ := field["name"].(string)
+		if embedded, _ := field["embedded"].(bool); embedded || name == "" {
+			continue
+		}
+		if static, _ := field["is_static"].(bool); static {
+			continue
+		}
+		if elem.Language == "go" {
+			if tags, _ := field["tags"].(map[string]*goparser.StructTag); tags["json"] != nil {
+				tagged = true
+				if tags["json"].Name == "-" {
+					continue
+				}
+				if tags["json"].Name != "" {
+					name = tags["json"].Name
+				}
+			}
+		}
+		if exported, _ := field["is_exported"].(bool); !exported {
+			continue
+		}
+		names = append(names, normalizeField(name))
+	}
+	if elem.Language == "go" && !tagged {
+		return nil
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// Normalizes a field name for comparison across naming conventions
+func normalizeField(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// Returns the sorted, normalized names of fields
+func normalizeFields(names []string) []string {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		result = append(result, normalizeField(name))
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// Checks if a type has fields in common with a message, or either has no known fields
+func sharesFields(fields, messageFields []string) bool {
+	if len(fields) == 0 || len(messageFields) == 0 {
+		return true
+	}
+	for _, field := range messageFields {
+		if slices.Contains(fields, normalizeField(field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if all sorted fields of a are in b
+func isSubset(a, b []string) bool {
+	for _, field := range a {
+		if _, found := slices.BinarySearch(b, field); !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,280 @@
+package boundary_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/analysis/boundary"
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	openapistructure "codedna/internal/core/analysis/structure/openapi"
+	protostructure "codedna/internal/core/analysis/structure/proto"
+	pystructure "codedna/internal/core/analysis/structure/python"
+	tsstructure "codedna/internal/core/analysis/structure/typescript"
+	openapiparser "codedna/internal/core/parser/openapi"
+	protoparser "codedna/internal/core/parser/proto"
+	pyparser "codedna/internal/core/parser/python"
+	tsparser "codedna/internal/core/parser/typescript"
+)
+
+// Helper function to merge the Go server, TypeScript web client and Python worker in testdata
+func loadProject(t *testing.T) *structure.Project {
+	t.Helper()
+	project := structure.NewProject()
+
+	goAnalysis, err := gostructure.NewWorkspace(gostructure.NewAnalyzer()).Update([]string{filepath.Join("testdata", "server")})
+	if err != nil {
+		t.Fatalf("Failed to analyze Go code: %v", err)
+	}
+
+	modules, err := tsparser.New().ParseDir(filepath.Join("testdata", "web"))
+	if err != nil {
+		t.Fatalf("Failed to parse TypeScript code: %v", err)
+	}
+	tsNodes := make([]structure.Node, 0, len(modules))
+	for _, module := range modules {
+		tsNodes = append(tsNodes, tsstructure.NewNode(module))
+	}
+	tsAnalysis, err := tsstructure.NewAnalyzer().AnalyzeAll(tsNodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze TypeScript code: %v", err)
+	}
+
+	modules, err = pyparser.New().ParseDir(filepath.Join("testdata", "worker"))
+	if err != nil {
+		t.Fatalf("Failed to parse Python code: %v", err)
+	}
+	pyNodes := make([]structure.Node, 0, len(modules))
+	for _, module := range modules {
+		pyNodes = append(pyNodes, pystructure.NewNode(module))
+	}
+	pyAnalysis, err := pystructure.NewAnalyzer().AnalyzeAll(pyNodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze Python code: %v", err)
+	}
+
+	for _, analysis := range []structure.Analysis{goAnalysis, tsAnalysis, pyAnalysis} {
+		if err := project.Add(analysis); err != nil {
+			t.Fatalf("Failed to add %s analysis: %v", analysis.Language(), err)
+		}
+	}
+	return project
+}
+
+// Helper function to describe elements as "language:name"
+func names(elements []*structure.Element) []string {
+	var result []string
+	for _, elem := range elements {
+		result = append(result, elem.Language+":"+elem.Name)
+	}
+	return result
+}
+
+// Helper function to list relationships of a type as "source->target"
+func relationships(project *structure.Project, relType structure.RelationType) []string {
+	var result []string
+	for _, rel := range project.Structure.Relationships {
+		if rel.Type == relType {
+			result = append(result, rel.Source.Language+":"+rel.Source.Name+"->"+rel.Target.Language+":"+rel.Target.Name)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+func TestDetector(t *testing.T) {
+	project := loadProject(t)
+	result := boundary.NewDetector().Detect(project)
+
+	byKind := make(map[boundary.Kind][]*boundary.Boundary)
+	for _, b := range result.Boundaries {
+		byKind[b.Kind] = append(byKind[b.Kind], b)
+	}
+
+	t.Run("Endpoints", func(t *testing.T) {
+		var got []string
+		for _, endpoint := range result.Endpoints {
+			got = append(got, fmt.Sprintf("%s %s %s", endpoint.Method, endpoint.Path, endpoint.Handler.Name))
+		}
+		expected := []string{
+			"GET /api/users listUsers",
+			"POST /api/users createUser",
+			"GET /api/users/{id} getUser",
+			"GET /api/users/me currentUser",
+			"GET /health Routes",
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("Expected endpoints %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		got := make(map[string][]string)
+		for _, b := range byKind[boundary.KindHTTP] {
+			got[b.Name] = names(b.Elements)
+		}
+		expected := map[string][]string{
+			"GET /api/users":      {"go:listUsers", "python:sync_users"},
+			"POST /api/users":     {"go:createUser", "typescript:createUser"},
+			"GET /api/users/{id}": {"go:getUser", "typescript:getUser"},
+			"GET /api/users/me":   {"go:currentUser", "typescript:currentUser"},
+			"GET /health":         {"go:Routes", "python:sync_users"},
+		}
+		if len(got) != len(expected) {
+			t.Errorf("Expected %d HTTP boundaries, got %v", len(expected), got)
+		}
+		for name, elements := range expected {
+			if !slices.Equal(got[name], elements) {
+				t.Errorf("%s: expected elements %v, got %v", name, elements, got[name])
+			}
+		}
+
+		if len(result.Unmatched) != 1 || result.Unmatched[0].Caller.Name != "weather" {
+			t.Errorf("Expected the weather request to be unmatched, got %v", result.Unmatched)
+		}
+
+		expectedRelationships := []string{
+			"python:sync_users->go:Routes",
+			"python:sync_users->go:listUsers",
+			"typescript:createUser->go:createUser",
+			"typescript:currentUser->go:currentUser",
+			"typescript:getUser->go:getUser",
+		}
+		if got := relationships(project, structure.RelationRequests); !slices.Equal(got, expectedRelationships) {
+			t.Errorf("Expected requests relationships %v, got %v", expectedRelationships, got)
+		}
+	})
+
+	t.Run("Shapes", func(t *testing.T) {
+		shapes := byKind[boundary.KindShape]
+		if len(shapes) != 1 {
+			t.Fatalf("Expected 1 shape boundary, got %d", len(shapes))
+		}
+		expected := []string{"go:User", "typescript:User", "python:Account"}
+		if got := names(shapes[0].Elements); !slices.Equal(got, expected) {
+			t.Errorf("Expected types %v, got %v", expected, got)
+		}
+		if !slices.Equal(shapes[0].Languages, []string{"go", "typescript", "python"}) {
+			t.Errorf("Expected languages go, typescript and python, got %v", shapes[0].Languages)
+		}
+		expectedRelationships := []string{"python:Account->go:User", "typescript:User->go:User"}
+		if got := relationships(project, structure.RelationSharesShape); !slices.Equal(got, expectedRelationships) {
+			t.Errorf("Expected shares_shape relationships %v, got %v", expectedRelationships, got)
+		}
+	})
+
+	t.Run("Contracts", func(t *testing.T) {
+		detector := boundary.NewDetector()
+		detector.AddContract(&boundary.Contract{
+			Kind: boundary.ContractOpenAPI,
+			Name: "Users API",
+			Operations: []*boundary.Operation{
+				{Name: "getUser", Method: "GET", Path: "/api/users/{userId}"},
+			},
+			Messages: []*boundary.Message{{Name: "User", Fields: []string{"id", "full_name"}}},
+		})
+		detector.AddContract(&boundary.Contract{Kind: boundary.ContractProtobuf, Name: "billing.v1"})
+
+		var contracts []*boundary.Boundary
+		for _, b := range detector.Detect(loadProject(t)).Boundaries {
+			if b.Kind == boundary.KindContract {
+				contracts = append(contracts, b)
+			}
+		}
+		if len(contracts) != 1 || contracts[0].Name != "Users API" {
+			t.Fatalf("Expected only the Users API contract to be used, got %v", contracts)
+		}
+		expected := []string{"go:User", "typescript:User", "go:getUser", "typescript:getUser", "typescript:currentUser"}
+		if got := names(contracts[0].Elements); !slices.Equal(got, expected) {
+			t.Errorf("Expected elements %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("ProtobufContracts", func(t *testing.T) {
+		project := loadProject(t)
+		module, err := protoparser.New().ParseFile(filepath.Join("testdata", "proto", "users.proto"))
+		if err != nil {
+			t.Fatalf("Failed to parse proto file: %v", err)
+		}
+		analysis, err := protostructure.NewAnalyzer().Analyze(protostructure.NewNode(module))
+		if err != nil {
+			t.Fatalf("Failed to analyze proto file: %v", err)
+		}
+		if err := project.Add(analysis); err != nil {
+			t.Fatalf("Failed to add proto analysis: %v", err)
+		}
+
+		contracts := boundary.ProtobufContracts(project)
+		if len(contracts) != 1 || contracts[0].Kind != boundary.ContractProtobuf || contracts[0].Name != "acme.users.v1" {
+			t.Fatalf("Expected the acme.users.v1 contract, got %v", contracts)
+		}
+		contract := contracts[0]
+		if op := contract.Operations; len(op) != 1 || op[0].Name != "GetUser" || op[0].Method != "GET" || op[0].Path != "/api/users/{id}" ||
+			!slices.Equal(op[0].Messages, []string{"GetUserRequest", "User"}) {
+			t.Errorf("Expected the GetUser operation, got %v", contract.Operations)
+		}
+		if len(contract.Messages) != 2 || !slices.Equal(contract.Messages[0].Fields, []string{"id", "fullName"}) {
+			t.Errorf("Expected User and GetUserRequest messages, got %v", contract.Messages)
+		}
+
+		detector := boundary.NewDetector()
+		detector.AddContract(contract)
+		for _, b := range detector.Detect(project).Boundaries {
+			if b.Kind != boundary.KindContract {
+				continue
+			}
+			expected := []string{"go:User", "typescript:User", "proto:User", "proto:GetUserRequest", "go:getUser", "typescript:getUser", "typescript:currentUser", "proto:GetUser"}
+			if got := names(b.Elements); !slices.Equal(got, expected) {
+				t.Errorf("Expected elements %v, got %v", expected, got)
+			}
+		}
+	})
+
+	t.Run("OpenAPI", func(t *testing.T) {
+		project := loadProject(t)
+		module, err := openapiparser.New().ParseFile(filepath.Join("testdata", "openapi", "users.yaml"))
+		if err != nil {
+			t.Fatalf("Failed to parse specification: %v", err)
+		}
+		analysis, err := openapistructure.NewAnalyzer().Analyze(openapistructure.NewNode(module))
+		if err != nil {
+			t.Fatalf("Failed to analyze specification: %v", err)
+		}
+		if err := project.Add(analysis); err != nil {
+			t.Fatalf("Failed to add OpenAPI analysis: %v", err)
+		}
+
+		contracts := boundary.OpenAPIContracts(project)
+		if len(contracts) != 1 || contracts[0].Kind != boundary.ContractOpenAPI || contracts[0].BasePath != "/v1" {
+			t.Fatalf("Expected the Users API contract with base path /v1, got %v", contracts)
+		}
+		detector := boundary.NewDetector()
+		detector.AddContract(contracts[0])
+		result := detector.Detect(project)
+
+		var operations []string
+		for _, match := range result.Operations {
+			operations = append(operations, fmt.Sprintf("%s %s %s handlers=%v types=%v", match.Operation.Method, match.Operation.Path,
+				match.Operation.Name, names(match.Handlers), names(match.Types)))
+		}
+		expected := []string{
+			"GET /v1/api/users listUsers handlers=[go:listUsers] types=[go:User]",
+			"POST /v1/api/users createUser handlers=[go:createUser] types=[go:User]",
+			"GET /v1/api/users/{userId} getUser handlers=[go:getUser] types=[go:User]",
+			"DELETE /v1/api/users/{userId} deleteUser handlers=[] types=[]",
+		}
+		if !slices.Equal(operations, expected) {
+			t.Errorf("Expected operations %q, got %q", expected, operations)
+		}
+
+		var undocumented []string
+		for _, endpoint := range result.Undocumented {
+			undocumented = append(undocumented, endpoint.Method+" "+endpoint.Path)
+		}
+		if expected := []string{"GET /api/users/me", "GET /health"}; !slices.Equal(undocumented, expected) {
+			t.Errorf("Expected undocumented endpoints %v, got %v", expected, undocumented)
+		}
+	})
+}
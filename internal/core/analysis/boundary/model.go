@@ -0,0 +1,113 @@
+// Package boundary correlates code structures across language and service boundaries
+package boundary
+
+import (
+	"codedna/internal/core/analysis/structure"
+	"codedna/internal/core/parser/ast"
+)
+
+// The kind of a boundary
+type Kind string
+
+const (
+	KindHTTP     Kind = "http"     // An HTTP endpoint served in Go and requested from other languages
+	KindContract Kind = "contract" // A protobuf or OpenAPI contract implemented or used by the project
+	KindShape    Kind = "shape"    // Types of several languages sharing a JSON shape
+)
+
+// Kinds of contracts
+const (
+	ContractProtobuf = "protobuf"
+	ContractOpenAPI  = "openapi"
+)
+
+// An HTTP endpoint served by a Go handler
+type Endpoint struct {
+	Method    string // Empty if the endpoint accepts any method
+	Path      string // The route pattern (e.g. "/api/users/{id}")
+	Framework string // The routing package (e.g. "chi")
+	Handler   *structure.Element
+	Position  ast.Position // Where the route is registered
+}
+
+// An HTTP request made by a client
+type Call struct {
+	Method   string
+	URL      string // The requested URL, with non-literal parts written as {}
+	Client   string // The client expression (e.g. "axios", "fetch")
+	Caller   *structure.Element
+	Position ast.Position
+}
+
+// A contract shared between services, such as a protobuf file or an OpenAPI document
+type Contract struct {
+	Kind       string // ContractProtobuf or ContractOpenAPI
+	Name       string // The protobuf package or API title
+	File       string
+	BasePath   string // The path under which HTTP operations are served, if the routes may omit it (e.g. "/v1")
+	Operations []*Operation
+	Messages   []*Message
+}
+
+// An operation of a contract
+type Operation struct {
+	Name     string   // The RPC name or operation ID
+	Method   string   // The HTTP method, for operations exposed over HTTP
+	Path     string   // The HTTP path, for operations exposed over HTTP
+	Messages []string // The request and response messages
+}
+
+// A contract operation and the elements serving it
+type OperationMatch struct {
+	Contract  *Contract
+	Operation *Operation
+	Handlers  []*structure.Element // Go handlers of the operation's endpoint, or Go functions and methods named after it
+	Types     []*structure.Element // Go types matching the operation's request and response messages
+}
+
+// A message or schema of a contract
+type Message struct {
+	Name   string
+	Fields []string // JSON field names
+}
+
+// A boundary and the elements on each side of it
+type Boundary struct {
+	Kind      Kind
+	Name      string   // The endpoint (e.g. "GET /api/users/{id}"), contract or shared type name
+	Languages []string // Languages of the elements, in order of appearance
+	Elements  []*structure.Element
+	Endpoint  *Endpoint // The served endpoint, for HTTP boundaries
+	Calls     []*Call   // The requests to the endpoint, for HTTP boundaries
+	Contract  *Contract // The contract, for contract boundaries
+}
+
+// The boundaries found in a project
+type Result struct {
+	Boundaries []*Boundary
+	Endpoints  []*Endpoint // All endpoints served by the project
+	Unmatched  []*Call     // Requests to endpoints the project does not serve (e.g. external APIs)
+
+	// The operations of each contract, with their handlers. Operations without handlers are not
+	// implemented by the project.
+	Operations []*OperationMatch
+
+	// Endpoints that no OpenAPI contract documents, when the project has one
+	Undocumented []*Endpoint
+}
+
+// Adds an element to the boundary, recording its language
+func (b *Boundary) add(elem *structure.Element) {
+	for _, existing := range b.Elements {
+		if existing == elem {
+			return
+		}
+	}
+	b.Elements = append(b.Elements, elem)
+	for _, language := range b.Languages {
+		if language == elem.Language {
+			return
+		}
+	}
+	b.Languages = append(b.Languages, elem.Language)
+}
@@ -0,0 +1,99 @@
+package boundary
+
+import (
+	"slices"
+	"strings"
+)
+
+// A URL path split into segments, with parameters normalized to {}
+type pathPattern struct {
+	segments []string
+	partial  bool // The path follows an unknown base URL, so it may match the end of a route
+}
+
+// Parses a route pattern (e.g. "/users/{id}", "/users/:id", "/files/*path")
+func parseRoute(path string) pathPattern {
+	return pathPattern{segments: segments(path)}
+}
+
+// Parses a requested URL, dropping its scheme, host, query and fragment
+// (e.g. "https://api.example.com/users/{}?page=1" becomes /users/{})
+func parseURL(url string) pathPattern {
+	partial := false
+	if rest, ok := strings.CutPrefix(url, "{}"); ok {
+		url, partial = rest, true
+	}
+	for _, scheme := range []string{"http://", "https://"} {
+		if rest, ok := strings.CutPrefix(url, scheme); ok {
+			url = "/"
+			if i := strings.Index(rest, "/"); i >= 0 {
+				url = rest[i:]
+			}
+			partial = false
+		}
+	}
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	return pathPattern{segments: segments(url), partial: partial}
+}
+
+// Splits a path into segments, normalizing parameters to {}
+func segments(path string) []string {
+	result := make([]string, 0)
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case segment == "":
+		case isParameter(segment):
+			result = append(result, "{}")
+		default:
+			result = append(result, segment)
+		}
+	}
+	return result
+}
+
+// Checks if a path segment is a parameter in any of the supported syntaxes
+func isParameter(segment string) bool {
+	return strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") ||
+		strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// Checks if two routes have the same segments, whatever their parameter names
+func (p pathPattern) equal(other pathPattern) bool {
+	return slices.Equal(p.segments, other.segments)
+}
+
+// Returns the normalized form of the pattern (e.g. "/users/{}")
+func (p pathPattern) String() string {
+	return "/" + strings.Join(p.segments, "/")
+}
+
+// Scores how well a requested path matches a route, or returns -1 if it does not match.
+// Partial paths match the end of the route. Segments that agree score 2, a literal requested
+// from a parameter scores 1, and an unknown value requested from a literal scores 0, so that
+// "/users/{}" prefers "/users/{id}" to "/users/me". Partial paths must agree with the route
+// on a literal segment.
+func (p pathPattern) score(route pathPattern) int {
+	offset := len(route.segments) - len(p.segments)
+	if offset < 0 || offset > 0 && !p.partial {
+		return -1
+	}
+	score, literal := 0, false
+	for i, segment := range p.segments {
+		other := route.segments[offset+i]
+		switch {
+		case segment == other:
+			score += 2
+			literal = literal || segment != "{}"
+		case other == "{}":
+			score++
+		case segment != "{}":
+			return -1
+		}
+	}
+	if p.partial && !literal {
+		return -1
+	}
+	return score
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// A user as served by the API
+type User struct {
+	ID        string `json:"id"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email,omitempty"`
+	password  string
+	CreatedAt string `json:"-"`
+}
+
+type Handler struct{}
+
+func Routes(h *Handler) http.Handler {
+	r := chi.NewRouter()
+	r.Route("/api/users", func(r chi.Router) {
+		r.Get("/", h.listUsers)
+		r.Post("/", h.createUser)
+		r.Get("/{id}", h.getUser)
+		r.Get("/me", h.currentUser)
+	})
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {})
+	return r
+}
+
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request)   {}
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request)  {}
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request)     {}
+func (h *Handler) currentUser(w http.ResponseWriter, r *http.Request) {}
@@ -0,0 +1,24 @@
+import axios from "axios";
+
+export interface User {
+  id: string;
+  fullName: string;
+  email?: string;
+}
+
+export async function getUser(id: string): Promise<User> {
+  const res = await fetch(`/api/users/${id}`);
+  return res.json();
+}
+
+export async function currentUser(): Promise<User> {
+  return (await axios.get("/api/users/me")).data;
+}
+
+export function createUser(user: User) {
+  return fetch("/api/users", { method: "POST", body: JSON.stringify(user) });
+}
+
+export function weather() {
+  return fetch("https://weather.example.com/today");
+}
@@ -0,0 +1,16 @@
+import requests
+
+API = "http://users:8080"
+
+
+class Account:
+    def __init__(self, user_id: str, email: str):
+        self.id = user_id
+        self.full_name = ""
+        self.email = email
+
+
+def sync_users():
+    for user in requests.get(API + "/api/users").json():
+        print(user)
+    requests.get(f"{API}/health")
@@ -0,0 +1,513 @@
+package goconvention
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// A naming rule the analyzer learns from the project
+type NamingRule string
+
+const (
+	RuleReceiverConsistency NamingRule = "receiver_consistency" // same receiver name for all methods of a type
+	RuleReceiverLength      NamingRule = "receiver_length"      // short (1-2 letters) vs long receiver names
+	RuleParameterStyle      NamingRule = "parameter_style"      // naming style of parameter names
+	RuleInitialisms         NamingRule = "initialisms"          // "ID"/"URL" vs "Id"/"Url"
+	RuleGetterPrefix        NamingRule = "getter_prefix"        // "Name()" vs "GetName()"
+	RuleInterfaceSuffix     NamingRule = "interface_suffix"     // single-method interfaces named with -er
+	RuleConstructorPrefix   NamingRule = "constructor_prefix"   // "NewX" vs "CreateX"/"MakeX"
+)
+
+// Variants observed for the naming rules
+const (
+	VariantConsistent   = "consistent"
+	VariantInconsistent = "inconsistent"
+	VariantShort        = "short"
+	VariantLong         = "long"
+	VariantUpper        = "upper"       // initialism written in upper case (e.g. "ID")
+	VariantTitle        = "title"       // initialism written in title case (e.g. "Id")
+	VariantGetPrefix    = "get_prefix"  // getter with a "Get" prefix
+	VariantBare         = "bare"        // getter without a prefix
+	VariantErSuffix     = "er_suffix"   // single-method interface ending in -er/-or
+	VariantOtherSuffix  = "other"       // single-method interface with another name
+	VariantNewType      = "NewX"        // constructor named after the type it returns
+	VariantNew          = "New"         // constructor named just "New"
+	VariantNewOther     = "NewOther"    // "New" prefix with a different type name
+	VariantOtherPrefix  = "otherPrefix" // constructor with Create/Make/Build prefix
+)
+
+// Common initialisms that Go code conventionally writes in a consistent case
+var Initialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS",
+	"ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA", "SMTP", "SQL", "SSH",
+	"TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID", "URI", "URL", "UTF8", "VM", "XML",
+	"XMPP", "XSRF", "XSS",
+}
+
+// Prefixes that mark a function as a constructor when it does not use "New"
+var constructorPrefixes = []string{"Create", "Make", "Build", "Construct", "Init"}
+
+// The naming convention learned for a single rule
+type NamingConvention struct {
+	Rule       NamingRule
+	Total      int            // Number of observations
+	Counts     map[string]int // Number of observations per variant
+	Dominant   string         // The majority variant
+	Deviations []*NamingDeviation
+}
+
+// A name that deviates from the project's majority style
+type NamingDeviation struct {
+	Rule     NamingRule
+	Element  *gostructure.Element
+	Name     string // The offending identifier
+	Actual   string // The variant used
+	Expected string // The majority variant
+	Position ast.Position
+	Message  string
+}
+
+// A single observation of a naming variant
+type observation struct {
+	elem    *gostructure.Element
+	name    string
+	variant string
+}
+
+// Learns naming conventions from the structure and reports deviations from the majority style
+type NamingAnalyzer struct{}
+
+// Creates a new naming analyzer
+func NewNamingAnalyzer() *NamingAnalyzer {
+	return &NamingAnalyzer{}
+}
+
+// Analyzes the names in the structure, returning one convention per rule with observations
+func (a *NamingAnalyzer) Analyze(structure *gostructure.Structure) []*NamingConvention {
+	var conventions []*NamingConvention
+
+	if conv := a.receiverConsistency(structure); conv != nil {
+		conventions = append(conventions, conv)
+	}
+
+	rules := []struct {
+		rule       NamingRule
+		observe    func(*gostructure.Structure) []observation
+		choose     func(counts map[string]int) string
+		compatible func(variant, dominant string) bool
+	}{
+		{RuleReceiverLength, a.observeReceiverLength, nil, nil},
+		{RuleParameterStyle, a.observeParameterStyle, dominantStyleVariant, func(variant, dominant string) bool {
+			return StyleCompatible(NamingStyle(variant), NamingStyle(dominant))
+		}},
+		{RuleInitialisms, a.observeInitialisms, nil, nil},
+		{RuleGetterPrefix, a.observeGetters, nil, nil},
+		{RuleInterfaceSuffix, a.observeInterfaces, nil, nil},
+		{RuleConstructorPrefix, a.observeConstructors, nil, func(variant, dominant string) bool {
+			// "New" and "NewX" are both idiomatic
+			newFamily := func(v string) bool { return v == VariantNew || v == VariantNewType }
+			return variant == dominant || newFamily(variant) && newFamily(dominant)
+		}},
+	}
+
+	for _, r := range rules {
+		if conv := buildConvention(r.rule, r.observe(structure), r.choose, r.compatible); conv != nil {
+			conventions = append(conventions, conv)
+		}
+	}
+	return conventions
+}
+
+// Builds a convention from observations, choosing the majority variant unless a chooser is given
+func buildConvention(rule NamingRule, observations []observation, choose func(counts map[string]int) string, compatible func(variant, dominant string) bool) *NamingConvention {
+	if len(observations) == 0 {
+		return nil
+	}
+	if choose == nil {
+		choose = majority
+	}
+	if compatible == nil {
+		compatible = func(variant, dominant string) bool { return variant == dominant }
+	}
+
+	conv := &NamingConvention{
+		Rule:   rule,
+		Total:  len(observations),
+		Counts: make(map[string]int),
+	}
+	for _, o := range observations {
+		conv.Counts[o.variant]++
+	}
+	conv.Dominant = choose(conv.Counts)
+
+	for _, o := range observations {
+		if compatible(o.variant, conv.Dominant) {
+			continue
+		}
+		conv.Deviations = append(conv.Deviations, &NamingDeviation{
+			Rule:     rule,
+			Element:  o.elem,
+			Name:     o.name,
+			Actual:   o.variant,
+			Expected: conv.Dominant,
+			Position: o.elem.Position,
+			Message:  fmt.Sprintf("%s uses %s naming (%s), but the project mostly uses %s", o.name, rule, o.variant, conv.Dominant),
+		})
+	}
+	return conv
+}
+
+// Returns the variant with the highest count, breaking ties alphabetically
+func majority(counts map[string]int) string {
+	variants := make([]string, 0, len(counts))
+	for variant := range counts {
+		variants = append(variants, variant)
+	}
+	sort.Strings(variants)
+
+	best, bestCount := "", 0
+	for _, variant := range variants {
+		if counts[variant] > bestCount {
+			best, bestCount = variant, counts[variant]
+		}
+	}
+	return best
+}
+
+// Returns the dominant naming style, ignoring ambiguous single-word names
+func dominantStyleVariant(counts map[string]int) string {
+	styles := make(map[NamingStyle]int, len(counts))
+	for variant, count := range counts {
+		styles[NamingStyle(variant)] = count
+	}
+	return string(dominantStyle(styles))
+}
+
+// Checks that every type uses the same receiver name across its methods
+func (a *NamingAnalyzer) receiverConsistency(structure *gostructure.Structure) *NamingConvention {
+	byType := make(map[string][]*gostructure.Element)
+	var typeNames []string
+	for _, method := range elementsOfType(structure, gostructure.ElementMethod) {
+		name, typeName := receiverName(method), receiverTypeName(method)
+		if name == "" || name == "_" || typeName == "" {
+			continue
+		}
+		key := typeKey(method, typeName)
+		if _, ok := byType[key]; !ok {
+			typeNames = append(typeNames, key)
+		}
+		byType[key] = append(byType[key], method)
+	}
+	if len(byType) == 0 {
+		return nil
+	}
+
+	conv := &NamingConvention{
+		Rule:   RuleReceiverConsistency,
+		Counts: make(map[string]int),
+	}
+	for _, key := range typeNames {
+		methods := byType[key]
+		typeName := receiverTypeName(methods[0])
+		counts := make(map[string]int)
+		for _, method := range methods {
+			counts[receiverName(method)]++
+		}
+		conv.Total++
+		if len(counts) == 1 {
+			conv.Counts[VariantConsistent]++
+			continue
+		}
+		conv.Counts[VariantInconsistent]++
+
+		expected := majority(counts)
+		for _, method := range methods {
+			if name := receiverName(method); name != expected {
+				conv.Deviations = append(conv.Deviations, &NamingDeviation{
+					Rule:     RuleReceiverConsistency,
+					Element:  method,
+					Name:     name,
+					Actual:   name,
+					Expected: expected,
+					Position: method.Position,
+					Message:  fmt.Sprintf("method %s.%s uses receiver %q, other methods of %s use %q", typeName, method.Name, name, typeName, expected),
+				})
+			}
+		}
+	}
+	conv.Dominant = majority(conv.Counts)
+	return conv
+}
+
+// Observes whether receiver names are short or long
+func (a *NamingAnalyzer) observeReceiverLength(structure *gostructure.Structure) []observation {
+	var observations []observation
+	for _, method := range elementsOfType(structure, gostructure.ElementMethod) {
+		name := receiverName(method)
+		if name == "" || name == "_" {
+			continue
+		}
+		variant := VariantShort
+		if len([]rune(name)) > 2 {
+			variant = VariantLong
+		}
+		observations = append(observations, observation{elem: method, name: name, variant: variant})
+	}
+	return observations
+}
+
+// Observes the naming style of function and method parameters
+func (a *NamingAnalyzer) observeParameterStyle(structure *gostructure.Structure) []observation {
+	var observations []observation
+	for _, fn := range structure.Elements {
+		if fn.Type != gostructure.ElementFunction && fn.Type != gostructure.ElementMethod {
+			continue
+		}
+		sig, _ := fn.Attributes["signature"].(map[string]any)
+		names, _ := sig["param_names"].([]string)
+		for _, name := range names {
+			if name == "" || name == "_" {
+				continue
+			}
+			observations = append(observations, observation{elem: fn, name: name, variant: string(DetectStyle(name))})
+		}
+	}
+	return observations
+}
+
+// Observes how initialisms are written in identifiers
+func (a *NamingAnalyzer) observeInitialisms(structure *gostructure.Structure) []observation {
+	var observations []observation
+	observe := func(elem *gostructure.Element, name string) {
+		for _, variant := range initialismVariants(name) {
+			observations = append(observations, observation{elem: elem, name: name, variant: variant})
+		}
+	}
+
+	for _, elem := range structure.Elements {
+		if elem.Type == gostructure.ElementPackage {
+			continue
+		}
+		observe(elem, elem.Name)
+		for _, field := range structFields(elem) {
+			if embedded, _ := field["embedded"].(bool); !embedded {
+				name, _ := field["name"].(string)
+				observe(elem, name)
+			}
+		}
+	}
+	return observations
+}
+
+// Observes whether getters carry a "Get" prefix
+func (a *NamingAnalyzer) observeGetters(structure *gostructure.Structure) []observation {
+	fieldsByType := make(map[string]map[string]bool)
+	for _, elem := range elementsOfType(structure, gostructure.ElementTypeDecl) {
+		names := make(map[string]bool)
+		for _, field := range structFields(elem) {
+			if name, ok := field["name"].(string); ok {
+				names[strings.ToLower(name)] = true
+			}
+		}
+		fieldsByType[typeKey(elem, elem.Name)] = names
+	}
+
+	var observations []observation
+	for _, method := range elementsOfType(structure, gostructure.ElementMethod) {
+		sig, _ := method.Attributes["signature"].(map[string]any)
+		params, _ := sig["params"].([]*goparser.TypeInfo)
+		returns, _ := sig["returns"].([]*goparser.TypeInfo)
+		if len(params) != 0 || len(returns) != 1 {
+			continue
+		}
+
+		name := method.Name
+		if rest, ok := strings.CutPrefix(name, "Get"); ok && rest != "" && unicode.IsUpper([]rune(rest)[0]) {
+			observations = append(observations, observation{elem: method, name: name, variant: VariantGetPrefix})
+		} else if fieldsByType[typeKey(method, receiverTypeName(method))][strings.ToLower(name)] {
+			observations = append(observations, observation{elem: method, name: name, variant: VariantBare})
+		}
+	}
+	return observations
+}
+
+// Observes whether single-method interfaces use an -er suffix
+func (a *NamingAnalyzer) observeInterfaces(structure *gostructure.Structure) []observation {
+	var observations []observation
+	for _, iface := range elementsOfType(structure, gostructure.ElementInterface) {
+		methods, _ := iface.Attributes["methods"].([]map[string]any)
+		embedded, _ := iface.Attributes["embedded"].([]map[string]any)
+		if len(methods) != 1 || len(embedded) != 0 {
+			continue
+		}
+		variant := VariantOtherSuffix
+		if strings.HasSuffix(iface.Name, "er") || strings.HasSuffix(iface.Name, "or") {
+			variant = VariantErSuffix
+		}
+		observations = append(observations, observation{elem: iface, name: iface.Name, variant: variant})
+	}
+	return observations
+}
+
+// Observes how constructor functions are named
+func (a *NamingAnalyzer) observeConstructors(structure *gostructure.Structure) []observation {
+	localTypes := make(map[string]bool)
+	for _, elem := range structure.Elements {
+		if elem.Type == gostructure.ElementTypeDecl || elem.Type == gostructure.ElementInterface {
+			localTypes[typeKey(elem, elem.Name)] = true
+		}
+	}
+
+	var observations []observation
+	for _, fn := range elementsOfType(structure, gostructure.ElementFunction) {
+		sig, _ := fn.Attributes["signature"].(map[string]any)
+		returns, _ := sig["returns"].([]*goparser.TypeInfo)
+		if len(returns) == 0 {
+			continue
+		}
+		returned := namedType(returns[0])
+		if !localTypes[typeKey(fn, returned)] {
+			continue
+		}
+
+		name := fn.Name
+		var variant string
+		switch {
+		case name == "New":
+			variant = VariantNew
+		case name == "New"+returned:
+			variant = VariantNewType
+		case strings.HasPrefix(name, "New"):
+			variant = VariantNewOther
+		case hasConstructorPrefix(name):
+			variant = VariantOtherPrefix
+		default:
+			continue
+		}
+		observations = append(observations, observation{elem: fn, name: name, variant: variant})
+	}
+	return observations
+}
+
+// Returns the variants of the initialisms that appear in a name
+func initialismVariants(name string) []string {
+	// Skip all-caps names (constants, acronyms) where case carries no signal
+	if strings.ToUpper(name) == name {
+		return nil
+	}
+
+	var variants []string
+	lastEnd := 0
+	for i := 0; i < len(name); {
+		matched := false
+		// A word starts at the beginning, right after a match or after a non-upper character
+		atBoundary := i == lastEnd || !unicode.IsUpper(rune(name[i-1]))
+		if atBoundary && unicode.IsUpper(rune(name[i])) {
+			for _, initialism := range initialisms() {
+				n := len(initialism)
+				if i+n > len(name) {
+					continue
+				}
+				word := name[i : i+n]
+				if !wordEnds(name, i+n) {
+					continue
+				}
+				switch word {
+				case initialism:
+					variants = append(variants, VariantUpper)
+				case initialism[:1] + strings.ToLower(initialism[1:]):
+					variants = append(variants, VariantTitle)
+				default:
+					continue
+				}
+				i += n
+				lastEnd = i
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			i++
+		}
+	}
+	return variants
+}
+
+// Checks if a word ending at i is followed by a word boundary (end, upper case, digit or plural "s")
+func wordEnds(name string, i int) bool {
+	if i == len(name) {
+		return true
+	}
+	c := rune(name[i])
+	if unicode.IsUpper(c) || unicode.IsDigit(c) || c == '_' {
+		return true
+	}
+	return c == 's' && (i+1 == len(name) || unicode.IsUpper(rune(name[i+1])))
+}
+
+// Returns the initialisms ordered longest first so that "HTTPS" wins over "HTTP"
+func initialisms() []string {
+	sorted := make([]string, len(Initialisms))
+	copy(sorted, Initialisms)
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	return sorted
+}
+
+// Checks if a function name starts with a non-"New" constructor prefix
+func hasConstructorPrefix(name string) bool {
+	for _, prefix := range constructorPrefixes {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" && unicode.IsUpper([]rune(rest)[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the name of a (possibly pointer) named type
+func namedType(typeInfo *goparser.TypeInfo) string {
+	if typeInfo == nil {
+		return ""
+	}
+	if typeInfo.Kind == "pointer" {
+		return namedType(typeInfo.ElemType)
+	}
+	if typeInfo.Kind == "basic" {
+		return typeInfo.Name
+	}
+	return ""
+}
+
+// Returns the receiver name of a method
+func receiverName(method *gostructure.Element) string {
+	name, _ := method.Attributes["receiver_name"].(string)
+	return name
+}
+
+// Returns the receiver type name of a method
+func receiverTypeName(method *gostructure.Element) string {
+	recv, _ := method.Attributes["receiver_type"].(*goparser.TypeInfo)
+	return namedType(recv)
+}
+
+// Returns the key of a type named in the package declaring an element, so that same-named
+// types of different packages stay apart
+func typeKey(elem *gostructure.Element, name string) string {
+	return elem.Scope + "." + name
+}
+
+// Returns the elements of the given type
+func elementsOfType(structure *gostructure.Structure, elemType gostructure.ElementType) []*gostructure.Element {
+	var result []*gostructure.Element
+	for _, elem := range structure.Elements {
+		if elem.Type == elemType {
+			result = append(result, elem)
+		}
+	}
+	return result
+}
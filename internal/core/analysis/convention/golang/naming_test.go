@@ -0,0 +1,111 @@
+package goconvention_test
+
+import (
+	"maps"
+	"path/filepath"
+	"testing"
+
+	goconvention "codedna/internal/core/analysis/convention/golang"
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+func TestNamingAnalyzer(t *testing.T) {
+	goAnalysis := analyzeFile(t, "naming.go")
+
+	conventions := make(map[goconvention.NamingRule]*goconvention.NamingConvention)
+	for _, conv := range goconvention.NewNamingAnalyzer().Analyze(goAnalysis.Structure) {
+		conventions[conv.Rule] = conv
+	}
+
+	tests := []struct {
+		rule       goconvention.NamingRule
+		dominant   string
+		deviations []string
+	}{
+		{goconvention.RuleReceiverConsistency, goconvention.VariantInconsistent, []string{"store"}},
+		{goconvention.RuleReceiverLength, goconvention.VariantShort, []string{"store"}},
+		{goconvention.RuleParameterStyle, string(goconvention.StyleCamelCase), []string{"user_name"}},
+		{goconvention.RuleInitialisms, goconvention.VariantUpper, []string{"UserId"}},
+		{goconvention.RuleGetterPrefix, goconvention.VariantBare, []string{"GetUsers"}},
+		{goconvention.RuleInterfaceSuffix, goconvention.VariantErSuffix, []string{"UserSource"}},
+		{goconvention.RuleConstructorPrefix, goconvention.VariantNewType, []string{"CreateUser"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rule), func(t *testing.T) {
+			conv := conventions[tt.rule]
+			if conv == nil {
+				t.Fatalf("Missing convention for %s", tt.rule)
+			}
+			if conv.Dominant != tt.dominant {
+				t.Errorf("Expected dominant %s, got %s (counts %v)", tt.dominant, conv.Dominant, conv.Counts)
+			}
+
+			var names []string
+			for _, dev := range conv.Deviations {
+				names = append(names, dev.Name)
+				if dev.Element == nil || dev.Position.Line == 0 {
+					t.Errorf("Deviation %s has no element location", dev.Name)
+				}
+			}
+			if len(names) != len(tt.deviations) {
+				t.Fatalf("Expected deviations %v, got %v", tt.deviations, names)
+			}
+			for i := range names {
+				if names[i] != tt.deviations[i] {
+					t.Errorf("Expected deviations %v, got %v", tt.deviations, names)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestNamingAnalyzer_SameNamedTypes(t *testing.T) {
+	// Both packages declare a Server with its own receiver name and fields
+	var nodes []structure.Node
+	for _, dir := range []string{"api", "admin"} {
+		astNodes, err := goparser.New().ParseDir(filepath.Join("testdata", "servers", dir))
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", dir, err)
+		}
+		for _, astNode := range astNodes {
+			nodes = append(nodes, gostructure.NewNode(astNode))
+		}
+	}
+	analysis, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze packages: %v", err)
+	}
+
+	conventions := make(map[goconvention.NamingRule]*goconvention.NamingConvention)
+	for _, conv := range goconvention.NewNamingAnalyzer().Analyze(analysis.(*gostructure.Analysis).Structure) {
+		conventions[conv.Rule] = conv
+	}
+
+	tests := []struct {
+		rule   goconvention.NamingRule
+		counts map[string]int
+	}{
+		{goconvention.RuleReceiverConsistency, map[string]int{goconvention.VariantConsistent: 2}},
+		{goconvention.RuleGetterPrefix, map[string]int{goconvention.VariantBare: 2}},
+		// MakeDefault returns its type parameter, not admin.Options
+		{goconvention.RuleConstructorPrefix, map[string]int{goconvention.VariantNewType: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.rule), func(t *testing.T) {
+			conv := conventions[tt.rule]
+			if conv == nil {
+				t.Fatalf("Missing convention for %s", tt.rule)
+			}
+			if !maps.Equal(conv.Counts, tt.counts) {
+				t.Errorf("Expected counts %v, got %v", tt.counts, conv.Counts)
+			}
+			if len(conv.Deviations) != 0 {
+				t.Errorf("Expected no deviations, got %d", len(conv.Deviations))
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Package goconvention learns Go coding conventions from the analyzed structure
+package goconvention
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The naming style of an identifier or tag name
+type NamingStyle string
+
+const (
+	StyleSnakeCase      NamingStyle = "snake_case"
+	StyleScreamingSnake NamingStyle = "SCREAMING_SNAKE_CASE"
+	StyleKebabCase      NamingStyle = "kebab-case"
+	StyleCamelCase      NamingStyle = "camelCase"
+	StylePascalCase     NamingStyle = "PascalCase"
+	StyleLowerCase      NamingStyle = "lowercase" // single lowercase word, compatible with several styles
+	StyleUpperCase      NamingStyle = "UPPERCASE" // single uppercase word, compatible with several styles
+	StyleMixed          NamingStyle = "mixed"
+)
+
+// Order used to break ties when choosing a dominant style
+var styleOrder = []NamingStyle{
+	StyleSnakeCase,
+	StyleCamelCase,
+	StylePascalCase,
+	StyleKebabCase,
+	StyleScreamingSnake,
+	StyleLowerCase,
+	StyleUpperCase,
+	StyleMixed,
+}
+
+// Detects the naming style of a name
+func DetectStyle(name string) NamingStyle {
+	if name == "" {
+		return StyleMixed
+	}
+
+	hasUnderscore := strings.Contains(name, "_")
+	hasDash := strings.Contains(name, "-")
+	hasUpper, hasLower := false, false
+	for _, r := range name {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		}
+	}
+
+	switch {
+	case hasUnderscore && hasDash:
+		return StyleMixed
+	case hasUnderscore:
+		if hasUpper && hasLower {
+			return StyleMixed
+		}
+		if hasUpper {
+			return StyleScreamingSnake
+		}
+		return StyleSnakeCase
+	case hasDash:
+		if hasUpper {
+			return StyleMixed
+		}
+		return StyleKebabCase
+	case !hasUpper:
+		return StyleLowerCase
+	case !hasLower:
+		return StyleUpperCase
+	case unicode.IsUpper([]rune(name)[0]):
+		return StylePascalCase
+	default:
+		return StyleCamelCase
+	}
+}
+
+// Reports whether a name of the given style is consistent with the expected style
+func StyleCompatible(style, expected NamingStyle) bool {
+	if style == expected {
+		return true
+	}
+	switch style {
+	case StyleLowerCase:
+		return expected == StyleSnakeCase || expected == StyleCamelCase || expected == StyleKebabCase
+	case StyleUpperCase:
+		return expected == StylePascalCase || expected == StyleScreamingSnake
+	}
+	return false
+}
+
+// Returns the most common unambiguous style in the counts
+func dominantStyle(counts map[NamingStyle]int) NamingStyle {
+	best, bestCount := NamingStyle(""), 0
+	for _, style := range styleOrder {
+		if style == StyleLowerCase || style == StyleUpperCase || style == StyleMixed {
+			continue
+		}
+		if counts[style] > bestCount {
+			best, bestCount = style, counts[style]
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	// Only ambiguous names were seen
+	for _, style := range styleOrder {
+		if counts[style] > bestCount {
+			best, bestCount = style, counts[style]
+		}
+	}
+	return best
+}
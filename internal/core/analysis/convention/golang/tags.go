@@ -0,0 +1,198 @@
+package goconvention
+
+import (
+	"fmt"
+	"sort"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Tag keys whose names are checked for naming conventions by default
+var DefaultTagKeys = []string{"json", "yaml", "db", "mapstructure", "xml", "toml", "bson"}
+
+// The kind of tag convention deviation
+type TagDeviationKind string
+
+const (
+	TagDeviationStyle   TagDeviationKind = "style"   // tag name does not follow the convention style
+	TagDeviationMissing TagDeviationKind = "missing" // exported field lacks a tag its struct otherwise uses
+)
+
+// A struct field whose tag deviates from the learned convention
+type TagDeviation struct {
+	Kind     TagDeviationKind
+	Element  *gostructure.Element // The struct type declaring the field
+	Field    string
+	Key      string
+	Name     string // The tag name (empty for missing tags)
+	Style    NamingStyle
+	Expected NamingStyle
+	Position ast.Position
+	Message  string
+}
+
+// The naming convention of a single tag key
+type TagConvention struct {
+	Key        string
+	Total      int                 // Number of named tags seen for the key
+	Styles     map[NamingStyle]int // Number of tags per naming style
+	Dominant   NamingStyle         // The learned (or enforced) style
+	Enforced   bool                // Whether the style was set explicitly instead of learned
+	Deviations []*TagDeviation
+}
+
+// Learns struct tag conventions and reports fields that deviate from them
+type TagAnalyzer struct {
+	keys     []string
+	expected map[string]NamingStyle
+}
+
+// Creates a new tag analyzer checking the default tag keys
+func NewTagAnalyzer() *TagAnalyzer {
+	return &TagAnalyzer{
+		keys:     DefaultTagKeys,
+		expected: make(map[string]NamingStyle),
+	}
+}
+
+// Sets the tag keys to check
+func (a *TagAnalyzer) SetKeys(keys ...string) {
+	a.keys = keys
+}
+
+// Enforces a style for a tag key instead of learning the majority style
+func (a *TagAnalyzer) Expect(key string, style NamingStyle) {
+	a.expected[key] = style
+}
+
+// Analyzes the struct tags in the structure, returning one convention per tag key in use
+func (a *TagAnalyzer) Analyze(structure *gostructure.Structure) []*TagConvention {
+	conventions := make(map[string]*TagConvention)
+	for _, key := range a.keys {
+		conventions[key] = &TagConvention{
+			Key:    key,
+			Styles: make(map[NamingStyle]int),
+		}
+	}
+
+	// First pass: count the styles of every tag name
+	for _, elem := range structure.Elements {
+		for _, field := range structFields(elem) {
+			tags, _ := field["tags"].(map[string]*goparser.StructTag)
+			for key, tag := range tags {
+				conv, ok := conventions[key]
+				if !ok || !isNamedTag(tag) {
+					continue
+				}
+				conv.Total++
+				conv.Styles[DetectStyle(tag.Name)]++
+			}
+		}
+	}
+
+	// Decide on the style for each key
+	for key, conv := range conventions {
+		if style, ok := a.expected[key]; ok {
+			conv.Dominant = style
+			conv.Enforced = true
+		} else {
+			conv.Dominant = dominantStyle(conv.Styles)
+		}
+	}
+
+	// Second pass: report deviations
+	for _, elem := range structure.Elements {
+		fields := structFields(elem)
+		for key, conv := range conventions {
+			if conv.Total == 0 && !conv.Enforced {
+				continue
+			}
+			conv.Deviations = append(conv.Deviations, a.fieldDeviations(elem, fields, key, conv.Dominant)...)
+		}
+	}
+
+	// Only keep keys that are actually used
+	result := make([]*TagConvention, 0, len(conventions))
+	for _, conv := range conventions {
+		if conv.Total > 0 {
+			result = append(result, conv)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// Returns the deviations of a struct's fields for a single tag key
+func (a *TagAnalyzer) fieldDeviations(elem *gostructure.Element, fields []map[string]any, key string, expected NamingStyle) []*TagDeviation {
+	var deviations []*TagDeviation
+
+	// Only report missing tags for structs that use the key on some field
+	usesKey := false
+	for _, field := range fields {
+		if tags, ok := field["tags"].(map[string]*goparser.StructTag); ok && tags[key] != nil {
+			usesKey = true
+			break
+		}
+	}
+	if !usesKey {
+		return nil
+	}
+
+	for _, field := range fields {
+		name, _ := field["name"].(string)
+		pos, _ := field["position"].(ast.Position)
+		tags, _ := field["tags"].(map[string]*goparser.StructTag)
+		tag := tags[key]
+
+		if tag == nil {
+			exported, _ := field["is_exported"].(bool)
+			embedded, _ := field["embedded"].(bool)
+			if exported && !embedded {
+				deviations = append(deviations, &TagDeviation{
+					Kind:     TagDeviationMissing,
+					Element:  elem,
+					Field:    name,
+					Key:      key,
+					Expected: expected,
+					Position: pos,
+					Message:  fmt.Sprintf("field %s.%s has no %s tag", elem.Name, name, key),
+				})
+			}
+			continue
+		}
+
+		if !isNamedTag(tag) {
+			continue
+		}
+		if style := DetectStyle(tag.Name); !StyleCompatible(style, expected) {
+			deviations = append(deviations, &TagDeviation{
+				Kind:     TagDeviationStyle,
+				Element:  elem,
+				Field:    name,
+				Key:      key,
+				Name:     tag.Name,
+				Style:    style,
+				Expected: expected,
+				Position: pos,
+				Message:  fmt.Sprintf("%s tag %q on %s.%s is %s, expected %s", key, tag.Name, elem.Name, name, style, expected),
+			})
+		}
+	}
+	return deviations
+}
+
+// Returns the fields of a struct type element
+func structFields(elem *gostructure.Element) []map[string]any {
+	if elem.Type != gostructure.ElementTypeDecl {
+		return nil
+	}
+	fields, _ := elem.Attributes["fields"].([]map[string]any)
+	return fields
+}
+
+// Checks if a tag carries an explicit name (not skipped with "-" or left empty)
+func isNamedTag(tag *goparser.StructTag) bool {
+	return tag.Name != "" && tag.Name != "-"
+}
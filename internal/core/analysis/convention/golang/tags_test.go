@@ -0,0 +1,137 @@
+package goconvention_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	goconvention "codedna/internal/core/analysis/convention/golang"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Helper function to parse and analyze a testdata file
+func analyzeFile(t *testing.T, name string) *gostructure.Analysis {
+	t.Helper()
+
+	astNode, err := goparser.New().ParseFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	analysis, err := gostructure.NewAnalyzer().Analyze(gostructure.NewNode(astNode))
+	if err != nil {
+		t.Fatalf("Failed to analyze file: %v", err)
+	}
+
+	goAnalysis, ok := analysis.(*gostructure.Analysis)
+	if !ok {
+		t.Fatalf("Expected Go analysis, got %T", analysis)
+	}
+	return goAnalysis
+}
+
+func TestDetectStyle(t *testing.T) {
+	tests := map[string]goconvention.NamingStyle{
+		"user_id":     goconvention.StyleSnakeCase,
+		"USER_ID":     goconvention.StyleScreamingSnake,
+		"user-id":     goconvention.StyleKebabCase,
+		"userId":      goconvention.StyleCamelCase,
+		"UserID":      goconvention.StylePascalCase,
+		"email":       goconvention.StyleLowerCase,
+		"ID":          goconvention.StyleUpperCase,
+		"User_id":     goconvention.StyleMixed,
+		"user_id-key": goconvention.StyleMixed,
+	}
+
+	for name, expected := range tests {
+		if got := goconvention.DetectStyle(name); got != expected {
+			t.Errorf("DetectStyle(%q): expected %s, got %s", name, expected, got)
+		}
+	}
+}
+
+func TestTagAnalyzer(t *testing.T) {
+	goAnalysis := analyzeFile(t, "tags.go")
+
+	conventions := make(map[string]*goconvention.TagConvention)
+	for _, conv := range goconvention.NewTagAnalyzer().Analyze(goAnalysis.Structure) {
+		conventions[conv.Key] = conv
+	}
+
+	t.Run("Keys", func(t *testing.T) {
+		for _, key := range []string{"json", "db", "mapstructure", "yaml"} {
+			if conventions[key] == nil {
+				t.Errorf("Missing convention for %s", key)
+			}
+		}
+		if _, ok := conventions["validate"]; ok {
+			t.Error("Expected validate tags to be ignored")
+		}
+	})
+
+	t.Run("Dominant", func(t *testing.T) {
+		expected := map[string]goconvention.NamingStyle{
+			"json":         goconvention.StyleSnakeCase,
+			"db":           goconvention.StyleSnakeCase,
+			"mapstructure": goconvention.StyleSnakeCase,
+			"yaml":         goconvention.StyleKebabCase,
+		}
+		for key, style := range expected {
+			if conv := conventions[key]; conv != nil && conv.Dominant != style {
+				t.Errorf("Key %s: expected dominant %s, got %s", key, style, conv.Dominant)
+			}
+		}
+	})
+
+	t.Run("Deviations", func(t *testing.T) {
+		conv := conventions["json"]
+		if conv == nil {
+			t.Fatal("Missing json convention")
+		}
+
+		found := make(map[string]goconvention.TagDeviationKind)
+		for _, dev := range conv.Deviations {
+			found[dev.Element.Name+"."+dev.Field] = dev.Kind
+			if dev.Position.Line == 0 {
+				t.Errorf("Deviation %s.%s has no position", dev.Element.Name, dev.Field)
+			}
+		}
+
+		expected := map[string]goconvention.TagDeviationKind{
+			"Order.OrderID":   goconvention.TagDeviationStyle,
+			"Order.CreatedAt": goconvention.TagDeviationMissing,
+		}
+		if len(found) != len(expected) {
+			t.Errorf("Expected %d deviations, got %v", len(expected), found)
+		}
+		for field, kind := range expected {
+			if found[field] != kind {
+				t.Errorf("Field %s: expected %s deviation, got %q", field, kind, found[field])
+			}
+		}
+	})
+
+	t.Run("Enforced", func(t *testing.T) {
+		analyzer := goconvention.NewTagAnalyzer()
+		analyzer.SetKeys("json")
+		analyzer.Expect("json", goconvention.StyleCamelCase)
+
+		convs := analyzer.Analyze(goAnalysis.Structure)
+		if len(convs) != 1 {
+			t.Fatalf("Expected 1 convention, got %d", len(convs))
+		}
+		if !convs[0].Enforced || convs[0].Dominant != goconvention.StyleCamelCase {
+			t.Errorf("Expected enforced camelCase, got %s (enforced=%v)", convs[0].Dominant, convs[0].Enforced)
+		}
+
+		styleDeviations := 0
+		for _, dev := range convs[0].Deviations {
+			if dev.Kind == goconvention.TagDeviationStyle {
+				styleDeviations++
+			}
+		}
+		if styleDeviations != 3 {
+			t.Errorf("Expected 3 style deviations (first_name, last_name, user_id), got %d", styleDeviations)
+		}
+	})
+}
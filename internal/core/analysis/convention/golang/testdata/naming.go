@@ -0,0 +1,61 @@
+package testdata
+
+// Store keeps users by ID
+type Store struct {
+	name    string
+	users   map[int]*User
+	baseURL string
+}
+
+// User is a stored user
+type User struct {
+	ID     int
+	Name   string
+	UserId string
+}
+
+// NewStore creates a new store
+func NewStore(name string, baseURL string) *Store {
+	return &Store{name: name, baseURL: baseURL}
+}
+
+// CreateUser creates a new user
+func CreateUser(id int, user_name string) *User {
+	return &User{ID: id, Name: user_name}
+}
+
+// NewUser creates a new user
+func NewUser(id int, displayName string) *User {
+	return &User{ID: id, Name: displayName}
+}
+
+func (s *Store) Name() string {
+	return s.name
+}
+
+func (s *Store) BaseURL() string {
+	return s.baseURL
+}
+
+func (s *Store) Add(u *User) {
+	s.users[u.ID] = u
+}
+
+func (store *Store) GetUsers() map[int]*User {
+	return store.users
+}
+
+// Reader reads users
+type Reader interface {
+	Read(id int) (*User, error)
+}
+
+// Writer writes users
+type Writer interface {
+	Write(u *User) error
+}
+
+// UserSource provides users
+type UserSource interface {
+	Users() []*User
+}
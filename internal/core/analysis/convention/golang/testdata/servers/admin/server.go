@@ -0,0 +1,22 @@
+package admin
+
+// Server serves the admin console
+type Server struct {
+	port int
+}
+
+// Options configures a server
+type Options struct {
+	Port int
+}
+
+// NewServer creates a server from options
+func NewServer(options Options) *Server {
+	return &Server{port: options.Port}
+}
+
+func (srv *Server) Port() int {
+	return srv.port
+}
+
+func (srv *Server) Stop() {}
@@ -0,0 +1,23 @@
+package api
+
+// Server serves the public API
+type Server struct {
+	addr string
+}
+
+// NewServer creates a server listening on addr
+func NewServer(addr string) *Server {
+	return &Server{addr: addr}
+}
+
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+func (s *Server) Start() {}
+
+// MakeDefault returns the zero value of a type parameter named like admin.Options
+func MakeDefault[Options any]() Options {
+	var options Options
+	return options
+}
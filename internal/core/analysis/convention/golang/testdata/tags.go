@@ -0,0 +1,25 @@
+package testdata
+
+// User is serialized with snake_case JSON names
+type User struct {
+	ID        int    `json:"id" db:"id"`
+	FirstName string `json:"first_name" db:"first_name"`
+	LastName  string `json:"last_name" db:"last_name"`
+	Email     string `json:"email,omitempty" db:"email" validate:"required,email"`
+	password  string
+}
+
+// Order mixes naming styles
+type Order struct {
+	OrderID   int     `json:"orderId"`
+	UserID    int     `json:"user_id"`
+	Total     float64 `json:"total"`
+	CreatedAt string
+	Internal  string `json:"-"`
+}
+
+// Config is loaded through mapstructure
+type Config struct {
+	ListenAddr string `mapstructure:"listen_addr" yaml:"listen-addr"`
+	LogLevel   string `mapstructure:"log_level" yaml:"log-level"`
+}
@@ -0,0 +1,270 @@
+package coverage
+
+import (
+	"path/filepath"
+	"sort"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+)
+
+// Statement counts of a function, method, file or package
+type Counts struct {
+	Statements int `json:"statements"`
+	Covered    int `json:"covered"` // Statements run by tests
+}
+
+// Returns the share of statements run by tests, 0 without statements
+func (c Counts) Ratio() float64 {
+	if c.Statements == 0 {
+		return 0
+	}
+	return float64(c.Covered) / float64(c.Statements)
+}
+
+// Adds the statements of a block
+func (c *Counts) add(block *Block) {
+	c.Statements += block.Statements
+	if block.Count > 0 {
+		c.Covered += block.Statements
+	}
+}
+
+// Returns the statement counts attached to an element, and whether it has any. Functions,
+// methods and the package elements of files in a coverage profile have them.
+func ElementCounts(elem *gostructure.Element) (Counts, bool) {
+	statements, ok := elem.Attributes["statements"].(int)
+	if !ok {
+		return Counts{}, false
+	}
+	covered, _ := elem.Attributes["covered_statements"].(int)
+	return Counts{Statements: statements, Covered: covered}, true
+}
+
+// Statement coverage of a package: the files of a directory in the coverage profile
+type Package struct {
+	Name string
+	Dir  string
+	Counts
+}
+
+// A function or method with its statement coverage
+type Function struct {
+	Element  *gostructure.Element
+	Receiver *gostructure.Element // The receiver type of a method, if it is in the structure
+	Counts
+}
+
+// Returns the name of the function, qualified by the receiver type for methods (e.g. "Square.Area")
+func (f *Function) Name() string {
+	if f.Receiver == nil {
+		return f.Element.Name
+	}
+	return f.Receiver.Name + "." + f.Element.Name
+}
+
+// A method implementing an interface
+type Implementation struct {
+	Method    *Function
+	Interface *gostructure.Element
+}
+
+// The coverage of a structure
+type Report struct {
+	Mode     string
+	Total    Counts
+	Packages []*Package // Sorted by directory
+	// Exported functions and methods of exported types with statements, none of which ran.
+	// Generated code is left out.
+	UntestedExported []*Function
+	// Methods implementing an interface with statements, none of which ran. Generated code is
+	// left out.
+	UntestedImplementations []*Implementation
+	Unmatched               []string // Files of the profile missing from the structure, such as those of other modules
+}
+
+// Attaches the statement coverage of a profile to the functions, methods and package elements of
+// a structure, as "statements" and "covered_statements" attributes, and reports it. The profile
+// names files by import path, so they are located in the module of the structure; files of other
+// modules are reported as unmatched. Coverage attached by a previous call is replaced.
+func Apply(structure *gostructure.Structure, module *Module, profile *Profile) *Report {
+	report := &Report{Mode: profile.Mode}
+	files := make(map[string][]*gostructure.Element)  // File -> functions and methods
+	packages := make(map[string]*gostructure.Element) // File -> package element
+	for _, elem := range structure.Elements {
+		file := filepath.ToSlash(filepath.Clean(elem.Position.Filename))
+		switch elem.Type {
+		case gostructure.ElementPackage:
+			packages[file] = elem
+		case gostructure.ElementFunction, gostructure.ElementMethod:
+			files[file] = append(files[file], elem)
+		default:
+			continue
+		}
+		delete(elem.Attributes, "statements")
+		delete(elem.Attributes, "covered_statements")
+	}
+
+	byDir := make(map[string]*Package)
+	for _, name := range profile.FileNames() {
+		file := module.file(name)
+		if packages[file] == nil {
+			report.Unmatched = append(report.Unmatched, name)
+			continue
+		}
+
+		var fileCounts Counts
+		counts := make(map[*gostructure.Element]*Counts)
+		for _, fn := range files[file] {
+			counts[fn] = &Counts{}
+		}
+		for _, block := range profile.Files[name] {
+			fileCounts.add(block)
+			if fn := enclosing(files[file], block); fn != nil {
+				counts[fn].add(block)
+			}
+		}
+		for fn, c := range counts {
+			setCounts(fn, *c)
+		}
+		pkg := packages[file]
+		setCounts(pkg, fileCounts)
+
+		dir := filepath.Dir(pkg.Position.Filename)
+		p, ok := byDir[dir]
+		if !ok {
+			p = &Package{Name: pkg.Name, Dir: dir}
+			byDir[dir] = p
+			report.Packages = append(report.Packages, p)
+		}
+		p.Statements += fileCounts.Statements
+		p.Covered += fileCounts.Covered
+		report.Total.Statements += fileCounts.Statements
+		report.Total.Covered += fileCounts.Covered
+	}
+	sort.Slice(report.Packages, func(i, j int) bool {
+		return report.Packages[i].Dir < report.Packages[j].Dir
+	})
+
+	report.UntestedExported, report.UntestedImplementations = untested(structure)
+	return report
+}
+
+// Sets the statement counts of an element
+func setCounts(elem *gostructure.Element, counts Counts) {
+	elem.Attributes["statements"] = counts.Statements
+	elem.Attributes["covered_statements"] = counts.Covered
+}
+
+// Returns the function or method whose lines hold the start of a block, or nil when the block is
+// outside them (e.g. in a function literal assigned to a package variable)
+func enclosing(functions []*gostructure.Element, block *Block) *gostructure.Element {
+	for _, fn := range functions {
+		end, ok := fn.Attributes["end_line"].(int)
+		if ok && fn.Position.Line <= block.StartLine && block.StartLine <= end {
+			return fn
+		}
+	}
+	return nil
+}
+
+// Returns a function or method with its coverage if it has statements and tests run none of them
+func untestedFunction(elem, receiver *gostructure.Element) *Function {
+	counts, ok := ElementCounts(elem)
+	if !ok || counts.Statements == 0 || counts.Covered > 0 || elem.IsGenerated() {
+		return nil
+	}
+	return &Function{Element: elem, Receiver: receiver, Counts: counts}
+}
+
+// Finds the untested exported functions and methods, and the untested methods implementing
+// interfaces
+func untested(structure *gostructure.Structure) ([]*Function, []*Implementation) {
+	receivers := make(map[*gostructure.Element]*gostructure.Element) // Method -> receiver type
+	methods := make(map[*gostructure.Element][]*gostructure.Element) // Type -> methods
+	embeds := make(map[*gostructure.Element][]*gostructure.Element)  // Interface -> embedded interfaces
+	for _, rel := range structure.Relationships {
+		switch rel.Type {
+		case gostructure.RelationMethodReceiver:
+			receivers[rel.Source] = rel.Target
+			methods[rel.Target] = append(methods[rel.Target], rel.Source)
+		case gostructure.RelationInterfaceEmbeds:
+			embeds[rel.Source] = append(embeds[rel.Source], rel.Target)
+		}
+	}
+
+	var exported []*Function
+	for _, elem := range structure.Elements {
+		if elem.Type != gostructure.ElementFunction && elem.Type != gostructure.ElementMethod {
+			continue
+		}
+		receiver := receivers[elem]
+		if !isExported(elem) || (receiver != nil && !isExported(receiver)) {
+			continue
+		}
+		if fn := untestedFunction(elem, receiver); fn != nil {
+			exported = append(exported, fn)
+		}
+	}
+	sort.SliceStable(exported, func(i, j int) bool {
+		return less(exported[i].Element, exported[j].Element)
+	})
+
+	var implementations []*Implementation
+	for _, rel := range structure.Relationships {
+		if rel.Type != gostructure.RelationImplements {
+			continue
+		}
+		names := interfaceMethods(rel.Target, embeds, make(map[*gostructure.Element]bool))
+		for _, method := range methods[rel.Source] {
+			if !names[method.Name] {
+				continue
+			}
+			if fn := untestedFunction(method, rel.Source); fn != nil {
+				implementations = append(implementations, &Implementation{Method: fn, Interface: rel.Target})
+			}
+		}
+	}
+	sort.SliceStable(implementations, func(i, j int) bool {
+		a, b := implementations[i], implementations[j]
+		if a.Method.Element != b.Method.Element {
+			return less(a.Method.Element, b.Method.Element)
+		}
+		return a.Interface.Name < b.Interface.Name
+	})
+	return exported, implementations
+}
+
+// Returns the method names of an interface, including those of the interfaces it embeds
+func interfaceMethods(iface *gostructure.Element, embeds map[*gostructure.Element][]*gostructure.Element, visited map[*gostructure.Element]bool) map[string]bool {
+	names := make(map[string]bool)
+	if visited[iface] {
+		return names
+	}
+	visited[iface] = true
+	methods, _ := iface.Attributes["methods"].([]map[string]any)
+	for _, method := range methods {
+		if name, ok := method["name"].(string); ok {
+			names[name] = true
+		}
+	}
+	for _, embedded := range embeds[iface] {
+		for name := range interfaceMethods(embedded, embeds, visited) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// Checks if an element is exported
+func isExported(elem *gostructure.Element) bool {
+	exported, _ := elem.Attributes["is_exported"].(bool)
+	return exported
+}
+
+// Compares the positions of two elements
+func less(a, b *gostructure.Element) bool {
+	if a.Position.Filename != b.Position.Filename {
+		return a.Position.Filename < b.Position.Filename
+	}
+	return a.Position.Line < b.Position.Line
+}
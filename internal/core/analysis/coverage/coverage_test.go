@@ -0,0 +1,146 @@
+package coverage_test
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/analysis/coverage"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+)
+
+// Helper function to apply the testdata profile to the shapes module
+func applyProfile(t *testing.T) (*gostructure.Analysis, *coverage.Module, *coverage.Report) {
+	t.Helper()
+	dir := filepath.Join("testdata", "shapes")
+	analysis, err := gostructure.NewWorkspace(gostructure.NewAnalyzer()).Update([]string{dir})
+	if err != nil {
+		t.Fatalf("Failed to analyze testdata: %v", err)
+	}
+	module, err := coverage.ReadModule(dir)
+	if err != nil {
+		t.Fatalf("Failed to read module: %v", err)
+	}
+	profile, err := coverage.ParseFile(filepath.Join("testdata", "cover.out"))
+	if err != nil {
+		t.Fatalf("Failed to parse profile: %v", err)
+	}
+	return analysis, module, coverage.Apply(analysis.Structure, module, profile)
+}
+
+// Helper function to find a function or method by name and receiver type
+func findFunction(t *testing.T, structure *gostructure.Structure, receiver, name string) *gostructure.Element {
+	t.Helper()
+	receivers := make(map[*gostructure.Element]string)
+	for _, rel := range structure.Relationships {
+		if rel.Type == gostructure.RelationMethodReceiver {
+			receivers[rel.Source] = rel.Target.Name
+		}
+	}
+	for _, elem := range structure.Elements {
+		if (elem.Type == gostructure.ElementFunction || elem.Type == gostructure.ElementMethod) && elem.Name == name && receivers[elem] == receiver {
+			return elem
+		}
+	}
+	t.Fatalf("Function %s.%s not found", receiver, name)
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	analysis, module, report := applyProfile(t)
+	structure := analysis.Structure
+
+	t.Run("Module", func(t *testing.T) {
+		if module.Path != "example.com/shapes" {
+			t.Errorf("Expected module path example.com/shapes, got %q", module.Path)
+		}
+	})
+
+	t.Run("Elements", func(t *testing.T) {
+		for _, tt := range []struct {
+			receiver string
+			name     string
+			expected coverage.Counts
+		}{
+			{"Square", "Area", coverage.Counts{Statements: 1, Covered: 1}},
+			{"Square", "Perimeter", coverage.Counts{Statements: 1}},
+			{"", "Largest", coverage.Counts{Statements: 5, Covered: 5}},
+			{"", "TotalPerimeter", coverage.Counts{Statements: 4}},
+			{"", "scale", coverage.Counts{Statements: 1}},
+			{"", "TestLargest", coverage.Counts{}},
+		} {
+			counts, ok := coverage.ElementCounts(findFunction(t, structure, tt.receiver, tt.name))
+			if tt.name == "TestLargest" {
+				if ok {
+					t.Errorf("Expected no coverage for test function, got %+v", counts)
+				}
+				continue
+			}
+			if !ok || counts != tt.expected {
+				t.Errorf("Expected %s.%s counts %+v, got %+v", tt.receiver, tt.name, tt.expected, counts)
+			}
+		}
+	})
+
+	t.Run("Packages", func(t *testing.T) {
+		if report.Total != (coverage.Counts{Statements: 14, Covered: 7}) {
+			t.Errorf("Expected 7 of 14 statements covered, got %+v", report.Total)
+		}
+		if ratio := report.Total.Ratio(); ratio != 0.5 {
+			t.Errorf("Expected ratio 0.5, got %f", ratio)
+		}
+		if len(report.Packages) != 1 || report.Packages[0].Name != "shapes" || report.Packages[0].Counts != report.Total {
+			t.Errorf("Expected shapes package with the total counts, got %+v", report.Packages)
+		}
+		// Files of other modules stay unmatched, even with the base name of a project file
+		expected := []string{"example.com/other/other.go", "example.com/other/shapes/shapes.go"}
+		if !slices.Equal(report.Unmatched, expected) {
+			t.Errorf("Expected %v to be unmatched, got %v", expected, report.Unmatched)
+		}
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		collector := gostructure.NewMetricsCollector()
+		collector.CollectMetrics(structure)
+		if statements := collector.Metric(gostructure.MetricStatements); statements != 14 {
+			t.Errorf("Expected 14 statements, got %d", statements)
+		}
+		if covered := collector.Metric(gostructure.MetricCoveredStatements); covered != 7 {
+			t.Errorf("Expected 7 covered statements, got %d", covered)
+		}
+	})
+
+	t.Run("UntestedExported", func(t *testing.T) {
+		var names []string
+		for _, fn := range report.UntestedExported {
+			names = append(names, fn.Name())
+		}
+		expected := []string{"Square.Perimeter", "Circle.Perimeter", "TotalPerimeter"}
+		if !slices.Equal(names, expected) {
+			t.Errorf("Expected %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("UntestedImplementations", func(t *testing.T) {
+		if len(report.UntestedImplementations) != 2 {
+			t.Fatalf("Expected 2 untested implementations, got %d", len(report.UntestedImplementations))
+		}
+		for i, typ := range []string{"Square", "Circle"} {
+			impl := report.UntestedImplementations[i]
+			if name := impl.Method.Name(); name != typ+".Perimeter" || impl.Interface.Name != "Shape" {
+				t.Errorf("Expected %s.Perimeter implementing Shape, got %s implementing %s", typ, name, impl.Interface.Name)
+			}
+		}
+	})
+
+	t.Run("Reapply", func(t *testing.T) {
+		profile, err := coverage.ParseFile(filepath.Join("testdata", "cover.out"))
+		if err != nil {
+			t.Fatalf("Failed to parse profile: %v", err)
+		}
+		again := coverage.Apply(structure, module, profile)
+		if again.Total != report.Total {
+			t.Errorf("Expected the same totals when reapplied, got %+v", again.Total)
+		}
+	})
+}
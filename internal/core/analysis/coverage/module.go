@@ -0,0 +1,61 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// A Go module, locating the files a coverage profile names by import path
+type Module struct {
+	Dir  string // Directory holding the go.mod file
+	Path string // Module path (e.g. "example.com/app")
+}
+
+// Reads the module path from the go.mod file of a directory
+func ReadModule(dir string) (*Module, error) {
+	path := filepath.Join(dir, "go.mod")
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open go.mod: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		rest, ok := strings.CutPrefix(line, "module")
+		if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		modulePath := strings.TrimSpace(rest)
+		if unquoted, err := strconv.Unquote(modulePath); err == nil {
+			modulePath = unquoted
+		}
+		if modulePath == "" {
+			break
+		}
+		return &Module{Dir: dir, Path: modulePath}, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return nil, fmt.Errorf("%s: missing module directive", path)
+}
+
+// Returns the file of the module a profile file names by import path (e.g.
+// "example.com/app/store/store.go" -> "<dir>/store/store.go"), or "" when it belongs to another
+// module
+func (m *Module) file(name string) string {
+	rel, ok := strings.CutPrefix(name, m.Path+"/")
+	if !ok {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Join(m.Dir, filepath.FromSlash(rel)))
+}
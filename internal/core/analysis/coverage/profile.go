@@ -0,0 +1,159 @@
+// Package coverage reads Go coverage profiles and attaches statement coverage to code structures
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Counting modes of a coverage profile
+const (
+	ModeSet    = "set"    // Whether each block ran
+	ModeCount  = "count"  // How many times each block ran
+	ModeAtomic = "atomic" // Like count, safe for parallel tests
+)
+
+// A block of statements in a coverage profile
+type Block struct {
+	StartLine  int
+	StartCol   int
+	EndLine    int
+	EndCol     int
+	Statements int
+	Count      int // Times the block ran
+}
+
+// A coverage profile written by go test -coverprofile
+type Profile struct {
+	Mode  string
+	Files map[string][]*Block // Import path of the file (e.g. "example.com/app/store/store.go") -> blocks
+	order []string            // Files in order of appearance
+}
+
+// Returns the files of the profile in order of appearance
+func (p *Profile) FileNames() []string {
+	return p.order
+}
+
+// Reads a coverage profile from a file
+func ParseFile(path string) (*Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open coverage profile: %w", err)
+	}
+	defer f.Close()
+
+	profile, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return profile, nil
+}
+
+// Reads a coverage profile. Blocks listed several times, as in profiles concatenated from
+// several runs, are merged: their counts add up, or in set mode the block ran if any run did.
+func Parse(r io.Reader) (*Profile, error) {
+	profile := &Profile{Files: make(map[string][]*Block)}
+	seen := make(map[string]map[[4]int]*Block) // File -> block position -> block
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if mode, ok := strings.CutPrefix(line, "mode: "); ok {
+			switch {
+			case mode != ModeSet && mode != ModeCount && mode != ModeAtomic:
+				return nil, fmt.Errorf("line %d: unknown mode %q", number, mode)
+			case profile.Mode != "" && mode != profile.Mode:
+				return nil, fmt.Errorf("line %d: mode %q does not match %q", number, mode, profile.Mode)
+			}
+			profile.Mode = mode
+			continue
+		}
+		if profile.Mode == "" {
+			return nil, fmt.Errorf("line %d: missing mode line", number)
+		}
+
+		file, block, err := parseBlock(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		key := [4]int{block.StartLine, block.StartCol, block.EndLine, block.EndCol}
+		if seen[file] == nil {
+			seen[file] = make(map[[4]int]*Block)
+			profile.order = append(profile.order, file)
+		}
+		if existing, ok := seen[file][key]; ok {
+			if profile.Mode == ModeSet {
+				existing.Count = max(existing.Count, block.Count)
+			} else {
+				existing.Count += block.Count
+			}
+			continue
+		}
+		seen[file][key] = block
+		profile.Files[file] = append(profile.Files[file], block)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read coverage profile: %w", err)
+	}
+	if profile.Mode == "" {
+		return nil, fmt.Errorf("missing mode line")
+	}
+	return profile, nil
+}
+
+// Parses a block line: file:startLine.startCol,endLine.endCol statements count
+func parseBlock(line string) (string, *Block, error) {
+	colon := strings.LastIndex(line, ":")
+	if colon <= 0 {
+		return "", nil, fmt.Errorf("invalid block %q", line)
+	}
+	file := line[:colon]
+	fields := strings.Fields(line[colon+1:])
+	if len(fields) != 3 {
+		return "", nil, fmt.Errorf("invalid block %q", line)
+	}
+	start, end, ok := strings.Cut(fields[0], ",")
+	if !ok {
+		return "", nil, fmt.Errorf("invalid block range %q", fields[0])
+	}
+
+	var block Block
+	var err error
+	if block.StartLine, block.StartCol, err = parsePosition(start); err != nil {
+		return "", nil, err
+	}
+	if block.EndLine, block.EndCol, err = parsePosition(end); err != nil {
+		return "", nil, err
+	}
+	if block.Statements, err = strconv.Atoi(fields[1]); err != nil {
+		return "", nil, fmt.Errorf("invalid statement count %q", fields[1])
+	}
+	if block.Count, err = strconv.Atoi(fields[2]); err != nil {
+		return "", nil, fmt.Errorf("invalid count %q", fields[2])
+	}
+	return file, &block, nil
+}
+
+// Parses a line.column position
+func parsePosition(s string) (int, int, error) {
+	lineText, colText, ok := strings.Cut(s, ".")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid position %q", s)
+	}
+	line, err := strconv.Atoi(lineText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid position %q", s)
+	}
+	col, err := strconv.Atoi(colText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid position %q", s)
+	}
+	return line, col, nil
+}
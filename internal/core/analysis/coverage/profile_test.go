@@ -0,0 +1,77 @@
+package coverage_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"codedna/internal/core/analysis/coverage"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("File", func(t *testing.T) {
+		profile, err := coverage.ParseFile(filepath.Join("testdata", "cover.out"))
+		if err != nil {
+			t.Fatalf("Failed to parse profile: %v", err)
+		}
+		if profile.Mode != coverage.ModeSet {
+			t.Errorf("Expected mode %q, got %q", coverage.ModeSet, profile.Mode)
+		}
+		names := profile.FileNames()
+		expected := []string{"example.com/shapes/shapes.go", "example.com/other/other.go", "example.com/other/shapes/shapes.go"}
+		if !slices.Equal(names, expected) {
+			t.Fatalf("Expected %v, got %v", expected, names)
+		}
+
+		blocks := profile.Files["example.com/shapes/shapes.go"]
+		if len(blocks) != 12 {
+			t.Fatalf("Expected 12 blocks, got %d", len(blocks))
+		}
+		block := coverage.Block{StartLine: 39, StartCol: 2, EndLine: 40, EndCol: 31, Statements: 2, Count: 1}
+		if *blocks[4] != block {
+			t.Errorf("Expected block %+v, got %+v", block, *blocks[4])
+		}
+	})
+
+	t.Run("MergedBlocks", func(t *testing.T) {
+		for _, tt := range []struct {
+			mode   string
+			counts [2]int
+			count  int
+		}{
+			{coverage.ModeSet, [2]int{1, 0}, 1},
+			{coverage.ModeCount, [2]int{1, 4}, 5},
+		} {
+			src := fmt.Sprintf("mode: %[1]s\nexample.com/a/a.go:3.2,4.1 1 %[2]d\nmode: %[1]s\nexample.com/a/a.go:3.2,4.1 1 %[3]d\n",
+				tt.mode, tt.counts[0], tt.counts[1])
+			profile, err := coverage.Parse(strings.NewReader(src))
+			if err != nil {
+				t.Fatalf("Failed to parse %s profile: %v", tt.mode, err)
+			}
+			blocks := profile.Files["example.com/a/a.go"]
+			if len(blocks) != 1 {
+				t.Fatalf("Expected 1 block in %s mode, got %d", tt.mode, len(blocks))
+			}
+			if blocks[0].Count != tt.count {
+				t.Errorf("Expected count %d in %s mode, got %d", tt.count, tt.mode, blocks[0].Count)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for name, src := range map[string]string{
+			"MissingMode":   "example.com/a/a.go:3.2,4.1 1 1\n",
+			"UnknownMode":   "mode: sometimes\n",
+			"MixedModes":    "mode: set\nmode: count\n",
+			"BadRange":      "mode: set\nexample.com/a/a.go:3.2-4.1 1 1\n",
+			"BadStatements": "mode: set\nexample.com/a/a.go:3.2,4.1 x 1\n",
+			"MissingCount":  "mode: set\nexample.com/a/a.go:3.2,4.1 1\n",
+		} {
+			if _, err := coverage.Parse(strings.NewReader(src)); err == nil {
+				t.Errorf("Expected error for %s", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package shapes
+
+import "math"
+
+// A shape with an area and a perimeter
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+// A square
+type Square struct {
+	Side float64
+}
+
+func (s Square) Area() float64 {
+	return s.Side * s.Side
+}
+
+func (s Square) Perimeter() float64 {
+	return 4 * s.Side
+}
+
+// A circle
+type Circle struct {
+	Radius float64
+}
+
+func (c Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+func (c Circle) Perimeter() float64 {
+	return 2 * math.Pi * c.Radius
+}
+
+// Returns the shape with the largest area
+func Largest(shapes []Shape) Shape {
+	var largest Shape
+	for _, shape := range shapes {
+		if largest == nil || shape.Area() > largest.Area() {
+			largest = shape
+		}
+	}
+	return largest
+}
+
+// Returns the sum of the perimeters of shapes
+func TotalPerimeter(shapes []Shape) float64 {
+	total := 0.0
+	for _, shape := range shapes {
+		total += shape.Perimeter()
+	}
+	return total
+}
+
+func scale(s Square, factor float64) Square {
+	return Square{Side: s.Side * factor}
+}
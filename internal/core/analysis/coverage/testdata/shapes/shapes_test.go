@@ -0,0 +1,10 @@
+package shapes
+
+import "testing"
+
+func TestLargest(t *testing.T) {
+	shapes := []Shape{Square{Side: 2}, Circle{Radius: 1}}
+	if got := Largest(shapes); got != shapes[0] {
+		t.Errorf("Expected the square, got %v", got)
+	}
+}
@@ -0,0 +1,135 @@
+// Package debt scores technical debt from the signals of a code structure
+package debt
+
+import (
+	"fmt"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+)
+
+// A source of technical debt
+type Signal string
+
+const (
+	SignalComplexity     Signal = "complexity"      // Cyclomatic complexity of a function or method
+	SignalSize           Signal = "size"            // Lines of a function or method
+	SignalFanIn          Signal = "fan_in"          // Production functions and methods calling a function or method
+	SignalFanOut         Signal = "fan_out"         // Functions and methods a function or method calls
+	SignalInterfaceBloat Signal = "interface_bloat" // Methods of an interface
+	SignalDeadCode       Signal = "dead_code"       // Unexported function never called or used as a value
+	SignalTodo           Signal = "todo"            // TODO, FIXME, HACK and XXX comments
+	SignalMissingDocs    Signal = "missing_docs"    // Exported declaration without a doc comment
+	SignalChurn          Signal = "churn"           // Commits changing a file
+)
+
+// All signals, in the order they are reported
+var Signals = []Signal{
+	SignalComplexity, SignalSize, SignalFanIn, SignalFanOut, SignalInterfaceBloat,
+	SignalDeadCode, SignalTodo, SignalMissingDocs, SignalChurn,
+}
+
+// Descriptions of the signals
+var Descriptions = map[Signal]string{
+	SignalComplexity:     "Functions and methods with a cyclomatic complexity above the threshold",
+	SignalSize:           "Functions and methods with more lines than the threshold",
+	SignalFanIn:          "Functions and methods called from more places than the threshold, making changes risky",
+	SignalFanOut:         "Functions and methods calling more functions than the threshold",
+	SignalInterfaceBloat: "Interfaces with more methods than the threshold",
+	SignalDeadCode:       "Unexported functions that are never called or used as values",
+	SignalTodo:           "TODO, FIXME, HACK and XXX comments",
+	SignalMissingDocs:    "Exported declarations without a doc comment",
+	SignalChurn:          "Files changed in more commits than the threshold",
+}
+
+// The weight of each signal in scores, and the value above which it counts as debt. A signal
+// over a threshold adds its relative excess (e.g. 0.5 for a complexity of 15 over 10) times its
+// weight; a signal with a threshold of 0 adds its value times its weight.
+type Config struct {
+	Weights    map[Signal]float64
+	Thresholds map[Signal]int
+}
+
+// Creates the default configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Weights: map[Signal]float64{
+			SignalComplexity:     1,
+			SignalSize:           1,
+			SignalFanIn:          0.5,
+			SignalFanOut:         0.5,
+			SignalInterfaceBloat: 1,
+			SignalDeadCode:       1,
+			SignalTodo:           0.5,
+			SignalMissingDocs:    0.25,
+			SignalChurn:          1,
+		},
+		Thresholds: map[Signal]int{
+			SignalComplexity:     10,
+			SignalSize:           60,
+			SignalFanIn:          20,
+			SignalFanOut:         10,
+			SignalInterfaceBloat: 5,
+			SignalDeadCode:       0,
+			SignalTodo:           0,
+			SignalMissingDocs:    0,
+			SignalChurn:          10,
+		},
+	}
+}
+
+// Overrides weights and thresholds by signal name (e.g. from the analysis.debt config section)
+func (c *Config) Configure(weights map[string]float64, thresholds map[string]int) error {
+	for name, weight := range weights {
+		if _, ok := Descriptions[Signal(name)]; !ok {
+			return fmt.Errorf("unknown debt signal %q", name)
+		}
+		if weight < 0 {
+			return fmt.Errorf("negative weight %v for debt signal %q", weight, name)
+		}
+		c.Weights[Signal(name)] = weight
+	}
+	for name, threshold := range thresholds {
+		if _, ok := Descriptions[Signal(name)]; !ok {
+			return fmt.Errorf("unknown debt signal %q", name)
+		}
+		if threshold < 0 {
+			return fmt.Errorf("negative threshold %d for debt signal %q", threshold, name)
+		}
+		c.Thresholds[Signal(name)] = threshold
+	}
+	return nil
+}
+
+// The debt a signal adds to an element
+type Item struct {
+	Signal    Signal
+	Value     int // The measured value (e.g. the complexity)
+	Threshold int
+	Points    float64
+	Reason    string // Explanation (e.g. "cyclomatic complexity 15 exceeds 10")
+}
+
+// The debt of an element. Package elements carry the debt of their file.
+type ElementScore struct {
+	Element *gostructure.Element
+	Name    string // Qualified by the receiver type for methods (e.g. "Store.Add"), the file for package elements
+	Dir     string // Directory of the element's package
+	Score   float64
+	Items   []*Item // Highest points first
+}
+
+// The debt of a package: the elements of a directory
+type PackageScore struct {
+	Name     string
+	Dir      string
+	Score    float64
+	Elements int                // Elements with debt
+	Signals  map[Signal]float64 // Points per signal
+}
+
+// The debt of a structure
+type Report struct {
+	Score    float64
+	Elements []*ElementScore // Elements with debt, highest score first
+	Packages []*PackageScore // Packages with debt, highest score first
+}
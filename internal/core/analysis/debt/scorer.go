@@ -0,0 +1,256 @@
+package debt
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+)
+
+// Scores the technical debt of code structures
+type Scorer struct {
+	config *Config
+	churn  map[string]int // Absolute file path -> commits changing it
+}
+
+// Creates a new scorer. A nil config uses the default configuration.
+func NewScorer(config *Config) *Scorer {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Scorer{config: config, churn: make(map[string]int)}
+}
+
+// Sets the number of commits changing each file, keyed by absolute path (e.g. from git log)
+func (s *Scorer) SetChurn(churn map[string]int) {
+	s.churn = churn
+}
+
+// Relationships of the elements of a structure needed to score them
+type graph struct {
+	callers    map[*gostructure.Element]map[*gostructure.Element]bool // Callee -> callers
+	callees    map[*gostructure.Element]int                           // Caller -> callees
+	receivers  map[*gostructure.Element]*gostructure.Element          // Method -> receiver type
+	funcValues map[string]map[string]bool                             // Scope -> functions used as values
+	packages   map[string]*gostructure.Element                        // Directory -> package element
+}
+
+// Indexes the relationships of a structure
+func newGraph(structure *gostructure.Structure) *graph {
+	g := &graph{
+		callers:    make(map[*gostructure.Element]map[*gostructure.Element]bool),
+		callees:    make(map[*gostructure.Element]int),
+		receivers:  make(map[*gostructure.Element]*gostructure.Element),
+		funcValues: make(map[string]map[string]bool),
+		packages:   make(map[string]*gostructure.Element),
+	}
+	for _, rel := range structure.Relationships {
+		switch rel.Type {
+		case gostructure.RelationCalls:
+			if g.callers[rel.Target] == nil {
+				g.callers[rel.Target] = make(map[*gostructure.Element]bool)
+			}
+			g.callers[rel.Target][rel.Source] = true
+			g.callees[rel.Source]++
+		case gostructure.RelationMethodReceiver:
+			g.receivers[rel.Source] = rel.Target
+		}
+	}
+	for _, elem := range structure.Elements {
+		if elem.Type != gostructure.ElementPackage {
+			continue
+		}
+		values, _ := elem.Attributes["func_values"].([]string)
+		if g.funcValues[elem.Scope] == nil {
+			g.funcValues[elem.Scope] = make(map[string]bool)
+		}
+		for _, name := range values {
+			g.funcValues[elem.Scope][name] = true
+		}
+		// Name packages after their production files
+		dir := filepath.Dir(elem.Position.Filename)
+		if g.packages[dir] == nil || g.packages[dir].IsTest() {
+			g.packages[dir] = elem
+		}
+	}
+	return g
+}
+
+// Scores the handwritten production code of a structure. Test and generated code are left out.
+func (s *Scorer) Score(structure *gostructure.Structure) *Report {
+	g := newGraph(structure)
+	report := &Report{}
+	packages := make(map[string]*PackageScore)
+	for _, elem := range structure.Elements {
+		if elem.IsTest() || elem.IsGenerated() {
+			continue
+		}
+		items := s.items(elem, g)
+		if len(items) == 0 {
+			continue
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].Points > items[j].Points
+		})
+
+		score := &ElementScore{Element: elem, Name: g.name(elem), Dir: filepath.Dir(elem.Position.Filename), Items: items}
+		for _, item := range items {
+			score.Score += item.Points
+		}
+		report.Elements = append(report.Elements, score)
+		report.Score += score.Score
+
+		pkg, ok := packages[score.Dir]
+		if !ok {
+			pkg = &PackageScore{Dir: score.Dir, Signals: make(map[Signal]float64)}
+			if elem := g.packages[score.Dir]; elem != nil {
+				pkg.Name = elem.Name
+			}
+			packages[score.Dir] = pkg
+			report.Packages = append(report.Packages, pkg)
+		}
+		pkg.Score += score.Score
+		pkg.Elements++
+		for _, item := range items {
+			pkg.Signals[item.Signal] += item.Points
+		}
+	}
+
+	sort.SliceStable(report.Elements, func(i, j int) bool {
+		a, b := report.Elements[i], report.Elements[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Element.Position.Filename != b.Element.Position.Filename {
+			return a.Element.Position.Filename < b.Element.Position.Filename
+		}
+		return a.Element.Position.Line < b.Element.Position.Line
+	})
+	sort.SliceStable(report.Packages, func(i, j int) bool {
+		a, b := report.Packages[i], report.Packages[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Dir < b.Dir
+	})
+	return report
+}
+
+// Returns the name an element is reported under
+func (g *graph) name(elem *gostructure.Element) string {
+	switch {
+	case elem.Type == gostructure.ElementPackage:
+		return elem.Position.Filename
+	case g.receivers[elem] != nil:
+		return g.receivers[elem].Name + "." + elem.Name
+	}
+	return elem.Name
+}
+
+// Returns the debt items of an element
+func (s *Scorer) items(elem *gostructure.Element, g *graph) []*Item {
+	var items []*Item
+	add := func(signal Signal, value int, reason string) {
+		if item := s.item(signal, value, reason); item != nil {
+			items = append(items, item)
+		}
+	}
+
+	switch elem.Type {
+	case gostructure.ElementFunction, gostructure.ElementMethod:
+		if complexity, ok := elem.Attributes["complexity"].(int); ok {
+			add(SignalComplexity, complexity, fmt.Sprintf("cyclomatic complexity %d exceeds %d", complexity, s.config.Thresholds[SignalComplexity]))
+		}
+		if end, ok := elem.Attributes["end_line"].(int); ok {
+			lines := end - elem.Position.Line + 1
+			add(SignalSize, lines, fmt.Sprintf("%d lines exceed %d", lines, s.config.Thresholds[SignalSize]))
+		}
+
+		callers := 0
+		for caller := range g.callers[elem] {
+			if !caller.IsTest() {
+				callers++
+			}
+		}
+		add(SignalFanIn, callers, fmt.Sprintf("called by %d functions, more than %d", callers, s.config.Thresholds[SignalFanIn]))
+		add(SignalFanOut, g.callees[elem], fmt.Sprintf("calls %d functions, more than %d", g.callees[elem], s.config.Thresholds[SignalFanOut]))
+
+		if g.isDead(elem) {
+			add(SignalDeadCode, 1, "unexported and never called or used as a value")
+		}
+		if isExported(elem) && (g.receivers[elem] == nil || isExported(g.receivers[elem])) {
+			add(SignalMissingDocs, missingDoc(elem), "exported without a doc comment")
+		}
+		count, reason := todos(elem)
+		add(SignalTodo, count, reason)
+
+	case gostructure.ElementInterface:
+		methods, _ := elem.Attributes["methods"].([]map[string]any)
+		add(SignalInterfaceBloat, len(methods), fmt.Sprintf("%d methods exceed %d", len(methods), s.config.Thresholds[SignalInterfaceBloat]))
+		if isExported(elem) {
+			add(SignalMissingDocs, missingDoc(elem), "exported without a doc comment")
+		}
+
+	case gostructure.ElementTypeDecl:
+		if isExported(elem) {
+			add(SignalMissingDocs, missingDoc(elem), "exported without a doc comment")
+		}
+
+	case gostructure.ElementPackage:
+		count, reason := todos(elem)
+		add(SignalTodo, count, reason)
+		if abs, err := filepath.Abs(elem.Position.Filename); err == nil {
+			commits := s.churn[abs]
+			add(SignalChurn, commits, fmt.Sprintf("changed in %d commits, more than %d", commits, s.config.Thresholds[SignalChurn]))
+		}
+	}
+	return items
+}
+
+// Returns the item a signal adds for a value, or nil when the value is not over the threshold
+// or the signal has no weight
+func (s *Scorer) item(signal Signal, value int, reason string) *Item {
+	weight, threshold := s.config.Weights[signal], s.config.Thresholds[signal]
+	if weight == 0 || value <= threshold {
+		return nil
+	}
+	amount := float64(value)
+	if threshold > 0 {
+		amount = float64(value-threshold) / float64(threshold)
+	}
+	return &Item{Signal: signal, Value: value, Threshold: threshold, Points: weight * amount, Reason: reason}
+}
+
+// Checks if an element is an unexported function that is never called nor used as a value
+func (g *graph) isDead(elem *gostructure.Element) bool {
+	if elem.Type != gostructure.ElementFunction || isExported(elem) {
+		return false
+	}
+	switch elem.Name {
+	case "main", "init", "_":
+		return false
+	}
+	return len(g.callers[elem]) == 0 && !g.funcValues[elem.Scope][elem.Name]
+}
+
+// Returns 1 if an element has no doc comment, 0 otherwise
+func missingDoc(elem *gostructure.Element) int {
+	if doc, _ := elem.Attributes["doc"].(string); doc == "" {
+		return 1
+	}
+	return 0
+}
+
+// Returns the number of TODO comments of an element and an explanation listing them
+func todos(elem *gostructure.Element) (int, string) {
+	todos, _ := elem.Attributes["todos"].([]string)
+	return len(todos), "TODO comments: " + strings.Join(todos, "; ")
+}
+
+// Checks if an element is exported
+func isExported(elem *gostructure.Element) bool {
+	exported, _ := elem.Attributes["is_exported"].(bool)
+	return exported
+}
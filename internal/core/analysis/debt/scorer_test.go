@@ -0,0 +1,136 @@
+package debt_test
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/analysis/debt"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+)
+
+// Helper function to score the inventory package in testdata
+func scoreInventory(t *testing.T, config *debt.Config, churn map[string]int) *debt.Report {
+	t.Helper()
+	analysis, err := gostructure.NewWorkspace(gostructure.NewAnalyzer()).Update([]string{filepath.Join("testdata", "inventory")})
+	if err != nil {
+		t.Fatalf("Failed to analyze testdata: %v", err)
+	}
+	scorer := debt.NewScorer(config)
+	scorer.SetChurn(churn)
+	return scorer.Score(analysis.Structure)
+}
+
+// Helper function to compare scores
+func equalScore(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestScorer(t *testing.T) {
+	config := debt.DefaultConfig()
+	if err := config.Configure(nil, map[string]int{"complexity": 5}); err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+	file, err := filepath.Abs(filepath.Join("testdata", "inventory", "inventory.go"))
+	if err != nil {
+		t.Fatalf("Failed to resolve path: %v", err)
+	}
+	report := scoreInventory(t, config, map[string]int{file: 15})
+
+	t.Run("Ranking", func(t *testing.T) {
+		var got []string
+		for _, elem := range report.Elements {
+			got = append(got, fmt.Sprintf("%s %.2f", filepath.Base(elem.Name), elem.Score))
+		}
+		expected := []string{
+			"inventory.go 1.00",
+			"legacyLevel 1.00",
+			"Inventory.Set 0.75",
+			"Store 0.40",
+			"Level 0.40",
+			"Inventory 0.25",
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("Expected ranking %q, got %q", expected, got)
+		}
+		if !equalScore(report.Score, 3.8) {
+			t.Errorf("Expected total score 3.8, got %f", report.Score)
+		}
+	})
+
+	t.Run("Explanations", func(t *testing.T) {
+		for _, elem := range report.Elements {
+			var got []string
+			for _, item := range elem.Items {
+				got = append(got, fmt.Sprintf("%s: %s", item.Signal, item.Reason))
+			}
+			var expected []string
+			switch elem.Name {
+			case "Inventory.Set":
+				expected = []string{"todo: TODO comments: FIXME: reject negative counts", "missing_docs: exported without a doc comment"}
+			case "Level":
+				expected = []string{"complexity: cyclomatic complexity 7 exceeds 5"}
+			case "Store":
+				expected = []string{"interface_bloat: 7 methods exceed 5"}
+			case "legacyLevel":
+				expected = []string{"dead_code: unexported and never called or used as a value"}
+			default:
+				continue
+			}
+			if !slices.Equal(got, expected) {
+				t.Errorf("Expected %s items %q, got %q", elem.Name, expected, got)
+			}
+		}
+	})
+
+	t.Run("Packages", func(t *testing.T) {
+		if len(report.Packages) != 1 {
+			t.Fatalf("Expected 1 package, got %d", len(report.Packages))
+		}
+		pkg := report.Packages[0]
+		if pkg.Name != "inventory" || pkg.Elements != 6 || !equalScore(pkg.Score, report.Score) {
+			t.Errorf("Expected inventory package with 6 elements and the total score, got %+v", pkg)
+		}
+		expected := map[debt.Signal]float64{
+			debt.SignalTodo:           1,
+			debt.SignalChurn:          0.5,
+			debt.SignalDeadCode:       1,
+			debt.SignalMissingDocs:    0.5,
+			debt.SignalComplexity:     0.4,
+			debt.SignalInterfaceBloat: 0.4,
+		}
+		for signal, points := range expected {
+			if !equalScore(pkg.Signals[signal], points) {
+				t.Errorf("Expected %s points %.2f, got %.2f", signal, points, pkg.Signals[signal])
+			}
+		}
+	})
+
+	t.Run("Weights", func(t *testing.T) {
+		config := debt.DefaultConfig()
+		if err := config.Configure(map[string]float64{"dead_code": 0, "missing_docs": 2}, nil); err != nil {
+			t.Fatalf("Failed to configure: %v", err)
+		}
+		report := scoreInventory(t, config, nil)
+		for _, elem := range report.Elements {
+			if elem.Name == "legacyLevel" {
+				t.Errorf("Expected dead code to be ignored without weight, got %+v", elem)
+			}
+			if elem.Name == "Inventory" && !equalScore(elem.Score, 2) {
+				t.Errorf("Expected Inventory score 2, got %f", elem.Score)
+			}
+		}
+	})
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		config := debt.DefaultConfig()
+		if err := config.Configure(map[string]float64{"style": 1}, nil); err == nil {
+			t.Error("Expected error for unknown signal")
+		}
+		if err := config.Configure(nil, map[string]int{"size": -1}); err == nil {
+			t.Error("Expected error for negative threshold")
+		}
+	})
+}
@@ -0,0 +1,66 @@
+// Package inventory tracks stock levels.
+//
+// TODO: persist the stock
+package inventory
+
+import "errors"
+
+// Returned for items that are not in stock
+var ErrUnknown = errors.New("unknown item")
+
+// A store of stock levels
+type Store interface {
+	Get(name string) (int, error)
+	Set(name string, count int)
+	Delete(name string)
+	Names() []string
+	Len() int
+	Reset()
+	Close() error
+}
+
+type Inventory struct {
+	stock map[string]int
+}
+
+// Creates an empty inventory
+func New() *Inventory {
+	return &Inventory{stock: make(map[string]int)}
+}
+
+// Returns the stock of an item
+func (i *Inventory) Get(name string) (int, error) {
+	count, ok := i.stock[name]
+	if !ok {
+		return 0, ErrUnknown
+	}
+	return count, nil
+}
+
+func (i *Inventory) Set(name string, count int) {
+	// FIXME: reject negative counts
+	i.stock[name] = count
+}
+
+// Classifies a stock level
+func Level(count int) string {
+	switch {
+	case count < 0:
+		return "invalid"
+	case count == 0:
+		return "empty"
+	case count < 10:
+		return "low"
+	case count < 100:
+		return "normal"
+	case count < 1000 && count%2 == 0:
+		return "high"
+	}
+	return "huge"
+}
+
+func legacyLevel(count int) string { return Level(count) }
+
+func validate(name string) bool { return name != "" }
+
+var checks = []func(string) bool{validate}
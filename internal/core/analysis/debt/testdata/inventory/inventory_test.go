@@ -0,0 +1,14 @@
+package inventory
+
+import "testing"
+
+func TestGet(t *testing.T) {
+	inventory := New()
+	inventory.Set("apples", 3)
+	if count, err := inventory.Get("apples"); err != nil || count != 3 {
+		t.Errorf("Expected 3 apples, got %d (%v)", count, err)
+	}
+}
+
+// TODO: test the levels
+func unusedHelper() {}
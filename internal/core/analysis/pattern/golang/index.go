@@ -0,0 +1,120 @@
+package gopattern
+
+import (
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Lookup tables over a structure shared by the matchers
+type index struct {
+	structure    *gostructure.Structure
+	types        map[string]*gostructure.Element   // Type declarations and interfaces by name
+	methods      map[string][]*gostructure.Element // Methods by receiver type name
+	implements   map[*gostructure.Element][]*gostructure.Element
+	implementers map[*gostructure.Element][]*gostructure.Element
+}
+
+// Builds the lookup tables for a structure
+func newIndex(structure *gostructure.Structure) *index {
+	idx := &index{
+		structure:    structure,
+		types:        make(map[string]*gostructure.Element),
+		methods:      make(map[string][]*gostructure.Element),
+		implements:   make(map[*gostructure.Element][]*gostructure.Element),
+		implementers: make(map[*gostructure.Element][]*gostructure.Element),
+	}
+
+	for _, elem := range structure.Elements {
+		switch elem.Type {
+		case gostructure.ElementTypeDecl, gostructure.ElementInterface:
+			if _, ok := idx.types[elem.Name]; !ok {
+				idx.types[elem.Name] = elem
+			}
+		case gostructure.ElementMethod:
+			recv, _ := elem.Attributes["receiver_type"].(*goparser.TypeInfo)
+			if name := namedType(recv); name != "" {
+				idx.methods[name] = append(idx.methods[name], elem)
+			}
+		}
+	}
+
+	for _, rel := range structure.Relationships {
+		if rel.Type == gostructure.RelationImplements {
+			idx.implements[rel.Source] = append(idx.implements[rel.Source], rel.Target)
+			idx.implementers[rel.Target] = append(idx.implementers[rel.Target], rel.Source)
+		}
+	}
+	return idx
+}
+
+// Returns the type or interface element named by a (possibly pointer) type
+func (idx *index) lookup(typeInfo *goparser.TypeInfo) *gostructure.Element {
+	return idx.types[namedType(typeInfo)]
+}
+
+// Checks if a type implements an interface
+func (idx *index) implementsInterface(typ, iface *gostructure.Element) bool {
+	for _, impl := range idx.implements[typ] {
+		if impl == iface {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the elements of the given type
+func (idx *index) elementsOfType(elemType gostructure.ElementType) []*gostructure.Element {
+	var result []*gostructure.Element
+	for _, elem := range idx.structure.Elements {
+		if elem.Type == elemType {
+			result = append(result, elem)
+		}
+	}
+	return result
+}
+
+// Returns the name of a (possibly pointer) named type
+func namedType(typeInfo *goparser.TypeInfo) string {
+	if typeInfo == nil {
+		return ""
+	}
+	if typeInfo.Kind == "pointer" {
+		return namedType(typeInfo.ElemType)
+	}
+	if typeInfo.Kind == "basic" {
+		return typeInfo.Name
+	}
+	return ""
+}
+
+// Returns the parameter and result types of a function, method or function type
+func signature(elem *gostructure.Element) (params, returns []*goparser.TypeInfo) {
+	sig, _ := elem.Attributes["signature"].(map[string]any)
+	params, _ = sig["params"].([]*goparser.TypeInfo)
+	returns, _ = sig["returns"].([]*goparser.TypeInfo)
+	return params, returns
+}
+
+// Checks if a function's last parameter is variadic
+func isVariadic(elem *gostructure.Element) bool {
+	sig, _ := elem.Attributes["signature"].(map[string]any)
+	variadic, _ := sig["variadic"].(bool)
+	return variadic
+}
+
+// Returns the fields of a struct type element
+func structFields(elem *gostructure.Element) []map[string]any {
+	fields, _ := elem.Attributes["fields"].([]map[string]any)
+	return fields
+}
+
+// Returns the type of a struct field
+func fieldType(field map[string]any) *goparser.TypeInfo {
+	typeInfo, _ := field["type"].(*goparser.TypeInfo)
+	return typeInfo
+}
+
+// Checks if a result list is empty or a single error
+func returnsNothingOrError(returns []*goparser.TypeInfo) bool {
+	return len(returns) == 0 || (len(returns) == 1 && namedType(returns[0]) == "error")
+}
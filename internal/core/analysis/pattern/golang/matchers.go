@@ -0,0 +1,382 @@
+package gopattern
+
+import (
+	"strings"
+	"unicode"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Recognizes functional options: an option type applied to a *T, the functions
+// producing options and the variadic functions consuming them
+type functionalOptionsMatcher struct{}
+
+func (m *functionalOptionsMatcher) Name() string { return "functional_options" }
+
+func (m *functionalOptionsMatcher) Match(structure *gostructure.Structure) []*Match {
+	idx := newIndex(structure)
+	var matches []*Match
+
+	for _, elem := range structure.Elements {
+		target := m.optionTarget(idx, elem)
+		if target == nil {
+			continue
+		}
+
+		match := &Match{
+			Pattern:  PatternFunctionalOptions,
+			Position: elem.Position,
+			Detail:   elem.Name + " -> *" + target.Name,
+			Participants: []*Participant{
+				{Role: RoleOption, Element: elem},
+				{Role: RoleTarget, Element: target},
+			},
+		}
+		for _, fn := range append(idx.elementsOfType(gostructure.ElementFunction), idx.elementsOfType(gostructure.ElementMethod)...) {
+			params, returns := signature(fn)
+			if len(returns) == 1 && namedType(returns[0]) == elem.Name {
+				match.Participants = append(match.Participants, &Participant{Role: RoleOptionFunction, Element: fn})
+			}
+			if isVariadic(fn) && len(params) > 0 {
+				if last := params[len(params)-1]; last.Kind == "slice" && namedType(last.ElemType) == elem.Name {
+					match.Participants = append(match.Participants, &Participant{Role: RoleConsumer, Element: fn})
+				}
+			}
+		}
+
+		// An option type nobody produces or consumes is just a callback type
+		if len(match.Participants) > 2 {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// Returns the type an option type configures, or nil if the element is not an option type.
+// Both func(*T) types and single-method interfaces taking a *T qualify.
+func (m *functionalOptionsMatcher) optionTarget(idx *index, elem *gostructure.Element) *gostructure.Element {
+	var params, returns []*goparser.TypeInfo
+	switch elem.Type {
+	case gostructure.ElementTypeDecl:
+		if _, ok := elem.Attributes["signature"]; !ok {
+			return nil
+		}
+		params, returns = signature(elem)
+	case gostructure.ElementInterface:
+		methods, _ := elem.Attributes["methods"].([]map[string]any)
+		if len(methods) != 1 {
+			return nil
+		}
+		sig, _ := methods[0]["signature"].(map[string]any)
+		params, _ = sig["params"].([]*goparser.TypeInfo)
+		returns, _ = sig["returns"].([]*goparser.TypeInfo)
+	default:
+		return nil
+	}
+
+	if len(params) != 1 || params[0].Kind != "pointer" || !returnsNothingOrError(returns) {
+		return nil
+	}
+	target := idx.lookup(params[0])
+	if target == nil || target == elem || target.Type != gostructure.ElementTypeDecl {
+		return nil
+	}
+	return target
+}
+
+// Recognizes NewX constructors, distinguishing those returning interfaces from
+// those returning concrete types
+type constructorMatcher struct{}
+
+func (m *constructorMatcher) Name() string { return "constructor" }
+
+func (m *constructorMatcher) Match(structure *gostructure.Structure) []*Match {
+	idx := newIndex(structure)
+	var matches []*Match
+
+	for _, fn := range idx.elementsOfType(gostructure.ElementFunction) {
+		if !isConstructorName(fn.Name) {
+			continue
+		}
+		_, returns := signature(fn)
+		if len(returns) == 0 {
+			continue
+		}
+		product := idx.lookup(returns[0])
+		if product == nil {
+			continue
+		}
+
+		pattern := PatternConstructorConcrete
+		if product.Type == gostructure.ElementInterface {
+			pattern = PatternConstructorInterface
+		}
+		matches = append(matches, &Match{
+			Pattern:  pattern,
+			Position: fn.Position,
+			Detail:   returns[0].String(),
+			Participants: []*Participant{
+				{Role: RoleConstructor, Element: fn},
+				{Role: RoleProduct, Element: product},
+			},
+		})
+	}
+	return matches
+}
+
+// Checks if a function is named New or NewX
+func isConstructorName(name string) bool {
+	rest, ok := strings.CutPrefix(name, "New")
+	return ok && (rest == "" || unicode.IsUpper(rune(rest[0])))
+}
+
+// Recognizes builders: types with at least two chainable methods returning the
+// builder itself and a Build method producing the result
+type builderMatcher struct{}
+
+// Minimum number of chainable methods for a type to be considered a builder
+const minBuilderSteps = 2
+
+func (m *builderMatcher) Name() string { return "builder" }
+
+func (m *builderMatcher) Match(structure *gostructure.Structure) []*Match {
+	idx := newIndex(structure)
+	var matches []*Match
+
+	for _, typ := range idx.elementsOfType(gostructure.ElementTypeDecl) {
+		var steps []*gostructure.Element
+		var build *gostructure.Element
+		for _, method := range idx.methods[typ.Name] {
+			_, returns := signature(method)
+			switch {
+			case strings.HasPrefix(method.Name, "Build"):
+				build = method
+			case len(returns) == 1 && namedType(returns[0]) == typ.Name:
+				steps = append(steps, method)
+			}
+		}
+		if build == nil || len(steps) < minBuilderSteps {
+			continue
+		}
+
+		match := &Match{
+			Pattern:  PatternBuilder,
+			Position: typ.Position,
+			Participants: []*Participant{
+				{Role: RoleBuilder, Element: typ},
+				{Role: RoleBuild, Element: build},
+			},
+		}
+		for _, step := range steps {
+			match.Participants = append(match.Participants, &Participant{Role: RoleStep, Element: step})
+		}
+		if _, returns := signature(build); len(returns) > 0 {
+			match.Detail = returns[0].String()
+			if product := idx.lookup(returns[0]); product != nil {
+				match.Participants = append(match.Participants, &Participant{Role: RoleProduct, Element: product})
+			}
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// Recognizes singletons: a package-level sync.Once used by an accessor function
+type singletonMatcher struct{}
+
+func (m *singletonMatcher) Name() string { return "singleton" }
+
+func (m *singletonMatcher) Match(structure *gostructure.Structure) []*Match {
+	idx := newIndex(structure)
+	var matches []*Match
+
+	onces := make(map[string]*gostructure.Element)
+	for _, v := range idx.elementsOfType(gostructure.ElementVariable) {
+		if typeInfo, ok := v.Attributes["type"].(*goparser.TypeInfo); ok && typeInfo.String() == "sync.Once" {
+			onces[v.Name+".Do"] = v
+		}
+	}
+	if len(onces) == 0 {
+		return nil
+	}
+
+	for _, fn := range idx.elementsOfType(gostructure.ElementFunction) {
+		patterns, _ := fn.Attributes["concurrency_patterns"].([]*goparser.ConcurrencyPattern)
+		for _, pattern := range patterns {
+			once, ok := onces[pattern.Detail]
+			if pattern.Kind != goparser.ConcurrencyOnce || !ok {
+				continue
+			}
+
+			match := &Match{
+				Pattern:  PatternSingleton,
+				Position: fn.Position,
+				Participants: []*Participant{
+					{Role: RoleAccessor, Element: fn},
+					{Role: RoleOnce, Element: once},
+				},
+			}
+			if _, returns := signature(fn); len(returns) > 0 {
+				match.Detail = returns[0].String()
+				if instance := idx.lookup(returns[0]); instance != nil {
+					match.Participants = append(match.Participants, &Participant{Role: RoleInstance, Element: instance})
+				}
+			}
+			matches = append(matches, match)
+			break
+		}
+	}
+	return matches
+}
+
+// Recognizes decorators (types wrapping the interface they implement) and
+// adapters (types or function types exposing something else through an interface)
+type wrapperMatcher struct{}
+
+func (m *wrapperMatcher) Name() string { return "wrapper" }
+
+func (m *wrapperMatcher) Match(structure *gostructure.Structure) []*Match {
+	idx := newIndex(structure)
+	var matches []*Match
+
+	for _, typ := range idx.elementsOfType(gostructure.ElementTypeDecl) {
+		for _, iface := range idx.implements[typ] {
+			if match := m.match(idx, typ, iface); match != nil {
+				matches = append(matches, match)
+			}
+		}
+	}
+	return matches
+}
+
+// Returns the decorator or adapter match of a type implementing an interface, or nil
+func (m *wrapperMatcher) match(idx *index, typ, iface *gostructure.Element) *Match {
+	// Function types with methods (e.g. type HandlerFunc func(...)) adapt plain functions
+	if _, ok := typ.Attributes["signature"]; ok {
+		return &Match{
+			Pattern:  PatternAdapter,
+			Position: typ.Position,
+			Detail:   "func -> " + iface.Name,
+			Participants: []*Participant{
+				{Role: RoleAdapter, Element: typ},
+				{Role: RoleTarget, Element: iface},
+			},
+		}
+	}
+
+	fields := structFields(typ)
+	for _, field := range fields {
+		if namedType(fieldType(field)) == iface.Name {
+			return &Match{
+				Pattern:  PatternDecorator,
+				Position: typ.Position,
+				Detail:   iface.Name,
+				Participants: []*Participant{
+					{Role: RoleDecorator, Element: typ},
+					{Role: RoleComponent, Element: iface},
+				},
+			}
+		}
+	}
+
+	// An adapter wraps a single adaptee that does not implement the interface itself
+	if len(fields) != 1 {
+		return nil
+	}
+	adaptee := idx.lookup(fieldType(fields[0]))
+	if adaptee == nil || adaptee == typ || adaptee == iface || idx.implementsInterface(adaptee, iface) {
+		return nil
+	}
+	return &Match{
+		Pattern:  PatternAdapter,
+		Position: typ.Position,
+		Detail:   adaptee.Name + " -> " + iface.Name,
+		Participants: []*Participant{
+			{Role: RoleAdapter, Element: typ},
+			{Role: RoleTarget, Element: iface},
+			{Role: RoleAdaptee, Element: adaptee},
+		},
+	}
+}
+
+// Name suffixes marking data access types
+var repositorySuffixes = []string{"Repository", "Repo", "Store"}
+
+// Name suffixes marking service layer types
+var serviceSuffixes = []string{"Service"}
+
+// Recognizes repositories and the services layered on top of them
+type layeringMatcher struct{}
+
+func (m *layeringMatcher) Name() string { return "layering" }
+
+func (m *layeringMatcher) Match(structure *gostructure.Structure) []*Match {
+	idx := newIndex(structure)
+	var matches []*Match
+
+	// Repository interfaces come first so their implementations are not reported on their own
+	repositories := make(map[string]*gostructure.Element)
+	candidates := append(idx.elementsOfType(gostructure.ElementInterface), idx.elementsOfType(gostructure.ElementTypeDecl)...)
+	for _, elem := range candidates {
+		if !hasSuffix(elem.Name, repositorySuffixes) || m.implementsRepository(idx, elem, repositories) {
+			continue
+		}
+		repositories[elem.Name] = elem
+
+		match := &Match{
+			Pattern:  PatternRepository,
+			Position: elem.Position,
+			Detail:   string(elem.Type),
+			Participants: []*Participant{
+				{Role: RoleRepository, Element: elem},
+			},
+		}
+		for _, impl := range idx.implementers[elem] {
+			match.Participants = append(match.Participants, &Participant{Role: RoleImplementation, Element: impl})
+		}
+		matches = append(matches, match)
+	}
+
+	for _, typ := range idx.elementsOfType(gostructure.ElementTypeDecl) {
+		if !hasSuffix(typ.Name, serviceSuffixes) {
+			continue
+		}
+		for _, field := range structFields(typ) {
+			repo, ok := repositories[namedType(fieldType(field))]
+			if !ok {
+				continue
+			}
+			matches = append(matches, &Match{
+				Pattern:  PatternServiceLayer,
+				Position: typ.Position,
+				Detail:   typ.Name + " -> " + repo.Name,
+				Participants: []*Participant{
+					{Role: RoleService, Element: typ},
+					{Role: RoleRepository, Element: repo},
+				},
+			})
+		}
+	}
+	return matches
+}
+
+// Checks if a type implements one of the repository interfaces already found
+func (m *layeringMatcher) implementsRepository(idx *index, typ *gostructure.Element, repositories map[string]*gostructure.Element) bool {
+	for _, iface := range idx.implements[typ] {
+		if repositories[iface.Name] == iface {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if a name ends with one of the suffixes (and is not just the suffix)
+func hasSuffix(name string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if len(name) > len(suffix) && strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+// Package gopattern recognizes Go design idioms in an analyzed code structure
+package gopattern
+
+import (
+	"sort"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/parser/ast"
+)
+
+// The kind of design pattern recognized
+type PatternKind string
+
+const (
+	PatternFunctionalOptions    PatternKind = "functional_options"    // type Option func(*T) with WithX functions
+	PatternConstructorConcrete  PatternKind = "constructor_concrete"  // NewX returning a concrete type
+	PatternConstructorInterface PatternKind = "constructor_interface" // NewX returning an interface
+	PatternBuilder              PatternKind = "builder"               // chainable setters with a Build method
+	PatternSingleton            PatternKind = "singleton"             // package-level sync.Once guarding an instance
+	PatternDecorator            PatternKind = "decorator"             // type wrapping the interface it implements
+	PatternAdapter              PatternKind = "adapter"               // type adapting another type to an interface
+	PatternRepository           PatternKind = "repository"            // data access abstraction (XRepository, XStore)
+	PatternServiceLayer         PatternKind = "service_layer"         // service depending on a repository
+)
+
+// The role an element plays in a pattern
+type Role string
+
+const (
+	RoleOption         Role = "option"
+	RoleOptionFunction Role = "option_function"
+	RoleTarget         Role = "target"
+	RoleConsumer       Role = "consumer"
+	RoleConstructor    Role = "constructor"
+	RoleProduct        Role = "product"
+	RoleBuilder        Role = "builder"
+	RoleStep           Role = "step"
+	RoleBuild          Role = "build"
+	RoleOnce           Role = "once"
+	RoleAccessor       Role = "accessor"
+	RoleInstance       Role = "instance"
+	RoleDecorator      Role = "decorator"
+	RoleComponent      Role = "component"
+	RoleAdapter        Role = "adapter"
+	RoleAdaptee        Role = "adaptee"
+	RoleRepository     Role = "repository"
+	RoleImplementation Role = "implementation"
+	RoleService        Role = "service"
+)
+
+// An element taking part in a pattern
+type Participant struct {
+	Role    Role
+	Element *gostructure.Element
+}
+
+// A recognized occurrence of a pattern
+type Match struct {
+	Pattern      PatternKind
+	Position     ast.Position // Location of the element anchoring the pattern
+	Detail       string
+	Participants []*Participant
+}
+
+// Returns the participants playing the given role
+func (m *Match) Role(role Role) []*gostructure.Element {
+	var elements []*gostructure.Element
+	for _, p := range m.Participants {
+		if p.Role == role {
+			elements = append(elements, p.Element)
+		}
+	}
+	return elements
+}
+
+// Recognizes one or more patterns in a structure
+type Matcher interface {
+	// Returns a unique name for the matcher
+	Name() string
+
+	// Returns the pattern occurrences found in the structure
+	Match(structure *gostructure.Structure) []*Match
+}
+
+// Returns the built-in matchers
+func DefaultMatchers() []Matcher {
+	return []Matcher{
+		&functionalOptionsMatcher{},
+		&constructorMatcher{},
+		&builderMatcher{},
+		&singletonMatcher{},
+		&wrapperMatcher{},
+		&layeringMatcher{},
+	}
+}
+
+// Runs a set of matchers over a structure
+type Recognizer struct {
+	matchers []Matcher
+}
+
+// Creates a new recognizer with the built-in matchers
+func NewRecognizer() *Recognizer {
+	return &Recognizer{matchers: DefaultMatchers()}
+}
+
+// Adds matchers, replacing any registered matcher with the same name
+func (r *Recognizer) Register(matchers ...Matcher) {
+	for _, m := range matchers {
+		replaced := false
+		for i, existing := range r.matchers {
+			if existing.Name() == m.Name() {
+				r.matchers[i] = m
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			r.matchers = append(r.matchers, m)
+		}
+	}
+}
+
+// Returns the registered matchers
+func (r *Recognizer) Matchers() []Matcher {
+	return r.matchers
+}
+
+// Recognizes the patterns in the structure, ordered by location
+func (r *Recognizer) Recognize(structure *gostructure.Structure) []*Match {
+	matches := make([]*Match, 0)
+	for _, m := range r.matchers {
+		matches = append(matches, m.Match(structure)...)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i].Position, matches[j].Position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return matches[i].Pattern < matches[j].Pattern
+	})
+	return matches
+}
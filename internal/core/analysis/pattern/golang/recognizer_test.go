@@ -0,0 +1,190 @@
+package gopattern_test
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	gopattern "codedna/internal/core/analysis/pattern/golang"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Helper function to analyze a testdata file
+func analyzeFile(t *testing.T, name string) *gostructure.Structure {
+	t.Helper()
+
+	astNode, err := goparser.New().ParseFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	analysis, err := gostructure.NewAnalyzer().Analyze(gostructure.NewNode(astNode))
+	if err != nil {
+		t.Fatalf("Failed to analyze file: %v", err)
+	}
+	return analysis.(*gostructure.Analysis).Structure
+}
+
+// Helper function to return the names of the elements playing a role
+func roleNames(match *gopattern.Match, role gopattern.Role) []string {
+	var names []string
+	for _, elem := range match.Role(role) {
+		names = append(names, elem.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestRecognizer(t *testing.T) {
+	structure := analyzeFile(t, "patterns.go")
+	matches := gopattern.NewRecognizer().Recognize(structure)
+
+	byPattern := make(map[gopattern.PatternKind][]*gopattern.Match)
+	for _, match := range matches {
+		byPattern[match.Pattern] = append(byPattern[match.Pattern], match)
+		if match.Position.Line == 0 || match.Position.Filename == "" {
+			t.Errorf("Match %s has invalid position %v", match.Pattern, match.Position)
+		}
+	}
+
+	tests := []struct {
+		pattern gopattern.PatternKind
+		detail  string
+		roles   map[gopattern.Role][]string
+	}{
+		{
+			pattern: gopattern.PatternFunctionalOptions,
+			detail:  "Option -> *Server",
+			roles: map[gopattern.Role][]string{
+				gopattern.RoleOption:         {"Option"},
+				gopattern.RoleTarget:         {"Server"},
+				gopattern.RoleOptionFunction: {"WithAddr", "WithTimeout"},
+				gopattern.RoleConsumer:       {"NewServer"},
+			},
+		},
+		{
+			pattern: gopattern.PatternConstructorInterface,
+			detail:  "Logger",
+			roles: map[gopattern.Role][]string{
+				gopattern.RoleConstructor: {"NewLogger"},
+				gopattern.RoleProduct:     {"Logger"},
+			},
+		},
+		{
+			pattern: gopattern.PatternConstructorConcrete,
+			detail:  "*Server",
+			roles: map[gopattern.Role][]string{
+				gopattern.RoleConstructor: {"NewServer"},
+			},
+		},
+		{
+			pattern: gopattern.PatternBuilder,
+			detail:  "Request",
+			roles: map[gopattern.Role][]string{
+				gopattern.RoleBuilder: {"RequestBuilder"},
+				gopattern.RoleStep:    {"Method", "Path"},
+				gopattern.RoleBuild:   {"Build"},
+				gopattern.RoleProduct: {"Request"},
+			},
+		},
+		{
+			pattern: gopattern.PatternSingleton,
+			detail:  "*Registry",
+			roles: map[gopattern.Role][]string{
+				gopattern.RoleAccessor: {"DefaultRegistry"},
+				gopattern.RoleOnce:     {"registryOnce"},
+				gopattern.RoleInstance: {"Registry"},
+			},
+		},
+		{
+			pattern: gopattern.PatternDecorator,
+			detail:  "Logger",
+			roles: map[gopattern.Role][]string{
+				gopattern.RoleDecorator: {"timedLogger"},
+				gopattern.RoleComponent: {"Logger"},
+			},
+		},
+		{
+			pattern: gopattern.PatternRepository,
+			detail:  "interface",
+			roles: map[gopattern.Role][]string{
+				gopattern.RoleRepository:     {"UserRepository"},
+				gopattern.RoleImplementation: {"sqlUserRepository"},
+			},
+		},
+		{
+			pattern: gopattern.PatternServiceLayer,
+			detail:  "UserService -> UserRepository",
+			roles: map[gopattern.Role][]string{
+				gopattern.RoleService:    {"UserService"},
+				gopattern.RoleRepository: {"UserRepository"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.pattern), func(t *testing.T) {
+			found := byPattern[tt.pattern]
+			if len(found) != 1 {
+				t.Fatalf("Expected 1 %s match, got %d", tt.pattern, len(found))
+			}
+			match := found[0]
+			if match.Detail != tt.detail {
+				t.Errorf("Expected detail %q, got %q", tt.detail, match.Detail)
+			}
+			for role, names := range tt.roles {
+				if got := roleNames(match, role); !slices.Equal(got, names) {
+					t.Errorf("Expected %s participants %v, got %v", role, names, got)
+				}
+			}
+		})
+	}
+
+	t.Run("adapter", func(t *testing.T) {
+		var details []string
+		for _, match := range byPattern[gopattern.PatternAdapter] {
+			details = append(details, match.Detail)
+		}
+		slices.Sort(details)
+		expected := []string{"Printer -> Logger", "func -> Logger"}
+		if !slices.Equal(details, expected) {
+			t.Errorf("Expected adapters %v, got %v", expected, details)
+		}
+	})
+}
+
+// A custom matcher reporting every interface
+type interfaceMatcher struct{}
+
+func (m *interfaceMatcher) Name() string { return "interfaces" }
+
+func (m *interfaceMatcher) Match(structure *gostructure.Structure) []*gopattern.Match {
+	var matches []*gopattern.Match
+	for _, elem := range structure.Elements {
+		if elem.Type == gostructure.ElementInterface {
+			matches = append(matches, &gopattern.Match{Pattern: "interface", Position: elem.Position})
+		}
+	}
+	return matches
+}
+
+func TestRecognizer_Register(t *testing.T) {
+	recognizer := gopattern.NewRecognizer()
+	defaults := len(recognizer.Matchers())
+
+	recognizer.Register(&interfaceMatcher{}, &interfaceMatcher{})
+	if got := len(recognizer.Matchers()); got != defaults+1 {
+		t.Errorf("Expected %d matchers after registering, got %d", defaults+1, got)
+	}
+
+	count := 0
+	for _, match := range recognizer.Recognize(analyzeFile(t, "patterns.go")) {
+		if match.Pattern == "interface" {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 custom interface matches, got %d", count)
+	}
+}
@@ -0,0 +1,130 @@
+package testdata
+
+import (
+	"io"
+	"sync"
+)
+
+// Functional options
+
+type Server struct {
+	addr    string
+	timeout int
+}
+
+type Option func(*Server)
+
+func WithAddr(addr string) Option {
+	return func(s *Server) { s.addr = addr }
+}
+
+func WithTimeout(timeout int) Option {
+	return func(s *Server) { s.timeout = timeout }
+}
+
+func NewServer(opts ...Option) *Server {
+	s := &Server{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Constructors returning interfaces
+
+type Logger interface {
+	Log(msg string)
+}
+
+type stdLogger struct {
+	out io.Writer
+}
+
+func (l *stdLogger) Log(msg string) {}
+
+func NewLogger() Logger {
+	return &stdLogger{}
+}
+
+// Builders
+
+type Request struct {
+	method string
+	path   string
+}
+
+type RequestBuilder struct {
+	req Request
+}
+
+func (b *RequestBuilder) Method(method string) *RequestBuilder {
+	b.req.method = method
+	return b
+}
+
+func (b *RequestBuilder) Path(path string) *RequestBuilder {
+	b.req.path = path
+	return b
+}
+
+func (b *RequestBuilder) Build() Request {
+	return b.req
+}
+
+// Singletons
+
+type Registry struct{}
+
+var (
+	registry     *Registry
+	registryOnce sync.Once
+)
+
+func DefaultRegistry() *Registry {
+	registryOnce.Do(func() {
+		registry = &Registry{}
+	})
+	return registry
+}
+
+// Decorators and adapters
+
+type timedLogger struct {
+	next Logger
+}
+
+func (l *timedLogger) Log(msg string) {
+	l.next.Log(msg)
+}
+
+type Printer struct{}
+
+func (p *Printer) Print(text string) {}
+
+type printerLogger struct {
+	printer *Printer
+}
+
+func (l *printerLogger) Log(msg string) {
+	l.printer.Print(msg)
+}
+
+type LogFunc func(msg string)
+
+func (f LogFunc) Log(msg string) { f(msg) }
+
+// Repositories and services
+
+type User struct{}
+
+type UserRepository interface {
+	Find(id string) (*User, error)
+}
+
+type sqlUserRepository struct{}
+
+func (r *sqlUserRepository) Find(id string) (*User, error) { return nil, nil }
+
+type UserService struct {
+	users UserRepository
+}
@@ -11,6 +11,9 @@ type Node interface {
 type Analysis interface {
 	// Language returns the programming language that was analyzed
 	Language() string
+
+	// Graph returns the analyzed structure in the language-neutral model
+	Graph() *Structure
 }
 
 // Analyzer defines the interface for language-specific code structure analyzers
@@ -22,5 +25,5 @@ type Analyzer interface {
 	Analyze(node Node) (Analysis, error)
 
 	// Merge combines two analyses, handling language-specific differences
-	Merge(base, other *Analysis) error
+	Merge(base, other Analysis) error
 }
@@ -0,0 +1,55 @@
+package structure
+
+import "fmt"
+
+// How metrics and profiles treat generated code
+type GeneratedCode string
+
+const (
+	GeneratedInclude  GeneratedCode = "include"  // Generated code is counted like handwritten code
+	GeneratedExclude  GeneratedCode = "exclude"  // Generated code is ignored
+	GeneratedSeparate GeneratedCode = "separate" // Generated code is reported apart from handwritten code
+)
+
+// Parses how generated code is treated ("include", "exclude" or "separate")
+func ParseGeneratedCode(s string) (GeneratedCode, error) {
+	switch mode := GeneratedCode(s); mode {
+	case GeneratedInclude, GeneratedExclude, GeneratedSeparate:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown generated code treatment %q (expected include, exclude or separate)", s)
+}
+
+// Checks if the element was found in a generated file
+func (e *Element) IsGenerated() bool {
+	generated, _ := e.Attributes["is_generated"].(bool)
+	return generated
+}
+
+// Returns the part of the structure found in handwritten files
+func (s *Structure) Handwritten() *Structure {
+	return s.filter(false)
+}
+
+// Returns the part of the structure found in generated files
+func (s *Structure) Generated() *Structure {
+	return s.filter(true)
+}
+
+// Returns the elements that are generated or not, with the relationships from them. Relationships
+// to the other part (e.g. handwritten code calling generated code) are kept, as they describe the
+// selected code.
+func (s *Structure) filter(generated bool) *Structure {
+	result := NewStructure()
+	for _, elem := range s.Elements {
+		if elem.IsGenerated() == generated {
+			result.Elements = append(result.Elements, elem)
+		}
+	}
+	for _, rel := range s.Relationships {
+		if rel.Source.IsGenerated() == generated {
+			result.Relationships = append(result.Relationships, rel)
+		}
+	}
+	return result
+}
@@ -2,6 +2,13 @@ package gostructure
 
 import (
 	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
 
 	"codedna/internal/core/analysis/structure"
 	"codedna/internal/core/parser/ast"
@@ -9,11 +16,44 @@ import (
 )
 
 // Implements the structure.Analyzer interface for Go code
-type Analyzer struct{}
+type Analyzer struct {
+	registry *Registry
+	logger   *zap.Logger
+}
 
-// Creates a new Go analyzer
+// Creates a new Go analyzer with the built-in detectors
 func NewAnalyzer() *Analyzer {
-	return &Analyzer{}
+	a := &Analyzer{
+		registry: NewRegistry(),
+		logger:   zap.NewNop(),
+	}
+
+	builtins := []Detector{
+		newLocalDetector(DetectorReferences, nil, a.detectTypeReferences),
+		newLocalDetector(DetectorReceivers, nil, a.detectMethodReceivers),
+		NewDetector(DetectorInterfaceEmbeddings, nil, a.detectInterfaceEmbeddings),
+		NewDetector(DetectorImplementations, []string{DetectorReferences, DetectorReceivers, DetectorInterfaceEmbeddings}, a.detectInterfaceImplementations),
+		newLocalDetector(DetectorComposition, []string{DetectorReferences}, a.detectComposition),
+		// Custom error types may get Error() through embedding
+		NewDetector(DetectorErrorTypes, []string{DetectorReceivers, DetectorComposition}, a.detectErrorTypes),
+		NewDetector(DetectorSyncPrimitives, nil, a.detectSyncPrimitives),
+		newLocalDetector(DetectorCalls, nil, a.detectCalls),
+		NewDetector(DetectorTests, []string{DetectorCalls, DetectorReceivers}, a.detectTests),
+	}
+	for _, d := range builtins {
+		_ = a.registry.Register(d) // Built-in names are unique
+	}
+	return a
+}
+
+// Returns the detector registry, so detectors can be added, enabled or disabled
+func (a *Analyzer) Registry() *Registry {
+	return a.registry
+}
+
+// Sets the logger used to report detector runs
+func (a *Analyzer) SetLogger(logger *zap.Logger) {
+	a.logger = logger
 }
 
 // Returns the language this analyzer handles
@@ -23,23 +63,30 @@ func (a *Analyzer) Language() string {
 
 // Analyzes the structural analysis on Go code
 func (a *Analyzer) Analyze(node structure.Node) (structure.Analysis, error) {
-	// Type assert to Go node
-	goNode, ok := node.(*Node)
-	if !ok {
-		return nil, fmt.Errorf("expected Go node, got %T", node)
-	}
+	return a.AnalyzeAll([]structure.Node{node})
+}
 
+// Analyzes several Go nodes (e.g. all files of a project) into a single analysis,
+// so that detectors see relationships across files and packages
+func (a *Analyzer) AnalyzeAll(nodes []structure.Node) (structure.Analysis, error) {
 	// Create analysis result
 	analysis := NewAnalysis()
 
-	// Analyze the code
-	_, err := a.analyzeNode(goNode.Node, analysis)
-	if err != nil {
-		return nil, fmt.Errorf("failed to analyze Go code: %w", err)
+	for _, node := range nodes {
+		// Type assert to Go node
+		goNode, ok := node.(*Node)
+		if !ok {
+			return nil, fmt.Errorf("expected Go node, got %T", node)
+		}
+
+		// Analyze the code
+		if _, err := a.analyzeNode(goNode.Node, analysis); err != nil {
+			return nil, fmt.Errorf("failed to analyze Go code: %w", err)
+		}
 	}
 
 	// Detect language-specific patterns
-	if err := a.detectPatterns(analysis); err != nil {
+	if err := a.detectPatterns(analysis, nil); err != nil {
 		return nil, fmt.Errorf("failed to detect Go patterns: %w", err)
 	}
 
@@ -81,11 +128,29 @@ func (a *Analyzer) analyzeNode(node ast.Node, analysis *Analysis) (*Element, err
 
 	// Create element for the node
 	element := &Element{
+		Language:   "go",
 		Type:       elemType,
 		Name:       nodeName(node),
+		Scope:      filepath.Dir(node.Position().Filename), // Packages are directories
+		Position:   node.Position(),
 		Attributes: node.Attributes(),
 	}
 
+	// Tag the elements of generated and test files
+	if elemType != ElementPackage {
+		if pkg := a.findPackage(analysis); pkg != nil {
+			if pkg.IsGenerated() {
+				element.Attributes["is_generated"] = true
+			}
+			if pkg.IsTest() {
+				element.Attributes["is_test"] = true
+				if strings.HasSuffix(pkg.Name, "_test") {
+					element.Attributes["test_package"] = pkg.Name // External test package
+				}
+			}
+		}
+	}
+
 	// Add element to structure
 	analysis.Structure.Elements = append(analysis.Structure.Elements, element)
 
@@ -122,11 +187,12 @@ func (a *Analyzer) analyzeNode(node ast.Node, analysis *Analysis) (*Element, err
 	return element, nil
 }
 
-// Finds the package element
+// Finds the package element of the node being analyzed (the most recent one)
 func (a *Analyzer) findPackage(analysis *Analysis) *Element {
-	for _, elem := range analysis.Structure.Elements {
-		if elem.Type == ElementPackage {
-			return elem
+	elements := analysis.Structure.Elements
+	for i := len(elements) - 1; i >= 0; i-- {
+		if elements[i].Type == ElementPackage {
+			return elements[i]
 		}
 	}
 	return nil
@@ -183,33 +249,96 @@ func nodeName(node ast.Node) string {
 	return "" // Don't generate fallback names
 }
 
-// Detects Go-specific patterns in the code
-func (a *Analyzer) detectPatterns(analysis *Analysis) error {
-	// First detect type references since other detections may need them
-	if err := a.detectTypeReferences(analysis); err != nil {
+// Detects Go-specific patterns by running the enabled detectors in dependency order. When
+// scopes is not nil, package-local detectors only handle the elements of those package
+// directories.
+func (a *Analyzer) detectPatterns(analysis *Analysis, scopes map[string]bool) error {
+	detectors, err := a.registry.Ordered()
+	if err != nil {
 		return err
 	}
 
-	// Then detect method receivers and interface implementations
-	if err := a.detectMethodReceivers(analysis); err != nil {
-		return err
-	}
+	for _, d := range detectors {
+		if deps := a.registry.disabledDependencies(d); len(deps) > 0 {
+			a.logger.Warn("Detector dependencies are disabled",
+				zap.String("detector", d.Name()),
+				zap.Strings("disabled", deps))
+		}
 
-	// Then detect interface embeddings
-	if err := a.detectInterfaceEmbeddings(analysis); err != nil {
-		return err
+		local := isLocal(d)
+		if local {
+			analysis.scopes = scopes
+		}
+		added := len(analysis.Structure.Relationships)
+		start := time.Now()
+		err := d.Detect(analysis)
+		elapsed := time.Since(start)
+		analysis.scopes = nil
+		if err != nil {
+			return fmt.Errorf("detector %s: %w", d.Name(), err)
+		}
+		if local {
+			for _, rel := range analysis.Structure.Relationships[added:] {
+				analysis.local[rel] = true
+			}
+		}
+
+		analysis.DetectorTimings[d.Name()] = elapsed
+		a.logger.Debug("Detector finished",
+			zap.String("detector", d.Name()),
+			zap.Duration("duration", elapsed))
 	}
+	return nil
+}
 
-	// Then detect interface implementations
-	if err := a.detectInterfaceImplementations(analysis); err != nil {
-		return err
+// Detects types implementing the builtin error interface
+func (a *Analyzer) detectErrorTypes(analysis *Analysis) error {
+	errorMethod := map[string]any{
+		"name": "Error",
+		"signature": map[string]any{
+			"params":  []*goparser.TypeInfo{},
+			"returns": []*goparser.TypeInfo{{Kind: "basic", Name: "string"}},
+		},
 	}
 
-	// Finally detect composition relationships
-	if err := a.detectComposition(analysis); err != nil {
-		return err
+	for _, typ := range a.findElementsByType(analysis, ElementTypeDecl) {
+		isErrorType := a.typeImplementsInterface(analysis, []map[string]any{errorMethod}, a.typeMethods(typ, analysis, make(map[*Element]bool)))
+		typ.Attributes["is_error_type"] = isErrorType
 	}
+	return nil
+}
+
+// Sync primitive types that can guard a type's state
+var syncPrimitiveTypes = map[string]bool{
+	"sync.Mutex":     true,
+	"sync.RWMutex":   true,
+	"sync.WaitGroup": true,
+	"sync.Once":      true,
+	"sync.Cond":      true,
+	"sync.Map":       true,
+	"errgroup.Group": true,
+}
 
+// Records the sync primitives (e.g. sync.Mutex) held by each type's fields
+func (a *Analyzer) detectSyncPrimitives(analysis *Analysis) error {
+	for _, typ := range a.findElementsByType(analysis, ElementTypeDecl) {
+		fields, _ := typ.Attributes["fields"].([]map[string]any)
+		primitives := make([]string, 0)
+		for _, field := range fields {
+			typeInfo, ok := field["type"].(*goparser.TypeInfo)
+			if !ok {
+				continue
+			}
+			if typeInfo.Kind == "pointer" && typeInfo.ElemType != nil {
+				typeInfo = typeInfo.ElemType
+			}
+			name := typeInfo.String()
+			if syncPrimitiveTypes[name] || strings.HasPrefix(name, "atomic.") {
+				primitives = append(primitives, name)
+			}
+		}
+		typ.Attributes["sync_primitives"] = primitives
+	}
 	return nil
 }
 
@@ -217,6 +346,9 @@ func (a *Analyzer) detectPatterns(analysis *Analysis) error {
 func (a *Analyzer) detectMethodReceivers(analysis *Analysis) error {
 	// For each method element
 	for _, method := range a.findElementsByType(analysis, ElementMethod) {
+		if !analysis.detects(method) {
+			continue
+		}
 		// Get receiver type directly from attributes
 		if recv, ok := method.Attributes["receiver_type"].(*goparser.TypeInfo); ok && recv != nil {
 			// Find the actual receiver type (handle pointer receivers)
@@ -224,7 +356,7 @@ func (a *Analyzer) detectMethodReceivers(analysis *Analysis) error {
 			if recv.Kind == "pointer" && recv.ElemType != nil {
 				typeName = recv.ElemType.Name
 			}
-			if recvType := a.findTypeByName(analysis, typeName); recvType != nil {
+			if recvType := a.findTypeByName(analysis, typeName, method); recvType != nil {
 				// Add method receiver relationship
 				rel := &Relationship{
 					Type:   RelationMethodReceiver,
@@ -244,6 +376,9 @@ func (a *Analyzer) detectMethodReceivers(analysis *Analysis) error {
 func (a *Analyzer) detectTypeReferences(analysis *Analysis) error {
 	// For each type element
 	for _, typ := range a.findElementsByType(analysis, ElementTypeDecl) {
+		if !analysis.detects(typ) {
+			continue
+		}
 		// Check field types
 		if fields, ok := typ.Attributes["fields"].([]map[string]any); ok {
 			for _, field := range fields {
@@ -257,6 +392,9 @@ func (a *Analyzer) detectTypeReferences(analysis *Analysis) error {
 
 	// For each function element
 	for _, fn := range a.findElementsByType(analysis, ElementFunction) {
+		if !analysis.detects(fn) {
+			continue
+		}
 		// Check signature types
 		if sig, ok := fn.Attributes["signature"].(map[string]any); ok {
 			// Check parameter types
@@ -277,6 +415,9 @@ func (a *Analyzer) detectTypeReferences(analysis *Analysis) error {
 
 	// For each method element
 	for _, method := range a.findElementsByType(analysis, ElementMethod) {
+		if !analysis.detects(method) {
+			continue
+		}
 		// Check signature types
 		if sig, ok := method.Attributes["signature"].(map[string]any); ok {
 			// Check receiver type
@@ -302,6 +443,9 @@ func (a *Analyzer) detectTypeReferences(analysis *Analysis) error {
 
 	// For each interface element
 	for _, iface := range a.findElementsByType(analysis, ElementInterface) {
+		if !analysis.detects(iface) {
+			continue
+		}
 		// Check method signatures
 		if methods, ok := iface.Attributes["methods"].([]map[string]any); ok {
 			for _, method := range methods {
@@ -349,10 +493,10 @@ func (a *Analyzer) detectInterfaceImplementations(analysis *Analysis) error {
 		// For each type element
 		for _, typ := range a.findElementsByType(analysis, ElementTypeDecl) {
 			// Get type methods (including from embedded types)
-			typeMethods := a.typeMethods(typ, analysis)
+			typeMethods := a.typeMethods(typ, analysis, make(map[*Element]bool))
 
 			// Check if type implements interface
-			if a.typeImplementsInterface(ifaceMethods, typeMethods) {
+			if a.typeImplementsInterface(analysis, ifaceMethods, typeMethods) {
 				// Add implements relationship
 				rel := &Relationship{
 					Type:   RelationImplements,
@@ -374,7 +518,7 @@ func (a *Analyzer) detectComposition(analysis *Analysis) error {
 	for _, typ := range a.findElementsByType(analysis, ElementTypeDecl) {
 		// Get fields
 		fields, ok := typ.Attributes["fields"].([]map[string]any)
-		if !ok {
+		if !ok || !analysis.detects(typ) {
 			continue
 		}
 
@@ -392,7 +536,7 @@ func (a *Analyzer) detectComposition(analysis *Analysis) error {
 				if fieldType.Kind == "pointer" && fieldType.ElemType != nil {
 					typeName = fieldType.ElemType.Name
 				}
-				if embedded := a.findTypeByName(analysis, typeName); embedded != nil {
+				if embedded := a.findTypeByName(analysis, typeName, typ); embedded != nil {
 					// Add composition relationship
 					rel := &Relationship{
 						Type:   RelationEmbeds,
@@ -420,7 +564,7 @@ func (a *Analyzer) detectInterfaceEmbeddings(analysis *Analysis) error {
 				// Check if the embedded interface is a type
 				if embedType, ok := embed["type"].(*goparser.TypeInfo); ok {
 					// Check if the embedded interface is an interface
-					if target := a.findTypeByName(analysis, embedType.Name); target != nil && target.Type == ElementInterface {
+					if target := a.findTypeByName(analysis, embedType.Name, iface); target != nil && target.Type == ElementInterface {
 						// Add interface embedding relationship
 						rel := &Relationship{
 							Type:   RelationInterfaceEmbeds,
@@ -449,16 +593,87 @@ func (a *Analyzer) findElementsByType(analysis *Analysis, elemType ElementType)
 	return result
 }
 
-// Finds a type element by name
-func (a *Analyzer) findTypeByName(analysis *Analysis, name string) *Element {
+// Finds a type element by the name an element uses for it. Unqualified names only match
+// types of the element's own package. Qualified names (e.g. "domain.User") match types
+// contained in an analyzed package of that name; when several packages of that name declare
+// the type, the one imported by the element's file is chosen. Returns nil if the type is not
+// found or remains ambiguous.
+func (a *Analyzer) findTypeByName(analysis *Analysis, name string, from *Element) *Element {
+	if pkgName, typeName, ok := strings.Cut(name, "."); ok {
+		var candidates []*Element
+		for _, rel := range analysis.Structure.Relationships {
+			target := rel.Target
+			if rel.Type == RelationContains && rel.Source.Type == ElementPackage && rel.Source.Name == pkgName &&
+				(target.Type == ElementTypeDecl || target.Type == ElementInterface) && target.Name == typeName &&
+				!slices.Contains(candidates, target) {
+				candidates = append(candidates, target)
+			}
+		}
+		if len(candidates) > 1 {
+			return importedType(analysis, candidates, from)
+		}
+		if len(candidates) == 1 {
+			return candidates[0]
+		}
+		return nil
+	}
+
 	for _, elem := range analysis.Structure.Elements {
-		if (elem.Type == ElementTypeDecl || elem.Type == ElementInterface) && elem.Name == name {
+		if (elem.Type == ElementTypeDecl || elem.Type == ElementInterface) && elem.Name == name && samePackage(elem, from) {
 			return elem
 		}
 	}
 	return nil
 }
 
+// Returns the type whose package directory best matches an import of the file declaring an
+// element, or nil if no single type does
+func importedType(analysis *Analysis, candidates []*Element, from *Element) *Element {
+	var imports []string
+	for _, elem := range analysis.Structure.Elements {
+		if elem.Type == ElementPackage && elem.Position.Filename == from.Position.Filename {
+			imports, _ = elem.Attributes["dependencies"].([]string)
+			break
+		}
+	}
+
+	var best *Element
+	bestLen, tie := 0, false
+	for _, candidate := range candidates {
+		dir := strings.Split(filepath.ToSlash(filepath.Clean(candidate.Scope)), "/")
+		for _, path := range imports {
+			n := commonSuffixLen(dir, strings.Split(path, "/"))
+			switch {
+			case n > bestLen:
+				best, bestLen, tie = candidate, n, false
+			case n == bestLen && n > 0 && candidate != best:
+				tie = true
+			}
+		}
+	}
+	if tie {
+		return nil
+	}
+	return best
+}
+
+// Returns the number of trailing elements two paths have in common
+func commonSuffixLen(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
+
+// Checks if two elements are declared in the same package. The external test package of a
+// directory (foo_test) is a package of its own.
+func samePackage(e1, e2 *Element) bool {
+	p1, _ := e1.Attributes["test_package"].(string)
+	p2, _ := e2.Attributes["test_package"].(string)
+	return e1.Scope == e2.Scope && p1 == p2
+}
+
 // Returns all methods of an interface (including embedded)
 func (a *Analyzer) interfaceMethods(iface *Element, analysis *Analysis) []map[string]any {
 	var methods []map[string]any
@@ -468,6 +683,8 @@ func (a *Analyzer) interfaceMethods(iface *Element, analysis *Analysis) []map[st
 		for _, method := range methodList {
 			// Skip embedded interfaces (they have no name)
 			if name, ok := method["name"].(string); ok && name != "" {
+				method = maps.Clone(method)
+				method["package"] = analysis.packageName(iface)
 				methods = append(methods, method)
 			} else {
 				// For embedded interfaces, get their methods
@@ -475,7 +692,7 @@ func (a *Analyzer) interfaceMethods(iface *Element, analysis *Analysis) []map[st
 					if params, ok := sig["params"].([]*goparser.TypeInfo); ok && len(params) > 0 {
 						// The first param is the embedded interface type
 						embedType := params[0]
-						if embedded := a.findTypeByName(analysis, embedType.Name); embedded != nil && embedded.Type == ElementInterface {
+						if embedded := a.findTypeByName(analysis, embedType.Name, iface); embedded != nil && embedded.Type == ElementInterface {
 							methods = append(methods, a.interfaceMethods(embedded, analysis)...)
 						}
 					}
@@ -487,9 +704,14 @@ func (a *Analyzer) interfaceMethods(iface *Element, analysis *Analysis) []map[st
 	return methods
 }
 
-// Returns all methods of a type (including from embedded types)
-func (a *Analyzer) typeMethods(typ *Element, analysis *Analysis) []map[string]any {
+// Returns all methods of a type (including from embedded types). Types already in visited
+// are skipped, so a struct embedding itself through a pointer terminates.
+func (a *Analyzer) typeMethods(typ *Element, analysis *Analysis, visited map[*Element]bool) []map[string]any {
 	var methods []map[string]any
+	if visited[typ] {
+		return methods
+	}
+	visited[typ] = true
 
 	// Get direct methods
 	for _, method := range a.findElementsByType(analysis, ElementMethod) {
@@ -500,12 +722,13 @@ func (a *Analyzer) typeMethods(typ *Element, analysis *Analysis) []map[string]an
 			if recv.Kind == "pointer" && recv.ElemType != nil {
 				typeName = recv.ElemType.Name
 			}
-			if typeName == typ.Name {
+			if typeName == typ.Name && samePackage(method, typ) {
 				if sig, ok := method.Attributes["signature"].(map[string]any); ok {
 					methods = append(methods, map[string]any{
 						"name":               method.Name,
 						"signature":          sig,
 						"receiver_type_name": typeName,
+						"package":            analysis.packageName(method),
 					})
 				}
 			}
@@ -522,8 +745,8 @@ func (a *Analyzer) typeMethods(typ *Element, analysis *Analysis) []map[string]an
 					if fieldType.Kind == "pointer" && fieldType.ElemType != nil {
 						typeName = fieldType.ElemType.Name
 					}
-					if embedded := a.findTypeByName(analysis, typeName); embedded != nil {
-						embeddedMethods := a.typeMethods(embedded, analysis)
+					if embedded := a.findTypeByName(analysis, typeName, typ); embedded != nil {
+						embeddedMethods := a.typeMethods(embedded, analysis, visited)
 						// Add embedded type name to each method
 						for _, method := range embeddedMethods {
 							method["receiver_type_name"] = typeName
@@ -539,7 +762,7 @@ func (a *Analyzer) typeMethods(typ *Element, analysis *Analysis) []map[string]an
 }
 
 // Checks if a type implements an interface
-func (a *Analyzer) typeImplementsInterface(ifaceMethods, typeMethods []map[string]any) bool {
+func (a *Analyzer) typeImplementsInterface(analysis *Analysis, ifaceMethods, typeMethods []map[string]any) bool {
 	if len(typeMethods) == 0 {
 		return false
 	}
@@ -549,8 +772,13 @@ func (a *Analyzer) typeImplementsInterface(ifaceMethods, typeMethods []map[strin
 		found := false
 		// Look for matching method
 		for _, tmethod := range typeMethods {
-			if tmethod["name"] == imethod["name"] &&
-				a.signatureMatches(tmethod["signature"].(map[string]any), imethod["signature"].(map[string]any)) {
+			if tmethod["name"] != imethod["name"] {
+				continue
+			}
+			// Names in each signature are relative to the package declaring the method
+			tpkg, _ := tmethod["package"].(string)
+			ipkg, _ := imethod["package"].(string)
+			if a.signatureMatches(analysis, tmethod["signature"].(map[string]any), imethod["signature"].(map[string]any), tpkg, ipkg) {
 				found = true
 				break
 			}
@@ -562,12 +790,12 @@ func (a *Analyzer) typeImplementsInterface(ifaceMethods, typeMethods []map[strin
 	return true
 }
 
-// Checks if two method signatures match
-func (a *Analyzer) signatureMatches(sig1, sig2 map[string]any) bool {
+// Checks if two method signatures, declared in the packages named pkg1 and pkg2, match
+func (a *Analyzer) signatureMatches(analysis *Analysis, sig1, sig2 map[string]any, pkg1, pkg2 string) bool {
 	// Compare receiver types if present
 	if recv1, ok1 := sig1["receiver_type"].(*goparser.TypeInfo); ok1 {
 		if recv2, ok2 := sig2["receiver_type"].(*goparser.TypeInfo); ok2 {
-			if !a.typeMatches(recv1, recv2) {
+			if !a.typeMatches(analysis, recv1, recv2, pkg1, pkg2) {
 				return false
 			}
 		} else if ok1 != ok2 {
@@ -578,14 +806,14 @@ func (a *Analyzer) signatureMatches(sig1, sig2 map[string]any) bool {
 	// Compare parameter types
 	params1, ok1 := sig1["params"].([]*goparser.TypeInfo)
 	params2, ok2 := sig2["params"].([]*goparser.TypeInfo)
-	if !ok1 || !ok2 || !a.typeListMatches(params1, params2) {
+	if !ok1 || !ok2 || !a.typeListMatches(analysis, params1, params2, pkg1, pkg2) {
 		return false
 	}
 
 	// Compare return types
 	returns1, ok1 := sig1["returns"].([]*goparser.TypeInfo)
 	returns2, ok2 := sig2["returns"].([]*goparser.TypeInfo)
-	if !ok1 || !ok2 || !a.typeListMatches(returns1, returns2) {
+	if !ok1 || !ok2 || !a.typeListMatches(analysis, returns1, returns2, pkg1, pkg2) {
 		return false
 	}
 
@@ -593,46 +821,65 @@ func (a *Analyzer) signatureMatches(sig1, sig2 map[string]any) bool {
 }
 
 // Checks if two type lists match
-func (a *Analyzer) typeListMatches(types1, types2 []*goparser.TypeInfo) bool {
+func (a *Analyzer) typeListMatches(analysis *Analysis, types1, types2 []*goparser.TypeInfo, pkg1, pkg2 string) bool {
 	if len(types1) != len(types2) {
 		return false
 	}
 	for i := range types1 {
-		if !a.typeMatches(types1[i], types2[i]) {
+		if !a.typeMatches(analysis, types1[i], types2[i], pkg1, pkg2) {
 			return false
 		}
 	}
 	return true
 }
 
-// Checks if two types match
-func (a *Analyzer) typeMatches(t1, t2 *goparser.TypeInfo) bool {
+// Checks if two types, used in the packages named pkg1 and pkg2, match
+func (a *Analyzer) typeMatches(analysis *Analysis, t1, t2 *goparser.TypeInfo, pkg1, pkg2 string) bool {
 	if t1 == nil || t2 == nil {
 		return t1 == t2
 	}
-
-	// Check kind and name
-	if t1.Kind != t2.Kind || t1.Name != t2.Name {
+	if t1.Kind != t2.Kind {
 		return false
 	}
 
-	// For pointer types, check element type
-	if t1.Kind == "pointer" {
-		return a.typeMatches(t1.ElemType, t2.ElemType)
-	}
-
-	// For slice types, check element type
-	if t1.Kind == "slice" {
-		return a.typeMatches(t1.ElemType, t2.ElemType)
-	}
-
-	// For map types, check key and value types
-	if t1.Kind == "map" {
-		return a.typeMatches(t1.KeyType, t2.KeyType) &&
-			a.typeMatches(t1.ValueType, t2.ValueType)
+	switch t1.Kind {
+	case "basic":
+		// Named types, with their type arguments
+		return analysis.qualifiedTypeName(t1.Name, pkg1) == analysis.qualifiedTypeName(t2.Name, pkg2) &&
+			a.typeListMatches(analysis, t1.Args, t2.Args, pkg1, pkg2)
+	case "pointer", "slice":
+		return a.typeMatches(analysis, t1.ElemType, t2.ElemType, pkg1, pkg2)
+	case "array":
+		return t1.Len == t2.Len && a.typeMatches(analysis, t1.ElemType, t2.ElemType, pkg1, pkg2)
+	case "chan":
+		return t1.Dir == t2.Dir && a.typeMatches(analysis, t1.ElemType, t2.ElemType, pkg1, pkg2)
+	case "map":
+		return a.typeMatches(analysis, t1.KeyType, t2.KeyType, pkg1, pkg2) &&
+			a.typeMatches(analysis, t1.ValueType, t2.ValueType, pkg1, pkg2)
+	case "func":
+		return t1.Variadic == t2.Variadic &&
+			a.typeListMatches(analysis, t1.Params, t2.Params, pkg1, pkg2) &&
+			a.typeListMatches(analysis, t1.Results, t2.Results, pkg1, pkg2)
+	case "struct", "interface":
+		if len(t1.Fields) != len(t2.Fields) {
+			return false
+		}
+		for i := range t1.Fields {
+			if t1.Fields[i].Name != t2.Fields[i].Name || !a.typeMatches(analysis, t1.Fields[i].Type, t2.Fields[i].Type, pkg1, pkg2) {
+				return false
+			}
+		}
+		return true
 	}
+	return t1.Name == t2.Name
+}
 
-	return true
+// Types declared by the language rather than a package (the parser reports float64 as float)
+var predeclaredTypes = map[string]bool{
+	"bool": true, "string": true, "int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"float": true, "float32": true, "float64": true, "complex64": true, "complex128": true,
+	"byte": true, "rune": true, "error": true, "any": true, "comparable": true,
 }
 
 func (a *Analyzer) addTypeReference(analysis *Analysis, source *Element, typeInfo *goparser.TypeInfo) {
@@ -665,35 +912,17 @@ func (a *Analyzer) addTypeReference(analysis *Analysis, source *Element, typeInf
 
 	// Skip actual primitive types
 	if typeInfo.Kind == "basic" && typeInfo.Name != "" {
-		// Check if it's a primitive type
-		primitiveTypes := map[string]bool{
-			"bool":       true,
-			"string":     true,
-			"int":        true,
-			"int8":       true,
-			"int16":      true,
-			"int32":      true,
-			"int64":      true,
-			"uint":       true,
-			"uint8":      true,
-			"uint16":     true,
-			"uint32":     true,
-			"uint64":     true,
-			"float32":    true,
-			"float64":    true,
-			"complex64":  true,
-			"complex128": true,
-			"byte":       true,
-			"rune":       true,
-			"error":      true,
-		}
-
-		if primitiveTypes[typeInfo.Name] {
+		if predeclaredTypes[typeInfo.Name] {
 			return
 		}
 
-		// Not a primitive - try to find the named type
-		if target := a.findTypeByName(analysis, typeInfo.Name); target != nil {
+		// Type arguments of generic types are referenced too
+		for _, arg := range typeInfo.Args {
+			a.addTypeReference(analysis, source, arg)
+		}
+
+		// Not a primitive - try to find the named type or interface
+		if target := a.findTypeByName(analysis, typeInfo.Name, source); target != nil {
 			rel := &Relationship{
 				Type:   RelationReferences,
 				Source: source,
@@ -702,33 +931,20 @@ func (a *Analyzer) addTypeReference(analysis *Analysis, source *Element, typeInf
 			if !a.hasRelationship(analysis, rel) {
 				analysis.Structure.Relationships = append(analysis.Structure.Relationships, rel)
 			}
-		} else {
-			// Try to find interface
-			for _, iface := range a.findElementsByType(analysis, ElementInterface) {
-				if iface.Name == typeInfo.Name {
-					rel := &Relationship{
-						Type:   RelationReferences,
-						Source: source,
-						Target: iface,
-					}
-					if !a.hasRelationship(analysis, rel) {
-						analysis.Structure.Relationships = append(analysis.Structure.Relationships, rel)
-					}
-					return
-				}
-			}
 		}
 	}
 }
 
 // Merges two analyses
-func (a *Analyzer) Merge(base, other *Analysis) error {
-	if base == nil || other == nil {
+func (a *Analyzer) Merge(baseAnalysis, otherAnalysis structure.Analysis) error {
+	if baseAnalysis == nil || otherAnalysis == nil {
 		return fmt.Errorf("cannot merge nil analyses")
 	}
 
 	// Verify both analyses are Go analyses
-	if base.Language() != "go" || other.Language() != "go" {
+	base, ok1 := baseAnalysis.(*Analysis)
+	other, ok2 := otherAnalysis.(*Analysis)
+	if !ok1 || !ok2 || base == nil || other == nil {
 		return fmt.Errorf("can only merge Go analyses")
 	}
 
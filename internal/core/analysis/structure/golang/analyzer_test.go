@@ -2,8 +2,11 @@ package gostructure_test
 
 import (
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
+	"codedna/internal/core/analysis/structure"
 	gostructure "codedna/internal/core/analysis/structure/golang"
 	goparser "codedna/internal/core/parser/golang"
 )
@@ -307,6 +310,215 @@ func TestAnalyzer_WholeDirectory(t *testing.T) {
 	})
 }
 
+func TestAnalyzer_GeneratedCode(t *testing.T) {
+	astNodes, err := goparser.New().ParseDir(filepath.Join("testdata", "generated"))
+	if err != nil {
+		t.Fatalf("Failed to parse directory: %v", err)
+	}
+	nodes := make([]structure.Node, 0, len(astNodes))
+	for _, astNode := range astNodes {
+		nodes = append(nodes, gostructure.NewNode(astNode))
+	}
+	analysis, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+	code := analysis.(*gostructure.Analysis).Structure
+
+	t.Run("Tags", func(t *testing.T) {
+		for _, elem := range code.Elements {
+			generated := filepath.Base(elem.Position.Filename) == "mock_store.go"
+			if elem.IsGenerated() != generated {
+				t.Errorf("Expected %s %s to have generated %v, got %v", elem.Type, elem.Name, generated, elem.IsGenerated())
+			}
+		}
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		tests := []struct {
+			mode      gostructure.GeneratedCode
+			expected  map[gostructure.MetricType]int
+			generated map[gostructure.MetricType]int
+		}{
+			{
+				mode: gostructure.GeneratedInclude,
+				expected: map[gostructure.MetricType]int{
+					gostructure.MetricTotalElements: 9, gostructure.MetricTypes: 2, gostructure.MetricMethods: 2, gostructure.MetricImplements: 2,
+				},
+			},
+			{
+				mode: gostructure.GeneratedExclude,
+				expected: map[gostructure.MetricType]int{
+					gostructure.MetricTotalElements: 5, gostructure.MetricTypes: 1, gostructure.MetricMethods: 1, gostructure.MetricImplements: 1,
+				},
+			},
+			{
+				mode: gostructure.GeneratedSeparate,
+				expected: map[gostructure.MetricType]int{
+					gostructure.MetricTotalElements: 5, gostructure.MetricTypes: 1, gostructure.MetricMethods: 1, gostructure.MetricImplements: 1,
+				},
+				// MockStore implements the handwritten Store interface
+				generated: map[gostructure.MetricType]int{
+					gostructure.MetricTotalElements: 4, gostructure.MetricInterfaces: 0, gostructure.MetricMethods: 1, gostructure.MetricImplements: 1,
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(string(tt.mode), func(t *testing.T) {
+				collector := gostructure.NewMetricsCollector()
+				collector.SetGenerated(tt.mode)
+				collector.CollectMetrics(code)
+				for metric, expected := range tt.expected {
+					if actual := collector.Metric(metric); actual != expected {
+						t.Errorf("Metric %v: expected %d, got %d", metric, expected, actual)
+					}
+				}
+				for metric, expected := range tt.generated {
+					if actual := collector.GeneratedMetric(metric); actual != expected {
+						t.Errorf("Generated metric %v: expected %d, got %d", metric, expected, actual)
+					}
+				}
+				if tt.generated == nil && len(collector.GeneratedMetrics()) != 0 {
+					t.Errorf("Expected no generated metrics, got %v", collector.GeneratedMetrics())
+				}
+			})
+		}
+	})
+}
+
+func TestAnalyzer_Tests(t *testing.T) {
+	astNodes, err := goparser.New().ParseDir(filepath.Join("testdata", "store"))
+	if err != nil {
+		t.Fatalf("Failed to parse directory: %v", err)
+	}
+	nodes := make([]structure.Node, 0, len(astNodes))
+	for _, astNode := range astNodes {
+		nodes = append(nodes, gostructure.NewNode(astNode))
+	}
+	analysis, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+	code := analysis.(*gostructure.Analysis).Structure
+
+	// Helper function to list relationships of a type as "source->target"
+	relationships := func(relType gostructure.RelationType) []string {
+		var result []string
+		for _, rel := range code.Relationships {
+			if rel.Type == relType {
+				result = append(result, rel.Source.Name+"->"+rel.Target.Name)
+			}
+		}
+		slices.Sort(result)
+		return result
+	}
+
+	t.Run("Tags", func(t *testing.T) {
+		for _, elem := range code.Elements {
+			test := strings.HasSuffix(elem.Position.Filename, "_test.go")
+			if elem.IsTest() != test {
+				t.Errorf("Expected %s %s to have test %v, got %v", elem.Type, elem.Name, test, elem.IsTest())
+			}
+			// Only names_test.go is in the external test package
+			external := filepath.Base(elem.Position.Filename) == "names_test.go" && elem.Type != gostructure.ElementPackage
+			if pkg, _ := elem.Attributes["test_package"].(string); (pkg == "store_test") != external {
+				t.Errorf("Expected %s %s to have test package %v, got %q", elem.Type, elem.Name, external, pkg)
+			}
+		}
+	})
+
+	t.Run("Calls", func(t *testing.T) {
+		// The external test reaches Names through the imported package, without type information
+		expected := []string{"Save->validate", "TestNames->Names", "TestNames->newStore", "TestSave->New", "TestSave->Save", "newStore->New"}
+		if got := relationships(gostructure.RelationCalls); !slices.Equal(got, expected) {
+			t.Errorf("Expected calls %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Tests", func(t *testing.T) {
+		// Helpers are followed; production code calls (Save->validate) are not
+		expected := []string{"TestNames->Names", "TestNames->New", "TestNames->Store", "TestSave->New", "TestSave->Save", "TestSave->Store"}
+		if got := relationships(gostructure.RelationTests); !slices.Equal(got, expected) {
+			t.Errorf("Expected tests %v, got %v", expected, got)
+		}
+	})
+}
+
+func TestAnalyzer_PackageNames(t *testing.T) {
+	// Both packages declare an Item type; names only resolve within their package or through
+	// a qualified name
+	var nodes []structure.Node
+	for _, dir := range []string{"catalog", "orders"} {
+		astNodes, err := goparser.New().ParseDir(filepath.Join("testdata", "names", dir))
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", dir, err)
+		}
+		for _, astNode := range astNodes {
+			nodes = append(nodes, gostructure.NewNode(astNode))
+		}
+	}
+	analysis, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze packages: %v", err)
+	}
+	code := analysis.(*gostructure.Analysis).Structure
+
+	// Helper function to list relationships of a type as "pkg.source->pkg.target"
+	relationships := func(relType gostructure.RelationType) []string {
+		var result []string
+		for _, rel := range code.Relationships {
+			if rel.Type == relType {
+				result = append(result, filepath.Base(rel.Source.Scope)+"."+rel.Source.Name+"->"+filepath.Base(rel.Target.Scope)+"."+rel.Target.Name)
+			}
+		}
+		slices.Sort(result)
+		return result
+	}
+
+	tests := map[gostructure.RelationType][]string{
+		gostructure.RelationMethodReceiver: {"catalog.Items->catalog.Catalog", "catalog.Price->catalog.Item", "orders.Find->orders.Index", "orders.Price->orders.Item"},
+		gostructure.RelationReferences: {"catalog.Finder->catalog.Item", "catalog.Items->catalog.Item", "orders.Find->orders.Item",
+			"orders.Item->catalog.Item", "orders.Lister->orders.Entry"},
+		// Entry is an alias of catalog.Item, and Index finds order lines rather than catalog items
+		gostructure.RelationImplements: {"catalog.Catalog->orders.Lister", "catalog.Item->orders.Priced", "orders.Item->orders.Priced"},
+	}
+	for relType, expected := range tests {
+		t.Run(string(relType), func(t *testing.T) {
+			if got := relationships(relType); !slices.Equal(got, expected) {
+				t.Errorf("Expected %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_SelfEmbedding(t *testing.T) {
+	// Structs embedding themselves directly or through another type must not recurse forever
+	astNodes, err := goparser.New().ParseDir(filepath.Join("testdata", "embedding"))
+	if err != nil {
+		t.Fatalf("Failed to parse directory: %v", err)
+	}
+	var nodes []structure.Node
+	for _, astNode := range astNodes {
+		nodes = append(nodes, gostructure.NewNode(astNode))
+	}
+	analysis, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze package: %v", err)
+	}
+
+	expected := map[string]bool{"Loop": false, "Ring": true, "Link": true, "Failure": true}
+	for _, elem := range analysis.(*gostructure.Analysis).Structure.Elements {
+		want, ok := expected[elem.Name]
+		if !ok || elem.Type != gostructure.ElementTypeDecl {
+			continue
+		}
+		if got := elem.Attributes["is_error_type"]; got != want {
+			t.Errorf("Expected %s is_error_type %v, got %v", elem.Name, want, got)
+		}
+	}
+}
+
 func BenchmarkAnalyzer_SampleFile(b *testing.B) {
 	parser := goparser.New()
 	analyzer := gostructure.NewAnalyzer()
@@ -0,0 +1,221 @@
+package gostructure
+
+import (
+	"path/filepath"
+	"strings"
+
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Resolves the calls recorded by the parser to the functions and methods of an analysis
+type callIndex struct {
+	functions map[string]map[string][]*Element // Scope -> name -> functions
+	methods   map[string]map[string][]*Element // Scope -> name -> methods
+	imports   map[string][]string              // File -> imported paths
+	scopes    []string
+	resolved  map[string]string // Import path -> scope
+}
+
+// Indexes the functions, methods and imports of a structure
+func newCallIndex(structure *Structure) *callIndex {
+	idx := &callIndex{
+		functions: make(map[string]map[string][]*Element),
+		methods:   make(map[string]map[string][]*Element),
+		imports:   make(map[string][]string),
+		resolved:  make(map[string]string),
+	}
+	add := func(index map[string]map[string][]*Element, elem *Element) {
+		if index[elem.Scope] == nil {
+			index[elem.Scope] = make(map[string][]*Element)
+		}
+		index[elem.Scope][elem.Name] = append(index[elem.Scope][elem.Name], elem)
+	}
+	seen := make(map[string]bool)
+	for _, elem := range structure.Elements {
+		switch elem.Type {
+		case ElementPackage:
+			idx.imports[elem.Position.Filename], _ = elem.Attributes["dependencies"].([]string)
+			if !seen[elem.Scope] {
+				seen[elem.Scope] = true
+				idx.scopes = append(idx.scopes, elem.Scope)
+			}
+		case ElementFunction:
+			add(idx.functions, elem)
+		case ElementMethod:
+			add(idx.methods, elem)
+		}
+	}
+	return idx
+}
+
+// Returns the scope of the package imported with a path: the directory sharing the most
+// trailing path elements with it, or "" when none or several do. Single-element paths are
+// standard library packages.
+func (idx *callIndex) scope(path string) string {
+	if !strings.Contains(path, "/") {
+		return ""
+	}
+	if scope, ok := idx.resolved[path]; ok {
+		return scope
+	}
+	segments := strings.Split(path, "/")
+	best, bestCount, ties := "", 0, 0
+	for _, scope := range idx.scopes {
+		dirs := strings.Split(filepath.ToSlash(filepath.Clean(scope)), "/")
+		count := 0
+		for count < len(segments) && count < len(dirs) && segments[len(segments)-1-count] == dirs[len(dirs)-1-count] {
+			count++
+		}
+		switch {
+		case count > bestCount:
+			best, bestCount, ties = scope, count, 0
+		case count == bestCount && count > 0:
+			ties++
+		}
+	}
+	if ties > 0 {
+		best = ""
+	}
+	idx.resolved[path] = best
+	return best
+}
+
+// Returns the function or method a call of caller refers to, or nil when it is outside the
+// analysis or ambiguous. Production code is never resolved to test code.
+func (idx *callIndex) resolve(caller *Element, call *goparser.Call) *Element {
+	scope := caller.Scope
+	if call.Package != "" {
+		if scope = idx.scope(call.Package); scope == "" {
+			return nil
+		}
+	}
+
+	var candidates []*Element
+	switch {
+	case !call.Method:
+		candidates = idx.functions[scope][call.Name]
+	case call.Package != "" || call.Receiver != "":
+		for _, method := range idx.methods[scope][call.Name] {
+			if receiverName(method) == call.Receiver {
+				candidates = append(candidates, method)
+			}
+		}
+	default:
+		// The receiver's type is unknown: look for the method in the caller's package and the
+		// packages its file imports
+		candidates = idx.methods[scope][call.Name]
+		for _, path := range idx.imports[caller.Position.Filename] {
+			if imported := idx.scope(path); imported != "" && imported != scope {
+				candidates = append(candidates, idx.methods[imported][call.Name]...)
+			}
+		}
+	}
+
+	var target *Element
+	for _, candidate := range candidates {
+		if candidate.IsTest() && !caller.IsTest() {
+			continue
+		}
+		if target != nil {
+			return nil
+		}
+		target = candidate
+	}
+	return target
+}
+
+// Returns the name of a method's receiver type, without pointer
+func receiverName(method *Element) string {
+	recv, ok := method.Attributes["receiver_type"].(*goparser.TypeInfo)
+	if !ok || recv == nil {
+		return ""
+	}
+	if recv.Kind == "pointer" && recv.ElemType != nil {
+		return recv.ElemType.Name
+	}
+	return recv.Name
+}
+
+// Detects calls between the functions and methods of the analysis
+func (a *Analyzer) detectCalls(analysis *Analysis) error {
+	idx := newCallIndex(analysis.Structure)
+	seen := make(map[callKey]bool)
+	for _, elem := range analysis.Structure.Elements {
+		if (elem.Type != ElementFunction && elem.Type != ElementMethod) || !analysis.detects(elem) {
+			continue
+		}
+		calls, _ := elem.Attributes["calls"].([]*goparser.Call)
+		for _, call := range calls {
+			target := idx.resolve(elem, call)
+			if target == nil || target == elem {
+				continue
+			}
+			key := callKey{source: elem, target: target}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			analysis.Structure.Relationships = append(analysis.Structure.Relationships, &Relationship{
+				Type:   RelationCalls,
+				Source: elem,
+				Target: target,
+			})
+		}
+	}
+	return nil
+}
+
+// Identifies a call from a source to a target, to avoid duplicates
+type callKey struct {
+	source *Element
+	target *Element
+}
+
+// Detects the production functions and methods each test exercises, and the types of those
+// methods. Calls are followed through the helpers of test files, so a test calling a helper
+// exercises what the helper calls.
+func (a *Analyzer) detectTests(analysis *Analysis) error {
+	calls := make(map[*Element][]*Element)
+	receivers := make(map[*Element]*Element)
+	for _, rel := range analysis.Structure.Relationships {
+		switch rel.Type {
+		case RelationCalls:
+			calls[rel.Source] = append(calls[rel.Source], rel.Target)
+		case RelationMethodReceiver:
+			receivers[rel.Source] = rel.Target
+		}
+	}
+
+	for _, test := range analysis.Structure.Elements {
+		if kind, _ := test.Attributes["test_kind"].(goparser.TestKind); kind == "" || kind == goparser.TestMain {
+			continue
+		}
+		exercised := make(map[*Element]bool)
+		visited := map[*Element]bool{test: true}
+		pending := []*Element{test}
+		for len(pending) > 0 {
+			caller := pending[0]
+			pending = pending[1:]
+			for _, target := range calls[caller] {
+				if target.IsTest() {
+					if !visited[target] {
+						visited[target] = true
+						pending = append(pending, target)
+					}
+					continue
+				}
+				for _, elem := range []*Element{target, receivers[target]} {
+					if elem != nil && !exercised[elem] {
+						exercised[elem] = true
+						analysis.Structure.Relationships = append(analysis.Structure.Relationships, &Relationship{
+							Type:   RelationTests,
+							Source: test,
+							Target: elem,
+						})
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
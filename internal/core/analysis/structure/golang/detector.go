@@ -0,0 +1,194 @@
+package gostructure
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Names of the built-in detectors
+const (
+	DetectorReferences          = "references"
+	DetectorReceivers           = "receivers"
+	DetectorInterfaceEmbeddings = "interface_embeddings"
+	DetectorImplementations     = "implementations"
+	DetectorComposition         = "composition"
+	DetectorErrorTypes          = "error_types"
+	DetectorSyncPrimitives      = "sync_primitives"
+	DetectorCalls               = "calls"
+	DetectorTests               = "tests"
+)
+
+// Detects patterns in an analysis, adding relationships or element attributes
+type Detector interface {
+	// Returns a unique name for the detector
+	Name() string
+
+	// Returns the names of the detectors that must run before this one
+	DependsOn() []string
+
+	// Runs the detection on the analysis
+	Detect(analysis *Analysis) error
+}
+
+// A detector backed by a function
+type funcDetector struct {
+	name      string
+	dependsOn []string
+	detect    func(analysis *Analysis) error
+	local     bool // Results for an element depend only on the element and the declarations it refers to
+}
+
+// Creates a detector from a detection function
+func NewDetector(name string, dependsOn []string, detect func(analysis *Analysis) error) Detector {
+	return &funcDetector{name: name, dependsOn: dependsOn, detect: detect}
+}
+
+// Creates a package-local detector, which a workspace update only re-runs for the elements of
+// the changed packages and the packages referring to them. The detection function must skip
+// the elements Analysis.detects reports as out of scope.
+func newLocalDetector(name string, dependsOn []string, detect func(analysis *Analysis) error) Detector {
+	return &funcDetector{name: name, dependsOn: dependsOn, detect: detect, local: true}
+}
+
+// Checks if a detector is package-local
+func isLocal(detector Detector) bool {
+	d, ok := detector.(*funcDetector)
+	return ok && d.local
+}
+
+func (d *funcDetector) Name() string                    { return d.name }
+func (d *funcDetector) DependsOn() []string             { return d.dependsOn }
+func (d *funcDetector) Detect(analysis *Analysis) error { return d.detect(analysis) }
+
+// Holds the detectors run by the analyzer and the order they run in
+type Registry struct {
+	detectors []Detector // In registration order
+	disabled  map[string]bool
+}
+
+// Creates a new empty registry
+func NewRegistry() *Registry {
+	return &Registry{disabled: make(map[string]bool)}
+}
+
+// Adds a detector. Detector names must be unique.
+func (r *Registry) Register(detector Detector) error {
+	if r.Detector(detector.Name()) != nil {
+		return fmt.Errorf("detector %q is already registered", detector.Name())
+	}
+	r.detectors = append(r.detectors, detector)
+	return nil
+}
+
+// Returns the registered detector with the given name, or nil
+func (r *Registry) Detector(name string) Detector {
+	for _, d := range r.detectors {
+		if d.Name() == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// Enables a detector
+func (r *Registry) Enable(name string) {
+	delete(r.disabled, name)
+}
+
+// Disables a detector so that it is skipped when running
+func (r *Registry) Disable(name string) {
+	r.disabled[name] = true
+}
+
+// Reports whether a detector is enabled
+func (r *Registry) Enabled(name string) bool {
+	return !r.disabled[name]
+}
+
+// Enables or disables detectors by name (e.g. from the analysis.detectors config section)
+func (r *Registry) Configure(settings map[string]bool) error {
+	for name, enabled := range settings {
+		if r.Detector(name) == nil {
+			return fmt.Errorf("unknown detector %q", name)
+		}
+		if enabled {
+			r.Enable(name)
+		} else {
+			r.Disable(name)
+		}
+	}
+	return nil
+}
+
+// Returns the enabled detectors ordered so that every detector runs after its
+// dependencies. Ties keep the registration order.
+func (r *Registry) Ordered() ([]Detector, error) {
+	position := make(map[string]int, len(r.detectors))
+	for i, d := range r.detectors {
+		position[d.Name()] = i
+	}
+
+	// Count unmet dependencies (Kahn's algorithm)
+	pending := make(map[string]int, len(r.detectors))
+	dependents := make(map[string][]string)
+	for _, d := range r.detectors {
+		for _, dep := range d.DependsOn() {
+			if _, ok := position[dep]; !ok {
+				return nil, fmt.Errorf("detector %q depends on unknown detector %q", d.Name(), dep)
+			}
+			pending[d.Name()]++
+			dependents[dep] = append(dependents[dep], d.Name())
+		}
+	}
+
+	var ready []string
+	for _, d := range r.detectors {
+		if pending[d.Name()] == 0 {
+			ready = append(ready, d.Name())
+		}
+	}
+
+	ordered := make([]Detector, 0, len(r.detectors))
+	visited := 0
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		visited++
+
+		d := r.detectors[position[name]]
+		if r.Enabled(name) {
+			ordered = append(ordered, d)
+		}
+
+		for _, dependent := range dependents[name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+		sort.Slice(ready, func(i, j int) bool { return position[ready[i]] < position[ready[j]] })
+	}
+
+	if visited < len(r.detectors) {
+		var cycle []string
+		for _, d := range r.detectors {
+			if pending[d.Name()] > 0 {
+				cycle = append(cycle, d.Name())
+			}
+		}
+		return nil, fmt.Errorf("dependency cycle between detectors %v", cycle)
+	}
+	return ordered, nil
+}
+
+// Returns the disabled dependencies of a detector
+func (r *Registry) disabledDependencies(detector Detector) []string {
+	var deps []string
+	for _, dep := range detector.DependsOn() {
+		if !r.Enabled(dep) && !slices.Contains(deps, dep) {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
@@ -0,0 +1,217 @@
+package gostructure_test
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Helper function returning a detector that does nothing
+func noopDetector(name string, dependsOn ...string) gostructure.Detector {
+	return gostructure.NewDetector(name, dependsOn, func(*gostructure.Analysis) error { return nil })
+}
+
+// Helper function returning the names of detectors
+func detectorNames(detectors []gostructure.Detector) []string {
+	names := make([]string, 0, len(detectors))
+	for _, d := range detectors {
+		names = append(names, d.Name())
+	}
+	return names
+}
+
+func TestRegistry_Ordered(t *testing.T) {
+	tests := []struct {
+		name      string
+		detectors []gostructure.Detector
+		disabled  []string
+		expected  []string
+		wantErr   bool
+	}{
+		{
+			name: "RegistrationOrderWithoutDependencies",
+			detectors: []gostructure.Detector{
+				noopDetector("a"), noopDetector("b"), noopDetector("c"),
+			},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name: "DependenciesFirst",
+			detectors: []gostructure.Detector{
+				noopDetector("implementations", "references"),
+				noopDetector("custom", "implementations"),
+				noopDetector("references"),
+			},
+			expected: []string{"references", "implementations", "custom"},
+		},
+		{
+			name: "DisabledSkipped",
+			detectors: []gostructure.Detector{
+				noopDetector("a"), noopDetector("b", "a"), noopDetector("c", "b"),
+			},
+			disabled: []string{"b"},
+			expected: []string{"a", "c"},
+		},
+		{
+			name: "UnknownDependency",
+			detectors: []gostructure.Detector{
+				noopDetector("a", "missing"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cycle",
+			detectors: []gostructure.Detector{
+				noopDetector("a", "c"), noopDetector("b", "a"), noopDetector("c", "b"), noopDetector("d"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := gostructure.NewRegistry()
+			for _, d := range tt.detectors {
+				if err := registry.Register(d); err != nil {
+					t.Fatalf("Failed to register %s: %v", d.Name(), err)
+				}
+			}
+			for _, name := range tt.disabled {
+				registry.Disable(name)
+			}
+
+			ordered, err := registry.Ordered()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got order %v", detectorNames(ordered))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := detectorNames(ordered); !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected order %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRegistry_RegisterDuplicate(t *testing.T) {
+	registry := gostructure.NewRegistry()
+	if err := registry.Register(noopDetector("a")); err != nil {
+		t.Fatalf("Failed to register: %v", err)
+	}
+	if err := registry.Register(noopDetector("a")); err == nil {
+		t.Error("Expected error registering a duplicate detector")
+	}
+}
+
+func TestRegistry_Configure(t *testing.T) {
+	registry := gostructure.NewAnalyzer().Registry()
+
+	if err := registry.Configure(map[string]bool{gostructure.DetectorComposition: false}); err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+	if registry.Enabled(gostructure.DetectorComposition) {
+		t.Error("Expected composition detector to be disabled")
+	}
+
+	if err := registry.Configure(map[string]bool{gostructure.DetectorComposition: true}); err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+	if !registry.Enabled(gostructure.DetectorComposition) {
+		t.Error("Expected composition detector to be enabled")
+	}
+
+	if err := registry.Configure(map[string]bool{"missing": false}); err == nil {
+		t.Error("Expected error configuring an unknown detector")
+	}
+}
+
+func TestAnalyzer_CustomDetector(t *testing.T) {
+	analyzer := gostructure.NewAnalyzer()
+	analyzer.Registry().Disable(gostructure.DetectorComposition)
+
+	// Count the implementations found by the built-in detector
+	implementations := -1
+	err := analyzer.Registry().Register(gostructure.NewDetector("count_implementations", []string{gostructure.DetectorImplementations}, func(analysis *gostructure.Analysis) error {
+		implementations = 0
+		for _, rel := range analysis.Structure.Relationships {
+			if rel.Type == gostructure.RelationImplements {
+				implementations++
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("Failed to register detector: %v", err)
+	}
+
+	astNode, err := goparser.New().ParseFile(filepath.Join("testdata", "sample.go"))
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+	analysis, err := analyzer.Analyze(gostructure.NewNode(astNode))
+	if err != nil {
+		t.Fatalf("Failed to analyze file: %v", err)
+	}
+	goAnalysis := analysis.(*gostructure.Analysis)
+
+	if implementations <= 0 {
+		t.Errorf("Expected custom detector to see implementations, got %d", implementations)
+	}
+	for _, rel := range goAnalysis.Structure.Relationships {
+		if rel.Type == gostructure.RelationEmbeds {
+			t.Errorf("Expected no embeds relationships with composition disabled, got %s -> %s", rel.Source.Name, rel.Target.Name)
+		}
+	}
+
+	if _, ok := goAnalysis.DetectorTimings["count_implementations"]; !ok {
+		t.Error("Expected timing for the custom detector")
+	}
+	if _, ok := goAnalysis.DetectorTimings[gostructure.DetectorComposition]; ok {
+		t.Error("Expected no timing for the disabled composition detector")
+	}
+}
+
+func TestAnalyzer_Logger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	analyzer := gostructure.NewAnalyzer()
+	analyzer.SetLogger(zap.New(core))
+	if err := analyzer.Registry().Configure(map[string]bool{gostructure.DetectorReferences: false}); err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+
+	astNode, err := goparser.New().ParseFile(filepath.Join("testdata", "sample.go"))
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+	if _, err := analyzer.Analyze(gostructure.NewNode(astNode)); err != nil {
+		t.Fatalf("Failed to analyze file: %v", err)
+	}
+
+	// Detectors depending on the disabled references detector are reported
+	var warned []string
+	for _, entry := range logs.FilterMessage("Detector dependencies are disabled").All() {
+		if entry.Level != zapcore.WarnLevel {
+			t.Errorf("Expected a warning, got %s", entry.Level)
+		}
+		warned = append(warned, entry.ContextMap()["detector"].(string))
+	}
+	if expected := []string{gostructure.DetectorImplementations, gostructure.DetectorComposition}; !slices.Equal(warned, expected) {
+		t.Errorf("Expected warnings for %v, got %v", expected, warned)
+	}
+
+	// Every detector that ran reports its duration
+	if finished := logs.FilterMessage("Detector finished").Len(); finished != 8 {
+		t.Errorf("Expected 8 finished detectors, got %d", finished)
+	}
+}
@@ -0,0 +1,113 @@
+package gostructure
+
+import "sort"
+
+// The differences between two structures
+type Diff struct {
+	AddedElements        []*Element
+	RemovedElements      []*Element
+	AddedRelationships   []*Relationship
+	RemovedRelationships []*Relationship
+}
+
+// Checks if the structures are the same
+func (d *Diff) Empty() bool {
+	return len(d.AddedElements) == 0 && len(d.RemovedElements) == 0 &&
+		len(d.AddedRelationships) == 0 && len(d.RemovedRelationships) == 0
+}
+
+// Compares two structures. Elements are matched by type, directory, receiver and
+// name, so moving code within a package or between lines is not a change.
+func DiffStructures(before, after *Structure) *Diff {
+	diff := &Diff{}
+
+	oldElements := elementsByKey(before)
+	newElements := elementsByKey(after)
+	for key, elem := range newElements {
+		if _, ok := oldElements[key]; !ok {
+			diff.AddedElements = append(diff.AddedElements, elem)
+		}
+	}
+	for key, elem := range oldElements {
+		if _, ok := newElements[key]; !ok {
+			diff.RemovedElements = append(diff.RemovedElements, elem)
+		}
+	}
+
+	oldRelationships := relationshipsByKey(before)
+	newRelationships := relationshipsByKey(after)
+	for key, rel := range newRelationships {
+		if _, ok := oldRelationships[key]; !ok {
+			diff.AddedRelationships = append(diff.AddedRelationships, rel)
+		}
+	}
+	for key, rel := range oldRelationships {
+		if _, ok := newRelationships[key]; !ok {
+			diff.RemovedRelationships = append(diff.RemovedRelationships, rel)
+		}
+	}
+
+	sortElements(diff.AddedElements)
+	sortElements(diff.RemovedElements)
+	sortRelationships(diff.AddedRelationships)
+	sortRelationships(diff.RemovedRelationships)
+	return diff
+}
+
+// Returns the identity of an element across analyses
+func elementKey(elem *Element) string {
+	return string(elem.ID())
+}
+
+// Indexes the named elements of a structure by key
+func elementsByKey(structure *Structure) map[string]*Element {
+	elements := make(map[string]*Element)
+	for _, elem := range structure.Elements {
+		if elem.Name == "" {
+			continue
+		}
+		elements[elementKey(elem)] = elem
+	}
+	return elements
+}
+
+// Indexes the relationships between named elements by key
+func relationshipsByKey(structure *Structure) map[string]*Relationship {
+	relationships := make(map[string]*Relationship)
+	for _, rel := range structure.Relationships {
+		if rel.Source.Name == "" || rel.Target.Name == "" {
+			continue
+		}
+		key := string(rel.Type) + "\x01" + elementKey(rel.Source) + "\x01" + elementKey(rel.Target)
+		relationships[key] = rel
+	}
+	return relationships
+}
+
+// Orders elements by location
+func sortElements(elements []*Element) {
+	sort.Slice(elements, func(i, j int) bool {
+		a, b := elements[i].Position, elements[j].Position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return elements[i].Name < elements[j].Name
+	})
+}
+
+// Orders relationships by type, then by source and target location
+func sortRelationships(relationships []*Relationship) {
+	sort.Slice(relationships, func(i, j int) bool {
+		a, b := relationships[i], relationships[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if ka, kb := elementKey(a.Source), elementKey(b.Source); ka != kb {
+			return ka < kb
+		}
+		return elementKey(a.Target) < elementKey(b.Target)
+	})
+}
@@ -0,0 +1,87 @@
+package gostructure_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Helper function to analyze a testdata file copied to path, so versions share a location
+func analyzeAt(t *testing.T, testdata, path string) *gostructure.Structure {
+	t.Helper()
+
+	src, err := os.ReadFile(filepath.Join("testdata", testdata))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", testdata, err)
+	}
+	if err := os.WriteFile(path, src, 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	node, err := goparser.New().ParseFile(path)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", testdata, err)
+	}
+	analysis, err := gostructure.NewAnalyzer().Analyze(gostructure.NewNode(node))
+	if err != nil {
+		t.Fatalf("Failed to analyze %s: %v", testdata, err)
+	}
+	return analysis.(*gostructure.Analysis).Structure
+}
+
+func TestDiffStructures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shapes.go")
+	old := analyzeAt(t, "diff/old.go", path)
+	updated := analyzeAt(t, "diff/new.go", path)
+
+	t.Run("Unchanged", func(t *testing.T) {
+		if diff := gostructure.DiffStructures(old, old); !diff.Empty() {
+			t.Errorf("Expected no differences, got %+v", diff)
+		}
+	})
+
+	t.Run("Changes", func(t *testing.T) {
+		diff := gostructure.DiffStructures(old, updated)
+
+		elements := func(elems []*gostructure.Element) []string {
+			var names []string
+			for _, e := range elems {
+				names = append(names, fmt.Sprintf("%s %s", e.Type, e.Name))
+			}
+			return names
+		}
+		relationships := func(rels []*gostructure.Relationship) []string {
+			var names []string
+			for _, r := range rels {
+				names = append(names, fmt.Sprintf("%s %s->%s", r.Type, r.Source.Name, r.Target.Name))
+			}
+			return names
+		}
+
+		tests := []struct {
+			name     string
+			got      []string
+			expected []string
+		}{
+			{"AddedElements", elements(diff.AddedElements), []string{"method Area"}},
+			{"RemovedElements", elements(diff.RemovedElements), []string{"method Scale"}},
+			{"AddedRelationships", relationships(diff.AddedRelationships), []string{
+				"contains shapes->Area", "implements Circle->Shape", "method_receiver Area->Circle",
+			}},
+			{"RemovedRelationships", relationships(diff.RemovedRelationships), []string{
+				"contains shapes->Scale", "method_receiver Scale->Square",
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if !slices.Equal(tt.got, tt.expected) {
+					t.Errorf("Expected %v, got %v", tt.expected, tt.got)
+				}
+			})
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package gostructure
 
+import "maps"
+
 // The type of metric
 type MetricType string
 
@@ -27,46 +29,93 @@ const (
 	MetricAvgDepth    MetricType = "avg_depth"
 	MetricMaxChildren MetricType = "max_children"
 	MetricAvgChildren MetricType = "avg_children"
+
+	// Coverage metrics, counted once a coverage profile is applied
+	MetricStatements        MetricType = "statements"
+	MetricCoveredStatements MetricType = "covered_statements"
 )
 
 // Collects metrics about the code structure
 type MetricsCollector struct {
-	metrics map[MetricType]int
+	metrics          map[MetricType]int
+	generatedMetrics map[MetricType]int // Metrics of generated code, when reported separately
+	generated        GeneratedCode
 }
 
-// Creates a new metrics collector
+// Creates a new metrics collector, counting generated code like handwritten code
 func NewMetricsCollector() *MetricsCollector {
 	return &MetricsCollector{
-		metrics: make(map[MetricType]int),
+		metrics:          make(map[MetricType]int),
+		generatedMetrics: make(map[MetricType]int),
+		generated:        GeneratedInclude,
 	}
 }
 
+// Sets how generated code is treated. When it is reported separately, the metrics describe
+// handwritten code and the generated code has its own metrics.
+func (c *MetricsCollector) SetGenerated(mode GeneratedCode) {
+	c.generated = mode
+}
+
 // Returns the value of a metric
 func (c *MetricsCollector) Metric(metric MetricType) int {
 	return c.metrics[metric]
 }
 
+// Returns a copy of all collected metrics
+func (c *MetricsCollector) Metrics() map[MetricType]int {
+	return maps.Clone(c.metrics)
+}
+
+// Returns the value of a metric for generated code, when it is reported separately
+func (c *MetricsCollector) GeneratedMetric(metric MetricType) int {
+	return c.generatedMetrics[metric]
+}
+
+// Returns a copy of the metrics of generated code, empty unless it is reported separately
+func (c *MetricsCollector) GeneratedMetrics() map[MetricType]int {
+	return maps.Clone(c.generatedMetrics)
+}
+
 // CollectMetrics collects metrics from the structure
 func (c *MetricsCollector) CollectMetrics(structure *Structure) {
 	// Reset metrics
 	c.metrics = make(map[MetricType]int)
+	c.generatedMetrics = make(map[MetricType]int)
+
+	switch c.generated {
+	case GeneratedExclude:
+		structure = structure.Handwritten()
+	case GeneratedSeparate:
+		collectMetrics(c.generatedMetrics, structure.Generated())
+		structure = structure.Handwritten()
+	}
+	collectMetrics(c.metrics, structure)
+}
 
+// Collects the metrics of a structure into metrics
+func collectMetrics(metrics map[MetricType]int, structure *Structure) {
 	// Count elements by type
 	for _, elem := range structure.Elements {
-		c.metrics[MetricTotalElements]++
+		metrics[MetricTotalElements]++
 		switch elem.Type {
 		case ElementPackage:
-			c.metrics[MetricPackages]++
+			metrics[MetricPackages]++
+			// Each package element carries the statement counts of its file
+			statements, _ := elem.Attributes["statements"].(int)
+			covered, _ := elem.Attributes["covered_statements"].(int)
+			metrics[MetricStatements] += statements
+			metrics[MetricCoveredStatements] += covered
 		case ElementTypeDecl:
-			c.metrics[MetricTypes]++
+			metrics[MetricTypes]++
 		case ElementFunction:
-			c.metrics[MetricFunctions]++
+			metrics[MetricFunctions]++
 		case ElementMethod:
-			c.metrics[MetricMethods]++
+			metrics[MetricMethods]++
 		case ElementInterface:
-			c.metrics[MetricInterfaces]++
+			metrics[MetricInterfaces]++
 		case ElementVariable:
-			c.metrics[MetricVariables]++
+			metrics[MetricVariables]++
 		}
 	}
 
@@ -74,27 +123,27 @@ func (c *MetricsCollector) CollectMetrics(structure *Structure) {
 	for _, rel := range structure.Relationships {
 		switch rel.Type {
 		case RelationContains:
-			c.metrics[MetricContains]++
+			metrics[MetricContains]++
 		case RelationImplements:
-			c.metrics[MetricImplements]++
+			metrics[MetricImplements]++
 		case RelationEmbeds:
-			c.metrics[MetricEmbeds]++
+			metrics[MetricEmbeds]++
 		case RelationInterfaceEmbeds:
-			c.metrics[MetricInterfaceEmbeds]++
+			metrics[MetricInterfaceEmbeds]++
 		case RelationMethodReceiver:
-			c.metrics[MetricMethodReceiver]++
+			metrics[MetricMethodReceiver]++
 		case RelationCalls:
-			c.metrics[MetricCalls]++
+			metrics[MetricCalls]++
 		case RelationReferences:
-			c.metrics[MetricReferences]++
+			metrics[MetricReferences]++
 		}
 	}
 
-	c.calculateComplexityMetrics(structure)
+	calculateComplexityMetrics(metrics, structure)
 }
 
 // Calculates complexity-related metrics
-func (c *MetricsCollector) calculateComplexityMetrics(structure *Structure) {
+func calculateComplexityMetrics(metrics map[MetricType]int, structure *Structure) {
 	depths := make(map[*Element]int)
 	children := make(map[*Element]int)
 
@@ -127,12 +176,12 @@ func (c *MetricsCollector) calculateComplexityMetrics(structure *Structure) {
 	}
 
 	// Store metrics
-	c.metrics[MetricMaxDepth] = maxDepth
+	metrics[MetricMaxDepth] = maxDepth
 	if len(depths) > 0 {
-		c.metrics[MetricAvgDepth] = totalDepth / len(depths)
+		metrics[MetricAvgDepth] = totalDepth / len(depths)
 	}
-	c.metrics[MetricMaxChildren] = maxChildren
+	metrics[MetricMaxChildren] = maxChildren
 	if len(children) > 0 {
-		c.metrics[MetricAvgChildren] = totalChildren / len(children)
+		metrics[MetricAvgChildren] = totalChildren / len(children)
 	}
 }
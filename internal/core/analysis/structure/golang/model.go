@@ -2,7 +2,12 @@
 package gostructure
 
 import (
+	"strings"
+	"time"
+
+	"codedna/internal/core/analysis/structure"
 	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
 )
 
 // Node wraps an AST node with Go-specific functionality
@@ -20,65 +25,66 @@ func NewNode(node ast.Node) *Node {
 	return &Node{Node: node}
 }
 
-// The type of a code element
-type ElementType string
+// The language-neutral structure model, shared with the analyzers of other languages
+type (
+	ElementType  = structure.ElementType
+	RelationType = structure.RelationType
+	Element      = structure.Element
+	Relationship = structure.Relationship
+	Structure    = structure.Structure
+)
 
 const (
-	ElementPackage   ElementType = "package"
-	ElementInterface ElementType = "interface"
-	ElementTypeDecl  ElementType = "type"
-	ElementFunction  ElementType = "function"
-	ElementMethod    ElementType = "method"
-	ElementVariable  ElementType = "variable"
+	ElementPackage   = structure.ElementPackage
+	ElementInterface = structure.ElementInterface
+	ElementTypeDecl  = structure.ElementTypeDecl
+	ElementFunction  = structure.ElementFunction
+	ElementMethod    = structure.ElementMethod
+	ElementVariable  = structure.ElementVariable
 )
 
-// The type of relationship between elements
-type RelationType string
-
 const (
-	RelationContains        RelationType = "contains"
-	RelationImplements      RelationType = "implements"
-	RelationEmbeds          RelationType = "embeds"
-	RelationInterfaceEmbeds RelationType = "interface_embeds"
-	RelationMethodReceiver  RelationType = "method_receiver"
-	RelationCalls           RelationType = "calls" // function/method calls
-	RelationReferences      RelationType = "references"
+	RelationContains        = structure.RelationContains
+	RelationImplements      = structure.RelationImplements
+	RelationEmbeds          = structure.RelationEmbeds
+	RelationInterfaceEmbeds = structure.RelationInterfaceEmbeds
+	RelationMethodReceiver  = structure.RelationMethodReceiver
+	RelationCalls           = structure.RelationCalls
+	RelationReferences      = structure.RelationReferences
+	RelationExtends         = structure.RelationExtends
+	RelationRequests        = structure.RelationRequests
+	RelationSharesShape     = structure.RelationSharesShape
+	RelationGeneratedFrom   = structure.RelationGeneratedFrom
+	RelationTests           = structure.RelationTests
 )
 
-// A code element in the structure
-type Element struct {
-	Type       ElementType
-	Name       string
-	Attributes map[string]any
-}
-
-// A relationship between two elements
-type Relationship struct {
-	Type   RelationType
-	Source *Element
-	Target *Element
-}
+// How metrics and profiles treat generated code
+type GeneratedCode = structure.GeneratedCode
 
-// The analyzed code structure
-type Structure struct {
-	Elements      []*Element
-	Relationships []*Relationship
-}
+const (
+	GeneratedInclude  = structure.GeneratedInclude
+	GeneratedExclude  = structure.GeneratedExclude
+	GeneratedSeparate = structure.GeneratedSeparate
+)
 
 // The results of Go code structure analysis
 type Analysis struct {
-	language  string
-	Structure *Structure
+	language        string
+	Structure       *Structure
+	DetectorTimings map[string]time.Duration // Time spent in each detector
+	packageNames    map[string]string        // Package directory -> name, built on first use
+	typeAliases     map[string]string        // Qualified alias name -> qualified aliased name, built on first use
+	local           map[*Relationship]bool   // Relationships added by package-local detectors
+	scopes          map[string]bool          // Package directories package-local detectors revisit, nil for all
 }
 
 // Creates a new analysis result
 func NewAnalysis() *Analysis {
 	return &Analysis{
-		language: "go",
-		Structure: &Structure{
-			Elements:      make([]*Element, 0),
-			Relationships: make([]*Relationship, 0),
-		},
+		language:        "go",
+		Structure:       structure.NewStructure(),
+		DetectorTimings: make(map[string]time.Duration),
+		local:           make(map[*Relationship]bool),
 	}
 }
 
@@ -86,3 +92,66 @@ func NewAnalysis() *Analysis {
 func (a *Analysis) Language() string {
 	return a.language
 }
+
+// Checks if the running detector handles an element. Package-local detectors skip the
+// elements of packages a workspace update did not affect.
+func (a *Analysis) detects(elem *Element) bool {
+	return a.scopes == nil || a.scopes[elem.Scope]
+}
+
+// Returns the name of the package declaring an element
+func (a *Analysis) packageName(elem *Element) string {
+	if pkg, ok := elem.Attributes["test_package"].(string); ok {
+		return pkg
+	}
+	if a.packageNames == nil {
+		a.packageNames = make(map[string]string)
+		for _, e := range a.Structure.Elements {
+			if e.Type == ElementPackage && !strings.HasSuffix(e.Name, "_test") {
+				a.packageNames[e.Scope] = e.Name
+			}
+		}
+	}
+	return a.packageNames[elem.Scope]
+}
+
+// Returns the qualified name of a type used in the named package (e.g. "User" in package
+// domain is "domain.User"), following type aliases to the type they denote. Predeclared
+// types keep their name.
+func (a *Analysis) qualifiedTypeName(name, pkg string) string {
+	qualify := func(name, pkg string) string {
+		if name == "" || predeclaredTypes[name] || strings.Contains(name, ".") {
+			return name
+		}
+		return pkg + "." + name
+	}
+
+	if a.typeAliases == nil {
+		a.typeAliases = make(map[string]string)
+		for _, e := range a.Structure.Elements {
+			if alias, _ := e.Attributes["is_alias"].(bool); !alias || e.Type != ElementTypeDecl {
+				continue
+			}
+			if typ, ok := e.Attributes["underlying_type"].(*goparser.TypeInfo); ok && typ.Kind == "basic" {
+				pkg := a.packageName(e)
+				a.typeAliases[qualify(e.Name, pkg)] = qualify(typ.Name, pkg)
+			}
+		}
+	}
+
+	name = qualify(name, pkg)
+	// Aliases may denote other aliases; the bound guards against alias cycles
+	for range len(a.typeAliases) {
+		aliased, ok := a.typeAliases[name]
+		if !ok {
+			break
+		}
+		name = aliased
+	}
+	return name
+}
+
+// Returns the analyzed structure
+func (a *Analysis) Graph() *Structure {
+	return a.Structure
+}
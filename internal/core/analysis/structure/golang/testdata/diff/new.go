@@ -0,0 +1,18 @@
+package shapes
+
+type Shape interface {
+	Area() int
+}
+
+// Moved down by the comment, but otherwise unchanged
+type Square struct {
+	Side int
+}
+
+func (s *Square) Area() int { return s.Side * s.Side }
+
+type Circle struct {
+	Radius int
+}
+
+func (c Circle) Area() int { return 3 * c.Radius * c.Radius }
@@ -0,0 +1,17 @@
+package shapes
+
+type Shape interface {
+	Area() int
+}
+
+type Square struct {
+	Side int
+}
+
+func (s *Square) Area() int { return s.Side * s.Side }
+
+func (s *Square) Scale(n int) { s.Side *= n }
+
+type Circle struct {
+	Radius int
+}
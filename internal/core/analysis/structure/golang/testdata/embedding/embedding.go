@@ -0,0 +1,23 @@
+package embedding
+
+// Loop embeds a pointer to itself, as linked nodes in encoding/json tests do
+type Loop struct {
+	N int
+	*Loop
+}
+
+// Ring and Link embed each other
+type Ring struct {
+	*Link
+}
+
+// Link is the other half of Ring
+type Link struct {
+	*Ring
+	Failure
+}
+
+// Failure is an error promoted through Link into Ring
+type Failure struct{}
+
+func (Failure) Error() string { return "failure" }
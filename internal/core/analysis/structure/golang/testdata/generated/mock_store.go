@@ -0,0 +1,17 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: store.go
+
+package store
+
+type MockStore struct {
+	saved []string
+}
+
+func NewMockStore() *MockStore {
+	return &MockStore{}
+}
+
+func (m *MockStore) Save(name string) error {
+	m.saved = append(m.saved, name)
+	return nil
+}
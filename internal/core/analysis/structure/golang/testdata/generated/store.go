@@ -0,0 +1,19 @@
+package store
+
+// Persists users
+type Store interface {
+	Save(name string) error
+}
+
+type memoryStore struct {
+	names []string
+}
+
+func (s *memoryStore) Save(name string) error {
+	s.names = append(s.names, name)
+	return nil
+}
+
+func NewStore() Store {
+	return &memoryStore{}
+}
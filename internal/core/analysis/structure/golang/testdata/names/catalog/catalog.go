@@ -0,0 +1,15 @@
+package catalog
+
+type Item struct {
+	Name string
+}
+
+func (i *Item) Price() float64 { return 0 }
+
+type Finder interface {
+	Find(name string) *Item
+}
+
+type Catalog struct{}
+
+func (c *Catalog) Items() []Item { return nil }
@@ -0,0 +1,26 @@
+package orders
+
+import "example.com/names/catalog"
+
+// An order line, unrelated to the catalog item of the same name
+type Item struct {
+	Product *catalog.Item
+	Count   int
+}
+
+func (i *Item) Price() float64 { return 0 }
+
+type Entry = catalog.Item
+
+type Priced interface {
+	Price() float64
+}
+
+type Lister interface {
+	Items() []Entry
+}
+
+// Finds order lines, so it is not a catalog.Finder
+type Index struct{}
+
+func (x *Index) Find(name string) *Item { return nil }
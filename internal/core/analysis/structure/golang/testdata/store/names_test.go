@@ -0,0 +1,19 @@
+package store_test
+
+import (
+	"testing"
+
+	"example.com/app/store"
+)
+
+func newStore(t *testing.T) *store.Store {
+	t.Helper()
+	return store.New()
+}
+
+func TestNames(t *testing.T) {
+	s := newStore(t)
+	if len(s.Names()) != 0 {
+		t.Error("Expected no names")
+	}
+}
@@ -0,0 +1,36 @@
+package store
+
+import "errors"
+
+type Store struct {
+	names []string
+}
+
+func New() *Store {
+	return &Store{}
+}
+
+func (s *Store) Save(name string) error {
+	if err := s.validate(name); err != nil {
+		return err
+	}
+	s.names = append(s.names, name)
+	return nil
+}
+
+func (s *Store) validate(name string) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+	return nil
+}
+
+func (s *Store) Names() []string {
+	return s.names
+}
+
+type Cache struct{}
+
+func (c *Cache) Get(key string) string {
+	return key
+}
@@ -0,0 +1,10 @@
+package store
+
+import "testing"
+
+func TestSave(t *testing.T) {
+	s := New()
+	if err := s.Save("alice"); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package gostructure
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"codedna/internal/core/analysis/structure"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Keeps the parsed packages of a project, so that a change only re-parses and re-analyzes
+// the packages it affects. Package-local detectors (references, receivers, composition and
+// calls) re-run for the changed packages and the packages referring to them, keeping their
+// earlier results elsewhere. The other detectors run over the whole workspace: interfaces are
+// satisfied implicitly, so a change can add or remove relationships in packages that do not
+// import the changed one.
+type Workspace struct {
+	analyzer *Analyzer
+	packages map[string][]structure.Node // Package directory -> parsed files
+	overlay  map[string][]byte           // Absolute file path -> contents parsed instead of the file on disk
+	analysis *Analysis
+}
+
+// Creates a new empty workspace analyzed with the given analyzer
+func NewWorkspace(analyzer *Analyzer) *Workspace {
+	return &Workspace{
+		analyzer: analyzer,
+		packages: make(map[string][]structure.Node),
+		analysis: NewAnalysis(),
+	}
+}
+
+// Returns the current analysis
+func (w *Workspace) Analysis() *Analysis {
+	return w.analysis
+}
+
+// Returns the directories of the parsed packages
+func (w *Workspace) Packages() []string {
+	dirs := make([]string, 0, len(w.packages))
+	for dir := range w.packages {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// Sets the contents of files to parse instead of reading them from disk, such as the unsaved
+// buffers of an editor. It applies to the packages re-parsed by later updates.
+func (w *Workspace) SetOverlay(overlay map[string][]byte) {
+	w.overlay = overlay
+}
+
+// Re-parses the packages in the given directories and analyzes the workspace again.
+// Directories that no longer exist or hold no Go files are dropped, along with the packages
+// below removed directories. When a package fails to parse, the workspace is left unchanged
+// and the error is returned.
+func (w *Workspace) Update(dirs []string) (*Analysis, error) {
+	parser := goparser.New()
+	parser.SetOverlay(w.overlay)
+	parsed := make(map[string][]structure.Node)
+	var removed []string
+
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		info, err := os.Stat(dir)
+		if errors.Is(err, fs.ErrNotExist) || (err == nil && !info.IsDir()) {
+			removed = append(removed, dir)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		astNodes, err := parser.ParseDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", dir, err)
+		}
+		nodes := make([]structure.Node, 0, len(astNodes))
+		for _, node := range astNodes {
+			nodes = append(nodes, NewNode(node))
+		}
+		parsed[dir] = nodes
+	}
+
+	previous := make(map[string][]structure.Node, len(w.packages))
+	maps.Copy(previous, w.packages)
+	changed := make(map[string]bool)
+	for _, dir := range removed {
+		for existing := range w.packages {
+			if existing == dir || strings.HasPrefix(existing, dir+string(filepath.Separator)) {
+				delete(w.packages, existing)
+				changed[existing] = true
+			}
+		}
+	}
+	for dir, nodes := range parsed {
+		if len(nodes) == 0 {
+			delete(w.packages, dir)
+		} else {
+			w.packages[dir] = nodes
+		}
+		changed[dir] = true
+	}
+
+	analysis, err := w.analyze(changed)
+	if err != nil {
+		w.packages = previous
+		return nil, err
+	}
+	w.analysis = analysis
+	return analysis, nil
+}
+
+// Analyzes the workspace again after the packages in the changed directories were re-parsed.
+// The elements of other packages are kept, with their relationships from package-local
+// detectors unless they may refer to a changed package.
+func (w *Workspace) analyze(changed map[string]bool) (*Analysis, error) {
+	previous := w.analysis
+	kept := make(map[string][]*Element)
+	for _, elem := range previous.Structure.Elements {
+		if !changed[elem.Scope] {
+			kept[elem.Scope] = append(kept[elem.Scope], elem)
+		}
+	}
+
+	analysis := NewAnalysis()
+	for _, dir := range w.Packages() {
+		if !changed[dir] {
+			analysis.Structure.Elements = append(analysis.Structure.Elements, kept[dir]...)
+			continue
+		}
+		for _, node := range w.packages[dir] {
+			goNode, ok := node.(*Node)
+			if !ok {
+				return nil, fmt.Errorf("expected Go node, got %T", node)
+			}
+			if _, err := w.analyzer.analyzeNode(goNode.Node, analysis); err != nil {
+				return nil, fmt.Errorf("failed to analyze Go code: %w", err)
+			}
+		}
+	}
+
+	affected := w.affected(changed)
+	for _, rel := range previous.Structure.Relationships {
+		if changed[rel.Source.Scope] || changed[rel.Target.Scope] {
+			continue
+		}
+		switch {
+		case rel.Type == RelationContains:
+		case previous.local[rel] && !affected[rel.Source.Scope]:
+			analysis.local[rel] = true
+		default:
+			continue // Detected again below
+		}
+		analysis.Structure.Relationships = append(analysis.Structure.Relationships, rel)
+	}
+
+	if err := w.analyzer.detectPatterns(analysis, affected); err != nil {
+		return nil, fmt.Errorf("failed to detect Go patterns: %w", err)
+	}
+	return analysis, nil
+}
+
+// Returns the changed package directories together with the packages that may refer to them:
+// those with a package-local relationship into a changed package, and those importing a path
+// with an element naming a changed package or its directory. Go code can only use another
+// package's declarations through an import of it.
+func (w *Workspace) affected(changed map[string]bool) map[string]bool {
+	affected := maps.Clone(changed)
+	names := make(map[string]bool)
+	for dir := range changed {
+		names[filepath.Base(dir)] = true
+		for _, node := range w.packages[dir] {
+			names[nodeName(node.(*Node).Node)] = true
+		}
+	}
+
+	previous := w.analysis
+	for _, elem := range previous.Structure.Elements {
+		if elem.Type == ElementPackage && changed[elem.Scope] {
+			names[elem.Name] = true
+		}
+	}
+	for _, elem := range previous.Structure.Elements {
+		if elem.Type != ElementPackage || changed[elem.Scope] {
+			continue
+		}
+		deps, _ := elem.Attributes["dependencies"].([]string)
+		for _, dep := range deps {
+			if slices.ContainsFunc(strings.Split(dep, "/"), func(part string) bool { return names[part] }) {
+				affected[elem.Scope] = true
+			}
+		}
+	}
+	for _, rel := range previous.Structure.Relationships {
+		if previous.local[rel] && changed[rel.Target.Scope] {
+			affected[rel.Source.Scope] = true
+		}
+	}
+	return affected
+}
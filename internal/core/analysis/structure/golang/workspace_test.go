@@ -0,0 +1,232 @@
+package gostructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+)
+
+// Helper function to write a Go source file, creating its directory
+func writeSource(t *testing.T, path, src string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// Helper function to find an element by type and name
+func findElement(analysis *gostructure.Analysis, elemType gostructure.ElementType, name string) *gostructure.Element {
+	for _, elem := range analysis.Structure.Elements {
+		if elem.Type == elemType && elem.Name == name {
+			return elem
+		}
+	}
+	return nil
+}
+
+func TestWorkspace(t *testing.T) {
+	root := t.TempDir()
+	shapes := filepath.Join(root, "shapes")
+	app := filepath.Join(root, "app")
+	writeSource(t, filepath.Join(shapes, "shapes.go"), "package shapes\n\ntype Shape interface {\n\tArea() float64\n}\n")
+	writeSource(t, filepath.Join(app, "app.go"), "package app\n\nfunc Run() {}\n")
+
+	ws := gostructure.NewWorkspace(gostructure.NewAnalyzer())
+	analysis, err := ws.Update([]string{shapes, app})
+	if err != nil {
+		t.Fatalf("Failed to update workspace: %v", err)
+	}
+
+	t.Run("InitialLoad", func(t *testing.T) {
+		if got := ws.Packages(); len(got) != 2 {
+			t.Errorf("Expected 2 packages, got %v", got)
+		}
+		if findElement(analysis, gostructure.ElementInterface, "Shape") == nil {
+			t.Error("Expected interface Shape")
+		}
+		if findElement(analysis, gostructure.ElementFunction, "Run") == nil {
+			t.Error("Expected function Run")
+		}
+	})
+
+	t.Run("ReparseChangedPackage", func(t *testing.T) {
+		writeSource(t, filepath.Join(shapes, "circle.go"),
+			"package shapes\n\ntype Circle struct{ R float64 }\n\nfunc (c Circle) Area() float64 { return c.R * c.R }\n")
+		updated, err := ws.Update([]string{shapes})
+		if err != nil {
+			t.Fatalf("Failed to update workspace: %v", err)
+		}
+
+		if findElement(updated, gostructure.ElementTypeDecl, "Circle") == nil {
+			t.Error("Expected type Circle after update")
+		}
+		if findElement(updated, gostructure.ElementFunction, "Run") == nil {
+			t.Error("Expected unchanged package to be kept")
+		}
+		var implements bool
+		for _, rel := range updated.Structure.Relationships {
+			if rel.Type == gostructure.RelationImplements && rel.Source.Name == "Circle" && rel.Target.Name == "Shape" {
+				implements = true
+			}
+		}
+		if !implements {
+			t.Error("Expected Circle to implement Shape")
+		}
+	})
+
+	t.Run("ParseErrorKeepsState", func(t *testing.T) {
+		before := ws.Analysis()
+		writeSource(t, filepath.Join(app, "broken.go"), "package app\n\nfunc Broken( {\n")
+		if _, err := ws.Update([]string{app}); err == nil {
+			t.Fatal("Expected parse error")
+		}
+		if ws.Analysis() != before {
+			t.Error("Expected analysis to be unchanged after a parse error")
+		}
+		if err := os.Remove(filepath.Join(app, "broken.go")); err != nil {
+			t.Fatalf("Failed to remove broken file: %v", err)
+		}
+	})
+
+	t.Run("Overlay", func(t *testing.T) {
+		file := filepath.Join(app, "app.go")
+		ws.SetOverlay(map[string][]byte{file: []byte("package app\n\nfunc Start() {}\n")})
+		updated, err := ws.Update([]string{app})
+		if err != nil {
+			t.Fatalf("Failed to update workspace: %v", err)
+		}
+		if findElement(updated, gostructure.ElementFunction, "Start") == nil || findElement(updated, gostructure.ElementFunction, "Run") != nil {
+			t.Error("Expected the overlay to replace app.go")
+		}
+
+		ws.SetOverlay(nil)
+		updated, err = ws.Update([]string{app})
+		if err != nil {
+			t.Fatalf("Failed to update workspace: %v", err)
+		}
+		if findElement(updated, gostructure.ElementFunction, "Run") == nil {
+			t.Error("Expected app.go to be read from disk once the overlay is cleared")
+		}
+	})
+
+	t.Run("RemovedPackage", func(t *testing.T) {
+		if err := os.RemoveAll(app); err != nil {
+			t.Fatalf("Failed to remove %s: %v", app, err)
+		}
+		updated, err := ws.Update([]string{app})
+		if err != nil {
+			t.Fatalf("Failed to update workspace: %v", err)
+		}
+		if got := ws.Packages(); len(got) != 1 || got[0] != shapes {
+			t.Errorf("Expected only %s, got %v", shapes, got)
+		}
+		if findElement(updated, gostructure.ElementFunction, "Run") != nil {
+			t.Error("Expected function Run to be removed")
+		}
+	})
+}
+
+func TestWorkspace_Incremental(t *testing.T) {
+	root := t.TempDir()
+	domain := filepath.Join(root, "domain")
+	app := filepath.Join(root, "app")
+	util := filepath.Join(root, "util")
+	writeSource(t, filepath.Join(domain, "user.go"),
+		"package domain\n\ntype User struct{ Name string }\n\nfunc NewUser(name string) *User { return &User{Name: name} }\n")
+	writeSource(t, filepath.Join(app, "app.go"),
+		"package app\n\nimport \"example.com/project/domain\"\n\ntype Service struct{ users []*domain.User }\n\n"+
+			"func (s *Service) Add(name string) { s.users = append(s.users, domain.NewUser(name)) }\n")
+	writeSource(t, filepath.Join(util, "util.go"),
+		"package util\n\ntype Base struct{}\n\nfunc (b *Base) Close() error { return nil }\n\ntype Conn struct{ *Base }\n\n"+
+			"func Open() *Conn { return &Conn{} }\n\nfunc Dial() *Conn { return Open() }\n")
+
+	analyzer := gostructure.NewAnalyzer()
+	ws := gostructure.NewWorkspace(analyzer)
+	if _, err := ws.Update([]string{domain, app, util}); err != nil {
+		t.Fatalf("Failed to update workspace: %v", err)
+	}
+
+	// Helper function to check an update against analyzing every package from scratch
+	checkUpdate := func(t *testing.T, dirs ...string) *gostructure.Analysis {
+		t.Helper()
+		updated, err := ws.Update(dirs)
+		if err != nil {
+			t.Fatalf("Failed to update workspace: %v", err)
+		}
+		full, err := gostructure.NewWorkspace(analyzer).Update(ws.Packages())
+		if err != nil {
+			t.Fatalf("Failed to analyze workspace: %v", err)
+		}
+		if diff := gostructure.DiffStructures(full.Structure, updated.Structure); !diff.Empty() {
+			t.Errorf("Expected the update to match a full analysis, got added %v %v, removed %v %v",
+				diff.AddedElements, diff.AddedRelationships, diff.RemovedElements, diff.RemovedRelationships)
+		}
+		return updated
+	}
+
+	// Helper function to find a relationship between two named elements
+	findRelationship := func(analysis *gostructure.Analysis, relType gostructure.RelationType, source, target string) *gostructure.Relationship {
+		for _, rel := range analysis.Structure.Relationships {
+			if rel.Type == relType && rel.Source.Name == source && rel.Target.Name == target {
+				return rel
+			}
+		}
+		return nil
+	}
+
+	t.Run("UnaffectedPackagesKeepResults", func(t *testing.T) {
+		before := ws.Analysis()
+		embeds := findRelationship(before, gostructure.RelationEmbeds, "Conn", "Base")
+		calls := findRelationship(before, gostructure.RelationCalls, "Dial", "Open")
+		if embeds == nil || calls == nil {
+			t.Fatal("Expected util relationships before the update")
+		}
+
+		writeSource(t, filepath.Join(domain, "user.go"),
+			"package domain\n\ntype User struct{ Name, Email string }\n\nfunc NewUser(name string) *User { return &User{Name: name} }\n")
+		updated := checkUpdate(t, domain)
+
+		// util does not import domain, so its package-local relationships are not detected again
+		if findRelationship(updated, gostructure.RelationEmbeds, "Conn", "Base") != embeds ||
+			findRelationship(updated, gostructure.RelationCalls, "Dial", "Open") != calls {
+			t.Error("Expected util relationships to be kept")
+		}
+		// app imports domain, so its references move to the re-parsed User
+		if rel := findRelationship(updated, gostructure.RelationReferences, "Service", "User"); rel == nil || rel.Target != findElement(updated, gostructure.ElementTypeDecl, "User") {
+			t.Error("Expected Service to reference the re-parsed User")
+		}
+	})
+
+	t.Run("RemovedDeclaration", func(t *testing.T) {
+		writeSource(t, filepath.Join(domain, "user.go"), "package domain\n\ntype Account struct{}\n")
+		updated := checkUpdate(t, domain)
+		if findRelationship(updated, gostructure.RelationReferences, "Service", "User") != nil ||
+			findRelationship(updated, gostructure.RelationCalls, "Add", "NewUser") != nil {
+			t.Error("Expected relationships into removed declarations to be dropped")
+		}
+	})
+
+	t.Run("ImplicitImplementation", func(t *testing.T) {
+		// A type in a package that does not import util starts implementing an interface
+		writeSource(t, filepath.Join(util, "closer.go"), "package util\n\ntype Closer interface {\n\tClose() error\n}\n")
+		checkUpdate(t, util)
+		writeSource(t, filepath.Join(domain, "user.go"), "package domain\n\ntype Account struct{}\n\nfunc (a Account) Close() error { return nil }\n")
+		updated := checkUpdate(t, domain)
+		if findRelationship(updated, gostructure.RelationImplements, "Account", "Closer") == nil {
+			t.Error("Expected Account to implement Closer")
+		}
+	})
+
+	t.Run("RemovedPackage", func(t *testing.T) {
+		if err := os.RemoveAll(domain); err != nil {
+			t.Fatalf("Failed to remove %s: %v", domain, err)
+		}
+		checkUpdate(t, domain)
+	})
+}
@@ -0,0 +1,106 @@
+package structure
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A project-wide structure merged from the analyses of several languages. Elements are
+// deduplicated by ID, so adding overlapping analyses keeps a single element for each.
+type Project struct {
+	Structure     *Structure
+	ParseErrors   []error // Files left out of the structure because they could not be parsed
+	languages     []string
+	elements      map[ElementID]*Element
+	relationships map[relationshipKey]bool
+}
+
+// Identifies a relationship, to avoid duplicates
+type relationshipKey struct {
+	typ    RelationType
+	source ElementID
+	target ElementID
+}
+
+// Creates an empty project
+func NewProject() *Project {
+	return &Project{
+		Structure:     NewStructure(),
+		elements:      make(map[ElementID]*Element),
+		relationships: make(map[relationshipKey]bool),
+	}
+}
+
+// Adds the structure of an analysis to the project. Relationships to elements the project
+// already has are redirected to the existing elements.
+func (p *Project) Add(analysis Analysis) error {
+	if analysis == nil || analysis.Graph() == nil {
+		return fmt.Errorf("cannot add a nil analysis")
+	}
+	if !slices.Contains(p.languages, analysis.Language()) {
+		p.languages = append(p.languages, analysis.Language())
+	}
+
+	graph := analysis.Graph()
+	merged := make(map[*Element]*Element, len(graph.Elements))
+	for _, elem := range graph.Elements {
+		id := elem.ID()
+		if existing, ok := p.elements[id]; ok {
+			merged[elem] = existing
+			continue
+		}
+		p.elements[id] = elem
+		merged[elem] = elem
+		p.Structure.Elements = append(p.Structure.Elements, elem)
+	}
+
+	for _, rel := range graph.Relationships {
+		source, target := merged[rel.Source], merged[rel.Target]
+		if source == nil || target == nil {
+			continue // Refers to an element outside the analysis
+		}
+		key := relationshipKey{typ: rel.Type, source: source.ID(), target: target.ID()}
+		if p.relationships[key] {
+			continue
+		}
+		p.relationships[key] = true
+		if source != rel.Source || target != rel.Target {
+			rel = &Relationship{Type: rel.Type, Source: source, Target: target}
+		}
+		p.Structure.Relationships = append(p.Structure.Relationships, rel)
+	}
+	return nil
+}
+
+// Adds a relationship between elements of the project, unless it already exists.
+// Returns whether the relationship was added.
+func (p *Project) Relate(relType RelationType, source, target *Element) bool {
+	key := relationshipKey{typ: relType, source: source.ID(), target: target.ID()}
+	if p.relationships[key] {
+		return false
+	}
+	p.relationships[key] = true
+	p.Structure.Relationships = append(p.Structure.Relationships, &Relationship{Type: relType, Source: source, Target: target})
+	return true
+}
+
+// Returns the element with the given ID, or nil
+func (p *Project) Element(id ElementID) *Element {
+	return p.elements[id]
+}
+
+// Returns the languages of the added analyses, in the order they were added
+func (p *Project) Languages() []string {
+	return slices.Clone(p.languages)
+}
+
+// Returns the languages of the project joined with "+" (e.g. "go+python")
+func (p *Project) Language() string {
+	return strings.Join(p.languages, "+")
+}
+
+// Returns the merged structure
+func (p *Project) Graph() *Structure {
+	return p.Structure
+}
@@ -0,0 +1,227 @@
+package structure_test
+
+import (
+	"testing"
+
+	"codedna/internal/core/analysis/structure"
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// A fixed analysis of one language
+type testAnalysis struct {
+	language string
+	graph    *structure.Structure
+}
+
+func (a *testAnalysis) Language() string            { return a.language }
+func (a *testAnalysis) Graph() *structure.Structure { return a.graph }
+
+// Helper function to build an analysis with a type, a function referencing it and
+// the package containing both
+func newTestAnalysis(t *testing.T, language, file string) *testAnalysis {
+	t.Helper()
+	pkg := &structure.Element{Language: language, Type: structure.ElementPackage, Name: "shapes", Position: ast.Position{Filename: file, Line: 1, Column: 1}}
+	typ := &structure.Element{Language: language, Type: structure.ElementTypeDecl, Name: "Square", Position: ast.Position{Filename: file, Line: 3, Column: 1}}
+	fn := &structure.Element{Language: language, Type: structure.ElementFunction, Name: "Area", Position: ast.Position{Filename: file, Line: 7, Column: 1}}
+	graph := structure.NewStructure()
+	graph.Elements = append(graph.Elements, pkg, typ, fn)
+	graph.Relationships = append(graph.Relationships,
+		&structure.Relationship{Type: structure.RelationContains, Source: pkg, Target: typ},
+		&structure.Relationship{Type: structure.RelationContains, Source: pkg, Target: fn},
+		&structure.Relationship{Type: structure.RelationReferences, Source: fn, Target: typ},
+	)
+	return &testAnalysis{language: language, graph: graph}
+}
+
+func TestElementID(t *testing.T) {
+	tests := []struct {
+		name    string
+		element *structure.Element
+		want    structure.ElementID
+	}{
+		{
+			name:    "ScopedByDirectory",
+			element: &structure.Element{Language: "go", Type: structure.ElementFunction, Name: "Area", Position: ast.Position{Filename: "shapes/area.go", Line: 5}},
+			want:    "go:function:shapes:Area",
+		},
+		{
+			name:    "ExplicitScope",
+			element: &structure.Element{Language: "python", Type: structure.ElementTypeDecl, Name: "Square", Scope: "app/shapes.py", Position: ast.Position{Filename: "app/shapes.py", Line: 3}},
+			want:    "python:type:app/shapes.py:Square",
+		},
+		{
+			name: "Method",
+			element: &structure.Element{Language: "go", Type: structure.ElementMethod, Name: "Save", Scope: "store", Attributes: map[string]any{
+				"receiver_type": &goparser.TypeInfo{Kind: "pointer", ElemType: &goparser.TypeInfo{Kind: "basic", Name: "Store"}},
+			}},
+			want: "go:method:store:*Store.Save",
+		},
+		{
+			name: "GenericMethod",
+			element: &structure.Element{Language: "go", Type: structure.ElementMethod, Name: "Push", Scope: "store", Attributes: map[string]any{
+				"receiver_type": &goparser.TypeInfo{Kind: "pointer", ElemType: &goparser.TypeInfo{Kind: "basic", Name: "Stack", Args: []*goparser.TypeInfo{{Kind: "basic", Name: "T"}}}},
+			}},
+			want: "go:method:store:*Stack.Push",
+		},
+		{
+			name:    "PackagePerFile",
+			element: &structure.Element{Language: "go", Type: structure.ElementPackage, Name: "store", Scope: "store", Position: ast.Position{Filename: "store/db.go", Line: 1}},
+			want:    "go:package:store/db.go:store",
+		},
+		{
+			name:    "Unnamed",
+			element: &structure.Element{Language: "go", Type: structure.ElementTypeDecl, Scope: "store", Position: ast.Position{Filename: "store/db.go", Line: 3, Column: 2}},
+			want:    "go:type:store/db.go:3:2",
+		},
+		{
+			name:    "InitFunction",
+			element: &structure.Element{Language: "go", Type: structure.ElementFunction, Name: "init", Scope: "store", Position: ast.Position{Filename: "store/db.go", Line: 9, Column: 1}},
+			want:    "go:function:store/db.go:init@9:1",
+		},
+		{
+			name:    "BlankIdentifier",
+			element: &structure.Element{Language: "go", Type: structure.ElementVariable, Name: "_", Scope: "store", Position: ast.Position{Filename: "store/db.go", Line: 12, Column: 5}},
+			want:    "go:variable:store/db.go:_@12:5",
+		},
+		{
+			name: "ExternalTestPackage",
+			element: &structure.Element{Language: "go", Type: structure.ElementFunction, Name: "helper", Scope: "store", Position: ast.Position{Filename: "store/db_test.go", Line: 7}, Attributes: map[string]any{
+				"is_test": true, "test_package": "store_test",
+			}},
+			want: "go:function:store#store_test:helper",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.element.ID(); got != tt.want {
+				t.Errorf("Expected ID %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	t.Run("MergesLanguages", func(t *testing.T) {
+		project := structure.NewProject()
+		for _, analysis := range []*testAnalysis{
+			newTestAnalysis(t, "go", "shapes/square.go"),
+			newTestAnalysis(t, "python", "shapes/square.py"),
+		} {
+			if err := project.Add(analysis); err != nil {
+				t.Fatalf("Failed to add %s analysis: %v", analysis.language, err)
+			}
+		}
+
+		if project.Language() != "go+python" {
+			t.Errorf("Expected language go+python, got %s", project.Language())
+		}
+		if len(project.Structure.Elements) != 6 {
+			t.Errorf("Expected 6 elements, got %d", len(project.Structure.Elements))
+		}
+		if len(project.Structure.Relationships) != 6 {
+			t.Errorf("Expected 6 relationships, got %d", len(project.Structure.Relationships))
+		}
+		if elem := project.Element("python:type:shapes:Square"); elem == nil || elem.Language != "python" {
+			t.Errorf("Expected to find the Python type by ID, got %v", elem)
+		}
+	})
+
+	t.Run("DeduplicatesElements", func(t *testing.T) {
+		project := structure.NewProject()
+		first := newTestAnalysis(t, "go", "shapes/square.go")
+		second := newTestAnalysis(t, "go", "shapes/square.go")
+		extra := &structure.Element{Language: "go", Type: structure.ElementFunction, Name: "Perimeter", Position: ast.Position{Filename: "shapes/square.go", Line: 11}}
+		second.graph.Elements = append(second.graph.Elements, extra)
+		second.graph.Relationships = append(second.graph.Relationships,
+			&structure.Relationship{Type: structure.RelationReferences, Source: extra, Target: second.graph.Elements[1]})
+
+		for _, analysis := range []*testAnalysis{first, second} {
+			if err := project.Add(analysis); err != nil {
+				t.Fatalf("Failed to add analysis: %v", err)
+			}
+		}
+
+		if project.Language() != "go" {
+			t.Errorf("Expected language go, got %s", project.Language())
+		}
+		if len(project.Structure.Elements) != 4 {
+			t.Errorf("Expected 4 elements, got %d", len(project.Structure.Elements))
+		}
+		if len(project.Structure.Relationships) != 4 {
+			t.Fatalf("Expected 4 relationships, got %d", len(project.Structure.Relationships))
+		}
+		added := project.Structure.Relationships[3]
+		if added.Source != extra || added.Target != first.graph.Elements[1] {
+			t.Errorf("Expected the relationship to be redirected to the existing type, got %s -> %s", added.Source.ID(), added.Target.ID())
+		}
+	})
+
+	t.Run("KeepsRepeatedDeclarations", func(t *testing.T) {
+		// Several init functions and blank variables in a package, and a helper declared both in a
+		// package and in its external test package, are distinct elements
+		element := func(typ structure.ElementType, name, file string, line int, attributes map[string]any) *structure.Element {
+			return &structure.Element{Language: "go", Type: typ, Name: name, Scope: "store", Position: ast.Position{Filename: file, Line: line, Column: 1}, Attributes: attributes}
+		}
+		test := map[string]any{"is_test": true, "test_package": "store_test"}
+		graph := structure.NewStructure()
+		graph.Elements = append(graph.Elements,
+			element(structure.ElementFunction, "init", "store/db.go", 3, nil),
+			element(structure.ElementFunction, "init", "store/cache.go", 3, nil),
+			element(structure.ElementFunction, "init", "store/cache.go", 9, nil),
+			element(structure.ElementVariable, "_", "store/db.go", 12, nil),
+			element(structure.ElementVariable, "_", "store/db.go", 13, nil),
+			element(structure.ElementFunction, "helper", "store/db.go", 20, nil),
+			element(structure.ElementFunction, "helper", "store/db_test.go", 20, test),
+		)
+		graph.Relationships = append(graph.Relationships,
+			&structure.Relationship{Type: structure.RelationCalls, Source: graph.Elements[6], Target: graph.Elements[5]})
+
+		project := structure.NewProject()
+		if err := project.Add(&testAnalysis{language: "go", graph: graph}); err != nil {
+			t.Fatalf("Failed to add analysis: %v", err)
+		}
+		if len(project.Structure.Elements) != len(graph.Elements) {
+			t.Errorf("Expected %d elements, got %d", len(graph.Elements), len(project.Structure.Elements))
+		}
+		if len(project.Structure.Relationships) != 1 {
+			t.Fatalf("Expected 1 relationship, got %d", len(project.Structure.Relationships))
+		}
+		if rel := project.Structure.Relationships[0]; rel.Source == rel.Target {
+			t.Errorf("Expected the test helper to call the package helper, got a self-call of %s", rel.Source.ID())
+		}
+	})
+
+	t.Run("KeepsGenericMethods", func(t *testing.T) {
+		// Methods of the same name on different generic types are distinct elements
+		graph := structure.NewStructure()
+		for i, receiver := range []string{"*Stack[T]", "*Queue[T]", "Pair[K, V]"} {
+			recv, err := goparser.ParseTypeInfo(receiver)
+			if err != nil {
+				t.Fatalf("Failed to parse receiver %q: %v", receiver, err)
+			}
+			graph.Elements = append(graph.Elements, &structure.Element{
+				Language: "go", Type: structure.ElementMethod, Name: "Push", Scope: "store",
+				Position:   ast.Position{Filename: "store/generic.go", Line: 10 * (i + 1), Column: 1},
+				Attributes: map[string]any{"receiver_type": recv},
+			})
+		}
+
+		project := structure.NewProject()
+		if err := project.Add(&testAnalysis{language: "go", graph: graph}); err != nil {
+			t.Fatalf("Failed to add analysis: %v", err)
+		}
+		if len(project.Structure.Elements) != len(graph.Elements) {
+			t.Errorf("Expected %d elements, got %d", len(graph.Elements), len(project.Structure.Elements))
+		}
+		if elem := project.Element("go:method:store:Pair.Push"); elem != graph.Elements[2] {
+			t.Errorf("Expected to find Pair.Push by ID, got %v", elem)
+		}
+	})
+
+	t.Run("RejectsNil", func(t *testing.T) {
+		if err := structure.NewProject().Add(nil); err == nil {
+			t.Error("Expected error adding nil analysis")
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package structure
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// The type of a code element
+type ElementType string
+
+const (
+	ElementPackage   ElementType = "package" // A Go package file, a Python or TypeScript module, or a .proto file
+	ElementInterface ElementType = "interface"
+	ElementTypeDecl  ElementType = "type" // Structs, classes, aliases and enums
+	ElementFunction  ElementType = "function"
+	ElementMethod    ElementType = "method"
+	ElementVariable  ElementType = "variable"
+)
+
+// The type of relationship between elements
+type RelationType string
+
+const (
+	RelationContains        RelationType = "contains"
+	RelationImplements      RelationType = "implements"
+	RelationEmbeds          RelationType = "embeds"
+	RelationInterfaceEmbeds RelationType = "interface_embeds"
+	RelationMethodReceiver  RelationType = "method_receiver"
+	RelationCalls           RelationType = "calls" // function/method calls
+	RelationReferences      RelationType = "references"
+	RelationExtends         RelationType = "extends"        // class inheritance, in languages that have it
+	RelationRequests        RelationType = "requests"       // an HTTP client call to the handler serving the endpoint
+	RelationSharesShape     RelationType = "shares_shape"   // types of different languages with the same JSON shape
+	RelationGeneratedFrom   RelationType = "generated_from" // generated code to the schema definition it was generated from
+	RelationTests           RelationType = "tests"          // a test to the production code it exercises
+)
+
+// Identifies an element across analyses and languages
+// (e.g. "go:method:internal/store:*Store.Save" or "python:type:app/models.py:User")
+type ElementID string
+
+// A code element in the structure
+type Element struct {
+	Language   string // Language of the analyzer that found the element
+	Type       ElementType
+	Name       string
+	Scope      string       // Package or module declaring the element: a Go package directory, a Python or TypeScript file
+	Position   ast.Position // Location of the element in its source file
+	Attributes map[string]any
+}
+
+// Returns the identifier of the element. Named elements are identified by language, type, scope,
+// receiver type name and name, so moving code within its scope does not change it; unnamed
+// elements (e.g. imports), names declared several times in a scope (init functions and the blank
+// identifier) and package elements, which exist per file, by their location. Elements without a scope are
+// scoped by the directory of their file, and elements of a Go external test package (foo_test)
+// by the directory and the package name.
+func (e *Element) ID() ElementID {
+	scope := e.Scope
+	if scope == "" {
+		scope = filepath.Dir(e.Position.Filename)
+	}
+	name := e.Name
+	switch {
+	case e.Type == ElementPackage:
+		scope = e.Position.Filename
+	case name == "":
+		scope = e.Position.Filename
+		name = fmt.Sprintf("%d:%d", e.Position.Line, e.Position.Column)
+	case e.repeatable():
+		scope = e.Position.Filename
+		name = fmt.Sprintf("%s@%d:%d", name, e.Position.Line, e.Position.Column)
+	default:
+		if pkg, ok := e.Attributes["test_package"].(string); ok {
+			scope += "#" + pkg
+		}
+	}
+	switch receiver := e.Attributes["receiver_type"].(type) {
+	case receiverNamer:
+		name = receiver.ReceiverName() + "." + name
+	case fmt.Stringer:
+		name = receiver.String() + "." + name
+	}
+	return ElementID(strings.Join([]string{e.Language, string(e.Type), filepath.ToSlash(scope), name}, ":"))
+}
+
+// A receiver type naming the type its methods belong to independently of type parameters, so
+// that methods of generic types are identified by the type name (e.g. "*Stack" for "*Stack[T]")
+type receiverNamer interface {
+	ReceiverName() string
+}
+
+// Checks if the element's name may be declared several times in its scope
+func (e *Element) repeatable() bool {
+	return e.Name == "_" || (e.Language == "go" && e.Type == ElementFunction && e.Name == "init")
+}
+
+// A relationship between two elements
+type Relationship struct {
+	Type   RelationType
+	Source *Element
+	Target *Element
+}
+
+// The analyzed code structure
+type Structure struct {
+	Elements      []*Element
+	Relationships []*Relationship
+}
+
+// Creates an empty structure
+func NewStructure() *Structure {
+	return &Structure{
+		Elements:      make([]*Element, 0),
+		Relationships: make([]*Relationship, 0),
+	}
+}
@@ -0,0 +1,195 @@
+package openapistructure
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"codedna/internal/core/analysis/structure"
+	"codedna/internal/core/parser/ast"
+	openapiparser "codedna/internal/core/parser/openapi"
+)
+
+// Implements structural analysis for OpenAPI specifications
+type Analyzer struct {
+	logger *zap.Logger
+}
+
+// Creates a new OpenAPI analyzer
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{logger: zap.NewNop()}
+}
+
+// Sets the logger used to report analysis steps
+func (a *Analyzer) SetLogger(logger *zap.Logger) {
+	a.logger = logger
+}
+
+// Returns the language this analyzer handles
+func (a *Analyzer) Language() string {
+	return "openapi"
+}
+
+// Analyzes the structure of a specification
+func (a *Analyzer) Analyze(node structure.Node) (structure.Analysis, error) {
+	return a.AnalyzeAll([]structure.Node{node})
+}
+
+// Analyzes several specifications into a single analysis. Schema references are resolved
+// within each specification.
+func (a *Analyzer) AnalyzeAll(nodes []structure.Node) (structure.Analysis, error) {
+	analysis := NewAnalysis()
+	b := &builder{
+		analysis:      analysis,
+		relationships: make(map[relationshipKey]bool),
+		schemas:       make(map[*structure.Element]map[string]*structure.Element),
+		modules:       make(map[*structure.Element]*structure.Element),
+	}
+
+	for _, node := range nodes {
+		specNode, ok := node.(*Node)
+		if !ok {
+			return nil, fmt.Errorf("expected OpenAPI node, got %T", node)
+		}
+		if specNode.Type() != string(ast.Module) {
+			return nil, fmt.Errorf("expected OpenAPI specification, got %s", specNode.Type())
+		}
+		b.addModule(specNode.Node)
+	}
+
+	b.detectReferences()
+	a.logger.Debug("Detection finished", zap.String("detector", "references"))
+	return analysis, nil
+}
+
+// Merges another OpenAPI analysis into base
+func (a *Analyzer) Merge(base, other structure.Analysis) error {
+	baseAnalysis, ok1 := base.(*Analysis)
+	otherAnalysis, ok2 := other.(*Analysis)
+	if !ok1 || !ok2 || baseAnalysis == nil || otherAnalysis == nil {
+		return fmt.Errorf("can only merge OpenAPI analyses")
+	}
+	baseAnalysis.Structure.Elements = append(baseAnalysis.Structure.Elements, otherAnalysis.Structure.Elements...)
+	baseAnalysis.Structure.Relationships = append(baseAnalysis.Structure.Relationships, otherAnalysis.Structure.Relationships...)
+	return nil
+}
+
+// Identifies a relationship, to avoid duplicates
+type relationshipKey struct {
+	typ    structure.RelationType
+	source *structure.Element
+	target *structure.Element
+}
+
+// Builds an analysis and the lookups used by detection
+type builder struct {
+	analysis      *Analysis
+	relationships map[relationshipKey]bool
+	schemas       map[*structure.Element]map[string]*structure.Element // Specification -> schema name -> schema
+	modules       map[*structure.Element]*structure.Element            // Element -> containing specification
+}
+
+// Creates elements for a specification and its schemas and operations, contained by it
+func (b *builder) addModule(node ast.Node) {
+	module := b.newElement(node)
+	b.schemas[module] = make(map[string]*structure.Element)
+	for _, child := range node.Children() {
+		elem := b.newElement(child)
+		b.modules[elem] = module
+		if elem.Type == structure.ElementTypeDecl {
+			b.schemas[module][elem.Name] = elem
+		}
+		b.addRelationship(structure.RelationContains, module, elem)
+	}
+}
+
+// Creates the element of a node
+func (b *builder) newElement(node ast.Node) *structure.Element {
+	element := &structure.Element{
+		Language:   "openapi",
+		Type:       mapNodeType(node.Type()),
+		Name:       nodeName(node),
+		Scope:      node.Position().Filename, // Schemas and operations are scoped by their specification
+		Position:   node.Position(),
+		Attributes: node.Attributes(),
+	}
+	b.analysis.Structure.Elements = append(b.analysis.Structure.Elements, element)
+	return element
+}
+
+// Adds a relationship unless it already exists
+func (b *builder) addRelationship(typ structure.RelationType, source, target *structure.Element) {
+	key := relationshipKey{typ: typ, source: source, target: target}
+	if b.relationships[key] {
+		return
+	}
+	b.relationships[key] = true
+	b.analysis.Structure.Relationships = append(b.analysis.Structure.Relationships, &structure.Relationship{
+		Type:   typ,
+		Source: source,
+		Target: target,
+	})
+}
+
+// Maps AST node types to element types
+func mapNodeType(nodeType string) structure.ElementType {
+	switch nodeType {
+	case "Module":
+		return structure.ElementPackage
+	case "Function":
+		return structure.ElementFunction
+	default:
+		return structure.ElementTypeDecl
+	}
+}
+
+// Gets the name from a node's attributes
+func nodeName(node ast.Node) string {
+	if node.Type() == "Module" {
+		if name, ok := node.Attributes()["package_name"].(string); ok {
+			return name
+		}
+	}
+	if name, ok := node.Attributes()["name"].(string); ok {
+		return name
+	}
+	return ""
+}
+
+// Detects extends relationships from schemas to the schemas they include with allOf, and
+// references from schemas to the schemas of their properties and from operations to their
+// request and response schemas
+func (b *builder) detectReferences() {
+	for _, elem := range b.analysis.Structure.Elements {
+		schemas := b.schemas[b.modules[elem]]
+		var types []*openapiparser.TypeInfo
+		switch elem.Type {
+		case structure.ElementTypeDecl:
+			bases, _ := elem.Attributes["bases"].([]*openapiparser.TypeInfo)
+			for _, base := range bases {
+				if target, ok := schemas[base.Name]; ok && target != elem {
+					b.addRelationship(structure.RelationExtends, elem, target)
+				}
+			}
+			fields, _ := elem.Attributes["fields"].([]map[string]any)
+			for _, field := range fields {
+				if typ, ok := field["type"].(*openapiparser.TypeInfo); ok {
+					types = append(types, typ)
+				}
+			}
+		case structure.ElementFunction:
+			if typ, ok := elem.Attributes["request_type"].(*openapiparser.TypeInfo); ok {
+				types = append(types, typ)
+			}
+			responses, _ := elem.Attributes["response_types"].([]*openapiparser.TypeInfo)
+			types = append(types, responses...)
+		}
+		for _, typ := range types {
+			for _, name := range typ.Schemas() {
+				if target, ok := schemas[name]; ok && target != elem {
+					b.addRelationship(structure.RelationReferences, elem, target)
+				}
+			}
+		}
+	}
+}
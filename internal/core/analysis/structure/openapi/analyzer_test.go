@@ -0,0 +1,102 @@
+package openapistructure_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/analysis/structure"
+	openapistructure "codedna/internal/core/analysis/structure/openapi"
+	openapiparser "codedna/internal/core/parser/openapi"
+)
+
+// Helper function to analyze the specifications in testdata/api
+func analyzeTestdata(t *testing.T) *openapistructure.Analysis {
+	t.Helper()
+
+	modules, err := openapiparser.New().ParseDir(filepath.Join("testdata", "api"))
+	if err != nil {
+		t.Fatalf("Failed to parse testdata: %v", err)
+	}
+	nodes := make([]structure.Node, 0, len(modules))
+	for _, module := range modules {
+		nodes = append(nodes, openapistructure.NewNode(module))
+	}
+	analysis, err := openapistructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze testdata: %v", err)
+	}
+	return analysis.(*openapistructure.Analysis)
+}
+
+// Helper function to list relationships of a type as "source->target", with the file of the target
+func relationships(relationships []*structure.Relationship, relType structure.RelationType) []string {
+	var result []string
+	for _, rel := range relationships {
+		if rel.Type == relType {
+			result = append(result, fmt.Sprintf("%s->%s@%s", rel.Source.Name, rel.Target.Name, filepath.Base(rel.Target.Scope)))
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+func TestAnalyzer(t *testing.T) {
+	analysis := analyzeTestdata(t)
+
+	t.Run("Elements", func(t *testing.T) {
+		var got []string
+		for _, elem := range analysis.Structure.Elements {
+			got = append(got, string(elem.ID()))
+		}
+		billing := filepath.ToSlash(filepath.Join("testdata", "api", "billing.yaml"))
+		users := filepath.ToSlash(filepath.Join("testdata", "api", "users.yaml"))
+		expected := []string{
+			"openapi:package:" + billing + ":Billing API",
+			"openapi:type:" + billing + ":Invoice",
+			"openapi:type:" + billing + ":User",
+			"openapi:function:" + billing + ":listInvoices",
+			"openapi:package:" + users + ":Users API",
+			"openapi:type:" + users + ":User",
+			"openapi:type:" + users + ":Address",
+			"openapi:type:" + users + ":Admin",
+			"openapi:type:" + users + ":createUserRequest",
+			"openapi:function:" + users + ":createUser",
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("Expected elements %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Extends", func(t *testing.T) {
+		expected := []string{"Admin->User@users.yaml"}
+		if got := relationships(analysis.Structure.Relationships, structure.RelationExtends); !slices.Equal(got, expected) {
+			t.Errorf("Expected extends relationships %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("References", func(t *testing.T) {
+		// Schemas are resolved within their own specification
+		expected := []string{
+			"Invoice->User@billing.yaml",
+			"User->Address@users.yaml",
+			"createUser->User@users.yaml",
+			"createUser->createUserRequest@users.yaml",
+			"listInvoices->Invoice@billing.yaml",
+		}
+		if got := relationships(analysis.Structure.Relationships, structure.RelationReferences); !slices.Equal(got, expected) {
+			t.Errorf("Expected references %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		base := openapistructure.NewAnalysis()
+		if err := openapistructure.NewAnalyzer().Merge(base, analysis); err != nil {
+			t.Fatalf("Failed to merge analyses: %v", err)
+		}
+		if len(base.Structure.Elements) != len(analysis.Structure.Elements) {
+			t.Errorf("Expected %d merged elements, got %d", len(analysis.Structure.Elements), len(base.Structure.Elements))
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package protostructure
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"codedna/internal/core/analysis/structure"
+	"codedna/internal/core/parser/ast"
+	protoparser "codedna/internal/core/parser/proto"
+)
+
+// Implements structural analysis for .proto files
+type Analyzer struct {
+	logger *zap.Logger
+}
+
+// Creates a new protobuf analyzer
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{logger: zap.NewNop()}
+}
+
+// Sets the logger used to report analysis steps
+func (a *Analyzer) SetLogger(logger *zap.Logger) {
+	a.logger = logger
+}
+
+// Returns the language this analyzer handles
+func (a *Analyzer) Language() string {
+	return "proto"
+}
+
+// Analyzes the structure of a .proto file
+func (a *Analyzer) Analyze(node structure.Node) (structure.Analysis, error) {
+	return a.AnalyzeAll([]structure.Node{node})
+}
+
+// Analyzes several .proto files into a single analysis, so that message types are resolved
+// across files and packages
+func (a *Analyzer) AnalyzeAll(nodes []structure.Node) (structure.Analysis, error) {
+	analysis := NewAnalysis()
+	b := &builder{
+		analysis:      analysis,
+		relationships: make(map[relationshipKey]bool),
+		modules:       make(map[*structure.Element]*structure.Element),
+		definitions:   make(map[string]*structure.Element),
+	}
+
+	for _, node := range nodes {
+		protoNode, ok := node.(*Node)
+		if !ok {
+			return nil, fmt.Errorf("expected protobuf node, got %T", node)
+		}
+		if protoNode.Type() != string(ast.Module) {
+			return nil, fmt.Errorf("expected protobuf file, got %s", protoNode.Type())
+		}
+		b.addNode(protoNode.Node, nil, nil)
+	}
+
+	b.detectReferences()
+	a.logger.Debug("Detection finished", zap.String("detector", "references"))
+	return analysis, nil
+}
+
+// Merges another protobuf analysis into base
+func (a *Analyzer) Merge(base, other structure.Analysis) error {
+	baseAnalysis, ok1 := base.(*Analysis)
+	otherAnalysis, ok2 := other.(*Analysis)
+	if !ok1 || !ok2 || baseAnalysis == nil || otherAnalysis == nil {
+		return fmt.Errorf("can only merge protobuf analyses")
+	}
+	baseAnalysis.Structure.Elements = append(baseAnalysis.Structure.Elements, otherAnalysis.Structure.Elements...)
+	baseAnalysis.Structure.Relationships = append(baseAnalysis.Structure.Relationships, otherAnalysis.Structure.Relationships...)
+	return nil
+}
+
+// Identifies a relationship, to avoid duplicates
+type relationshipKey struct {
+	typ    structure.RelationType
+	source *structure.Element
+	target *structure.Element
+}
+
+// Builds an analysis and the lookups used by detection
+type builder struct {
+	analysis      *Analysis
+	relationships map[relationshipKey]bool
+	modules       map[*structure.Element]*structure.Element // Element -> containing file
+	definitions   map[string]*structure.Element             // Full name -> message or enum
+}
+
+// Creates elements for a node and its children. Definitions are contained by their file;
+// RPCs are also linked to their service with a method_receiver relationship.
+func (b *builder) addNode(node ast.Node, module, service *structure.Element) {
+	element := &structure.Element{
+		Language:   "proto",
+		Type:       mapNodeType(node.Type()),
+		Name:       nodeName(node),
+		Scope:      node.Position().Filename, // Definitions are scoped by their file
+		Position:   node.Position(),
+		Attributes: node.Attributes(),
+	}
+	b.analysis.Structure.Elements = append(b.analysis.Structure.Elements, element)
+	if element.Type == structure.ElementTypeDecl && element.Name != "" {
+		b.definitions[element.Attributes["full_name"].(string)] = element
+	}
+
+	if module != nil {
+		b.modules[element] = module
+		b.addRelationship(structure.RelationContains, module, element)
+	}
+	if service != nil {
+		b.addRelationship(structure.RelationMethodReceiver, element, service)
+	}
+
+	for _, child := range node.Children() {
+		if element.Type == structure.ElementPackage {
+			b.addNode(child, element, nil)
+		} else {
+			b.addNode(child, module, element)
+		}
+	}
+}
+
+// Adds a relationship unless it already exists
+func (b *builder) addRelationship(typ structure.RelationType, source, target *structure.Element) {
+	key := relationshipKey{typ: typ, source: source, target: target}
+	if b.relationships[key] {
+		return
+	}
+	b.relationships[key] = true
+	b.analysis.Structure.Relationships = append(b.analysis.Structure.Relationships, &structure.Relationship{
+		Type:   typ,
+		Source: source,
+		Target: target,
+	})
+}
+
+// Maps AST node types to element types
+func mapNodeType(nodeType string) structure.ElementType {
+	switch nodeType {
+	case "Module":
+		return structure.ElementPackage
+	case "Interface":
+		return structure.ElementInterface
+	case "Method":
+		return structure.ElementMethod
+	default:
+		// Messages, enums, and imports which are kept as elements carrying their path
+		return structure.ElementTypeDecl
+	}
+}
+
+// Gets the name from a node's attributes
+func nodeName(node ast.Node) string {
+	if node.Type() == "Module" {
+		if name, ok := node.Attributes()["package_name"].(string); ok {
+			return name
+		}
+	}
+	if name, ok := node.Attributes()["name"].(string); ok {
+		return name
+	}
+	return ""
+}
+
+// Resolves a type name used in a message or service to the message or enum it refers to,
+// following protobuf scoping: names are looked up from the innermost enclosing scope outwards,
+// and names starting with a dot are fully qualified. Returns nil for types outside the analysis.
+func (b *builder) resolve(typ *protoparser.TypeInfo, scope string) *structure.Element {
+	if name, ok := strings.CutPrefix(typ.Name, "."); ok {
+		return b.definitions[name]
+	}
+	for {
+		candidate := typ.Name
+		if scope != "" {
+			candidate = scope + "." + typ.Name
+		}
+		if elem, ok := b.definitions[candidate]; ok {
+			return elem
+		}
+		if scope == "" {
+			return nil
+		}
+		i := strings.LastIndex(scope, ".")
+		scope = scope[:max(i, 0)]
+	}
+}
+
+// Detects references from messages to the messages and enums of their fields, and from RPCs
+// to their request and response messages
+func (b *builder) detectReferences() {
+	for _, elem := range b.analysis.Structure.Elements {
+		fullName, _ := elem.Attributes["full_name"].(string)
+		var types []*protoparser.TypeInfo
+		switch elem.Attributes["kind"] {
+		case protoparser.KindMessage:
+			fields, _ := elem.Attributes["fields"].([]map[string]any)
+			for _, field := range fields {
+				typ, _ := field["type"].(*protoparser.TypeInfo)
+				types = append(types, typ.Messages()...)
+			}
+		case protoparser.KindRPC:
+			// RPC types are looked up from the service's package
+			fullName = fullName[:max(strings.LastIndex(fullName, "."), 0)]
+			for _, key := range []string{"request_type", "response_type"} {
+				if typ, ok := elem.Attributes[key].(*protoparser.TypeInfo); ok {
+					types = append(types, typ)
+				}
+			}
+		}
+		for _, typ := range types {
+			if target := b.resolve(typ, fullName); target != nil && target != elem {
+				b.addRelationship(structure.RelationReferences, elem, target)
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package protostructure_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	protostructure "codedna/internal/core/analysis/structure/proto"
+	protoparser "codedna/internal/core/parser/proto"
+)
+
+// Helper function to analyze the .proto files in testdata/api
+func analyzeTestdata(t *testing.T) *protostructure.Analysis {
+	t.Helper()
+
+	modules, err := protoparser.New().ParseDir(filepath.Join("testdata", "api"))
+	if err != nil {
+		t.Fatalf("Failed to parse testdata: %v", err)
+	}
+	nodes := make([]structure.Node, 0, len(modules))
+	for _, module := range modules {
+		nodes = append(nodes, protostructure.NewNode(module))
+	}
+	analysis, err := protostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze testdata: %v", err)
+	}
+	return analysis.(*protostructure.Analysis)
+}
+
+// Helper function to list relationships of a type as "source->target"
+func relationships(relationships []*structure.Relationship, relType structure.RelationType) []string {
+	var result []string
+	for _, rel := range relationships {
+		if rel.Type == relType {
+			result = append(result, fmt.Sprintf("%s->%s", rel.Source.Name, rel.Target.Name))
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+func TestAnalyzer(t *testing.T) {
+	analysis := analyzeTestdata(t)
+
+	t.Run("Elements", func(t *testing.T) {
+		var got []string
+		for _, elem := range analysis.Structure.Elements {
+			got = append(got, string(elem.ID()))
+		}
+		file := filepath.ToSlash(filepath.Join("testdata", "api", "users.proto"))
+		expected := []string{
+			"proto:package:" + file + ":acme.users.v1",
+			"proto:type:" + file + ":User",
+			"proto:type:" + file + ":User.Address",
+			"proto:type:" + file + ":Status",
+			"proto:type:" + file + ":GetUserRequest",
+			"proto:type:" + file + ":ListUsersRequest",
+			"proto:interface:" + file + ":Users",
+			"proto:method:" + file + ":Users.GetUser",
+			"proto:method:" + file + ":Users.ListUsers",
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("Expected elements %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Receivers", func(t *testing.T) {
+		expected := []string{"GetUser->Users", "ListUsers->Users"}
+		if got := relationships(analysis.Structure.Relationships, structure.RelationMethodReceiver); !slices.Equal(got, expected) {
+			t.Errorf("Expected method_receiver relationships %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("References", func(t *testing.T) {
+		expected := []string{
+			"GetUser->GetUserRequest",
+			"GetUser->User",
+			"ListUsers->ListUsersRequest",
+			"ListUsers->User",
+			"User->Status",
+			"User->User.Address",
+		}
+		if got := relationships(analysis.Structure.Relationships, structure.RelationReferences); !slices.Equal(got, expected) {
+			t.Errorf("Expected references %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("RejectsOtherLanguages", func(t *testing.T) {
+		if _, err := protostructure.NewAnalyzer().Analyze(&gostructure.Node{}); err == nil {
+			t.Error("Expected an error for a Go node")
+		}
+	})
+}
+
+func TestLink(t *testing.T) {
+	project := structure.NewProject()
+	goAnalysis, err := gostructure.NewWorkspace(gostructure.NewAnalyzer()).Update([]string{
+		filepath.Join("testdata", "gen", "usersv1"),
+		filepath.Join("testdata", "server"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to analyze Go code: %v", err)
+	}
+	for _, analysis := range []structure.Analysis{goAnalysis, analyzeTestdata(t)} {
+		if err := project.Add(analysis); err != nil {
+			t.Fatalf("Failed to add %s analysis: %v", analysis.Language(), err)
+		}
+	}
+
+	bindings := protostructure.Link(project)
+	got := make(map[string][]string)
+	for _, binding := range bindings {
+		var names []string
+		for _, elem := range binding.Generated {
+			names = append(names, elem.Name)
+		}
+		for _, elem := range binding.Implementations {
+			names = append(names, "impl:"+elem.Name)
+		}
+		got[binding.Definition.Name] = names
+	}
+
+	expected := map[string][]string{
+		"acme.users.v1":    {"file_users_proto_rawDesc"},
+		"User":             {"User", "Reset", "GetUserId"},
+		"User.Address":     {"User_Address"},
+		"Status":           {"Status", "Status_STATUS_UNSPECIFIED", "Status_STATUS_ACTIVE", "Status_name"},
+		"GetUserRequest":   {"GetUserRequest"},
+		"ListUsersRequest": {"ListUsersRequest"},
+		"Users": {"UsersClient", "usersClient", "NewUsersClient", "GetUser", "UsersServer", "UnimplementedUsersServer",
+			"GetUser", "ListUsers", "RegisterUsersServer", "impl:usersServer", "impl:fakeUsers"},
+		"GetUser":   {"_Users_GetUser_Handler", "impl:GetUser", "impl:GetUser"},
+		"ListUsers": {"Users_ListUsersServer", "impl:ListUsers"},
+	}
+	if len(got) != len(expected) {
+		t.Errorf("Expected %d bindings, got %v", len(expected), got)
+	}
+	for name, elements := range expected {
+		if !slices.Equal(got[name], elements) {
+			t.Errorf("%s: expected %v, got %v", name, elements, got[name])
+		}
+	}
+
+	implements := relationships(project.Structure.Relationships, structure.RelationImplements)
+	for _, rel := range []string{"usersServer->Users", "fakeUsers->Users", "GetUser->GetUser", "ListUsers->ListUsers"} {
+		if !slices.Contains(implements, rel) {
+			t.Errorf("Expected implements relationship %s, got %v", rel, implements)
+		}
+	}
+	if slices.Contains(implements, "partial->Users") {
+		t.Error("Expected partial not to implement Users")
+	}
+	if generated := relationships(project.Structure.Relationships, structure.RelationGeneratedFrom); !slices.Contains(generated, "User_Address->User.Address") {
+		t.Errorf("Expected User_Address to be generated from User.Address, got %v", generated)
+	}
+}
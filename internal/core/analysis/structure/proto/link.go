@@ -0,0 +1,262 @@
+package protostructure
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"codedna/internal/core/analysis/structure"
+	goparser "codedna/internal/core/parser/golang"
+	protoparser "codedna/internal/core/parser/proto"
+)
+
+// A proto definition and the Go code generated from or implementing it
+type Binding struct {
+	Definition      *structure.Element   // A .proto file, message, enum, service or RPC
+	Generated       []*structure.Element // Go elements of the *.pb.go files generated from the definition
+	Implementations []*structure.Element // Go types implementing a service, or methods implementing an RPC
+}
+
+// Links the Go code of a project to the proto definitions it was generated from or implements.
+// Elements of a *.pb.go file are related to the definitions of the .proto file with the same
+// name (users.pb.go and users_grpc.pb.go to users.proto) with generated_from relationships;
+// elements that belong to no single definition (e.g. file descriptors) to the file itself. Go
+// types that embed a service's Unimplemented<Service>Server, or have a method for each of its
+// RPCs, are related to the service, and their methods to the RPCs, with implements
+// relationships. Returns the bindings in definition order.
+func Link(project *structure.Project) []*Binding {
+	l := &linker{project: project, bindings: make(map[*structure.Element]*Binding)}
+	l.index()
+	l.linkGenerated()
+	l.linkImplementations()
+
+	result := make([]*Binding, 0)
+	for _, elem := range project.Structure.Elements {
+		if binding, ok := l.bindings[elem]; ok {
+			result = append(result, binding)
+		}
+	}
+	return result
+}
+
+// The definitions of one .proto file, by generated Go name
+type protoFile struct {
+	module    *structure.Element
+	goPackage string                        // The Go package name from the go_package option, if any
+	names     map[string]*structure.Element // Generated Go name prefix -> definition
+}
+
+// Links Go elements to proto definitions
+type linker struct {
+	project  *structure.Project
+	files    map[string][]*protoFile                     // File name without .proto -> files
+	services []*structure.Element                        // Proto services
+	rpcs     map[*structure.Element][]*structure.Element // Service -> its RPCs
+	packages map[string]string                           // Go package directory -> package name
+	bindings map[*structure.Element]*Binding
+}
+
+// Indexes the proto definitions by the names of the Go code generated from them
+func (l *linker) index() {
+	l.files = make(map[string][]*protoFile)
+	l.rpcs = make(map[*structure.Element][]*structure.Element)
+	l.packages = make(map[string]string)
+	byModule := make(map[*structure.Element]*protoFile)
+
+	for _, elem := range l.project.Structure.Elements {
+		switch {
+		case elem.Language == "go" && elem.Type == structure.ElementPackage:
+			l.packages[elem.Scope] = elem.Name
+		case elem.Language == "proto" && elem.Type == structure.ElementPackage:
+			goPackage, _ := elem.Attributes["go_package"].(string)
+			file := &protoFile{module: elem, goPackage: goPackage, names: make(map[string]*structure.Element)}
+			byModule[elem] = file
+			base := strings.TrimSuffix(filepath.Base(elem.Position.Filename), ".proto")
+			l.files[base] = append(l.files[base], file)
+		}
+	}
+
+	for _, rel := range l.project.Structure.Relationships {
+		switch {
+		case rel.Type == structure.RelationContains && byModule[rel.Source] != nil:
+			file, elem := byModule[rel.Source], rel.Target
+			switch elem.Attributes["kind"] {
+			case protoparser.KindMessage, protoparser.KindEnum:
+				// Nested definitions are generated with underscores (e.g. User_Address)
+				file.names[strings.ReplaceAll(elem.Name, ".", "_")] = elem
+			case protoparser.KindService:
+				l.services = append(l.services, elem)
+				for _, name := range []string{"", "New", "Register", "Unimplemented", "Unsafe"} {
+					file.names[name+elem.Name] = elem
+				}
+			}
+		case rel.Type == structure.RelationMethodReceiver && rel.Target.Language == "proto":
+			l.rpcs[rel.Target] = append(l.rpcs[rel.Target], rel.Source)
+		}
+	}
+
+	// RPCs name their handlers and stream types (e.g. _Users_GetUser_Handler, Users_ListUsersServer)
+	for _, files := range l.files {
+		for _, file := range files {
+			for _, service := range l.services {
+				if file.names[service.Name] != service {
+					continue
+				}
+				for _, rpc := range l.rpcs[service] {
+					file.names[service.Name+"_"+rpc.Name] = rpc
+					file.names[service.Name+rpc.Name] = rpc
+				}
+			}
+		}
+	}
+}
+
+// Relates the elements of generated Go files to their definitions
+func (l *linker) linkGenerated() {
+	for _, elem := range l.project.Structure.Elements {
+		// Packages and imports (unnamed) are not generated from a definition
+		if elem.Language != "go" || elem.Type == structure.ElementPackage || elem.Name == "" || !isGeneratedFile(elem.Position.Filename) {
+			continue
+		}
+		file := l.generatingFile(elem)
+		if file == nil {
+			continue
+		}
+		name := elem.Name
+		if recv, ok := elem.Attributes["receiver_type"].(*goparser.TypeInfo); ok {
+			name = receiverName(recv)
+		}
+		definition := file.module
+		if match := file.match(name); match != nil {
+			definition = match
+		}
+		l.project.Relate(structure.RelationGeneratedFrom, elem, definition)
+		l.binding(definition).Generated = append(l.binding(definition).Generated, elem)
+	}
+}
+
+// Checks if a Go file was generated by protoc-gen-go or protoc-gen-go-grpc
+func isGeneratedFile(filename string) bool {
+	return strings.HasSuffix(filename, ".pb.go")
+}
+
+// Returns the .proto file a generated Go element was generated from, or nil
+func (l *linker) generatingFile(elem *structure.Element) *protoFile {
+	base := filepath.Base(elem.Position.Filename)
+	base = strings.TrimSuffix(strings.TrimSuffix(base, ".pb.go"), "_grpc")
+	for _, file := range l.files[base] {
+		if file.goPackage == "" || file.goPackage == l.packages[elem.Scope] {
+			return file
+		}
+	}
+	return nil
+}
+
+// Returns the definition whose generated name is the longest prefix of a Go name, ending at
+// an underscore or a new word (e.g. Users for UsersClient, but not User), or nil
+func (f *protoFile) match(name string) *structure.Element {
+	// Unexported generated names (e.g. usersClient, _Users_GetUser_Handler) start like exported ones
+	name = strings.TrimPrefix(name, "_")
+	if r, size := utf8.DecodeRuneInString(name); size > 0 {
+		name = string(unicode.ToUpper(r)) + name[size:]
+	}
+
+	var best *structure.Element
+	bestLength := 0
+	for prefix, definition := range f.names {
+		if len(prefix) <= bestLength || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if rest := name[len(prefix):]; rest == "" || rest[0] == '_' || unicode.IsUpper(rune(rest[0])) {
+			best, bestLength = definition, len(prefix)
+		}
+	}
+	return best
+}
+
+// Relates the Go types implementing a service, and their methods to its RPCs
+func (l *linker) linkImplementations() {
+	methods := make(map[string]map[string]*structure.Element) // Go package directory + type -> method name -> method
+	var types []*structure.Element
+	for _, elem := range l.project.Structure.Elements {
+		// Generated code (e.g. mocks of a server) does not implement the service
+		if elem.Language != "go" || elem.IsGenerated() {
+			continue
+		}
+		switch elem.Type {
+		case structure.ElementTypeDecl:
+			if elem.Name != "" {
+				types = append(types, elem)
+			}
+		case structure.ElementMethod:
+			if recv, ok := elem.Attributes["receiver_type"].(*goparser.TypeInfo); ok {
+				key := elem.Scope + "." + receiverName(recv)
+				if methods[key] == nil {
+					methods[key] = make(map[string]*structure.Element)
+				}
+				methods[key][elem.Name] = elem
+			}
+		}
+	}
+
+	for _, service := range l.services {
+		rpcs := l.rpcs[service]
+		if len(rpcs) == 0 {
+			continue
+		}
+		for _, typ := range types {
+			own := methods[typ.Scope+"."+typ.Name]
+			if !embeds(typ, "Unimplemented"+service.Name+"Server") && !hasAll(own, rpcs) {
+				continue
+			}
+			l.project.Relate(structure.RelationImplements, typ, service)
+			l.binding(service).Implementations = append(l.binding(service).Implementations, typ)
+			for _, rpc := range rpcs {
+				if method, ok := own[rpc.Name]; ok {
+					l.project.Relate(structure.RelationImplements, method, rpc)
+					l.binding(rpc).Implementations = append(l.binding(rpc).Implementations, method)
+				}
+			}
+		}
+	}
+}
+
+// Returns the binding of a definition, creating it if needed
+func (l *linker) binding(definition *structure.Element) *Binding {
+	binding, ok := l.bindings[definition]
+	if !ok {
+		binding = &Binding{Definition: definition}
+		l.bindings[definition] = binding
+	}
+	return binding
+}
+
+// Returns the name of a receiver type without its pointer (e.g. "User" for *User)
+func receiverName(recv *goparser.TypeInfo) string {
+	if recv.Kind == "pointer" && recv.ElemType != nil {
+		recv = recv.ElemType
+	}
+	return recv.Name
+}
+
+// Checks if a Go struct embeds a type of the given name, from any package
+func embeds(typ *structure.Element, name string) bool {
+	fields, _ := typ.Attributes["fields"].([]map[string]any)
+	for _, field := range fields {
+		if embedded, _ := field["embedded"].(bool); embedded && field["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if there is a method for each RPC
+func hasAll(methods map[string]*structure.Element, rpcs []*structure.Element) bool {
+	for _, rpc := range rpcs {
+		if _, ok := methods[rpc.Name]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+// Package protostructure provides Protocol Buffers schema analysis, linking generated and
+// implementing Go code back to the proto definitions
+package protostructure
+
+import (
+	"codedna/internal/core/analysis/structure"
+	"codedna/internal/core/parser/ast"
+)
+
+// Node wraps an AST node with protobuf-specific functionality
+type Node struct {
+	ast.Node
+}
+
+// Returns the programming language of this node
+func (n *Node) Language() string {
+	return "proto"
+}
+
+// Creates a new protobuf node
+func NewNode(node ast.Node) *Node {
+	return &Node{Node: node}
+}
+
+// The results of protobuf schema analysis. Elements and relationships use the
+// language-neutral structure model, so queries, diffs and reports work across languages.
+type Analysis struct {
+	language  string
+	Structure *structure.Structure
+}
+
+// Creates a new analysis result
+func NewAnalysis() *Analysis {
+	return &Analysis{
+		language:  "proto",
+		Structure: structure.NewStructure(),
+	}
+}
+
+// Returns the programming language that was analyzed
+func (a *Analysis) Language() string {
+	return a.language
+}
+
+// Returns the analyzed structure
+func (a *Analysis) Graph() *structure.Structure {
+	return a.Structure
+}
@@ -0,0 +1,40 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: users.proto
+
+package usersv1
+
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED Status = 0
+	Status_STATUS_ACTIVE      Status = 1
+)
+
+var Status_name = map[int32]string{0: "STATUS_UNSPECIFIED", 1: "STATUS_ACTIVE"}
+
+type User struct {
+	UserId   string        `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FullName string        `protobuf:"bytes,2,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	Address  *User_Address `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Status   Status        `protobuf:"varint,4,opt,name=status,proto3,enum=acme.users.v1.Status" json:"status,omitempty"`
+}
+
+func (x *User) Reset() {}
+
+func (x *User) GetUserId() string {
+	return x.UserId
+}
+
+type User_Address struct {
+	City string `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+}
+
+type GetUserRequest struct {
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+}
+
+type ListUsersRequest struct {
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+}
+
+var file_users_proto_rawDesc = []byte{}
@@ -0,0 +1,44 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package usersv1
+
+import "context"
+
+type UsersClient interface {
+	GetUser(ctx context.Context, in *GetUserRequest) (*User, error)
+}
+
+type usersClient struct{}
+
+func NewUsersClient() UsersClient {
+	return &usersClient{}
+}
+
+func (c *usersClient) GetUser(ctx context.Context, in *GetUserRequest) (*User, error) {
+	return nil, nil
+}
+
+type UsersServer interface {
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	ListUsers(*ListUsersRequest, Users_ListUsersServer) error
+}
+
+type UnimplementedUsersServer struct{}
+
+func (UnimplementedUsersServer) GetUser(context.Context, *GetUserRequest) (*User, error) {
+	return nil, nil
+}
+
+func (UnimplementedUsersServer) ListUsers(*ListUsersRequest, Users_ListUsersServer) error {
+	return nil
+}
+
+type Users_ListUsersServer interface {
+	Send(*User) error
+}
+
+func RegisterUsersServer(s any, srv UsersServer) {}
+
+func _Users_GetUser_Handler(srv any) (any, error) {
+	return nil, nil
+}
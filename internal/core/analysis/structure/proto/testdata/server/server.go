@@ -0,0 +1,34 @@
+package server
+
+import (
+	"context"
+
+	"example.com/acme/gen/usersv1"
+)
+
+// Serves the Users API from the database
+type usersServer struct {
+	usersv1.UnimplementedUsersServer
+}
+
+func (s *usersServer) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.User, error) {
+	return &usersv1.User{UserId: req.UserId}, nil
+}
+
+// Serves the Users API from a fixed list, without embedding
+type fakeUsers struct{}
+
+func (fakeUsers) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.User, error) {
+	return nil, nil
+}
+
+func (fakeUsers) ListUsers(req *usersv1.ListUsersRequest, stream usersv1.Users_ListUsersServer) error {
+	return nil
+}
+
+// Not a Users server: ListUsers is missing
+type partial struct{}
+
+func (partial) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.User, error) {
+	return nil, nil
+}
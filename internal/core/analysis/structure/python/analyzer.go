@@ -0,0 +1,436 @@
+package pystructure
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"codedna/internal/core/analysis/structure"
+	"codedna/internal/core/parser/ast"
+	pyparser "codedna/internal/core/parser/python"
+)
+
+// Implements structural analysis for Python code
+type Analyzer struct {
+	logger *zap.Logger
+}
+
+// Creates a new Python analyzer
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{logger: zap.NewNop()}
+}
+
+// Sets the logger used to report analysis steps
+func (a *Analyzer) SetLogger(logger *zap.Logger) {
+	a.logger = logger
+}
+
+// Returns the language this analyzer handles
+func (a *Analyzer) Language() string {
+	return "python"
+}
+
+// Analyzes the structure of a Python module
+func (a *Analyzer) Analyze(node structure.Node) (structure.Analysis, error) {
+	return a.AnalyzeAll([]structure.Node{node})
+}
+
+// Analyzes several Python modules (e.g. all files of a project) into a single analysis,
+// so that inheritance and protocol relationships are found across modules
+func (a *Analyzer) AnalyzeAll(nodes []structure.Node) (structure.Analysis, error) {
+	analysis := NewAnalysis()
+	b := &builder{
+		analysis:      analysis,
+		relationships: make(map[relationshipKey]bool),
+		modules:       make(map[*structure.Element]*structure.Element),
+	}
+
+	for _, node := range nodes {
+		pyNode, ok := node.(*Node)
+		if !ok {
+			return nil, fmt.Errorf("expected Python node, got %T", node)
+		}
+		if pyNode.Type() != string(ast.Module) {
+			return nil, fmt.Errorf("expected Python module, got %s", pyNode.Type())
+		}
+		b.addNode(pyNode.Node, nil, nil)
+	}
+
+	b.index()
+	steps := []struct {
+		name   string
+		detect func()
+	}{
+		{"inheritance", b.detectInheritance},
+		{"error_types", b.detectErrorTypes},
+		{"implementations", b.detectImplementations},
+		{"references", b.detectReferences},
+	}
+	for _, step := range steps {
+		step.detect()
+		a.logger.Debug("Detection finished", zap.String("detector", step.name))
+	}
+	return analysis, nil
+}
+
+// Merges another Python analysis into base
+func (a *Analyzer) Merge(base, other structure.Analysis) error {
+	baseAnalysis, ok1 := base.(*Analysis)
+	otherAnalysis, ok2 := other.(*Analysis)
+	if !ok1 || !ok2 || baseAnalysis == nil || otherAnalysis == nil {
+		return fmt.Errorf("can only merge Python analyses")
+	}
+	baseAnalysis.Structure.Elements = append(baseAnalysis.Structure.Elements, otherAnalysis.Structure.Elements...)
+	baseAnalysis.Structure.Relationships = append(baseAnalysis.Structure.Relationships, otherAnalysis.Structure.Relationships...)
+	return nil
+}
+
+// Identifies a relationship, to avoid duplicates
+type relationshipKey struct {
+	typ    structure.RelationType
+	source *structure.Element
+	target *structure.Element
+}
+
+// Builds an analysis and the lookups used by detection
+type builder struct {
+	analysis      *Analysis
+	relationships map[relationshipKey]bool
+	modules       map[*structure.Element]*structure.Element   // Element -> containing module
+	classes       map[string][]*structure.Element             // Class name -> classes and protocols
+	imports       map[*structure.Element]map[string]imported  // Module -> local name -> imported name
+	methods       map[*structure.Element][]*structure.Element // Class -> its own methods
+}
+
+// A name bound by a from-import
+type imported struct {
+	module string // Imported module path
+	name   string // Name in the imported module
+}
+
+// Creates elements for a node and its children. Module-level elements are contained by
+// their module; methods are also linked to their class with a method_receiver relationship.
+func (b *builder) addNode(node ast.Node, module, class *structure.Element) {
+	element := &structure.Element{
+		Language:   "python",
+		Type:       mapNodeType(node.Type()),
+		Name:       nodeName(node),
+		Scope:      node.Position().Filename, // Modules are files
+		Position:   node.Position(),
+		Attributes: node.Attributes(),
+	}
+	b.analysis.Structure.Elements = append(b.analysis.Structure.Elements, element)
+
+	if module != nil {
+		b.modules[element] = module
+		b.addRelationship(structure.RelationContains, module, element)
+	}
+	if class != nil && element.Type == structure.ElementMethod {
+		b.addRelationship(structure.RelationMethodReceiver, element, class)
+	}
+
+	for _, child := range node.Children() {
+		if element.Type == structure.ElementPackage {
+			b.addNode(child, element, nil)
+		} else {
+			b.addNode(child, module, element)
+		}
+	}
+}
+
+// Adds a relationship unless it already exists
+func (b *builder) addRelationship(typ structure.RelationType, source, target *structure.Element) {
+	key := relationshipKey{typ: typ, source: source, target: target}
+	if b.relationships[key] {
+		return
+	}
+	b.relationships[key] = true
+	b.analysis.Structure.Relationships = append(b.analysis.Structure.Relationships, &structure.Relationship{
+		Type:   typ,
+		Source: source,
+		Target: target,
+	})
+}
+
+// Maps AST node types to element types
+func mapNodeType(nodeType string) structure.ElementType {
+	switch nodeType {
+	case "Module":
+		return structure.ElementPackage
+	case "Interface":
+		return structure.ElementInterface
+	case "Function":
+		return structure.ElementFunction
+	case "Method":
+		return structure.ElementMethod
+	case "Variable":
+		return structure.ElementVariable
+	default:
+		// Classes, and imports which are kept as elements carrying their path
+		return structure.ElementTypeDecl
+	}
+}
+
+// Gets the name from a node's attributes
+func nodeName(node ast.Node) string {
+	if node.Type() == "Module" {
+		if name, ok := node.Attributes()["package_name"].(string); ok {
+			return name
+		}
+	}
+	if name, ok := node.Attributes()["name"].(string); ok {
+		return name
+	}
+	return ""
+}
+
+// Checks if an element is a class or protocol
+func isClass(elem *structure.Element) bool {
+	return (elem.Type == structure.ElementTypeDecl || elem.Type == structure.ElementInterface) && elem.Name != ""
+}
+
+// Indexes classes, methods and from-imports for name resolution
+func (b *builder) index() {
+	b.classes = make(map[string][]*structure.Element)
+	b.imports = make(map[*structure.Element]map[string]imported)
+	b.methods = make(map[*structure.Element][]*structure.Element)
+
+	for _, elem := range b.analysis.Structure.Elements {
+		if isClass(elem) {
+			b.classes[elem.Name] = append(b.classes[elem.Name], elem)
+		}
+		names, ok := elem.Attributes["names"].([]string)
+		if !ok {
+			continue
+		}
+		aliases, _ := elem.Attributes["aliases"].([]string)
+		path, _ := elem.Attributes["path"].(string)
+		module := b.modules[elem]
+		if b.imports[module] == nil {
+			b.imports[module] = make(map[string]imported)
+		}
+		for i, name := range names {
+			local := name
+			if i < len(aliases) && aliases[i] != "" {
+				local = aliases[i]
+			}
+			b.imports[module][local] = imported{module: path, name: name}
+		}
+	}
+	for _, rel := range b.analysis.Structure.Relationships {
+		if rel.Type == structure.RelationMethodReceiver {
+			b.methods[rel.Target] = append(b.methods[rel.Target], rel.Source)
+		}
+	}
+}
+
+// Resolves a class name used in a module (e.g. Base, models.Base or an imported alias)
+// to the class it refers to, or nil if it is not part of the analysis or is ambiguous
+func (b *builder) resolve(module *structure.Element, name string) *structure.Element {
+	qualifier := ""
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		qualifier, name = name[:i], name[i+1:]
+	}
+
+	if qualifier == "" {
+		// Classes defined in the same module shadow imports
+		for _, class := range b.classes[name] {
+			if b.modules[class] == module {
+				return class
+			}
+		}
+		if imp, ok := b.imports[module][name]; ok {
+			name, qualifier = imp.name, imp.module
+		}
+	}
+
+	candidates := b.classes[name]
+	if qualifier != "" {
+		moduleName := qualifier[strings.LastIndex(qualifier, ".")+1:]
+		for _, class := range candidates {
+			if owner := b.modules[class]; owner != nil && owner.Name == moduleName {
+				return class
+			}
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// Detects extends relationships between classes, implements relationships from classes to
+// the protocols they derive from, and interface_embeds relationships between protocols
+func (b *builder) detectInheritance() {
+	for _, class := range b.analysis.Structure.Elements {
+		bases, ok := class.Attributes["bases"].([]*pyparser.TypeInfo)
+		if !ok || !isClass(class) {
+			continue
+		}
+		for _, base := range bases {
+			target := b.resolve(b.modules[class], base.Name)
+			if target == nil || target == class {
+				continue
+			}
+			switch {
+			case class.Type == structure.ElementInterface && target.Type == structure.ElementInterface:
+				b.addRelationship(structure.RelationInterfaceEmbeds, class, target)
+			case target.Type == structure.ElementInterface:
+				b.addRelationship(structure.RelationImplements, class, target)
+			default:
+				b.addRelationship(structure.RelationExtends, class, target)
+			}
+		}
+	}
+}
+
+// Returns the classes a class extends, directly or indirectly
+func (b *builder) ancestors(class *structure.Element) []*structure.Element {
+	var result []*structure.Element
+	seen := map[*structure.Element]bool{class: true}
+	queue := []*structure.Element{class}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, rel := range b.analysis.Structure.Relationships {
+			if rel.Source == current && (rel.Type == structure.RelationExtends || rel.Type == structure.RelationInterfaceEmbeds) && !seen[rel.Target] {
+				seen[rel.Target] = true
+				result = append(result, rel.Target)
+				queue = append(queue, rel.Target)
+			}
+		}
+	}
+	return result
+}
+
+// Built-in exception classes that do not follow the Error/Exception naming
+var builtinExceptions = map[string]bool{
+	"BaseException": true, "Exception": true, "KeyboardInterrupt": true, "SystemExit": true,
+	"StopIteration": true, "StopAsyncIteration": true, "GeneratorExit": true,
+}
+
+// Flags classes deriving from an exception class, directly or through other classes
+func (b *builder) detectErrorTypes() {
+	for _, class := range b.analysis.Structure.Elements {
+		if class.Type != structure.ElementTypeDecl || class.Name == "" {
+			continue
+		}
+		isError := false
+		for _, c := range append([]*structure.Element{class}, b.ancestors(class)...) {
+			bases, _ := c.Attributes["bases"].([]*pyparser.TypeInfo)
+			for _, base := range bases {
+				name := base.BaseName()
+				if builtinExceptions[name] || strings.HasSuffix(name, "Error") || strings.HasSuffix(name, "Exception") {
+					isError = true
+				}
+			}
+		}
+		class.Attributes["is_error_type"] = isError
+	}
+}
+
+// Returns the methods of a class, including inherited ones, by name
+func (b *builder) classMethods(class *structure.Element) map[string]*structure.Element {
+	methods := make(map[string]*structure.Element)
+	// Ancestors first, so overrides win
+	ancestors := b.ancestors(class)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		for _, m := range b.methods[ancestors[i]] {
+			methods[m.Name] = m
+		}
+	}
+	for _, m := range b.methods[class] {
+		methods[m.Name] = m
+	}
+	return methods
+}
+
+// Returns the method signatures of a protocol, including those of the protocols it extends
+func (b *builder) protocolMethods(protocol *structure.Element) []map[string]any {
+	var methods []map[string]any
+	for _, p := range append([]*structure.Element{protocol}, b.ancestors(protocol)...) {
+		if own, ok := p.Attributes["methods"].([]map[string]any); ok {
+			methods = append(methods, own...)
+		}
+	}
+	return methods
+}
+
+// Detects classes that structurally satisfy a protocol: they have every protocol method,
+// taking the same number of parameters
+func (b *builder) detectImplementations() {
+	var protocols, classes []*structure.Element
+	for _, elem := range b.analysis.Structure.Elements {
+		switch {
+		case elem.Type == structure.ElementInterface:
+			protocols = append(protocols, elem)
+		case isClass(elem):
+			classes = append(classes, elem)
+		}
+	}
+
+	for _, protocol := range protocols {
+		required := b.protocolMethods(protocol)
+		if len(required) == 0 {
+			continue
+		}
+		for _, class := range classes {
+			methods := b.classMethods(class)
+			if satisfies(methods, required) {
+				b.addRelationship(structure.RelationImplements, class, protocol)
+			}
+		}
+	}
+}
+
+// Checks if a set of methods satisfies the required protocol methods
+func satisfies(methods map[string]*structure.Element, required []map[string]any) bool {
+	for _, req := range required {
+		method, ok := methods[req["name"].(string)]
+		if !ok {
+			return false
+		}
+		want := len(req["signature"].(map[string]any)["param_names"].([]string))
+		signature, _ := method.Attributes["signature"].(map[string]any)
+		if names, _ := signature["param_names"].([]string); len(names) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Detects references from functions, methods, classes and variables to the classes
+// named in their annotations
+func (b *builder) detectReferences() {
+	for _, elem := range b.analysis.Structure.Elements {
+		var types []*pyparser.TypeInfo
+		switch elem.Type {
+		case structure.ElementFunction, structure.ElementMethod:
+			if signature, ok := elem.Attributes["signature"].(map[string]any); ok {
+				params, _ := signature["params"].([]*pyparser.TypeInfo)
+				returns, _ := signature["returns"].([]*pyparser.TypeInfo)
+				types = append(append(types, params...), returns...)
+			}
+		case structure.ElementTypeDecl, structure.ElementInterface:
+			fields, _ := elem.Attributes["fields"].([]map[string]any)
+			for _, field := range fields {
+				if typ, ok := field["type"].(*pyparser.TypeInfo); ok {
+					types = append(types, typ)
+				}
+			}
+		case structure.ElementVariable:
+			if typ, ok := elem.Attributes["type"].(*pyparser.TypeInfo); ok {
+				types = append(types, typ)
+			}
+		}
+
+		for _, typ := range types {
+			for _, name := range typ.Names() {
+				if target := b.resolve(b.modules[elem], name); target != nil && target != elem {
+					b.addRelationship(structure.RelationReferences, elem, target)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package pystructure_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	pystructure "codedna/internal/core/analysis/structure/python"
+	pyparser "codedna/internal/core/parser/python"
+)
+
+// Helper function to analyze the Python modules in testdata
+func analyzeTestdata(t *testing.T) *pystructure.Analysis {
+	t.Helper()
+
+	modules, err := pyparser.New().ParseDir("testdata")
+	if err != nil {
+		t.Fatalf("Failed to parse testdata: %v", err)
+	}
+	nodes := make([]structure.Node, 0, len(modules))
+	for _, module := range modules {
+		nodes = append(nodes, pystructure.NewNode(module))
+	}
+	analysis, err := pystructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze testdata: %v", err)
+	}
+	return analysis.(*pystructure.Analysis)
+}
+
+// Helper function to list relationships of a type as "source->target"
+func relationships(analysis *pystructure.Analysis, relType structure.RelationType) []string {
+	var result []string
+	for _, rel := range analysis.Structure.Relationships {
+		if rel.Type == relType {
+			result = append(result, fmt.Sprintf("%s->%s", rel.Source.Name, rel.Target.Name))
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Helper function to find an element by type and name
+func findElement(t *testing.T, analysis *pystructure.Analysis, elemType structure.ElementType, name string) *structure.Element {
+	t.Helper()
+	for _, elem := range analysis.Structure.Elements {
+		if elem.Type == elemType && elem.Name == name {
+			return elem
+		}
+	}
+	t.Fatalf("%s %s not found", elemType, name)
+	return nil
+}
+
+func TestAnalyzer(t *testing.T) {
+	analysis := analyzeTestdata(t)
+
+	if analysis.Language() != "python" {
+		t.Errorf("Expected language python, got %s", analysis.Language())
+	}
+
+	t.Run("Elements", func(t *testing.T) {
+		counts := make(map[structure.ElementType]int)
+		for _, elem := range analysis.Structure.Elements {
+			if elem.Name != "" {
+				counts[elem.Type]++
+			}
+		}
+		want := map[structure.ElementType]int{
+			structure.ElementPackage:   2,
+			structure.ElementInterface: 2,
+			structure.ElementTypeDecl:  7,
+			structure.ElementMethod:    7,
+			structure.ElementFunction:  1,
+			structure.ElementVariable:  1,
+		}
+		for elemType, n := range want {
+			if counts[elemType] != n {
+				t.Errorf("Expected %d %s elements, got %d", n, elemType, counts[elemType])
+			}
+		}
+		if path := filepath.Base(findElement(t, analysis, structure.ElementTypeDecl, "Big").Position.Filename); path != "app.py" {
+			t.Errorf("Expected Big in app.py, got %s", path)
+		}
+	})
+
+	t.Run("Contains", func(t *testing.T) {
+		contains := relationships(analysis, structure.RelationContains)
+		for _, want := range []string{"shapes->Square", "shapes->area", "app->render", "app->DEFAULT"} {
+			if !slices.Contains(contains, want) {
+				t.Errorf("Expected contains %s", want)
+			}
+		}
+	})
+
+	t.Run("MethodReceivers", func(t *testing.T) {
+		receivers := relationships(analysis, structure.RelationMethodReceiver)
+		want := []string{"__init__->Canvas", "__init__->Square", "area->Base", "area->Circle", "area->Square", "draw->Circle", "draw->Square"}
+		if len(receivers) != 7 || !slices.Equal(receivers, want) {
+			t.Errorf("Expected receivers %v, got %v", want, receivers)
+		}
+	})
+
+	t.Run("Extends", func(t *testing.T) {
+		want := []string{"Big->Square", "NegativeSide->ShapeError", "Square->Base"}
+		if got := relationships(analysis, structure.RelationExtends); !slices.Equal(got, want) {
+			t.Errorf("Expected extends %v, got %v", want, got)
+		}
+	})
+
+	t.Run("InterfaceEmbeds", func(t *testing.T) {
+		want := []string{"Drawable->Shape"}
+		if got := relationships(analysis, structure.RelationInterfaceEmbeds); !slices.Equal(got, want) {
+			t.Errorf("Expected interface embeds %v, got %v", want, got)
+		}
+	})
+
+	t.Run("Implements", func(t *testing.T) {
+		// Circle.draw takes no canvas, so it only satisfies Shape
+		want := []string{"Base->Shape", "Big->Drawable", "Big->Shape", "Circle->Shape", "Square->Drawable", "Square->Shape"}
+		if got := relationships(analysis, structure.RelationImplements); !slices.Equal(got, want) {
+			t.Errorf("Expected implements %v, got %v", want, got)
+		}
+	})
+
+	t.Run("ErrorTypes", func(t *testing.T) {
+		for name, want := range map[string]bool{"ShapeError": true, "NegativeSide": true, "Square": false} {
+			if got := findElement(t, analysis, structure.ElementTypeDecl, name).Attributes["is_error_type"]; got != want {
+				t.Errorf("Expected %s is_error_type %v, got %v", name, want, got)
+			}
+		}
+	})
+
+	t.Run("References", func(t *testing.T) {
+		references := relationships(analysis, structure.RelationReferences)
+		for _, want := range []string{"render->Shape", "render->Canvas", "render->Square", "DEFAULT->Shape", "draw->Canvas"} {
+			if !slices.Contains(references, want) {
+				t.Errorf("Expected reference %s, got %v", want, references)
+			}
+		}
+	})
+}
+
+func TestAnalyzer_RejectsOtherNodes(t *testing.T) {
+	if _, err := pystructure.NewAnalyzer().Analyze(&gostructure.Node{}); err == nil {
+		t.Error("Expected error for a non-Python node")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	analyzer := pystructure.NewAnalyzer()
+	base := analyzeTestdata(t)
+	other := analyzeTestdata(t)
+	elements := len(base.Structure.Elements) + len(other.Structure.Elements)
+
+	if err := analyzer.Merge(base, other); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+	if len(base.Structure.Elements) != elements {
+		t.Errorf("Expected %d elements, got %d", elements, len(base.Structure.Elements))
+	}
+	if err := analyzer.Merge(base, nil); err == nil {
+		t.Error("Expected error merging nil analysis")
+	}
+}
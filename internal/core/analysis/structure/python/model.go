@@ -0,0 +1,47 @@
+// Package pystructure provides Python-specific code structure analysis
+package pystructure
+
+import (
+	"codedna/internal/core/analysis/structure"
+	"codedna/internal/core/parser/ast"
+)
+
+// Node wraps an AST node with Python-specific functionality
+type Node struct {
+	ast.Node
+}
+
+// Returns the programming language of this node
+func (n *Node) Language() string {
+	return "python"
+}
+
+// Creates a new Python node
+func NewNode(node ast.Node) *Node {
+	return &Node{Node: node}
+}
+
+// The results of Python code structure analysis. Elements and relationships use the
+// language-neutral structure model, so queries, diffs and reports work across languages.
+type Analysis struct {
+	language  string
+	Structure *structure.Structure
+}
+
+// Creates a new analysis result
+func NewAnalysis() *Analysis {
+	return &Analysis{
+		language:  "python",
+		Structure: structure.NewStructure(),
+	}
+}
+
+// Returns the programming language that was analyzed
+func (a *Analysis) Language() string {
+	return a.language
+}
+
+// Returns the analyzed structure
+func (a *Analysis) Graph() *structure.Structure {
+	return a.Structure
+}
@@ -0,0 +1,12 @@
+from shapes import Square as Sq, Shape
+
+
+class Big(Sq):
+    pass
+
+
+def render(shape: Shape, canvas: "shapes.Canvas") -> list[Sq]:
+    return []
+
+
+DEFAULT: Shape = Big(10)
@@ -0,0 +1,47 @@
+from abc import ABC, abstractmethod
+from typing import Protocol
+
+
+class Shape(Protocol):
+    def area(self) -> float: ...
+
+
+class Drawable(Shape, Protocol):
+    def draw(self, canvas: "Canvas") -> None: ...
+
+
+class Canvas:
+    def __init__(self, width: int) -> None:
+        self.width = width
+
+
+class Base(ABC):
+    @abstractmethod
+    def area(self) -> float: ...
+
+
+class Square(Base):
+    def __init__(self, side: float) -> None:
+        self.side = side
+
+    def area(self) -> float:
+        return self.side * self.side
+
+    def draw(self, canvas: Canvas) -> None:
+        pass
+
+
+class Circle:
+    def area(self) -> float:
+        return 3.14
+
+    def draw(self) -> None:
+        pass
+
+
+class ShapeError(ValueError):
+    pass
+
+
+class NegativeSide(ShapeError):
+    pass
@@ -0,0 +1,7 @@
+package structure
+
+// Checks if the element was found in a test file
+func (e *Element) IsTest() bool {
+	test, _ := e.Attributes["is_test"].(bool)
+	return test
+}
@@ -0,0 +1,418 @@
+package tsstructure
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"codedna/internal/core/analysis/structure"
+	"codedna/internal/core/parser/ast"
+	tsparser "codedna/internal/core/parser/typescript"
+)
+
+// Implements structural analysis for TypeScript and JavaScript code
+type Analyzer struct {
+	logger *zap.Logger
+}
+
+// Creates a new TypeScript analyzer
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{logger: zap.NewNop()}
+}
+
+// Sets the logger used to report analysis steps
+func (a *Analyzer) SetLogger(logger *zap.Logger) {
+	a.logger = logger
+}
+
+// Returns the language this analyzer handles
+func (a *Analyzer) Language() string {
+	return "typescript"
+}
+
+// Analyzes the structure of a TypeScript module
+func (a *Analyzer) Analyze(node structure.Node) (structure.Analysis, error) {
+	return a.AnalyzeAll([]structure.Node{node})
+}
+
+// Analyzes several TypeScript modules (e.g. all files of a project) into a single analysis,
+// so that inheritance and references are found across modules
+func (a *Analyzer) AnalyzeAll(nodes []structure.Node) (structure.Analysis, error) {
+	analysis := NewAnalysis()
+	b := &builder{
+		analysis:      analysis,
+		relationships: make(map[relationshipKey]bool),
+		modules:       make(map[*structure.Element]*structure.Element),
+	}
+
+	for _, node := range nodes {
+		tsNode, ok := node.(*Node)
+		if !ok {
+			return nil, fmt.Errorf("expected TypeScript node, got %T", node)
+		}
+		if tsNode.Type() != string(ast.Module) {
+			return nil, fmt.Errorf("expected TypeScript module, got %s", tsNode.Type())
+		}
+		b.addNode(tsNode.Node, nil, nil)
+	}
+
+	b.index()
+	steps := []struct {
+		name   string
+		detect func()
+	}{
+		{"inheritance", b.detectInheritance},
+		{"error_types", b.detectErrorTypes},
+		{"references", b.detectReferences},
+	}
+	for _, step := range steps {
+		step.detect()
+		a.logger.Debug("Detection finished", zap.String("detector", step.name))
+	}
+	return analysis, nil
+}
+
+// Merges another TypeScript analysis into base
+func (a *Analyzer) Merge(base, other structure.Analysis) error {
+	baseAnalysis, ok1 := base.(*Analysis)
+	otherAnalysis, ok2 := other.(*Analysis)
+	if !ok1 || !ok2 || baseAnalysis == nil || otherAnalysis == nil {
+		return fmt.Errorf("can only merge TypeScript analyses")
+	}
+	baseAnalysis.Structure.Elements = append(baseAnalysis.Structure.Elements, otherAnalysis.Structure.Elements...)
+	baseAnalysis.Structure.Relationships = append(baseAnalysis.Structure.Relationships, otherAnalysis.Structure.Relationships...)
+	return nil
+}
+
+// Identifies a relationship, to avoid duplicates
+type relationshipKey struct {
+	typ    structure.RelationType
+	source *structure.Element
+	target *structure.Element
+}
+
+// Builds an analysis and the lookups used by detection
+type builder struct {
+	analysis      *Analysis
+	relationships map[relationshipKey]bool
+	modules       map[*structure.Element]*structure.Element  // Element -> containing module
+	types         map[string][]*structure.Element            // Type name -> classes, interfaces, aliases and enums
+	imports       map[*structure.Element]map[string]imported // Module -> local name -> imported name
+	owners        map[*structure.Element]*structure.Element  // Method -> its class
+	defaults      map[string]*structure.Element              // Module name -> default exported type
+}
+
+// A name bound by an import
+type imported struct {
+	module string // Imported module path
+	name   string // Name in the imported module; empty for namespace imports
+}
+
+// Creates elements for a node and its children. Module-level elements are contained by
+// their module; methods are also linked to their class with a method_receiver relationship.
+func (b *builder) addNode(node ast.Node, module, class *structure.Element) {
+	element := &structure.Element{
+		Language:   "typescript",
+		Type:       mapNodeType(node.Type()),
+		Name:       nodeName(node),
+		Scope:      node.Position().Filename, // Modules are files
+		Position:   node.Position(),
+		Attributes: node.Attributes(),
+	}
+	b.analysis.Structure.Elements = append(b.analysis.Structure.Elements, element)
+
+	if module != nil {
+		b.modules[element] = module
+		b.addRelationship(structure.RelationContains, module, element)
+	}
+	if class != nil && element.Type == structure.ElementMethod {
+		b.addRelationship(structure.RelationMethodReceiver, element, class)
+	}
+
+	for _, child := range node.Children() {
+		if element.Type == structure.ElementPackage {
+			b.addNode(child, element, nil)
+		} else {
+			b.addNode(child, module, element)
+		}
+	}
+}
+
+// Adds a relationship unless it already exists
+func (b *builder) addRelationship(typ structure.RelationType, source, target *structure.Element) {
+	key := relationshipKey{typ: typ, source: source, target: target}
+	if b.relationships[key] {
+		return
+	}
+	b.relationships[key] = true
+	b.analysis.Structure.Relationships = append(b.analysis.Structure.Relationships, &structure.Relationship{
+		Type:   typ,
+		Source: source,
+		Target: target,
+	})
+}
+
+// Maps AST node types to element types
+func mapNodeType(nodeType string) structure.ElementType {
+	switch nodeType {
+	case "Module":
+		return structure.ElementPackage
+	case "Interface":
+		return structure.ElementInterface
+	case "Function":
+		return structure.ElementFunction
+	case "Method":
+		return structure.ElementMethod
+	case "Variable":
+		return structure.ElementVariable
+	default:
+		// Classes, aliases and enums, and imports which are kept as elements carrying their path
+		return structure.ElementTypeDecl
+	}
+}
+
+// Gets the name from a node's attributes
+func nodeName(node ast.Node) string {
+	if node.Type() == "Module" {
+		if name, ok := node.Attributes()["package_name"].(string); ok {
+			return name
+		}
+	}
+	if name, ok := node.Attributes()["name"].(string); ok {
+		return name
+	}
+	return ""
+}
+
+// Checks if an element declares a type (class, interface, alias or enum)
+func isType(elem *structure.Element) bool {
+	return (elem.Type == structure.ElementTypeDecl || elem.Type == structure.ElementInterface) && elem.Name != ""
+}
+
+// Checks if an element is a class
+func isClass(elem *structure.Element) bool {
+	return elem.Type == structure.ElementTypeDecl && elem.Attributes["kind"] == tsparser.KindClass
+}
+
+// Returns the module name an import path refers to (e.g. "shapes" for "./models/shapes")
+func moduleName(importPath string) string {
+	name := path.Base(importPath)
+	for _, ext := range []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+// Indexes types, methods and imports for name resolution
+func (b *builder) index() {
+	b.types = make(map[string][]*structure.Element)
+	b.imports = make(map[*structure.Element]map[string]imported)
+	b.owners = make(map[*structure.Element]*structure.Element)
+	b.defaults = make(map[string]*structure.Element)
+
+	for _, elem := range b.analysis.Structure.Elements {
+		if isType(elem) {
+			b.types[elem.Name] = append(b.types[elem.Name], elem)
+			if elem.Attributes["is_default_export"] == true {
+				b.defaults[b.modules[elem].Name] = elem
+			}
+		}
+		importPath, ok := elem.Attributes["path"].(string)
+		if !ok || elem.Name != "" {
+			continue
+		}
+		module := b.modules[elem]
+		if b.imports[module] == nil {
+			b.imports[module] = make(map[string]imported)
+		}
+		if alias, _ := elem.Attributes["alias"].(string); alias != "" {
+			b.imports[module][alias] = imported{module: importPath}
+		}
+		names, _ := elem.Attributes["names"].([]string)
+		aliases, _ := elem.Attributes["aliases"].([]string)
+		for i, name := range names {
+			local := name
+			if i < len(aliases) && aliases[i] != "" {
+				local = aliases[i]
+			}
+			b.imports[module][local] = imported{module: importPath, name: name}
+		}
+	}
+	for _, rel := range b.analysis.Structure.Relationships {
+		if rel.Type == structure.RelationMethodReceiver {
+			b.owners[rel.Source] = rel.Target
+		}
+	}
+}
+
+// Resolves a type name used in a module (e.g. Shape, models.Shape or an imported alias)
+// to the type it refers to, or nil if it is not part of the analysis or is ambiguous
+func (b *builder) resolve(module *structure.Element, name string) *structure.Element {
+	qualifier := ""
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		qualifier, name = name[:i], name[i+1:]
+	}
+
+	if qualifier == "" {
+		// Types declared in the same module shadow imports
+		for _, typ := range b.types[name] {
+			if b.modules[typ] == module {
+				return typ
+			}
+		}
+		if imp, ok := b.imports[module][name]; ok {
+			if imp.name == "default" {
+				return b.defaults[moduleName(imp.module)]
+			}
+			name, qualifier = imp.name, imp.module
+		}
+	} else if imp, ok := b.imports[module][qualifier]; ok && imp.name == "" {
+		qualifier = imp.module // Namespace import
+	}
+
+	candidates := b.types[name]
+	if qualifier != "" {
+		owner := moduleName(qualifier)
+		for _, typ := range candidates {
+			if m := b.modules[typ]; m != nil && m.Name == owner {
+				return typ
+			}
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// Detects extends relationships between classes, implements relationships from classes to the
+// interfaces they name, and interface_embeds relationships between interfaces. Like Go, TypeScript
+// types interfaces structurally, but only declared implementations are reported: structural matches
+// against small interfaces are mostly coincidental.
+func (b *builder) detectInheritance() {
+	for _, elem := range b.analysis.Structure.Elements {
+		if !isType(elem) {
+			continue
+		}
+		module := b.modules[elem]
+		bases, _ := elem.Attributes["bases"].([]*tsparser.TypeInfo)
+		for _, base := range bases {
+			target := b.resolve(module, base.Name)
+			if target == nil || target == elem {
+				continue
+			}
+			switch {
+			case elem.Type == structure.ElementInterface && target.Type == structure.ElementInterface:
+				b.addRelationship(structure.RelationInterfaceEmbeds, elem, target)
+			case target.Type == structure.ElementInterface:
+				b.addRelationship(structure.RelationImplements, elem, target)
+			default:
+				b.addRelationship(structure.RelationExtends, elem, target)
+			}
+		}
+		implements, _ := elem.Attributes["implements"].([]*tsparser.TypeInfo)
+		for _, iface := range implements {
+			if target := b.resolve(module, iface.Name); target != nil && target != elem {
+				b.addRelationship(structure.RelationImplements, elem, target)
+			}
+		}
+	}
+}
+
+// Returns the classes a class extends, directly or indirectly
+func (b *builder) ancestors(class *structure.Element) []*structure.Element {
+	var result []*structure.Element
+	seen := map[*structure.Element]bool{class: true}
+	queue := []*structure.Element{class}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, rel := range b.analysis.Structure.Relationships {
+			if rel.Source == current && rel.Type == structure.RelationExtends && !seen[rel.Target] {
+				seen[rel.Target] = true
+				result = append(result, rel.Target)
+				queue = append(queue, rel.Target)
+			}
+		}
+	}
+	return result
+}
+
+// Flags classes deriving from Error, directly or through other classes
+func (b *builder) detectErrorTypes() {
+	for _, class := range b.analysis.Structure.Elements {
+		if !isClass(class) {
+			continue
+		}
+		isError := false
+		for _, c := range append([]*structure.Element{class}, b.ancestors(class)...) {
+			bases, _ := c.Attributes["bases"].([]*tsparser.TypeInfo)
+			for _, base := range bases {
+				if name := base.BaseName(); name == "Error" || strings.HasSuffix(name, "Error") || strings.HasSuffix(name, "Exception") {
+					isError = true
+				}
+			}
+		}
+		class.Attributes["is_error_type"] = isError
+	}
+}
+
+// Returns the types of a signature's parameters and results
+func signatureTypes(signature map[string]any) []*tsparser.TypeInfo {
+	params, _ := signature["params"].([]*tsparser.TypeInfo)
+	returns, _ := signature["returns"].([]*tsparser.TypeInfo)
+	return append(slices.Clone(params), returns...)
+}
+
+// Detects references from functions, methods, types and variables to the types named in
+// their annotations. Type parameters shadow types of the same name.
+func (b *builder) detectReferences() {
+	for _, elem := range b.analysis.Structure.Elements {
+		var types []*tsparser.TypeInfo
+		switch elem.Type {
+		case structure.ElementFunction, structure.ElementMethod:
+			if signature, ok := elem.Attributes["signature"].(map[string]any); ok {
+				types = signatureTypes(signature)
+			}
+		case structure.ElementTypeDecl, structure.ElementInterface:
+			fields, _ := elem.Attributes["fields"].([]map[string]any)
+			for _, field := range fields {
+				if typ, ok := field["type"].(*tsparser.TypeInfo); ok {
+					types = append(types, typ)
+				}
+			}
+			methods, _ := elem.Attributes["methods"].([]map[string]any)
+			for _, method := range methods {
+				if signature, ok := method["signature"].(map[string]any); ok {
+					types = append(types, signatureTypes(signature)...)
+				}
+			}
+			if aliased, ok := elem.Attributes["aliased"].(*tsparser.TypeInfo); ok {
+				types = append(types, aliased)
+			}
+		case structure.ElementVariable:
+			if typ, ok := elem.Attributes["type"].(*tsparser.TypeInfo); ok {
+				types = append(types, typ)
+			}
+		}
+
+		typeParams, _ := elem.Attributes["type_params"].([]string)
+		if owner := b.owners[elem]; owner != nil {
+			classParams, _ := owner.Attributes["type_params"].([]string)
+			typeParams = append(slices.Clone(typeParams), classParams...)
+		}
+		for _, typ := range types {
+			for _, name := range typ.Names() {
+				if slices.Contains(typeParams, name) {
+					continue
+				}
+				if target := b.resolve(b.modules[elem], name); target != nil && target != elem {
+					b.addRelationship(structure.RelationReferences, elem, target)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package tsstructure_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	tsstructure "codedna/internal/core/analysis/structure/typescript"
+	tsparser "codedna/internal/core/parser/typescript"
+)
+
+// Helper function to analyze the TypeScript modules in testdata
+func analyzeTestdata(t *testing.T) *tsstructure.Analysis {
+	t.Helper()
+
+	modules, err := tsparser.New().ParseDir("testdata")
+	if err != nil {
+		t.Fatalf("Failed to parse testdata: %v", err)
+	}
+	nodes := make([]structure.Node, 0, len(modules))
+	for _, module := range modules {
+		nodes = append(nodes, tsstructure.NewNode(module))
+	}
+	analysis, err := tsstructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze testdata: %v", err)
+	}
+	return analysis.(*tsstructure.Analysis)
+}
+
+// Helper function to list relationships of a type as "source->target"
+func relationships(analysis *tsstructure.Analysis, relType structure.RelationType) []string {
+	var result []string
+	for _, rel := range analysis.Structure.Relationships {
+		if rel.Type == relType {
+			result = append(result, fmt.Sprintf("%s->%s", rel.Source.Name, rel.Target.Name))
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Helper function to find an element by type and name
+func findElement(t *testing.T, analysis *tsstructure.Analysis, elemType structure.ElementType, name string) *structure.Element {
+	t.Helper()
+	for _, elem := range analysis.Structure.Elements {
+		if elem.Type == elemType && elem.Name == name {
+			return elem
+		}
+	}
+	t.Fatalf("%s %s not found", elemType, name)
+	return nil
+}
+
+func TestAnalyzer(t *testing.T) {
+	analysis := analyzeTestdata(t)
+
+	if analysis.Language() != "typescript" {
+		t.Errorf("Expected language typescript, got %s", analysis.Language())
+	}
+
+	t.Run("Elements", func(t *testing.T) {
+		counts := make(map[structure.ElementType]int)
+		for _, elem := range analysis.Structure.Elements {
+			if elem.Name != "" {
+				counts[elem.Type]++
+			}
+		}
+		want := map[structure.ElementType]int{
+			structure.ElementPackage:   2,
+			structure.ElementInterface: 2,
+			structure.ElementTypeDecl:  9,
+			structure.ElementMethod:    5,
+			structure.ElementFunction:  1,
+			structure.ElementVariable:  2,
+		}
+		for elemType, n := range want {
+			if counts[elemType] != n {
+				t.Errorf("Expected %d %s elements, got %d", n, elemType, counts[elemType])
+			}
+		}
+	})
+
+	t.Run("Contains", func(t *testing.T) {
+		contains := relationships(analysis, structure.RelationContains)
+		for _, want := range []string{"shapes->Square", "shapes->Named", "app->render", "app->DEFAULT"} {
+			if !slices.Contains(contains, want) {
+				t.Errorf("Expected contains %s", want)
+			}
+		}
+	})
+
+	t.Run("MethodReceivers", func(t *testing.T) {
+		want := []string{"area->Base", "area->Circle", "area->Square", "constructor->Square", "draw->Square"}
+		if got := relationships(analysis, structure.RelationMethodReceiver); !slices.Equal(got, want) {
+			t.Errorf("Expected receivers %v, got %v", want, got)
+		}
+	})
+
+	t.Run("Extends", func(t *testing.T) {
+		want := []string{"Big->Square", "NegativeSide->ShapeError", "Square->Base"}
+		if got := relationships(analysis, structure.RelationExtends); !slices.Equal(got, want) {
+			t.Errorf("Expected extends %v, got %v", want, got)
+		}
+	})
+
+	t.Run("InterfaceEmbeds", func(t *testing.T) {
+		want := []string{"Drawable->Shape"}
+		if got := relationships(analysis, structure.RelationInterfaceEmbeds); !slices.Equal(got, want) {
+			t.Errorf("Expected interface embeds %v, got %v", want, got)
+		}
+	})
+
+	t.Run("Implements", func(t *testing.T) {
+		want := []string{"Base->Shape", "Circle->Shape", "Square->Drawable"}
+		if got := relationships(analysis, structure.RelationImplements); !slices.Equal(got, want) {
+			t.Errorf("Expected implements %v, got %v", want, got)
+		}
+	})
+
+	t.Run("ErrorTypes", func(t *testing.T) {
+		for name, want := range map[string]bool{"ShapeError": true, "NegativeSide": true, "Square": false} {
+			if got := findElement(t, analysis, structure.ElementTypeDecl, name).Attributes["is_error_type"]; got != want {
+				t.Errorf("Expected %s is_error_type %v, got %v", name, want, got)
+			}
+		}
+	})
+
+	t.Run("References", func(t *testing.T) {
+		references := relationships(analysis, structure.RelationReferences)
+		for _, want := range []string{
+			"render->Shape", "render->Canvas", "render->Square", "DEFAULT->Shape", "registry->Registry",
+			"Canvas->Shape", "Drawable->Canvas", "draw->Canvas", "Registry->Shape",
+		} {
+			if !slices.Contains(references, want) {
+				t.Errorf("Expected reference %s, got %v", want, references)
+			}
+		}
+		for _, unwanted := range []string{"render->T", "Named->T"} {
+			if slices.Contains(references, unwanted) {
+				t.Errorf("Expected no reference %s to a type parameter", unwanted)
+			}
+		}
+	})
+}
+
+func TestAnalyzer_RejectsOtherNodes(t *testing.T) {
+	if _, err := tsstructure.NewAnalyzer().Analyze(&gostructure.Node{}); err == nil {
+		t.Error("Expected error for a non-TypeScript node")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	analyzer := tsstructure.NewAnalyzer()
+	base := analyzeTestdata(t)
+	other := analyzeTestdata(t)
+	elements := len(base.Structure.Elements) + len(other.Structure.Elements)
+
+	if err := analyzer.Merge(base, other); err != nil {
+		t.Fatalf("Failed to merge: %v", err)
+	}
+	if len(base.Structure.Elements) != elements {
+		t.Errorf("Expected %d elements, got %d", elements, len(base.Structure.Elements))
+	}
+	if err := analyzer.Merge(base, nil); err == nil {
+		t.Error("Expected error merging nil analysis")
+	}
+}
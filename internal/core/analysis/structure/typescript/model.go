@@ -0,0 +1,47 @@
+// Package tsstructure provides TypeScript- and JavaScript-specific code structure analysis
+package tsstructure
+
+import (
+	"codedna/internal/core/analysis/structure"
+	"codedna/internal/core/parser/ast"
+)
+
+// Node wraps an AST node with TypeScript-specific functionality
+type Node struct {
+	ast.Node
+}
+
+// Returns the programming language of this node
+func (n *Node) Language() string {
+	return "typescript"
+}
+
+// Creates a new TypeScript node
+func NewNode(node ast.Node) *Node {
+	return &Node{Node: node}
+}
+
+// The results of TypeScript code structure analysis. Elements and relationships use the
+// language-neutral structure model, so queries, diffs and reports work on all of them.
+type Analysis struct {
+	language  string
+	Structure *structure.Structure
+}
+
+// Creates a new analysis result
+func NewAnalysis() *Analysis {
+	return &Analysis{
+		language:  "typescript",
+		Structure: structure.NewStructure(),
+	}
+}
+
+// Returns the programming language that was analyzed
+func (a *Analysis) Language() string {
+	return a.language
+}
+
+// Returns the analyzed structure
+func (a *Analysis) Graph() *structure.Structure {
+	return a.Structure
+}
@@ -0,0 +1,11 @@
+import Registry, { Square as Sq, Shape } from "./shapes";
+import * as shapes from "./shapes";
+
+export class Big extends Sq {}
+
+export function render<T extends Shape>(shape: Shape, canvas: shapes.Canvas, item: T): Sq[] {
+  return [];
+}
+
+export const DEFAULT: Shape = new Big(10);
+export const registry = new Registry();
@@ -0,0 +1,42 @@
+export interface Shape {
+  area(): number;
+}
+
+export interface Drawable extends Shape {
+  draw(canvas: Canvas): void;
+}
+
+export class Canvas {
+  shapes: Shape[] = [];
+}
+
+export abstract class Base implements Shape {
+  abstract area(): number;
+}
+
+export class Square extends Base implements Drawable {
+  constructor(private side: number) {
+    super();
+  }
+
+  area(): number {
+    return this.side ** 2;
+  }
+
+  draw(canvas: Canvas): void {}
+}
+
+export class Circle implements Shape {
+  area(): number {
+    return 3;
+  }
+}
+
+export class ShapeError extends Error {}
+export class NegativeSide extends ShapeError {}
+
+export type Named<T> = { shape: T; label: string };
+
+export default class Registry {
+  items = new Map<string, Shape>();
+}
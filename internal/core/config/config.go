@@ -30,6 +30,17 @@ type Config struct {
 			File   string `mapstructure:"file"`
 		} `mapstructure:"global"`
 	} `mapstructure:"log"`
+
+	Analysis struct {
+		// Enables (true) or disables (false) structure detectors by name
+		Detectors map[string]bool `mapstructure:"detectors"`
+
+		// Overrides the weights and thresholds of technical debt signals by name
+		Debt struct {
+			Weights    map[string]float64 `mapstructure:"weights"`
+			Thresholds map[string]int     `mapstructure:"thresholds"`
+		} `mapstructure:"debt"`
+	} `mapstructure:"analysis"`
 }
 
 func setDefaults(v *viper.Viper) {
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultRulesFile is the rules file used when none is given
+	DefaultRulesFile = "codedna-rules.yaml"
+)
+
+// Rules declares architecture constraints checked by `codedna check`
+type Rules struct {
+	Layers []Layer `mapstructure:"layers"`
+	Rules  []Rule  `mapstructure:"rules"`
+}
+
+// Layer groups packages into an architectural layer
+type Layer struct {
+	Name     string   `mapstructure:"name"`
+	Packages []string `mapstructure:"packages"` // Package patterns (e.g. "internal/domain/...")
+}
+
+// Rule is a single constraint over the code structure
+type Rule struct {
+	Name      string   `mapstructure:"name"`
+	Type      string   `mapstructure:"type"`      // no_reference, interface_implemented, max_interface_methods, no_cross_layer_embedding
+	Packages  []string `mapstructure:"packages"`  // Package patterns the rule applies to (all packages if empty)
+	Forbidden []string `mapstructure:"forbidden"` // Package patterns that must not be referenced (no_reference)
+	Max       int      `mapstructure:"max"`       // Maximum count (max_interface_methods)
+}
+
+// LoadRules reads a rules file
+func LoadRules(path string) (*Rules, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+
+	var rules Rules
+	if err := v.Unmarshal(&rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
+	}
+	return &rules, nil
+}
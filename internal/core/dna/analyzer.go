@@ -1,6 +1,9 @@
 package dna
 
+import "codedna/internal/core/analysis/structure"
+
 // Analyzer defines the interface for DNA analysis
 type Analyzer interface {
-	// TODO: Define analysis methods
+	// Analyze builds the DNA profile of an analyzed code structure
+	Analyze(analysis structure.Analysis) (*Profile, error)
 }
@@ -0,0 +1,110 @@
+package dna
+
+import (
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Channel direction labels used in the concurrency profile
+const (
+	ChannelBidirectional = "bidirectional"
+	ChannelSendOnly      = "send"
+	ChannelRecvOnly      = "recv"
+)
+
+// How goroutines, channels, sync primitives and contexts are used
+type ConcurrencyProfile struct {
+	Patterns                map[goparser.ConcurrencyPatternKind]int        `json:"patterns"`                  // Occurrences per pattern
+	ChannelDirections       map[string]int                                 `json:"channel_directions"`        // Channel parameters and fields per direction
+	ContextFunctions        int                                            `json:"context_functions"`         // Functions taking a context.Context
+	ContextFirst            int                                            `json:"context_first"`             // Of which take it as the first parameter
+	ContextFirstRatio       float64                                        `json:"context_first_ratio"`       // Share of context functions taking it first
+	ContextPropagationRatio float64                                        `json:"context_propagation_ratio"` // Share of context functions passing it on
+	SyncTypes               []*Example                                     `json:"sync_types"`                // Types guarded by sync primitives
+	Examples                map[goparser.ConcurrencyPatternKind][]*Example `json:"examples"`
+
+	propagating int // Context functions that pass their context to a callee
+}
+
+// Creates a new empty concurrency profile
+func newConcurrencyProfile() *ConcurrencyProfile {
+	return &ConcurrencyProfile{
+		Patterns:          make(map[goparser.ConcurrencyPatternKind]int),
+		ChannelDirections: make(map[string]int),
+		SyncTypes:         make([]*Example, 0),
+		Examples:          make(map[goparser.ConcurrencyPatternKind][]*Example),
+	}
+}
+
+// Records the concurrency traits of an element
+func (c *ConcurrencyProfile) record(elem *gostructure.Element) {
+	switch elem.Type {
+	case gostructure.ElementFunction, gostructure.ElementMethod:
+		patterns, _ := elem.Attributes["concurrency_patterns"].([]*goparser.ConcurrencyPattern)
+		propagates := false
+		for _, pattern := range patterns {
+			c.Patterns[pattern.Kind]++
+			c.Examples[pattern.Kind] = addExample(c.Examples[pattern.Kind], &Example{
+				Element:  elem.Name,
+				Position: pattern.Position,
+				Detail:   pattern.Detail,
+			})
+			propagates = propagates || pattern.Kind == goparser.ConcurrencyContextPropagate
+		}
+
+		if param, _ := elem.Attributes["context_param"].(string); param != "" {
+			c.ContextFunctions++
+			if first, _ := elem.Attributes["context_first"].(bool); first {
+				c.ContextFirst++
+			}
+			if propagates {
+				c.propagating++
+			}
+		}
+
+		if signature, ok := elem.Attributes["signature"].(map[string]any); ok {
+			params, _ := signature["params"].([]*goparser.TypeInfo)
+			for _, param := range params {
+				c.recordChannel(param)
+			}
+		}
+
+	case gostructure.ElementTypeDecl:
+		fields, _ := elem.Attributes["fields"].([]map[string]any)
+		for _, field := range fields {
+			typeInfo, _ := field["type"].(*goparser.TypeInfo)
+			c.recordChannel(typeInfo)
+		}
+
+		if primitives, _ := elem.Attributes["sync_primitives"].([]string); len(primitives) > 0 {
+			c.SyncTypes = append(c.SyncTypes, &Example{
+				Element:  elem.Name,
+				Position: elem.Position,
+				Detail:   primitives[0],
+			})
+		}
+	}
+}
+
+// Records the direction of a channel type
+func (c *ConcurrencyProfile) recordChannel(typeInfo *goparser.TypeInfo) {
+	if typeInfo == nil || typeInfo.Kind != "chan" {
+		return
+	}
+	switch typeInfo.Dir {
+	case goparser.ChanSend:
+		c.ChannelDirections[ChannelSendOnly]++
+	case goparser.ChanRecv:
+		c.ChannelDirections[ChannelRecvOnly]++
+	default:
+		c.ChannelDirections[ChannelBidirectional]++
+	}
+}
+
+// Computes derived values once all elements are recorded
+func (c *ConcurrencyProfile) finish() {
+	if c.ContextFunctions > 0 {
+		c.ContextFirstRatio = float64(c.ContextFirst) / float64(c.ContextFunctions)
+		c.ContextPropagationRatio = float64(c.propagating) / float64(c.ContextFunctions)
+	}
+}
@@ -0,0 +1,78 @@
+package dna
+
+import (
+	goconvention "codedna/internal/core/analysis/convention/golang"
+)
+
+// Naming and struct tag conventions learned from the code, with the names deviating from them
+type ConventionProfile struct {
+	Naming map[goconvention.NamingRule]*NamingProfile `json:"naming"` // Conventions per naming rule with observations
+	Tags   map[string]*TagProfile                     `json:"tags"`   // Conventions per tag key in use
+}
+
+// The convention learned for a naming rule
+type NamingProfile struct {
+	Total      int            `json:"total"`      // Number of observed names
+	Variants   map[string]int `json:"variants"`   // Names per variant
+	Dominant   string         `json:"dominant"`   // The majority variant
+	Deviations int            `json:"deviations"` // Names deviating from the majority
+	Examples   []*Example     `json:"examples"`   // Sample deviations
+}
+
+// The naming convention learned for a struct tag key
+type TagProfile struct {
+	Total      int                              `json:"total"`      // Number of named tags
+	Styles     map[goconvention.NamingStyle]int `json:"styles"`     // Tags per naming style
+	Dominant   goconvention.NamingStyle         `json:"dominant"`   // The learned or enforced style
+	Enforced   bool                             `json:"enforced"`   // Whether the style was set instead of learned
+	Deviations int                              `json:"deviations"` // Tags deviating from the style
+	Missing    int                              `json:"missing"`    // Exported fields lacking a tag their struct otherwise uses
+	Examples   []*Example                       `json:"examples"`   // Sample deviations and missing tags
+}
+
+// Creates a new empty convention profile
+func newConventionProfile() *ConventionProfile {
+	return &ConventionProfile{
+		Naming: make(map[goconvention.NamingRule]*NamingProfile),
+		Tags:   make(map[string]*TagProfile),
+	}
+}
+
+// Records the learned naming conventions
+func (c *ConventionProfile) recordNaming(conventions []*goconvention.NamingConvention) {
+	for _, convention := range conventions {
+		naming := &NamingProfile{
+			Total:      convention.Total,
+			Variants:   convention.Counts,
+			Dominant:   convention.Dominant,
+			Deviations: len(convention.Deviations),
+			Examples:   make([]*Example, 0),
+		}
+		for _, d := range convention.Deviations {
+			naming.Examples = addExample(naming.Examples, &Example{Element: d.Name, Position: d.Position, Detail: d.Message})
+		}
+		c.Naming[convention.Rule] = naming
+	}
+}
+
+// Records the learned tag conventions
+func (c *ConventionProfile) recordTags(conventions []*goconvention.TagConvention) {
+	for _, convention := range conventions {
+		tags := &TagProfile{
+			Total:    convention.Total,
+			Styles:   convention.Styles,
+			Dominant: convention.Dominant,
+			Enforced: convention.Enforced,
+			Examples: make([]*Example, 0),
+		}
+		for _, d := range convention.Deviations {
+			if d.Kind == goconvention.TagDeviationMissing {
+				tags.Missing++
+			} else {
+				tags.Deviations++
+			}
+			tags.Examples = addExample(tags.Examples, &Example{Element: d.Element.Name + "." + d.Field, Position: d.Position, Detail: d.Message})
+		}
+		c.Tags[convention.Key] = tags
+	}
+}
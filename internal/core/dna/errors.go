@@ -0,0 +1,59 @@
+package dna
+
+import (
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// How errors are created, wrapped, inspected and ignored
+type ErrorProfile struct {
+	Patterns         map[goparser.ErrorPatternKind]int        `json:"patterns"`           // Occurrences per pattern
+	WrapRatio        float64                                  `json:"wrap_ratio"`         // Share of fmt.Errorf-style calls that wrap with %w
+	SentinelErrors   []*Example                               `json:"sentinel_errors"`    // Package-level error variables
+	CustomErrorTypes []*Example                               `json:"custom_error_types"` // Types implementing error
+	Examples         map[goparser.ErrorPatternKind][]*Example `json:"examples"`
+}
+
+// Creates a new empty error profile
+func newErrorProfile() *ErrorProfile {
+	return &ErrorProfile{
+		Patterns:         make(map[goparser.ErrorPatternKind]int),
+		SentinelErrors:   make([]*Example, 0),
+		CustomErrorTypes: make([]*Example, 0),
+		Examples:         make(map[goparser.ErrorPatternKind][]*Example),
+	}
+}
+
+// Records the error-handling traits of an element
+func (e *ErrorProfile) record(elem *gostructure.Element) {
+	switch elem.Type {
+	case gostructure.ElementFunction, gostructure.ElementMethod:
+		patterns, _ := elem.Attributes["error_patterns"].([]*goparser.ErrorPattern)
+		for _, pattern := range patterns {
+			e.Patterns[pattern.Kind]++
+			e.Examples[pattern.Kind] = addExample(e.Examples[pattern.Kind], &Example{
+				Element:  elem.Name,
+				Position: pattern.Position,
+				Detail:   pattern.Detail,
+			})
+		}
+
+	case gostructure.ElementVariable:
+		if sentinel, _ := elem.Attributes["is_sentinel_error"].(bool); sentinel {
+			e.SentinelErrors = append(e.SentinelErrors, &Example{Element: elem.Name, Position: elem.Position})
+		}
+
+	case gostructure.ElementTypeDecl:
+		if isError, _ := elem.Attributes["is_error_type"].(bool); isError {
+			e.CustomErrorTypes = append(e.CustomErrorTypes, &Example{Element: elem.Name, Position: elem.Position})
+		}
+	}
+}
+
+// Computes derived values once all elements are recorded
+func (e *ErrorProfile) finish() {
+	wrapped := e.Patterns[goparser.ErrorWrap]
+	if total := wrapped + e.Patterns[goparser.ErrorFormat]; total > 0 {
+		e.WrapRatio = float64(wrapped) / float64(total)
+	}
+}
@@ -0,0 +1,153 @@
+package dna
+
+import (
+	"fmt"
+	"strings"
+
+	goconvention "codedna/internal/core/analysis/convention/golang"
+	gopattern "codedna/internal/core/analysis/pattern/golang"
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Implements the Analyzer interface for Go analyses
+type GoAnalyzer struct {
+	recognizer *gopattern.Recognizer
+	naming     *goconvention.NamingAnalyzer
+	tags       *goconvention.TagAnalyzer
+	generated  structure.GeneratedCode
+}
+
+// Creates a new Go DNA analyzer, profiling generated code like handwritten code
+func NewGoAnalyzer() *GoAnalyzer {
+	return &GoAnalyzer{
+		recognizer: gopattern.NewRecognizer(),
+		naming:     goconvention.NewNamingAnalyzer(),
+		tags:       goconvention.NewTagAnalyzer(),
+		generated:  structure.GeneratedInclude,
+	}
+}
+
+// Sets how generated code is treated. When it is reported separately, the profile describes
+// handwritten code and the generated code has its own profile.
+func (a *GoAnalyzer) SetGenerated(mode structure.GeneratedCode) {
+	a.generated = mode
+}
+
+// Returns the recognizer used for design idioms, so custom matchers can be registered
+func (a *GoAnalyzer) Recognizer() *gopattern.Recognizer {
+	return a.recognizer
+}
+
+// Returns the analyzer used for struct tag conventions, so tag keys and styles can be configured
+func (a *GoAnalyzer) Tags() *goconvention.TagAnalyzer {
+	return a.tags
+}
+
+// Builds the DNA profile of a Go analysis
+func (a *GoAnalyzer) Analyze(analysis structure.Analysis) (*Profile, error) {
+	goAnalysis, ok := analysis.(*gostructure.Analysis)
+	if !ok {
+		return nil, fmt.Errorf("expected Go analysis, got %T", analysis)
+	}
+
+	code := goAnalysis.Structure
+	switch a.generated {
+	case structure.GeneratedExclude:
+		return a.profile(goAnalysis.Language(), code.Handwritten()), nil
+	case structure.GeneratedSeparate:
+		profile := a.profile(goAnalysis.Language(), code.Handwritten())
+		profile.Generated = a.profile(goAnalysis.Language(), code.Generated())
+		return profile, nil
+	}
+	return a.profile(goAnalysis.Language(), code), nil
+}
+
+// Builds the DNA profile of a structure
+func (a *GoAnalyzer) profile(language string, code *gostructure.Structure) *Profile {
+	profile := NewProfile(language)
+	packages := packageOf(code)
+
+	for _, elem := range code.Elements {
+		if elem.Type == gostructure.ElementPackage {
+			profile.Testing.record(elem)
+			packageProfile(profile, elem).Testing.record(elem)
+			continue
+		}
+
+		profile.Errors.record(elem)
+		profile.Concurrency.record(elem)
+		profile.Testing.record(elem)
+		if pkg := packages[elem]; pkg != nil {
+			pkgProfile := packageProfile(profile, pkg)
+			pkgProfile.Errors.record(elem)
+			pkgProfile.Concurrency.record(elem)
+			pkgProfile.Testing.record(elem)
+		}
+	}
+
+	// Production functions and methods called by tests
+	tested := make(map[*gostructure.Element]bool)
+	for _, rel := range code.Relationships {
+		target := rel.Target
+		if rel.Type != gostructure.RelationTests || tested[target] || target.IsTest() ||
+			(target.Type != gostructure.ElementFunction && target.Type != gostructure.ElementMethod) {
+			continue
+		}
+		tested[target] = true
+		profile.Testing.recordTested()
+		if pkg := packages[target]; pkg != nil {
+			packageProfile(profile, pkg).Testing.recordTested()
+		}
+	}
+
+	for _, match := range a.recognizer.Recognize(code) {
+		recordIdiom(profile.Idioms, match)
+		if len(match.Participants) == 0 {
+			continue
+		}
+		if pkg := packages[match.Participants[0].Element]; pkg != nil {
+			recordIdiom(packageProfile(profile, pkg).Idioms, match)
+		}
+	}
+
+	profile.Conventions.recordNaming(a.naming.Analyze(code))
+	profile.Conventions.recordTags(a.tags.Analyze(code))
+
+	profile.Errors.finish()
+	profile.Concurrency.finish()
+	profile.Testing.finish()
+	for _, pkg := range profile.Packages {
+		pkg.Errors.finish()
+		pkg.Concurrency.finish()
+		pkg.Testing.finish()
+	}
+	return profile
+}
+
+// Returns the profile of the package a package element belongs to. External test packages
+// (<name>_test) share the directory of the package they test and are profiled with it.
+func packageProfile(profile *Profile, pkg *gostructure.Element) *PackageProfile {
+	return profile.Package(pkg.Scope, packageName(pkg))
+}
+
+// Returns the name of the package a package element belongs to, naming external test packages
+// (<name>_test) after the package they test
+func packageName(pkg *gostructure.Element) string {
+	if testPackage, _ := pkg.Attributes["test_package"].(string); testPackage == goparser.TestPackageExternal {
+		return strings.TrimSuffix(pkg.Name, "_test")
+	}
+	return pkg.Name
+}
+
+// Maps each element to the package that contains it
+func packageOf(structure *gostructure.Structure) map[*gostructure.Element]*gostructure.Element {
+	packages := make(map[*gostructure.Element]*gostructure.Element)
+	for _, rel := range structure.Relationships {
+		if rel.Type == gostructure.RelationContains && rel.Source.Type == gostructure.ElementPackage {
+			packages[rel.Target] = rel.Source
+		}
+	}
+	return packages
+}
@@ -0,0 +1,336 @@
+package dna_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	goconvention "codedna/internal/core/analysis/convention/golang"
+	gopattern "codedna/internal/core/analysis/pattern/golang"
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/dna"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Helper function to build the profile of a testdata file
+func profileFile(t *testing.T, name string) *dna.Profile {
+	t.Helper()
+
+	astNode, err := goparser.New().ParseFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	analysis, err := gostructure.NewAnalyzer().Analyze(gostructure.NewNode(astNode))
+	if err != nil {
+		t.Fatalf("Failed to analyze file: %v", err)
+	}
+
+	profile, err := dna.NewGoAnalyzer().Analyze(analysis)
+	if err != nil {
+		t.Fatalf("Failed to build profile: %v", err)
+	}
+	return profile
+}
+
+func TestGoAnalyzer_Errors(t *testing.T) {
+	profile := profileFile(t, "errors.go")
+
+	pkg, ok := profile.Packages["testdata"]
+	if !ok {
+		t.Fatalf("Expected testdata package profile, got %v", profile.Packages)
+	}
+
+	for name, errors := range map[string]*dna.ErrorProfile{"project": profile.Errors, "package": pkg.Errors} {
+		t.Run(name, func(t *testing.T) {
+			expected := map[goparser.ErrorPatternKind]int{
+				goparser.ErrorWrap:   2,
+				goparser.ErrorFormat: 1,
+				goparser.ErrorIs:     1,
+			}
+			for kind, count := range expected {
+				if errors.Patterns[kind] != count {
+					t.Errorf("Expected %d %s patterns, got %d", count, kind, errors.Patterns[kind])
+				}
+			}
+
+			if ratio := errors.WrapRatio; ratio < 0.66 || ratio > 0.67 {
+				t.Errorf("Expected wrap ratio of 2/3, got %f", ratio)
+			}
+
+			if len(errors.SentinelErrors) != 2 {
+				t.Errorf("Expected 2 sentinel errors, got %d", len(errors.SentinelErrors))
+			}
+
+			if len(errors.CustomErrorTypes) != 1 || errors.CustomErrorTypes[0].Element != "ValidationError" {
+				t.Errorf("Expected ValidationError custom error type, got %v", errors.CustomErrorTypes)
+			}
+
+			examples := errors.Examples[goparser.ErrorWrap]
+			if len(examples) != 2 {
+				t.Fatalf("Expected 2 wrap examples, got %d", len(examples))
+			}
+			if examples[0].Position.Line == 0 || examples[0].Position.Filename == "" {
+				t.Errorf("Expected example location, got %v", examples[0].Position)
+			}
+		})
+	}
+}
+
+func TestGoAnalyzer_Idioms(t *testing.T) {
+	profile := profileFile(t, "idioms.go")
+
+	expected := map[gopattern.PatternKind]int{
+		gopattern.PatternFunctionalOptions:    1,
+		gopattern.PatternConstructorConcrete:  2,
+		gopattern.PatternConstructorInterface: 1,
+	}
+	if len(profile.Idioms) != len(expected) {
+		t.Errorf("Expected idioms %v, got %v", expected, profile.Idioms)
+	}
+	for kind, count := range expected {
+		idiom, ok := profile.Idioms[kind]
+		if !ok || idiom.Count != count {
+			t.Errorf("Expected %d %s idioms, got %v", count, kind, idiom)
+			continue
+		}
+		if len(idiom.Examples) == 0 || idiom.Examples[0].Position.Line == 0 {
+			t.Errorf("Expected %s example with location, got %v", kind, idiom.Examples)
+		}
+	}
+
+	options := profile.Idioms[gopattern.PatternFunctionalOptions].Examples[0]
+	if options.Element != "ClientOption" || options.Detail != "ClientOption -> *Client" {
+		t.Errorf("Expected ClientOption example, got %+v", options)
+	}
+
+	if pkg := profile.Packages["testdata"]; pkg == nil || pkg.Idioms[gopattern.PatternConstructorConcrete] == nil {
+		t.Errorf("Expected package idioms to include concrete constructors")
+	}
+}
+
+func TestGoAnalyzer_GeneratedCode(t *testing.T) {
+	astNodes, err := goparser.New().ParseDir(filepath.Join("testdata", "generated"))
+	if err != nil {
+		t.Fatalf("Failed to parse directory: %v", err)
+	}
+	nodes := make([]structure.Node, 0, len(astNodes))
+	for _, astNode := range astNodes {
+		nodes = append(nodes, gostructure.NewNode(astNode))
+	}
+	analysis, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+
+	// Helper function to profile the analysis with a treatment of generated code
+	profile := func(t *testing.T, mode structure.GeneratedCode) *dna.Profile {
+		t.Helper()
+		analyzer := dna.NewGoAnalyzer()
+		analyzer.SetGenerated(mode)
+		profile, err := analyzer.Analyze(analysis)
+		if err != nil {
+			t.Fatalf("Failed to build profile: %v", err)
+		}
+		return profile
+	}
+
+	t.Run("Include", func(t *testing.T) {
+		p := profile(t, structure.GeneratedInclude)
+		if p.Errors.Patterns[goparser.ErrorWrap] != 1 || p.Errors.Patterns[goparser.ErrorFormat] != 1 || p.Generated != nil {
+			t.Errorf("Expected the wrap and format patterns of both files, got %v (generated %v)", p.Errors.Patterns, p.Generated)
+		}
+	})
+
+	t.Run("Exclude", func(t *testing.T) {
+		p := profile(t, structure.GeneratedExclude)
+		if p.Errors.Patterns[goparser.ErrorFormat] != 0 || p.Errors.WrapRatio != 1 || p.Generated != nil {
+			t.Errorf("Expected only the handwritten wrap pattern, got %v (generated %v)", p.Errors.Patterns, p.Generated)
+		}
+	})
+
+	t.Run("Separate", func(t *testing.T) {
+		p := profile(t, structure.GeneratedSeparate)
+		if p.Errors.Patterns[goparser.ErrorFormat] != 0 || p.Errors.WrapRatio != 1 {
+			t.Errorf("Expected only the handwritten wrap pattern, got %v", p.Errors.Patterns)
+		}
+		if p.Generated == nil {
+			t.Fatal("Expected a profile of generated code")
+		}
+		if p.Generated.Errors.Patterns[goparser.ErrorWrap] != 0 || p.Generated.Errors.Patterns[goparser.ErrorFormat] != 1 {
+			t.Errorf("Expected the generated format pattern, got %v", p.Generated.Errors.Patterns)
+		}
+		if pkg := p.Generated.Packages[filepath.Join("testdata", "generated")]; pkg == nil || pkg.Name != "users" || pkg.Errors.Patterns[goparser.ErrorFormat] != 1 {
+			t.Errorf("Expected the generated users package profile, got %v", p.Generated.Packages)
+		}
+	})
+}
+
+func TestGoAnalyzer_Testing(t *testing.T) {
+	astNodes, err := goparser.New().ParseDir(filepath.Join("testdata", "testing"))
+	if err != nil {
+		t.Fatalf("Failed to parse directory: %v", err)
+	}
+	nodes := make([]structure.Node, 0, len(astNodes))
+	for _, astNode := range astNodes {
+		nodes = append(nodes, gostructure.NewNode(astNode))
+	}
+	analysis, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+	profile, err := dna.NewGoAnalyzer().Analyze(analysis)
+	if err != nil {
+		t.Fatalf("Failed to build profile: %v", err)
+	}
+
+	// The external store_test package is profiled with the store package
+	pkg, ok := profile.Packages[filepath.Join("testdata", "testing")]
+	if !ok || pkg.Name != "store" || len(profile.Packages) != 1 {
+		t.Fatalf("Expected a single store package profile, got %v", profile.Packages)
+	}
+
+	for name, profile := range map[string]*dna.TestingProfile{"project": profile.Testing, "package": pkg.Testing} {
+		t.Run(name, func(t *testing.T) {
+			if profile.Functions[goparser.TestFunction] != 2 || profile.Functions[goparser.TestBenchmark] != 1 {
+				t.Errorf("Expected 2 tests and 1 benchmark, got %v", profile.Functions)
+			}
+			if profile.Files[goparser.TestPackageInternal] != 1 || profile.Files[goparser.TestPackageExternal] != 1 {
+				t.Errorf("Expected an internal and an external test file, got %v", profile.Files)
+			}
+			if profile.TableDrivenRatio != 0.5 || profile.SubtestRatio != 0.5 {
+				t.Errorf("Expected half of the tests to be table-driven with subtests, got %f and %f", profile.TableDrivenRatio, profile.SubtestRatio)
+			}
+			if profile.Assertions != dna.AssertionsMixed {
+				t.Errorf("Expected mixed assertions, got %q", profile.Assertions)
+			}
+			// Reset is not called by any test
+			if profile.Testable != 4 || profile.Tested != 3 || profile.TestedRatio != 0.75 {
+				t.Errorf("Expected 3 of 4 functions tested, got %d of %d (%f)", profile.Tested, profile.Testable, profile.TestedRatio)
+			}
+
+			examples := profile.Examples[goparser.TestTableDriven]
+			if len(examples) != 1 || examples[0].Element != "TestSave" || examples[0].Detail != "tests" {
+				t.Errorf("Expected the TestSave table example, got %v", examples)
+			}
+		})
+	}
+}
+
+func TestGoAnalyzer_UnsupportedAnalysis(t *testing.T) {
+	if _, err := dna.NewGoAnalyzer().Analyze(nil); err == nil {
+		t.Error("Expected error for non-Go analysis")
+	}
+}
+
+func TestGoAnalyzer_Concurrency(t *testing.T) {
+	profile := profileFile(t, "concurrency.go")
+	concurrency := profile.Concurrency
+
+	expected := map[goparser.ConcurrencyPatternKind]int{
+		goparser.ConcurrencySelect:            1,
+		goparser.ConcurrencyChannelReceive:    2,
+		goparser.ConcurrencyChannelSend:       1,
+		goparser.ConcurrencyChannelMake:       1,
+		goparser.ConcurrencyGoroutine:         1,
+		goparser.ConcurrencyMutex:             1,
+		goparser.ConcurrencyContextPropagate:  1,
+		goparser.ConcurrencyContextBackground: 1,
+	}
+	for kind, count := range expected {
+		if concurrency.Patterns[kind] != count {
+			t.Errorf("Expected %d %s patterns, got %d", count, kind, concurrency.Patterns[kind])
+		}
+	}
+
+	directions := map[string]int{
+		dna.ChannelBidirectional: 1,
+		dna.ChannelSendOnly:      1,
+		dna.ChannelRecvOnly:      1,
+	}
+	for dir, count := range directions {
+		if concurrency.ChannelDirections[dir] != count {
+			t.Errorf("Expected %d %s channels, got %d", count, dir, concurrency.ChannelDirections[dir])
+		}
+	}
+
+	if concurrency.ContextFunctions != 3 || concurrency.ContextFirst != 2 {
+		t.Errorf("Expected 2 of 3 context functions to take it first, got %d of %d", concurrency.ContextFirst, concurrency.ContextFunctions)
+	}
+	if ratio := concurrency.ContextPropagationRatio; ratio < 0.33 || ratio > 0.34 {
+		t.Errorf("Expected context propagation ratio of 1/3, got %f", ratio)
+	}
+
+	if len(concurrency.SyncTypes) != 1 || concurrency.SyncTypes[0].Element != "Worker" || concurrency.SyncTypes[0].Detail != "sync.Mutex" {
+		t.Errorf("Expected Worker guarded by sync.Mutex, got %v", concurrency.SyncTypes)
+	}
+
+	if pkg := profile.Packages["testdata"]; pkg == nil || pkg.Concurrency.Patterns[goparser.ConcurrencyMutex] != 1 {
+		t.Errorf("Expected package concurrency profile with 1 mutex pattern")
+	}
+}
+
+func TestGoAnalyzer_SameNamedPackages(t *testing.T) {
+	var nodes []structure.Node
+	for _, dir := range []string{"server", "worker"} {
+		astNodes, err := goparser.New().ParseDir(filepath.Join("testdata", "mains", dir))
+		if err != nil {
+			t.Fatalf("Failed to parse directory: %v", err)
+		}
+		for _, astNode := range astNodes {
+			nodes = append(nodes, gostructure.NewNode(astNode))
+		}
+	}
+	analysis, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze directories: %v", err)
+	}
+	profile, err := dna.NewGoAnalyzer().Analyze(analysis)
+	if err != nil {
+		t.Fatalf("Failed to build profile: %v", err)
+	}
+
+	// Both main packages get their own profile, keyed by directory
+	if len(profile.Packages) != 2 {
+		t.Fatalf("Expected 2 package profiles, got %v", profile.Packages)
+	}
+	server := profile.Packages[filepath.Join("testdata", "mains", "server")]
+	worker := profile.Packages[filepath.Join("testdata", "mains", "worker")]
+	if server == nil || worker == nil || server.Name != "main" || worker.Name != "main" {
+		t.Fatalf("Expected main package profiles for server and worker, got %v", profile.Packages)
+	}
+	if server.Errors.Patterns[goparser.ErrorFormat] != 1 || server.Errors.Patterns[goparser.ErrorWrap] != 0 {
+		t.Errorf("Expected only the server format pattern, got %v", server.Errors.Patterns)
+	}
+	if worker.Errors.Patterns[goparser.ErrorWrap] != 1 || worker.Errors.Patterns[goparser.ErrorFormat] != 0 {
+		t.Errorf("Expected only the worker wrap pattern, got %v", worker.Errors.Patterns)
+	}
+	if server.Concurrency.Patterns[goparser.ConcurrencyMutex] != 0 || worker.Concurrency.Patterns[goparser.ConcurrencyMutex] != 1 {
+		t.Errorf("Expected the mutex in the worker only, got %v and %v", server.Concurrency.Patterns, worker.Concurrency.Patterns)
+	}
+}
+
+func TestGoAnalyzer_Conventions(t *testing.T) {
+	profile := profileFile(t, "conventions.go")
+	conventions := profile.Conventions
+
+	t.Run("tags", func(t *testing.T) {
+		tags := conventions.Tags["json"]
+		if tags == nil || tags.Total != 7 || tags.Dominant != goconvention.StyleSnakeCase {
+			t.Fatalf("Expected 7 snake_case json tags, got %+v", tags)
+		}
+		if tags.Deviations != 1 || len(tags.Examples) != 1 || tags.Examples[0].Element != "User.Email" {
+			t.Errorf("Expected the emailAddress tag of User.Email to deviate, got %+v", tags.Examples)
+		}
+	})
+
+	t.Run("naming", func(t *testing.T) {
+		receivers := conventions.Naming[goconvention.RuleReceiverConsistency]
+		if receivers == nil || receivers.Dominant != goconvention.VariantConsistent || receivers.Deviations != 1 {
+			t.Fatalf("Expected one inconsistent receiver, got %+v", receivers)
+		}
+		if example := receivers.Examples[0]; example.Element != "acct" || example.Detail == "" {
+			t.Errorf("Expected the acct receiver as example, got %+v", example)
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package dna
+
+import (
+	gopattern "codedna/internal/core/analysis/pattern/golang"
+)
+
+// How often a design idiom is used, with sample occurrences
+type IdiomProfile struct {
+	Count    int        `json:"count"`
+	Examples []*Example `json:"examples"`
+}
+
+// Records a recognized pattern in a set of idioms
+func recordIdiom(idioms map[gopattern.PatternKind]*IdiomProfile, match *gopattern.Match) {
+	idiom, ok := idioms[match.Pattern]
+	if !ok {
+		idiom = &IdiomProfile{Examples: make([]*Example, 0)}
+		idioms[match.Pattern] = idiom
+	}
+	idiom.Count++
+
+	example := &Example{Position: match.Position, Detail: match.Detail}
+	if len(match.Participants) > 0 {
+		example.Element = match.Participants[0].Element.Name
+	}
+	idiom.Examples = addExample(idiom.Examples, example)
+}
@@ -0,0 +1,78 @@
+// Package dna builds a project's DNA profile from its analyzed structure
+package dna
+
+import (
+	gopattern "codedna/internal/core/analysis/pattern/golang"
+	"codedna/internal/core/parser/ast"
+)
+
+// Maximum number of examples kept per trait
+const MaxExamples = 3
+
+// The DNA profile of a project
+type Profile struct {
+	Language    string                                  `json:"language"`
+	Packages    map[string]*PackageProfile              `json:"packages"`            // Directory -> package profile
+	Errors      *ErrorProfile                           `json:"errors"`              // Project-wide error handling
+	Concurrency *ConcurrencyProfile                     `json:"concurrency"`         // Project-wide concurrency
+	Idioms      map[gopattern.PatternKind]*IdiomProfile `json:"idioms"`              // Design idioms in use
+	Testing     *TestingProfile                         `json:"testing"`             // Project-wide testing style
+	Conventions *ConventionProfile                      `json:"conventions"`         // Naming and struct tag conventions
+	Generated   *Profile                                `json:"generated,omitempty"` // Generated code, when profiled separately
+}
+
+// The DNA profile of a single package
+type PackageProfile struct {
+	Name        string                                  `json:"name"`
+	Dir         string                                  `json:"dir"`
+	Errors      *ErrorProfile                           `json:"errors"`
+	Concurrency *ConcurrencyProfile                     `json:"concurrency"`
+	Idioms      map[gopattern.PatternKind]*IdiomProfile `json:"idioms"`
+	Testing     *TestingProfile                         `json:"testing"`
+}
+
+// A concrete occurrence of a trait in the code
+type Example struct {
+	Element  string       `json:"element"`
+	Position ast.Position `json:"position"`
+	Detail   string       `json:"detail,omitempty"`
+}
+
+// Creates a new empty profile
+func NewProfile(language string) *Profile {
+	return &Profile{
+		Language:    language,
+		Packages:    make(map[string]*PackageProfile),
+		Errors:      newErrorProfile(),
+		Concurrency: newConcurrencyProfile(),
+		Idioms:      make(map[gopattern.PatternKind]*IdiomProfile),
+		Testing:     newTestingProfile(),
+		Conventions: newConventionProfile(),
+	}
+}
+
+// Returns the profile of the package in a directory, creating it with the given name if needed.
+// Packages are keyed by directory, so same-named packages (e.g. several main packages) stay apart.
+func (p *Profile) Package(dir, name string) *PackageProfile {
+	pkg, ok := p.Packages[dir]
+	if !ok {
+		pkg = &PackageProfile{
+			Name:        name,
+			Dir:         dir,
+			Errors:      newErrorProfile(),
+			Concurrency: newConcurrencyProfile(),
+			Idioms:      make(map[gopattern.PatternKind]*IdiomProfile),
+			Testing:     newTestingProfile(),
+		}
+		p.Packages[dir] = pkg
+	}
+	return pkg
+}
+
+// Appends an example unless the limit has been reached
+func addExample(examples []*Example, example *Example) []*Example {
+	if len(examples) >= MaxExamples {
+		return examples
+	}
+	return append(examples, example)
+}
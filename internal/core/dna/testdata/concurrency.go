@@ -0,0 +1,40 @@
+package testdata
+
+import (
+	"context"
+	"sync"
+)
+
+type Worker struct {
+	mu      sync.Mutex
+	jobs    chan Job
+	results chan<- Result
+	count   int
+}
+
+type Job struct{}
+
+type Result struct{}
+
+func (w *Worker) Run(ctx context.Context, in <-chan Job) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-in:
+			w.handle(ctx, job)
+		}
+	}
+}
+
+func (w *Worker) handle(ctx context.Context, job Job) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.count++
+	w.results <- Result{}
+}
+
+func Start(name string, ctx context.Context) {
+	w := &Worker{jobs: make(chan Job, 8)}
+	go w.Run(context.Background(), w.jobs)
+}
@@ -0,0 +1,20 @@
+package testdata
+
+type User struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"emailAddress"`
+}
+
+type Account struct {
+	UserID  int    `json:"user_id"`
+	Balance int64  `json:"balance"`
+	Owner   string `json:"owner_name"`
+}
+
+func (u *User) Name() string       { return u.FirstName + " " + u.LastName }
+func (u *User) Contact() string    { return u.Email }
+func (a *Account) Empty() bool     { return a.Balance == 0 }
+func (a *Account) Holder() string  { return a.Owner }
+func (acct *Account) Total() int64 { return acct.Balance }
@@ -0,0 +1,49 @@
+package testdata
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned when a record does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned when a record already exists
+var ErrConflict = fmt.Errorf("conflict")
+
+// ValidationError describes an invalid record
+type ValidationError struct {
+	Field string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid " + e.Field
+}
+
+// Repository stores records
+type Repository struct {
+	records map[string]string
+}
+
+// Get returns a record
+func (r *Repository) Get(id string) (string, error) {
+	record, ok := r.records[id]
+	if !ok {
+		return "", fmt.Errorf("get %s: %w", id, ErrNotFound)
+	}
+	return record, nil
+}
+
+// Put stores a record
+func (r *Repository) Put(id, record string) error {
+	if id == "" {
+		return &ValidationError{Field: "id"}
+	}
+	if _, err := r.Get(id); err == nil {
+		return fmt.Errorf("put %s: %w", id, ErrConflict)
+	} else if !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("put %s: %v", id, err)
+	}
+	r.records[id] = record
+	return nil
+}
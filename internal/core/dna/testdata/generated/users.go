@@ -0,0 +1,14 @@
+package users
+
+import "fmt"
+
+type Service struct {
+	client UsersClient
+}
+
+func (s *Service) Rename(id, name string) error {
+	if err := s.client.Update(id, name); err != nil {
+		return fmt.Errorf("rename user %s: %w", id, err)
+	}
+	return nil
+}
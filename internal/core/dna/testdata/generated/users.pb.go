@@ -0,0 +1,19 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: users.proto
+
+package users
+
+import "fmt"
+
+type UsersClient interface {
+	Update(id, name string) error
+}
+
+type usersClient struct{}
+
+func (c *usersClient) Update(id, name string) error {
+	if id == "" {
+		return fmt.Errorf("missing id for %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package testdata
+
+type Client struct {
+	retries int
+}
+
+type ClientOption func(*Client)
+
+func WithRetries(retries int) ClientOption {
+	return func(c *Client) { c.retries = retries }
+}
+
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type Cache interface {
+	Get(key string) string
+}
+
+type memoryCache struct{}
+
+func (c *memoryCache) Get(key string) string { return "" }
+
+func NewCache() Cache {
+	return &memoryCache{}
+}
+
+func NewMemoryCache() *memoryCache {
+	return &memoryCache{}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func run(addr string) error {
+	if addr == "" {
+		return fmt.Errorf("missing address")
+	}
+	return nil
+}
+
+func main() {
+	if err := run(os.Getenv("ADDR")); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+var mu sync.Mutex
+
+func work(queue string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, err := os.Stat(queue); err != nil {
+		return fmt.Errorf("open queue: %w", err)
+	}
+	return nil
+}
+
+func main() {
+	if err := work(os.Getenv("QUEUE")); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,14 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/app/store"
+)
+
+func TestNames(t *testing.T) {
+	s := store.New()
+	require.Empty(t, s.Names())
+}
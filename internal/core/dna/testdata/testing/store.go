@@ -0,0 +1,21 @@
+package store
+
+type Store struct {
+	names []string
+}
+
+func New() *Store {
+	return &Store{}
+}
+
+func (s *Store) Save(name string) {
+	s.names = append(s.names, name)
+}
+
+func (s *Store) Names() []string {
+	return s.names
+}
+
+func (s *Store) Reset() {
+	s.names = nil
+}
@@ -0,0 +1,29 @@
+package store
+
+import "testing"
+
+func TestSave(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+	}{
+		{name: "alice", count: 1},
+		{name: "bob", count: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New()
+			s.Save(tt.name)
+			if len(s.names) != tt.count {
+				t.Errorf("Expected %d names, got %d", tt.count, len(s.names))
+			}
+		})
+	}
+}
+
+func BenchmarkSave(b *testing.B) {
+	s := New()
+	for b.Loop() {
+		s.Save("alice")
+	}
+}
@@ -0,0 +1,113 @@
+package dna
+
+import (
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Assertion styles used in the testing profile
+const (
+	AssertionsStdlib  = "stdlib"  // t.Error and t.Fatal family
+	AssertionsTestify = "testify" // github.com/stretchr/testify assert and require
+	AssertionsMixed   = "mixed"   // Both
+)
+
+// How tests are organized and written, and how much of the code they exercise
+type TestingProfile struct {
+	Functions        map[goparser.TestKind]int               `json:"functions"`          // Test, benchmark, fuzz, example and TestMain functions
+	Files            map[string]int                          `json:"files"`              // Test files per package kind (internal or external)
+	Patterns         map[goparser.TestPatternKind]int        `json:"patterns"`           // Occurrences per pattern
+	TableDrivenRatio float64                                 `json:"table_driven_ratio"` // Share of tests ranging over a table of cases
+	SubtestRatio     float64                                 `json:"subtest_ratio"`      // Share of tests running subtests
+	Assertions       string                                  `json:"assertions"`         // Assertion style of test code, empty without assertions
+	Testable         int                                     `json:"testable"`           // Production functions and methods
+	Tested           int                                     `json:"tested"`             // Of which are called by tests
+	TestedRatio      float64                                 `json:"tested_ratio"`       // Share of production functions and methods called by tests
+	Examples         map[goparser.TestPatternKind][]*Example `json:"examples"`
+
+	tableDriven int // Tests ranging over a table of cases
+	subtests    int // Tests running subtests
+	stdlib      int // Test code functions asserting with the testing package
+	testify     int // Test code functions asserting with testify
+}
+
+// Creates a new empty testing profile
+func newTestingProfile() *TestingProfile {
+	return &TestingProfile{
+		Functions: make(map[goparser.TestKind]int),
+		Files:     make(map[string]int),
+		Patterns:  make(map[goparser.TestPatternKind]int),
+		Examples:  make(map[goparser.TestPatternKind][]*Example),
+	}
+}
+
+// Records the testing traits of an element
+func (p *TestingProfile) record(elem *gostructure.Element) {
+	switch elem.Type {
+	case gostructure.ElementPackage:
+		if testPackage, _ := elem.Attributes["test_package"].(string); testPackage != "" {
+			p.Files[testPackage]++
+		}
+
+	case gostructure.ElementFunction, gostructure.ElementMethod:
+		if !elem.IsTest() {
+			p.Testable++
+			return
+		}
+
+		kind, _ := elem.Attributes["test_kind"].(goparser.TestKind)
+		if kind != "" {
+			p.Functions[kind]++
+		}
+		found := make(map[goparser.TestPatternKind]bool)
+		patterns, _ := elem.Attributes["test_patterns"].([]*goparser.TestPattern)
+		for _, pattern := range patterns {
+			p.Patterns[pattern.Kind]++
+			p.Examples[pattern.Kind] = addExample(p.Examples[pattern.Kind], &Example{
+				Element:  elem.Name,
+				Position: pattern.Position,
+				Detail:   pattern.Detail,
+			})
+			found[pattern.Kind] = true
+		}
+
+		if kind == goparser.TestFunction {
+			if found[goparser.TestTableDriven] {
+				p.tableDriven++
+			}
+			if found[goparser.TestSubtest] {
+				p.subtests++
+			}
+		}
+		if found[goparser.TestStdlibAssert] {
+			p.stdlib++
+		}
+		if found[goparser.TestTestify] {
+			p.testify++
+		}
+	}
+}
+
+// Records a production function or method called by tests
+func (p *TestingProfile) recordTested() {
+	p.Tested++
+}
+
+// Computes derived values once all elements are recorded
+func (p *TestingProfile) finish() {
+	if tests := p.Functions[goparser.TestFunction]; tests > 0 {
+		p.TableDrivenRatio = float64(p.tableDriven) / float64(tests)
+		p.SubtestRatio = float64(p.subtests) / float64(tests)
+	}
+	switch {
+	case p.stdlib > 0 && p.testify > 0:
+		p.Assertions = AssertionsMixed
+	case p.testify > 0:
+		p.Assertions = AssertionsTestify
+	case p.stdlib > 0:
+		p.Assertions = AssertionsStdlib
+	}
+	if p.Testable > 0 {
+		p.TestedRatio = float64(p.Tested) / float64(p.Testable)
+	}
+}
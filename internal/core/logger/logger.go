@@ -15,6 +15,8 @@
 package logger
 
 import (
+	"io"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -34,23 +36,10 @@ func New(cfg Config) (*zap.Logger, error) {
 	}
 
 	zapCfg := zap.Config{
-		Level:       zap.NewAtomicLevelAt(getZapLevel(cfg.Level)),
-		Development: false,
-		Encoding:    getEncoderFormat(cfg.Format),
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:        "time",
-			LevelKey:       "level",
-			NameKey:        "logger",
-			CallerKey:      "caller",
-			FunctionKey:    zapcore.OmitKey,
-			MessageKey:     "msg",
-			StacktraceKey:  "stacktrace",
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeLevel:    zapcore.CapitalColorLevelEncoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.SecondsDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
-		},
+		Level:            zap.NewAtomicLevelAt(getZapLevel(cfg.Level)),
+		Development:      false,
+		Encoding:         getEncoderFormat(cfg.Format),
+		EncoderConfig:    encoderConfig(),
 		OutputPaths:      getOutputPaths(cfg.Output, cfg.File),
 		ErrorOutputPaths: []string{"stderr"},
 		InitialFields:    map[string]any{"component": component},
@@ -59,6 +48,39 @@ func New(cfg Config) (*zap.Logger, error) {
 	return zapCfg.Build()
 }
 
+// Creates a logger writing to w instead of the configured output, e.g. the stderr of a CLI
+// command whose stdout carries its results
+func NewWriter(cfg Config, w io.Writer) *zap.Logger {
+	component := cfg.Component
+	if component == "" {
+		component = "unknown"
+	}
+
+	encoder := zapcore.NewConsoleEncoder(encoderConfig())
+	if getEncoderFormat(cfg.Format) == "json" {
+		encoder = zapcore.NewJSONEncoder(encoderConfig())
+	}
+	core := zapcore.NewCore(encoder, zapcore.AddSync(w), zap.NewAtomicLevelAt(getZapLevel(cfg.Level)))
+	return zap.New(core).With(zap.String("component", component))
+}
+
+func encoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		FunctionKey:    zapcore.OmitKey,
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.CapitalColorLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
 func getOutputPaths(output, file string) []string {
 	switch output {
 	case "stdout":
@@ -5,9 +5,10 @@ import "fmt"
 
 // represents a position in source code
 type Position struct {
-	Line   int
-	Column int
-	Offset int
+	Filename string
+	Line     int
+	Column   int
+	Offset   int
 }
 
 // AST node
@@ -67,6 +68,14 @@ func (n *BaseNode) SetAttribute(key string, value any) {
 	n.attributes[key] = value
 }
 
+// provides a "file:line:column" representation of the position
+func (p Position) String() string {
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
 // provides a debug representation of the node
 func (n *BaseNode) String() string {
 	return fmt.Sprintf("%s at line %d", n.nodeType, n.pos.Line)
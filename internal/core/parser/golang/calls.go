@@ -0,0 +1,206 @@
+package goparser
+
+import (
+	goast "go/ast"
+	"go/types"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// A function or method call made in a function body
+type Call struct {
+	Name     string // The called function or method
+	Package  string // Import path of the called function or of the receiver's type, when it is imported
+	Receiver string // Type name of the called method's receiver, when known (e.g. "Store")
+	Method   bool   // Whether a method is called (x.Name() on a value rather than a package)
+	Position ast.Position
+}
+
+// Finds the calls made in a function body, including the bodies of its function literals.
+// Conversions and calls to builtins and function values are skipped.
+func (p *Parser) calls(fn *goast.FuncDecl) []*Call {
+	calls := make([]*Call, 0)
+	if fn.Body == nil {
+		return calls
+	}
+
+	// Parameters of imported types, whose methods type checking cannot resolve without importing them
+	params := p.importedParams(fn.Type)
+	goast.Inspect(fn.Body, func(n goast.Node) bool {
+		if lit, ok := n.(*goast.FuncLit); ok {
+			for name, typ := range p.importedParams(lit.Type) {
+				params[name] = typ
+			}
+		}
+		call, ok := n.(*goast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		fun := goast.Unparen(call.Fun)
+		switch f := fun.(type) {
+		case *goast.IndexExpr: // Explicitly instantiated generic function
+			fun = f.X
+		case *goast.IndexListExpr:
+			fun = f.X
+		}
+
+		switch f := fun.(type) {
+		case *goast.Ident:
+			switch p.info.Uses[f].(type) {
+			case *types.Builtin, *types.TypeName, *types.Var:
+				return true
+			case nil:
+				if types.Universe.Lookup(f.Name) != nil {
+					return true
+				}
+			}
+			calls = append(calls, &Call{Name: f.Name, Position: p.position(call.Pos())})
+
+		case *goast.SelectorExpr:
+			c := &Call{Name: f.Sel.Name, Position: p.position(call.Pos())}
+			x, isIdent := f.X.(*goast.Ident)
+			if path, ok := p.imports[identName(x)]; ok && isIdent && !p.isLocalObject(x) {
+				if _, isType := p.info.Uses[f.Sel].(*types.TypeName); isType {
+					return true
+				}
+				c.Package = path
+			} else {
+				c.Method = true
+				if recv := methodReceiver(p.info.Uses[f.Sel]); recv != nil && recv.Obj().Pkg() == p.pkg {
+					c.Receiver = recv.Obj().Name() // Declared in the parsed package
+				} else if recv := p.selectionReceiver(f); recv != nil {
+					// Imported types (from the standard library) are named as the caller sees them,
+					// rather than by the embedded type that may declare the method
+					c.Package, c.Receiver = recv.Obj().Pkg().Path(), recv.Obj().Name()
+				} else if isIdent {
+					if typ, ok := params[x.Name]; ok {
+						c.Package, c.Receiver = typ[0], typ[1]
+					}
+				}
+			}
+			calls = append(calls, c)
+		}
+		return true
+	})
+	return calls
+}
+
+// Returns the name of an identifier, or "" for nil
+func identName(ident *goast.Ident) string {
+	if ident == nil {
+		return ""
+	}
+	return ident.Name
+}
+
+// Returns the named receiver type of a method object, or nil
+func methodReceiver(obj types.Object) *types.Named {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil
+	}
+	typ := recv.Type()
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, _ := typ.(*types.Named)
+	return named
+}
+
+// Returns the named type of an imported value whose method is selected, or nil
+func (p *Parser) selectionReceiver(sel *goast.SelectorExpr) *types.Named {
+	selection, ok := p.info.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return nil
+	}
+	typ := selection.Recv()
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, _ := typ.(*types.Named)
+	if named == nil || named.Obj().Pkg() == nil || named.Obj().Pkg() == p.pkg {
+		return nil
+	}
+	return named
+}
+
+// Maps the parameters of a function type declared with an imported type (e.g. t *testing.T) to
+// the import path and name of the type
+func (p *Parser) importedParams(fnType *goast.FuncType) map[string][2]string {
+	params := make(map[string][2]string)
+	if fnType == nil || fnType.Params == nil {
+		return params
+	}
+	for _, field := range fnType.Params.List {
+		typ := field.Type
+		if star, ok := typ.(*goast.StarExpr); ok {
+			typ = star.X
+		}
+		sel, ok := typ.(*goast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		pkg, ok := sel.X.(*goast.Ident)
+		if !ok {
+			continue
+		}
+		path, ok := p.imports[pkg.Name]
+		if !ok {
+			continue
+		}
+		for _, name := range field.Names {
+			params[name.Name] = [2]string{path, sel.Sel.Name}
+		}
+	}
+	return params
+}
+
+// Returns the names of the package's functions that a file uses other than by calling them from
+// a function, such as handlers passed to a router or functions initializing package variables
+func (p *Parser) funcValues(file *goast.File) []string {
+	callees := make(map[*goast.Ident]bool)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*goast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		goast.Inspect(fn.Body, func(n goast.Node) bool {
+			call, ok := n.(*goast.CallExpr)
+			if !ok {
+				return true
+			}
+			fun := goast.Unparen(call.Fun)
+			switch f := fun.(type) {
+			case *goast.IndexExpr:
+				fun = f.X
+			case *goast.IndexListExpr:
+				fun = f.X
+			}
+			if ident, ok := fun.(*goast.Ident); ok {
+				callees[ident] = true
+			}
+			return true
+		})
+	}
+
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	goast.Inspect(file, func(n goast.Node) bool {
+		ident, ok := n.(*goast.Ident)
+		if !ok || callees[ident] || seen[ident.Name] {
+			return true
+		}
+		fn, ok := p.info.Uses[ident].(*types.Func)
+		if ok && fn.Pkg() == p.pkg && fn.Parent() == fn.Pkg().Scope() {
+			seen[ident.Name] = true
+			names = append(names, ident.Name)
+		}
+		return true
+	})
+	return names
+}
@@ -0,0 +1,107 @@
+package goparser_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+func TestCalls(t *testing.T) {
+	modules := parseFiles(t, map[string]string{
+		"store.go": `
+		package store
+
+		import (
+			"strings"
+			"testing"
+
+			"example.com/app/users"
+		)
+
+		type Store struct{ names []string }
+
+		func (s *Store) Save(name string) { s.names = append(s.names, name) }
+
+		func New() *Store { return &Store{} }
+
+		func first[T any](values []T) T { return values[0] }
+
+		func Load(t *testing.T, list users.List) {
+			s := New()
+			s.Save(strings.ToUpper("x"))
+			_ = first[string](s.names)
+			n := len(s.names)
+			_ = int64(n)
+			list.Each(func(b *testing.B) { b.ResetTimer() })
+			users.Find("alice").Rename()
+			t.Log("loaded")
+		}
+		`,
+	})
+
+	var load ast.Node
+	for _, fn := range findNodes(modules["store.go"], ast.Function) {
+		if fn.Attributes()["name"] == "Load" {
+			load = fn
+		}
+	}
+	var got []string
+	for _, call := range load.Attributes()["calls"].([]*goparser.Call) {
+		got = append(got, fmt.Sprintf("%s package=%s receiver=%s method=%v", call.Name, call.Package, call.Receiver, call.Method))
+	}
+	expected := []string{
+		"New package= receiver= method=false",
+		"Save package= receiver=Store method=true",
+		"ToUpper package=strings receiver= method=false",
+		"first package= receiver= method=false",
+		"Each package=example.com/app/users receiver=List method=true",
+		"ResetTimer package=testing receiver=B method=true",
+		"Rename package= receiver= method=true",
+		"Find package=example.com/app/users receiver= method=false",
+		"Log package=testing receiver=T method=true",
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected calls %q, got %q", expected, got)
+	}
+}
+
+func TestFuncValues(t *testing.T) {
+	modules := parseFiles(t, map[string]string{
+		"routes.go": `
+		package routes
+
+		import "net/http"
+
+		var handlers = map[string]http.HandlerFunc{"/health": health}
+
+		var mux = newMux()
+
+		func newMux() *http.ServeMux { return http.NewServeMux() }
+
+		func health(w http.ResponseWriter, r *http.Request) {}
+
+		func users(w http.ResponseWriter, r *http.Request) {}
+
+		func unused() {}
+
+		func Register(mux *http.ServeMux) {
+			mux.HandleFunc("/users", users)
+			register(mux)
+		}
+
+		func register(mux *http.ServeMux) {
+			for path, handler := range handlers {
+				mux.HandleFunc(path, handler)
+			}
+		}
+		`,
+	})
+
+	values := modules["routes.go"].Attributes()["func_values"].([]string)
+	if expected := []string{"health", "newMux", "users"}; !slices.Equal(values, expected) {
+		t.Errorf("Expected function values %q, got %q", expected, values)
+	}
+}
@@ -0,0 +1,77 @@
+package goparser
+
+import (
+	goast "go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// Markers of comments flagging unfinished or questionable code, starting a comment line and
+// followed by a colon or a parenthesized owner (e.g. "TODO: retry" or "FIXME(ann): leaks")
+var todoMarker = regexp.MustCompile(`(?m)^\s*(?://|/\*|\*)?\s*((?:TODO|FIXME|HACK|XXX)(?:\([^)]*\))?:.*)$`)
+
+// Returns the text of a doc comment, or "" without one
+func docText(doc *goast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+// A comment line holding a TODO marker
+type todoComment struct {
+	pos  token.Pos
+	text string // The comment line from the marker on (e.g. "TODO: handle retries")
+}
+
+// Finds the comment lines of a file holding TODO markers
+func todoComments(file *goast.File) []*todoComment {
+	var todos []*todoComment
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			for _, match := range todoMarker.FindAllStringSubmatch(strings.TrimSuffix(comment.Text, "*/"), -1) {
+				todos = append(todos, &todoComment{pos: comment.Pos(), text: strings.TrimSpace(match[1])})
+			}
+		}
+	}
+	return todos
+}
+
+// Returns the TODO comments between two positions
+func todosBetween(todos []*todoComment, start, end token.Pos) []string {
+	texts := make([]string, 0)
+	for _, todo := range todos {
+		if start <= todo.pos && todo.pos < end {
+			texts = append(texts, todo.text)
+		}
+	}
+	return texts
+}
+
+// Returns the TODO comments of a file outside its function declarations, including their doc
+// comments
+func fileTodos(file *goast.File, todos []*todoComment) []string {
+	texts := make([]string, 0)
+	for _, todo := range todos {
+		inFunction := false
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*goast.FuncDecl); ok && functionStart(fn) <= todo.pos && todo.pos < fn.End() {
+				inFunction = true
+				break
+			}
+		}
+		if !inFunction {
+			texts = append(texts, todo.text)
+		}
+	}
+	return texts
+}
+
+// Returns the start of a function declaration, including its doc comment
+func functionStart(fn *goast.FuncDecl) token.Pos {
+	if fn.Doc != nil {
+		return fn.Doc.Pos()
+	}
+	return fn.Pos()
+}
@@ -0,0 +1,84 @@
+package goparser_test
+
+import (
+	"slices"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+)
+
+func TestComments(t *testing.T) {
+	modules := parseFiles(t, map[string]string{
+		"store.go": `
+		// Package store keeps names.
+		//
+		// TODO(ann): persist them
+		package store
+
+		// A store of names
+		type Store struct{ names []string }
+
+		type (
+			// The number of names
+			Count int
+
+			Name string
+		)
+
+		type Index map[string]int
+
+		// Adds a name.
+		// FIXME: reject duplicates
+		func (s *Store) Add(name string) {
+			// XXX: not thread-safe
+			s.names = append(s.names, name)
+		}
+
+		func (s *Store) Len() int {
+			return len(s.names) // TODO markers must start the comment
+		}
+
+		/* HACK: kept for old callers */
+		var legacy = true
+		`,
+	})
+	module := modules["store.go"]
+
+	t.Run("Docs", func(t *testing.T) {
+		docs := make(map[string]any)
+		for _, node := range append(findNodes(module, ast.Type), findNodes(module, ast.Method)...) {
+			docs[node.Attributes()["name"].(string)] = node.Attributes()["doc"]
+		}
+		expected := map[string]any{
+			"Store": "A store of names",
+			"Count": "The number of names",
+			"Name":  "",
+			"Index": "",
+			"Add":   "Adds a name.\nFIXME: reject duplicates",
+			"Len":   "",
+		}
+		for name, doc := range expected {
+			if docs[name] != doc {
+				t.Errorf("Expected %s doc %q, got %q", name, doc, docs[name])
+			}
+		}
+	})
+
+	t.Run("Todos", func(t *testing.T) {
+		todos := make(map[string][]string)
+		for _, node := range findNodes(module, ast.Method) {
+			todos[node.Attributes()["name"].(string)] = node.Attributes()["todos"].([]string)
+		}
+		if expected := []string{"FIXME: reject duplicates", "XXX: not thread-safe"}; !slices.Equal(todos["Add"], expected) {
+			t.Errorf("Expected Add todos %q, got %q", expected, todos["Add"])
+		}
+		if len(todos["Len"]) != 0 {
+			t.Errorf("Expected no Len todos, got %q", todos["Len"])
+		}
+
+		fileTodos := module.Attributes()["todos"].([]string)
+		if expected := []string{"TODO(ann): persist them", "HACK: kept for old callers"}; !slices.Equal(fileTodos, expected) {
+			t.Errorf("Expected file todos %q, got %q", expected, fileTodos)
+		}
+	})
+}
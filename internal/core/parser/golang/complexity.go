@@ -0,0 +1,36 @@
+package goparser
+
+import (
+	goast "go/ast"
+	"go/token"
+)
+
+// Returns the cyclomatic complexity of a function body: one plus its decision points, which
+// are if, for and range statements, case and select clauses other than default, and the &&
+// and || operators. Function literals count toward the enclosing function.
+func complexity(body *goast.BlockStmt) int {
+	count := 1
+	if body == nil {
+		return count
+	}
+	goast.Inspect(body, func(n goast.Node) bool {
+		switch s := n.(type) {
+		case *goast.IfStmt, *goast.ForStmt, *goast.RangeStmt:
+			count++
+		case *goast.CaseClause:
+			if s.List != nil {
+				count++
+			}
+		case *goast.CommClause:
+			if s.Comm != nil {
+				count++
+			}
+		case *goast.BinaryExpr:
+			if s.Op == token.LAND || s.Op == token.LOR {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
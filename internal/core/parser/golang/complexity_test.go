@@ -0,0 +1,55 @@
+package goparser_test
+
+import (
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+)
+
+func TestComplexity(t *testing.T) {
+	modules := parseFiles(t, map[string]string{
+		"shapes.go": `
+		package shapes
+
+		func Empty() {}
+
+		func Straight(a, b int) int { return a + b }
+
+		func Branches(values []int, ch chan int) int {
+			total := 0
+			for _, v := range values {
+				if v > 0 && v < 100 || v == -1 {
+					total += v
+				}
+			}
+			switch {
+			case total > 10:
+				total = 10
+			case total < 0:
+				total = 0
+			default:
+			}
+			select {
+			case v := <-ch:
+				total += v
+			default:
+			}
+			func() {
+				if total == 0 {
+					total = 1
+				}
+			}()
+			return total
+		}
+		`,
+	})
+
+	// Branches: range, if, &&, ||, two cases, one select case and the if of the literal
+	expected := map[string]int{"Empty": 1, "Straight": 1, "Branches": 9}
+	for _, fn := range findNodes(modules["shapes.go"], ast.Function) {
+		name := fn.Attributes()["name"].(string)
+		if got := fn.Attributes()["complexity"]; got != expected[name] {
+			t.Errorf("Expected %s complexity %d, got %v", name, expected[name], got)
+		}
+	}
+}
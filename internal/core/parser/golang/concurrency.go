@@ -0,0 +1,341 @@
+package goparser
+
+import (
+	goast "go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// The kind of concurrency pattern found in a function body
+type ConcurrencyPatternKind string
+
+const (
+	ConcurrencyGoroutine         ConcurrencyPatternKind = "goroutine"          // go statement
+	ConcurrencyChannelMake       ConcurrencyPatternKind = "channel_make"       // make(chan T)
+	ConcurrencyChannelSend       ConcurrencyPatternKind = "channel_send"       // ch <- v
+	ConcurrencyChannelReceive    ConcurrencyPatternKind = "channel_receive"    // <-ch
+	ConcurrencySelect            ConcurrencyPatternKind = "select"             // select statement
+	ConcurrencyMutex             ConcurrencyPatternKind = "mutex"              // sync.Mutex Lock
+	ConcurrencyRWMutex           ConcurrencyPatternKind = "rwmutex"            // sync.RWMutex Lock/RLock
+	ConcurrencyWaitGroup         ConcurrencyPatternKind = "waitgroup"          // sync.WaitGroup Wait/Go
+	ConcurrencyOnce              ConcurrencyPatternKind = "once"               // sync.Once Do
+	ConcurrencyCond              ConcurrencyPatternKind = "cond"               // sync.Cond Wait/Signal/Broadcast
+	ConcurrencyErrGroup          ConcurrencyPatternKind = "errgroup"           // errgroup.Group Go / errgroup.WithContext
+	ConcurrencyAtomic            ConcurrencyPatternKind = "atomic"             // sync/atomic operations
+	ConcurrencyContextPropagate  ConcurrencyPatternKind = "context_propagate"  // context parameter passed to a callee
+	ConcurrencyContextBackground ConcurrencyPatternKind = "context_background" // context.Background/TODO despite having a context parameter
+	ConcurrencyContextRoot       ConcurrencyPatternKind = "context_root"       // context.Background/TODO without a context parameter
+)
+
+// A concurrency pattern occurrence in a function body
+type ConcurrencyPattern struct {
+	Kind     ConcurrencyPatternKind
+	Position ast.Position
+	Detail   string // The callee, channel type or synchronized expression involved
+}
+
+// Sync primitives by import path and type name
+var syncPrimitives = map[string]map[string]bool{
+	"sync": {
+		"Mutex": true, "RWMutex": true, "WaitGroup": true, "Once": true,
+		"Cond": true, "Map": true, "Pool": true,
+	},
+	"golang.org/x/sync/errgroup": {"Group": true},
+}
+
+// Finds the concurrency patterns in a function body
+func (p *Parser) concurrencyPatterns(fn *goast.FuncDecl, contextParam string) []*ConcurrencyPattern {
+	patterns := make([]*ConcurrencyPattern, 0)
+	if fn.Body == nil {
+		return patterns
+	}
+
+	add := func(kind ConcurrencyPatternKind, node goast.Node, detail string) {
+		patterns = append(patterns, &ConcurrencyPattern{
+			Kind:     kind,
+			Position: p.position(node.Pos()),
+			Detail:   detail,
+		})
+	}
+
+	// Track local names of sync primitives and contexts derived from the context parameter
+	locals := make(map[string]string)
+	for name, syncType := range p.syncVars {
+		locals[name] = syncType
+	}
+	if fn.Type.Params != nil {
+		for _, field := range fn.Type.Params.List {
+			if syncType := syncTypeName(field.Type, p.imports); syncType != "" {
+				for _, name := range field.Names {
+					locals[name.Name] = syncType
+				}
+			}
+		}
+	}
+	contexts := make(map[string]bool)
+	if contextParam != "" && contextParam != "_" {
+		contexts[contextParam] = true
+	}
+
+	goast.Inspect(fn.Body, func(n goast.Node) bool {
+		switch s := n.(type) {
+		case *goast.GoStmt:
+			detail := p.calleeName(s.Call)
+			if _, ok := s.Call.Fun.(*goast.FuncLit); ok {
+				detail = "func literal"
+			}
+			add(ConcurrencyGoroutine, s, detail)
+
+		case *goast.SelectStmt:
+			add(ConcurrencySelect, s, "")
+
+		case *goast.SendStmt:
+			add(ConcurrencyChannelSend, s, types.ExprString(s.Chan))
+
+		case *goast.UnaryExpr:
+			if s.Op == token.ARROW {
+				add(ConcurrencyChannelReceive, s, types.ExprString(s.X))
+			}
+
+		case *goast.ValueSpec:
+			if syncType := syncTypeName(s.Type, p.imports); syncType != "" {
+				for _, name := range s.Names {
+					locals[name.Name] = syncType
+				}
+			}
+
+		case *goast.AssignStmt:
+			p.trackAssignment(s, locals, contexts)
+
+		case *goast.CallExpr:
+			p.recordCall(s, locals, contexts, contextParam != "", add)
+		}
+		return true
+	})
+
+	return patterns
+}
+
+// Tracks sync primitives and derived contexts introduced by an assignment
+func (p *Parser) trackAssignment(s *goast.AssignStmt, locals map[string]string, contexts map[string]bool) {
+	if len(s.Rhs) != 1 || len(s.Lhs) == 0 {
+		return
+	}
+	first, ok := s.Lhs[0].(*goast.Ident)
+	if !ok {
+		return
+	}
+
+	switch rhs := s.Rhs[0].(type) {
+	case *goast.CompositeLit:
+		if syncType := syncTypeName(rhs.Type, p.imports); syncType != "" {
+			locals[first.Name] = syncType
+		}
+	case *goast.UnaryExpr:
+		if lit, ok := rhs.X.(*goast.CompositeLit); ok && rhs.Op == token.AND {
+			if syncType := syncTypeName(lit.Type, p.imports); syncType != "" {
+				locals[first.Name] = syncType
+			}
+		}
+	case *goast.CallExpr:
+		callee := p.calleeName(rhs)
+		switch {
+		case callee == "golang.org/x/sync/errgroup.WithContext":
+			locals[first.Name] = "errgroup.Group"
+			if len(s.Lhs) > 1 {
+				if ctx, ok := s.Lhs[1].(*goast.Ident); ok {
+					contexts[ctx.Name] = true
+				}
+			}
+		case strings.HasPrefix(callee, "context.With") && len(rhs.Args) > 0:
+			// ctx, cancel := context.WithTimeout(ctx, ...) keeps the parent context flowing
+			if parent, ok := rhs.Args[0].(*goast.Ident); ok && contexts[parent.Name] {
+				contexts[first.Name] = true
+			}
+		}
+	}
+}
+
+// Records the concurrency patterns of a single call
+func (p *Parser) recordCall(call *goast.CallExpr, locals map[string]string, contexts map[string]bool, hasContextParam bool, add func(ConcurrencyPatternKind, goast.Node, string)) {
+	callee := p.calleeName(call)
+
+	switch {
+	case callee == "make" && len(call.Args) > 0:
+		if _, ok := call.Args[0].(*goast.ChanType); ok {
+			detail := typeToTypeInfo(call.Args[0]).String()
+			if len(call.Args) > 1 {
+				detail += " (buffered)"
+			}
+			add(ConcurrencyChannelMake, call, detail)
+		}
+	case strings.HasPrefix(callee, "sync/atomic."):
+		add(ConcurrencyAtomic, call, strings.TrimPrefix(callee, "sync/"))
+	case callee == "golang.org/x/sync/errgroup.WithContext":
+		add(ConcurrencyErrGroup, call, "errgroup.WithContext")
+	case callee == "context.Background" || callee == "context.TODO":
+		if hasContextParam {
+			add(ConcurrencyContextBackground, call, callee)
+		} else {
+			add(ConcurrencyContextRoot, call, callee)
+		}
+	}
+
+	// Method calls on sync primitives
+	if sel, ok := call.Fun.(*goast.SelectorExpr); ok {
+		if kind := syncMethodKind(p.syncTypeOf(sel.X, locals), sel.Sel.Name); kind != "" {
+			add(kind, call, types.ExprString(sel))
+		}
+	}
+
+	// Context propagation to callees
+	for _, arg := range call.Args {
+		if ident, ok := arg.(*goast.Ident); ok && contexts[ident.Name] {
+			add(ConcurrencyContextPropagate, call, callee)
+			break
+		}
+	}
+}
+
+// Returns the sync primitive type of an expression, using local declarations and struct fields
+func (p *Parser) syncTypeOf(expr goast.Expr, locals map[string]string) string {
+	switch e := expr.(type) {
+	case *goast.Ident:
+		return locals[e.Name]
+	case *goast.SelectorExpr:
+		return p.syncFields[e.Sel.Name]
+	case *goast.StarExpr:
+		return p.syncTypeOf(e.X, locals)
+	case *goast.ParenExpr:
+		return p.syncTypeOf(e.X, locals)
+	}
+	return ""
+}
+
+// Returns the pattern kind of a method called on a sync primitive, or "" if it is not a sync operation
+func syncMethodKind(syncType, method string) ConcurrencyPatternKind {
+	switch syncType {
+	case "sync.Mutex":
+		if method == "Lock" || method == "TryLock" {
+			return ConcurrencyMutex
+		}
+	case "sync.RWMutex":
+		if method == "Lock" || method == "RLock" || method == "TryLock" || method == "TryRLock" {
+			return ConcurrencyRWMutex
+		}
+	case "sync.WaitGroup":
+		if method == "Wait" || method == "Go" {
+			return ConcurrencyWaitGroup
+		}
+	case "sync.Once":
+		if method == "Do" {
+			return ConcurrencyOnce
+		}
+	case "sync.Cond":
+		if method == "Wait" || method == "Signal" || method == "Broadcast" {
+			return ConcurrencyCond
+		}
+	case "errgroup.Group":
+		if method == "Go" {
+			return ConcurrencyErrGroup
+		}
+	case "":
+		// Unknown receiver: Lock and RLock are unambiguous enough on their own
+		switch method {
+		case "Lock":
+			return ConcurrencyMutex
+		case "RLock":
+			return ConcurrencyRWMutex
+		}
+	}
+	return ""
+}
+
+// Returns the sync primitive type name (e.g. "sync.Mutex", "errgroup.Group") of a type expression, or ""
+func syncTypeName(expr goast.Expr, imports map[string]string) string {
+	if star, ok := expr.(*goast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*goast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	x, ok := sel.X.(*goast.Ident)
+	if !ok {
+		return ""
+	}
+	path := imports[x.Name]
+	if syncPrimitives[path][sel.Sel.Name] {
+		return path[strings.LastIndex(path, "/")+1:] + "." + sel.Sel.Name
+	}
+	if path == "sync/atomic" {
+		return "atomic." + sel.Sel.Name
+	}
+	return ""
+}
+
+// Maps struct field names and package-level variable names to the sync primitive types they hold
+func syncTypes(files []*goast.File) (fields, vars map[string]string) {
+	fields = make(map[string]string)
+	vars = make(map[string]string)
+	for _, file := range files {
+		imports := importNames(file)
+
+		// Package-level variables
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*goast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*goast.ValueSpec)
+				if syncType := syncTypeName(vs.Type, imports); syncType != "" {
+					for _, name := range vs.Names {
+						vars[name.Name] = syncType
+					}
+				}
+			}
+		}
+
+		// Struct fields
+		goast.Inspect(file, func(n goast.Node) bool {
+			st, ok := n.(*goast.StructType)
+			if !ok || st.Fields == nil {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				syncType := syncTypeName(field.Type, imports)
+				if syncType == "" {
+					continue
+				}
+				if len(field.Names) == 0 {
+					// Embedded sync primitive (e.g. struct{ sync.Mutex })
+					fields[syncType[strings.Index(syncType, ".")+1:]] = syncType
+				}
+				for _, name := range field.Names {
+					fields[name.Name] = syncType
+				}
+			}
+			return true
+		})
+	}
+	return fields, vars
+}
+
+// Returns the name and position of the context.Context parameter ("_" if unnamed),
+// and whether it is the first parameter
+func contextParameter(params []*TypeInfo, names []string) (string, bool) {
+	for i, param := range params {
+		if param.Kind == "basic" && param.Name == "context.Context" {
+			name := names[i]
+			if name == "" {
+				name = "_"
+			}
+			return name, i == 0
+		}
+	}
+	return "", false
+}
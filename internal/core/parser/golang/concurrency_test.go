@@ -0,0 +1,159 @@
+package goparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+func TestConcurrencyPatterns(t *testing.T) {
+	src := `
+	package example
+
+	import (
+		"context"
+		"sync"
+		"sync/atomic"
+		"time"
+
+		"golang.org/x/sync/errgroup"
+	)
+
+	type Cache struct {
+		mu    sync.RWMutex
+		once  sync.Once
+		items map[string]string
+		hits  atomic.Int64
+	}
+
+	func (c *Cache) Get(key string) string {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		c.once.Do(c.init)
+		return c.items[key]
+	}
+
+	func (c *Cache) init() {}
+
+	func Fetch(ctx context.Context, urls []string) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		g, gctx := errgroup.WithContext(ctx)
+		for _, url := range urls {
+			g.Go(func() error { return get(gctx, url) })
+		}
+		return g.Wait()
+	}
+
+	func get(ctx context.Context, url string) error { return nil }
+
+	func Pipeline(in <-chan int, out chan<- int, _ context.Context) {
+		var wg sync.WaitGroup
+		done := make(chan struct{})
+		results := make(chan int, 10)
+		go func() {
+			for v := range in {
+				results <- v
+			}
+			close(done)
+		}()
+		wg.Wait()
+		select {
+		case v := <-results:
+			out <- v
+		case <-done:
+		}
+		_ = context.Background()
+	}
+
+	func Main() {
+		_ = Fetch(context.Background(), nil)
+	}
+	`
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+	if err := os.WriteFile(testFile, []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	root, err := goparser.New().ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	patterns := make(map[string]map[goparser.ConcurrencyPatternKind]int)
+	contexts := make(map[string][2]any)
+	for _, fn := range append(findNodes(root, ast.Function), findNodes(root, ast.Method)...) {
+		attrs := fn.Attributes()
+		found, ok := attrs["concurrency_patterns"].([]*goparser.ConcurrencyPattern)
+		if !ok {
+			t.Fatalf("Expected concurrency_patterns to be []*ConcurrencyPattern, got %T", attrs["concurrency_patterns"])
+		}
+		counts := make(map[goparser.ConcurrencyPatternKind]int)
+		for _, pattern := range found {
+			counts[pattern.Kind]++
+			if pattern.Position.Line == 0 || pattern.Position.Filename != testFile {
+				t.Errorf("Pattern %s has invalid position %v", pattern.Kind, pattern.Position)
+			}
+		}
+		name := attrs["name"].(string)
+		patterns[name] = counts
+		contexts[name] = [2]any{attrs["context_param"], attrs["context_first"]}
+	}
+
+	expected := map[string]map[goparser.ConcurrencyPatternKind]int{
+		"Get": {
+			goparser.ConcurrencyRWMutex: 1,
+			goparser.ConcurrencyOnce:    1,
+		},
+		"init": {},
+		"Fetch": {
+			goparser.ConcurrencyErrGroup:         2,
+			goparser.ConcurrencyContextPropagate: 3,
+		},
+		"get": {},
+		"Pipeline": {
+			goparser.ConcurrencyWaitGroup:         1,
+			goparser.ConcurrencyChannelMake:       2,
+			goparser.ConcurrencyGoroutine:         1,
+			goparser.ConcurrencyChannelSend:       2,
+			goparser.ConcurrencyChannelReceive:    2,
+			goparser.ConcurrencySelect:            1,
+			goparser.ConcurrencyContextBackground: 1,
+		},
+		"Main": {
+			goparser.ConcurrencyContextRoot: 1,
+		},
+	}
+
+	for name, kinds := range expected {
+		got := patterns[name]
+		if len(got) != len(kinds) {
+			t.Errorf("%s: expected patterns %v, got %v", name, kinds, got)
+			continue
+		}
+		for kind, count := range kinds {
+			if got[kind] != count {
+				t.Errorf("%s: expected %d %s patterns, got %d", name, count, kind, got[kind])
+			}
+		}
+	}
+
+	t.Run("ContextParameter", func(t *testing.T) {
+		expected := map[string][2]any{
+			"Fetch":    {"ctx", true},
+			"get":      {"ctx", true},
+			"Pipeline": {"_", false},
+			"Main":     {"", false},
+		}
+		for name, want := range expected {
+			if got := contexts[name]; got != want {
+				t.Errorf("%s: expected context param/first %v, got %v", name, want, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package goparser
+
+import (
+	goast "go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// The kind of error-handling pattern found in a function body
+type ErrorPatternKind string
+
+const (
+	ErrorWrap            ErrorPatternKind = "wrap"             // fmt.Errorf with %w (or errors.Wrap)
+	ErrorFormat          ErrorPatternKind = "format"           // fmt.Errorf without %w
+	ErrorNew             ErrorPatternKind = "new"              // errors.New inside a function
+	ErrorIs              ErrorPatternKind = "is"               // errors.Is
+	ErrorAs              ErrorPatternKind = "as"               // errors.As
+	ErrorSentinelCompare ErrorPatternKind = "sentinel_compare" // err == ErrX
+	ErrorPropagate       ErrorPatternKind = "propagate"        // return err unchanged
+	ErrorIgnored         ErrorPatternKind = "ignored"          // error assigned to _
+	ErrorUnchecked       ErrorPatternKind = "unchecked"        // error-returning call used as a statement, deferred or run as a goroutine
+	ErrorPanic           ErrorPatternKind = "panic"            // panic(...)
+)
+
+// An error-handling pattern occurrence in a function body
+type ErrorPattern struct {
+	Kind     ErrorPatternKind
+	Position ast.Position
+	Detail   string // The callee, format string or sentinel involved
+}
+
+// Functions that wrap errors from the popular github.com/pkg/errors package
+var pkgErrorsWrappers = map[string]bool{
+	"github.com/pkg/errors.Wrap":        true,
+	"github.com/pkg/errors.Wrapf":       true,
+	"github.com/pkg/errors.WithMessage": true,
+	"github.com/pkg/errors.WithStack":   true,
+}
+
+// Finds the error-handling patterns in a function body
+func (p *Parser) errorPatterns(body *goast.BlockStmt) []*ErrorPattern {
+	patterns := make([]*ErrorPattern, 0)
+	if body == nil {
+		return patterns
+	}
+
+	add := func(kind ErrorPatternKind, node goast.Node, detail string) {
+		patterns = append(patterns, &ErrorPattern{
+			Kind:     kind,
+			Position: p.position(node.Pos()),
+			Detail:   detail,
+		})
+	}
+
+	goast.Inspect(body, func(n goast.Node) bool {
+		switch s := n.(type) {
+		case *goast.CallExpr:
+			callee := p.calleeName(s)
+			switch {
+			case callee == "fmt.Errorf":
+				format := stringLiteral(s.Args)
+				if strings.Contains(format, "%w") {
+					add(ErrorWrap, s, format)
+				} else {
+					add(ErrorFormat, s, format)
+				}
+			case pkgErrorsWrappers[callee]:
+				add(ErrorWrap, s, callee)
+			case callee == "errors.New" || callee == "github.com/pkg/errors.New":
+				add(ErrorNew, s, stringLiteral(s.Args))
+			case callee == "errors.Is":
+				add(ErrorIs, s, exprString(s.Args, 1))
+			case callee == "errors.As":
+				add(ErrorAs, s, exprString(s.Args, 1))
+			case callee == "panic":
+				add(ErrorPanic, s, exprString(s.Args, 0))
+			}
+
+		case *goast.ExprStmt:
+			if call, ok := s.X.(*goast.CallExpr); ok && p.resultIsError(call, -1) {
+				add(ErrorUnchecked, s, p.calleeName(call))
+			}
+
+		case *goast.DeferStmt:
+			if p.resultIsError(s.Call, -1) {
+				add(ErrorUnchecked, s, p.calleeName(s.Call))
+			}
+
+		case *goast.GoStmt:
+			if p.resultIsError(s.Call, -1) {
+				add(ErrorUnchecked, s, p.calleeName(s.Call))
+			}
+
+		case *goast.AssignStmt:
+			if len(s.Rhs) != 1 {
+				break
+			}
+			call, ok := s.Rhs[0].(*goast.CallExpr)
+			if !ok {
+				break
+			}
+			for i, lhs := range s.Lhs {
+				if ident, ok := lhs.(*goast.Ident); ok && ident.Name == "_" && p.resultIsError(call, i) {
+					add(ErrorIgnored, s, p.calleeName(call))
+				}
+			}
+
+		case *goast.BinaryExpr:
+			if s.Op != token.EQL && s.Op != token.NEQ {
+				break
+			}
+			if sentinel := sentinelName(s.X); sentinel != "" && p.isErrorExpr(s.Y) {
+				add(ErrorSentinelCompare, s, sentinel)
+			} else if sentinel := sentinelName(s.Y); sentinel != "" && p.isErrorExpr(s.X) {
+				add(ErrorSentinelCompare, s, sentinel)
+			}
+
+		case *goast.ReturnStmt:
+			if len(s.Results) == 0 {
+				break
+			}
+			if ident, ok := s.Results[len(s.Results)-1].(*goast.Ident); ok && ident.Name != "nil" && p.isErrorExpr(ident) {
+				add(ErrorPropagate, s, ident.Name)
+			}
+		}
+		return true
+	})
+
+	return patterns
+}
+
+// Returns the name of the called function, qualified by import path for package functions
+// (e.g. "fmt.Errorf", "github.com/pkg/errors.Wrap"), or the plain name for local functions
+func (p *Parser) calleeName(call *goast.CallExpr) string {
+	switch fun := call.Fun.(type) {
+	case *goast.Ident:
+		return fun.Name
+	case *goast.SelectorExpr:
+		if x, ok := fun.X.(*goast.Ident); ok {
+			if path, ok := p.imports[x.Name]; ok && !p.isLocalObject(x) {
+				return path + "." + fun.Sel.Name
+			}
+			return x.Name + "." + fun.Sel.Name
+		}
+		return fun.Sel.Name
+	}
+	return ""
+}
+
+// Checks if an identifier refers to a local object rather than an imported package
+func (p *Parser) isLocalObject(ident *goast.Ident) bool {
+	obj := p.info.Uses[ident]
+	if obj == nil {
+		return false
+	}
+	_, isPkg := obj.(*types.PkgName)
+	return !isPkg
+}
+
+// Checks if result i of a call is an error. With i < 0, checks whether any result is an error.
+// Calls whose result types are unknown (e.g. to packages outside the standard library) are not
+// reported as errors, since their results cannot be told apart.
+func (p *Parser) resultIsError(call *goast.CallExpr, i int) bool {
+	tv, ok := p.info.Types[call]
+	if !ok || tv.Type == nil || isInvalidType(tv.Type) {
+		return false
+	}
+
+	var results []types.Type
+	if tuple, ok := tv.Type.(*types.Tuple); ok {
+		for j := range tuple.Len() {
+			results = append(results, tuple.At(j).Type())
+		}
+	} else {
+		results = []types.Type{tv.Type}
+	}
+
+	if i < 0 {
+		for _, result := range results {
+			if isErrorType(result) {
+				return true
+			}
+		}
+		return false
+	}
+	return i < len(results) && isErrorType(results[i])
+}
+
+// Checks if an expression is an error value (by type, or by the conventional "err" name)
+func (p *Parser) isErrorExpr(expr goast.Expr) bool {
+	if tv, ok := p.info.Types[expr]; ok && tv.Type != nil && !isInvalidType(tv.Type) {
+		return isErrorType(tv.Type)
+	}
+	if ident, ok := expr.(*goast.Ident); ok {
+		if obj := p.info.Uses[ident]; obj != nil && obj.Type() != nil && !isInvalidType(obj.Type()) {
+			return isErrorType(obj.Type())
+		}
+		return ident.Name == "err" || strings.HasSuffix(ident.Name, "Err")
+	}
+	return false
+}
+
+// Checks if a type is the builtin error interface
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// Checks if a type could not be determined by the type checker
+func isInvalidType(t types.Type) bool {
+	basic, ok := t.(*types.Basic)
+	return ok && basic.Kind() == types.Invalid
+}
+
+// Returns the name of a sentinel error expression (e.g. ErrNotFound, io.EOF), or ""
+func sentinelName(expr goast.Expr) string {
+	var name, full string
+	switch e := expr.(type) {
+	case *goast.Ident:
+		name, full = e.Name, e.Name
+	case *goast.SelectorExpr:
+		if x, ok := e.X.(*goast.Ident); ok {
+			name, full = e.Sel.Name, x.Name+"."+e.Sel.Name
+		}
+	}
+	if strings.HasPrefix(name, "Err") || name == "EOF" {
+		return full
+	}
+	return ""
+}
+
+// Returns the value of the first argument if it is a string literal
+func stringLiteral(args []goast.Expr) string {
+	if len(args) == 0 {
+		return ""
+	}
+	if lit, ok := args[0].(*goast.BasicLit); ok && lit.Kind == token.STRING {
+		if value, err := strconv.Unquote(lit.Value); err == nil {
+			return value
+		}
+	}
+	return ""
+}
+
+// Returns a short source representation of argument i
+func exprString(args []goast.Expr, i int) string {
+	if i >= len(args) {
+		return ""
+	}
+	return types.ExprString(args[i])
+}
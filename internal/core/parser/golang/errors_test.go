@@ -0,0 +1,240 @@
+package goparser_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+func TestErrorPatterns(t *testing.T) {
+	src := `
+	package example
+
+	import (
+		"errors"
+		"fmt"
+		"io"
+		"os"
+	)
+
+	var ErrNotFound = errors.New("not found")
+
+	var defaultName = "example"
+
+	type Store struct{}
+
+	func (s *Store) Close() error { return nil }
+
+	func (s *Store) Load(name string) ([]byte, error) {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", name, err)
+		}
+		if len(data) == 0 {
+			return nil, fmt.Errorf("empty file %s", name)
+		}
+		return data, nil
+	}
+
+	func (s *Store) Find(name string) error {
+		_, err := s.Load(name)
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		if err == io.EOF {
+			panic("unexpected EOF")
+		}
+		return err
+	}
+
+	func Cleanup(s *Store) {
+		s.Close()
+		_ = s.Close()
+		n, _ := fmt.Println("done")
+		_ = n
+	}
+	`
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+	if err := os.WriteFile(testFile, []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	root, err := goparser.New().ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	patterns := make(map[string]map[goparser.ErrorPatternKind]int)
+	for _, fn := range append(findNodes(root, ast.Function), findNodes(root, ast.Method)...) {
+		attrs := fn.Attributes()
+		found, ok := attrs["error_patterns"].([]*goparser.ErrorPattern)
+		if !ok {
+			t.Fatalf("Expected error_patterns to be []*ErrorPattern, got %T", attrs["error_patterns"])
+		}
+		counts := make(map[goparser.ErrorPatternKind]int)
+		for _, pattern := range found {
+			counts[pattern.Kind]++
+			if pattern.Position.Line == 0 || pattern.Position.Filename != testFile {
+				t.Errorf("Pattern %s has invalid position %v", pattern.Kind, pattern.Position)
+			}
+		}
+		patterns[attrs["name"].(string)] = counts
+	}
+
+	expected := map[string]map[goparser.ErrorPatternKind]int{
+		"Close": {},
+		"Load": {
+			goparser.ErrorWrap:   1,
+			goparser.ErrorFormat: 1,
+		},
+		"Find": {
+			goparser.ErrorIs:              1,
+			goparser.ErrorSentinelCompare: 1,
+			goparser.ErrorPanic:           1,
+			goparser.ErrorPropagate:       1,
+		},
+		"Cleanup": {
+			goparser.ErrorUnchecked: 1,
+			goparser.ErrorIgnored:   2,
+		},
+	}
+
+	for name, kinds := range expected {
+		got := patterns[name]
+		if len(got) != len(kinds) {
+			t.Errorf("%s: expected patterns %v, got %v", name, kinds, got)
+			continue
+		}
+		for kind, count := range kinds {
+			if got[kind] != count {
+				t.Errorf("%s: expected %d %s patterns, got %d", name, count, kind, got[kind])
+			}
+		}
+	}
+
+	t.Run("StandardLibrary", func(t *testing.T) {
+		src := `
+		package example
+
+		import (
+			"os"
+			"strings"
+			"sync"
+
+			"example.com/third"
+		)
+
+		func Stdlib(f *os.File, cache *sync.Map, path string) {
+			os.Remove(path)
+			defer f.Close()
+			_ = os.Remove(path)
+			v, _ := cache.Load(path)
+			s, _ := strings.CutPrefix(path, "/")
+			_, _ = v, s
+		}
+
+		func Unknown() {
+			third.Close()
+			v, _ := third.Load()
+			_ = v
+		}
+		`
+		file := filepath.Join(t.TempDir(), "stdlib.go")
+		if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		root, err := goparser.New().ParseFile(file)
+		if err != nil {
+			t.Fatalf("Failed to parse: %v", err)
+		}
+
+		// Calls to packages outside the standard library have unknown results and are not reported
+		expected := map[string][]string{
+			"Stdlib":  {"unchecked os.Remove", "unchecked f.Close", "ignored os.Remove"},
+			"Unknown": nil,
+		}
+		for _, fn := range findNodes(root, ast.Function) {
+			name := fn.Attributes()["name"].(string)
+			var got []string
+			for _, pattern := range fn.Attributes()["error_patterns"].([]*goparser.ErrorPattern) {
+				got = append(got, string(pattern.Kind)+" "+pattern.Detail)
+			}
+			if !slices.Equal(got, expected[name]) {
+				t.Errorf("%s: expected patterns %v, got %v", name, expected[name], got)
+			}
+		}
+	})
+
+	t.Run("SentinelErrors", func(t *testing.T) {
+		for _, v := range findNodes(root, ast.Variable) {
+			attrs := v.Attributes()
+			isSentinel := attrs["is_sentinel_error"].(bool)
+			if expected := attrs["name"] == "ErrNotFound"; isSentinel != expected {
+				t.Errorf("Variable %s: expected is_sentinel_error=%v, got %v", attrs["name"], expected, isSentinel)
+			}
+		}
+	})
+}
+
+func TestErrorPatterns_WithoutToolchain(t *testing.T) {
+	// The toolchain is looked up once per process, so the check runs in a copy of the test
+	// binary whose GOROOT holds no Go installation
+	if os.Getenv("CODEDNA_TEST_NO_TOOLCHAIN") != "1" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestErrorPatterns_WithoutToolchain$", "-test.v")
+		cmd.Env = append(os.Environ(), "CODEDNA_TEST_NO_TOOLCHAIN=1", "GOROOT="+t.TempDir())
+		out, err := cmd.CombinedOutput()
+		if err != nil || !strings.Contains(string(out), "--- PASS") {
+			t.Fatalf("Test without toolchain failed: %v\n%s", err, out)
+		}
+		return
+	}
+
+	if goparser.StdlibTypes() {
+		t.Fatal("Expected standard library types to be unavailable")
+	}
+
+	src := `
+	package example
+
+	import (
+		"os"
+		"strings"
+	)
+
+	func Remove(path string) error {
+		os.Remove(path)
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		s, _ := strings.CutPrefix(path, "/")
+		_ = s
+		return nil
+	}
+	`
+	file := filepath.Join(t.TempDir(), "stdlib.go")
+	if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// Parsing still succeeds, with standard library calls left untyped and so not reported
+	for range 2 {
+		root, err := goparser.New().ParseFile(file)
+		if err != nil {
+			t.Fatalf("Failed to parse: %v", err)
+		}
+		fn := findNodes(root, ast.Function)[0]
+		for _, pattern := range fn.Attributes()["error_patterns"].([]*goparser.ErrorPattern) {
+			if pattern.Kind == goparser.ErrorUnchecked || pattern.Kind == goparser.ErrorIgnored {
+				t.Errorf("Expected untyped calls not to be reported, got %s %s", pattern.Kind, pattern.Detail)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package goparser
+
+import (
+	goast "go/ast"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// The standard header of generated Go files (see https://go.dev/s/generatedcode)
+var generatedHeader = regexp.MustCompile(`^// Code generated (.*) DO NOT EDIT\.$`)
+
+// Reports whether a file was generated, and by which tool when known (e.g. "protoc-gen-go").
+// Files are generated when they have the standard header before the package clause, or when
+// their name is one given by code generators to their output: *.pb.go files and mocks
+// (mock_*.go, *_mock.go).
+func generatedFile(filename string, file *goast.File) (bool, string) {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			match := generatedHeader.FindStringSubmatch(comment.Text)
+			if match == nil {
+				continue
+			}
+			if by, ok := strings.CutPrefix(match[1], "by "); ok {
+				if fields := strings.Fields(by); len(fields) > 0 {
+					return true, strings.TrimRight(fields[0], ".,;:")
+				}
+			}
+			return true, ""
+		}
+	}
+
+	base := filepath.Base(filename)
+	switch {
+	case strings.HasSuffix(base, ".pb.go"):
+		return true, ""
+	case strings.HasPrefix(base, "mock_"), strings.HasSuffix(base, "_mock.go"), strings.HasSuffix(base, "_mock_test.go"):
+		return true, ""
+	}
+	return false, ""
+}
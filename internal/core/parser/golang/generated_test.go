@@ -0,0 +1,88 @@
+package goparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	goparser "codedna/internal/core/parser/golang"
+)
+
+func TestGeneratedFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		filename  string
+		src       string
+		generated bool
+		generator string
+	}{
+		{
+			name:      "Header",
+			filename:  "users.go",
+			src:       "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: users.proto\n\npackage users\n",
+			generated: true,
+			generator: "protoc-gen-go",
+		},
+		{
+			name:      "HeaderWithoutGenerator",
+			filename:  "tables.go",
+			src:       "// Copyright 2024 The Authors.\n\n// Code generated from tables.txt; DO NOT EDIT.\n\npackage tables\n",
+			generated: true,
+		},
+		{
+			name:     "HeaderAfterPackage",
+			filename: "notes.go",
+			src:      "package notes\n\n// Code generated by hand. DO NOT EDIT.\n",
+		},
+		{
+			name:     "NotAHeader",
+			filename: "codegen.go",
+			src:      "// Code generated files must not be edited.\npackage codegen\n",
+		},
+		{
+			name:      "ProtobufFile",
+			filename:  "users.pb.go",
+			src:       "package users\n",
+			generated: true,
+		},
+		{
+			name:      "MockFile",
+			filename:  "mock_store.go",
+			src:       "package store\n",
+			generated: true,
+		},
+		{
+			name:      "MockSuffix",
+			filename:  "store_mock.go",
+			src:       "package store\n",
+			generated: true,
+		},
+		{
+			name:     "Handwritten",
+			filename: "store.go",
+			src:      "// Package store persists users.\npackage store\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.filename)
+			if err := os.WriteFile(path, []byte(tt.src), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			root, err := goparser.New().ParseFile(path)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			attrs := root.Attributes()
+			if attrs["is_generated"] != tt.generated {
+				t.Errorf("Expected is_generated %v, got %v", tt.generated, attrs["is_generated"])
+			}
+			generator, _ := attrs["generator"].(string)
+			if generator != tt.generator {
+				t.Errorf("Expected generator %q, got %q", tt.generator, generator)
+			}
+		})
+	}
+}
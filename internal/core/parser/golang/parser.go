@@ -2,29 +2,62 @@
 package goparser
 
 import (
+	"fmt"
 	goast "go/ast"
+	"go/importer"
 	"go/parser"
 	"go/token"
 	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"codedna/internal/core/parser/ast"
 )
 
 // TypeInfo represents a type in a structural way
 type TypeInfo struct {
-	Kind      string    // The kind of type (e.g. "basic", "pointer", "array", "map", "chan", "interface")
-	Name      string    // The name of the type (e.g. "int", "string", "MyStruct")
-	ElemType  *TypeInfo // For pointer, array, chan types
-	KeyType   *TypeInfo // For map types
-	ValueType *TypeInfo // For map types
+	Kind      string       // The kind of type (e.g. "basic", "pointer", "array", "map", "chan", "func", "struct", "interface")
+	Name      string       // The name of the type (e.g. "int", "string", "MyStruct")
+	ElemType  *TypeInfo    // For pointer, array, chan types
+	KeyType   *TypeInfo    // For map types
+	ValueType *TypeInfo    // For map types
+	Len       int          // For array types (-1 if the length is not a literal)
+	Dir       string       // For chan types ("" for bidirectional, ChanSend or ChanRecv)
+	Args      []*TypeInfo  // For instantiated generic types (e.g. the int of Stack[int])
+	Params    []*TypeInfo  // For func types (a variadic parameter is a slice)
+	Results   []*TypeInfo  // For func types
+	Variadic  bool         // For func types whose last parameter is variadic
+	Fields    []*FieldInfo // For struct types, and the methods and embedded types of interfaces
 }
 
+// A field of a struct type or a method of an interface type (Name is "" for embedded types)
+type FieldInfo struct {
+	Name string
+	Type *TypeInfo
+}
+
+// Channel directions
+const (
+	ChanSend = "send" // chan<- T
+	ChanRecv = "recv" // <-chan T
+)
+
 // Implements the parser.Parser interface for Go
 type Parser struct {
-	fset *token.FileSet
-	info *types.Info
-	conf types.Config
+	fset       *token.FileSet
+	info       *types.Info
+	conf       types.Config
+	pkg        *types.Package    // The type-checked package being converted
+	imports    map[string]string // Local import name -> import path for the file being converted
+	testFile   bool              // Whether the file being converted holds tests
+	todos      []*todoComment    // TODO comments of the file being converted
+	syncFields map[string]string // Struct field name -> sync primitive type for the package being converted
+	syncVars   map[string]string // Package-level variable name -> sync primitive type for the package being converted
+	overlay    map[string][]byte // Absolute file path -> contents parsed instead of the file on disk
 }
 
 // Creates a new Go parser
@@ -32,17 +65,79 @@ func New() *Parser {
 	return &Parser{
 		fset: token.NewFileSet(),
 		info: &types.Info{
-			Types: make(map[goast.Expr]types.TypeAndValue),
-			Defs:  make(map[*goast.Ident]types.Object),
-			Uses:  make(map[*goast.Ident]types.Object),
+			Types:      make(map[goast.Expr]types.TypeAndValue),
+			Defs:       make(map[*goast.Ident]types.Object),
+			Uses:       make(map[*goast.Ident]types.Object),
+			Selections: make(map[*goast.SelectorExpr]*types.Selection),
 		},
 		conf: types.Config{
-			Importer: nil,                // We don't need imports for type checking
+			Importer: stdlibImporter{},   // Other imports are left untyped
 			Error:    func(err error) {}, // Ignore type checking errors
 		},
 	}
 }
 
+// Imports standard library packages from the export data of the Go toolchain found through
+// GOROOT, shared by all parsers. Other packages would need the dependencies of the analyzed
+// module, so their imports fail and the code using them is left untyped.
+//
+// Without a toolchain (e.g. a codedna binary run where Go is not installed) every import
+// fails, and standard library calls are left untyped like any other: their error results are
+// not recognized, so fewer error handling patterns are found. StdlibTypes reports which case
+// applies. Imported packages and failures are cached for the life of the process, since
+// loading export data runs the go command.
+type stdlibImporter struct{}
+
+// Package imported to check that the toolchain is available
+const toolchainProbe = "errors"
+
+// The result of importing a standard library package
+type stdlibImport struct {
+	pkg *types.Package
+	err error
+}
+
+var (
+	stdlibMu       sync.Mutex
+	stdlibPackages = importer.Default()
+	stdlibImports  = make(map[string]stdlibImport) // Import path -> result
+)
+
+func (stdlibImporter) Import(path string) (*types.Package, error) {
+	if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") || path == "C" {
+		return nil, fmt.Errorf("package %s is not in the standard library", path)
+	}
+	stdlibMu.Lock()
+	defer stdlibMu.Unlock()
+	return importStdlib(path)
+}
+
+// Imports a standard library package once, without trying again when the toolchain is
+// missing. The caller holds stdlibMu.
+func importStdlib(path string) (*types.Package, error) {
+	if imported, ok := stdlibImports[path]; ok {
+		return imported.pkg, imported.err
+	}
+	var imported stdlibImport
+	if path != toolchainProbe {
+		if _, err := importStdlib(toolchainProbe); err != nil {
+			imported.err = fmt.Errorf("cannot import %s without the Go toolchain: %w", path, err)
+		}
+	}
+	if imported.err == nil {
+		imported.pkg, imported.err = stdlibPackages.Import(path)
+	}
+	stdlibImports[path] = imported
+	return imported.pkg, imported.err
+}
+
+// Reports whether standard library packages can be imported for type checking, which needs
+// the Go toolchain. Without it, standard library calls are left untyped.
+func StdlibTypes() bool {
+	_, err := stdlibImporter{}.Import(toolchainProbe)
+	return err == nil
+}
+
 func (p *Parser) Language() string {
 	return "Go"
 }
@@ -51,16 +146,33 @@ func (p *Parser) FileExtensions() []string {
 	return []string{".go"}
 }
 
+// Sets the contents of files to parse instead of reading them from disk, such as the unsaved
+// buffers of an editor. Files are keyed by absolute path; only files present on disk are parsed.
+func (p *Parser) SetOverlay(overlay map[string][]byte) {
+	p.overlay = overlay
+}
+
+// Parses a Go file, taking its overlay contents over the file on disk
+func (p *Parser) parseGoFile(filename string) (*goast.File, error) {
+	var src any
+	if abs, err := filepath.Abs(filename); err == nil {
+		if data, ok := p.overlay[abs]; ok {
+			src = data
+		}
+	}
+	return parser.ParseFile(p.fset, filename, src, parser.ParseComments)
+}
+
 func (p *Parser) ParseFile(filename string) (ast.Node, error) {
-	file, err := parser.ParseFile(p.fset, filename, nil, parser.ParseComments)
+	file, err := p.parseGoFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
 	// Type check the file
-	pkg := types.NewPackage(file.Name.Name, "")
+	p.pkg = types.NewPackage(file.Name.Name, "")
 	files := []*goast.File{file}
-	if err := types.NewChecker(&p.conf, p.fset, pkg, p.info).Files(files); err != nil {
+	if err := types.NewChecker(&p.conf, p.fset, p.pkg, p.info).Files(files); err != nil {
 		// Intentionally ignoring type errors:
 		// - Type checking is best-effort for enhanced type information
 		// - Parsing should succeed even with type errors
@@ -68,26 +180,32 @@ func (p *Parser) ParseFile(filename string) (ast.Node, error) {
 		_ = err
 	}
 
+	// Collect struct fields holding sync primitives
+	p.syncFields, p.syncVars = syncTypes(files)
+
 	return p.convertFile(file), nil
 }
 
 func (p *Parser) ParseDir(dir string) ([]ast.Node, error) {
-	pkgs, err := parser.ParseDir(p.fset, dir, nil, parser.ParseComments)
+	pkgs, err := p.parseDir(dir)
 	if err != nil {
 		return nil, err
 	}
 
+	names := make([]string, 0, len(pkgs))
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
 	var nodes []ast.Node
-	for _, pkg := range pkgs {
+	for _, name := range names {
 		// Type check all files in the package together
-		files := make([]*goast.File, 0, len(pkg.Files))
-		for _, file := range pkg.Files {
-			files = append(files, file)
-		}
+		files := pkgs[name]
 
 		// Create a new package and type checker
-		typePkg := types.NewPackage(pkg.Name, "")
-		if err := types.NewChecker(&p.conf, p.fset, typePkg, p.info).Files(files); err != nil {
+		p.pkg = types.NewPackage(name, "")
+		if err := types.NewChecker(&p.conf, p.fset, p.pkg, p.info).Files(files); err != nil {
 			// Intentionally ignoring type errors:
 			// - Type checking is best-effort for enhanced type information
 			// - Parsing should succeed even with type errors
@@ -95,26 +213,80 @@ func (p *Parser) ParseDir(dir string) ([]ast.Node, error) {
 			_ = err
 		}
 
+		// Collect struct fields holding sync primitives across the package
+		p.syncFields, p.syncVars = syncTypes(files)
+
 		// Convert each file to our AST
-		for _, file := range pkg.Files {
+		for _, file := range files {
 			nodes = append(nodes, p.convertFile(file))
 		}
 	}
 	return nodes, nil
 }
 
+// Parses the Go files of a directory, taking overlay contents over the files on disk, and
+// groups them by package name. Like go/parser.ParseDir, it fails on the first file that does
+// not parse.
+func (p *Parser) parseDir(dir string) (map[string][]*goast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkgs := make(map[string][]*goast.File)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		file, err := p.parseGoFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		pkgs[file.Name.Name] = append(pkgs[file.Name.Name], file)
+	}
+	return pkgs, nil
+}
+
 // Converts Go AST file to our generic AST
 func (p *Parser) convertFile(file *goast.File) ast.Node {
 	pos := p.fset.Position(file.Pos())
 	node := ast.NewBaseNode(ast.Module, ast.Position{
-		Line:   pos.Line,
-		Column: pos.Column,
-		Offset: pos.Offset,
+		Filename: pos.Filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Offset:   pos.Offset,
 	})
 
 	// Add package name
 	node.SetAttribute("package_name", file.Name.Name)
 
+	// Flag generated files, which are analyzed like handwritten ones but reported apart
+	generated, generator := generatedFile(pos.Filename, file)
+	node.SetAttribute("is_generated", generated)
+	if generator != "" {
+		node.SetAttribute("generator", generator)
+	}
+
+	// Classify test files by the package they belong to
+	p.testFile = isTestFile(pos.Filename)
+	node.SetAttribute("is_test", p.testFile)
+	if p.testFile {
+		testPackage := TestPackageInternal
+		if strings.HasSuffix(file.Name.Name, "_test") {
+			testPackage = TestPackageExternal
+		}
+		node.SetAttribute("test_package", testPackage)
+	}
+
+	// Resolve import names used by function bodies
+	p.imports = importNames(file)
+
+	// Find the TODO comments, kept by the functions holding them and otherwise by the file
+	p.todos = todoComments(file)
+	node.SetAttribute("todos", fileTodos(file, p.todos))
+
+	// Record the package functions used as values, which are live without being called
+	node.SetAttribute("func_values", p.funcValues(file))
+
 	// Track dependencies
 	dependencies := make([]string, 0)
 
@@ -141,9 +313,10 @@ func (p *Parser) convertFile(file *goast.File) ast.Node {
 func (p *Parser) convertImport(imp *goast.ImportSpec) ast.Node {
 	pos := p.fset.Position(imp.Pos())
 	node := ast.NewBaseNode(ast.Import, ast.Position{
-		Line:   pos.Line,
-		Column: pos.Column,
-		Offset: pos.Offset,
+		Filename: pos.Filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Offset:   pos.Offset,
 	})
 
 	// Store import path without quotes
@@ -162,6 +335,37 @@ func (p *Parser) convertImport(imp *goast.ImportSpec) ast.Node {
 	return node
 }
 
+// Helper function to map the local names of a file's imports to their paths
+func importNames(file *goast.File) map[string]string {
+	names := make(map[string]string)
+	for _, imp := range file.Imports {
+		if imp.Path == nil {
+			continue
+		}
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		names[name] = path
+	}
+	return names
+}
+
+// Helper function to convert a token position to our generic position
+func (p *Parser) position(pos token.Pos) ast.Position {
+	position := p.fset.Position(pos)
+	return ast.Position{
+		Filename: position.Filename,
+		Line:     position.Line,
+		Column:   position.Column,
+		Offset:   position.Offset,
+	}
+}
+
 // Helper function to check if import path contains a path separator
 func containsPath(path string) bool {
 	return strings.Contains(path, "/") || strings.Contains(path, "\\")
@@ -187,51 +391,66 @@ func (p *Parser) convertFunction(fn *goast.FuncDecl) ast.Node {
 	}
 
 	node := ast.NewBaseNode(nodeType, ast.Position{
-		Line:   pos.Line,
-		Column: pos.Column,
-		Offset: pos.Offset,
+		Filename: pos.Filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Offset:   pos.Offset,
 	})
 
 	// Store function name and export status
 	node.SetAttribute("name", fn.Name.Name)
 	node.SetAttribute("is_exported", fn.Name.IsExported())
+	node.SetAttribute("end_line", p.fset.Position(fn.End()).Line)
+	node.SetAttribute("doc", docText(fn.Doc))
+	node.SetAttribute("complexity", complexity(fn.Body))
+	node.SetAttribute("todos", todosBetween(p.todos, functionStart(fn), fn.End()))
 
 	// Build function signature
-	params := make([]*TypeInfo, 0)
-	if fn.Type.Params != nil {
-		for _, param := range fn.Type.Params.List {
-			paramType := typeToTypeInfo(param.Type)
-			for range param.Names {
-				params = append(params, paramType)
-			}
-		}
-	}
-
-	returns := make([]*TypeInfo, 0)
-	if fn.Type.Results != nil {
-		for _, result := range fn.Type.Results.List {
-			resultType := typeToTypeInfo(result.Type)
-			if len(result.Names) == 0 {
-				returns = append(returns, resultType)
-			} else {
-				for range result.Names {
-					returns = append(returns, resultType)
-				}
-			}
-		}
-	}
+	params, paramNames := fieldListTypes(fn.Type.Params)
+	returns, returnNames := fieldListTypes(fn.Type.Results)
 
 	signature := map[string]any{
-		"params":  params,
-		"returns": returns,
+		"params":       params,
+		"returns":      returns,
+		"param_names":  paramNames,
+		"return_names": returnNames,
+		"variadic":     isVariadic(fn.Type),
 	}
 	node.SetAttribute("signature", signature)
 
+	// Store error-handling patterns found in the body
+	node.SetAttribute("error_patterns", p.errorPatterns(fn.Body))
+
+	// Store context usage and concurrency patterns found in the body
+	contextParam, contextFirst := contextParameter(params, paramNames)
+	node.SetAttribute("context_param", contextParam)
+	node.SetAttribute("context_first", contextFirst)
+	node.SetAttribute("concurrency_patterns", p.concurrencyPatterns(fn, contextParam))
+
+	// Store the HTTP routes registered in the body
+	node.SetAttribute("http_routes", p.httpRoutes(fn.Body))
+
+	// Store the calls made by the body
+	node.SetAttribute("calls", p.calls(fn))
+
+	// Store the kind of test and the testing patterns of functions in test files
+	if p.testFile {
+		if kind := p.testKind(fn); kind != "" {
+			node.SetAttribute("test_kind", kind)
+		}
+		node.SetAttribute("test_patterns", p.testPatterns(fn))
+	}
+
 	// Store receiver information for methods
 	if fn.Recv != nil {
 		for _, recv := range fn.Recv.List {
 			recvType := typeToTypeInfo(recv.Type)
 			node.SetAttribute("receiver_type", recvType)
+			recvName := ""
+			if len(recv.Names) > 0 {
+				recvName = recv.Names[0].Name
+			}
+			node.SetAttribute("receiver_name", recvName)
 			break
 		}
 	}
@@ -239,6 +458,38 @@ func (p *Parser) convertFunction(fn *goast.FuncDecl) ast.Node {
 	return node
 }
 
+// Helper function to check if the last parameter of a function type is variadic
+func isVariadic(fn *goast.FuncType) bool {
+	if fn.Params == nil || len(fn.Params.List) == 0 {
+		return false
+	}
+	_, ok := fn.Params.List[len(fn.Params.List)-1].Type.(*goast.Ellipsis)
+	return ok
+}
+
+// Helper function to extract the types and names of a parameter or result list.
+// Unnamed entries get an empty name so both slices stay aligned.
+func fieldListTypes(fields *goast.FieldList) ([]*TypeInfo, []string) {
+	types := make([]*TypeInfo, 0)
+	names := make([]string, 0)
+	if fields == nil {
+		return types, names
+	}
+	for _, field := range fields.List {
+		fieldType := typeToTypeInfo(field.Type)
+		if len(field.Names) == 0 {
+			types = append(types, fieldType)
+			names = append(names, "")
+			continue
+		}
+		for _, name := range field.Names {
+			types = append(types, fieldType)
+			names = append(names, name.Name)
+		}
+	}
+	return types, names
+}
+
 // Helper function to convert Go AST type to TypeInfo
 func typeToTypeInfo(expr goast.Expr) *TypeInfo {
 	switch t := expr.(type) {
@@ -265,6 +516,7 @@ func typeToTypeInfo(expr goast.Expr) *TypeInfo {
 		return &TypeInfo{
 			Kind:     "array",
 			ElemType: typeToTypeInfo(t.Elt),
+			Len:      arrayLen(t.Len),
 		}
 	case *goast.MapType:
 		return &TypeInfo{
@@ -273,28 +525,112 @@ func typeToTypeInfo(expr goast.Expr) *TypeInfo {
 			ValueType: typeToTypeInfo(t.Value),
 		}
 	case *goast.InterfaceType:
-		return &TypeInfo{Kind: "interface", Name: "interface{}"}
+		return interfaceTypeInfo(t)
+	case *goast.StructType:
+		fields := make([]*FieldInfo, 0)
+		for _, field := range t.Fields.List {
+			fieldType := typeToTypeInfo(field.Type)
+			if len(field.Names) == 0 {
+				fields = append(fields, &FieldInfo{Type: fieldType})
+			}
+			for _, name := range field.Names {
+				fields = append(fields, &FieldInfo{Name: name.Name, Type: fieldType})
+			}
+		}
+		return &TypeInfo{Kind: "struct", Fields: fields}
+	case *goast.FuncType:
+		return &TypeInfo{
+			Kind:     "func",
+			Params:   typeList(t.Params),
+			Results:  typeList(t.Results),
+			Variadic: isVariadic(t),
+		}
+	case *goast.IndexExpr:
+		return instantiate(typeToTypeInfo(t.X), t.Index)
+	case *goast.IndexListExpr:
+		return instantiate(typeToTypeInfo(t.X), t.Indices...)
+	case *goast.ParenExpr:
+		return typeToTypeInfo(t.X)
 	case *goast.SelectorExpr:
 		if x, ok := t.X.(*goast.Ident); ok {
 			return &TypeInfo{Kind: "basic", Name: x.Name + "." + t.Sel.Name}
 		}
+	case *goast.Ellipsis:
+		// Variadic parameters are slices inside the function
+		return &TypeInfo{
+			Kind:     "slice",
+			ElemType: typeToTypeInfo(t.Elt),
+		}
 	case *goast.ChanType:
+		dir := ""
+		switch t.Dir {
+		case goast.SEND:
+			dir = ChanSend
+		case goast.RECV:
+			dir = ChanRecv
+		}
 		return &TypeInfo{
 			Kind:     "chan",
 			ElemType: typeToTypeInfo(t.Value),
+			Dir:      dir,
 		}
 	}
 	return &TypeInfo{Kind: "unknown"}
 }
 
+// Helper function to add type arguments to a named generic type
+func instantiate(base *TypeInfo, args ...goast.Expr) *TypeInfo {
+	if base.Kind != "basic" {
+		return &TypeInfo{Kind: "unknown"}
+	}
+	for _, arg := range args {
+		base.Args = append(base.Args, typeToTypeInfo(arg))
+	}
+	return base
+}
+
+// Helper function to convert an interface type, keeping its methods and embedded types
+func interfaceTypeInfo(t *goast.InterfaceType) *TypeInfo {
+	if t.Methods == nil || len(t.Methods.List) == 0 {
+		return &TypeInfo{Kind: "interface", Name: "interface{}"}
+	}
+	fields := make([]*FieldInfo, 0, len(t.Methods.List))
+	for _, method := range t.Methods.List {
+		// Type sets (e.g. ~int | string) have no structural form and become unknown
+		methodType := typeToTypeInfo(method.Type)
+		if len(method.Names) == 0 {
+			fields = append(fields, &FieldInfo{Type: methodType})
+		}
+		for _, name := range method.Names {
+			fields = append(fields, &FieldInfo{Name: name.Name, Type: methodType})
+		}
+	}
+	return &TypeInfo{Kind: "interface", Fields: fields}
+}
+
+// Helper function to get the length of an array type expression
+func arrayLen(expr goast.Expr) int {
+	if lit, ok := expr.(*goast.BasicLit); ok && lit.Kind == token.INT {
+		if n, err := strconv.Atoi(lit.Value); err == nil {
+			return n
+		}
+	}
+	return -1
+}
+
 // Helper function to convert Go type to TypeInfo
-func typeFromGoType(t types.Type) *TypeInfo {
+func (p *Parser) typeFromGoType(t types.Type) *TypeInfo {
 	if t == nil {
 		return &TypeInfo{Kind: "unknown"}
 	}
 
 	switch typ := t.(type) {
 	case *types.Basic:
+		// Untyped constants take the type they default to (untyped nil has none)
+		typ = types.Default(typ).(*types.Basic)
+		if typ.Kind() == types.Invalid || typ.Kind() == types.UntypedNil {
+			return &TypeInfo{Kind: "unknown"}
+		}
 		name := typ.Name()
 		// Convert float64 to float for language-agnostic representation
 		if name == "float64" {
@@ -304,43 +640,107 @@ func typeFromGoType(t types.Type) *TypeInfo {
 	case *types.Pointer:
 		return &TypeInfo{
 			Kind:     "pointer",
-			ElemType: typeFromGoType(typ.Elem()),
+			ElemType: p.typeFromGoType(typ.Elem()),
 		}
 	case *types.Slice:
 		return &TypeInfo{
 			Kind:     "slice",
-			ElemType: typeFromGoType(typ.Elem()),
+			ElemType: p.typeFromGoType(typ.Elem()),
 		}
 	case *types.Array:
 		return &TypeInfo{
 			Kind:     "array",
-			ElemType: typeFromGoType(typ.Elem()),
+			ElemType: p.typeFromGoType(typ.Elem()),
+			Len:      int(typ.Len()),
 		}
 	case *types.Map:
 		return &TypeInfo{
 			Kind:      "map",
-			KeyType:   typeFromGoType(typ.Key()),
-			ValueType: typeFromGoType(typ.Elem()),
+			KeyType:   p.typeFromGoType(typ.Key()),
+			ValueType: p.typeFromGoType(typ.Elem()),
 		}
 	case *types.Chan:
+		dir := ""
+		switch typ.Dir() {
+		case types.SendOnly:
+			dir = ChanSend
+		case types.RecvOnly:
+			dir = ChanRecv
+		}
 		return &TypeInfo{
 			Kind:     "chan",
-			ElemType: typeFromGoType(typ.Elem()),
+			ElemType: p.typeFromGoType(typ.Elem()),
+			Dir:      dir,
 		}
 	case *types.Interface:
-		return &TypeInfo{Kind: "interface", Name: "interface{}"}
+		if typ.NumEmbeddeds() == 0 && typ.NumExplicitMethods() == 0 {
+			return &TypeInfo{Kind: "interface", Name: "interface{}"}
+		}
+		fields := make([]*FieldInfo, 0, typ.NumEmbeddeds()+typ.NumExplicitMethods())
+		for i := range typ.NumEmbeddeds() {
+			fields = append(fields, &FieldInfo{Type: p.typeFromGoType(typ.EmbeddedType(i))})
+		}
+		for i := range typ.NumExplicitMethods() {
+			method := typ.ExplicitMethod(i)
+			fields = append(fields, &FieldInfo{Name: method.Name(), Type: p.typeFromGoType(method.Type())})
+		}
+		return &TypeInfo{Kind: "interface", Fields: fields}
+	case *types.Struct:
+		fields := make([]*FieldInfo, 0, typ.NumFields())
+		for i := range typ.NumFields() {
+			field := typ.Field(i)
+			name := field.Name()
+			if field.Embedded() {
+				name = ""
+			}
+			fields = append(fields, &FieldInfo{Name: name, Type: p.typeFromGoType(field.Type())})
+		}
+		return &TypeInfo{Kind: "struct", Fields: fields}
+	case *types.Signature:
+		return &TypeInfo{
+			Kind:     "func",
+			Params:   p.tupleTypes(typ.Params()),
+			Results:  p.tupleTypes(typ.Results()),
+			Variadic: typ.Variadic(),
+		}
 	case *types.Named:
+		info := &TypeInfo{Kind: "basic", Name: p.typeName(typ.Obj())}
+		for i := range typ.TypeArgs().Len() {
+			info.Args = append(info.Args, p.typeFromGoType(typ.TypeArgs().At(i)))
+		}
+		return info
+	case *types.Alias:
+		return &TypeInfo{Kind: "basic", Name: p.typeName(typ.Obj())}
+	case *types.TypeParam:
 		return &TypeInfo{Kind: "basic", Name: typ.Obj().Name()}
 	default:
 		return &TypeInfo{Kind: "unknown"}
 	}
 }
 
+// Returns the name of a type as written in the parsed package, qualified by the package name for
+// imported types (e.g. "time.Duration")
+func (p *Parser) typeName(obj *types.TypeName) string {
+	if obj.Pkg() == nil || obj.Pkg() == p.pkg {
+		return obj.Name()
+	}
+	return obj.Pkg().Name() + "." + obj.Name()
+}
+
+// Helper function to convert the types of a parameter or result tuple
+func (p *Parser) tupleTypes(tuple *types.Tuple) []*TypeInfo {
+	result := make([]*TypeInfo, 0, tuple.Len())
+	for i := range tuple.Len() {
+		result = append(result, p.typeFromGoType(tuple.At(i).Type()))
+	}
+	return result
+}
+
 // Helper function to infer type from an expression
 func (p *Parser) inferTypeFromExpr(expr goast.Expr) *TypeInfo {
 	// First try to get the type from the type checker
 	if tv, ok := p.info.Types[expr]; ok {
-		return typeFromGoType(tv.Type)
+		return p.typeFromGoType(tv.Type)
 	}
 
 	// If type checker info is not available, fall back to AST-based inference
@@ -359,7 +759,7 @@ func (p *Parser) inferTypeFromExpr(expr goast.Expr) *TypeInfo {
 	case *goast.Ident:
 		if obj := p.info.Uses[e]; obj != nil {
 			if t := obj.Type(); t != nil {
-				return typeFromGoType(t)
+				return p.typeFromGoType(t)
 			}
 		}
 		// Handle boolean literals
@@ -384,9 +784,10 @@ func (p *Parser) createValueNode(spec *goast.ValueSpec, i int) ast.Node {
 	pos := p.fset.Position(name.Pos())
 
 	node := ast.NewBaseNode(ast.Variable, ast.Position{
-		Line:   pos.Line,
-		Column: pos.Column,
-		Offset: pos.Offset,
+		Filename: pos.Filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Offset:   pos.Offset,
 	})
 
 	node.SetAttribute("name", name.Name)
@@ -397,19 +798,19 @@ func (p *Parser) createValueNode(spec *goast.ValueSpec, i int) ast.Node {
 	// Try to get type from type checker first
 	if obj := p.info.Defs[name]; obj != nil {
 		if typ := obj.Type(); typ != nil {
-			typeInfo = typeFromGoType(typ)
+			typeInfo = p.typeFromGoType(typ)
 		}
 	}
 
 	// If we have values, try to get type from the value expression
-	if typeInfo == nil && i < len(spec.Values) {
+	if !typeInfo.isKnown() && i < len(spec.Values) {
 		if typeAndValue, ok := p.info.Types[spec.Values[i]]; ok {
-			typeInfo = typeFromGoType(typeAndValue.Type)
+			typeInfo = p.typeFromGoType(typeAndValue.Type)
 		}
 	}
 
 	// Fallback to AST-based type inference
-	if typeInfo == nil {
+	if !typeInfo.isKnown() {
 		if spec.Type != nil {
 			typeInfo = typeToTypeInfo(spec.Type)
 		} else if i < len(spec.Values) {
@@ -418,6 +819,17 @@ func (p *Parser) createValueNode(spec *goast.ValueSpec, i int) ast.Node {
 	}
 
 	node.SetAttribute("type", typeInfo)
+
+	// Flag package-level sentinel errors (e.g. var ErrNotFound = errors.New("not found"))
+	isSentinel := false
+	if i < len(spec.Values) && strings.HasPrefix(strings.ToLower(name.Name), "err") {
+		if call, ok := spec.Values[i].(*goast.CallExpr); ok {
+			callee := p.calleeName(call)
+			isSentinel = callee == "errors.New" || callee == "fmt.Errorf"
+		}
+	}
+	node.SetAttribute("is_sentinel_error", isSentinel)
+
 	return node
 }
 
@@ -428,7 +840,7 @@ func (p *Parser) convertGenDecl(decl *goast.GenDecl) ast.Node {
 		// For single declarations
 		if len(decl.Specs) == 1 && !decl.Lparen.IsValid() {
 			if spec, ok := decl.Specs[0].(*goast.TypeSpec); ok {
-				return p.createTypeNode(spec)
+				return p.createTypeNode(spec, decl.Doc)
 			}
 			return nil
 		}
@@ -437,14 +849,15 @@ func (p *Parser) convertGenDecl(decl *goast.GenDecl) ast.Node {
 		if len(decl.Specs) > 0 {
 			pos := p.fset.Position(decl.Pos())
 			groupNode := ast.NewBaseNode(ast.Block, ast.Position{
-				Line:   pos.Line,
-				Column: pos.Column,
-				Offset: pos.Offset,
+				Filename: pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Offset:   pos.Offset,
 			})
 
 			for _, spec := range decl.Specs {
 				if typeSpec, ok := spec.(*goast.TypeSpec); ok {
-					groupNode.AddChild(p.createTypeNode(typeSpec))
+					groupNode.AddChild(p.createTypeNode(typeSpec, typeSpec.Doc))
 				}
 			}
 			return groupNode
@@ -459,9 +872,10 @@ func (p *Parser) convertGenDecl(decl *goast.GenDecl) ast.Node {
 				} else if len(spec.Names) > 1 {
 					pos := p.fset.Position(decl.Pos())
 					groupNode := ast.NewBaseNode(ast.Block, ast.Position{
-						Line:   pos.Line,
-						Column: pos.Column,
-						Offset: pos.Offset,
+						Filename: pos.Filename,
+						Line:     pos.Line,
+						Column:   pos.Column,
+						Offset:   pos.Offset,
 					})
 					for i := range spec.Names {
 						groupNode.AddChild(p.createValueNode(spec, i))
@@ -476,9 +890,10 @@ func (p *Parser) convertGenDecl(decl *goast.GenDecl) ast.Node {
 		if len(decl.Specs) > 0 {
 			pos := p.fset.Position(decl.Pos())
 			groupNode := ast.NewBaseNode(ast.Block, ast.Position{
-				Line:   pos.Line,
-				Column: pos.Column,
-				Offset: pos.Offset,
+				Filename: pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Offset:   pos.Offset,
 			})
 
 			for _, spec := range decl.Specs {
@@ -513,8 +928,8 @@ func typeList(fields *goast.FieldList) []*TypeInfo {
 	return types
 }
 
-// Create a node for a type declaration
-func (p *Parser) createTypeNode(spec *goast.TypeSpec) ast.Node {
+// Create a node for a type declaration, documented by doc
+func (p *Parser) createTypeNode(spec *goast.TypeSpec, doc *goast.CommentGroup) ast.Node {
 	specPos := p.fset.Position(spec.Pos())
 
 	nodeType := ast.Type
@@ -523,13 +938,19 @@ func (p *Parser) createTypeNode(spec *goast.TypeSpec) ast.Node {
 	}
 
 	node := ast.NewBaseNode(nodeType, ast.Position{
-		Line:   specPos.Line,
-		Column: specPos.Column,
-		Offset: specPos.Offset,
+		Filename: specPos.Filename,
+		Line:     specPos.Line,
+		Column:   specPos.Column,
+		Offset:   specPos.Offset,
 	})
 
 	node.SetAttribute("name", spec.Name.Name)
 	node.SetAttribute("is_exported", spec.Name.IsExported())
+	node.SetAttribute("doc", docText(doc))
+	if spec.Assign.IsValid() {
+		// Aliases (type A = B) denote the same type as their underlying type
+		node.SetAttribute("is_alias", true)
+	}
 
 	switch t := spec.Type.(type) {
 	case *goast.InterfaceType:
@@ -582,21 +1003,12 @@ func (p *Parser) createTypeNode(spec *goast.TypeSpec) ast.Node {
 		fields := make([]map[string]any, 0)
 		if t.Fields != nil {
 			for _, field := range t.Fields.List {
-				fieldType := typeToTypeInfo(field.Type)
 				if len(field.Names) == 0 {
 					// Embedded field
-					fields = append(fields, map[string]any{
-						"name":     fieldType.Name,
-						"type":     fieldType,
-						"embedded": true,
-					})
+					fields = append(fields, p.createFieldInfo(field, nil))
 				} else {
 					for _, name := range field.Names {
-						fields = append(fields, map[string]any{
-							"name":     name.Name,
-							"type":     fieldType,
-							"embedded": false,
-						})
+						fields = append(fields, p.createFieldInfo(field, name))
 					}
 				}
 			}
@@ -604,9 +1016,73 @@ func (p *Parser) createTypeNode(spec *goast.TypeSpec) ast.Node {
 		node.SetAttribute("fields", fields)
 		node.SetAttribute("underlying_type", "struct")
 
+	case *goast.FuncType:
+		// Function types (e.g. functional options) keep their signature
+		node.SetAttribute("underlying_type", typeToTypeInfo(spec.Type))
+		node.SetAttribute("signature", map[string]any{
+			"params":  typeList(t.Params),
+			"returns": typeList(t.Results),
+		})
+
 	default:
 		node.SetAttribute("underlying_type", typeToTypeInfo(spec.Type))
 	}
 
 	return node
 }
+
+// Create the attributes for a single struct field (name is nil for embedded fields)
+func (p *Parser) createFieldInfo(field *goast.Field, name *goast.Ident) map[string]any {
+	fieldType := typeToTypeInfo(field.Type)
+
+	fieldName := embeddedFieldName(fieldType)
+	posExpr := goast.Node(field.Type)
+	if name != nil {
+		fieldName = name.Name
+		posExpr = name
+	}
+	pos := p.fset.Position(posExpr.Pos())
+
+	info := map[string]any{
+		"name":        fieldName,
+		"type":        fieldType,
+		"embedded":    name == nil,
+		"is_exported": goast.IsExported(fieldName),
+		"position": ast.Position{
+			Filename: pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Offset:   pos.Offset,
+		},
+	}
+
+	// Store the raw tag and its parsed key/value pairs
+	if field.Tag != nil {
+		if tag, err := strconv.Unquote(field.Tag.Value); err == nil {
+			info["tag"] = tag
+			info["tags"] = ParseStructTag(tag)
+		}
+	}
+
+	// Store doc and line comments
+	if field.Doc != nil {
+		info["doc"] = strings.TrimSpace(field.Doc.Text())
+	}
+	if field.Comment != nil {
+		info["comment"] = strings.TrimSpace(field.Comment.Text())
+	}
+
+	return info
+}
+
+// Helper function to get the implicit name of an embedded field
+func embeddedFieldName(fieldType *TypeInfo) string {
+	if fieldType.Kind == "pointer" && fieldType.ElemType != nil {
+		fieldType = fieldType.ElemType
+	}
+	name := fieldType.Name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
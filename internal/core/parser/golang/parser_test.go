@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
 
 	"codedna/internal/core/parser/ast"
@@ -585,6 +586,61 @@ func TestDirectoryParsing(t *testing.T) {
 	}
 }
 
+func TestParser_Overlay(t *testing.T) {
+	tmpDir := t.TempDir()
+	onDisk := filepath.Join(tmpDir, "disk.go")
+	edited := filepath.Join(tmpDir, "edited.go")
+	if err := os.WriteFile(onDisk, []byte("package example\n\nfunc Saved() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(edited, []byte("package example\n\nfunc Old() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := goparser.New()
+	p.SetOverlay(map[string][]byte{
+		edited:                           []byte("package example\n\nfunc Unsaved() {}\n"),
+		filepath.Join(tmpDir, "gone.go"): []byte("package example\n\nfunc Gone() {}\n"),
+	})
+	names := func(nodes []ast.Node) []string {
+		var names []string
+		for _, node := range nodes {
+			for _, fn := range findNodes(node, ast.Function) {
+				names = append(names, fn.Attributes()["name"].(string))
+			}
+		}
+		return names
+	}
+
+	t.Run("ParseDir", func(t *testing.T) {
+		nodes, err := p.ParseDir(tmpDir)
+		if err != nil {
+			t.Fatalf("ParseDir failed: %v", err)
+		}
+		// Overlay files missing on disk are not parsed
+		if got := strings.Join(names(nodes), ","); got != "Saved,Unsaved" {
+			t.Errorf("Expected the overlay to replace edited.go, got functions %s", got)
+		}
+	})
+
+	t.Run("ParseFile", func(t *testing.T) {
+		node, err := p.ParseFile(edited)
+		if err != nil {
+			t.Fatalf("ParseFile failed: %v", err)
+		}
+		if got := strings.Join(names([]ast.Node{node}), ","); got != "Unsaved" {
+			t.Errorf("Expected the overlay contents, got functions %s", got)
+		}
+	})
+
+	t.Run("SyntaxError", func(t *testing.T) {
+		p.SetOverlay(map[string][]byte{edited: []byte("package example\n\nfunc {\n")})
+		if _, err := p.ParseDir(tmpDir); err == nil {
+			t.Error("Expected the broken overlay to fail parsing")
+		}
+	})
+}
+
 func BenchmarkParser_ParseFile(b *testing.B) {
 	parser := goparser.New()
 	testFile := filepath.Join("testdata", "sample.go")
@@ -614,3 +670,76 @@ func BenchmarkParser_ParseDir(b *testing.B) {
 		}
 	}
 }
+
+func TestFunctionNames(t *testing.T) {
+	src := `
+	package example
+
+	type Store struct{}
+
+	func (s *Store) Put(key string, value []byte) (n int, err error) {
+		return 0, nil
+	}
+
+	func (Store) Len() int {
+		return 0
+	}
+
+	func Apply(func(int) int, int) {}
+	`
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+	if err := os.WriteFile(testFile, []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	root, err := goparser.New().ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	byName := make(map[string]map[string]any)
+	for _, n := range append(findNodes(root, ast.Method), findNodes(root, ast.Function)...) {
+		byName[n.Attributes()["name"].(string)] = n.Attributes()
+	}
+
+	t.Run("ReceiverNames", func(t *testing.T) {
+		if name := byName["Put"]["receiver_name"]; name != "s" {
+			t.Errorf("Expected receiver name 's', got %v", name)
+		}
+		if name := byName["Len"]["receiver_name"]; name != "" {
+			t.Errorf("Expected empty receiver name, got %v", name)
+		}
+	})
+
+	t.Run("ParameterNames", func(t *testing.T) {
+		sig := byName["Put"]["signature"].(map[string]any)
+		if names := sig["param_names"].([]string); !slices.Equal(names, []string{"key", "value"}) {
+			t.Errorf("Expected param names [key value], got %v", names)
+		}
+		if names := sig["return_names"].([]string); !slices.Equal(names, []string{"n", "err"}) {
+			t.Errorf("Expected return names [n err], got %v", names)
+		}
+	})
+
+	t.Run("UnnamedParameters", func(t *testing.T) {
+		sig := byName["Apply"]["signature"].(map[string]any)
+		params := sig["params"].([]*goparser.TypeInfo)
+		names := sig["param_names"].([]string)
+		if len(params) != 2 || len(names) != 2 {
+			t.Fatalf("Expected 2 aligned params, got %v and %v", params, names)
+		}
+		if names[0] != "" || names[1] != "" {
+			t.Errorf("Expected empty param names, got %q", names)
+		}
+	})
+
+	t.Run("EndLines", func(t *testing.T) {
+		for name, want := range map[string]int{"Put": 8, "Len": 12, "Apply": 14} {
+			if line := byName[name]["end_line"]; line != want {
+				t.Errorf("Expected %s to end on line %d, got %v", name, want, line)
+			}
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package goparser
+
+import (
+	goast "go/ast"
+	"go/token"
+	"go/types"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// HTTP routing packages whose route registrations are recognized
+const (
+	FrameworkNetHTTP = "net/http"
+	FrameworkChi     = "chi"
+	FrameworkGin     = "gin"
+)
+
+// An HTTP route registered in a function body
+type HTTPRoute struct {
+	Framework string // FrameworkNetHTTP, FrameworkChi or FrameworkGin
+	Method    string // The HTTP method (e.g. "GET"); empty if the route accepts any method
+	Path      string // The full path pattern, including group prefixes (e.g. "/api/users/{id}")
+	Handler   string // The handler expression (e.g. "h.getUser"); empty for function literals
+	Position  ast.Position
+}
+
+// Route registration methods of chi routers, by HTTP method
+var chiMethods = map[string]string{
+	"Get": http.MethodGet, "Post": http.MethodPost, "Put": http.MethodPut, "Patch": http.MethodPatch,
+	"Delete": http.MethodDelete, "Head": http.MethodHead, "Options": http.MethodOptions,
+	"Connect": http.MethodConnect, "Trace": http.MethodTrace,
+}
+
+// Route registration methods of gin routers and groups, by HTTP method
+var ginMethods = map[string]string{
+	"GET": http.MethodGet, "POST": http.MethodPost, "PUT": http.MethodPut, "PATCH": http.MethodPatch,
+	"DELETE": http.MethodDelete, "HEAD": http.MethodHead, "OPTIONS": http.MethodOptions, "Any": "",
+}
+
+// Finds the HTTP routes registered in a function body. Paths registered on chi sub-routers
+// (r.Route) and gin groups (r.Group) include the prefix of their router.
+func (p *Parser) httpRoutes(body *goast.BlockStmt) []*HTTPRoute {
+	routes := make([]*HTTPRoute, 0)
+	if body == nil {
+		return routes
+	}
+	framework := p.routingFramework()
+	p.collectRoutes(body, framework, make(map[string]string), &routes)
+	return routes
+}
+
+// Returns the routing framework imported by the file being converted
+func (p *Parser) routingFramework() string {
+	for _, path := range p.imports {
+		switch {
+		case strings.HasPrefix(path, "github.com/go-chi/chi"):
+			return FrameworkChi
+		case path == "github.com/gin-gonic/gin":
+			return FrameworkGin
+		}
+	}
+	return FrameworkNetHTTP
+}
+
+// Collects the routes registered under node. Prefixes maps router expressions to the path
+// prefix of the group they stand for.
+func (p *Parser) collectRoutes(node goast.Node, framework string, prefixes map[string]string, routes *[]*HTTPRoute) {
+	goast.Inspect(node, func(n goast.Node) bool {
+		switch s := n.(type) {
+		case *goast.AssignStmt:
+			// v1 := r.Group("/v1")
+			if len(s.Lhs) != 1 || len(s.Rhs) != 1 {
+				return true
+			}
+			call, ok := s.Rhs[0].(*goast.CallExpr)
+			if !ok {
+				return true
+			}
+			if router, method, ok := routerCall(call); ok && method == "Group" {
+				if path, ok := routePath(call.Args, 0); ok {
+					prefixes[types.ExprString(s.Lhs[0])] = joinRoute(prefixes[router], path)
+				}
+			}
+
+		case *goast.CallExpr:
+			router, method, ok := routerCall(s)
+			if !ok {
+				return true
+			}
+			prefix := prefixes[router]
+
+			// Sub-routers: r.Route("/users", func(r chi.Router) { ... }) and r.Group(func(r chi.Router) { ... })
+			if fn, ok := lastArg(s.Args).(*goast.FuncLit); ok && (method == "Route" || method == "Group") {
+				sub := prefix
+				if method == "Route" {
+					path, ok := routePath(s.Args, 0)
+					if !ok {
+						return true
+					}
+					sub = joinRoute(prefix, path)
+				}
+				inner := make(map[string]string, len(prefixes)+1)
+				for k, v := range prefixes {
+					inner[k] = v
+				}
+				if params := fn.Type.Params; params != nil && len(params.List) > 0 && len(params.List[0].Names) > 0 {
+					inner[params.List[0].Names[0].Name] = sub
+				}
+				p.collectRoutes(fn.Body, framework, inner, routes)
+				return false
+			}
+
+			if route := p.route(s, router, method, framework); route != nil {
+				route.Path = joinRoute(prefix, route.Path)
+				*routes = append(*routes, route)
+			}
+		}
+		return true
+	})
+}
+
+// Returns the route registered by a router method call, or nil if the call registers none
+func (p *Parser) route(call *goast.CallExpr, router, method, framework string) *HTTPRoute {
+	route := &HTTPRoute{Framework: framework, Position: p.position(call.Pos())}
+	pathArg := 0
+	switch {
+	case method == "HandleFunc" || (method == "Handle" && framework != FrameworkGin):
+		// http.HandleFunc("/users", h), mux.Handle("GET /users/{id}", h), r.HandleFunc("/users", h) in chi
+		if path, isPackage := p.imports[router]; isPackage && path != "net/http" {
+			return nil
+		}
+		if p.imports[router] == "net/http" || framework == FrameworkGin {
+			route.Framework = FrameworkNetHTTP
+		}
+		pattern, ok := routePath(call.Args, 0)
+		if !ok {
+			return nil
+		}
+		// Patterns may start with a method and a host (e.g. "GET example.com/users/{id}")
+		if verb, rest, found := strings.Cut(pattern, " "); found {
+			route.Method, pattern = verb, strings.TrimSpace(rest)
+		}
+		if i := strings.Index(pattern, "/"); i > 0 {
+			pattern = pattern[i:]
+		}
+		route.Path = pattern
+
+	case chiMethods[method] != "":
+		route.Framework = FrameworkChi
+		route.Method = chiMethods[method]
+
+	case ginMethods[method] != "" || method == "Any":
+		route.Framework = FrameworkGin
+		route.Method = ginMethods[method]
+
+	case method == "Method" || method == "MethodFunc" || (method == "Handle" && framework == FrameworkGin):
+		// r.Method("GET", "/users", h) in chi, r.Handle("GET", "/users", h) in gin
+		verb, ok := routePath(call.Args, 0)
+		if !ok {
+			return nil
+		}
+		route.Method = strings.ToUpper(verb)
+		pathArg = 1
+
+	default:
+		return nil
+	}
+
+	if route.Path == "" {
+		path, ok := routePath(call.Args, pathArg)
+		if !ok {
+			return nil
+		}
+		route.Path = path
+	}
+	if !strings.HasPrefix(route.Path, "/") || len(call.Args) < pathArg+2 {
+		return nil
+	}
+	if _, ok := lastArg(call.Args).(*goast.FuncLit); !ok {
+		route.Handler = types.ExprString(lastArg(call.Args))
+	}
+	return route
+}
+
+// Splits a method call on a router (e.g. r.Get(...), s.router.HandleFunc(...)) into the router
+// expression and the method name
+func routerCall(call *goast.CallExpr) (string, string, bool) {
+	sel, ok := call.Fun.(*goast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+	return types.ExprString(sel.X), sel.Sel.Name, true
+}
+
+// Returns argument i if it is a string literal
+func routePath(args []goast.Expr, i int) (string, bool) {
+	if i >= len(args) {
+		return "", false
+	}
+	lit, ok := args[i].(*goast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// Returns the last argument of a call, or nil
+func lastArg(args []goast.Expr) goast.Expr {
+	if len(args) == 0 {
+		return nil
+	}
+	return args[len(args)-1]
+}
+
+// Joins a group prefix and a route path
+func joinRoute(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "/" || path == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
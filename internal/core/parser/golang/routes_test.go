@@ -0,0 +1,131 @@
+package goparser_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+func TestHTTPRoutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected []string // "framework method path handler"
+	}{
+		{
+			name: "NetHTTP",
+			src: `
+			package server
+
+			import "net/http"
+
+			func Routes(h *Handler) *http.ServeMux {
+				mux := http.NewServeMux()
+				mux.HandleFunc("GET /api/users/{id}", h.getUser)
+				mux.Handle("/static/", http.FileServer(http.Dir("static")))
+				http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+				return mux
+			}
+			`,
+			expected: []string{
+				`net/http GET /api/users/{id} h.getUser`,
+				`net/http  /static/ http.FileServer(http.Dir("static"))`,
+				`net/http  /health `,
+			},
+		},
+		{
+			name: "Chi",
+			src: `
+			package server
+
+			import (
+				"net/http"
+
+				"github.com/go-chi/chi/v5"
+			)
+
+			func Routes(h *Handler) http.Handler {
+				r := chi.NewRouter()
+				r.Get("/health", health)
+				r.Route("/api/users", func(r chi.Router) {
+					r.Post("/", h.createUser)
+					r.Route("/{id}", func(r chi.Router) {
+						r.Get("/", h.getUser)
+						r.Method("DELETE", "/", http.HandlerFunc(h.deleteUser))
+					})
+				})
+				r.Group(func(r chi.Router) {
+					r.Put("/settings", h.saveSettings)
+				})
+				return r
+			}
+			`,
+			expected: []string{
+				`chi GET /health health`,
+				`chi POST /api/users h.createUser`,
+				`chi GET /api/users/{id} h.getUser`,
+				`chi DELETE /api/users/{id} http.HandlerFunc(h.deleteUser)`,
+				`chi PUT /settings h.saveSettings`,
+			},
+		},
+		{
+			name: "Gin",
+			src: `
+			package server
+
+			import "github.com/gin-gonic/gin"
+
+			func Routes(r *gin.Engine, h *Handler) {
+				api := r.Group("/api")
+				v1 := api.Group("/v1")
+				v1.GET("/users/:id", h.GetUser)
+				v1.POST("/users", h.CreateUser)
+				r.Handle("PATCH", "/users/:id", h.UpdateUser)
+				r.Any("/ping", ping)
+				cache.Get("/not/a/route")
+			}
+			`,
+			expected: []string{
+				`gin GET /api/v1/users/:id h.GetUser`,
+				`gin POST /api/v1/users h.CreateUser`,
+				`gin PATCH /users/:id h.UpdateUser`,
+				`gin  /ping ping`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(t.TempDir(), "routes.go")
+			if err := os.WriteFile(testFile, []byte(tt.src), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			root, err := goparser.New().ParseFile(testFile)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			var got []string
+			for _, fn := range findNodes(root, ast.Function) {
+				routes, ok := fn.Attributes()["http_routes"].([]*goparser.HTTPRoute)
+				if !ok {
+					t.Fatalf("Expected http_routes to be []*HTTPRoute, got %T", fn.Attributes()["http_routes"])
+				}
+				for _, route := range routes {
+					if route.Position.Line == 0 || route.Position.Filename != testFile {
+						t.Errorf("Route %s has invalid position %v", route.Path, route.Position)
+					}
+					got = append(got, fmt.Sprintf("%s %s %s %s", route.Framework, route.Method, route.Path, route.Handler))
+				}
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected routes %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
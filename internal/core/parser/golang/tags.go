@@ -0,0 +1,76 @@
+package goparser
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// StructTag represents a single key:"value" pair of a struct field tag
+type StructTag struct {
+	Key     string   // The tag key (e.g. "json", "yaml", "db", "validate", "mapstructure")
+	Value   string   // The raw, unquoted tag value (e.g. "user_id,omitempty")
+	Name    string   // The first comma-separated part of the value (e.g. "user_id")
+	Options []string // The remaining comma-separated parts of the value (e.g. ["omitempty"])
+}
+
+// Parses a raw struct tag into its key/value pairs, keyed by tag key.
+// Parsing follows reflect.StructTag conventions and stops at the first malformed pair.
+func ParseStructTag(tag string) map[string]*StructTag {
+	tags := make(map[string]*StructTag)
+	for tag != "" {
+		// Skip leading space
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// Scan to colon. A space, a quote or a control character is a syntax error.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		// Scan quoted string to find value
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		quoted := tag[:i+1]
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			break
+		}
+
+		parts := strings.Split(value, ",")
+		tags[key] = &StructTag{
+			Key:     key,
+			Value:   value,
+			Name:    parts[0],
+			Options: parts[1:],
+		}
+	}
+	return tags
+}
+
+// Reports whether the tag value carries the given option (e.g. "omitempty")
+func (t *StructTag) HasOption(option string) bool {
+	return slices.Contains(t.Options, option)
+}
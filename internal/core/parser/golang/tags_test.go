@@ -0,0 +1,131 @@
+package goparser_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+func TestParseStructTag(t *testing.T) {
+	tags := goparser.ParseStructTag(`json:"user_id,omitempty" db:"user_id" validate:"required,min=3"`)
+	if len(tags) != 3 {
+		t.Fatalf("Expected 3 tags, got %d", len(tags))
+	}
+
+	json := tags["json"]
+	if json == nil || json.Name != "user_id" || !slices.Equal(json.Options, []string{"omitempty"}) {
+		t.Errorf("Expected json tag user_id with omitempty, got %+v", json)
+	}
+	if !json.HasOption("omitempty") || json.HasOption("string") {
+		t.Error("Expected HasOption to report omitempty only")
+	}
+
+	if validate := tags["validate"]; validate == nil || validate.Value != "required,min=3" {
+		t.Errorf("Expected raw validate value, got %+v", validate)
+	}
+
+	t.Run("Malformed", func(t *testing.T) {
+		tags := goparser.ParseStructTag(`json:"name" broken yaml:"name"`)
+		if len(tags) != 1 || tags["json"] == nil {
+			t.Errorf("Expected parsing to stop at malformed pair, got %v", tags)
+		}
+	})
+}
+
+func TestStructFieldMetadata(t *testing.T) {
+	src := `
+	package example
+
+	import "example.com/base"
+
+	type User struct {
+		*base.Model
+
+		// ID is the primary key
+		ID    int    ` + "`json:\"id\" db:\"id\"`" + `
+		Email string ` + "`json:\"email,omitempty\"`" + ` // contact address
+		notes string
+	}
+	`
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+	if err := os.WriteFile(testFile, []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	root, err := goparser.New().ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	types := findNodes(root, ast.Type)
+	if len(types) != 1 {
+		t.Fatalf("Expected 1 type, got %d", len(types))
+	}
+	fields, ok := types[0].Attributes()["fields"].([]map[string]any)
+	if !ok || len(fields) != 4 {
+		t.Fatalf("Expected 4 fields, got %v", types[0].Attributes()["fields"])
+	}
+
+	byName := make(map[string]map[string]any)
+	for _, field := range fields {
+		byName[field["name"].(string)] = field
+	}
+
+	t.Run("Embedded", func(t *testing.T) {
+		model := byName["Model"]
+		if model == nil {
+			t.Fatal("Expected embedded field named Model")
+		}
+		if !model["embedded"].(bool) || !model["is_exported"].(bool) {
+			t.Errorf("Expected exported embedded field, got %v", model)
+		}
+	})
+
+	t.Run("Exported", func(t *testing.T) {
+		if !byName["ID"]["is_exported"].(bool) {
+			t.Error("Expected ID to be exported")
+		}
+		if byName["notes"]["is_exported"].(bool) {
+			t.Error("Expected notes to not be exported")
+		}
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		id := byName["ID"]
+		if tag := id["tag"]; tag != `json:"id" db:"id"` {
+			t.Errorf("Expected raw tag, got %v", tag)
+		}
+		tags, ok := id["tags"].(map[string]*goparser.StructTag)
+		if !ok || tags["json"] == nil || tags["db"] == nil {
+			t.Fatalf("Expected json and db tags, got %v", id["tags"])
+		}
+		if _, ok := byName["notes"]["tags"]; ok {
+			t.Error("Expected no tags on untagged field")
+		}
+	})
+
+	t.Run("Comments", func(t *testing.T) {
+		if doc := byName["ID"]["doc"]; doc != "ID is the primary key" {
+			t.Errorf("Expected doc comment, got %v", doc)
+		}
+		if comment := byName["Email"]["comment"]; comment != "contact address" {
+			t.Errorf("Expected line comment, got %v", comment)
+		}
+	})
+
+	t.Run("Positions", func(t *testing.T) {
+		pos, ok := byName["Email"]["position"].(ast.Position)
+		if !ok {
+			t.Fatalf("Expected position to be ast.Position, got %T", byName["Email"]["position"])
+		}
+		if pos.Line != 11 {
+			t.Errorf("Expected Email on line 11, got %d", pos.Line)
+		}
+	})
+}
@@ -0,0 +1,233 @@
+package goparser
+
+import (
+	goast "go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// The kind of function run by go test
+type TestKind string
+
+const (
+	TestFunction  TestKind = "test"      // func TestX(t *testing.T)
+	TestBenchmark TestKind = "benchmark" // func BenchmarkX(b *testing.B)
+	TestFuzz      TestKind = "fuzz"      // func FuzzX(f *testing.F)
+	TestExample   TestKind = "example"   // func ExampleX()
+	TestMain      TestKind = "main"      // func TestMain(m *testing.M)
+)
+
+// The package of a test file
+const (
+	TestPackageInternal = "internal" // The package under test
+	TestPackageExternal = "external" // The separate <name>_test package
+)
+
+// The kind of testing pattern found in a function body
+type TestPatternKind string
+
+const (
+	TestTableDriven  TestPatternKind = "table_driven"  // range over a table of cases (a slice or map of structs)
+	TestSubtest      TestPatternKind = "subtest"       // t.Run or b.Run
+	TestParallel     TestPatternKind = "parallel"      // t.Parallel
+	TestHelper       TestPatternKind = "helper"        // t.Helper
+	TestStdlibAssert TestPatternKind = "stdlib_assert" // t.Error, t.Errorf, t.Fatal, t.Fatalf, t.Fail or t.FailNow
+	TestTestify      TestPatternKind = "testify"       // github.com/stretchr/testify assert and require calls
+	TestGoldenFile   TestPatternKind = "golden_file"   // a .golden file name
+)
+
+// A testing pattern occurrence in a function body
+type TestPattern struct {
+	Kind     TestPatternKind
+	Position ast.Position
+	Detail   string // The table, subtest name, callee or file name involved
+}
+
+// Checks if a file holds tests
+func isTestFile(filename string) bool {
+	return strings.HasSuffix(filename, "_test.go")
+}
+
+// Returns the kind of a function run by go test, or "" for other functions
+func (p *Parser) testKind(fn *goast.FuncDecl) TestKind {
+	if fn.Recv != nil {
+		return ""
+	}
+	params := fn.Type.Params.List
+	if fn.Name.Name == "TestMain" {
+		if len(params) == 1 && p.isTestingType(params[0].Type, "M") {
+			return TestMain
+		}
+		return ""
+	}
+	for _, candidate := range []struct {
+		prefix string
+		kind   TestKind
+		param  string
+	}{
+		{"Test", TestFunction, "T"},
+		{"Benchmark", TestBenchmark, "B"},
+		{"Fuzz", TestFuzz, "F"},
+		{"Example", TestExample, ""},
+	} {
+		rest, ok := strings.CutPrefix(fn.Name.Name, candidate.prefix)
+		if !ok {
+			continue
+		}
+		// The name must not continue with a lowercase letter (e.g. Testify is not a test)
+		if r, _ := utf8.DecodeRuneInString(rest); rest != "" && unicode.IsLower(r) {
+			return ""
+		}
+		if candidate.param == "" {
+			if len(params) == 0 && fn.Type.Results == nil {
+				return candidate.kind
+			}
+			return ""
+		}
+		if len(params) == 1 && len(params[0].Names) <= 1 && p.isTestingType(params[0].Type, candidate.param) {
+			return candidate.kind
+		}
+		return ""
+	}
+	return ""
+}
+
+// Checks if a type expression is a pointer to a type of the testing package (e.g. *testing.T)
+func (p *Parser) isTestingType(expr goast.Expr, name string) bool {
+	star, ok := expr.(*goast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*goast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	pkg, ok := sel.X.(*goast.Ident)
+	return ok && p.imports[pkg.Name] == "testing"
+}
+
+// Methods of testing.T and testing.B reporting failures
+var stdlibAsserts = map[string]bool{
+	"Error": true, "Errorf": true, "Fatal": true, "Fatalf": true, "Fail": true, "FailNow": true,
+}
+
+// Finds the testing patterns in a function body
+func (p *Parser) testPatterns(fn *goast.FuncDecl) []*TestPattern {
+	patterns := make([]*TestPattern, 0)
+	if fn.Body == nil {
+		return patterns
+	}
+
+	add := func(kind TestPatternKind, node goast.Node, detail string) {
+		patterns = append(patterns, &TestPattern{
+			Kind:     kind,
+			Position: p.position(node.Pos()),
+			Detail:   detail,
+		})
+	}
+
+	params := p.importedParams(fn.Type)
+	tables := make(map[string]bool) // Variables holding tables of cases
+	goast.Inspect(fn.Body, func(n goast.Node) bool {
+		switch s := n.(type) {
+		case *goast.FuncLit:
+			for name, typ := range p.importedParams(s.Type) {
+				params[name] = typ
+			}
+
+		case *goast.AssignStmt:
+			for i, rhs := range s.Rhs {
+				if i >= len(s.Lhs) {
+					break
+				}
+				if ident, ok := s.Lhs[i].(*goast.Ident); ok && isTable(rhs) {
+					tables[ident.Name] = true
+				}
+			}
+
+		case *goast.ValueSpec:
+			for i, value := range s.Values {
+				if i < len(s.Names) && isTable(value) {
+					tables[s.Names[i].Name] = true
+				}
+			}
+
+		case *goast.RangeStmt:
+			if ident, ok := s.X.(*goast.Ident); ok && tables[ident.Name] {
+				add(TestTableDriven, s, ident.Name)
+			} else if isTable(s.X) {
+				add(TestTableDriven, s, "")
+			}
+
+		case *goast.CallExpr:
+			if callee := p.calleeName(s); strings.HasPrefix(callee, "github.com/stretchr/testify/") {
+				add(TestTestify, s, callee)
+				return true
+			}
+			sel, ok := s.Fun.(*goast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			x, ok := sel.X.(*goast.Ident)
+			if !ok || params[x.Name][0] != "testing" {
+				return true
+			}
+			switch name := sel.Sel.Name; {
+			case name == "Run":
+				add(TestSubtest, s, stringLiteral(s.Args))
+			case name == "Parallel":
+				add(TestParallel, s, "")
+			case name == "Helper":
+				add(TestHelper, s, "")
+			case stdlibAsserts[name]:
+				add(TestStdlibAssert, s, x.Name+"."+name)
+			}
+
+		case *goast.BasicLit:
+			if s.Kind == token.STRING && strings.Contains(s.Value, ".golden") {
+				value, err := strconv.Unquote(s.Value)
+				if err != nil {
+					value = s.Value
+				}
+				add(TestGoldenFile, s, value)
+			}
+		}
+		return true
+	})
+	return patterns
+}
+
+// Checks if an expression is a table of test cases: a slice, array or map literal of structs
+func isTable(expr goast.Expr) bool {
+	lit, ok := expr.(*goast.CompositeLit)
+	if !ok {
+		return false
+	}
+	var elem goast.Expr
+	switch t := lit.Type.(type) {
+	case *goast.ArrayType:
+		elem = t.Elt
+	case *goast.MapType:
+		elem = t.Value
+	default:
+		return false
+	}
+	if star, ok := elem.(*goast.StarExpr); ok {
+		elem = star.X
+	}
+	switch e := elem.(type) {
+	case *goast.StructType:
+		return true
+	case *goast.Ident:
+		// A named case type, rather than a predeclared one (e.g. []string)
+		_, predeclared := types.Universe.Lookup(e.Name).(*types.TypeName)
+		return !predeclared
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package goparser_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// Helper function to parse a directory of source files
+func parseFiles(t *testing.T, files map[string]string) map[string]ast.Node {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	nodes, err := goparser.New().ParseDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	modules := make(map[string]ast.Node)
+	for _, node := range nodes {
+		modules[filepath.Base(node.Position().Filename)] = node
+	}
+	return modules
+}
+
+func TestTestFiles(t *testing.T) {
+	modules := parseFiles(t, map[string]string{
+		"store.go": `
+		package store
+
+		type Store struct{ names []string }
+
+		func (s *Store) Save(name string) { s.names = append(s.names, name) }
+		`,
+		"store_test.go": `
+		package store
+
+		import "testing"
+
+		func TestSave(t *testing.T) {
+			tests := []struct {
+				name string
+			}{{name: "alice"}, {name: "bob"}}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					t.Parallel()
+					s := &Store{}
+					s.Save(tt.name)
+					if len(s.names) != 1 {
+						t.Errorf("Expected 1 name, got %d", len(s.names))
+					}
+				})
+			}
+		}
+
+		func Testable(t *testing.T) {}
+
+		func TestMain(m *testing.M) { m.Run() }
+
+		func BenchmarkSave(b *testing.B) {
+			for b.Loop() {
+				(&Store{}).Save("x")
+			}
+		}
+
+		func FuzzSave(f *testing.F) {}
+
+		func ExampleStore_Save() {}
+		`,
+		"export_test.go": `
+		package store_test
+
+		import (
+			"os"
+			"testing"
+
+			"github.com/stretchr/testify/require"
+		)
+
+		func TestGolden(t *testing.T) {
+			want, err := os.ReadFile("testdata/save.golden")
+			require.NoError(t, err)
+			check(t, string(want))
+		}
+
+		func check(t *testing.T, s string) {
+			t.Helper()
+			if s == "" {
+				t.Fatal("empty")
+			}
+		}
+		`,
+	})
+
+	t.Run("Packages", func(t *testing.T) {
+		expected := map[string][2]any{
+			"store.go":       {false, nil},
+			"store_test.go":  {true, goparser.TestPackageInternal},
+			"export_test.go": {true, goparser.TestPackageExternal},
+		}
+		for name, exp := range expected {
+			attrs := modules[name].Attributes()
+			if attrs["is_test"] != exp[0] || attrs["test_package"] != exp[1] {
+				t.Errorf("Expected %s to have is_test %v and test_package %v, got %v %v", name, exp[0], exp[1], attrs["is_test"], attrs["test_package"])
+			}
+		}
+	})
+
+	t.Run("Kinds", func(t *testing.T) {
+		var got []string
+		for _, name := range []string{"store_test.go", "export_test.go"} {
+			for _, fn := range findNodes(modules[name], ast.Function) {
+				got = append(got, fmt.Sprintf("%s:%v", fn.Attributes()["name"], fn.Attributes()["test_kind"]))
+			}
+		}
+		expected := []string{
+			"TestSave:test", "Testable:<nil>", "TestMain:main", "BenchmarkSave:benchmark", "FuzzSave:fuzz",
+			"ExampleStore_Save:example", "TestGolden:test", "check:<nil>",
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("Expected test kinds %v, got %v", expected, got)
+		}
+		if _, ok := findNodes(modules["store.go"], ast.Method)[0].Attributes()["test_patterns"]; ok {
+			t.Error("Expected no testing patterns outside test files")
+		}
+	})
+
+	t.Run("Patterns", func(t *testing.T) {
+		patterns := func(file string, index int) []string {
+			var result []string
+			fn := findNodes(modules[file], ast.Function)[index]
+			for _, pattern := range fn.Attributes()["test_patterns"].([]*goparser.TestPattern) {
+				result = append(result, fmt.Sprintf("%s %s", pattern.Kind, pattern.Detail))
+			}
+			return result
+		}
+
+		expected := []string{"table_driven tests", "subtest ", "parallel ", "stdlib_assert t.Errorf"}
+		if got := patterns("store_test.go", 0); !slices.Equal(got, expected) {
+			t.Errorf("Expected TestSave patterns %q, got %q", expected, got)
+		}
+		expected = []string{"golden_file testdata/save.golden", "testify github.com/stretchr/testify/require.NoError"}
+		if got := patterns("export_test.go", 0); !slices.Equal(got, expected) {
+			t.Errorf("Expected TestGolden patterns %q, got %q", expected, got)
+		}
+		expected = []string{"helper ", "stdlib_assert t.Fatal"}
+		if got := patterns("export_test.go", 1); !slices.Equal(got, expected) {
+			t.Errorf("Expected check patterns %q, got %q", expected, got)
+		}
+	})
+}
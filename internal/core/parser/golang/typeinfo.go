@@ -0,0 +1,442 @@
+package goparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Returns the canonical string form of the type (e.g. "map[string][]*pkg.Foo")
+func (t *TypeInfo) String() string {
+	var b strings.Builder
+	t.writeTo(&b)
+	return b.String()
+}
+
+// Returns the name of a method receiver type without its type arguments (e.g. "*Stack" for
+// "*Stack[T]"), which names the type the method belongs to
+func (t *TypeInfo) ReceiverName() string {
+	if t != nil && t.Kind == "pointer" {
+		return "*" + t.ElemType.ReceiverName()
+	}
+	if t == nil || t.Kind != "basic" || t.Name == "" {
+		return "?"
+	}
+	return t.Name
+}
+
+// Writes the canonical form of the type to the builder
+func (t *TypeInfo) writeTo(b *strings.Builder) {
+	if t == nil {
+		b.WriteString("?")
+		return
+	}
+
+	switch t.Kind {
+	case "basic":
+		if t.Name == "" {
+			b.WriteString("?")
+			return
+		}
+		b.WriteString(t.Name)
+		if len(t.Args) > 0 {
+			b.WriteString("[")
+			writeList(b, t.Args, false)
+			b.WriteString("]")
+		}
+	case "pointer":
+		b.WriteString("*")
+		t.ElemType.writeTo(b)
+	case "slice":
+		b.WriteString("[]")
+		t.ElemType.writeTo(b)
+	case "array":
+		if t.Len < 0 {
+			b.WriteString("[...]")
+		} else {
+			b.WriteString("[" + strconv.Itoa(t.Len) + "]")
+		}
+		t.ElemType.writeTo(b)
+	case "map":
+		b.WriteString("map[")
+		t.KeyType.writeTo(b)
+		b.WriteString("]")
+		t.ValueType.writeTo(b)
+	case "chan":
+		switch t.Dir {
+		case ChanSend:
+			b.WriteString("chan<- ")
+		case ChanRecv:
+			b.WriteString("<-chan ")
+		default:
+			b.WriteString("chan ")
+		}
+		t.ElemType.writeTo(b)
+	case "func":
+		b.WriteString("func")
+		t.writeSignature(b)
+	case "struct":
+		b.WriteString("struct{")
+		for i, field := range t.Fields {
+			if i > 0 {
+				b.WriteString("; ")
+			}
+			if field.Name != "" {
+				b.WriteString(field.Name + " ")
+			}
+			field.Type.writeTo(b)
+		}
+		b.WriteString("}")
+	case "interface":
+		b.WriteString("interface{")
+		for i, field := range t.Fields {
+			if i > 0 {
+				b.WriteString("; ")
+			}
+			if field.Name != "" && field.Type != nil && field.Type.Kind == "func" {
+				b.WriteString(field.Name)
+				field.Type.writeSignature(b)
+			} else {
+				field.Type.writeTo(b)
+			}
+		}
+		b.WriteString("}")
+	default:
+		b.WriteString("?")
+	}
+}
+
+// Writes the parameters and results of a func type (e.g. "(int, ...string) (bool, error)")
+func (t *TypeInfo) writeSignature(b *strings.Builder) {
+	b.WriteString("(")
+	writeList(b, t.Params, t.Variadic)
+	b.WriteString(")")
+	switch len(t.Results) {
+	case 0:
+	case 1:
+		b.WriteString(" ")
+		t.Results[0].writeTo(b)
+	default:
+		b.WriteString(" (")
+		writeList(b, t.Results, false)
+		b.WriteString(")")
+	}
+}
+
+// Writes a comma-separated list of types; the last one is written as "...T" when variadic
+func writeList(b *strings.Builder, types []*TypeInfo, variadic bool) {
+	for i, typ := range types {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if variadic && i == len(types)-1 && typ != nil && typ.Kind == "slice" {
+			b.WriteString("...")
+			typ.ElemType.writeTo(b)
+			continue
+		}
+		typ.writeTo(b)
+	}
+}
+
+// Reports whether the type and all of its component types are known
+func (t *TypeInfo) isKnown() bool {
+	if t == nil || t.Kind == "unknown" {
+		return false
+	}
+	components := []*TypeInfo{t.ElemType, t.KeyType, t.ValueType}
+	components = append(components, t.Args...)
+	components = append(components, t.Params...)
+	components = append(components, t.Results...)
+	for _, field := range t.Fields {
+		components = append(components, field.Type)
+	}
+	for _, component := range components {
+		if component != nil && !component.isKnown() {
+			return false
+		}
+	}
+	return true
+}
+
+// Implements encoding.TextMarshaler using the canonical string form
+func (t *TypeInfo) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// Implements encoding.TextUnmarshaler using the canonical string form
+func (t *TypeInfo) UnmarshalText(text []byte) error {
+	parsed, err := ParseTypeInfo(string(text))
+	if err != nil {
+		return err
+	}
+	*t = *parsed
+	return nil
+}
+
+// Parses the canonical string form of a type back into a TypeInfo
+func ParseTypeInfo(s string) (*TypeInfo, error) {
+	r := &typeReader{src: s}
+	typ, err := r.readType()
+	if err != nil {
+		return nil, err
+	}
+	if r.pos != len(r.src) {
+		return nil, fmt.Errorf("invalid type %q: unexpected %q at offset %d", s, r.src[r.pos:], r.pos)
+	}
+	return typ, nil
+}
+
+// Reads a canonical type string from left to right
+type typeReader struct {
+	src string
+	pos int
+}
+
+// Reads a single type starting at the current position
+func (r *typeReader) readType() (*TypeInfo, error) {
+	rest := r.src[r.pos:]
+	switch {
+	case rest == "":
+		return nil, r.errorf("unexpected end of type")
+	case strings.HasPrefix(rest, "?"):
+		r.pos++
+		return &TypeInfo{Kind: "unknown"}, nil
+	case strings.HasPrefix(rest, "*"):
+		r.pos++
+		elem, err := r.readType()
+		if err != nil {
+			return nil, err
+		}
+		return &TypeInfo{Kind: "pointer", ElemType: elem}, nil
+	case strings.HasPrefix(rest, "[]"):
+		r.pos += 2
+		elem, err := r.readType()
+		if err != nil {
+			return nil, err
+		}
+		return &TypeInfo{Kind: "slice", ElemType: elem}, nil
+	case strings.HasPrefix(rest, "["):
+		return r.readArray()
+	case strings.HasPrefix(rest, "map["):
+		r.pos += len("map[")
+		key, err := r.readType()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(r.src[r.pos:], "]") {
+			return nil, r.errorf("expected ']' after map key")
+		}
+		r.pos++
+		value, err := r.readType()
+		if err != nil {
+			return nil, err
+		}
+		return &TypeInfo{Kind: "map", KeyType: key, ValueType: value}, nil
+	case strings.HasPrefix(rest, "chan "), strings.HasPrefix(rest, "chan<- "), strings.HasPrefix(rest, "<-chan "):
+		dir := ""
+		switch {
+		case strings.HasPrefix(rest, "chan<- "):
+			dir = ChanSend
+			r.pos += len("chan<- ")
+		case strings.HasPrefix(rest, "<-chan "):
+			dir = ChanRecv
+			r.pos += len("<-chan ")
+		default:
+			r.pos += len("chan ")
+		}
+		elem, err := r.readType()
+		if err != nil {
+			return nil, err
+		}
+		return &TypeInfo{Kind: "chan", ElemType: elem, Dir: dir}, nil
+	case strings.HasPrefix(rest, "interface{}"):
+		r.pos += len("interface{}")
+		return &TypeInfo{Kind: "interface", Name: "interface{}"}, nil
+	case strings.HasPrefix(rest, "interface{"):
+		r.pos += len("interface{")
+		fields, err := r.readFields(true)
+		if err != nil {
+			return nil, err
+		}
+		return &TypeInfo{Kind: "interface", Fields: fields}, nil
+	case strings.HasPrefix(rest, "struct{"):
+		r.pos += len("struct{")
+		fields, err := r.readFields(false)
+		if err != nil {
+			return nil, err
+		}
+		return &TypeInfo{Kind: "struct", Fields: fields}, nil
+	case strings.HasPrefix(rest, "func("):
+		r.pos += len("func")
+		return r.readSignature()
+	}
+
+	name := r.readName()
+	if name == "" {
+		return nil, r.errorf("unexpected %q", rest[:1])
+	}
+	typ := &TypeInfo{Kind: "basic", Name: name}
+	if r.consume("[") {
+		args, _, err := r.readList("]", false)
+		if err != nil {
+			return nil, err
+		}
+		if len(args) == 0 {
+			return nil, r.errorf("empty type argument list")
+		}
+		typ.Args = args
+	}
+	return typ, nil
+}
+
+// Reads the parameters and results of a func type, starting at its opening parenthesis
+func (r *typeReader) readSignature() (*TypeInfo, error) {
+	if !r.consume("(") {
+		return nil, r.errorf("expected '('")
+	}
+	params, variadic, err := r.readList(")", true)
+	if err != nil {
+		return nil, err
+	}
+	typ := &TypeInfo{Kind: "func", Params: params, Results: make([]*TypeInfo, 0), Variadic: variadic}
+	switch {
+	case r.consume(" ("):
+		if typ.Results, _, err = r.readList(")", false); err != nil {
+			return nil, err
+		}
+		if len(typ.Results) < 2 {
+			return nil, r.errorf("parenthesized results need several types")
+		}
+	case r.consume(" "):
+		result, err := r.readType()
+		if err != nil {
+			return nil, err
+		}
+		typ.Results = append(typ.Results, result)
+	}
+	return typ, nil
+}
+
+// Reads a comma-separated list of types up to and including the closing delimiter. When
+// variadic is allowed, the last type may be written "...T" and is read as a slice of T.
+func (r *typeReader) readList(closing string, variadic bool) ([]*TypeInfo, bool, error) {
+	types := make([]*TypeInfo, 0)
+	if r.consume(closing) {
+		return types, false, nil
+	}
+	for {
+		isVariadic := variadic && r.consume("...")
+		typ, err := r.readType()
+		if err != nil {
+			return nil, false, err
+		}
+		if isVariadic {
+			typ = &TypeInfo{Kind: "slice", ElemType: typ}
+		}
+		types = append(types, typ)
+		switch {
+		case r.consume(closing):
+			return types, isVariadic, nil
+		case isVariadic:
+			return nil, false, r.errorf("variadic parameter must be last")
+		case !r.consume(", "):
+			return nil, false, r.errorf("expected ', ' or %q", closing)
+		}
+	}
+}
+
+// Reads the fields of a struct or the methods and embedded types of an interface, up to and
+// including the closing brace
+func (r *typeReader) readFields(methods bool) ([]*FieldInfo, error) {
+	fields := make([]*FieldInfo, 0)
+	if r.consume("}") {
+		return fields, nil
+	}
+	for {
+		field := &FieldInfo{}
+		start := r.pos
+		name := r.readName()
+		switch {
+		case methods && name != "" && strings.HasPrefix(r.src[r.pos:], "("):
+			signature, err := r.readSignature()
+			if err != nil {
+				return nil, err
+			}
+			field.Name, field.Type = name, signature
+		case !methods && name != "" && !strings.Contains(name, ".") && r.consume(" "):
+			typ, err := r.readType()
+			if err != nil {
+				return nil, err
+			}
+			field.Name, field.Type = name, typ
+		default:
+			// Embedded type
+			r.pos = start
+			typ, err := r.readType()
+			if err != nil {
+				return nil, err
+			}
+			field.Type = typ
+		}
+		fields = append(fields, field)
+
+		if r.consume("}") {
+			return fields, nil
+		}
+		if !r.consume("; ") {
+			return nil, r.errorf("expected '; ' or '}'")
+		}
+	}
+}
+
+// Skips the given text if it is next, reporting whether it was
+func (r *typeReader) consume(text string) bool {
+	if strings.HasPrefix(r.src[r.pos:], text) {
+		r.pos += len(text)
+		return true
+	}
+	return false
+}
+
+// Reads an array type such as "[4]int" or "[...]int"
+func (r *typeReader) readArray() (*TypeInfo, error) {
+	end := strings.IndexByte(r.src[r.pos:], ']')
+	if end < 0 {
+		return nil, r.errorf("unterminated array length")
+	}
+	lenText := r.src[r.pos+1 : r.pos+end]
+	length := -1
+	if lenText != "..." {
+		n, err := strconv.Atoi(lenText)
+		if err != nil || n < 0 {
+			return nil, r.errorf("invalid array length %q", lenText)
+		}
+		length = n
+	}
+	r.pos += end + 1
+
+	elem, err := r.readType()
+	if err != nil {
+		return nil, err
+	}
+	return &TypeInfo{Kind: "array", ElemType: elem, Len: length}, nil
+}
+
+// Reads a (possibly package-qualified) type name
+func (r *typeReader) readName() string {
+	start := r.pos
+	for r.pos < len(r.src) {
+		c := r.src[r.pos]
+		if c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80 {
+			r.pos++
+			continue
+		}
+		break
+	}
+	return r.src[start:r.pos]
+}
+
+// Returns a parse error annotated with the current position
+func (r *typeReader) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid type %q at offset %d: %s", r.src, r.pos, fmt.Sprintf(format, args...))
+}
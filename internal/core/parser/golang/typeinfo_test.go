@@ -0,0 +1,349 @@
+package goparser_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+func TestTypeInfoString(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeInfo *goparser.TypeInfo
+		expected string
+	}{
+		{"Basic", &goparser.TypeInfo{Kind: "basic", Name: "int"}, "int"},
+		{"Qualified", &goparser.TypeInfo{Kind: "basic", Name: "pkg.Foo"}, "pkg.Foo"},
+		{"Pointer", &goparser.TypeInfo{Kind: "pointer", ElemType: &goparser.TypeInfo{Kind: "basic", Name: "User"}}, "*User"},
+		{"Slice", &goparser.TypeInfo{Kind: "slice", ElemType: &goparser.TypeInfo{Kind: "basic", Name: "byte"}}, "[]byte"},
+		{"Array", &goparser.TypeInfo{Kind: "array", Len: 4, ElemType: &goparser.TypeInfo{Kind: "basic", Name: "int"}}, "[4]int"},
+		{"ArrayUnknownLen", &goparser.TypeInfo{Kind: "array", Len: -1, ElemType: &goparser.TypeInfo{Kind: "basic", Name: "int"}}, "[...]int"},
+		{"Chan", &goparser.TypeInfo{Kind: "chan", ElemType: &goparser.TypeInfo{Kind: "basic", Name: "string"}}, "chan string"},
+		{"SendChan", &goparser.TypeInfo{Kind: "chan", Dir: goparser.ChanSend, ElemType: &goparser.TypeInfo{Kind: "basic", Name: "int"}}, "chan<- int"},
+		{"RecvChan", &goparser.TypeInfo{Kind: "chan", Dir: goparser.ChanRecv, ElemType: &goparser.TypeInfo{Kind: "basic", Name: "int"}}, "<-chan int"},
+		{"Interface", &goparser.TypeInfo{Kind: "interface", Name: "interface{}"}, "interface{}"},
+		{"Unknown", &goparser.TypeInfo{Kind: "unknown"}, "?"},
+		{"Nil", nil, "?"},
+		{"Nested", &goparser.TypeInfo{
+			Kind:    "map",
+			KeyType: &goparser.TypeInfo{Kind: "basic", Name: "string"},
+			ValueType: &goparser.TypeInfo{
+				Kind: "slice",
+				ElemType: &goparser.TypeInfo{
+					Kind:     "pointer",
+					ElemType: &goparser.TypeInfo{Kind: "basic", Name: "pkg.Foo"},
+				},
+			},
+		}, "map[string][]*pkg.Foo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.typeInfo.String(); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseTypeInfo(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		inputs := []string{
+			"int",
+			"float",
+			"pkg.Foo",
+			"*User",
+			"**User",
+			"[]byte",
+			"[16]byte",
+			"[...]string",
+			"map[string]int",
+			"map[string][]*pkg.Foo",
+			"map[pkg.Key]map[string]chan *Event",
+			"chan []int",
+			"chan<- *Event",
+			"<-chan error",
+			"chan <-chan int",
+			"interface{}",
+			"[]interface{}",
+			"?",
+			"*?",
+			"func()",
+			"func(int) error",
+			"func(*Server)",
+			"func(string, ...any) (int, error)",
+			"func() func() bool",
+			"map[string]func(context.Context) error",
+			"struct{}",
+			"struct{Name string; Tags []string}",
+			"struct{pkg.Base; *Node; next func() (int, bool)}",
+			"interface{Close() error}",
+			"interface{io.Reader; Write([]byte) (int, error)}",
+			"Stack[int]",
+			"*Pair[string, []*pkg.Foo]",
+			"pkg.Cache[Key, Stack[func(T) bool]]",
+		}
+
+		for _, input := range inputs {
+			typeInfo, err := goparser.ParseTypeInfo(input)
+			if err != nil {
+				t.Errorf("ParseTypeInfo(%q) failed: %v", input, err)
+				continue
+			}
+			if got := typeInfo.String(); got != input {
+				t.Errorf("Round trip of %q produced %q", input, got)
+			}
+		}
+	})
+
+	t.Run("Structure", func(t *testing.T) {
+		typeInfo, err := goparser.ParseTypeInfo("map[string][]*pkg.Foo")
+		if err != nil {
+			t.Fatalf("ParseTypeInfo failed: %v", err)
+		}
+		if typeInfo.Kind != "map" || typeInfo.KeyType == nil || typeInfo.KeyType.Name != "string" {
+			t.Fatalf("Expected map with string key, got %+v", typeInfo)
+		}
+		value := typeInfo.ValueType
+		if value == nil || value.Kind != "slice" || value.ElemType == nil || value.ElemType.Kind != "pointer" {
+			t.Fatalf("Expected []* value type, got %v", value)
+		}
+		if elem := value.ElemType.ElemType; elem == nil || elem.Kind != "basic" || elem.Name != "pkg.Foo" {
+			t.Errorf("Expected pointer to pkg.Foo, got %v", elem)
+		}
+	})
+
+	t.Run("Func", func(t *testing.T) {
+		typeInfo, err := goparser.ParseTypeInfo("func(string, ...any) (int, error)")
+		if err != nil {
+			t.Fatalf("ParseTypeInfo failed: %v", err)
+		}
+		if typeInfo.Kind != "func" || !typeInfo.Variadic || len(typeInfo.Params) != 2 || len(typeInfo.Results) != 2 {
+			t.Fatalf("Expected variadic func with 2 params and 2 results, got %+v", typeInfo)
+		}
+		if last := typeInfo.Params[1]; last.Kind != "slice" || last.ElemType == nil || last.ElemType.Name != "any" {
+			t.Errorf("Expected the variadic parameter to be []any, got %v", last)
+		}
+	})
+
+	t.Run("Generic", func(t *testing.T) {
+		typeInfo, err := goparser.ParseTypeInfo("*Pair[string, []int]")
+		if err != nil {
+			t.Fatalf("ParseTypeInfo failed: %v", err)
+		}
+		elem := typeInfo.ElemType
+		if typeInfo.Kind != "pointer" || elem == nil || elem.Kind != "basic" || elem.Name != "Pair" || len(elem.Args) != 2 {
+			t.Fatalf("Expected pointer to Pair with 2 type arguments, got %v", typeInfo)
+		}
+		if elem.Args[1].Kind != "slice" {
+			t.Errorf("Expected the second type argument to be a slice, got %v", elem.Args[1])
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		inputs := []string{
+			"",
+			"*",
+			"[]",
+			"map[string",
+			"map[string]",
+			"[x]int",
+			"[-1]int",
+			"int extra",
+			"chan ",
+			"func(",
+			"func(int",
+			"func(...int, string)",
+			"func() (int)",
+			"Stack[]",
+			"Stack[int",
+			"struct{Name string",
+			"interface{Close(}",
+		}
+
+		for _, input := range inputs {
+			if typeInfo, err := goparser.ParseTypeInfo(input); err == nil {
+				t.Errorf("Expected error for %q, got %v", input, typeInfo)
+			}
+		}
+	})
+}
+
+func TestTypeInfoJSON(t *testing.T) {
+	original, err := goparser.ParseTypeInfo("map[string][]*pkg.Foo")
+	if err != nil {
+		t.Fatalf("ParseTypeInfo failed: %v", err)
+	}
+
+	data, err := json.Marshal(map[string]*goparser.TypeInfo{"type": original})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if expected := `{"type":"map[string][]*pkg.Foo"}`; string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	var decoded map[string]*goparser.TypeInfo
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got := decoded["type"].String(); got != original.String() {
+		t.Errorf("Expected %q after decoding, got %q", original.String(), got)
+	}
+}
+
+func TestTypeInfoFromSource(t *testing.T) {
+	src := `
+	package example
+
+	import "example.com/pkg"
+
+	type Stack[T any] struct{ items []T }
+
+	type Pair[K comparable, V any] struct{}
+
+	var (
+		index   map[string][]*pkg.Foo
+		buffer  [16]byte
+		updates chan *pkg.Event
+		stack   *Stack[int]
+		pairs   []Pair[string, *pkg.Foo]
+		handler func(string, ...any) (int, error)
+		point   struct{ X, Y int }
+		closer  interface{ Close() error }
+	)
+	`
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.go")
+	if err := os.WriteFile(testFile, []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	p := goparser.New()
+	root, err := p.ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	expected := map[string]string{
+		"index":   "map[string][]*pkg.Foo",
+		"buffer":  "[16]byte",
+		"updates": "chan *pkg.Event",
+		"stack":   "*Stack[int]",
+		"pairs":   "[]Pair[string, *pkg.Foo]",
+		"handler": "func(string, ...any) (int, error)",
+		"point":   "struct{X int; Y int}",
+		"closer":  "interface{Close() error}",
+	}
+
+	for _, v := range findNodes(root, ast.Variable) {
+		attrs := v.Attributes()
+		name := attrs["name"].(string)
+		typeInfo, ok := attrs["type"].(*goparser.TypeInfo)
+		if !ok {
+			t.Errorf("For variable %s: Expected type to be *TypeInfo, got %T", name, attrs["type"])
+			continue
+		}
+		if got := typeInfo.String(); got != expected[name] {
+			t.Errorf("For variable %s: Expected %q, got %q", name, expected[name], got)
+		}
+	}
+}
+
+// Helper function to collect the types held by an attribute value
+func collectTypes(value any, types []*goparser.TypeInfo) []*goparser.TypeInfo {
+	switch v := value.(type) {
+	case *goparser.TypeInfo:
+		if v != nil {
+			types = append(types, v)
+		}
+	case []*goparser.TypeInfo:
+		for _, typ := range v {
+			types = collectTypes(typ, types)
+		}
+	case map[string]any:
+		for _, item := range v {
+			types = collectTypes(item, types)
+		}
+	case []map[string]any:
+		for _, item := range v {
+			types = collectTypes(item, types)
+		}
+	case []any:
+		for _, item := range v {
+			types = collectTypes(item, types)
+		}
+	}
+	return types
+}
+
+func TestTypeInfoRoundTrip(t *testing.T) {
+	src := `
+	package example
+
+	import "strings"
+
+	const (
+		count   = 3
+		ratio   = 1.5
+		initial = 'a'
+		greeting = "hello"
+		enabled = true
+		mask    = 1 << 4
+		complexValue = 2i
+	)
+
+	var (
+		total    = count * 2
+		builder  strings.Builder
+		replacer = strings.NewReplacer("a", "b")
+		fields   = strings.Fields(greeting)
+		lookup   = map[string]func(int) error{}
+	)
+
+	func scale(v float64) float64 { return v * ratio }
+	`
+
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "example.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// Every type the parser produces, including those of untyped constants, parses back from
+	// its canonical form
+	for _, dir := range []string{tmpDir, "testdata"} {
+		roots, err := goparser.New().ParseDir(dir)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", dir, err)
+		}
+		var types []*goparser.TypeInfo
+		for _, root := range roots {
+			var walk func(ast.Node)
+			walk = func(n ast.Node) {
+				types = collectTypes(n.Attributes(), types)
+				for _, child := range n.Children() {
+					walk(child)
+				}
+			}
+			walk(root)
+		}
+		if len(types) == 0 {
+			t.Fatalf("Expected types in %s", dir)
+		}
+		for _, typ := range types {
+			parsed, err := goparser.ParseTypeInfo(typ.String())
+			if err != nil {
+				t.Errorf("Failed to parse %q: %v", typ.String(), err)
+				continue
+			}
+			if parsed.String() != typ.String() {
+				t.Errorf("Expected %q after parsing, got %q", typ.String(), parsed.String())
+			}
+		}
+	}
+}
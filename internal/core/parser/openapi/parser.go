@@ -0,0 +1,434 @@
+// Provides the OpenAPI specification parser implementation
+package openapiparser
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// Implements the parser.Parser interface for OpenAPI 3 (and Swagger 2) specifications written
+// in YAML or JSON. Schemas become Type nodes and operations Function nodes.
+type Parser struct{}
+
+// Creates a new OpenAPI parser
+func New() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Language() string {
+	return "OpenAPI"
+}
+
+func (p *Parser) FileExtensions() []string {
+	return []string{".yaml", ".yml", ".json"}
+}
+
+// Returned when parsing a YAML or JSON file that is not an OpenAPI specification
+var ErrNotSpec = errors.New("not an OpenAPI specification")
+
+// Matches the top-level openapi or swagger version key of a specification
+var specVersion = regexp.MustCompile(`(?m)^\s*["']?(openapi|swagger)["']?\s*:`)
+
+func (p *Parser) ParseFile(filename string) (ast.Node, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if !specVersion.Match(src) {
+		return nil, fmt.Errorf("%s: %w", filename, ErrNotSpec)
+	}
+	return p.parse(filename, src)
+}
+
+// Parses the OpenAPI specifications of a directory, in file name order. Other YAML and JSON
+// files (e.g. configuration, package.json) are skipped.
+func (p *Parser) ParseDir(dir string) ([]ast.Node, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []ast.Node
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(p.FileExtensions(), filepath.Ext(entry.Name())) {
+			continue
+		}
+		node, err := p.ParseFile(filepath.Join(dir, entry.Name()))
+		if errors.Is(err, ErrNotSpec) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// A specification that could not be decoded
+type SyntaxError struct {
+	Pos ast.Position
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// Matches the line number in YAML decoding errors
+var errorLine = regexp.MustCompile(`line (\d+): `)
+
+// Parses a specification into our generic AST
+func (p *Parser) parse(filename string, src []byte) (ast.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		pos := ast.Position{Filename: filename, Line: 1, Column: 1}
+		msg := strings.TrimPrefix(err.Error(), "yaml: ")
+		if m := errorLine.FindStringSubmatch(msg); m != nil {
+			pos.Line, _ = strconv.Atoi(m[1])
+			msg = strings.Replace(msg, m[0], "", 1)
+		}
+		return nil, &SyntaxError{Pos: pos, Msg: msg}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, &SyntaxError{Pos: ast.Position{Filename: filename, Line: 1, Column: 1}, Msg: "expected a mapping at the top level"}
+	}
+
+	c := &converter{filename: filename, root: doc.Content[0]}
+	return c.convertSpec(), nil
+}
+
+// Converts the document of one specification
+type converter struct {
+	filename string
+	root     *yaml.Node
+	module   *ast.BaseNode
+}
+
+// Returns the value of a key in a mapping node, or nil
+func get(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// Returns the value of a scalar under a key of a mapping node, or an empty string
+func scalar(node *yaml.Node, key string) string {
+	if value := get(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// Calls fn for each key and value of a mapping node, in document order
+func each(node *yaml.Node, fn func(key, value *yaml.Node)) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(node.Content[i], node.Content[i+1])
+	}
+}
+
+// Returns the position of a node
+func (c *converter) pos(node *yaml.Node) ast.Position {
+	return ast.Position{Filename: c.filename, Line: node.Line, Column: node.Column}
+}
+
+// Converts a specification to our generic AST
+func (c *converter) convertSpec() ast.Node {
+	c.module = ast.NewBaseNode(ast.Module, ast.Position{
+		Filename: c.filename,
+		Line:     1,
+		Column:   1,
+	})
+
+	info := get(c.root, "info")
+	title := scalar(info, "title")
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(c.filename), filepath.Ext(c.filename))
+	}
+	version := scalar(c.root, "openapi")
+	if version == "" {
+		version = scalar(c.root, "swagger")
+	}
+
+	// Servers (OpenAPI 3) or basePath (Swagger 2) prefix every path
+	servers := make([]string, 0)
+	if list := get(c.root, "servers"); list != nil {
+		for _, server := range list.Content {
+			servers = append(servers, scalar(server, "url"))
+		}
+	}
+	basePath := scalar(c.root, "basePath")
+	if len(servers) > 0 {
+		basePath = serverPath(servers[0])
+	}
+	basePath = strings.TrimSuffix(basePath, "/")
+
+	c.module.SetAttribute("package_name", title)
+	c.module.SetAttribute("api_version", scalar(info, "version"))
+	c.module.SetAttribute("spec_version", version)
+	c.module.SetAttribute("servers", servers)
+	c.module.SetAttribute("base_path", basePath)
+	c.module.SetAttribute("dependencies", make([]string, 0))
+
+	schemas := get(get(c.root, "components"), "schemas")
+	if schemas == nil {
+		schemas = get(c.root, "definitions")
+	}
+	each(schemas, func(key, value *yaml.Node) {
+		c.module.AddChild(c.convertSchema(key.Value, key, value, false))
+	})
+
+	each(get(c.root, "paths"), func(path, item *yaml.Node) {
+		each(item, func(method, op *yaml.Node) {
+			if slices.Contains(httpMethods, method.Value) {
+				c.convertOperation(basePath, path.Value, method, op, get(item, "parameters"))
+			}
+		})
+	})
+	return c.module
+}
+
+// Returns the path of a server URL (e.g. "/v1" for "https://api.example.com/v1"), ignoring
+// server variables in the host
+func serverPath(server string) string {
+	if !strings.Contains(server, "://") {
+		return server
+	}
+	u, err := url.Parse(strings.NewReplacer("{", "", "}", "").Replace(server))
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
+
+// The methods of a path item
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Converts a named schema to a Type node. Object properties become fields; the properties of
+// allOf members are included, with referenced members recorded as bases.
+func (c *converter) convertSchema(name string, key, schema *yaml.Node, inline bool) ast.Node {
+	node := ast.NewBaseNode(ast.Type, c.pos(key))
+	fields := make([]map[string]any, 0)
+	bases := make([]*TypeInfo, 0)
+
+	members := []*yaml.Node{schema}
+	if allOf := get(schema, "allOf"); allOf != nil {
+		members = append(members, allOf.Content...)
+	}
+	for _, member := range members {
+		if ref := scalar(member, "$ref"); ref != "" {
+			bases = append(bases, &TypeInfo{Name: refName(ref)})
+			continue
+		}
+		required := make(map[string]bool)
+		if list := get(member, "required"); list != nil {
+			for _, field := range list.Content {
+				required[field.Value] = true
+			}
+		}
+		each(get(member, "properties"), func(prop, value *yaml.Node) {
+			fields = append(fields, map[string]any{
+				"name":        prop.Value,
+				"type":        typeOf(value),
+				"required":    required[prop.Value],
+				"is_exported": true,
+				"position":    c.pos(prop),
+			})
+		})
+	}
+
+	node.SetAttribute("name", name)
+	node.SetAttribute("kind", KindSchema)
+	node.SetAttribute("is_exported", true)
+	node.SetAttribute("is_inline", inline)
+	node.SetAttribute("type", typeOf(schema))
+	node.SetAttribute("fields", fields)
+	node.SetAttribute("bases", bases)
+	node.SetAttribute("description", scalar(schema, "description"))
+	return node
+}
+
+// Kinds of definitions
+const (
+	KindSchema    = "schema"
+	KindOperation = "operation"
+)
+
+// Converts an operation to a Function node. Inline object schemas of its JSON request and
+// responses are added as schemas named after the operation (e.g. createUserRequest).
+func (c *converter) convertOperation(basePath, path string, method, op, pathParameters *yaml.Node) {
+	node := ast.NewBaseNode(ast.Function, c.pos(method))
+	id := scalar(op, "operationId")
+	name := id
+	if name == "" {
+		name = strings.ToUpper(method.Value) + " " + path
+	}
+
+	parameters := make([]map[string]any, 0)
+	var request *TypeInfo
+	for _, list := range []*yaml.Node{pathParameters, get(op, "parameters")} {
+		if list == nil {
+			continue
+		}
+		for _, param := range list.Content {
+			if scalar(param, "in") == "body" {
+				// Swagger 2 request bodies are parameters
+				request = c.schemaType(name+"Request", get(param, "schema"))
+				continue
+			}
+			parameters = append(parameters, map[string]any{
+				"name":     scalar(param, "name"),
+				"in":       scalar(param, "in"),
+				"required": scalar(param, "required") == "true",
+				"type":     parameterType(param),
+			})
+		}
+	}
+	if body := get(op, "requestBody"); body != nil {
+		request = c.schemaType(name+"Request", mediaSchema(body))
+	}
+
+	responses := make([]*TypeInfo, 0)
+	statuses := make([]string, 0)
+	each(get(op, "responses"), func(status, response *yaml.Node) {
+		statuses = append(statuses, status.Value)
+		if strings.HasPrefix(status.Value, "2") {
+			if typ := c.schemaType(name+"Response", mediaSchema(response)); typ != nil {
+				responses = append(responses, typ)
+			}
+		}
+	})
+
+	tags := make([]string, 0)
+	if list := get(op, "tags"); list != nil {
+		for _, tag := range list.Content {
+			tags = append(tags, tag.Value)
+		}
+	}
+
+	node.SetAttribute("name", name)
+	node.SetAttribute("kind", KindOperation)
+	node.SetAttribute("is_exported", true)
+	node.SetAttribute("operation_id", id)
+	node.SetAttribute("method", strings.ToUpper(method.Value))
+	node.SetAttribute("path", basePath+path)
+	node.SetAttribute("spec_path", path)
+	node.SetAttribute("summary", scalar(op, "summary"))
+	node.SetAttribute("tags", tags)
+	node.SetAttribute("deprecated", scalar(op, "deprecated") == "true")
+	node.SetAttribute("parameters", parameters)
+	node.SetAttribute("request_type", request)
+	node.SetAttribute("response_types", responses)
+	node.SetAttribute("statuses", statuses)
+	c.module.AddChild(node)
+}
+
+// Returns the type of a request or response schema. Inline object schemas are added to the
+// module under the given name.
+func (c *converter) schemaType(name string, schema *yaml.Node) *TypeInfo {
+	if schema == nil {
+		return nil
+	}
+	if get(schema, "properties") != nil && get(schema, "$ref") == nil {
+		c.module.AddChild(c.convertSchema(name, schema, schema, true))
+		return &TypeInfo{Name: name}
+	}
+	return typeOf(schema)
+}
+
+// Returns the JSON schema of a request body or response: its application/json content, the
+// first JSON-like content, or the schema of a Swagger 2 response
+func mediaSchema(node *yaml.Node) *yaml.Node {
+	content := get(node, "content")
+	if content == nil {
+		return get(node, "schema")
+	}
+	if media := get(content, "application/json"); media != nil {
+		return get(media, "schema")
+	}
+	var types []string
+	each(content, func(key, _ *yaml.Node) { types = append(types, key.Value) })
+	sort.Strings(types)
+	for _, typ := range types {
+		if strings.Contains(typ, "json") {
+			return get(get(content, typ), "schema")
+		}
+	}
+	if len(types) > 0 {
+		return get(get(content, types[0]), "schema")
+	}
+	return nil
+}
+
+// Returns the type of a parameter, from its schema (OpenAPI 3) or its type (Swagger 2)
+func parameterType(param *yaml.Node) *TypeInfo {
+	if schema := get(param, "schema"); schema != nil {
+		return typeOf(schema)
+	}
+	return typeOf(param)
+}
+
+// Returns the name a reference points to (e.g. "User" for "#/components/schemas/User")
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// Returns the type of a schema
+func typeOf(schema *yaml.Node) *TypeInfo {
+	if schema == nil {
+		return &TypeInfo{}
+	}
+	if ref := scalar(schema, "$ref"); ref != "" {
+		return &TypeInfo{Name: refName(ref)}
+	}
+	for _, combinator := range []string{"oneOf", "anyOf", "allOf"} {
+		list := get(schema, combinator)
+		if list == nil {
+			continue
+		}
+		if combinator == "allOf" && len(list.Content) == 1 {
+			return typeOf(list.Content[0])
+		}
+		typ := &TypeInfo{Name: combinator}
+		for _, member := range list.Content {
+			typ.Args = append(typ.Args, typeOf(member))
+		}
+		return typ
+	}
+
+	switch name := scalar(schema, "type"); {
+	case name == "array":
+		return &TypeInfo{Name: name, Args: []*TypeInfo{typeOf(get(schema, "items"))}}
+	case get(schema, "additionalProperties") != nil && get(schema, "properties") == nil:
+		value := get(schema, "additionalProperties")
+		if value.Kind == yaml.ScalarNode {
+			value = nil // additionalProperties: true
+		}
+		return &TypeInfo{Name: "map", Args: []*TypeInfo{{Name: "string"}, typeOf(value)}}
+	case name == "" && get(schema, "properties") != nil:
+		return &TypeInfo{Name: "object"}
+	default:
+		return &TypeInfo{Name: name}
+	}
+}
@@ -0,0 +1,159 @@
+package openapiparser_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	openapiparser "codedna/internal/core/parser/openapi"
+)
+
+// Helper function to find a named child node of a specific type
+func findNode(t *testing.T, root ast.Node, nodeType ast.NodeType, name string) ast.Node {
+	t.Helper()
+	for _, child := range root.Children() {
+		if child.Type() == string(nodeType) && child.Attributes()["name"] == name {
+			return child
+		}
+	}
+	t.Fatalf("%s %s not found", nodeType, name)
+	return nil
+}
+
+// Helper function to describe the fields of a schema as "name:type", with a ! for required fields
+func fields(node ast.Node) []string {
+	var result []string
+	for _, field := range node.Attributes()["fields"].([]map[string]any) {
+		required := ""
+		if field["required"] == true {
+			required = "!"
+		}
+		result = append(result, fmt.Sprintf("%s%s:%s", field["name"], required, field["type"]))
+	}
+	return result
+}
+
+func TestParseFile(t *testing.T) {
+	root, err := openapiparser.New().ParseFile(filepath.Join("testdata", "api", "users.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	t.Run("Module", func(t *testing.T) {
+		attrs := root.Attributes()
+		if root.Type() != string(ast.Module) || attrs["package_name"] != "Users API" || attrs["api_version"] != "1.2.0" {
+			t.Errorf("Expected module Users API 1.2.0, got %s %v %v", root.Type(), attrs["package_name"], attrs["api_version"])
+		}
+		if attrs["spec_version"] != "3.0.3" || attrs["base_path"] != "/v1" {
+			t.Errorf("Expected OpenAPI 3.0.3 with base path /v1, got %v %v", attrs["spec_version"], attrs["base_path"])
+		}
+	})
+
+	t.Run("Schemas", func(t *testing.T) {
+		user := findNode(t, root, ast.Type, "User")
+		if pos := user.Position(); pos.Line != 63 || pos.Column != 5 {
+			t.Errorf("Expected User at 63:5, got %d:%d", pos.Line, pos.Column)
+		}
+		expected := []string{"id!:string", "full_name:string", "tags:array<string>", "labels:map<string, integer>"}
+		if got := fields(user); !slices.Equal(got, expected) {
+			t.Errorf("Expected fields %v, got %v", expected, got)
+		}
+
+		admin := findNode(t, root, ast.Type, "Admin")
+		if got := fields(admin); !slices.Equal(got, []string{"role:string"}) {
+			t.Errorf("Expected the role field, got %v", got)
+		}
+		if bases := admin.Attributes()["bases"].([]*openapiparser.TypeInfo); len(bases) != 1 || bases[0].Name != "User" {
+			t.Errorf("Expected Admin to extend User, got %v", bases)
+		}
+
+		request := findNode(t, root, ast.Type, "createUserRequest")
+		if request.Attributes()["is_inline"] != true {
+			t.Error("Expected createUserRequest to be an inline schema")
+		}
+		if got := fields(request); !slices.Equal(got, []string{"full_name!:string", "email:string"}) {
+			t.Errorf("Expected request fields, got %v", got)
+		}
+	})
+
+	t.Run("Operations", func(t *testing.T) {
+		var got []string
+		for _, child := range root.Children() {
+			if child.Type() != string(ast.Function) {
+				continue
+			}
+			attrs := child.Attributes()
+			got = append(got, fmt.Sprintf("%s %s %s req=%s resp=%v", attrs["name"], attrs["method"], attrs["path"], attrs["request_type"], attrs["response_types"]))
+		}
+		expected := []string{
+			"listUsers GET /v1/users req=? resp=[array<User>]",
+			"createUser POST /v1/users req=createUserRequest resp=[User]",
+			"DELETE /users/{userId} DELETE /v1/users/{userId} req=? resp=[]",
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("Expected operations %q, got %q", expected, got)
+		}
+
+		del := findNode(t, root, ast.Function, "DELETE /users/{userId}").Attributes()
+		params := del["parameters"].([]map[string]any)
+		if len(params) != 1 || params[0]["name"] != "userId" || params[0]["in"] != "path" || params[0]["required"] != true {
+			t.Errorf("Expected the path-level userId parameter, got %v", params)
+		}
+		if del["deprecated"] != true || !slices.Equal(del["statuses"].([]string), []string{"204"}) {
+			t.Errorf("Expected a deprecated operation with status 204, got %v %v", del["deprecated"], del["statuses"])
+		}
+	})
+}
+
+func TestParseDir(t *testing.T) {
+	nodes, err := openapiparser.New().ParseDir(filepath.Join("testdata", "api"))
+	if err != nil {
+		t.Fatalf("Failed to parse directory: %v", err)
+	}
+	var titles []string
+	for _, node := range nodes {
+		titles = append(titles, node.Attributes()["package_name"].(string))
+	}
+	// config.yaml is not a specification
+	if !slices.Equal(titles, []string{"Legacy API", "Users API"}) {
+		t.Fatalf("Expected the Legacy and Users APIs, got %v", titles)
+	}
+
+	t.Run("Swagger", func(t *testing.T) {
+		legacy := nodes[0]
+		if legacy.Attributes()["base_path"] != "/legacy" {
+			t.Errorf("Expected base path /legacy, got %v", legacy.Attributes()["base_path"])
+		}
+		if got := fields(findNode(t, legacy, ast.Type, "Account")); !slices.Equal(got, []string{"id:integer", "owner:string"}) {
+			t.Errorf("Expected Account fields, got %v", got)
+		}
+		op := findNode(t, legacy, ast.Function, "createAccount").Attributes()
+		if op["path"] != "/legacy/accounts" || op["request_type"].(*openapiparser.TypeInfo).Name != "Account" {
+			t.Errorf("Expected POST /legacy/accounts with an Account body, got %v %v", op["path"], op["request_type"])
+		}
+	})
+}
+
+func TestErrors(t *testing.T) {
+	t.Run("NotSpec", func(t *testing.T) {
+		_, err := openapiparser.New().ParseFile(filepath.Join("testdata", "api", "config.yaml"))
+		if !errors.Is(err, openapiparser.ErrNotSpec) {
+			t.Errorf("Expected ErrNotSpec, got %v", err)
+		}
+	})
+
+	t.Run("BrokenFile", func(t *testing.T) {
+		_, err := openapiparser.New().ParseFile(filepath.Join("testdata", "broken.yaml"))
+		var syntaxErr *openapiparser.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("Expected SyntaxError, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "broken.yaml:4:1: did not find expected ',' or ']'") {
+			t.Errorf("Expected positioned error, got %v", err)
+		}
+	})
+}
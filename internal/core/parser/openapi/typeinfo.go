@@ -0,0 +1,47 @@
+package openapiparser
+
+import "strings"
+
+// TypeInfo represents the type of a schema, property or parameter
+type TypeInfo struct {
+	Name string      // The JSON schema type (e.g. "string", "array"), a referenced schema name, or "oneOf", "anyOf" or "allOf"; empty if unknown
+	Args []*TypeInfo // The item type of arrays, the key and value types of maps, and the members of combinations
+}
+
+// Returns the canonical form of the type (e.g. "array<User>", "map<string, integer>")
+func (t *TypeInfo) String() string {
+	if t == nil || t.Name == "" {
+		return "?"
+	}
+	if len(t.Args) == 0 {
+		return t.Name
+	}
+	args := make([]string, len(t.Args))
+	for i, arg := range t.Args {
+		args[i] = arg.String()
+	}
+	return t.Name + "<" + strings.Join(args, ", ") + ">"
+}
+
+// Returns the schemas the type refers to: itself, or those of its arguments
+func (t *TypeInfo) Schemas() []string {
+	if t == nil || t.Name == "" {
+		return nil
+	}
+	if len(t.Args) > 0 {
+		var names []string
+		for _, arg := range t.Args {
+			names = append(names, arg.Schemas()...)
+		}
+		return names
+	}
+	if primitiveTypes[t.Name] {
+		return nil
+	}
+	return []string{t.Name}
+}
+
+// The JSON schema types that do not refer to a schema
+var primitiveTypes = map[string]bool{
+	"string": true, "number": true, "integer": true, "boolean": true, "object": true, "array": true, "null": true,
+}
@@ -0,0 +1,192 @@
+package protoparser
+
+import (
+	"fmt"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// The kind of a lexical token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+)
+
+// A lexical token. Identifiers include their dots (e.g. "google.protobuf.Timestamp").
+type token struct {
+	kind tokenKind
+	text string
+	pos  ast.Position
+}
+
+// Checks if the token is the given punctuation or keyword
+func (t token) is(text string) bool {
+	return (t.kind == tokOp || t.kind == tokIdent) && t.text == text
+}
+
+// A syntax error with its position
+type SyntaxError struct {
+	Pos ast.Position
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// Splits protobuf source into tokens
+type lexer struct {
+	filename string
+	src      string
+	off      int
+	line     int
+	col      int
+}
+
+// Tokenizes the source, skipping whitespace and comments. The last token is always tokEOF.
+func tokenize(filename, src string) ([]token, error) {
+	l := &lexer{filename: filename, src: src, line: 1, col: 1}
+	var tokens []token
+
+	for l.off < len(l.src) {
+		c := l.src[l.off]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			l.advance(1)
+
+		case strings.HasPrefix(l.src[l.off:], "//"):
+			for l.off < len(l.src) && l.src[l.off] != '\n' {
+				l.advance(1)
+			}
+
+		case strings.HasPrefix(l.src[l.off:], "/*"):
+			pos := l.pos()
+			end := strings.Index(l.src[l.off+2:], "*/")
+			if end < 0 {
+				return nil, &SyntaxError{Pos: pos, Msg: "comment not terminated"}
+			}
+			l.advance(end + 4)
+
+		case isIdentStart(c) || c == '.' && l.off+1 < len(l.src) && isIdentStart(l.src[l.off+1]):
+			pos := l.pos()
+			start := l.off
+			l.advance(1)
+			for l.off < len(l.src) && (isIdentChar(l.src[l.off]) ||
+				l.src[l.off] == '.' && l.off+1 < len(l.src) && isIdentStart(l.src[l.off+1])) {
+				l.advance(1)
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: l.src[start:l.off], pos: pos})
+
+		case isDigit(c) || (c == '-' || c == '.') && l.off+1 < len(l.src) && isDigit(l.src[l.off+1]):
+			pos := l.pos()
+			start := l.off
+			l.advance(1)
+			for l.off < len(l.src) && (isIdentChar(l.src[l.off]) || l.src[l.off] == '.' ||
+				(l.src[l.off] == '-' || l.src[l.off] == '+') && (l.src[l.off-1] == 'e' || l.src[l.off-1] == 'E')) {
+				l.advance(1)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: l.src[start:l.off], pos: pos})
+
+		case c == '"' || c == '\'':
+			tok, err := l.string()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+
+		case strings.IndexByte("{}[]()<>;,=:/-+", c) >= 0:
+			tokens = append(tokens, token{kind: tokOp, text: string(c), pos: l.pos()})
+			l.advance(1)
+
+		default:
+			return nil, &SyntaxError{Pos: l.pos(), Msg: fmt.Sprintf("invalid character %q", c)}
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: l.pos()}), nil
+}
+
+// Lexes a quoted string, keeping its quotes and escapes
+func (l *lexer) string() (token, error) {
+	pos := l.pos()
+	start := l.off
+	quote := l.src[l.off]
+	l.advance(1)
+	for l.off < len(l.src) && l.src[l.off] != quote {
+		switch l.src[l.off] {
+		case '\\':
+			l.advance(1)
+		case '\n':
+			return token{}, &SyntaxError{Pos: pos, Msg: "string literal not terminated"}
+		}
+		l.advance(1)
+	}
+	if l.off >= len(l.src) {
+		return token{}, &SyntaxError{Pos: pos, Msg: "string literal not terminated"}
+	}
+	l.advance(1)
+	return token{kind: tokString, text: l.src[start:l.off], pos: pos}, nil
+}
+
+// Moves forward n bytes, tracking lines and columns
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.off < len(l.src); i++ {
+		if l.src[l.off] == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.off++
+	}
+}
+
+// Returns the current position
+func (l *lexer) pos() ast.Position {
+	return ast.Position{Filename: l.filename, Line: l.line, Column: l.col, Offset: l.off}
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// Returns the value of a quoted string token, with simple escapes resolved
+func stringValue(text string) string {
+	if len(text) < 2 {
+		return text
+	}
+	text = text[1 : len(text)-1]
+	if !strings.Contains(text, `\`) {
+		return text
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' || i+1 == len(text) {
+			b.WriteByte(text[i])
+			continue
+		}
+		i++
+		switch text[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,648 @@
+// Provides the Protocol Buffers schema parser implementation
+package protoparser
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// Implements the parser.Parser interface for .proto files. Messages and enums become Type
+// nodes, services Interface nodes with a Method node per RPC.
+type Parser struct{}
+
+// Creates a new protobuf parser
+func New() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Language() string {
+	return "Protobuf"
+}
+
+func (p *Parser) FileExtensions() []string {
+	return []string{".proto"}
+}
+
+func (p *Parser) ParseFile(filename string) (ast.Node, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return p.parse(filename, string(src))
+}
+
+// Parses the .proto files of a directory, in file name order
+func (p *Parser) ParseDir(dir string) ([]ast.Node, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []ast.Node
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(p.FileExtensions(), filepath.Ext(entry.Name())) {
+			continue
+		}
+		node, err := p.ParseFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// Parses protobuf source into our generic AST
+func (p *Parser) parse(filename, src string) (ast.Node, error) {
+	tokens, err := tokenize(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	c := &converter{filename: filename, tokens: tokens}
+	node := c.convertFile()
+	if c.err != nil {
+		return nil, c.err
+	}
+	return node, nil
+}
+
+// The HTTP mapping of an RPC, from its google.api.http option
+type HTTPRule struct {
+	Method string // e.g. "GET"
+	Path   string // The path template (e.g. "/v1/users/{user_id}")
+	Body   string // The request field sent as the body, "*" for the whole request
+}
+
+// Converts the tokens of one file, stopping at the first syntax error
+type converter struct {
+	filename string
+	tokens   []token
+	next     int
+	pkg      string // The package declared by the file
+	module   *ast.BaseNode
+	err      error
+}
+
+// Returns the current token, or EOF once an error was found
+func (c *converter) peek() token {
+	if c.err != nil {
+		return c.tokens[len(c.tokens)-1]
+	}
+	return c.tokens[c.next]
+}
+
+// Consumes and returns the current token
+func (c *converter) advance() token {
+	tok := c.peek()
+	if tok.kind != tokEOF {
+		c.next++
+	}
+	return tok
+}
+
+// Consumes the current token if it is the given punctuation or keyword
+func (c *converter) accept(text string) bool {
+	if c.peek().is(text) {
+		c.advance()
+		return true
+	}
+	return false
+}
+
+// Consumes the given punctuation or keyword, or records an error
+func (c *converter) expect(text string) token {
+	tok := c.advance()
+	if !tok.is(text) {
+		c.errorf(tok, "expected '"+text+"', found "+describe(tok))
+	}
+	return tok
+}
+
+// Consumes an identifier, or records an error
+func (c *converter) ident() string {
+	tok := c.advance()
+	if tok.kind != tokIdent {
+		c.errorf(tok, "expected identifier, found "+describe(tok))
+	}
+	return tok.text
+}
+
+// Consumes a string literal, concatenating adjacent literals, or records an error
+func (c *converter) str() string {
+	tok := c.advance()
+	if tok.kind != tokString {
+		c.errorf(tok, "expected string, found "+describe(tok))
+		return ""
+	}
+	value := stringValue(tok.text)
+	for c.peek().kind == tokString {
+		value += stringValue(c.advance().text)
+	}
+	return value
+}
+
+// Consumes the closing brace of a block. Reaching the end of the file also ends the block,
+// recording an error.
+func (c *converter) closing() bool {
+	if tok := c.peek(); tok.kind == tokEOF {
+		c.errorf(tok, "expected '}', found end of file")
+		return true
+	}
+	return c.accept("}")
+}
+
+// Records a syntax error at a token
+func (c *converter) errorf(tok token, msg string) {
+	if c.err == nil {
+		c.err = &SyntaxError{Pos: tok.pos, Msg: msg}
+	}
+}
+
+// Describes a token for error messages
+func describe(tok token) string {
+	if tok.kind == tokEOF {
+		return "end of file"
+	}
+	return "'" + tok.text + "'"
+}
+
+// Converts a .proto file to our generic AST
+func (c *converter) convertFile() ast.Node {
+	c.module = ast.NewBaseNode(ast.Module, ast.Position{
+		Filename: c.filename,
+		Line:     1,
+		Column:   1,
+	})
+	dependencies := make([]string, 0)
+	options := make(map[string]any)
+	syntax := "proto2" // The default when a file declares no syntax
+
+	for c.peek().kind != tokEOF {
+		tok := c.peek()
+		switch {
+		case c.accept(";"):
+		case c.accept("syntax"), c.accept("edition"):
+			c.expect("=")
+			syntax = c.str()
+			c.expect(";")
+		case c.accept("package"):
+			c.pkg = c.ident()
+			c.expect(";")
+		case tok.is("import"):
+			imp := c.convertImport()
+			c.module.AddChild(imp)
+			dependencies = append(dependencies, imp.Attributes()["path"].(string))
+		case c.accept("option"):
+			c.option(options)
+			c.expect(";")
+		case tok.is("message"):
+			c.convertMessage("")
+		case tok.is("enum"):
+			c.convertEnum("")
+		case tok.is("service"):
+			c.module.AddChild(c.convertService())
+		case c.accept("extend"):
+			c.ident()
+			c.skipBlock()
+		default:
+			c.errorf(tok, "unexpected "+describe(tok))
+		}
+	}
+
+	name := c.pkg
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(c.filename), filepath.Ext(c.filename))
+	}
+	c.module.SetAttribute("package_name", name)
+	c.module.SetAttribute("proto_package", c.pkg)
+	c.module.SetAttribute("syntax", syntax)
+	c.module.SetAttribute("options", options)
+	c.module.SetAttribute("go_package", goPackage(options))
+	c.module.SetAttribute("dependencies", dependencies)
+	return c.module
+}
+
+// Converts an import statement (e.g. import public "common/types.proto";)
+func (c *converter) convertImport() ast.Node {
+	node := ast.NewBaseNode(ast.Import, c.expect("import").pos)
+	public := c.accept("public")
+	weak := !public && c.accept("weak")
+	node.SetAttribute("path", c.str())
+	node.SetAttribute("is_public", public)
+	node.SetAttribute("is_weak", weak)
+	c.expect(";")
+	return node
+}
+
+// Returns the qualified name of a definition within the package
+func (c *converter) fullName(name string) string {
+	if c.pkg == "" {
+		return name
+	}
+	return c.pkg + "." + name
+}
+
+// Converts a message and the messages and enums nested in it. Nested definitions are added to
+// the module with their qualified name (e.g. "User.Address").
+func (c *converter) convertMessage(parent string) {
+	pos := c.expect("message").pos
+	name := c.ident()
+	if parent != "" {
+		name = parent + "." + name
+	}
+	node := ast.NewBaseNode(ast.Type, pos)
+	c.module.AddChild(node)
+
+	fields := make([]map[string]any, 0)
+	oneofs := make([]string, 0)
+	options := make(map[string]any)
+	oneof := ""
+	c.expect("{")
+	for depth := 1; depth > 0; {
+		tok := c.peek()
+		switch {
+		case c.closing():
+			depth-- // Closes a oneof or the message
+			oneof = ""
+		case c.accept(";"):
+		case c.accept("option"):
+			c.option(options)
+			c.expect(";")
+		case tok.is("message"):
+			c.convertMessage(name)
+		case tok.is("enum"):
+			c.convertEnum(name)
+		case c.accept("oneof"):
+			oneof = c.ident()
+			oneofs = append(oneofs, oneof)
+			c.expect("{")
+			depth++
+		case c.accept("reserved"), c.accept("extensions"):
+			c.skipStatement()
+		case c.accept("extend"):
+			c.ident()
+			c.skipBlock()
+		default:
+			if field := c.convertField(oneof); field != nil {
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	node.SetAttribute("name", name)
+	node.SetAttribute("full_name", c.fullName(name))
+	node.SetAttribute("kind", KindMessage)
+	node.SetAttribute("is_exported", true)
+	node.SetAttribute("parent", parent)
+	node.SetAttribute("fields", fields)
+	node.SetAttribute("oneofs", oneofs)
+	node.SetAttribute("options", options)
+}
+
+// Kinds of definitions
+const (
+	KindMessage = "message"
+	KindEnum    = "enum"
+	KindService = "service"
+	KindRPC     = "rpc"
+)
+
+// Field labels
+const (
+	LabelRepeated = "repeated"
+	LabelOptional = "optional"
+	LabelRequired = "required"
+)
+
+// Converts a field (e.g. repeated string tags = 3 [deprecated = true];)
+func (c *converter) convertField(oneof string) map[string]any {
+	pos := c.peek().pos
+	label := ""
+	for _, l := range []string{LabelRepeated, LabelOptional, LabelRequired} {
+		if c.peek().is(l) && c.tokens[c.next+1].kind == tokIdent {
+			label = c.advance().text
+		}
+	}
+
+	var typ *TypeInfo
+	if c.accept("map") {
+		c.expect("<")
+		key := c.ident()
+		c.expect(",")
+		value := c.ident()
+		c.expect(">")
+		typ = &TypeInfo{Name: "map", Args: []*TypeInfo{{Name: key}, {Name: value}}}
+	} else if c.accept("group") {
+		// Proto2 groups declare a nested message; only the field is kept
+		name := c.ident()
+		c.expect("=")
+		number := c.number()
+		c.skipBlock()
+		return map[string]any{
+			"name": strings.ToLower(name), "type": &TypeInfo{Name: name}, "number": number, "label": label,
+			"oneof": oneof, "json_name": jsonName(strings.ToLower(name)), "is_exported": true,
+			"options": make(map[string]any), "position": pos,
+		}
+	} else {
+		typ = &TypeInfo{Name: c.ident()}
+	}
+	name := c.ident()
+	c.expect("=")
+	number := c.number()
+
+	options := c.fieldOptions()
+	c.expect(";")
+	if c.err != nil {
+		return nil
+	}
+	json := jsonName(name)
+	if custom, ok := options["json_name"].(string); ok {
+		json = custom
+	}
+	return map[string]any{
+		"name":        name,
+		"type":        typ,
+		"number":      number,
+		"label":       label,
+		"oneof":       oneof,
+		"json_name":   json,
+		"is_exported": true,
+		"options":     options,
+		"position":    pos,
+	}
+}
+
+// Consumes an integer, or records an error
+func (c *converter) number() int {
+	tok := c.advance()
+	n, err := strconv.ParseInt(tok.text, 0, 64)
+	if tok.kind != tokNumber || err != nil {
+		c.errorf(tok, "expected number, found "+describe(tok))
+	}
+	return int(n)
+}
+
+// Converts the options of a field or enum value (e.g. [deprecated = true, json_name = "id"])
+func (c *converter) fieldOptions() map[string]any {
+	options := make(map[string]any)
+	if !c.accept("[") {
+		return options
+	}
+	for {
+		c.option(options)
+		if !c.accept(",") {
+			break
+		}
+	}
+	c.expect("]")
+	return options
+}
+
+// Converts an enum and its values
+func (c *converter) convertEnum(parent string) {
+	pos := c.expect("enum").pos
+	name := c.ident()
+	if parent != "" {
+		name = parent + "." + name
+	}
+	node := ast.NewBaseNode(ast.Type, pos)
+	c.module.AddChild(node)
+
+	values := make([]map[string]any, 0)
+	options := make(map[string]any)
+	c.expect("{")
+	for !c.closing() {
+		tok := c.peek()
+		switch {
+		case c.accept(";"):
+		case c.accept("option"):
+			c.option(options)
+			c.expect(";")
+		case c.accept("reserved"):
+			c.skipStatement()
+		default:
+			value := c.ident()
+			c.expect("=")
+			number := c.number()
+			c.fieldOptions()
+			c.expect(";")
+			values = append(values, map[string]any{"name": value, "number": number, "position": tok.pos})
+		}
+	}
+
+	node.SetAttribute("name", name)
+	node.SetAttribute("full_name", c.fullName(name))
+	node.SetAttribute("kind", KindEnum)
+	node.SetAttribute("is_exported", true)
+	node.SetAttribute("parent", parent)
+	node.SetAttribute("values", values)
+	node.SetAttribute("options", options)
+}
+
+// Converts a service and its RPCs
+func (c *converter) convertService() ast.Node {
+	node := ast.NewBaseNode(ast.Interface, c.expect("service").pos)
+	name := c.ident()
+	options := make(map[string]any)
+	c.expect("{")
+	for !c.closing() {
+		tok := c.peek()
+		switch {
+		case c.accept(";"):
+		case c.accept("option"):
+			c.option(options)
+			c.expect(";")
+		case tok.is("rpc"):
+			node.AddChild(c.convertRPC(name))
+		default:
+			c.errorf(tok, "unexpected "+describe(tok)+" in service")
+		}
+	}
+
+	node.SetAttribute("name", name)
+	node.SetAttribute("full_name", c.fullName(name))
+	node.SetAttribute("kind", KindService)
+	node.SetAttribute("is_exported", true)
+	node.SetAttribute("options", options)
+	return node
+}
+
+// Converts an RPC (e.g. rpc ListUsers(ListUsersRequest) returns (stream User);)
+func (c *converter) convertRPC(service string) ast.Node {
+	node := ast.NewBaseNode(ast.Method, c.expect("rpc").pos)
+	name := c.ident()
+	c.expect("(")
+	clientStreaming := c.peek().is("stream") && c.tokens[c.next+1].kind == tokIdent && c.accept("stream")
+	request := c.ident()
+	c.expect(")")
+	c.expect("returns")
+	c.expect("(")
+	serverStreaming := c.peek().is("stream") && c.tokens[c.next+1].kind == tokIdent && c.accept("stream")
+	response := c.ident()
+	c.expect(")")
+
+	options := make(map[string]any)
+	if c.accept("{") {
+		for !c.closing() {
+			if c.accept(";") {
+				continue
+			}
+			c.expect("option")
+			c.option(options)
+			c.expect(";")
+		}
+	} else {
+		c.expect(";")
+	}
+
+	node.SetAttribute("name", name)
+	node.SetAttribute("full_name", c.fullName(service+"."+name))
+	node.SetAttribute("kind", KindRPC)
+	node.SetAttribute("is_exported", true)
+	node.SetAttribute("receiver_type", &TypeInfo{Name: service})
+	node.SetAttribute("request_type", &TypeInfo{Name: request})
+	node.SetAttribute("response_type", &TypeInfo{Name: response})
+	node.SetAttribute("client_streaming", clientStreaming)
+	node.SetAttribute("server_streaming", serverStreaming)
+	node.SetAttribute("options", options)
+	node.SetAttribute("http", httpRule(options))
+	return node
+}
+
+// Converts an option assignment into options. Names in parentheses are custom options and are
+// stored without them (e.g. "google.api.http"); aggregate values become nested maps.
+func (c *converter) option(options map[string]any) {
+	var name strings.Builder
+	for !c.peek().is("=") && c.peek().kind != tokEOF {
+		tok := c.advance()
+		if !tok.is("(") && !tok.is(")") {
+			name.WriteString(tok.text)
+		}
+	}
+	c.expect("=")
+	options[name.String()] = c.constant()
+}
+
+// Converts an option value: an identifier, number, string or aggregate in text format
+func (c *converter) constant() any {
+	tok := c.peek()
+	switch {
+	case tok.kind == tokString:
+		return c.str()
+	case tok.is("{"):
+		return c.aggregate()
+	case tok.is("-"), tok.is("+"):
+		c.advance()
+		return tok.text + c.advance().text
+	case tok.kind == tokIdent || tok.kind == tokNumber:
+		return c.advance().text
+	}
+	c.errorf(tok, "expected constant, found "+describe(tok))
+	return nil
+}
+
+// Converts an aggregate value (e.g. { get: "/v1/users" body: "*" }). Only the first value of
+// repeated keys is kept.
+func (c *converter) aggregate() map[string]any {
+	values := make(map[string]any)
+	c.expect("{")
+	for !c.closing() {
+		if c.accept(",") || c.accept(";") {
+			continue
+		}
+		key := c.advance().text
+		c.accept(":")
+		var value any
+		if c.accept("[") {
+			var list []any
+			for !c.accept("]") && c.peek().kind != tokEOF {
+				if !c.accept(",") {
+					list = append(list, c.constant())
+				}
+			}
+			value = list
+		} else {
+			value = c.constant()
+		}
+		if _, ok := values[key]; !ok {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// Skips a statement up to and including its semicolon
+func (c *converter) skipStatement() {
+	for !c.accept(";") && c.peek().kind != tokEOF {
+		c.advance()
+	}
+}
+
+// Skips a block in braces, including nested blocks
+func (c *converter) skipBlock() {
+	c.expect("{")
+	for depth := 1; depth > 0; {
+		switch {
+		case c.closing():
+			depth--
+		case c.advance().is("{"):
+			depth++
+		}
+	}
+}
+
+// HTTP methods of the google.api.http option
+var httpMethods = []string{"get", "put", "post", "delete", "patch"}
+
+// Returns the HTTP mapping declared by the google.api.http option of an RPC, or nil
+func httpRule(options map[string]any) *HTTPRule {
+	http, ok := options["google.api.http"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	body, _ := http["body"].(string)
+	for _, method := range httpMethods {
+		if path, ok := http[method].(string); ok {
+			return &HTTPRule{Method: strings.ToUpper(method), Path: path, Body: body}
+		}
+	}
+	if custom, ok := http["custom"].(map[string]any); ok {
+		kind, _ := custom["kind"].(string)
+		path, _ := custom["path"].(string)
+		return &HTTPRule{Method: strings.ToUpper(kind), Path: path, Body: body}
+	}
+	return nil
+}
+
+// Returns the Go package name declared by the go_package option (e.g. "usersv1" for
+// "example.com/gen/users/v1;usersv1"), or an empty string
+func goPackage(options map[string]any) string {
+	path, _ := options["go_package"].(string)
+	if i := strings.LastIndexByte(path, ';'); i >= 0 {
+		return path[i+1:]
+	}
+	return path[strings.LastIndexByte(path, '/')+1:]
+}
+
+// Returns the default JSON name of a field: its name in lowerCamelCase (e.g. "userId" for user_id)
+func jsonName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper:
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
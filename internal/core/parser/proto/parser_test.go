@@ -0,0 +1,196 @@
+package protoparser_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	protoparser "codedna/internal/core/parser/proto"
+)
+
+// Helper function to find a named node of a specific type
+func findNode(t *testing.T, root ast.Node, nodeType ast.NodeType, name string) ast.Node {
+	t.Helper()
+	var found ast.Node
+	var walk func(ast.Node)
+	walk = func(n ast.Node) {
+		if n.Type() == string(nodeType) && n.Attributes()["name"] == name {
+			found = n
+		}
+		for _, child := range n.Children() {
+			walk(child)
+		}
+	}
+	walk(root)
+	if found == nil {
+		t.Fatalf("%s %s not found", nodeType, name)
+	}
+	return found
+}
+
+// Helper function to parse protobuf source written to a temporary file
+func parseSource(t *testing.T, src string) (ast.Node, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schema.proto")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	return protoparser.New().ParseFile(path)
+}
+
+func TestParseFile(t *testing.T) {
+	root, err := protoparser.New().ParseFile(filepath.Join("testdata", "users", "users.proto"))
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	t.Run("Module", func(t *testing.T) {
+		attrs := root.Attributes()
+		if root.Type() != string(ast.Module) || attrs["package_name"] != "acme.users.v1" || attrs["syntax"] != "proto3" {
+			t.Errorf("Expected proto3 module acme.users.v1, got %s %v %v", root.Type(), attrs["package_name"], attrs["syntax"])
+		}
+		if attrs["go_package"] != "usersv1" {
+			t.Errorf("Expected Go package usersv1, got %v", attrs["go_package"])
+		}
+		if options := attrs["options"].(map[string]any); options["java_multiple_files"] != "true" {
+			t.Errorf("Expected java_multiple_files option, got %v", options)
+		}
+		want := []string{"google/api/annotations.proto", "google/protobuf/timestamp.proto"}
+		if deps := attrs["dependencies"].([]string); !slices.Equal(deps, want) {
+			t.Errorf("Expected dependencies %v, got %v", want, deps)
+		}
+		if imp := root.Children()[1].Attributes(); imp["is_public"] != true {
+			t.Errorf("Expected public import, got %v", imp)
+		}
+	})
+
+	t.Run("Message", func(t *testing.T) {
+		user := findNode(t, root, ast.Type, "User")
+		attrs := user.Attributes()
+		if attrs["kind"] != protoparser.KindMessage || attrs["full_name"] != "acme.users.v1.User" {
+			t.Errorf("Expected message acme.users.v1.User, got %v %v", attrs["kind"], attrs["full_name"])
+		}
+		if pos := user.Position(); pos.Line != 13 || pos.Column != 1 {
+			t.Errorf("Expected message at 13:1, got %d:%d", pos.Line, pos.Column)
+		}
+
+		var got []string
+		for _, field := range attrs["fields"].([]map[string]any) {
+			got = append(got, fmt.Sprintf("%v %s %s=%d %s %s", field["label"], field["type"], field["name"], field["number"], field["json_name"], field["oneof"]))
+		}
+		expected := []string{
+			" string user_id=1 userId ",
+			" string full_name=2 name ",
+			"repeated string tags=3 tags ",
+			" map<string, Address> addresses=4 addresses ",
+			" google.protobuf.Timestamp created_at=5 createdAt ",
+			" Status status=6 status ",
+			" string email=7 email contact",
+			" string phone=8 phone contact",
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("Expected fields %q, got %q", expected, got)
+		}
+		if oneofs := attrs["oneofs"].([]string); !slices.Equal(oneofs, []string{"contact"}) {
+			t.Errorf("Expected oneof contact, got %v", oneofs)
+		}
+
+		address := findNode(t, root, ast.Type, "User.Address")
+		if address.Attributes()["parent"] != "User" || address.Attributes()["full_name"] != "acme.users.v1.User.Address" {
+			t.Errorf("Expected nested message User.Address, got %v", address.Attributes())
+		}
+	})
+
+	t.Run("Enum", func(t *testing.T) {
+		attrs := findNode(t, root, ast.Type, "Status").Attributes()
+		if attrs["kind"] != protoparser.KindEnum {
+			t.Errorf("Expected enum, got %v", attrs["kind"])
+		}
+		var got []string
+		for _, value := range attrs["values"].([]map[string]any) {
+			got = append(got, fmt.Sprintf("%s=%d", value["name"], value["number"]))
+		}
+		if expected := []string{"STATUS_UNSPECIFIED=0", "STATUS_ACTIVE=1", "STATUS_ENABLED=1"}; !slices.Equal(got, expected) {
+			t.Errorf("Expected values %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Service", func(t *testing.T) {
+		service := findNode(t, root, ast.Interface, "Users")
+		if service.Attributes()["kind"] != protoparser.KindService || len(service.Children()) != 3 {
+			t.Fatalf("Expected service with 3 RPCs, got %v with %d children", service.Attributes()["kind"], len(service.Children()))
+		}
+
+		var got []string
+		for _, rpc := range service.Children() {
+			attrs := rpc.Attributes()
+			http := "-"
+			if rule := attrs["http"].(*protoparser.HTTPRule); rule != nil {
+				http = rule.Method + " " + rule.Path + " " + rule.Body
+			}
+			got = append(got, fmt.Sprintf("%s.%s(%v %s) (%v %s) %s", attrs["receiver_type"], attrs["name"],
+				attrs["client_streaming"], attrs["request_type"], attrs["server_streaming"], attrs["response_type"], http))
+		}
+		expected := []string{
+			"Users.GetUser(false GetUserRequest) (false User) GET /v1/users/{user_id} ",
+			"Users.ListUsers(false ListUsersRequest) (true User) -",
+			"Users.Import(true User) (false ListUsersRequest) POST /v1/users:import *",
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("Expected RPCs %q, got %q", expected, got)
+		}
+	})
+}
+
+func TestParseDir(t *testing.T) {
+	nodes, err := protoparser.New().ParseDir(filepath.Join("testdata", "users"))
+	if err != nil {
+		t.Fatalf("Failed to parse directory: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Attributes()["package_name"] != "acme.users.v1" {
+		t.Errorf("Expected the users module, got %d modules", len(nodes))
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"UnterminatedComment", "syntax = \"proto3\";\n/* open", "2:1: comment not terminated"},
+		{"UnterminatedString", "syntax = \"proto3;\n", "1:10: string literal not terminated"},
+		{"MissingSemicolon", "syntax = \"proto3\"\npackage a;", "2:1: expected ';', found 'package'"},
+		{"UnknownStatement", "syntax = \"proto3\";\nfoo Bar {}", "2:1: unexpected 'foo'"},
+		{"UnclosedMessage", "message A {\n  string a = 1;\n", "expected '}', found end of file"},
+		{"BadRPC", "service S {\n  rpc Get(A) (B);\n}", "2:14: expected 'returns', found '('"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSource(t, tt.src)
+			if err == nil {
+				t.Fatal("Expected syntax error")
+			}
+			var syntaxErr *protoparser.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Expected SyntaxError, got %T", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %q", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("BrokenFile", func(t *testing.T) {
+		_, err := protoparser.New().ParseFile(filepath.Join("testdata", "broken.proto"))
+		if err == nil || !strings.Contains(err.Error(), "broken.proto:4:17: expected number, found ';'") {
+			t.Errorf("Expected positioned number error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package protoparser
+
+import "strings"
+
+// TypeInfo represents the type of a field or the request or response of an RPC
+type TypeInfo struct {
+	Name string      // The type as written (e.g. "string", "User", "google.protobuf.Timestamp"), or "map"
+	Args []*TypeInfo // The key and value types of a map
+}
+
+// Returns the canonical form of the type (e.g. "map<string, User>")
+func (t *TypeInfo) String() string {
+	if t == nil || t.Name == "" {
+		return "?"
+	}
+	if len(t.Args) == 0 {
+		return t.Name
+	}
+	args := make([]string, len(t.Args))
+	for i, arg := range t.Args {
+		args[i] = arg.String()
+	}
+	return t.Name + "<" + strings.Join(args, ", ") + ">"
+}
+
+// Returns the unqualified name of the type (e.g. "Timestamp" for "google.protobuf.Timestamp")
+func (t *TypeInfo) BaseName() string {
+	if t == nil {
+		return ""
+	}
+	return t.Name[strings.LastIndex(t.Name, ".")+1:]
+}
+
+// Checks if the type is a scalar value type (e.g. int32, string, bytes)
+func (t *TypeInfo) IsScalar() bool {
+	return t != nil && scalarTypes[t.Name]
+}
+
+// Returns the message and enum types the type refers to: itself, or the value type of a map
+func (t *TypeInfo) Messages() []*TypeInfo {
+	if t == nil || t.IsScalar() {
+		return nil
+	}
+	if t.Name == "map" && len(t.Args) == 2 {
+		return t.Args[1].Messages()
+	}
+	return []*TypeInfo{t}
+}
+
+// The scalar value types
+var scalarTypes = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true,
+	"bool": true, "string": true, "bytes": true,
+}
@@ -0,0 +1,174 @@
+package pyparser
+
+import (
+	"slices"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// An HTTP request made through a client library (requests, httpx, aiohttp, urllib)
+type HTTPCall struct {
+	Client   string // The client expression (e.g. "requests", "self.session")
+	Method   string // The HTTP method (e.g. "GET")
+	URL      string // The requested URL; parts that are not literals are written as {} (e.g. "{}/users/{user_id}")
+	Position ast.Position
+}
+
+// Client methods named after the HTTP method they send
+var httpMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// Finds the HTTP requests made by a function: calls of an HTTP method on a client
+// (requests.get(url), self.session.post(url)), client.request(method, url) and urlopen(url).
+// Only calls whose URL looks like a path or an absolute URL are kept.
+func httpCalls(inline []token, body []*stmt) []*HTTPCall {
+	calls := make([]*HTTPCall, 0)
+	var visit func(tokens []token, body []*stmt)
+	visit = func(tokens []token, body []*stmt) {
+		for i := range tokens {
+			if call := httpCallAt(tokens, i); call != nil {
+				calls = append(calls, call)
+			}
+		}
+		for _, s := range body {
+			visit(s.tokens, s.body)
+		}
+	}
+	visit(inline, body)
+	return calls
+}
+
+// Returns the HTTP request made by a call starting at tokens[i], or nil
+func httpCallAt(tokens []token, i int) *HTTPCall {
+	if i > 0 && tokens[i-1].is(".") {
+		return nil // Inside a dotted name
+	}
+	name, n := dottedName(tokens[i:])
+	if name == "" || i+n >= len(tokens) || !tokens[i+n].is("(") {
+		return nil
+	}
+	open := i + n
+	args := splitTopLevel(tokens[open+1:matchingBracket(tokens, open)], ",")
+
+	client, method, _ := cutLast(name, ".")
+	call := &HTTPCall{Client: client, Position: tokens[i].pos}
+	urlArg := 0
+	switch {
+	case client != "" && slices.Contains(httpMethods, method):
+		call.Method = strings.ToUpper(method)
+	case client != "" && method == "request":
+		verb, ok := literal(args, 0)
+		if !ok {
+			return nil
+		}
+		call.Method = strings.ToUpper(verb)
+		urlArg = 1
+	case method == "urlopen":
+		call.Method = "GET"
+	default:
+		return nil
+	}
+
+	url := keywordArgument(args, "url")
+	if url == nil && urlArg < len(args) && !isKeywordArgument(args[urlArg]) {
+		url = args[urlArg]
+	}
+	call.URL = urlExpression(url)
+	if !looksLikeURL(call.URL) {
+		return nil
+	}
+	return call
+}
+
+// Splits a dotted name at its last dot (e.g. "self.session.get" into "self.session" and "get")
+func cutLast(name, sep string) (string, string, bool) {
+	i := strings.LastIndex(name, sep)
+	if i < 0 {
+		return "", name, false
+	}
+	return name[:i], name[i+1:], true
+}
+
+// Returns argument i if it is a plain string literal
+func literal(args [][]token, i int) (string, bool) {
+	if i >= len(args) || len(args[i]) != 1 || args[i][0].kind != tokString {
+		return "", false
+	}
+	return stringValue(args[i][0].text), true
+}
+
+// Checks if an argument is passed by keyword (name=value)
+func isKeywordArgument(arg []token) bool {
+	return len(arg) > 1 && arg[0].kind == tokName && arg[1].is("=")
+}
+
+// Returns the value of a keyword argument, or nil
+func keywordArgument(args [][]token, name string) []token {
+	for _, arg := range args {
+		if isKeywordArgument(arg) && arg[0].text == name {
+			return arg[2:]
+		}
+	}
+	return nil
+}
+
+// Renders a URL expression: string literals (including adjacent and concatenated ones) are kept,
+// other operands become {}
+func urlExpression(tokens []token) string {
+	var b strings.Builder
+	for _, part := range splitTopLevel(tokens, "+") {
+		if len(part) == 0 {
+			continue
+		}
+		isLiteral := true
+		for _, tok := range part {
+			isLiteral = isLiteral && tok.kind == tokString
+		}
+		if !isLiteral {
+			b.WriteString("{}")
+			continue
+		}
+		for _, tok := range part {
+			value := stringValue(tok.text)
+			if prefix := strings.ToLower(tok.text[:strings.IndexAny(tok.text, `"'`)]); strings.Contains(prefix, "f") {
+				value = placeholders(value)
+			}
+			b.WriteString(value)
+		}
+	}
+	return b.String()
+}
+
+// Replaces the replacement fields of an f-string with {}, keeping escaped braces
+func placeholders(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case strings.HasPrefix(value[i:], "{{"), strings.HasPrefix(value[i:], "}}"):
+			b.WriteByte(value[i])
+			i++
+		case value[i] == '{':
+			depth := 0
+			for ; i < len(value); i++ {
+				if value[i] == '{' {
+					depth++
+				} else if value[i] == '}' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			b.WriteString("{}")
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// Checks if a URL is a path or an absolute HTTP URL, possibly after an unknown base
+func looksLikeURL(url string) bool {
+	url = strings.TrimPrefix(url, "{}")
+	return strings.HasPrefix(url, "/") || strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
@@ -0,0 +1,66 @@
+package pyparser_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	pyparser "codedna/internal/core/parser/python"
+)
+
+func TestHTTPCalls(t *testing.T) {
+	src := `import requests
+from urllib.request import urlopen
+
+BASE = "https://api.example.com"
+
+class UserClient:
+    def __init__(self, session):
+        self.session = session
+
+    def get_user(self, user_id):
+        return self.session.get(f"{BASE}/api/users/{user_id}").json()
+
+    def create_user(self, data):
+        return self.session.post(BASE + "/api/users", json=data)
+
+    def delete_user(self, user_id):
+        self.session.request("DELETE", "/api/users/" + str(user_id))
+
+def health():
+    if requests.head(url="https://api.example.com/health").ok:
+        return urlopen("/status")
+    cache = {}
+    return cache.get("key")
+`
+	root, err := parseSource(t, src)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	expected := map[string][]string{
+		"__init__":    nil,
+		"get_user":    {"self.session GET {}/api/users/{}"},
+		"create_user": {"self.session POST {}/api/users"},
+		"delete_user": {"self.session DELETE /api/users/{}"},
+		"health":      {"requests HEAD https://api.example.com/health", " GET /status"},
+	}
+	for _, fn := range append(findNodes(root, ast.Function), findNodes(root, ast.Method)...) {
+		name := fn.Attributes()["name"].(string)
+		calls, ok := fn.Attributes()["http_calls"].([]*pyparser.HTTPCall)
+		if !ok {
+			t.Fatalf("Expected http_calls to be []*HTTPCall, got %T", fn.Attributes()["http_calls"])
+		}
+		var got []string
+		for _, call := range calls {
+			if call.Position.Line == 0 {
+				t.Errorf("%s: call %s has no position", name, call.URL)
+			}
+			got = append(got, fmt.Sprintf("%s %s %s", call.Client, call.Method, call.URL))
+		}
+		if !slices.Equal(got, expected[name]) {
+			t.Errorf("%s: expected calls %q, got %q", name, expected[name], got)
+		}
+	}
+}
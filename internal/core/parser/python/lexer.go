@@ -0,0 +1,326 @@
+package pyparser
+
+import (
+	"fmt"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// The kind of a lexical token
+type tokenKind int
+
+const (
+	tokName tokenKind = iota
+	tokNumber
+	tokString
+	tokOp
+)
+
+// A lexical token
+type token struct {
+	kind tokenKind
+	text string
+	pos  ast.Position
+}
+
+// Checks if the token is the given operator or keyword
+func (t token) is(text string) bool {
+	return (t.kind == tokOp || t.kind == tokName) && t.text == text
+}
+
+// A logical line: a simple statement or the header of a compound statement,
+// with lines joined inside brackets and after backslashes
+type line struct {
+	indent int
+	tokens []token
+}
+
+// Multi-character operators, longest first
+var operators = []string{
+	"**=", "//=", ">>=", "<<=", "...",
+	"->", "**", "//", ":=", "==", "!=", "<=", ">=", "<<", ">>",
+	"+=", "-=", "*=", "/=", "%=", "@=", "&=", "|=", "^=",
+}
+
+// Splits Python source into logical lines
+type lexer struct {
+	filename string
+	src      string
+	off      int
+	line     int
+	col      int
+}
+
+// A syntax error with its position
+type SyntaxError struct {
+	Pos ast.Position
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// Tokenizes the source into logical lines, skipping blank and comment-only lines
+func tokenize(filename, src string) ([]line, error) {
+	l := &lexer{filename: filename, src: src, line: 1, col: 1}
+	var lines []line
+	var current *line
+	var brackets []token // Open brackets, innermost last
+
+	for l.off < len(l.src) {
+		if current == nil {
+			indent, blank := l.indentation()
+			if blank {
+				continue
+			}
+			current = &line{indent: indent}
+		}
+
+		c := l.src[l.off]
+		switch {
+		case c == ' ' || c == '\t' || c == '\f' || c == '\r':
+			l.advance(1)
+
+		case c == '\n':
+			l.advance(1)
+			if len(brackets) == 0 {
+				lines = appendStatements(lines, *current)
+				current = nil
+			}
+
+		case c == '\\':
+			if !strings.HasPrefix(l.src[l.off+1:], "\n") && !strings.HasPrefix(l.src[l.off+1:], "\r\n") {
+				return nil, l.errorf(l.pos(), "unexpected character after line continuation character")
+			}
+			l.advance(1)
+			if l.src[l.off] == '\r' {
+				l.advance(1)
+			}
+			l.advance(1)
+
+		case c == '#':
+			for l.off < len(l.src) && l.src[l.off] != '\n' {
+				l.advance(1)
+			}
+
+		case isNameStart(c):
+			pos := l.pos()
+			start := l.off
+			for l.off < len(l.src) && isNameChar(l.src[l.off]) {
+				l.advance(1)
+			}
+			if l.off < len(l.src) && isQuote(l.src[l.off]) && isStringPrefix(l.src[start:l.off]) {
+				tok, err := l.string(start, pos)
+				if err != nil {
+					return nil, err
+				}
+				current.tokens = append(current.tokens, tok)
+				continue
+			}
+			current.tokens = append(current.tokens, token{kind: tokName, text: l.src[start:l.off], pos: pos})
+
+		case isQuote(c):
+			tok, err := l.string(l.off, l.pos())
+			if err != nil {
+				return nil, err
+			}
+			current.tokens = append(current.tokens, tok)
+
+		case isDigit(c) || (c == '.' && l.off+1 < len(l.src) && isDigit(l.src[l.off+1])):
+			pos := l.pos()
+			start := l.off
+			for l.off < len(l.src) && (isNameChar(l.src[l.off]) || l.src[l.off] == '.' ||
+				((l.src[l.off] == '+' || l.src[l.off] == '-') && (l.src[l.off-1] == 'e' || l.src[l.off-1] == 'E'))) {
+				l.advance(1)
+			}
+			current.tokens = append(current.tokens, token{kind: tokNumber, text: l.src[start:l.off], pos: pos})
+
+		default:
+			pos := l.pos()
+			text := string(c)
+			for _, op := range operators {
+				if strings.HasPrefix(l.src[l.off:], op) {
+					text = op
+					break
+				}
+			}
+			switch text {
+			case "(", "[", "{":
+				brackets = append(brackets, token{kind: tokOp, text: text, pos: pos})
+			case ")", "]", "}":
+				if len(brackets) == 0 || closing(brackets[len(brackets)-1].text) != text {
+					return nil, l.errorf(pos, "unmatched '%s'", text)
+				}
+				brackets = brackets[:len(brackets)-1]
+			}
+			l.advance(len(text))
+			current.tokens = append(current.tokens, token{kind: tokOp, text: text, pos: pos})
+		}
+	}
+
+	if len(brackets) > 0 {
+		open := brackets[len(brackets)-1]
+		return nil, l.errorf(open.pos, "'%s' was never closed", open.text)
+	}
+	if current != nil {
+		lines = appendStatements(lines, *current)
+	}
+	return lines, nil
+}
+
+// Appends the statements of a logical line, splitting statements separated by semicolons
+func appendStatements(lines []line, l line) []line {
+	start := 0
+	for i, tok := range l.tokens {
+		if tok.is(";") {
+			if i > start {
+				lines = append(lines, line{indent: l.indent, tokens: l.tokens[start:i]})
+			}
+			start = i + 1
+		}
+	}
+	if start < len(l.tokens) {
+		lines = append(lines, line{indent: l.indent, tokens: l.tokens[start:]})
+	}
+	return lines
+}
+
+// Measures the indentation of the line starting at the current offset.
+// Blank and comment-only lines are consumed and reported as blank.
+func (l *lexer) indentation() (int, bool) {
+	indent := 0
+measure:
+	for l.off < len(l.src) {
+		switch l.src[l.off] {
+		case ' ':
+			indent++
+		case '\t':
+			indent = (indent/8 + 1) * 8
+		case '\f':
+			indent = 0
+		default:
+			break measure
+		}
+		l.advance(1)
+	}
+	if l.off >= len(l.src) {
+		return 0, true
+	}
+	switch l.src[l.off] {
+	case '#':
+		for l.off < len(l.src) && l.src[l.off] != '\n' {
+			l.advance(1)
+		}
+		fallthrough
+	case '\r', '\n':
+		for l.off < len(l.src) && l.src[l.off] != '\n' {
+			l.advance(1)
+		}
+		if l.off < len(l.src) {
+			l.advance(1)
+		}
+		return 0, true
+	}
+	return indent, false
+}
+
+// Scans a string literal whose prefix starts at start and whose quote is at the current offset
+func (l *lexer) string(start int, pos ast.Position) (token, error) {
+	quote := l.src[l.off]
+	delimiter := string(quote)
+	if strings.HasPrefix(l.src[l.off:], strings.Repeat(delimiter, 3)) {
+		delimiter = strings.Repeat(delimiter, 3)
+	}
+	l.advance(len(delimiter))
+
+	for l.off < len(l.src) {
+		c := l.src[l.off]
+		switch {
+		case c == '\\':
+			l.advance(min(2, len(l.src)-l.off))
+		case c == '\n' && len(delimiter) == 1:
+			return token{}, l.errorf(pos, "unterminated string literal")
+		case strings.HasPrefix(l.src[l.off:], delimiter):
+			l.advance(len(delimiter))
+			return token{kind: tokString, text: l.src[start:l.off], pos: pos}, nil
+		default:
+			l.advance(1)
+		}
+	}
+	if len(delimiter) == 3 {
+		return token{}, l.errorf(pos, "unterminated triple-quoted string literal")
+	}
+	return token{}, l.errorf(pos, "unterminated string literal")
+}
+
+// Moves the offset forward, tracking lines and columns
+func (l *lexer) advance(n int) {
+	for range n {
+		if l.src[l.off] == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.off++
+	}
+}
+
+// Returns the current position
+func (l *lexer) pos() ast.Position {
+	return ast.Position{Filename: l.filename, Line: l.line, Column: l.col, Offset: l.off}
+}
+
+// Creates a syntax error at a position
+func (l *lexer) errorf(pos ast.Position, format string, args ...any) error {
+	return &SyntaxError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Returns the closing bracket matching an opening one
+func closing(open string) string {
+	switch open {
+	case "(":
+		return ")"
+	case "[":
+		return "]"
+	}
+	return "}"
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isQuote(c byte) bool {
+	return c == '"' || c == '\''
+}
+
+// Checks if a name is a string literal prefix (e.g. r, b, f, rb)
+func isStringPrefix(name string) bool {
+	switch strings.ToLower(name) {
+	case "r", "u", "b", "f", "br", "rb", "fr", "rf":
+		return true
+	}
+	return false
+}
+
+// Returns the content of a string literal without prefix and quotes
+func stringValue(literal string) string {
+	literal = strings.TrimLeft(literal, "rRuUbBfF")
+	for _, delimiter := range []string{`"""`, `'''`, `"`, `'`} {
+		if strings.HasPrefix(literal, delimiter) && strings.HasSuffix(literal, delimiter) && len(literal) >= 2*len(delimiter) {
+			return literal[len(delimiter) : len(literal)-len(delimiter)]
+		}
+	}
+	return literal
+}
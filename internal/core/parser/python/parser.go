@@ -0,0 +1,870 @@
+// Provides the Python language parser implementation
+package pyparser
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// Implements the parser.Parser interface for Python
+type Parser struct{}
+
+// Creates a new Python parser
+func New() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Language() string {
+	return "Python"
+}
+
+func (p *Parser) FileExtensions() []string {
+	return []string{".py"}
+}
+
+func (p *Parser) ParseFile(filename string) (ast.Node, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return p.parse(filename, string(src))
+}
+
+// Parses the Python modules of a directory, in file name order
+func (p *Parser) ParseDir(dir string) ([]ast.Node, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []ast.Node
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(p.FileExtensions(), filepath.Ext(entry.Name())) {
+			continue
+		}
+		node, err := p.ParseFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// Parses Python source into our generic AST
+func (p *Parser) parse(filename, src string) (ast.Node, error) {
+	lines, err := tokenize(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	stmts, err := buildTree(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &converter{filename: filename, variables: make(map[string]bool)}
+	node := c.convertModule(stmts)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return node, nil
+}
+
+// A statement with the statements of its indented block
+type stmt struct {
+	line
+	body []*stmt
+}
+
+// Checks if the statement is the header of a compound statement with an indented block
+func (s *stmt) isHeader() bool {
+	return s.tokens[len(s.tokens)-1].is(":")
+}
+
+// An open block while building the statement tree
+type block struct {
+	owner  *stmt
+	indent int
+}
+
+// Nests logical lines into statements by indentation
+func buildTree(lines []line) ([]*stmt, error) {
+	root := &stmt{}
+	blocks := []block{{owner: root, indent: 0}}
+	var prev *stmt
+
+	for _, l := range lines {
+		top := blocks[len(blocks)-1]
+		if prev != nil && prev.isHeader() {
+			if l.indent <= top.indent {
+				return nil, &SyntaxError{Pos: l.tokens[0].pos, Msg: "expected an indented block"}
+			}
+			blocks = append(blocks, block{owner: prev, indent: l.indent})
+		} else {
+			dedented := false
+			for len(blocks) > 1 && l.indent < blocks[len(blocks)-1].indent {
+				blocks = blocks[:len(blocks)-1]
+				dedented = true
+			}
+			switch current := blocks[len(blocks)-1].indent; {
+			case l.indent != current && dedented:
+				return nil, &SyntaxError{Pos: l.tokens[0].pos, Msg: "unindent does not match any outer indentation level"}
+			case l.indent != current:
+				return nil, &SyntaxError{Pos: l.tokens[0].pos, Msg: "unexpected indent"}
+			}
+		}
+
+		s := &stmt{line: l}
+		owner := blocks[len(blocks)-1].owner
+		owner.body = append(owner.body, s)
+		prev = s
+	}
+
+	if prev != nil && prev.isHeader() {
+		return nil, &SyntaxError{Pos: prev.tokens[len(prev.tokens)-1].pos, Msg: "expected an indented block"}
+	}
+	return root.body, nil
+}
+
+// Converts the statements of one file, collecting the first syntax error
+type converter struct {
+	filename     string
+	dependencies []string
+	variables    map[string]bool // Module-level names already converted
+	exports      []string        // Names listed in __all__
+	err          error
+}
+
+// Records a syntax error at a token
+func (c *converter) errorf(tok token, msg string) {
+	if c.err == nil {
+		c.err = &SyntaxError{Pos: tok.pos, Msg: msg}
+	}
+}
+
+// Converts a Python module to our generic AST
+func (c *converter) convertModule(stmts []*stmt) ast.Node {
+	node := ast.NewBaseNode(ast.Module, ast.Position{
+		Filename: c.filename,
+		Line:     1,
+		Column:   1,
+	})
+
+	node.SetAttribute("package_name", moduleName(c.filename))
+	node.SetAttribute("docstring", docstring(stmts))
+	c.dependencies = make([]string, 0)
+
+	c.convertStatements(node, stmts)
+	node.SetAttribute("dependencies", c.dependencies)
+
+	// __all__ defines the public names of a module
+	if c.exports != nil {
+		node.SetAttribute("exports", c.exports)
+		for _, child := range node.Children() {
+			if name, ok := child.Attributes()["name"].(string); ok {
+				child.Attributes()["is_exported"] = slices.Contains(c.exports, name)
+			}
+		}
+	}
+
+	return node
+}
+
+// Compound statements whose blocks still run at module level (e.g. imports under if TYPE_CHECKING)
+var moduleLevelBlocks = []string{"if", "elif", "else", "try", "except", "finally", "with"}
+
+// Converts module-level statements, descending into conditional blocks
+func (c *converter) convertStatements(node *ast.BaseNode, stmts []*stmt) {
+	var decorators []string
+	for _, s := range stmts {
+		first := s.tokens[0]
+		switch {
+		case first.is("@"):
+			decorators = append(decorators, tokensText(s.tokens[1:]))
+			continue
+		case first.is("import"):
+			for _, imp := range c.convertImport(s.tokens) {
+				node.AddChild(imp)
+				c.dependencies = append(c.dependencies, imp.Attributes()["path"].(string))
+			}
+		case first.is("from"):
+			if imp := c.convertFromImport(s.tokens); imp != nil {
+				node.AddChild(imp)
+				c.dependencies = append(c.dependencies, imp.Attributes()["path"].(string))
+			}
+		case isFunctionDef(s.tokens):
+			if fn := c.convertFunction(s, decorators, ""); fn != nil {
+				node.AddChild(fn)
+			}
+		case first.is("class"):
+			if class := c.convertClass(s, decorators); class != nil {
+				node.AddChild(class)
+			}
+		case isMainGuard(s.tokens):
+			// Script code, not module structure
+		case slices.Contains(moduleLevelBlocks, first.text) && first.kind == tokName:
+			c.convertStatements(node, s.body)
+		default:
+			for _, v := range c.convertVariables(s.tokens) {
+				node.AddChild(v)
+			}
+		}
+		decorators = nil
+	}
+}
+
+// Converts a Python import statement (import a.b as c, d) to one node per module
+func (c *converter) convertImport(tokens []token) []ast.Node {
+	var nodes []ast.Node
+	for _, part := range splitTopLevel(tokens[1:], ",") {
+		path, n := dottedName(part)
+		if path == "" {
+			c.errorf(tokens[0], "invalid syntax: expected a module name")
+			return nil
+		}
+		node := ast.NewBaseNode(ast.Import, part[0].pos)
+		node.SetAttribute("path", path)
+		node.SetAttribute("is_std_lib", isStdLib(path))
+		node.SetAttribute("is_relative", false)
+		if n+1 < len(part) && part[n].is("as") {
+			node.SetAttribute("alias", part[n+1].text)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// Converts a Python from-import statement (from .a import b as c, d) to a node
+func (c *converter) convertFromImport(tokens []token) ast.Node {
+	i := 1
+	path := ""
+	for i < len(tokens) && (tokens[i].is(".") || tokens[i].is("...")) {
+		path += tokens[i].text
+		i++
+	}
+	if i < len(tokens) && !tokens[i].is("import") {
+		module, n := dottedName(tokens[i:])
+		path += module
+		i += n
+	}
+	if path == "" || i >= len(tokens) || !tokens[i].is("import") {
+		c.errorf(tokens[0], "invalid syntax: expected 'from module import names'")
+		return nil
+	}
+
+	names := tokens[i+1:]
+	if len(names) > 0 && names[0].is("(") {
+		names = names[1 : len(names)-1]
+	}
+	imported := make([]string, 0)
+	aliases := make([]string, 0)
+	for _, part := range splitTopLevel(names, ",") {
+		if len(part) == 0 {
+			continue
+		}
+		alias := ""
+		if len(part) == 3 && part[1].is("as") {
+			alias = part[2].text
+		}
+		imported = append(imported, part[0].text)
+		aliases = append(aliases, alias)
+	}
+
+	node := ast.NewBaseNode(ast.Import, tokens[0].pos)
+	node.SetAttribute("path", path)
+	node.SetAttribute("names", imported)
+	node.SetAttribute("aliases", aliases)
+	node.SetAttribute("is_relative", strings.HasPrefix(path, "."))
+	node.SetAttribute("is_std_lib", !strings.HasPrefix(path, ".") && isStdLib(path))
+	return node
+}
+
+// Checks if the statement is a function definition (def or async def)
+func isFunctionDef(tokens []token) bool {
+	return tokens[0].is("def") || (len(tokens) > 1 && tokens[0].is("async") && tokens[1].is("def"))
+}
+
+// Checks if the statement is the if __name__ == "__main__" guard
+func isMainGuard(tokens []token) bool {
+	return len(tokens) >= 4 && tokens[0].is("if") && tokens[1].is("__name__") && tokens[2].is("==") &&
+		tokens[3].kind == tokString && stringValue(tokens[3].text) == "__main__"
+}
+
+// Converts a function or method definition to our generic AST.
+// Methods are converted when class is the name of the enclosing class.
+func (c *converter) convertFunction(s *stmt, decorators []string, class string) ast.Node {
+	tokens := s.tokens
+	isAsync := tokens[0].is("async")
+	if isAsync {
+		tokens = tokens[1:]
+	}
+	if len(tokens) < 3 || tokens[1].kind != tokName || !tokens[2].is("(") {
+		c.errorf(tokens[0], "invalid syntax: expected 'def name(...)'")
+		return nil
+	}
+	name := tokens[1].text
+	closeParen := matchingBracket(tokens, 2)
+	colon := indexTopLevel(tokens, ":", closeParen+1)
+	if colon < 0 {
+		c.errorf(tokens[0], "expected ':'")
+		return nil
+	}
+
+	nodeType := ast.Function
+	if class != "" {
+		nodeType = ast.Method
+	}
+	node := ast.NewBaseNode(nodeType, s.tokens[0].pos)
+	node.SetAttribute("name", name)
+	node.SetAttribute("is_exported", isExported(name))
+	node.SetAttribute("is_async", isAsync)
+	node.SetAttribute("decorators", decoratorsOrEmpty(decorators))
+	node.SetAttribute("docstring", docstring(s.body))
+	node.SetAttribute("raises", raises(s.body, tokens[colon+1:]))
+	node.SetAttribute("http_calls", httpCalls(tokens[colon+1:], s.body))
+
+	// Build the signature
+	params, paramNames, variadic, keywordVariadic := parameters(tokens[3:closeParen])
+	returns := make([]*TypeInfo, 0)
+	returnNames := make([]string, 0)
+	if closeParen+1 < colon && tokens[closeParen+1].is("->") {
+		returns = append(returns, parseAnnotation(tokens[closeParen+2:colon]))
+		returnNames = append(returnNames, "")
+	}
+
+	if class != "" {
+		kind := methodKind(decorators)
+		node.SetAttribute("method_kind", kind)
+		node.SetAttribute("is_abstract", hasDecorator(decorators, "abstractmethod"))
+		node.SetAttribute("receiver_type", &TypeInfo{Name: class})
+
+		// The first parameter of instance and class methods is the receiver
+		receiverName := ""
+		if kind != MethodStatic && len(paramNames) > 0 {
+			receiverName = paramNames[0]
+			params, paramNames = params[1:], paramNames[1:]
+		}
+		node.SetAttribute("receiver_name", receiverName)
+	}
+
+	node.SetAttribute("signature", map[string]any{
+		"params":           params,
+		"returns":          returns,
+		"param_names":      paramNames,
+		"return_names":     returnNames,
+		"variadic":         variadic,
+		"keyword_variadic": keywordVariadic,
+	})
+
+	return node
+}
+
+// Kinds of methods
+const (
+	MethodInstance = "instance"
+	MethodClass    = "class"
+	MethodStatic   = "static"
+	MethodProperty = "property"
+)
+
+// Returns the kind of a method from its decorators
+func methodKind(decorators []string) string {
+	switch {
+	case hasDecorator(decorators, "staticmethod"):
+		return MethodStatic
+	case hasDecorator(decorators, "classmethod"):
+		return MethodClass
+	case hasDecorator(decorators, "property"), hasDecorator(decorators, "cached_property"):
+		return MethodProperty
+	}
+	for _, d := range decorators {
+		if strings.HasSuffix(d, ".setter") || strings.HasSuffix(d, ".deleter") {
+			return MethodProperty
+		}
+	}
+	return MethodInstance
+}
+
+// Checks if a decorator is applied, by unqualified name
+func hasDecorator(decorators []string, name string) bool {
+	for _, d := range decorators {
+		if i := strings.IndexByte(d, '('); i >= 0 {
+			d = d[:i]
+		}
+		if d == name || strings.HasSuffix(d, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to keep decorator lists non-nil
+func decoratorsOrEmpty(decorators []string) []string {
+	if decorators == nil {
+		return make([]string, 0)
+	}
+	return decorators
+}
+
+// Helper function to extract the types and names of a parameter list.
+// Unannotated parameters get an unknown type so both slices stay aligned.
+func parameters(tokens []token) (params []*TypeInfo, names []string, variadic, keywordVariadic bool) {
+	params = make([]*TypeInfo, 0)
+	names = make([]string, 0)
+	for _, param := range splitTopLevel(tokens, ",") {
+		if len(param) == 0 || param[0].is("/") || (param[0].is("*") && len(param) == 1) {
+			continue // Positional-only and keyword-only markers
+		}
+		switch {
+		case param[0].is("*"):
+			variadic = true
+			param = param[1:]
+		case param[0].is("**"):
+			keywordVariadic = true
+			param = param[1:]
+		}
+		if len(param) == 0 || param[0].kind != tokName {
+			continue
+		}
+
+		typ := &TypeInfo{}
+		if colon := indexTopLevel(param, ":", 1); colon == 1 {
+			end := indexTopLevel(param, "=", colon)
+			if end < 0 {
+				end = len(param)
+			}
+			typ = parseAnnotation(param[colon+1 : end])
+		}
+		params = append(params, typ)
+		names = append(names, param[0].text)
+	}
+	return params, names, variadic, keywordVariadic
+}
+
+// Returns the exception names raised in a function body, in order of appearance
+func raises(body []*stmt, inline []token) []string {
+	names := make([]string, 0)
+	var visit func(tokens []token, body []*stmt)
+	visit = func(tokens []token, body []*stmt) {
+		if len(tokens) > 1 && tokens[0].is("raise") {
+			if name, _ := dottedName(tokens[1:]); name != "" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		for _, s := range body {
+			visit(s.tokens, s.body)
+		}
+	}
+	visit(inline, body)
+	return names
+}
+
+// Converts a class definition to our generic AST. Classes deriving from Protocol become interfaces.
+func (c *converter) convertClass(s *stmt, decorators []string) ast.Node {
+	tokens := s.tokens
+	if len(tokens) < 2 || tokens[1].kind != tokName {
+		c.errorf(tokens[0], "invalid syntax: expected 'class name'")
+		return nil
+	}
+	name := tokens[1].text
+	colon := indexTopLevel(tokens, ":", 2)
+	if colon < 0 {
+		c.errorf(tokens[0], "expected ':'")
+		return nil
+	}
+
+	// Bases and keywords
+	bases := make([]*TypeInfo, 0)
+	metaclass := ""
+	if tokens[2].is("(") {
+		for _, arg := range splitTopLevel(tokens[3:matchingBracket(tokens, 2)], ",") {
+			switch {
+			case len(arg) == 0:
+			case len(arg) > 2 && arg[1].is("="):
+				if arg[0].is("metaclass") {
+					metaclass = tokensText(arg[2:])
+				}
+			default:
+				bases = append(bases, parseAnnotation(arg))
+			}
+		}
+	}
+
+	isProtocol := false
+	isAbstract := metaclass == "ABCMeta" || strings.HasSuffix(metaclass, ".ABCMeta")
+	for _, base := range bases {
+		switch base.BaseName() {
+		case "Protocol":
+			isProtocol = true
+		case "ABC":
+			isAbstract = true
+		}
+	}
+
+	nodeType := ast.Type
+	if isProtocol {
+		nodeType = ast.Interface
+	}
+	node := ast.NewBaseNode(nodeType, tokens[0].pos)
+	node.SetAttribute("name", name)
+	node.SetAttribute("is_exported", isExported(name))
+	node.SetAttribute("bases", bases)
+	node.SetAttribute("metaclass", metaclass)
+	node.SetAttribute("decorators", decoratorsOrEmpty(decorators))
+	node.SetAttribute("docstring", docstring(s.body))
+
+	fields := make([]map[string]any, 0)
+	seen := make(map[string]bool)
+	addField := func(nameTok token, typ *TypeInfo) {
+		if seen[nameTok.text] {
+			return
+		}
+		seen[nameTok.text] = true
+		fields = append(fields, map[string]any{
+			"name":        nameTok.text,
+			"type":        typ,
+			"embedded":    false,
+			"is_exported": isExported(nameTok.text),
+			"position":    nameTok.pos,
+		})
+	}
+
+	var methods []ast.Node
+	var methodDecorators []string
+	for _, child := range s.body {
+		first := child.tokens[0]
+		switch {
+		case first.is("@"):
+			methodDecorators = append(methodDecorators, tokensText(child.tokens[1:]))
+			continue
+		case isFunctionDef(child.tokens):
+			method := c.convertFunction(child, methodDecorators, name)
+			if method == nil {
+				break
+			}
+			methods = append(methods, method)
+			if method.Attributes()["is_abstract"] == true {
+				isAbstract = true
+			}
+			if method.Attributes()["name"] == "__init__" {
+				for _, field := range instanceFields(child, method) {
+					addField(field.name, field.typ)
+				}
+			}
+		case first.is("class"):
+			// Nested classes are not part of the module structure
+		default:
+			for _, a := range assignments(child.tokens) {
+				addField(a.name, a.typ)
+			}
+		}
+		methodDecorators = nil
+	}
+	node.SetAttribute("fields", fields)
+	node.SetAttribute("is_abstract", isAbstract)
+
+	if isProtocol {
+		// Like Go interfaces, protocol members are described by the interface itself
+		signatures := make([]map[string]any, 0, len(methods))
+		for _, method := range methods {
+			signatures = append(signatures, map[string]any{
+				"name":      method.Attributes()["name"],
+				"signature": method.Attributes()["signature"],
+			})
+		}
+		node.SetAttribute("methods", signatures)
+		return node
+	}
+	for _, method := range methods {
+		node.AddChild(method)
+	}
+	return node
+}
+
+// A name bound by an assignment, with its annotated or inferred type
+type assigned struct {
+	name token
+	typ  *TypeInfo
+}
+
+// Returns the attributes assigned on the receiver in an __init__ method (self.x = ...)
+func instanceFields(s *stmt, method ast.Node) []assigned {
+	receiver, _ := method.Attributes()["receiver_name"].(string)
+	if receiver == "" {
+		return nil
+	}
+	signature := method.Attributes()["signature"].(map[string]any)
+	paramTypes := make(map[string]*TypeInfo)
+	for i, name := range signature["param_names"].([]string) {
+		paramTypes[name] = signature["params"].([]*TypeInfo)[i]
+	}
+
+	var fields []assigned
+	var visit func(body []*stmt)
+	visit = func(body []*stmt) {
+		for _, child := range body {
+			tokens := child.tokens
+			if len(tokens) > 3 && tokens[0].is(receiver) && tokens[1].is(".") && tokens[2].kind == tokName {
+				target := tokens[2]
+				rest := tokens[3:]
+				typ := &TypeInfo{}
+				switch {
+				case rest[0].is(":"):
+					end := indexTopLevel(rest, "=", 1)
+					if end < 0 {
+						end = len(rest)
+					}
+					typ = parseAnnotation(rest[1:end])
+				case rest[0].is("="):
+					value := rest[1:]
+					if len(value) == 1 && value[0].kind == tokName && paramTypes[value[0].text].isKnown() {
+						typ = paramTypes[value[0].text]
+					} else if indexTopLevel(value, "=", 0) < 0 {
+						typ = inferType(value)
+					}
+				default:
+					continue
+				}
+				fields = append(fields, assigned{name: target, typ: typ})
+			}
+			visit(child.body)
+		}
+	}
+	visit(s.body)
+	return fields
+}
+
+// Converts a module-level assignment to variable nodes
+func (c *converter) convertVariables(tokens []token) []ast.Node {
+	var nodes []ast.Node
+	for _, a := range assignments(tokens) {
+		name := a.name.text
+		if name == "__all__" {
+			c.exports = stringList(tokens[indexTopLevel(tokens, "=", 0)+1:])
+			continue
+		}
+		if c.variables[name] {
+			continue
+		}
+		c.variables[name] = true
+
+		node := ast.NewBaseNode(ast.Variable, a.name.pos)
+		node.SetAttribute("name", name)
+		node.SetAttribute("is_exported", isExported(name))
+		node.SetAttribute("is_constant", isConstantName(name))
+		node.SetAttribute("type", a.typ)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// Returns the names bound by an assignment or annotated declaration
+// (x = 1, x: int = 1, x: int, a = b = 1, a, b = 1, 2)
+func assignments(tokens []token) []assigned {
+	if tokens[0].kind != tokName || keywords[tokens[0].text] {
+		return nil
+	}
+
+	// Annotated declaration, with or without value
+	if len(tokens) > 2 && tokens[1].is(":") {
+		end := indexTopLevel(tokens, "=", 2)
+		if end < 0 {
+			end = len(tokens)
+		}
+		typ := parseAnnotation(tokens[2:end])
+		return []assigned{{name: tokens[0], typ: typ}}
+	}
+
+	parts := splitTopLevel(tokens, "=")
+	if len(parts) < 2 {
+		return nil
+	}
+	value := parts[len(parts)-1]
+
+	var result []assigned
+	for _, target := range parts[:len(parts)-1] {
+		if len(target) > 1 && (target[0].is("(") || target[0].is("[")) && closes(target, 0) {
+			target = target[1 : len(target)-1]
+		}
+		names := splitTopLevel(target, ",")
+		for _, name := range names {
+			if len(name) == 2 && name[0].is("*") {
+				name = name[1:]
+			}
+			if len(name) != 1 || name[0].kind != tokName || keywords[name[0].text] {
+				return nil // Attribute, subscript or invalid target
+			}
+			typ := &TypeInfo{}
+			if len(names) == 1 {
+				typ = inferType(value)
+			}
+			result = append(result, assigned{name: name[0], typ: typ})
+		}
+	}
+	return result
+}
+
+// Returns the strings of a list or tuple literal of strings, or nil
+func stringList(tokens []token) []string {
+	if len(tokens) < 2 || !(tokens[0].is("[") || tokens[0].is("(")) || !closes(tokens, 0) {
+		return nil
+	}
+	names := make([]string, 0)
+	for _, item := range splitTopLevel(tokens[1:len(tokens)-1], ",") {
+		if len(item) == 0 {
+			continue
+		}
+		if len(item) != 1 || item[0].kind != tokString {
+			return nil
+		}
+		names = append(names, stringValue(item[0].text))
+	}
+	return names
+}
+
+// Returns the docstring of a module, class or function body
+func docstring(body []*stmt) string {
+	if len(body) == 0 || !allStrings(body[0].tokens) {
+		return ""
+	}
+	var b strings.Builder
+	for _, tok := range body[0].tokens {
+		b.WriteString(stringValue(tok.text))
+	}
+	return cleanDoc(b.String())
+}
+
+// Removes the indentation common to the continuation lines of a docstring, like inspect.cleandoc
+func cleanDoc(doc string) string {
+	lines := strings.Split(strings.ReplaceAll(doc, "\t", "        "), "\n")
+	margin := -1
+	for _, l := range lines[1:] {
+		if trimmed := strings.TrimLeft(l, " "); trimmed != "" {
+			if indent := len(l) - len(trimmed); margin < 0 || indent < margin {
+				margin = indent
+			}
+		}
+	}
+	lines[0] = strings.TrimSpace(lines[0])
+	for i := 1; i < len(lines) && margin > 0; i++ {
+		if len(lines[i]) >= margin {
+			lines[i] = lines[i][margin:]
+		} else {
+			lines[i] = strings.TrimLeft(lines[i], " ")
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Returns the module name of a file: the file name without extension, or the
+// directory name for a package's __init__.py
+func moduleName(filename string) string {
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	if name == "__init__" {
+		return filepath.Base(filepath.Dir(filename))
+	}
+	return name
+}
+
+// Checks if a name is public by Python convention: no leading underscore, or a dunder name
+func isExported(name string) bool {
+	if strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__") && len(name) > 4 {
+		return true
+	}
+	return !strings.HasPrefix(name, "_")
+}
+
+// Checks if a name follows the UPPER_CASE constant convention
+func isConstantName(name string) bool {
+	return strings.ToUpper(name) == name && strings.ToLower(name) != name
+}
+
+// Python keywords, which never name variables
+var keywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// Returns the index of the bracket closing the one at index open
+func matchingBracket(tokens []token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].kind != tokOp {
+			continue
+		}
+		switch tokens[i].text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// Returns the index of the first operator outside brackets at or after start, or -1
+func indexTopLevel(tokens []token, op string, start int) int {
+	depth := 0
+	for i, tok := range tokens {
+		if tok.kind != tokOp {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		case op:
+			if depth == 0 && i >= start {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Checks if an operator occurs outside brackets
+func hasTopLevel(tokens []token, op string) bool {
+	return indexTopLevel(tokens, op, 0) >= 0
+}
+
+// Splits tokens at an operator outside brackets
+func splitTopLevel(tokens []token, op string) [][]token {
+	var parts [][]token
+	start := 0
+	for {
+		i := indexTopLevel(tokens, op, start)
+		if i < 0 {
+			break
+		}
+		parts = append(parts, tokens[start:i])
+		start = i + 1
+	}
+	return append(parts, tokens[start:])
+}
+
+// Returns the source-like text of tokens (e.g. for decorators and metaclasses)
+func tokensText(tokens []token) string {
+	var b strings.Builder
+	for i, tok := range tokens {
+		if i > 0 {
+			prev := tokens[i-1]
+			if prev.is(",") || (prev.kind != tokOp && tok.kind != tokOp) {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(tok.text)
+	}
+	return b.String()
+}
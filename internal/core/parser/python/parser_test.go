@@ -0,0 +1,325 @@
+package pyparser_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	pyparser "codedna/internal/core/parser/python"
+)
+
+// Helper function to find nodes of a specific type
+func findNodes(root ast.Node, nodeType ast.NodeType) []ast.Node {
+	var nodes []ast.Node
+	var walk func(ast.Node)
+	walk = func(n ast.Node) {
+		if n.Type() == string(nodeType) {
+			nodes = append(nodes, n)
+		}
+		for _, child := range n.Children() {
+			walk(child)
+		}
+	}
+	walk(root)
+	return nodes
+}
+
+// Helper function to find a named node of a specific type
+func findNode(t *testing.T, root ast.Node, nodeType ast.NodeType, name string) ast.Node {
+	t.Helper()
+	for _, node := range findNodes(root, nodeType) {
+		if node.Attributes()["name"] == name {
+			return node
+		}
+	}
+	t.Fatalf("%s %s not found", nodeType, name)
+	return nil
+}
+
+// Helper function to parse Python source written to a temporary file
+func parseSource(t *testing.T, src string) (ast.Node, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "module.py")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	return pyparser.New().ParseFile(path)
+}
+
+// Helper function to get the types of a signature as strings
+func signatureTypes(t *testing.T, node ast.Node, key string) []string {
+	t.Helper()
+	signature := node.Attributes()["signature"].(map[string]any)
+	var types []string
+	for _, typ := range signature[key].([]*pyparser.TypeInfo) {
+		types = append(types, typ.String())
+	}
+	return types
+}
+
+func TestParseFile(t *testing.T) {
+	root, err := pyparser.New().ParseFile(filepath.Join("testdata", "shapes", "base.py"))
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	t.Run("Module", func(t *testing.T) {
+		attrs := root.Attributes()
+		if root.Type() != string(ast.Module) || attrs["package_name"] != "base" {
+			t.Errorf("Expected module base, got %s %v", root.Type(), attrs["package_name"])
+		}
+		if doc := attrs["docstring"].(string); doc != "Shape protocols and base classes.\n\nShapes know their area." {
+			t.Errorf("Unexpected module docstring %q", doc)
+		}
+		want := []string{"__future__", "abc", "math", "os.path", "typing", ".", "collections.abc"}
+		if deps := attrs["dependencies"].([]string); !slices.Equal(deps, want) {
+			t.Errorf("Expected dependencies %v, got %v", want, deps)
+		}
+	})
+
+	t.Run("Imports", func(t *testing.T) {
+		imports := findNodes(root, ast.Import)
+		if len(imports) != 7 {
+			t.Fatalf("Expected 7 imports, got %d", len(imports))
+		}
+		math := imports[2].Attributes()
+		if math["path"] != "math" || math["alias"] != "m" || math["is_std_lib"] != true {
+			t.Errorf("Unexpected math import %v", math)
+		}
+		typing := imports[4].Attributes()
+		if names := typing["names"].([]string); !slices.Equal(names, []string{"Protocol", "Optional"}) {
+			t.Errorf("Expected names Protocol, Optional, got %v", names)
+		}
+		relative := imports[5].Attributes()
+		if relative["is_relative"] != true || relative["is_std_lib"] != false {
+			t.Errorf("Expected a relative non-std import, got %v", relative)
+		}
+		if names, aliases := relative["names"].([]string), relative["aliases"].([]string); names[0] != "util" || aliases[0] != "helpers" {
+			t.Errorf("Expected util as helpers, got %v %v", names, aliases)
+		}
+	})
+
+	t.Run("Variables", func(t *testing.T) {
+		want := map[string]string{
+			"MAX_SIDES": "int", "ratio": "float", "_cache": "dict", "name": "?", "version": "?",
+			"DEFAULTS": "dict", "EMPTY": "set", "LABEL": "str",
+		}
+		variables := findNodes(root, ast.Variable)
+		if len(variables) != len(want) {
+			t.Errorf("Expected %d variables, got %d", len(want), len(variables))
+		}
+		for _, v := range variables {
+			name := v.Attributes()["name"].(string)
+			if got := v.Attributes()["type"].(*pyparser.TypeInfo).String(); got != want[name] {
+				t.Errorf("Expected %s to have type %s, got %s", name, want[name], got)
+			}
+		}
+		if max := findNode(t, root, ast.Variable, "MAX_SIDES"); max.Attributes()["is_constant"] != true {
+			t.Error("Expected MAX_SIDES to be a constant")
+		}
+		if cache := findNode(t, root, ast.Variable, "_cache"); cache.Attributes()["is_exported"] != false {
+			t.Error("Expected _cache to be private")
+		}
+	})
+
+	t.Run("Protocol", func(t *testing.T) {
+		shape := findNode(t, root, ast.Interface, "Shape")
+		if shape.Attributes()["docstring"] != "Anything with an area." {
+			t.Errorf("Unexpected docstring %q", shape.Attributes()["docstring"])
+		}
+		if len(shape.Children()) != 0 {
+			t.Errorf("Expected protocol members as attributes only, got %d children", len(shape.Children()))
+		}
+		methods := shape.Attributes()["methods"].([]map[string]any)
+		if len(methods) != 2 || methods[0]["name"] != "area" || methods[1]["name"] != "scale" {
+			t.Fatalf("Expected methods area and scale, got %v", methods)
+		}
+		returns := methods[1]["signature"].(map[string]any)["returns"].([]*pyparser.TypeInfo)
+		if returns[0].String() != "Shape" {
+			t.Errorf("Expected forward reference Shape, got %s", returns[0])
+		}
+	})
+
+	t.Run("Class", func(t *testing.T) {
+		base := findNode(t, root, ast.Type, "Base")
+		attrs := base.Attributes()
+		if bases := attrs["bases"].([]*pyparser.TypeInfo); len(bases) != 1 || bases[0].String() != "abc.ABC" {
+			t.Errorf("Expected base abc.ABC, got %v", bases)
+		}
+		if attrs["is_abstract"] != true {
+			t.Error("Expected Base to be abstract")
+		}
+
+		want := map[string]string{
+			"kind": "str", "sides": "int", "name": "str", "parent": "Optional[Base]", "tags": "list[str]", "extra": "?",
+		}
+		fields := attrs["fields"].([]map[string]any)
+		if len(fields) != len(want) {
+			t.Errorf("Expected %d fields, got %d", len(want), len(fields))
+		}
+		for _, field := range fields {
+			name := field["name"].(string)
+			if got := field["type"].(*pyparser.TypeInfo).String(); got != want[name] {
+				t.Errorf("Expected field %s to have type %s, got %s", name, want[name], got)
+			}
+		}
+
+		kinds := map[string]string{
+			"__init__": pyparser.MethodInstance, "area": pyparser.MethodInstance, "label": pyparser.MethodProperty,
+			"unit": pyparser.MethodStatic, "create": pyparser.MethodClass,
+		}
+		if len(base.Children()) != len(kinds) {
+			t.Errorf("Expected %d methods, got %d", len(kinds), len(base.Children()))
+		}
+		for _, method := range base.Children() {
+			name := method.Attributes()["name"].(string)
+			if method.Type() != string(ast.Method) || method.Attributes()["method_kind"] != kinds[name] {
+				t.Errorf("Expected %s to be a %s method, got %s %v", name, kinds[name], method.Type(), method.Attributes()["method_kind"])
+			}
+		}
+	})
+
+	t.Run("Methods", func(t *testing.T) {
+		base := findNode(t, root, ast.Type, "Base")
+		init := base.Children()[0]
+		if init.Attributes()["receiver_name"] != "self" || init.Attributes()["receiver_type"].(*pyparser.TypeInfo).String() != "Base" {
+			t.Errorf("Unexpected receiver %v %v", init.Attributes()["receiver_name"], init.Attributes()["receiver_type"])
+		}
+		if got := signatureTypes(t, init, "params"); !slices.Equal(got, []string{"str", "Optional[Base]", "?", "?"}) {
+			t.Errorf("Unexpected __init__ params %v", got)
+		}
+		signature := init.Attributes()["signature"].(map[string]any)
+		if signature["variadic"] != true || signature["keyword_variadic"] != true {
+			t.Errorf("Expected variadic parameters, got %v", signature)
+		}
+
+		unit := findNode(t, base, ast.Method, "unit")
+		if unit.Attributes()["receiver_name"] != "" {
+			t.Errorf("Expected static method without receiver, got %v", unit.Attributes()["receiver_name"])
+		}
+		create := findNode(t, base, ast.Method, "create")
+		if create.Attributes()["receiver_name"] != "cls" {
+			t.Errorf("Expected class method receiver cls, got %v", create.Attributes()["receiver_name"])
+		}
+		area := findNode(t, base, ast.Method, "area")
+		if area.Attributes()["is_abstract"] != true || !slices.Equal(area.Attributes()["raises"].([]string), []string{"NotImplementedError"}) {
+			t.Errorf("Expected abstract area raising NotImplementedError, got %v", area.Attributes())
+		}
+
+		square := findNode(t, root, ast.Type, "Square")
+		if decorators := square.Attributes()["decorators"].([]string); !slices.Equal(decorators, []string{"dataclass(frozen=True)"}) {
+			t.Errorf("Unexpected decorators %v", decorators)
+		}
+		fetch := findNode(t, square, ast.Method, "fetch")
+		if fetch.Attributes()["is_async"] != true {
+			t.Error("Expected fetch to be async")
+		}
+		if got := signatureTypes(t, fetch, "params"); !slices.Equal(got, []string{"str", "Union[int, None]"}) {
+			t.Errorf("Unexpected fetch params %v", got)
+		}
+		if got := signatureTypes(t, fetch, "returns"); !slices.Equal(got, []string{"dict[str, list[int]]"}) {
+			t.Errorf("Unexpected fetch returns %v", got)
+		}
+		if raises := fetch.Attributes()["raises"].([]string); !slices.Equal(raises, []string{"errors.FetchError"}) {
+			t.Errorf("Unexpected raises %v", raises)
+		}
+		if private := findNode(t, square, ast.Method, "_private"); private.Attributes()["is_exported"] != false {
+			t.Error("Expected _private to be unexported")
+		}
+	})
+
+	t.Run("Functions", func(t *testing.T) {
+		functions := findNodes(root, ast.Function)
+		if len(functions) != 2 {
+			t.Fatalf("Expected 2 functions, got %d", len(functions))
+		}
+		largest := functions[0]
+		if got := signatureTypes(t, largest, "params"); !slices.Equal(got, []string{"Iterable[Shape]", "?"}) {
+			t.Errorf("Unexpected largest params %v", got)
+		}
+		if names := largest.Attributes()["signature"].(map[string]any)["param_names"].([]string); !slices.Equal(names, []string{"shapes", "key"}) {
+			t.Errorf("Unexpected param names %v", names)
+		}
+		load := functions[1]
+		if load.Attributes()["name"] != "load" || load.Attributes()["is_async"] != true {
+			t.Errorf("Expected async load, got %v", load.Attributes())
+		}
+		if pos := load.Position(); pos.Line != 87 || pos.Column != 1 {
+			t.Errorf("Expected load at 87:1, got %s", pos)
+		}
+	})
+}
+
+func TestParseDir(t *testing.T) {
+	nodes, err := pyparser.New().ParseDir(filepath.Join("testdata", "shapes"))
+	if err != nil {
+		t.Fatalf("Failed to parse dir: %v", err)
+	}
+
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Attributes()["package_name"].(string))
+	}
+	if !slices.Equal(names, []string{"shapes", "base", "util"}) {
+		t.Fatalf("Expected modules shapes, base, util, got %v", names)
+	}
+
+	t.Run("Exports", func(t *testing.T) {
+		if exports := nodes[0].Attributes()["exports"].([]string); !slices.Equal(exports, []string{"Shape"}) {
+			t.Errorf("Expected exports [Shape], got %v", exports)
+		}
+	})
+
+	t.Run("Parameters", func(t *testing.T) {
+		helper := findNode(t, nodes[2], ast.Function, "helper")
+		names := helper.Attributes()["signature"].(map[string]any)["param_names"].([]string)
+		if !slices.Equal(names, []string{"x", "y", "z", "rest", "k", "options"}) {
+			t.Errorf("Unexpected param names %v", names)
+		}
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"UnclosedBracket", "x = (1,\n", "'(' was never closed"},
+		{"UnmatchedBracket", "x = 1)\n", "unmatched ')'"},
+		{"UnterminatedString", "x = 'abc\n", "unterminated string literal"},
+		{"UnterminatedTripleString", "x = \"\"\"abc\n", "unterminated triple-quoted string literal"},
+		{"UnexpectedIndent", "x = 1\n  y = 2\n", "unexpected indent"},
+		{"Unindent", "def f():\n    x = 1\n  y = 2\n", "unindent does not match"},
+		{"MissingBlock", "def f():\nx = 1\n", "expected an indented block"},
+		{"MissingBlockAtEnd", "class A:\n", "expected an indented block"},
+		{"MissingName", "def (x):\n    pass\n", "expected 'def name(...)'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSource(t, tt.src)
+			if err == nil {
+				t.Fatal("Expected syntax error")
+			}
+			var syntaxErr *pyparser.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Expected SyntaxError, got %T", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %q", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("BrokenFile", func(t *testing.T) {
+		_, err := pyparser.New().ParseFile(filepath.Join("testdata", "broken.py"))
+		if err == nil || !strings.Contains(err.Error(), "broken.py:4:3: unindent does not match") {
+			t.Errorf("Expected positioned unindent error, got %v", err)
+		}
+	})
+}
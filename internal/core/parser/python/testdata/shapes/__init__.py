@@ -0,0 +1,5 @@
+"""Shapes package."""
+
+from .base import Shape
+
+__all__ = ["Shape"]
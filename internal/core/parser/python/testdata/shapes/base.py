@@ -0,0 +1,92 @@
+"""Shape protocols and base classes.
+
+Shapes know their area.
+"""
+
+from __future__ import annotations
+
+import abc
+import math as m, os.path
+from typing import (
+    Protocol,
+    Optional,  # trailing comment
+)
+from . import util as helpers
+
+if TYPE_CHECKING:
+    from collections.abc import Iterable
+
+MAX_SIDES = 12
+ratio: float = 1.5
+_cache = {}
+name, version = "shapes", 2
+DEFAULTS = {"color": "red",
+            "width": 1}
+EMPTY = set()
+LABEL = (
+    "multi"
+    "line"
+)
+
+
+class Shape(Protocol):
+    """Anything with an area."""
+
+    def area(self) -> float: ...
+
+    def scale(self, factor: float) -> "Shape": ...
+
+
+class Base(abc.ABC):
+    kind: str = "base"
+    sides = 0
+
+    def __init__(self, name: str, parent: Optional[Base] = None, *args, **kwargs) -> None:
+        self.name = name
+        self.parent = parent
+        self.tags: list[str] = []
+        if args:
+            self.extra = len(args)
+
+    @abc.abstractmethod
+    def area(self) -> float:
+        raise NotImplementedError
+
+    @property
+    def label(self) -> str:
+        return self.name
+
+    @staticmethod
+    def unit() -> int:
+        return 1
+
+    @classmethod
+    def create(cls, name: str) -> Base:
+        return cls(name)
+
+
+@dataclass(frozen=True)
+class Square(Base):
+    side: float = 1.0
+
+    def area(self) -> float:
+        if self.side < 0:
+            raise ValueError("negative side")
+        return self.side * self.side
+
+    async def fetch(self, url: str, *, timeout: int | None = None) -> dict[str, list[int]]:
+        raise errors.FetchError(url) from None
+
+    def _private(self): pass
+
+
+def largest(shapes: Iterable[Shape], key=lambda s: s.area()) -> Optional[Shape]:
+    return max(shapes, key=key, default=None)
+
+
+async def load(path: str) -> list[Square]: return []
+
+
+if __name__ == "__main__":
+    main_var = 1
+    print(largest([]))
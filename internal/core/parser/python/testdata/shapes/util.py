@@ -0,0 +1,2 @@
+def helper(x, y=1, /, z=2, *rest, k, **options):
+    return x; other = 1
@@ -0,0 +1,258 @@
+package pyparser
+
+import "strings"
+
+// TypeInfo represents a type annotation in a structural way
+type TypeInfo struct {
+	Name string      // The name of the type (e.g. "int", "list", "typing.Optional", "Shape"); empty if unknown
+	Args []*TypeInfo // Type arguments (e.g. [str, int] for dict[str, int])
+}
+
+// Name used for unions written with |
+const unionName = "Union"
+
+// Returns the canonical form of the type (e.g. "dict[str, list[int]]")
+func (t *TypeInfo) String() string {
+	var b strings.Builder
+	t.writeTo(&b)
+	return b.String()
+}
+
+// Writes the canonical form of the type to the builder
+func (t *TypeInfo) writeTo(b *strings.Builder) {
+	if t == nil || t.Name == "" {
+		b.WriteString("?")
+		return
+	}
+	b.WriteString(t.Name)
+	if len(t.Args) == 0 {
+		return
+	}
+	b.WriteString("[")
+	for i, arg := range t.Args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		arg.writeTo(b)
+	}
+	b.WriteString("]")
+}
+
+// Checks if the type is known
+func (t *TypeInfo) isKnown() bool {
+	return t != nil && t.Name != ""
+}
+
+// Returns the unqualified name of the type (e.g. "Protocol" for "typing.Protocol")
+func (t *TypeInfo) BaseName() string {
+	if t == nil {
+		return ""
+	}
+	return t.Name[strings.LastIndex(t.Name, ".")+1:]
+}
+
+// Returns the names of the type and all its arguments, outermost first
+func (t *TypeInfo) Names() []string {
+	if !t.isKnown() {
+		return nil
+	}
+	names := []string{t.Name}
+	for _, arg := range t.Args {
+		names = append(names, arg.Names()...)
+	}
+	return names
+}
+
+// Parses an annotation expression into a TypeInfo
+func parseAnnotation(tokens []token) *TypeInfo {
+	p := &annotationParser{tokens: tokens}
+	t := p.union()
+	if p.i != len(p.tokens) {
+		return &TypeInfo{} // Not a plain type expression
+	}
+	return t
+}
+
+// Parses the tokens of an annotation
+type annotationParser struct {
+	tokens []token
+	i      int
+}
+
+// Parses alternatives separated by |
+func (p *annotationParser) union() *TypeInfo {
+	t := p.primary()
+	if p.i >= len(p.tokens) || !p.tokens[p.i].is("|") {
+		return t
+	}
+	union := &TypeInfo{Name: unionName, Args: []*TypeInfo{t}}
+	for p.i < len(p.tokens) && p.tokens[p.i].is("|") {
+		p.i++
+		union.Args = append(union.Args, p.primary())
+	}
+	return union
+}
+
+// Parses a dotted name with optional subscript, a string forward reference,
+// None or a bracketed argument list (e.g. the parameters of Callable)
+func (p *annotationParser) primary() *TypeInfo {
+	if p.i >= len(p.tokens) {
+		return &TypeInfo{}
+	}
+	tok := p.tokens[p.i]
+	switch {
+	case tok.kind == tokString:
+		p.i++
+		inner, err := tokenize(tok.pos.Filename, stringValue(tok.text))
+		if err != nil || len(inner) != 1 {
+			return &TypeInfo{}
+		}
+		return parseAnnotation(inner[0].tokens)
+
+	case tok.is("["):
+		p.i++
+		list := &TypeInfo{Name: "[]"}
+		list.Args = p.arguments("]")
+		return list
+
+	case tok.is("..."):
+		p.i++
+		return &TypeInfo{Name: "..."}
+
+	case tok.kind == tokName:
+		p.i++
+		name := tok.text
+		for p.i+1 < len(p.tokens) && p.tokens[p.i].is(".") && p.tokens[p.i+1].kind == tokName {
+			name += "." + p.tokens[p.i+1].text
+			p.i += 2
+		}
+		t := &TypeInfo{Name: name}
+		if p.i < len(p.tokens) && p.tokens[p.i].is("[") {
+			p.i++
+			t.Args = p.arguments("]")
+		}
+		return t
+	}
+	p.i = len(p.tokens)
+	return &TypeInfo{}
+}
+
+// Parses comma-separated type arguments up to the closing bracket
+func (p *annotationParser) arguments(end string) []*TypeInfo {
+	args := make([]*TypeInfo, 0)
+	for p.i < len(p.tokens) && !p.tokens[p.i].is(end) {
+		args = append(args, p.union())
+		if p.i < len(p.tokens) && p.tokens[p.i].is(",") {
+			p.i++
+		} else if p.i < len(p.tokens) && !p.tokens[p.i].is(end) {
+			p.i = len(p.tokens) // Unexpected token
+			return args
+		}
+	}
+	p.i++ // Closing bracket
+	return args
+}
+
+// Infers the type of a value expression from its literal form
+func inferType(tokens []token) *TypeInfo {
+	if len(tokens) == 0 {
+		return &TypeInfo{}
+	}
+	first := tokens[0]
+	single := len(tokens) == 1
+	switch {
+	case first.kind == tokString && allStrings(tokens):
+		prefix := strings.ToLower(first.text[:strings.IndexAny(first.text, `"'`)])
+		if strings.Contains(prefix, "b") {
+			return &TypeInfo{Name: "bytes"}
+		}
+		return &TypeInfo{Name: "str"}
+	case first.kind == tokNumber && single:
+		text := strings.ToLower(first.text)
+		switch {
+		case strings.HasSuffix(text, "j"):
+			return &TypeInfo{Name: "complex"}
+		case strings.HasPrefix(text, "0x"), strings.HasPrefix(text, "0o"), strings.HasPrefix(text, "0b"):
+			return &TypeInfo{Name: "int"}
+		case strings.ContainsAny(text, ".e"):
+			return &TypeInfo{Name: "float"}
+		}
+		return &TypeInfo{Name: "int"}
+	case single && (first.is("True") || first.is("False")):
+		return &TypeInfo{Name: "bool"}
+	case single && first.is("None"):
+		return &TypeInfo{Name: "None"}
+	case first.is("[") && closes(tokens, 0):
+		return &TypeInfo{Name: "list"}
+	case first.is("(") && closes(tokens, 0):
+		inner := tokens[1 : len(tokens)-1]
+		if len(inner) == 0 || hasTopLevel(inner, ",") {
+			return &TypeInfo{Name: "tuple"}
+		}
+		return inferType(inner) // Parenthesized expression
+	case first.is("{") && closes(tokens, 0):
+		inner := tokens[1 : len(tokens)-1]
+		if len(inner) > 0 && !hasTopLevel(inner, ":") && !inner[0].is("**") {
+			return &TypeInfo{Name: "set"}
+		}
+		return &TypeInfo{Name: "dict"}
+	case first.kind == tokName && len(tokens) >= 3 && tokens[len(tokens)-1].is(")"):
+		// A call to a capitalized name is most likely a constructor (e.g. Config())
+		name, n := dottedName(tokens)
+		if n < len(tokens) && tokens[n].is("(") && closes(tokens, n) {
+			if base := name[strings.LastIndex(name, ".")+1:]; base != "" && base[0] >= 'A' && base[0] <= 'Z' {
+				return &TypeInfo{Name: name}
+			}
+			switch name {
+			case "list", "dict", "set", "tuple", "frozenset", "str", "int", "float", "bool", "bytes":
+				return &TypeInfo{Name: name}
+			}
+		}
+	}
+	return &TypeInfo{}
+}
+
+// Checks if all tokens are strings (implicit concatenation)
+func allStrings(tokens []token) bool {
+	for _, tok := range tokens {
+		if tok.kind != tokString {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks if the bracket at index start is closed by the last token
+func closes(tokens []token, start int) bool {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		switch tokens[i].text {
+		case "(", "[", "{":
+			if tokens[i].kind == tokOp {
+				depth++
+			}
+		case ")", "]", "}":
+			if tokens[i].kind == tokOp {
+				depth--
+				if depth == 0 {
+					return i == len(tokens)-1
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Returns a dotted name at the start of the tokens and the number of tokens it spans
+func dottedName(tokens []token) (string, int) {
+	if len(tokens) == 0 || tokens[0].kind != tokName {
+		return "", 0
+	}
+	name := tokens[0].text
+	n := 1
+	for n+1 < len(tokens) && tokens[n].is(".") && tokens[n+1].kind == tokName {
+		name += "." + tokens[n+1].text
+		n += 2
+	}
+	return name, n
+}
@@ -0,0 +1,26 @@
+package tsparser
+
+import "strings"
+
+// Node.js built-in modules
+var builtinModules = map[string]bool{
+	"assert": true, "async_hooks": true, "buffer": true, "child_process": true, "cluster": true,
+	"console": true, "constants": true, "crypto": true, "dgram": true, "diagnostics_channel": true,
+	"dns": true, "domain": true, "events": true, "fs": true, "http": true, "http2": true,
+	"https": true, "inspector": true, "module": true, "net": true, "os": true, "path": true,
+	"perf_hooks": true, "process": true, "punycode": true, "querystring": true, "readline": true,
+	"repl": true, "stream": true, "string_decoder": true, "sys": true, "timers": true, "tls": true,
+	"trace_events": true, "tty": true, "url": true, "util": true, "v8": true, "vm": true,
+	"wasi": true, "worker_threads": true, "zlib": true, "test": true,
+}
+
+// Checks if a module path refers to a Node.js built-in module (e.g. fs, fs/promises, node:path)
+func isStdLib(path string) bool {
+	if strings.HasPrefix(path, "node:") {
+		return true
+	}
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		path = path[:i]
+	}
+	return builtinModules[path]
+}
@@ -0,0 +1,168 @@
+package tsparser
+
+import (
+	"slices"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// An HTTP request made through fetch or a client library (axios, ky, Angular's HttpClient)
+type HTTPCall struct {
+	Client   string // The client expression (e.g. "axios", "this.http"); "fetch" for fetch calls
+	Method   string // The HTTP method (e.g. "GET")
+	URL      string // The requested URL; parts that are not literals are written as {} (e.g. "{}/users/{}")
+	Position ast.Position
+}
+
+// Client methods named after the HTTP method they send
+var httpMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// Finds the HTTP requests made in a function body: fetch(url, init), calls of an HTTP method on
+// a client (axios.get(url), this.http.post<T>(url, body)) and client.request(config).
+// Only calls whose URL looks like a path or an absolute URL are kept.
+func httpCalls(tokens []token) []*HTTPCall {
+	calls := make([]*HTTPCall, 0)
+	for i := range tokens {
+		if call := httpCallAt(tokens, i); call != nil {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Returns the HTTP request made by a call starting at tokens[i], or nil
+func httpCallAt(tokens []token, i int) *HTTPCall {
+	if i > 0 && (tokens[i-1].is(".") || tokens[i-1].is("?.")) {
+		return nil // Inside a dotted name
+	}
+	name, n := dottedName(tokens[i:])
+	open := i + n
+	if name == "" || open >= len(tokens) {
+		return nil
+	}
+	if tokens[open].is("<") {
+		if end := matchingAngle(tokens, open); end > 0 {
+			open = end + 1 // Type arguments (this.http.get<User>(url))
+		}
+	}
+	if open >= len(tokens) || !tokens[open].is("(") {
+		return nil
+	}
+	args := splitTopLevel(tokens[open+1:matchingBracket(tokens, open)], ",")
+
+	client, method := "", name
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		client, method = name[:dot], name[dot+1:]
+	}
+	call := &HTTPCall{Client: client, Method: "GET", Position: tokens[i].pos}
+	var url []token
+	switch {
+	case method == "fetch" && (client == "" || client == "window" || client == "globalThis"):
+		call.Client = "fetch"
+		url = argument(args, 0)
+		if verb, ok := property(argument(args, 1), "method"); ok {
+			call.Method = strings.ToUpper(verb)
+		}
+	case client != "" && slices.Contains(httpMethods, method):
+		call.Method = strings.ToUpper(method)
+		url = argument(args, 0)
+	case client != "" && method == "request":
+		config := argument(args, 0)
+		url = propertyValue(config, "url")
+		if verb, ok := property(config, "method"); ok {
+			call.Method = strings.ToUpper(verb)
+		}
+	default:
+		return nil
+	}
+
+	call.URL = urlExpression(url)
+	if !looksLikeURL(call.URL) {
+		return nil
+	}
+	return call
+}
+
+// Returns argument i, or nil
+func argument(args [][]token, i int) []token {
+	if i >= len(args) {
+		return nil
+	}
+	return args[i]
+}
+
+// Returns the tokens of a property value in an object literal ({method: "POST"}), or nil
+func propertyValue(object []token, name string) []token {
+	if len(object) < 2 || !object[0].is("{") {
+		return nil
+	}
+	for _, member := range splitTopLevel(object[1:matchingBracket(object, 0)], ",") {
+		if len(member) > 2 && (member[0].text == name || stringValue(member[0].text) == name) && member[1].is(":") {
+			return member[2:]
+		}
+	}
+	return nil
+}
+
+// Returns a property of an object literal if its value is a string literal
+func property(object []token, name string) (string, bool) {
+	value := propertyValue(object, name)
+	if len(value) != 1 || value[0].kind != tokString {
+		return "", false
+	}
+	return stringValue(value[0].text), true
+}
+
+// Renders a URL expression: string and template literals are kept, with template substitutions
+// and other operands written as {}
+func urlExpression(tokens []token) string {
+	var b strings.Builder
+	for _, part := range splitTopLevel(tokens, "+") {
+		switch {
+		case len(part) == 0:
+		case len(part) == 1 && part[0].kind == tokString:
+			b.WriteString(stringValue(part[0].text))
+		case len(part) == 1 && part[0].kind == tokTemplate:
+			b.WriteString(placeholders(stringValue(part[0].text)))
+		default:
+			b.WriteString("{}")
+		}
+	}
+	return b.String()
+}
+
+// Replaces the substitutions of a template literal with {}
+func placeholders(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			b.WriteString(value[i : i+2])
+			i++
+			continue
+		}
+		if !strings.HasPrefix(value[i:], "${") {
+			b.WriteByte(value[i])
+			continue
+		}
+		depth := 0
+		for ; i < len(value); i++ {
+			if value[i] == '{' {
+				depth++
+			} else if value[i] == '}' {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+		b.WriteString("{}")
+	}
+	return b.String()
+}
+
+// Checks if a URL is a path or an absolute HTTP URL, possibly after an unknown base
+func looksLikeURL(url string) bool {
+	url = strings.TrimPrefix(url, "{}")
+	return strings.HasPrefix(url, "/") || strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
@@ -0,0 +1,75 @@
+package tsparser_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/parser/ast"
+	tsparser "codedna/internal/core/parser/typescript"
+)
+
+func TestHTTPCalls(t *testing.T) {
+	src := `import axios from "axios";
+
+const BASE = "https://api.example.com";
+
+export async function getUser(id: string): Promise<User> {
+  const res = await fetch(` + "`${BASE}/api/users/${id}`" + `);
+  return res.json();
+}
+
+export async function createUser(user: User) {
+  await fetch("/api/users", { method: "post", body: JSON.stringify(user) });
+}
+
+export const deleteUser = (id: string) => axios.delete("/api/users/" + id);
+
+export class UserService {
+  constructor(private http: HttpClient) {}
+
+  list() {
+    return this.http.get<User[]>("/api/users");
+  }
+
+  update = async (user: User) => {
+    await axios.request({ url: "/api/users/" + user.id, method: "PUT", data: user });
+  };
+
+  cached(key: string) {
+    return this.cache.get(key);
+  }
+}
+`
+	root, err := parseSource(t, ".ts", src)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	expected := map[string][]string{
+		"getUser":     {"fetch GET {}/api/users/{}"},
+		"createUser":  {"fetch POST /api/users"},
+		"deleteUser":  {"axios DELETE /api/users/{}"},
+		"constructor": nil,
+		"list":        {"this.http GET /api/users"},
+		"update":      {"axios PUT /api/users/{}"},
+		"cached":      nil,
+	}
+	for _, fn := range append(findNodes(root, ast.Function), findNodes(root, ast.Method)...) {
+		name := fn.Attributes()["name"].(string)
+		calls, ok := fn.Attributes()["http_calls"].([]*tsparser.HTTPCall)
+		if !ok {
+			t.Fatalf("%s: expected http_calls to be []*HTTPCall, got %T", name, fn.Attributes()["http_calls"])
+		}
+		var got []string
+		for _, call := range calls {
+			if call.Position.Line == 0 {
+				t.Errorf("%s: call %s has no position", name, call.URL)
+			}
+			got = append(got, fmt.Sprintf("%s %s %s", call.Client, call.Method, call.URL))
+		}
+		if !slices.Equal(got, expected[name]) {
+			t.Errorf("%s: expected calls %q, got %q", name, expected[name], got)
+		}
+	}
+}
@@ -0,0 +1,488 @@
+package tsparser
+
+import (
+	"fmt"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// The kind of a lexical token
+type tokenKind int
+
+const (
+	tokName tokenKind = iota
+	tokNumber
+	tokString
+	tokTemplate
+	tokRegExp
+	tokJSX
+	tokOp
+)
+
+// A lexical token
+type token struct {
+	kind    tokenKind
+	text    string
+	pos     ast.Position
+	newline bool   // A line break precedes the token
+	doc     string // JSDoc comment directly before the token
+}
+
+// Checks if the token is the given operator or keyword
+func (t token) is(text string) bool {
+	return (t.kind == tokOp || t.kind == tokName) && t.text == text
+}
+
+// Multi-character operators, longest first. Operators starting with > are never joined,
+// so that closing type argument lists (e.g. Map<string, Set<T>>) tokenize as single >.
+var operators = []string{
+	"===", "!==", "**=", "<<=", "&&=", "||=", "??=", "...",
+	"=>", "==", "!=", "<=", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=",
+	"&&", "||", "??", "?.", "++", "--", "**", "<<",
+}
+
+// Keywords after which an expression, and not an operator, follows
+var expressionKeywords = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true, "of": true, "new": true,
+	"delete": true, "void": true, "throw": true, "case": true, "do": true, "else": true,
+	"yield": true, "await": true, "extends": true,
+}
+
+// Splits TypeScript or JavaScript source into tokens
+type lexer struct {
+	filename string
+	src      string
+	off      int
+	line     int
+	col      int
+	jsx      bool   // JSX elements are recognized
+	newline  bool   // A line break was seen since the last token
+	doc      string // Last JSDoc comment since the last token
+	prev     *token // Last token
+}
+
+// A syntax error with its position
+type SyntaxError struct {
+	Pos ast.Position
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// Tokenizes the source, checking that brackets are balanced
+func tokenize(filename, src string, jsx bool) ([]token, error) {
+	l := &lexer{filename: filename, src: src, line: 1, col: 1, jsx: jsx}
+	if strings.HasPrefix(src, "#!") {
+		l.skipLine()
+	}
+
+	var tokens []token
+	var brackets []token // Open brackets, innermost last
+	for {
+		tok, ok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			if tok.kind == tokOp {
+				brackets = append(brackets, tok)
+			}
+		case ")", "]", "}":
+			if tok.kind == tokOp {
+				if len(brackets) == 0 || closing(brackets[len(brackets)-1].text) != tok.text {
+					return nil, l.errorf(tok.pos, "unmatched '%s'", tok.text)
+				}
+				brackets = brackets[:len(brackets)-1]
+			}
+		}
+		tokens = append(tokens, tok)
+	}
+
+	if len(brackets) > 0 {
+		open := brackets[len(brackets)-1]
+		return nil, l.errorf(open.pos, "'%s' was never closed", open.text)
+	}
+	return tokens, nil
+}
+
+// Scans the next token, skipping whitespace and comments. Reports false at the end of the source.
+func (l *lexer) next() (token, bool, error) {
+	for l.off < len(l.src) {
+		c := l.src[l.off]
+		switch {
+		case c == '\n':
+			l.newline = true
+			l.advance(1)
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			l.advance(1)
+		case strings.HasPrefix(l.src[l.off:], "//"):
+			l.skipLine()
+		case strings.HasPrefix(l.src[l.off:], "/*"):
+			if err := l.comment(); err != nil {
+				return token{}, false, err
+			}
+		default:
+			tok, err := l.scan()
+			if err != nil {
+				return token{}, false, err
+			}
+			tok.newline, tok.doc = l.newline, l.doc
+			l.newline, l.doc = false, ""
+			l.prev = &tok
+			return tok, true, nil
+		}
+	}
+	return token{}, false, nil
+}
+
+// Scans a token starting at the current offset
+func (l *lexer) scan() (token, error) {
+	pos := l.pos()
+	start := l.off
+	c := l.src[l.off]
+	switch {
+	case isNameStart(c) || (c == '#' && l.off+1 < len(l.src) && isNameStart(l.src[l.off+1])):
+		l.advance(1)
+		for l.off < len(l.src) && isNameChar(l.src[l.off]) {
+			l.advance(1)
+		}
+		return token{kind: tokName, text: l.src[start:l.off], pos: pos}, nil
+
+	case isDigit(c) || (c == '.' && l.off+1 < len(l.src) && isDigit(l.src[l.off+1])):
+		for l.off < len(l.src) && (isNameChar(l.src[l.off]) || l.src[l.off] == '.' ||
+			((l.src[l.off] == '+' || l.src[l.off] == '-') && (l.src[l.off-1] == 'e' || l.src[l.off-1] == 'E'))) {
+			l.advance(1)
+		}
+		return token{kind: tokNumber, text: l.src[start:l.off], pos: pos}, nil
+
+	case c == '"' || c == '\'':
+		return l.string(pos)
+
+	case c == '`':
+		if err := l.template(pos); err != nil {
+			return token{}, err
+		}
+		return token{kind: tokTemplate, text: l.src[start:l.off], pos: pos}, nil
+
+	case c == '/' && l.expressionStart():
+		if l.regexp() {
+			return token{kind: tokRegExp, text: l.src[start:l.off], pos: pos}, nil
+		}
+
+	case c == '<' && l.jsx && l.expressionStart() && l.jsxStart():
+		if err := l.element(pos); err != nil {
+			return token{}, err
+		}
+		return token{kind: tokJSX, text: l.src[start:l.off], pos: pos}, nil
+	}
+
+	text := string(c)
+	for _, op := range operators {
+		if strings.HasPrefix(l.src[l.off:], op) {
+			text = op
+			break
+		}
+	}
+	l.advance(len(text))
+	return token{kind: tokOp, text: text, pos: pos}, nil
+}
+
+// Checks if an expression may start at the current offset, which tells regular expressions
+// and JSX apart from division and comparison
+func (l *lexer) expressionStart() bool {
+	if l.prev == nil {
+		return true
+	}
+	switch l.prev.kind {
+	case tokName:
+		return expressionKeywords[l.prev.text]
+	case tokOp:
+		return l.prev.text != ")" && l.prev.text != "]" && l.prev.text != "}" &&
+			l.prev.text != "++" && l.prev.text != "--"
+	}
+	return false
+}
+
+// Scans a regular expression literal. Reports false, without consuming anything, if the
+// slash does not start one.
+func (l *lexer) regexp() bool {
+	end := l.off + 1
+	inClass := false
+	for end < len(l.src) {
+		switch c := l.src[end]; {
+		case c == '\n':
+			return false
+		case c == '\\':
+			end++
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			end++
+			for end < len(l.src) && isNameChar(l.src[end]) {
+				end++ // Flags
+			}
+			l.advance(end - l.off)
+			return true
+		}
+		end++
+	}
+	return false
+}
+
+// Scans a string literal
+func (l *lexer) string(pos ast.Position) (token, error) {
+	start := l.off
+	quote := l.src[l.off]
+	l.advance(1)
+	for l.off < len(l.src) {
+		switch c := l.src[l.off]; {
+		case c == '\\':
+			l.advance(min(2, len(l.src)-l.off))
+		case c == '\n':
+			return token{}, l.errorf(pos, "unterminated string literal")
+		case c == quote:
+			l.advance(1)
+			return token{kind: tokString, text: l.src[start:l.off], pos: pos}, nil
+		default:
+			l.advance(1)
+		}
+	}
+	return token{}, l.errorf(pos, "unterminated string literal")
+}
+
+// Scans a template literal, including its substitutions
+func (l *lexer) template(pos ast.Position) error {
+	l.advance(1)
+	for l.off < len(l.src) {
+		switch {
+		case l.src[l.off] == '\\':
+			l.advance(min(2, len(l.src)-l.off))
+		case l.src[l.off] == '`':
+			l.advance(1)
+			return nil
+		case strings.HasPrefix(l.src[l.off:], "${"):
+			l.advance(2)
+			if err := l.embedded(pos, "template literal"); err != nil {
+				return err
+			}
+		default:
+			l.advance(1)
+		}
+	}
+	return l.errorf(pos, "unterminated template literal")
+}
+
+// Scans the tokens of an expression embedded in a template literal or JSX, up to and
+// including its closing brace
+func (l *lexer) embedded(pos ast.Position, within string) error {
+	prev, newline, doc := l.prev, l.newline, l.doc
+	defer func() { l.prev, l.newline, l.doc = prev, newline, doc }()
+	l.prev = nil
+
+	depth := 0
+	for {
+		tok, ok, err := l.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return l.errorf(pos, "unterminated %s", within)
+		}
+		switch {
+		case tok.kind != tokOp:
+		case tok.text == "{":
+			depth++
+		case tok.text == "}" && depth == 0:
+			return nil
+		case tok.text == "}":
+			depth--
+		}
+	}
+}
+
+// Checks if the < at the current offset opens a JSX element or fragment rather than a type
+// parameter list (e.g. <T,>() => ... in a .tsx file)
+func (l *lexer) jsxStart() bool {
+	i := l.off + 1
+	if i < len(l.src) && l.src[i] == '>' {
+		return true // Fragment
+	}
+	if i >= len(l.src) || !isNameStart(l.src[i]) {
+		return false
+	}
+	for i < len(l.src) && (isNameChar(l.src[i]) || l.src[i] == '.' || l.src[i] == '-' || l.src[i] == ':') {
+		i++
+	}
+	rest := strings.TrimLeft(l.src[i:], " \t\r\n")
+	return !strings.HasPrefix(rest, ",") && !strings.HasPrefix(rest, "extends ") && !strings.HasPrefix(rest, "=")
+}
+
+// Scans a JSX element or fragment with its children
+func (l *lexer) element(pos ast.Position) error {
+	depth := 0
+	for l.off < len(l.src) {
+		switch c := l.src[l.off]; {
+		case c == '{':
+			l.advance(1)
+			if err := l.embedded(pos, "JSX expression"); err != nil {
+				return err
+			}
+		case c == '<' && strings.HasPrefix(l.src[l.off:], "</"):
+			if err := l.tag(pos); err != nil {
+				return err
+			}
+			if depth--; depth == 0 {
+				return nil
+			}
+		case c == '<':
+			selfClosing, err := l.tagWithAttributes(pos)
+			if err != nil {
+				return err
+			}
+			if !selfClosing {
+				depth++
+			} else if depth == 0 {
+				return nil
+			}
+		default:
+			l.advance(1) // Text
+		}
+	}
+	return l.errorf(pos, "unterminated JSX element")
+}
+
+// Scans a closing JSX tag
+func (l *lexer) tag(pos ast.Position) error {
+	for l.off < len(l.src) {
+		c := l.src[l.off]
+		l.advance(1)
+		if c == '>' {
+			return nil
+		}
+	}
+	return l.errorf(pos, "unterminated JSX element")
+}
+
+// Scans an opening JSX tag with its attributes, reporting whether it is self-closing
+func (l *lexer) tagWithAttributes(pos ast.Position) (bool, error) {
+	l.advance(1)
+	for l.off < len(l.src) {
+		switch c := l.src[l.off]; {
+		case c == '"' || c == '\'':
+			if _, err := l.string(l.pos()); err != nil {
+				return false, err
+			}
+		case c == '{':
+			l.advance(1)
+			if err := l.embedded(pos, "JSX expression"); err != nil {
+				return false, err
+			}
+		case strings.HasPrefix(l.src[l.off:], "/>"):
+			l.advance(2)
+			return true, nil
+		case c == '>':
+			l.advance(1)
+			return false, nil
+		default:
+			l.advance(1)
+		}
+	}
+	return false, l.errorf(pos, "unterminated JSX element")
+}
+
+// Skips a block comment, remembering it if it is a JSDoc comment
+func (l *lexer) comment() error {
+	pos := l.pos()
+	end := strings.Index(l.src[l.off+2:], "*/")
+	if end < 0 {
+		return l.errorf(pos, "unterminated comment")
+	}
+	text := l.src[l.off : l.off+2+end+2]
+	if strings.HasPrefix(text, "/**") && text != "/**/" {
+		l.doc = cleanDoc(text)
+	}
+	l.advance(len(text))
+	return nil
+}
+
+// Skips to the end of the line
+func (l *lexer) skipLine() {
+	for l.off < len(l.src) && l.src[l.off] != '\n' {
+		l.advance(1)
+	}
+}
+
+// Moves the offset forward, tracking lines and columns
+func (l *lexer) advance(n int) {
+	for range n {
+		if l.src[l.off] == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.off++
+	}
+}
+
+// Returns the current position
+func (l *lexer) pos() ast.Position {
+	return ast.Position{Filename: l.filename, Line: l.line, Column: l.col, Offset: l.off}
+}
+
+// Creates a syntax error at a position
+func (l *lexer) errorf(pos ast.Position, format string, args ...any) error {
+	return &SyntaxError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Removes the comment markers and leading asterisks of a JSDoc comment
+func cleanDoc(comment string) string {
+	comment = strings.TrimSuffix(strings.TrimPrefix(comment, "/**"), "*/")
+	lines := strings.Split(comment, "\n")
+	for i, l := range lines {
+		l = strings.TrimSpace(l)
+		l = strings.TrimPrefix(l, "*")
+		lines[i] = strings.TrimPrefix(l, " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Returns the closing bracket matching an opening one
+func closing(open string) string {
+	switch open {
+	case "(":
+		return ")"
+	case "[":
+		return "]"
+	}
+	return "}"
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Returns the content of a string literal without quotes
+func stringValue(literal string) string {
+	if len(literal) >= 2 {
+		return literal[1 : len(literal)-1]
+	}
+	return literal
+}
@@ -0,0 +1,1585 @@
+// Provides the TypeScript and JavaScript language parser implementation
+package tsparser
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"codedna/internal/core/parser/ast"
+)
+
+// Implements the parser.Parser interface for TypeScript and JavaScript
+type Parser struct{}
+
+// Creates a new TypeScript parser
+func New() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Language() string {
+	return "TypeScript"
+}
+
+func (p *Parser) FileExtensions() []string {
+	return []string{".ts", ".tsx", ".mts", ".cts", ".js", ".jsx", ".mjs", ".cjs"}
+}
+
+func (p *Parser) ParseFile(filename string) (ast.Node, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return p.parse(filename, string(src))
+}
+
+// Parses the TypeScript and JavaScript modules of a directory, in file name order
+func (p *Parser) ParseDir(dir string) ([]ast.Node, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []ast.Node
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(p.FileExtensions(), filepath.Ext(entry.Name())) {
+			continue
+		}
+		node, err := p.ParseFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// Parses TypeScript or JavaScript source into our generic AST
+func (p *Parser) parse(filename, src string) (ast.Node, error) {
+	tokens, err := tokenize(filename, src, hasJSX(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &converter{
+		filename: filename,
+		tokens:   tokens,
+		exported: make(map[string]bool),
+		declared: make(map[string]bool),
+	}
+	node := c.convertModule()
+	if c.err != nil {
+		return nil, c.err
+	}
+	return node, nil
+}
+
+// Checks if a file may contain JSX. Plain .ts files may not, since <T>x is a type assertion there.
+func hasJSX(filename string) bool {
+	switch filepath.Ext(filename) {
+	case ".ts", ".mts", ".cts":
+		return false
+	}
+	return true
+}
+
+// Kinds of methods
+const (
+	MethodInstance    = "instance"
+	MethodStatic      = "static"
+	MethodGetter      = "get"
+	MethodSetter      = "set"
+	MethodConstructor = "constructor"
+)
+
+// Kinds of type declarations
+const (
+	KindClass = "class"
+	KindAlias = "alias"
+	KindEnum  = "enum"
+)
+
+// Modifiers and documentation preceding a module-level declaration
+type declaration struct {
+	doc        string
+	decorators []string
+	exported   bool
+	isDefault  bool
+	ambient    bool // Declared with declare, without implementation
+}
+
+// Converts the tokens of one file, collecting the first syntax error
+type converter struct {
+	filename     string
+	tokens       []token
+	i            int
+	dependencies []string
+	exports      []string        // Public names of the module
+	exported     map[string]bool // Local names exported by export lists or module.exports
+	declared     map[string]bool // Module-level declarations already converted (overloads, merging)
+	err          error
+}
+
+// Records a syntax error at a token
+func (c *converter) errorf(tok token, msg string) {
+	if c.err == nil {
+		c.err = &SyntaxError{Pos: tok.pos, Msg: msg}
+	}
+}
+
+// Checks if the token k positions ahead is the given operator or keyword
+func (c *converter) at(k int, text string) bool {
+	return c.i+k < len(c.tokens) && c.tokens[c.i+k].is(text)
+}
+
+// Checks if the token k positions ahead is a name
+func (c *converter) nameAt(k int) bool {
+	return c.i+k < len(c.tokens) && c.tokens[c.i+k].kind == tokName
+}
+
+// Returns the current token
+func (c *converter) tok() token {
+	if c.i < len(c.tokens) {
+		return c.tokens[c.i]
+	}
+	return c.tokens[len(c.tokens)-1]
+}
+
+// Consumes the current token if it is the given operator or keyword
+func (c *converter) accept(text string) bool {
+	if c.at(0, text) {
+		c.i++
+		return true
+	}
+	return false
+}
+
+// Moves past the type parameter list opened at the current token. Records a syntax error and
+// reports false if it is not closed.
+func (c *converter) skipTypeParameters() bool {
+	end := matchingAngle(c.tokens, c.i)
+	if end < 0 {
+		c.errorf(c.tok(), "'<' was never closed")
+		return false
+	}
+	c.i = end + 1
+	return true
+}
+
+// Converts a module to our generic AST
+func (c *converter) convertModule() ast.Node {
+	node := ast.NewBaseNode(ast.Module, ast.Position{
+		Filename: c.filename,
+		Line:     1,
+		Column:   1,
+	})
+
+	node.SetAttribute("package_name", moduleName(c.filename))
+	node.SetAttribute("docstring", c.moduleDoc())
+	node.SetAttribute("is_declaration", strings.HasSuffix(c.filename, ".d.ts"))
+	c.dependencies = make([]string, 0)
+	c.exports = make([]string, 0)
+
+	for c.i < len(c.tokens) && c.err == nil {
+		start := c.i
+		c.convertStatement(node)
+		if c.i == start {
+			c.i++
+		}
+	}
+	node.SetAttribute("dependencies", c.dependencies)
+	node.SetAttribute("exports", c.exports)
+
+	// Export lists can precede or follow the declarations they export
+	for _, child := range node.Children() {
+		if name, ok := child.Attributes()["name"].(string); ok && c.exported[name] {
+			child.Attributes()["is_exported"] = true
+		}
+	}
+	return node
+}
+
+// Returns the file overview comment of a module, which is not the documentation of its first declaration
+func (c *converter) moduleDoc() string {
+	if len(c.tokens) == 0 {
+		return ""
+	}
+	doc := c.tokens[0].doc
+	for _, tag := range []string{"@file", "@fileoverview", "@module", "@packageDocumentation"} {
+		if strings.Contains(doc, tag) {
+			c.tokens[0].doc = ""
+			return doc
+		}
+	}
+	return ""
+}
+
+// Converts a module-level statement, skipping statements that are not declarations
+func (c *converter) convertStatement(node *ast.BaseNode) {
+	decl := declaration{doc: c.tok().doc}
+	decl.decorators = c.decorators()
+
+	if c.at(0, "export") {
+		switch {
+		case c.at(1, "*"), c.at(1, "{"), c.at(1, "type") && c.at(2, "{"):
+			c.i++
+			c.convertExportList(node)
+			return
+		case c.at(1, "="), c.at(1, "as"), c.at(1, "import"):
+			c.skipStatement()
+			return
+		case c.at(1, "default"):
+			decl.exported, decl.isDefault = true, true
+			c.i += 2
+		default:
+			decl.exported = true
+			c.i++
+		}
+	}
+	if c.at(0, "declare") && c.nameAt(1) && !c.tokens[c.i+1].newline {
+		decl.ambient = true
+		c.i++
+	}
+
+	tok := c.tok()
+	switch {
+	case tok.is("import") && !c.at(1, "(") && !c.at(1, "."):
+		c.convertImport(node)
+	case tok.is("function"), tok.is("async") && c.at(1, "function"):
+		c.add(node, c.convertFunction(decl), decl)
+	case tok.is("class"), tok.is("abstract") && c.at(1, "class"):
+		c.add(node, c.convertClass(decl), decl)
+	case tok.is("interface") && c.nameAt(1):
+		c.add(node, c.convertInterface(decl), decl)
+	case tok.is("type") && c.nameAt(1) && (c.at(2, "=") || c.at(2, "<")):
+		c.add(node, c.convertTypeAlias(decl), decl)
+	case tok.is("enum") && c.nameAt(1), tok.is("const") && c.at(1, "enum"):
+		c.add(node, c.convertEnum(decl), decl)
+	case tok.is("const"), tok.is("let"), tok.is("var"):
+		c.convertVariables(node, decl)
+	case (tok.is("namespace") || tok.is("module")) && c.i+1 < len(c.tokens) && (c.nameAt(1) || c.tokens[c.i+1].kind == tokString),
+		tok.is("global") && c.at(1, "{"):
+		// Namespaces and ambient module declarations are not part of the module structure
+		for c.i < len(c.tokens) && !c.at(0, "{") && !c.at(0, ";") {
+			c.i++
+		}
+		if c.at(0, "{") {
+			c.i = matchingBracket(c.tokens, c.i) + 1
+		}
+	case decl.isDefault:
+		c.convertDefaultExport(node, decl)
+	case c.isModuleExports():
+		c.convertModuleExports(node, decl)
+	default:
+		c.skipStatement()
+	}
+}
+
+// Adds a converted declaration to the module, skipping overloads and merged declarations
+func (c *converter) add(node *ast.BaseNode, child ast.Node, decl declaration) {
+	if child == nil {
+		return
+	}
+	name := child.Attributes()["name"].(string)
+	key := child.Type() + " " + name
+	if c.declared[key] {
+		return
+	}
+	c.declared[key] = true
+
+	child.Attributes()["is_exported"] = decl.exported
+	child.Attributes()["is_default_export"] = decl.isDefault
+	if decl.isDefault {
+		c.exports = append(c.exports, "default")
+	} else if decl.exported {
+		c.exports = append(c.exports, name)
+	}
+	node.AddChild(child)
+}
+
+// Consumes decorators (@name, @name.sub(args)), returning their text without @
+func (c *converter) decorators() []string {
+	decorators := make([]string, 0)
+	for c.at(0, "@") && c.nameAt(1) {
+		start := c.i + 1
+		_, n := dottedName(c.tokens[start:])
+		c.i = start + n
+		// Type arguments of a generic decorator factory (@dec<T>(...))
+		if c.at(0, "<") {
+			if end := matchingAngle(c.tokens, c.i); end > 0 && end+1 < len(c.tokens) && c.tokens[end+1].is("(") {
+				c.i = end + 1
+			}
+		}
+		if c.at(0, "(") {
+			c.i = matchingBracket(c.tokens, c.i) + 1
+		}
+		decorators = append(decorators, tokensText(c.tokens[start:c.i]))
+	}
+	return decorators
+}
+
+// Converts an import declaration to our generic AST
+// (import D, { a as b, type c } from "m"; import * as ns from "m"; import "m"; import x = require("m"))
+func (c *converter) convertImport(node *ast.BaseNode) {
+	start := c.tok()
+	c.i++
+	typeOnly := c.at(0, "type") && !c.at(1, "from") && !c.at(1, ",")
+	if typeOnly {
+		c.i++
+	}
+
+	names := make([]string, 0)
+	aliases := make([]string, 0)
+	alias := ""
+
+	// import x = require("m"), or an alias of a namespace member which is skipped
+	if c.nameAt(0) && c.at(1, "=") {
+		local := c.tok().text
+		c.i += 2
+		if c.at(0, "require") && c.at(1, "(") && c.i+2 < len(c.tokens) && c.tokens[c.i+2].kind == tokString {
+			path := stringValue(c.tokens[c.i+2].text)
+			c.i = matchingBracket(c.tokens, c.i+1) + 1
+			c.accept(";")
+			c.addImport(node, start, path, names, aliases, local, typeOnly, false)
+			return
+		}
+		c.skipStatement()
+		return
+	}
+
+	if c.nameAt(0) && !c.at(0, "from") || c.at(0, "from") && c.at(1, ",") {
+		names = append(names, "default")
+		aliases = append(aliases, c.tok().text)
+		c.i++
+		c.accept(",")
+	}
+	switch {
+	case c.at(0, "*") && c.at(1, "as") && c.nameAt(2):
+		alias = c.tokens[c.i+2].text
+		c.i += 3
+	case c.at(0, "{"):
+		end := matchingBracket(c.tokens, c.i)
+		for _, spec := range splitTopLevel(c.tokens[c.i+1:end], ",") {
+			if name, local, ok := specifier(spec); ok {
+				names = append(names, name)
+				aliases = append(aliases, local)
+			}
+		}
+		c.i = end + 1
+	}
+	if len(names) > 0 || alias != "" {
+		if !c.accept("from") {
+			c.errorf(c.tok(), "expected 'from'")
+			return
+		}
+	}
+	if c.i >= len(c.tokens) || c.tok().kind != tokString {
+		c.errorf(c.tok(), "expected a module path")
+		return
+	}
+	path := stringValue(c.tok().text)
+	c.i++
+	c.skipStatement() // Import attributes (with { type: "json" })
+	c.addImport(node, start, path, names, aliases, alias, typeOnly, false)
+}
+
+// Returns the name and alias of an import or export specifier (a, a as b, type a, default as b).
+// The alias is empty if the name is not renamed.
+func specifier(spec []token) (string, string, bool) {
+	if len(spec) > 1 && spec[0].is("type") && !spec[1].is("as") {
+		spec = spec[1:]
+	}
+	if len(spec) == 0 || (spec[0].kind != tokName && spec[0].kind != tokString) {
+		return "", "", false
+	}
+	name := spec[0].text
+	if spec[0].kind == tokString {
+		name = stringValue(name)
+	}
+	if len(spec) == 3 && spec[1].is("as") {
+		return name, spec[2].text, true
+	}
+	return name, "", true
+}
+
+// Adds an import node to the module and records the dependency
+func (c *converter) addImport(node *ast.BaseNode, start token, path string, names, aliases []string, alias string, typeOnly, reexport bool) {
+	imp := ast.NewBaseNode(ast.Import, start.pos)
+	imp.SetAttribute("path", path)
+	imp.SetAttribute("names", names)
+	imp.SetAttribute("aliases", aliases)
+	imp.SetAttribute("alias", alias)
+	imp.SetAttribute("is_type_only", typeOnly)
+	imp.SetAttribute("is_reexport", reexport)
+	imp.SetAttribute("is_relative", strings.HasPrefix(path, "."))
+	imp.SetAttribute("is_std_lib", isStdLib(path))
+	node.AddChild(imp)
+	c.dependencies = append(c.dependencies, path)
+}
+
+// Converts an export list (export { a, b as c }; export * as ns from "m"; export type { T } from "m").
+// Lists re-exporting from another module become imports.
+func (c *converter) convertExportList(node *ast.BaseNode) {
+	start := c.tokens[c.i-1]
+	typeOnly := c.accept("type")
+
+	names := make([]string, 0)
+	aliases := make([]string, 0)
+	alias := ""
+	if c.accept("*") {
+		names = append(names, "*")
+		aliases = append(aliases, "")
+		if c.accept("as") && c.nameAt(0) {
+			alias = c.tok().text
+			c.exports = append(c.exports, alias)
+			c.i++
+		}
+	} else {
+		end := matchingBracket(c.tokens, c.i)
+		for _, spec := range splitTopLevel(c.tokens[c.i+1:end], ",") {
+			if name, public, ok := specifier(spec); ok {
+				names = append(names, name)
+				aliases = append(aliases, public)
+			}
+		}
+		c.i = end + 1
+	}
+
+	if !c.accept("from") {
+		for i, name := range names {
+			c.exported[name] = true
+			if aliases[i] != "" {
+				name = aliases[i]
+			}
+			c.exports = append(c.exports, name)
+		}
+		c.skipStatement()
+		return
+	}
+	if c.i >= len(c.tokens) || c.tok().kind != tokString {
+		c.errorf(c.tok(), "expected a module path")
+		return
+	}
+	path := stringValue(c.tok().text)
+	c.i++
+	c.skipStatement()
+	for i, name := range names {
+		if name == "*" {
+			continue
+		}
+		if aliases[i] != "" {
+			name = aliases[i]
+		}
+		c.exports = append(c.exports, name)
+	}
+	c.addImport(node, start, path, names, aliases, alias, typeOnly, true)
+}
+
+// Converts the expression of export default, which may name or define the default export
+func (c *converter) convertDefaultExport(node *ast.BaseNode, decl declaration) {
+	start := c.i
+	end := c.expressionEnd(c.i, ";")
+	switch {
+	case end == start+1 && c.nameAt(0):
+		c.exported[c.tok().text] = true
+		c.exports = append(c.exports, "default")
+	default:
+		if fn := c.convertFunctionExpression("default", c.tok().pos, decl, c.tokens[start:end]); fn != nil {
+			c.add(node, fn, decl)
+		} else {
+			c.exports = append(c.exports, "default")
+		}
+	}
+	c.i = end
+	c.accept(";")
+}
+
+// Checks if the statement assigns to CommonJS exports (module.exports = ..., exports.name = ...)
+func (c *converter) isModuleExports() bool {
+	return c.at(0, "module") && c.at(1, ".") && c.at(2, "exports") || c.at(0, "exports") && c.at(1, ".")
+}
+
+// Converts a CommonJS export assignment, marking the local names it exports
+func (c *converter) convertModuleExports(node *ast.BaseNode, decl declaration) {
+	if c.at(0, "module") {
+		c.i += 2
+	}
+	c.i++ // exports
+	name := ""
+	if c.at(0, ".") && c.nameAt(1) {
+		name = c.tokens[c.i+1].text
+		c.i += 2
+	}
+	if !c.accept("=") {
+		c.skipStatement()
+		return
+	}
+
+	start := c.i
+	end := c.expressionEnd(c.i, ";")
+	value := c.tokens[start:end]
+	switch {
+	case name == "" && len(value) == 1 && value[0].kind == tokName:
+		c.exported[value[0].text] = true
+		c.exports = append(c.exports, "default")
+	case name == "" && len(value) > 0 && value[0].is("{") && closes(value, 0):
+		for _, prop := range splitTopLevel(value[1:len(value)-1], ",") {
+			if len(prop) == 0 || prop[0].kind != tokName {
+				continue
+			}
+			switch {
+			case len(prop) == 1:
+				c.exported[prop[0].text] = true
+			case len(prop) == 3 && prop[1].is(":") && prop[2].kind == tokName:
+				c.exported[prop[2].text] = true
+			}
+			c.exports = append(c.exports, prop[0].text)
+		}
+	case name != "":
+		decl.exported = true
+		if len(value) == 1 && value[0].kind == tokName {
+			c.exported[value[0].text] = true
+			c.exports = append(c.exports, name)
+		} else if fn := c.convertFunctionExpression(name, c.tokens[start-1].pos, decl, value); fn != nil {
+			c.add(node, fn, decl)
+		} else {
+			c.exports = append(c.exports, name)
+		}
+	}
+	c.i = end
+	c.accept(";")
+}
+
+// A parsed parameter
+type param struct {
+	name      string // Empty for destructuring patterns
+	typ       *TypeInfo
+	optional  bool
+	variadic  bool
+	modifiers []string // Accessibility and readonly modifiers of constructor parameter properties
+	pos       ast.Position
+}
+
+// Parameter modifiers which declare constructor parameter properties
+var parameterModifiers = []string{"public", "private", "protected", "readonly", "override"}
+
+// Parses the parameters between the parentheses of a parameter list. The this parameter,
+// which only types the receiver, is left out.
+func parameters(tokens []token) []param {
+	params := make([]param, 0)
+	for _, part := range splitTopLevel(tokens, ",") {
+		for len(part) > 1 && part[0].is("@") {
+			// Parameter decorators
+			_, n := dottedName(part[1:])
+			part = part[1+n:]
+			if len(part) > 0 && part[0].is("(") {
+				part = part[matchingBracket(part, 0)+1:]
+			}
+		}
+		var p param
+		for len(part) > 1 && part[0].kind == tokName && slices.Contains(parameterModifiers, part[0].text) &&
+			(part[1].kind == tokName || part[1].is("{") || part[1].is("[")) {
+			p.modifiers = append(p.modifiers, part[0].text)
+			part = part[1:]
+		}
+		if len(part) > 0 && part[0].is("...") {
+			p.variadic = true
+			part = part[1:]
+		}
+		if len(part) == 0 {
+			continue
+		}
+
+		i := 1
+		switch {
+		case part[0].is("this"):
+			continue
+		case part[0].kind == tokName:
+			p.name = part[0].text
+		case part[0].is("{"), part[0].is("["):
+			i = matchingBracket(part, 0) + 1
+		default:
+			continue
+		}
+		p.pos = part[0].pos
+		if i < len(part) && part[i].is("?") {
+			p.optional = true
+			i++
+		}
+		p.typ = &TypeInfo{}
+		if i < len(part) && part[i].is(":") {
+			p.typ, i = parseType(part, i+1)
+		}
+		if i < len(part) && part[i].is("=") {
+			p.optional = true
+			if !p.typ.isKnown() {
+				p.typ = inferType(part[i+1:])
+			}
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// A parsed call signature
+type callSignature struct {
+	typeParams []string
+	params     []param
+	returns    *TypeInfo // Nil if the return type is not annotated
+}
+
+// Parses a call signature (<T>(a: A): R) at tokens[i], returning it with the index just past it.
+// Reports false if there is no parameter list.
+func parseSignature(tokens []token, i int) (callSignature, int, bool) {
+	sig := callSignature{typeParams: make([]string, 0)}
+	if i < len(tokens) && tokens[i].is("<") {
+		end := matchingAngle(tokens, i)
+		if end < 0 {
+			return sig, i, false
+		}
+		for _, tp := range splitTopLevel(tokens[i+1:end], ",") {
+			if len(tp) > 0 && tp[0].is("const") {
+				tp = tp[1:]
+			}
+			if len(tp) > 0 && tp[0].kind == tokName {
+				sig.typeParams = append(sig.typeParams, tp[0].text)
+			}
+		}
+		i = end + 1
+	}
+	if i >= len(tokens) || !tokens[i].is("(") {
+		return sig, i, false
+	}
+	end := matchingBracket(tokens, i)
+	sig.params = parameters(tokens[i+1 : end])
+	i = end + 1
+	if i < len(tokens) && tokens[i].is(":") {
+		sig.returns, i = parseType(tokens, i+1)
+	}
+	return sig, i, true
+}
+
+// Sets the signature attributes of a function or method node
+func setSignature(node *ast.BaseNode, sig callSignature) {
+	params := make([]*TypeInfo, 0, len(sig.params))
+	names := make([]string, 0, len(sig.params))
+	variadic := false
+	for _, p := range sig.params {
+		params = append(params, p.typ)
+		names = append(names, p.name)
+		variadic = variadic || p.variadic
+	}
+	returns := make([]*TypeInfo, 0)
+	returnNames := make([]string, 0)
+	if sig.returns != nil {
+		returns = append(returns, sig.returns)
+		returnNames = append(returnNames, "")
+	}
+
+	node.SetAttribute("type_params", sig.typeParams)
+	node.SetAttribute("signature", map[string]any{
+		"params":       params,
+		"returns":      returns,
+		"param_names":  names,
+		"return_names": returnNames,
+		"variadic":     variadic,
+	})
+}
+
+// Skips a function body, or the end of a signature without one (overloads, ambient declarations),
+// returning the tokens of the body
+func (c *converter) skipBody() []token {
+	if c.at(0, "{") {
+		start := c.i
+		c.i = matchingBracket(c.tokens, c.i) + 1
+		return c.tokens[start:c.i]
+	}
+	c.accept(";")
+	return nil
+}
+
+// Converts a function declaration (async function* name<T>(a: A): R { ... }) to our generic AST
+func (c *converter) convertFunction(decl declaration) ast.Node {
+	pos := c.tok().pos
+	isAsync := c.accept("async")
+	c.i++ // function
+	isGenerator := c.accept("*")
+	name := "default"
+	if c.nameAt(0) {
+		name = c.tok().text
+		c.i++
+	} else if !decl.isDefault {
+		c.errorf(c.tok(), "expected a function name")
+		return nil
+	}
+
+	sig, next, ok := parseSignature(c.tokens, c.i)
+	if !ok {
+		c.errorf(c.tokens[min(next, len(c.tokens)-1)], "expected '('")
+		return nil
+	}
+	c.i = next
+	body := c.skipBody()
+
+	node := ast.NewBaseNode(ast.Function, pos)
+	node.SetAttribute("name", name)
+	node.SetAttribute("is_async", isAsync)
+	node.SetAttribute("is_generator", isGenerator)
+	node.SetAttribute("is_arrow", false)
+	node.SetAttribute("is_ambient", decl.ambient)
+	node.SetAttribute("decorators", decl.decorators)
+	node.SetAttribute("docstring", decl.doc)
+	node.SetAttribute("http_calls", httpCalls(body))
+	setSignature(node, sig)
+	return node
+}
+
+// Converts a function or arrow function expression bound to a name (const f = async (a: A) => ...)
+// to a function node. Returns nil if the expression is not a function.
+func (c *converter) convertFunctionExpression(name string, pos ast.Position, decl declaration, tokens []token) ast.Node {
+	i := 0
+	isAsync := len(tokens) > 1 && tokens[0].is("async") && !tokens[1].newline
+	if isAsync {
+		i++
+	}
+	if i >= len(tokens) {
+		return nil
+	}
+
+	isArrow, isGenerator := true, false
+	var sig callSignature
+	switch {
+	case tokens[i].is("function"):
+		isArrow = false
+		i++
+		if i < len(tokens) && tokens[i].is("*") {
+			isGenerator = true
+			i++
+		}
+		if i < len(tokens) && tokens[i].kind == tokName {
+			i++
+		}
+		var ok bool
+		if sig, i, ok = parseSignature(tokens, i); !ok || i >= len(tokens) || !tokens[i].is("{") {
+			return nil
+		}
+	case tokens[i].kind == tokName && i+1 < len(tokens) && tokens[i+1].is("=>"):
+		sig = callSignature{
+			typeParams: make([]string, 0),
+			params:     []param{{name: tokens[i].text, typ: &TypeInfo{}, pos: tokens[i].pos}},
+		}
+		i++
+	default:
+		var ok bool
+		if sig, i, ok = parseSignature(tokens, i); !ok || i >= len(tokens) || !tokens[i].is("=>") {
+			return nil
+		}
+	}
+
+	node := ast.NewBaseNode(ast.Function, pos)
+	node.SetAttribute("name", name)
+	node.SetAttribute("is_async", isAsync)
+	node.SetAttribute("is_generator", isGenerator)
+	node.SetAttribute("is_arrow", isArrow)
+	node.SetAttribute("is_ambient", false)
+	node.SetAttribute("decorators", decl.decorators)
+	node.SetAttribute("docstring", decl.doc)
+	node.SetAttribute("http_calls", httpCalls(tokens[i:]))
+	setSignature(node, sig)
+	return node
+}
+
+// Class member modifiers
+var memberModifiers = []string{
+	"public", "private", "protected", "static", "readonly", "abstract", "override", "declare", "accessor", "async",
+}
+
+// Checks if the token k positions ahead can start a class or interface member name
+func (c *converter) memberNameAt(k int) bool {
+	if c.i+k >= len(c.tokens) {
+		return false
+	}
+	tok := c.tokens[c.i+k]
+	return tok.kind == tokName || tok.kind == tokString || tok.kind == tokNumber || tok.is("[") || tok.is("*")
+}
+
+// Converts a class declaration to our generic AST, with its methods as children
+func (c *converter) convertClass(decl declaration) ast.Node {
+	pos := c.tok().pos
+	isAbstract := c.accept("abstract")
+	c.i++ // class
+	name := "default"
+	if c.nameAt(0) && !c.at(0, "extends") && !c.at(0, "implements") {
+		name = c.tok().text
+		c.i++
+	} else if !decl.isDefault {
+		c.errorf(c.tok(), "expected a class name")
+		return nil
+	}
+
+	typeParams := make([]string, 0)
+	if c.at(0, "<") {
+		sig, _, _ := parseSignature(c.tokens, c.i)
+		typeParams = sig.typeParams
+		if !c.skipTypeParameters() {
+			return nil
+		}
+	}
+
+	bases := make([]*TypeInfo, 0)
+	if c.accept("extends") {
+		base, next := parseType(c.tokens, c.i)
+		c.i = next
+		if c.at(0, "(") {
+			// A mixin call (extends Mixin(Base)) has no static type we can name
+			c.i = matchingBracket(c.tokens, c.i) + 1
+			base = &TypeInfo{}
+		}
+		bases = append(bases, base)
+	}
+	implements := make([]*TypeInfo, 0)
+	if c.accept("implements") {
+		for {
+			t, next := parseType(c.tokens, c.i)
+			c.i = next
+			implements = append(implements, t)
+			if !c.accept(",") {
+				break
+			}
+		}
+	}
+	if !c.at(0, "{") {
+		c.errorf(c.tok(), "expected '{'")
+		return nil
+	}
+	close := matchingBracket(c.tokens, c.i)
+	c.i++
+
+	node := ast.NewBaseNode(ast.Type, pos)
+	node.SetAttribute("name", name)
+	node.SetAttribute("kind", KindClass)
+	node.SetAttribute("bases", bases)
+	node.SetAttribute("implements", implements)
+	node.SetAttribute("type_params", typeParams)
+	node.SetAttribute("decorators", decl.decorators)
+	node.SetAttribute("docstring", decl.doc)
+
+	fields, methods := c.convertClassBody(name, close)
+	for _, method := range methods {
+		if method.Attributes()["is_abstract"] == true {
+			isAbstract = true
+		}
+		node.AddChild(method)
+	}
+	node.SetAttribute("fields", fields)
+	node.SetAttribute("is_abstract", isAbstract)
+	c.i = close + 1
+	return node
+}
+
+// Converts the members of a class body ending at close into fields and method nodes
+func (c *converter) convertClassBody(class string, close int) ([]map[string]any, []ast.Node) {
+	fields := make([]map[string]any, 0)
+	var methods []ast.Node
+	seen := make(map[string]bool)
+
+	for c.i < close && c.err == nil {
+		if c.accept(";") {
+			continue
+		}
+		if c.at(0, "static") && c.at(1, "{") {
+			c.i = matchingBracket(c.tokens, c.i+1) + 1 // Static initialization block
+			continue
+		}
+		start := c.i
+		doc := c.tok().doc
+		decorators := c.decorators()
+		modifiers := make(map[string]bool)
+		for c.nameAt(0) && slices.Contains(memberModifiers, c.tok().text) && c.memberNameAt(1) && !c.tokens[c.i+1].newline {
+			modifiers[c.tok().text] = true
+			c.i++
+		}
+		kind := MethodInstance
+		if modifiers["static"] {
+			kind = MethodStatic
+		}
+		if (c.at(0, "get") || c.at(0, "set")) && c.memberNameAt(1) && !c.at(1, "*") {
+			kind = c.tok().text
+			c.i++
+		}
+		isGenerator := c.accept("*")
+
+		// Member name, or an index signature
+		nameTok := c.tok()
+		name := nameTok.text
+		switch {
+		case nameTok.is("["):
+			end := matchingBracket(c.tokens, c.i)
+			if indexTopLevel(c.tokens[c.i+1:end], ":", 0) >= 0 {
+				c.i = c.expressionEnd(end+1, ";")
+				continue
+			}
+			name = tokensText(c.tokens[c.i : end+1]) // Computed name
+			c.i = end + 1
+		case nameTok.kind == tokString:
+			name = stringValue(name)
+			c.i++
+		case nameTok.kind == tokName, nameTok.kind == tokNumber:
+			c.i++
+		default:
+			c.errorf(nameTok, "unexpected token in class body")
+			return fields, methods
+		}
+		optional := c.accept("?")
+		c.accept("!")
+
+		visibility := "public"
+		switch {
+		case modifiers["private"] || strings.HasPrefix(name, "#"):
+			visibility = "private"
+		case modifiers["protected"]:
+			visibility = "protected"
+		}
+		if name == "constructor" && kind == MethodInstance {
+			kind = MethodConstructor
+		}
+
+		if c.at(0, "(") || c.at(0, "<") {
+			sig, next, ok := parseSignature(c.tokens, c.i)
+			if !ok {
+				c.errorf(c.tokens[min(next, len(c.tokens)-1)], "expected '('")
+				return fields, methods
+			}
+			c.i = next
+			body := c.skipBody()
+
+			if kind == MethodConstructor {
+				for _, p := range sig.params {
+					if len(p.modifiers) > 0 && p.name != "" {
+						fields = append(fields, parameterProperty(p))
+					}
+				}
+			}
+			key := kind + " " + name
+			if seen[key] {
+				continue // Overload
+			}
+			seen[key] = true
+			method := ast.NewBaseNode(ast.Method, c.tokens[start].pos)
+			method.SetAttribute("name", name)
+			method.SetAttribute("is_async", modifiers["async"])
+			method.SetAttribute("is_generator", isGenerator)
+			method.SetAttribute("is_arrow", false)
+			method.SetAttribute("http_calls", httpCalls(body))
+			setMember(method, class, kind, visibility, modifiers, decorators, doc)
+			setSignature(method, sig)
+			methods = append(methods, method)
+			continue
+		}
+
+		// Property, whose initializer may be an arrow function acting as a method
+		typ := &TypeInfo{}
+		if c.accept(":") {
+			typ, c.i = parseType(c.tokens, c.i)
+		}
+		if c.accept("=") {
+			end := c.expressionEnd(c.i, ";")
+			value := c.tokens[c.i:end]
+			c.i = end
+			if fn := c.convertFunctionExpression(name, nameTok.pos, declaration{doc: doc, decorators: decorators}, value); fn != nil {
+				method := ast.NewBaseNode(ast.Method, c.tokens[start].pos)
+				for key, v := range fn.Attributes() {
+					if key != "is_ambient" {
+						method.SetAttribute(key, v)
+					}
+				}
+				setMember(method, class, kind, visibility, modifiers, decorators, doc)
+				methods = append(methods, method)
+				continue
+			}
+			if !typ.isKnown() {
+				typ = inferType(value)
+			}
+		}
+		fields = append(fields, map[string]any{
+			"name":        name,
+			"type":        typ,
+			"embedded":    false,
+			"is_exported": visibility == "public",
+			"position":    nameTok.pos,
+			"visibility":  visibility,
+			"is_static":   modifiers["static"],
+			"is_readonly": modifiers["readonly"],
+			"is_optional": optional,
+		})
+	}
+	return fields, methods
+}
+
+// Sets the attributes shared by all class methods
+func setMember(method *ast.BaseNode, class, kind, visibility string, modifiers map[string]bool, decorators []string, doc string) {
+	method.SetAttribute("is_exported", visibility == "public")
+	method.SetAttribute("visibility", visibility)
+	method.SetAttribute("method_kind", kind)
+	method.SetAttribute("is_static", modifiers["static"])
+	method.SetAttribute("is_abstract", modifiers["abstract"])
+	method.SetAttribute("decorators", decorators)
+	method.SetAttribute("docstring", doc)
+	method.SetAttribute("receiver_type", &TypeInfo{Name: class})
+	method.SetAttribute("receiver_name", "this")
+}
+
+// Returns the field declared by a constructor parameter property (constructor(private repo: Repo))
+func parameterProperty(p param) map[string]any {
+	visibility := "public"
+	for _, m := range p.modifiers {
+		if m == "private" || m == "protected" {
+			visibility = m
+		}
+	}
+	return map[string]any{
+		"name":        p.name,
+		"type":        p.typ,
+		"embedded":    false,
+		"is_exported": visibility == "public",
+		"position":    p.pos,
+		"visibility":  visibility,
+		"is_static":   false,
+		"is_readonly": slices.Contains(p.modifiers, "readonly"),
+		"is_optional": p.optional,
+	}
+}
+
+// Converts an interface declaration to our generic AST. Like Go interfaces, its methods are
+// described by the interface itself.
+func (c *converter) convertInterface(decl declaration) ast.Node {
+	pos := c.tok().pos
+	name := c.tokens[c.i+1].text
+	c.i += 2
+
+	typeParams := make([]string, 0)
+	if c.at(0, "<") {
+		sig, _, _ := parseSignature(c.tokens, c.i)
+		typeParams = sig.typeParams
+		if !c.skipTypeParameters() {
+			return nil
+		}
+	}
+	bases := make([]*TypeInfo, 0)
+	if c.accept("extends") {
+		for {
+			t, next := parseType(c.tokens, c.i)
+			c.i = next
+			bases = append(bases, t)
+			if !c.accept(",") {
+				break
+			}
+		}
+	}
+	if !c.at(0, "{") {
+		c.errorf(c.tok(), "expected '{'")
+		return nil
+	}
+
+	node := ast.NewBaseNode(ast.Interface, pos)
+	node.SetAttribute("name", name)
+	node.SetAttribute("bases", bases)
+	node.SetAttribute("type_params", typeParams)
+	node.SetAttribute("docstring", decl.doc)
+	fields, methods := objectMembers(c.tokens, c.i)
+	node.SetAttribute("fields", fields)
+	node.SetAttribute("methods", methods)
+	c.i = matchingBracket(c.tokens, c.i) + 1
+	return node
+}
+
+// Returns the property and method signatures of the object type whose opening brace is at tokens[open]
+func objectMembers(tokens []token, open int) ([]map[string]any, []map[string]any) {
+	fields := make([]map[string]any, 0)
+	methods := make([]map[string]any, 0)
+	close := matchingBracket(tokens, open)
+
+	i := open + 1
+	for i < close {
+		start := i
+		if tokens[i].is(";") || tokens[i].is(",") {
+			i++
+			continue
+		}
+		readonly := false
+		if tokens[i].is("readonly") && i+1 < close && !tokens[i+1].is(":") && !tokens[i+1].is("?") && !tokens[i+1].is("(") {
+			readonly = true
+			i++
+		}
+		if (tokens[i].is("get") || tokens[i].is("set")) && i+1 < close && tokens[i+1].kind == tokName {
+			i++ // Accessors are described as properties
+		}
+
+		nameTok := tokens[i]
+		name := nameTok.text
+		switch {
+		case nameTok.kind == tokName, nameTok.kind == tokNumber:
+			i++
+		case nameTok.kind == tokString:
+			name = stringValue(name)
+			i++
+		default:
+			// Index, call and construct signatures
+			i = memberEnd(tokens, i, close)
+			continue
+		}
+		optional := false
+		if i < close && tokens[i].is("?") {
+			optional = true
+			i++
+		}
+
+		if i < close && (tokens[i].is("(") || tokens[i].is("<")) {
+			sig, next, ok := parseSignature(tokens, i)
+			if ok {
+				m := ast.NewBaseNode(ast.Method, nameTok.pos)
+				setSignature(m, sig)
+				methods = append(methods, map[string]any{
+					"name":        name,
+					"signature":   m.Attributes()["signature"],
+					"is_optional": optional,
+				})
+			}
+			i = max(next, i+1)
+		} else {
+			typ := &TypeInfo{}
+			if i < close && tokens[i].is(":") {
+				typ, i = parseType(tokens, i+1)
+			}
+			fields = append(fields, map[string]any{
+				"name":        name,
+				"type":        typ,
+				"embedded":    false,
+				"is_exported": true,
+				"position":    nameTok.pos,
+				"is_readonly": readonly,
+				"is_optional": optional,
+			})
+		}
+		if i == start {
+			i++
+		}
+	}
+	return fields, methods
+}
+
+// Returns the index past a member of an object type starting at i, skipping to the next separator
+func memberEnd(tokens []token, i, close int) int {
+	depth := 0
+	for ; i < close; i++ {
+		switch {
+		case tokens[i].kind != tokOp:
+		case tokens[i].text == "(" || tokens[i].text == "[" || tokens[i].text == "{":
+			depth++
+		case tokens[i].text == ")" || tokens[i].text == "]" || tokens[i].text == "}":
+			depth--
+		case depth == 0 && (tokens[i].text == ";" || tokens[i].text == ","):
+			return i + 1
+		}
+		if depth == 0 && i+1 < close && tokens[i+1].newline {
+			return i + 1
+		}
+	}
+	return close
+}
+
+// Converts a type alias (type Name<T> = ...) to our generic AST. Aliases of object types
+// carry the members of the object type.
+func (c *converter) convertTypeAlias(decl declaration) ast.Node {
+	pos := c.tok().pos
+	name := c.tokens[c.i+1].text
+	c.i += 2
+
+	typeParams := make([]string, 0)
+	if c.at(0, "<") {
+		sig, _, _ := parseSignature(c.tokens, c.i)
+		typeParams = sig.typeParams
+		if !c.skipTypeParameters() {
+			return nil
+		}
+	}
+	if !c.accept("=") {
+		c.errorf(c.tok(), "expected '='")
+		return nil
+	}
+
+	node := ast.NewBaseNode(ast.Type, pos)
+	node.SetAttribute("name", name)
+	node.SetAttribute("kind", KindAlias)
+	node.SetAttribute("type_params", typeParams)
+	node.SetAttribute("docstring", decl.doc)
+	fields := make([]map[string]any, 0)
+	if c.at(0, "{") {
+		var methods []map[string]any
+		fields, methods = objectMembers(c.tokens, c.i)
+		node.SetAttribute("methods", methods)
+	}
+	node.SetAttribute("fields", fields)
+
+	aliased, next := parseType(c.tokens, c.i)
+	node.SetAttribute("aliased", aliased)
+	c.i = next
+	c.skipStatement()
+	return node
+}
+
+// Converts an enum declaration (const enum Name { A, B = 1 }) to our generic AST
+func (c *converter) convertEnum(decl declaration) ast.Node {
+	pos := c.tok().pos
+	isConst := c.accept("const")
+	name := c.tokens[c.i+1].text
+	c.i += 2
+	if !c.at(0, "{") {
+		c.errorf(c.tok(), "expected '{'")
+		return nil
+	}
+	end := matchingBracket(c.tokens, c.i)
+	members := make([]string, 0)
+	for _, member := range splitTopLevel(c.tokens[c.i+1:end], ",") {
+		switch {
+		case len(member) == 0:
+		case member[0].kind == tokString:
+			members = append(members, stringValue(member[0].text))
+		case member[0].kind == tokName:
+			members = append(members, member[0].text)
+		}
+	}
+	c.i = end + 1
+
+	node := ast.NewBaseNode(ast.Type, pos)
+	node.SetAttribute("name", name)
+	node.SetAttribute("kind", KindEnum)
+	node.SetAttribute("is_const", isConst)
+	node.SetAttribute("members", members)
+	node.SetAttribute("type_params", make([]string, 0))
+	node.SetAttribute("docstring", decl.doc)
+	node.SetAttribute("fields", make([]map[string]any, 0))
+	return node
+}
+
+// Converts a variable statement to variable nodes. Function expressions become function
+// nodes, and require calls become imports.
+func (c *converter) convertVariables(node *ast.BaseNode, decl declaration) {
+	kind := c.tok().text
+	c.i++
+	for c.i < len(c.tokens) && c.err == nil {
+		target := c.tok()
+		var bound [][2]string // Property name and local name of destructured bindings
+		switch {
+		case target.kind == tokName:
+			c.i++
+		case target.is("{"), target.is("["):
+			end := matchingBracket(c.tokens, c.i)
+			bound = bindings(c.tokens[c.i : end+1])
+			c.i = end + 1
+		default:
+			c.errorf(target, "expected a variable name")
+			return
+		}
+		c.accept("!")
+
+		typ := &TypeInfo{}
+		if c.accept(":") {
+			typ, c.i = parseType(c.tokens, c.i)
+		}
+		var value []token
+		if c.accept("=") {
+			end := c.expressionEnd(c.i, ",", ";")
+			value = c.tokens[c.i:end]
+			c.i = end
+		}
+
+		switch {
+		case isRequire(value):
+			path := stringValue(value[2].text)
+			names := make([]string, 0)
+			aliases := make([]string, 0)
+			alias := ""
+			if bound == nil {
+				alias = target.text
+			}
+			for _, b := range bound {
+				names = append(names, b[0])
+				if b[0] == b[1] {
+					aliases = append(aliases, "")
+				} else {
+					aliases = append(aliases, b[1])
+				}
+			}
+			c.addImport(node, target, path, names, aliases, alias, false, false)
+
+		case bound == nil:
+			d := decl
+			if d.doc == "" {
+				d.doc = target.doc
+			}
+			if fn := c.convertFunctionExpression(target.text, target.pos, d, value); fn != nil {
+				c.add(node, fn, decl)
+				break
+			}
+			if !typ.isKnown() {
+				typ = inferType(value)
+			}
+			c.add(node, variable(target.text, target.pos, kind, typ), decl)
+
+		default:
+			for _, b := range bound {
+				c.add(node, variable(b[1], target.pos, kind, &TypeInfo{}), decl)
+			}
+		}
+
+		if !c.accept(",") {
+			break
+		}
+	}
+	c.accept(";")
+}
+
+// Creates a variable node
+func variable(name string, pos ast.Position, kind string, typ *TypeInfo) ast.Node {
+	node := ast.NewBaseNode(ast.Variable, pos)
+	node.SetAttribute("name", name)
+	node.SetAttribute("kind", kind)
+	node.SetAttribute("is_constant", kind == "const")
+	node.SetAttribute("type", typ)
+	return node
+}
+
+// Checks if an initializer is a CommonJS require call with a literal path
+func isRequire(value []token) bool {
+	return len(value) == 4 && value[0].is("require") && value[1].is("(") && value[2].kind == tokString && value[3].is(")")
+}
+
+// Returns the property and local names bound by a destructuring pattern
+// ({ a, b: c, ...rest } or [a, , b]). Array elements are bound by position.
+func bindings(pattern []token) [][2]string {
+	var result [][2]string
+	isObject := pattern[0].is("{")
+	for i, part := range splitTopLevel(pattern[1:len(pattern)-1], ",") {
+		if len(part) > 0 && part[0].is("...") {
+			part = part[1:]
+		}
+		if len(part) == 0 {
+			continue
+		}
+		property := part[0].text
+		if !isObject {
+			property = strconv.Itoa(i)
+		}
+		local := part[0]
+		if colon := indexTopLevel(part, ":", 0); isObject && colon >= 0 && colon+1 < len(part) {
+			if part[colon+1].is("{") || part[colon+1].is("[") {
+				result = append(result, bindings(part[colon+1:matchingBracket(part, colon+1)+1])...)
+				continue
+			}
+			local = part[colon+1]
+		} else if part[0].is("{") || part[0].is("[") {
+			result = append(result, bindings(part[:matchingBracket(part, 0)+1])...)
+			continue
+		}
+		if local.kind == tokName {
+			result = append(result, [2]string{property, local.text})
+		}
+	}
+	return result
+}
+
+// Skips the rest of a statement, including its semicolon
+func (c *converter) skipStatement() {
+	start := c.i
+	c.i = max(c.expressionEnd(c.i, ";"), c.i)
+	if !c.accept(";") && c.i == start {
+		c.i++
+	}
+}
+
+// Keywords that continue a statement on the next line
+var continuationKeywords = map[string]bool{
+	"else": true, "catch": true, "finally": true, "while": true, "instanceof": true, "in": true,
+	"of": true, "as": true, "satisfies": true, "extends": true, "implements": true,
+}
+
+// Returns the index where the expression or statement starting at i ends: at a top-level
+// stop operator, at a closing bracket it did not open, or at a line break where automatic
+// semicolon insertion ends it
+func (c *converter) expressionEnd(i int, stops ...string) int {
+	depth := 0
+	asserted := false // In the type of an assertion (x as Map<K, V>) outside brackets
+	for j := i; j < len(c.tokens); j++ {
+		tok := c.tokens[j]
+		if depth == 0 && j > i && tok.newline && endsExpression(c.tokens[j-1]) && startsStatement(tok) {
+			return j
+		}
+		if tok.kind == tokName && depth == 0 && (tok.text == "as" || tok.text == "satisfies") {
+			asserted = true
+		}
+		if tok.kind != tokOp {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			if depth == 0 {
+				return j
+			}
+			depth--
+		case "<":
+			// Type arguments of a generic call (f<A, B>(x)) or asserted type, and type
+			// parameters of a generic arrow function (<T,>(x: T) => x) may contain commas
+			if j == i || c.tokens[j-1].kind == tokName {
+				end := matchingAngle(c.tokens, j)
+				if end > 0 && ((asserted && depth == 0) || (end+1 < len(c.tokens) && c.tokens[end+1].is("("))) {
+					j = end
+				}
+			}
+		default:
+			if depth == 0 && slices.Contains(stops, tok.text) {
+				return j
+			}
+		}
+	}
+	return len(c.tokens)
+}
+
+// Checks if a statement may end after the token
+func endsExpression(tok token) bool {
+	switch tok.kind {
+	case tokName:
+		return !expressionKeywords[tok.text]
+	case tokOp:
+		return tok.text == ")" || tok.text == "]" || tok.text == "}" || tok.text == "++" || tok.text == "--"
+	}
+	return true
+}
+
+// Checks if the token may start a new statement rather than continue the previous one
+func startsStatement(tok token) bool {
+	switch tok.kind {
+	case tokName:
+		return !continuationKeywords[tok.text]
+	case tokOp:
+		return tok.text == "{" || tok.text == "@" || tok.text == "!" || tok.text == "++" || tok.text == "--" || tok.text == "~"
+	}
+	return true
+}
+
+// Returns the module name of a file: the file name without extensions, or the directory
+// name for an index file
+func moduleName(filename string) string {
+	name := filepath.Base(filename)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	name = strings.TrimSuffix(name, ".d")
+	if name == "index" {
+		return filepath.Base(filepath.Dir(filename))
+	}
+	return name
+}
+
+// Returns the index of the bracket closing the one at index open
+func matchingBracket(tokens []token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].kind != tokOp {
+			continue
+		}
+		switch tokens[i].text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// Returns the index of the > closing the type parameter or argument list opened at index
+// open, or -1 if the < is not closed before the end of the enclosing expression
+func matchingAngle(tokens []token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].kind != tokOp {
+			continue
+		}
+		switch tokens[i].text {
+		case "<":
+			depth++
+		case ">":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case "(", "[", "{":
+			i = matchingBracket(tokens, i)
+		case ")", "]", "}", ";", "&&", "||":
+			return -1
+		}
+	}
+	return -1
+}
+
+// Returns the index of the first operator outside brackets and type argument lists at or after start, or -1
+func indexTopLevel(tokens []token, op string, start int) int {
+	depth, angles := 0, 0
+	for i, tok := range tokens {
+		if tok.kind != tokOp {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		case "<":
+			angles++
+		case ">":
+			if angles > 0 {
+				angles--
+			}
+		}
+		if tok.text == op && depth == 0 && angles == 0 && i >= start {
+			return i
+		}
+	}
+	return -1
+}
+
+// Splits tokens at an operator outside brackets and type argument lists
+func splitTopLevel(tokens []token, op string) [][]token {
+	var parts [][]token
+	start := 0
+	for {
+		i := indexTopLevel(tokens, op, start)
+		if i < 0 {
+			break
+		}
+		parts = append(parts, tokens[start:i])
+		start = i + 1
+	}
+	return append(parts, tokens[start:])
+}
+
+// Returns the source-like text of tokens (e.g. for decorators)
+func tokensText(tokens []token) string {
+	var b strings.Builder
+	for i, tok := range tokens {
+		if i > 0 {
+			prev := tokens[i-1]
+			if prev.is(",") || (prev.kind != tokOp && tok.kind != tokOp) {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(tok.text)
+	}
+	return b.String()
+}
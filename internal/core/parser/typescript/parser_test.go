@@ -0,0 +1,492 @@
+package tsparser_test
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"codedna/internal/core/parser"
+	"codedna/internal/core/parser/ast"
+	tsparser "codedna/internal/core/parser/typescript"
+)
+
+// Helper function to find nodes of a specific type
+func findNodes(root ast.Node, nodeType ast.NodeType) []ast.Node {
+	var nodes []ast.Node
+	var walk func(ast.Node)
+	walk = func(n ast.Node) {
+		if n.Type() == string(nodeType) {
+			nodes = append(nodes, n)
+		}
+		for _, child := range n.Children() {
+			walk(child)
+		}
+	}
+	walk(root)
+	return nodes
+}
+
+// Helper function to find a named node of a specific type
+func findNode(t *testing.T, root ast.Node, nodeType ast.NodeType, name string) ast.Node {
+	t.Helper()
+	for _, node := range findNodes(root, nodeType) {
+		if node.Attributes()["name"] == name {
+			return node
+		}
+	}
+	t.Fatalf("%s %s not found", nodeType, name)
+	return nil
+}
+
+// Helper function to parse source written to a temporary file with the given extension
+func parseSource(t *testing.T, ext, src string) (ast.Node, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "module"+ext)
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("Failed to write source: %v", err)
+	}
+	return tsparser.New().ParseFile(path)
+}
+
+// Helper function to get the types of a signature as strings
+func signatureTypes(t *testing.T, signature any, key string) []string {
+	t.Helper()
+	var types []string
+	for _, typ := range signature.(map[string]any)[key].([]*tsparser.TypeInfo) {
+		types = append(types, typ.String())
+	}
+	return types
+}
+
+// Helper function to get the types of fields by name
+func fieldTypes(node ast.Node) map[string]string {
+	types := make(map[string]string)
+	for _, field := range node.Attributes()["fields"].([]map[string]any) {
+		types[field["name"].(string)] = field["type"].(*tsparser.TypeInfo).String()
+	}
+	return types
+}
+
+func TestParseFile(t *testing.T) {
+	root, err := tsparser.New().ParseFile(filepath.Join("testdata", "shapes.ts"))
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	t.Run("Module", func(t *testing.T) {
+		attrs := root.Attributes()
+		if root.Type() != string(ast.Module) || attrs["package_name"] != "shapes" {
+			t.Errorf("Expected module shapes, got %s %v", root.Type(), attrs["package_name"])
+		}
+		if doc := attrs["docstring"]; doc != "@file Shape models shared by the frontend." {
+			t.Errorf("Unexpected module docstring %q", doc)
+		}
+		want := []string{"events", "./canvas", "./util", "react", "./colors", "./palette"}
+		if deps := attrs["dependencies"].([]string); !slices.Equal(deps, want) {
+			t.Errorf("Expected dependencies %v, got %v", want, deps)
+		}
+		exports := []string{
+			"Shape", "Drawable", "Point", "Id", "Color", "Base", "Square", "area", "DEFAULT_SIDE",
+			"registry", "render", "count", "label", "Colors", "default",
+		}
+		if got := attrs["exports"].([]string); !slices.Equal(got, exports) {
+			t.Errorf("Expected exports %v, got %v", exports, got)
+		}
+	})
+
+	t.Run("Imports", func(t *testing.T) {
+		imports := findNodes(root, ast.Import)
+		if len(imports) != 6 {
+			t.Fatalf("Expected 6 imports, got %d", len(imports))
+		}
+		if events := imports[0].Attributes(); events["is_std_lib"] != true || events["is_relative"] != false {
+			t.Errorf("Expected a std lib import, got %v", events)
+		}
+		if canvas := imports[1].Attributes(); canvas["is_type_only"] != true || canvas["is_relative"] != true {
+			t.Errorf("Expected a relative type-only import, got %v", canvas)
+		}
+		if util := imports[2].Attributes(); util["alias"] != "util" {
+			t.Errorf("Expected namespace import util, got %v", util)
+		}
+		react := imports[3].Attributes()
+		if names, aliases := react["names"].([]string), react["aliases"].([]string); !slices.Equal(names, []string{"default", "useState"}) ||
+			!slices.Equal(aliases, []string{"React", "useLocalState"}) {
+			t.Errorf("Unexpected react import %v %v", names, aliases)
+		}
+		palette := imports[5].Attributes()
+		if palette["is_reexport"] != true || !slices.Equal(palette["aliases"].([]string), []string{"Colors"}) {
+			t.Errorf("Expected re-export of Palette as Colors, got %v", palette)
+		}
+	})
+
+	t.Run("Interfaces", func(t *testing.T) {
+		shape := findNode(t, root, ast.Interface, "Shape")
+		if shape.Attributes()["docstring"] != "Something with an area." {
+			t.Errorf("Unexpected docstring %q", shape.Attributes()["docstring"])
+		}
+		methods := shape.Attributes()["methods"].([]map[string]any)
+		if len(methods) != 2 || methods[0]["name"] != "area" || methods[1]["name"] != "scale" || methods[1]["is_optional"] != true {
+			t.Fatalf("Expected methods area and optional scale, got %v", methods)
+		}
+		if got := signatureTypes(t, methods[1]["signature"], "params"); !slices.Equal(got, []string{"number", "Point"}) {
+			t.Errorf("Unexpected scale params %v", got)
+		}
+		if got := fieldTypes(shape); got["name"] != "string" {
+			t.Errorf("Expected name field of type string, got %v", got)
+		}
+
+		drawable := findNode(t, root, ast.Interface, "Drawable")
+		if bases := drawable.Attributes()["bases"].([]*tsparser.TypeInfo); len(bases) != 1 || bases[0].String() != "Shape" {
+			t.Errorf("Expected Drawable to extend Shape, got %v", bases)
+		}
+	})
+
+	t.Run("TypeAliases", func(t *testing.T) {
+		want := map[string]string{"Point": "object", "Id": "Union<string, number>", "Handler": "Function<T, Promise<void>>"}
+		for name, aliased := range want {
+			alias := findNode(t, root, ast.Type, name)
+			if alias.Attributes()["kind"] != tsparser.KindAlias || alias.Attributes()["aliased"].(*tsparser.TypeInfo).String() != aliased {
+				t.Errorf("Expected alias %s of %s, got %v", name, aliased, alias.Attributes())
+			}
+		}
+		if got := fieldTypes(findNode(t, root, ast.Type, "Point")); got["x"] != "number" || got["y"] != "number" {
+			t.Errorf("Expected Point fields x and y, got %v", got)
+		}
+		color := findNode(t, root, ast.Type, "Color")
+		if members := color.Attributes()["members"].([]string); color.Attributes()["kind"] != tsparser.KindEnum || !slices.Equal(members, []string{"Red", "Green", "Blue"}) {
+			t.Errorf("Unexpected enum %v", color.Attributes())
+		}
+	})
+
+	t.Run("Classes", func(t *testing.T) {
+		base := findNode(t, root, ast.Type, "Base")
+		attrs := base.Attributes()
+		if attrs["is_abstract"] != true {
+			t.Error("Expected Base to be abstract")
+		}
+		if implements := attrs["implements"].([]*tsparser.TypeInfo); len(implements) != 1 || implements[0].String() != "Shape" {
+			t.Errorf("Expected Base to implement Shape, got %v", implements)
+		}
+		want := map[string]string{"count": "number", "label": "string", "#secret": "number", "name": "string", "emitter": "EventEmitter"}
+		if got := fieldTypes(base); !maps.Equal(got, want) {
+			t.Errorf("Expected fields %v, got %v", want, got)
+		}
+		for _, field := range attrs["fields"].([]map[string]any) {
+			visibility := map[string]string{"label": "protected", "#secret": "private", "emitter": "private"}[field["name"].(string)]
+			if visibility == "" {
+				visibility = "public"
+			}
+			if field["visibility"] != visibility {
+				t.Errorf("Expected field %s to be %s, got %v", field["name"], visibility, field["visibility"])
+			}
+		}
+
+		square := findNode(t, root, ast.Type, "Square")
+		if bases := square.Attributes()["bases"].([]*tsparser.TypeInfo); len(bases) != 1 || bases[0].String() != "Base" {
+			t.Errorf("Expected Square to extend Base, got %v", bases)
+		}
+		if decorators := square.Attributes()["decorators"].([]string); !slices.Equal(decorators, []string{"sealed"}) {
+			t.Errorf("Unexpected decorators %v", decorators)
+		}
+		if square.Attributes()["docstring"] != "A square shape." {
+			t.Errorf("Unexpected docstring %q", square.Attributes()["docstring"])
+		}
+		if got := fieldTypes(square); got["corners"] != "Array<Point>" {
+			t.Errorf("Expected corners of type Array<Point>, got %v", got)
+		}
+	})
+
+	t.Run("Methods", func(t *testing.T) {
+		kinds := map[string]string{
+			"constructor": tsparser.MethodConstructor, "area": tsparser.MethodInstance, "draw": tsparser.MethodInstance,
+			"onClick": tsparser.MethodInstance, "unit": tsparser.MethodStatic,
+		}
+		square := findNode(t, root, ast.Type, "Square")
+		if len(square.Children()) != len(kinds) {
+			t.Errorf("Expected %d methods, got %d", len(kinds), len(square.Children()))
+		}
+		for _, method := range square.Children() {
+			name := method.Attributes()["name"].(string)
+			if method.Type() != string(ast.Method) || method.Attributes()["method_kind"] != kinds[name] {
+				t.Errorf("Expected %s to be a %s method, got %s %v", name, kinds[name], method.Type(), method.Attributes()["method_kind"])
+			}
+			if method.Attributes()["receiver_type"].(*tsparser.TypeInfo).String() != "Square" {
+				t.Errorf("Unexpected receiver of %s: %v", name, method.Attributes()["receiver_type"])
+			}
+		}
+
+		draw := findNode(t, square, ast.Method, "draw")
+		if draw.Attributes()["is_async"] != true {
+			t.Error("Expected draw to be async")
+		}
+		if got := signatureTypes(t, draw.Attributes()["signature"], "returns"); !slices.Equal(got, []string{"Promise<void>"}) {
+			t.Errorf("Unexpected draw returns %v", got)
+		}
+		if onClick := findNode(t, square, ast.Method, "onClick"); onClick.Attributes()["is_arrow"] != true {
+			t.Error("Expected onClick to be an arrow function property")
+		}
+		description := findNode(t, root, ast.Method, "description")
+		if description.Attributes()["method_kind"] != tsparser.MethodGetter {
+			t.Errorf("Expected description to be a getter, got %v", description.Attributes()["method_kind"])
+		}
+	})
+
+	t.Run("Functions", func(t *testing.T) {
+		functions := findNodes(root, ast.Function)
+		if len(functions) != 2 {
+			t.Fatalf("Expected 2 functions, got %d", len(functions))
+		}
+		area := functions[0]
+		if got := signatureTypes(t, area.Attributes()["signature"], "params"); !slices.Equal(got, []string{"Shape"}) {
+			t.Errorf("Expected the first overload of area, got params %v", got)
+		}
+		render := functions[1]
+		if render.Attributes()["name"] != "render" || render.Attributes()["is_arrow"] != true || render.Attributes()["is_async"] != true {
+			t.Errorf("Expected async arrow function render, got %v", render.Attributes())
+		}
+		if got := signatureTypes(t, render.Attributes()["signature"], "params"); !slices.Equal(got, []string{"Array<Shape>", "Canvas"}) {
+			t.Errorf("Unexpected render params %v", got)
+		}
+	})
+
+	t.Run("Variables", func(t *testing.T) {
+		want := map[string]string{
+			"DEFAULT_SIDE": "number", "counter": "number", "label": "string", "registry": "Map<string, Shape>",
+			"pattern": "RegExp", "a": "?", "renamed": "?",
+		}
+		variables := findNodes(root, ast.Variable)
+		if len(variables) != len(want) {
+			t.Errorf("Expected %d variables, got %d", len(want), len(variables))
+		}
+		for _, v := range variables {
+			name := v.Attributes()["name"].(string)
+			if got := v.Attributes()["type"].(*tsparser.TypeInfo).String(); got != want[name] {
+				t.Errorf("Expected %s to have type %s, got %s", name, want[name], got)
+			}
+		}
+		if counter := findNode(t, root, ast.Variable, "counter"); counter.Attributes()["is_exported"] != true || counter.Attributes()["is_constant"] != false {
+			t.Errorf("Expected counter to be an exported let, got %v", counter.Attributes())
+		}
+		if pattern := findNode(t, root, ast.Variable, "pattern"); pattern.Attributes()["is_exported"] != false {
+			t.Error("Expected pattern to be unexported")
+		}
+	})
+}
+
+func TestParseDir(t *testing.T) {
+	nodes, err := tsparser.New().ParseDir("testdata")
+	if err != nil {
+		t.Fatalf("Failed to parse dir: %v", err)
+	}
+
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Attributes()["package_name"].(string))
+	}
+	if !slices.Equal(names, []string{"app", "legacy", "shapes"}) {
+		t.Fatalf("Expected modules app, legacy, shapes, got %v", names)
+	}
+
+	t.Run("JSX", func(t *testing.T) {
+		app := nodes[0]
+		list := findNode(t, app, ast.Function, "ShapeList")
+		if got := signatureTypes(t, list.Attributes()["signature"], "params"); !slices.Equal(got, []string{"Props"}) {
+			t.Errorf("Unexpected ShapeList params %v", got)
+		}
+		identity := findNode(t, app, ast.Function, "identity")
+		if params := identity.Attributes()["type_params"].([]string); !slices.Equal(params, []string{"T"}) {
+			t.Errorf("Expected type parameter T, got %v", params)
+		}
+		gallery := findNode(t, app, ast.Type, "Gallery")
+		if gallery.Attributes()["is_default_export"] != true {
+			t.Error("Expected Gallery to be the default export")
+		}
+		if bases := gallery.Attributes()["bases"].([]*tsparser.TypeInfo); len(bases) != 1 || bases[0].String() != "React.Component<Props>" {
+			t.Errorf("Unexpected Gallery bases %v", bases)
+		}
+	})
+
+	t.Run("CommonJS", func(t *testing.T) {
+		legacy := nodes[1]
+		imports := findNodes(legacy, ast.Import)
+		if len(imports) != 2 || imports[0].Attributes()["alias"] != "fs" {
+			t.Fatalf("Expected require of fs, got %v", imports)
+		}
+		path := imports[1].Attributes()
+		if names, aliases := path["names"].([]string), path["aliases"].([]string); !slices.Equal(names, []string{"join", "resolve"}) ||
+			!slices.Equal(aliases, []string{"", "resolvePath"}) || path["is_std_lib"] != true {
+			t.Errorf("Unexpected node:path import %v", path)
+		}
+		for _, name := range []string{"load", "Cache"} {
+			for _, node := range legacy.Children() {
+				if node.Attributes()["name"] == name && node.Attributes()["is_exported"] != true {
+					t.Errorf("Expected %s to be exported through module.exports", name)
+				}
+			}
+		}
+		if exports := legacy.Attributes()["exports"].([]string); !slices.Equal(exports, []string{"load", "Cache", "version"}) {
+			t.Errorf("Unexpected exports %v", exports)
+		}
+	})
+}
+
+func TestDecorators(t *testing.T) {
+	root, err := tsparser.New().ParseFile(filepath.Join("testdata", "decorators", "browser.ts"))
+	if err != nil {
+		t.Fatalf("Failed to parse file: %v", err)
+	}
+
+	// Decorator factories may take type arguments
+	expected := map[string][]string{
+		"close":   {`throwIfDisposed<Browser>(browser=>{return browser.#disposed?"Browser already closed":undefined;})`},
+		"session": {"inspect<Browser, Map<string, Session>>(browser=>browser.sessions)", "memoize"},
+		"open":    {"throwIfDisposed(browser=>browser.sessions.size<10)"},
+	}
+	browser := findNode(t, root, ast.Type, "Browser")
+	if len(browser.Children()) != len(expected) {
+		t.Errorf("Expected %d methods, got %d", len(expected), len(browser.Children()))
+	}
+	for name, want := range expected {
+		method := findNode(t, browser, ast.Method, name)
+		if got := method.Attributes()["decorators"].([]string); !slices.Equal(got, want) {
+			t.Errorf("Expected %s decorators %q, got %q", name, want, got)
+		}
+	}
+	findNode(t, root, ast.Interface, "Session")
+}
+
+func TestRegistry(t *testing.T) {
+	registry := parser.NewRegistry()
+	registry.Register(tsparser.New())
+	for _, ext := range []string{".ts", ".tsx", ".js"} {
+		if p, ok := registry.GetByExtension(ext); !ok || p.Language() != "TypeScript" {
+			t.Errorf("Expected the TypeScript parser for %s", ext)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  string
+		src  string
+		want string
+	}{
+		{"UnclosedBracket", ".ts", "const x = (1,\n", "'(' was never closed"},
+		{"UnmatchedBracket", ".ts", "const x = 1)\n", "unmatched ')'"},
+		{"UnterminatedString", ".ts", "const x = 'abc\n", "unterminated string literal"},
+		{"UnterminatedTemplate", ".ts", "const x = `abc ${y}\n", "unterminated template literal"},
+		{"UnterminatedComment", ".ts", "/* abc\n", "unterminated comment"},
+		{"UnterminatedJSX", ".tsx", "const x = <div>\n", "unterminated JSX element"},
+		{"MissingFrom", ".ts", "import { a } './a'\n", "expected 'from'"},
+		{"MissingClassName", ".ts", "class {}\n", "expected a class name"},
+		{"MissingClassBody", ".ts", "class A extends B;\n", "expected '{'"},
+		{"MissingParameters", ".ts", "function f;\n", "expected '('"},
+		{"UnclosedTypeParameters", ".ts", "class A<T {}\n", "'<' was never closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSource(t, tt.ext, tt.src)
+			if err == nil {
+				t.Fatal("Expected syntax error")
+			}
+			var syntaxErr *tsparser.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Expected SyntaxError, got %T", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %q", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("Comparisons", func(t *testing.T) {
+		// In .ts files < is never JSX, and a / after a value is a division
+		if _, err := parseSource(t, ".ts", "const a = b < c, d = e > f;\nconst g = h / i / j;\n"); err != nil {
+			t.Errorf("Failed to parse comparisons: %v", err)
+		}
+	})
+
+	t.Run("UnclosedTypeArguments", func(t *testing.T) {
+		// Files being edited leave type argument lists open, which leaves the type unknown
+		for _, src := range []string{"let x: Foo<", "type A=A<", "const z = new Foo<string"} {
+			root, err := parseSource(t, ".ts", src)
+			if err != nil {
+				continue
+			}
+			for _, v := range findNodes(root, ast.Variable) {
+				if typ := v.Attributes()["type"].(*tsparser.TypeInfo).String(); typ != "?" {
+					t.Errorf("Expected unknown type for %q, got %s", src, typ)
+				}
+			}
+		}
+	})
+
+	t.Run("Assertions", func(t *testing.T) {
+		src := "const a = y as Array<Map<string, number>>, b = 1;\nconst c = z satisfies Set<Array<Array<string>>>;\n"
+		root, err := parseSource(t, ".ts", src)
+		if err != nil {
+			t.Fatalf("Failed to parse assertions: %v", err)
+		}
+		for name, want := range map[string]string{"a": "Array<Map<string, number>>", "b": "number", "c": "?"} {
+			if got := findNode(t, root, ast.Variable, name).Attributes()["type"].(*tsparser.TypeInfo).String(); got != want {
+				t.Errorf("Expected %s to have type %s, got %s", name, want, got)
+			}
+		}
+	})
+
+	t.Run("StaticBlocks", func(t *testing.T) {
+		src := "class A {\n  static count = 0;\n  static {\n    A.count = 1;\n  }\n  get(): number { return A.count; }\n}\n"
+		root, err := parseSource(t, ".ts", src)
+		if err != nil {
+			t.Fatalf("Failed to parse static block: %v", err)
+		}
+		var names []string
+		for _, method := range findNodes(root, ast.Method) {
+			names = append(names, method.Attributes()["name"].(string))
+		}
+		if !slices.Equal(names, []string{"get"}) {
+			t.Errorf("Expected only method get, got %v", names)
+		}
+	})
+}
+
+func FuzzParseFile(f *testing.F) {
+	for _, src := range []string{
+		"const x = y as Array<Map<string, number>>;\n",
+		"let x: Foo<",
+		"type A=A<",
+		"class A<T extends B<C>> extends D<T> implements E<F, G> {}\n",
+		"export function f<T,>(a: T[], b: { c: [T, ...U[]] }): Promise<T> | null { return g<T>(a); }\n",
+	} {
+		f.Add(src)
+	}
+	for _, name := range []string{"shapes.ts", "app.tsx", "legacy.js"} {
+		src, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			f.Fatalf("Failed to read %s: %v", name, err)
+		}
+		f.Add(string(src))
+	}
+
+	dir := f.TempDir()
+	f.Fuzz(func(t *testing.T, src string) {
+		// Any source either parses or fails with a syntax error, without panicking
+		for _, ext := range []string{".ts", ".tsx"} {
+			path := filepath.Join(dir, "module"+ext)
+			if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+				t.Fatalf("Failed to write source: %v", err)
+			}
+			_, err := tsparser.New().ParseFile(path)
+			var syntaxErr *tsparser.SyntaxError
+			if err != nil && !errors.As(err, &syntaxErr) {
+				t.Errorf("Expected SyntaxError, got %T: %v", err, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,29 @@
+import React from "react";
+import { Square, Shape } from "./shapes";
+
+interface Props {
+  shapes: Shape[];
+  onSelect: (shape: Shape) => void;
+}
+
+export const identity = <T,>(value: T): T => value;
+
+export function ShapeList({ shapes, onSelect }: Props) {
+  const ratio = 4 / 2;
+  return (
+    <ul className="shapes">
+      {shapes.map((shape) => (
+        <li key={shape.name} onClick={() => onSelect(shape)}>
+          {shape.name} isn't {ratio > 1 ? <b>big</b> : "small"}
+        </li>
+      ))}
+      <></>
+    </ul>
+  );
+}
+
+export default class Gallery extends React.Component<Props> {
+  render() {
+    return <ShapeList {...this.props} />;
+  }
+}
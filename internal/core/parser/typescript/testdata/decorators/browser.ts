@@ -0,0 +1,30 @@
+/**
+ * Decorators with type arguments, as used by puppeteer-core's BiDi browser.
+ */
+import { throwIfDisposed, inspect, memoize } from "./decorators";
+
+export class Browser {
+  #disposed = false;
+  sessions = new Map<string, Session>();
+
+  @throwIfDisposed<Browser>(browser => {
+    return browser.#disposed ? "Browser already closed" : undefined;
+  })
+  async close(): Promise<void> {
+    this.#disposed = true;
+  }
+
+  @inspect<Browser, Map<string, Session>>(browser => browser.sessions)
+  @memoize
+  session(id: string): Session | undefined {
+    return this.sessions.get(id);
+  }
+
+  // A comparison in a decorator argument is not a type argument list
+  @throwIfDisposed(browser => browser.sessions.size < 10)
+  open(): void {}
+}
+
+export interface Session {
+  id: string;
+}
@@ -0,0 +1,16 @@
+"use strict";
+const fs = require("fs");
+const { join, resolve: resolvePath } = require("node:path");
+
+function load(file) {
+  return fs.readFileSync(join(file), "utf8");
+}
+
+class Cache {
+  constructor() {
+    this.items = {};
+  }
+}
+
+module.exports = { load, Cache };
+exports.version = "1.0";
@@ -0,0 +1,99 @@
+/**
+ * @file Shape models shared by the frontend.
+ */
+import { EventEmitter } from "events";
+import type { Canvas } from "./canvas";
+import * as util from "./util";
+import React, { useState as useLocalState } from "react";
+
+/** Something with an area. */
+export interface Shape {
+  readonly name: string;
+  area(): number;
+  scale?(factor: number, origin: Point): Shape;
+}
+
+export interface Drawable extends Shape {
+  draw(canvas: Canvas): void
+}
+
+export type Point = { x: number; y: number };
+export type Id = string | number;
+type Handler<T> = (event: T) => Promise<void>;
+
+export enum Color { Red, Green = "green", Blue }
+
+export abstract class Base implements Shape {
+  static count = 0;
+  protected readonly label: string = "base";
+  #secret = 1;
+
+  constructor(public name: string, private readonly emitter: EventEmitter) {}
+
+  abstract area(): number;
+
+  get description(): string {
+    return `${this.name}: ${this.area()}`;
+  }
+}
+
+/**
+ * A square shape.
+ */
+@sealed
+export class Square extends Base implements Drawable {
+  private side: number;
+  corners: Point[] = [];
+
+  constructor(side: number) {
+    super("square", new EventEmitter());
+    this.side = side;
+  }
+
+  area(): number {
+    return this.side ** 2;
+  }
+
+  async draw(canvas: Canvas): Promise<void> {
+    canvas.rect(0, 0, this.side, this.side);
+  }
+
+  onClick = (event: MouseEvent): void => {
+    console.log(event);
+  };
+
+  static unit(): Square;
+  static unit(side?: number): Square {
+    return new Square(side ?? 1);
+  }
+}
+
+export function area(shape: Shape): number;
+export function area(shape: Shape, scale = 1): number {
+  return shape.area() * scale;
+}
+
+export const DEFAULT_SIDE = 10;
+let counter = 0, label = "x";
+export const registry = new Map<string, Shape>();
+const pattern = /^[a-z]+\/\d+$/i;
+export const render = async (shapes: Shape[], canvas?: Canvas) => {
+  for (const shape of shapes) {
+    if (counter / 2 > 1) {
+      console.log(shape);
+    }
+  }
+};
+const { a, b: renamed } = util.defaults;
+
+if (process.env.DEBUG) {
+  console.log("debug");
+}
+else {
+  console.log("release");
+}
+
+export { counter as count, label };
+export * from "./colors";
+export { Palette as Colors } from "./palette";
+export default Square;
@@ -0,0 +1,396 @@
+package tsparser
+
+import "strings"
+
+// TypeInfo represents a type annotation in a structural way
+type TypeInfo struct {
+	Name string      // The name of the type (e.g. "string", "Array", "React.FC", "Shape"); empty if unknown
+	Args []*TypeInfo // Type arguments (e.g. [string, number] for Map<string, number>)
+}
+
+// Names used for composite types
+const (
+	unionName        = "Union"
+	intersectionName = "Intersection"
+	arrayName        = "Array"
+	tupleName        = "Tuple"
+	functionName     = "Function"
+	objectName       = "object"
+)
+
+// Returns the canonical form of the type (e.g. "Map<string, Array<number>>")
+func (t *TypeInfo) String() string {
+	var b strings.Builder
+	t.writeTo(&b)
+	return b.String()
+}
+
+// Writes the canonical form of the type to the builder
+func (t *TypeInfo) writeTo(b *strings.Builder) {
+	if t == nil || t.Name == "" {
+		b.WriteString("?")
+		return
+	}
+	b.WriteString(t.Name)
+	if len(t.Args) == 0 {
+		return
+	}
+	b.WriteString("<")
+	for i, arg := range t.Args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		arg.writeTo(b)
+	}
+	b.WriteString(">")
+}
+
+// Checks if the type is known
+func (t *TypeInfo) isKnown() bool {
+	return t != nil && t.Name != ""
+}
+
+// Returns the unqualified name of the type (e.g. "Component" for "React.Component")
+func (t *TypeInfo) BaseName() string {
+	if t == nil {
+		return ""
+	}
+	return t.Name[strings.LastIndex(t.Name, ".")+1:]
+}
+
+// Returns the names of the type and all its arguments, outermost first
+func (t *TypeInfo) Names() []string {
+	if !t.isKnown() {
+		return nil
+	}
+	names := []string{t.Name}
+	for _, arg := range t.Args {
+		names = append(names, arg.Names()...)
+	}
+	return names
+}
+
+// Parses the type expression at tokens[i], returning it with the index just past it
+func parseType(tokens []token, i int) (*TypeInfo, int) {
+	p := &typeParser{tokens: tokens, i: i}
+	return p.conditional(), p.i
+}
+
+// Parses the tokens of a type expression
+type typeParser struct {
+	tokens []token
+	i      int
+}
+
+// Checks if the current token is the given operator or keyword
+func (p *typeParser) at(text string) bool {
+	return p.i < len(p.tokens) && p.tokens[p.i].is(text)
+}
+
+// Parses a conditional type (T extends U ? X : Y), which is unknown unless both branches agree
+func (p *typeParser) conditional() *TypeInfo {
+	t := p.union()
+	if !p.at("extends") || p.tokens[p.i].newline {
+		return t
+	}
+	p.i++
+	p.union()
+	if !p.at("?") {
+		return &TypeInfo{}
+	}
+	p.i++
+	yes := p.conditional()
+	if !p.at(":") {
+		return &TypeInfo{}
+	}
+	p.i++
+	no := p.conditional()
+	if yes.String() == no.String() {
+		return yes
+	}
+	return &TypeInfo{}
+}
+
+// Parses alternatives separated by |
+func (p *typeParser) union() *TypeInfo {
+	return p.composite("|", unionName, p.intersection)
+}
+
+// Parses members separated by &
+func (p *typeParser) intersection() *TypeInfo {
+	return p.composite("&", intersectionName, p.postfix)
+}
+
+// Parses operands separated by an operator into a composite type
+func (p *typeParser) composite(op, name string, operand func() *TypeInfo) *TypeInfo {
+	if p.at(op) {
+		p.i++ // Leading separator (e.g. type T = | A | B)
+	}
+	t := operand()
+	if !p.at(op) {
+		return t
+	}
+	result := &TypeInfo{Name: name, Args: []*TypeInfo{t}}
+	for p.at(op) {
+		p.i++
+		result.Args = append(result.Args, operand())
+	}
+	return result
+}
+
+// Parses a primary type followed by array or indexed access brackets
+func (p *typeParser) postfix() *TypeInfo {
+	t := p.primary()
+	for p.at("[") && !p.tokens[p.i].newline {
+		if p.i+1 < len(p.tokens) && p.tokens[p.i+1].is("]") {
+			p.i += 2
+			t = &TypeInfo{Name: arrayName, Args: []*TypeInfo{t}}
+			continue
+		}
+		p.i = matchingBracket(p.tokens, p.i) + 1
+		t = &TypeInfo{} // Indexed access
+	}
+	return t
+}
+
+// Parses a named, literal, object, tuple, function or operator type
+func (p *typeParser) primary() *TypeInfo {
+	if p.i >= len(p.tokens) {
+		return &TypeInfo{}
+	}
+	tok := p.tokens[p.i]
+	switch {
+	case tok.is("("):
+		end := matchingBracket(p.tokens, p.i)
+		if end+1 < len(p.tokens) && p.tokens[end+1].is("=>") {
+			return p.function()
+		}
+		p.i++
+		t := p.conditional()
+		p.i = end + 1
+		return t
+
+	case tok.is("<"):
+		end := matchingAngle(p.tokens, p.i)
+		if end < 0 {
+			p.i = len(p.tokens)
+			return &TypeInfo{}
+		}
+		p.i = end + 1 // Generic function type
+		return p.function()
+
+	case tok.is("new"), tok.is("abstract"):
+		p.i++
+		return p.primary()
+
+	case tok.is("{"):
+		p.i = matchingBracket(p.tokens, p.i) + 1
+		return &TypeInfo{Name: objectName}
+
+	case tok.is("["):
+		end := matchingBracket(p.tokens, p.i)
+		tuple := &TypeInfo{Name: tupleName, Args: make([]*TypeInfo, 0)}
+		for _, element := range splitTopLevel(p.tokens[p.i+1:end], ",") {
+			if len(element) == 0 {
+				continue
+			}
+			if element[0].is("...") {
+				element = element[1:]
+			}
+			if colon := indexTopLevel(element, ":", 0); colon > 0 {
+				element = element[colon+1:] // Labeled element
+			}
+			t, _ := parseType(element, 0)
+			tuple.Args = append(tuple.Args, t)
+		}
+		p.i = end + 1
+		return tuple
+
+	case tok.kind == tokString, tok.kind == tokNumber, tok.kind == tokTemplate:
+		p.i++
+		return &TypeInfo{Name: tok.text}
+
+	case tok.is("-") && p.i+1 < len(p.tokens) && p.tokens[p.i+1].kind == tokNumber:
+		p.i += 2
+		return &TypeInfo{Name: "-" + p.tokens[p.i-1].text}
+
+	case tok.is("keyof"):
+		p.i++
+		return &TypeInfo{Name: "keyof", Args: []*TypeInfo{p.postfix()}}
+
+	case tok.is("readonly"), tok.is("unique"), tok.is("infer"), tok.is("asserts"):
+		p.i++
+		return p.postfix()
+
+	case tok.is("typeof"):
+		p.i++
+		_, n := dottedName(p.tokens[p.i:])
+		p.i += n
+		return &TypeInfo{}
+
+	case tok.is("import") && p.i+1 < len(p.tokens) && p.tokens[p.i+1].is("("):
+		p.i = matchingBracket(p.tokens, p.i+1) + 1
+		for p.at(".") && p.i+1 < len(p.tokens) {
+			p.i += 2
+		}
+		p.typeArguments()
+		return &TypeInfo{}
+
+	case tok.kind == tokName:
+		name, n := dottedName(p.tokens[p.i:])
+		p.i += n
+		if p.at("is") && !p.tokens[p.i].newline {
+			p.i++
+			p.postfix()
+			return &TypeInfo{Name: "boolean"} // Type predicate
+		}
+		args, ok := p.typeArguments()
+		if !ok {
+			return &TypeInfo{}
+		}
+		return &TypeInfo{Name: name, Args: args}
+	}
+	p.i = len(p.tokens)
+	return &TypeInfo{}
+}
+
+// Parses a function type ((a: A) => R) into a Function type whose arguments are the
+// parameter types followed by the return type
+func (p *typeParser) function() *TypeInfo {
+	if !p.at("(") {
+		return &TypeInfo{}
+	}
+	end := matchingBracket(p.tokens, p.i)
+	params := parameters(p.tokens[p.i+1 : end])
+	p.i = end + 1
+	if !p.at("=>") {
+		return &TypeInfo{}
+	}
+	p.i++
+	returns := p.conditional()
+	args := make([]*TypeInfo, 0, len(params)+1)
+	for _, param := range params {
+		args = append(args, param.typ)
+	}
+	return &TypeInfo{Name: functionName, Args: append(args, returns)}
+}
+
+// Parses type arguments (<A, B>) if present. Reports false for an unclosed list (e.g. in a file
+// being edited), which consumes the remaining tokens.
+func (p *typeParser) typeArguments() ([]*TypeInfo, bool) {
+	if !p.at("<") {
+		return nil, true
+	}
+	end := matchingAngle(p.tokens, p.i)
+	if end < 0 {
+		p.i = len(p.tokens)
+		return nil, false
+	}
+	args := make([]*TypeInfo, 0)
+	for _, arg := range splitTopLevel(p.tokens[p.i+1:end], ",") {
+		if len(arg) > 0 {
+			t, _ := parseType(arg, 0)
+			args = append(args, t)
+		}
+	}
+	p.i = end + 1
+	return args, true
+}
+
+// Infers the type of an initializer expression from its literal form
+func inferType(tokens []token) *TypeInfo {
+	if len(tokens) == 0 {
+		return &TypeInfo{}
+	}
+	// An assertion states the type (x as Foo), except for const assertions
+	if as := lastTopLevel(tokens, "as"); as > 0 && as+1 < len(tokens) && !tokens[as+1].is("const") {
+		t, end := parseType(tokens, as+1)
+		if end == len(tokens) {
+			return t
+		}
+		return &TypeInfo{}
+	}
+	if satisfies := lastTopLevel(tokens, "satisfies"); satisfies > 0 {
+		tokens = tokens[:satisfies]
+	}
+
+	first := tokens[0]
+	single := len(tokens) == 1
+	switch {
+	case single && (first.kind == tokString || first.kind == tokTemplate):
+		return &TypeInfo{Name: "string"}
+	case single && first.kind == tokNumber:
+		if strings.HasSuffix(first.text, "n") && !strings.HasPrefix(strings.ToLower(first.text), "0x") {
+			return &TypeInfo{Name: "bigint"}
+		}
+		return &TypeInfo{Name: "number"}
+	case single && (first.is("true") || first.is("false")):
+		return &TypeInfo{Name: "boolean"}
+	case single && (first.is("null") || first.is("undefined")):
+		return &TypeInfo{Name: first.text}
+	case single && first.kind == tokRegExp:
+		return &TypeInfo{Name: "RegExp"}
+	case first.is("[") && closes(tokens, 0):
+		return &TypeInfo{Name: arrayName}
+	case first.is("{") && closes(tokens, 0):
+		return &TypeInfo{Name: objectName}
+	case first.is("(") && closes(tokens, 0):
+		return inferType(tokens[1 : len(tokens)-1]) // Parenthesized expression
+	case first.is("new") && len(tokens) > 1:
+		name, n := dottedName(tokens[1:])
+		if name == "" {
+			return &TypeInfo{}
+		}
+		t := &TypeInfo{Name: name}
+		rest := tokens[1+n:]
+		if len(rest) > 0 && rest[0].is("<") {
+			p := &typeParser{tokens: rest}
+			args, ok := p.typeArguments()
+			if !ok {
+				return &TypeInfo{}
+			}
+			t.Args = args
+			rest = rest[p.i:]
+		}
+		if len(rest) == 0 || (rest[0].is("(") && closes(rest, 0)) {
+			return t
+		}
+	}
+	return &TypeInfo{}
+}
+
+// Returns the index of the last name or operator outside brackets, or -1
+func lastTopLevel(tokens []token, text string) int {
+	last := -1
+	depth := 0
+	for i, tok := range tokens {
+		switch {
+		case tok.kind == tokOp && (tok.text == "(" || tok.text == "[" || tok.text == "{"):
+			depth++
+		case tok.kind == tokOp && (tok.text == ")" || tok.text == "]" || tok.text == "}"):
+			depth--
+		case depth == 0 && tok.is(text):
+			last = i
+		}
+	}
+	return last
+}
+
+// Checks if the bracket at index start is closed by the last token
+func closes(tokens []token, start int) bool {
+	return matchingBracket(tokens, start) == len(tokens)-1
+}
+
+// Returns a dotted name at the start of the tokens and the number of tokens it spans
+func dottedName(tokens []token) (string, int) {
+	if len(tokens) == 0 || tokens[0].kind != tokName {
+		return "", 0
+	}
+	name := tokens[0].text
+	n := 1
+	for n+1 < len(tokens) && tokens[n].is(".") && tokens[n+1].kind == tokName {
+		name += "." + tokens[n+1].text
+		n += 2
+	}
+	return name, n
+}
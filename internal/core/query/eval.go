@@ -0,0 +1,238 @@
+package query
+
+import (
+	"context"
+	"maps"
+	"regexp"
+	"strings"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+)
+
+// Variables bound to elements while matching
+type binding map[string]*gostructure.Element
+
+// An adjacency entry of the structure graph
+type edge struct {
+	relType gostructure.RelationType
+	target  *gostructure.Element
+}
+
+// Number of matching steps between checks of the evaluation context
+const checkInterval = 1024
+
+// Matches patterns against a structure
+type evaluator struct {
+	ctx       context.Context
+	structure *gostructure.Structure
+	out       map[*gostructure.Element][]edge
+	in        map[*gostructure.Element][]edge
+	steps     int
+	err       error // Error of the context, once it is done
+}
+
+// Builds the adjacency lists of a structure
+func newEvaluator(ctx context.Context, structure *gostructure.Structure) *evaluator {
+	e := &evaluator{
+		ctx:       ctx,
+		structure: structure,
+		out:       make(map[*gostructure.Element][]edge),
+		in:        make(map[*gostructure.Element][]edge),
+	}
+	for _, rel := range structure.Relationships {
+		e.out[rel.Source] = append(e.out[rel.Source], edge{rel.Type, rel.Target})
+		e.in[rel.Target] = append(e.in[rel.Target], edge{rel.Type, rel.Source})
+	}
+	return e
+}
+
+// Counts a matching step, checking every so often whether the context is done
+func (e *evaluator) done() bool {
+	if e.err == nil {
+		if e.steps++; e.steps%checkInterval == 0 {
+			e.err = e.ctx.Err()
+		}
+	}
+	return e.err != nil
+}
+
+// Matches the paths in order, calling emit for every complete binding.
+// Returns false once emit asks to stop or the context is done.
+func (e *evaluator) match(paths []*pathPattern, b binding, emit func(binding) bool) bool {
+	if len(paths) == 0 {
+		return emit(b)
+	}
+	return e.matchNode(paths[0], 0, b, func(b binding) bool {
+		return e.match(paths[1:], b, emit)
+	})
+}
+
+// Matches node i of a path and the rest of the path after it
+func (e *evaluator) matchNode(path *pathPattern, i int, b binding, cont func(binding) bool) bool {
+	node := path.nodes[i]
+
+	var candidates []*gostructure.Element
+	switch {
+	case i > 0:
+		candidates = e.neighbors(b[path.nodes[i-1].variable], path.rels[i-1])
+	case b[node.variable] != nil:
+		candidates = []*gostructure.Element{b[node.variable]}
+	default:
+		candidates = e.structure.Elements
+	}
+
+	for _, candidate := range candidates {
+		if e.done() {
+			return false
+		}
+		if !node.matches(candidate) {
+			continue
+		}
+
+		next := b
+		if bound, ok := b[node.variable]; ok {
+			if bound != candidate {
+				continue
+			}
+		} else {
+			next = maps.Clone(b)
+			next[node.variable] = candidate
+		}
+
+		if i == len(path.nodes)-1 {
+			if !cont(next) {
+				return false
+			}
+		} else if !e.matchNode(path, i+1, next, cont) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the elements reachable from an element through a relationship pattern
+func (e *evaluator) neighbors(elem *gostructure.Element, rel *relPattern) []*gostructure.Element {
+	step := func(from *gostructure.Element) []*gostructure.Element {
+		var result []*gostructure.Element
+		if rel.dir != directionIn {
+			for _, edge := range e.out[from] {
+				if rel.relType == "" || edge.relType == rel.relType {
+					result = append(result, edge.target)
+				}
+			}
+		}
+		if rel.dir != directionOut {
+			for _, edge := range e.in[from] {
+				if rel.relType == "" || edge.relType == rel.relType {
+					result = append(result, edge.target)
+				}
+			}
+		}
+		return result
+	}
+
+	if !rel.transitive {
+		return step(elem)
+	}
+
+	// Breadth-first search over one or more hops
+	var result []*gostructure.Element
+	visited := make(map[*gostructure.Element]bool)
+	queue := step(elem)
+	for len(queue) > 0 && !e.done() {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		result = append(result, current)
+		queue = append(queue, step(current)...)
+	}
+	return result
+}
+
+// Checks if an element matches a node's type and properties
+func (n *nodePattern) matches(elem *gostructure.Element) bool {
+	if n.elemType != "" && elem.Type != n.elemType {
+		return false
+	}
+	for key, value := range n.props {
+		if !equal(attribute(elem, key), value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *andCondition) eval(b binding) bool { return c.left.eval(b) && c.right.eval(b) }
+func (c *orCondition) eval(b binding) bool  { return c.left.eval(b) || c.right.eval(b) }
+func (c *notCondition) eval(b binding) bool { return !c.inner.eval(b) }
+
+func (c *comparison) eval(b binding) bool {
+	left, right := c.left.resolve(b), c.right.resolve(b)
+	switch c.op {
+	case "=":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "=~":
+		pattern := c.pattern
+		if pattern == nil {
+			var err error
+			if pattern, err = regexp.Compile(FormatCell(right)); err != nil {
+				return false
+			}
+		}
+		return pattern.MatchString(text(left))
+	}
+
+	cmp, ok := compare(left, right)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// Checks if two values are equal; elements compare by identity and other values by text
+func equal(a, b any) bool {
+	if ea, ok := a.(*gostructure.Element); ok {
+		eb, ok := b.(*gostructure.Element)
+		return ok && ea == eb
+	}
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return text(a) == text(b)
+}
+
+// Orders two values: numerically for numbers, by text otherwise
+func compare(a, b any) (int, bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+	na, aok := a.(int)
+	nb, bok := b.(int)
+	if aok && bok {
+		return na - nb, true
+	}
+	return strings.Compare(text(a), text(b)), true
+}
+
+// Returns the text of a value used for comparisons (element values compare by name)
+func text(value any) string {
+	if elem, ok := value.(*gostructure.Element); ok {
+		return elem.Name
+	}
+	return FormatCell(value)
+}
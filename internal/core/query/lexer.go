@@ -0,0 +1,88 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// The kind of a lexical token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+// A lexical token of a query
+type token struct {
+	kind  tokenKind
+	text  string
+	value string // Unquoted value for strings
+	pos   int    // Byte offset in the query
+}
+
+// Multi-character punctuation, checked before single characters
+var multiPunct = []string{"=~", "!=", "<=", ">="}
+
+// Splits a query into tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '"' || c == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(src) && rune(src[j]) != c; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: src[i : j+1], value: b.String(), pos: i})
+			i = j + 1
+
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(src) && unicode.IsDigit(rune(src[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[i:j], pos: i})
+			i = j
+
+		case c == '_' || unicode.IsLetter(c):
+			j := i
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[i:j], pos: i})
+			i = j
+
+		default:
+			text := string(c)
+			for _, p := range multiPunct {
+				if strings.HasPrefix(src[i:], p) {
+					text = p
+					break
+				}
+			}
+			if !strings.Contains("()[]{}:,.-<>=!*", string(c)) {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenPunct, text: text, pos: i})
+			i += len(text)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
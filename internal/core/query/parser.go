@@ -0,0 +1,480 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+)
+
+// The direction of a relationship in a pattern
+type direction int
+
+const (
+	directionOut  direction = iota // (a)-[]->(b)
+	directionIn                    // (a)<-[]-(b)
+	directionBoth                  // (a)-[]-(b)
+)
+
+// A node in a pattern, e.g. (t:type {name: "Analyzer"})
+type nodePattern struct {
+	variable string
+	elemType gostructure.ElementType // Empty matches every element type
+	props    map[string]any
+}
+
+// A relationship step in a pattern, e.g. -[:embeds*]->
+type relPattern struct {
+	relType    gostructure.RelationType // Empty matches every relationship type
+	dir        direction
+	transitive bool // Follows one or more hops
+}
+
+// A chain of nodes connected by relationships
+type pathPattern struct {
+	nodes []*nodePattern
+	rels  []*relPattern // rels[i] connects nodes[i] and nodes[i+1]
+}
+
+// A value in a condition: a literal or an attribute of a bound variable
+type operand struct {
+	variable  string // Set for variable references
+	attribute string // Set for attribute references (variable.attribute)
+	literal   any
+}
+
+// A boolean condition in the where clause
+type condition interface {
+	eval(b binding) bool
+}
+
+type andCondition struct{ left, right condition }
+type orCondition struct{ left, right condition }
+type notCondition struct{ inner condition }
+
+type comparison struct {
+	left, right operand
+	op          string
+	pattern     *regexp.Regexp // Compiled pattern for =~ with a literal right side
+}
+
+// A returned column
+type returnItem struct {
+	operand
+	name string
+}
+
+// Parses a query
+type parser struct {
+	tokens []token
+	pos    int
+	anon   int // Counter for anonymous variables
+}
+
+// Returns the current token
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// Consumes and returns the current token
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// Checks if the current token is the given punctuation or (case-insensitive) keyword
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	if t.kind == tokenPunct {
+		return t.text == text
+	}
+	return t.kind == tokenIdent && strings.EqualFold(t.text, text)
+}
+
+// Consumes the current token if it is the given punctuation or keyword
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// Consumes the given punctuation or keyword, failing otherwise
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorf("expected %q", text)
+	}
+	return nil
+}
+
+// Returns an error located at the current token
+func (p *parser) errorf(format string, args ...any) error {
+	t := p.peek()
+	found := t.text
+	if t.kind == tokenEOF {
+		found = "end of query"
+	}
+	return fmt.Errorf("%s at offset %d (found %s)", fmt.Sprintf(format, args...), t.pos, found)
+}
+
+// Parses an identifier
+func (p *parser) ident() (string, error) {
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return "", p.errorf("expected identifier")
+	}
+	p.next()
+	return t.text, nil
+}
+
+// Parses: MATCH path (, path)* [WHERE condition] RETURN item (, item)* [LIMIT n]
+func (p *parser) parseQuery() (*Query, error) {
+	q := &Query{}
+	if err := p.expect("match"); err != nil {
+		return nil, err
+	}
+	for {
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		q.paths = append(q.paths, path)
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if p.accept("where") {
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		q.where = cond
+	}
+
+	if err := p.expect("return"); err != nil {
+		return nil, err
+	}
+	for {
+		item, err := p.parseReturnItem()
+		if err != nil {
+			return nil, err
+		}
+		q.returns = append(q.returns, item)
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if p.accept("limit") {
+		t := p.next()
+		if t.kind != tokenNumber {
+			return nil, p.errorf("expected number after LIMIT")
+		}
+		q.limit, _ = strconv.Atoi(t.text)
+	}
+
+	if p.peek().kind != tokenEOF {
+		return nil, p.errorf("unexpected token")
+	}
+	return q, q.checkVariables()
+}
+
+// Parses: node (rel node)*
+func (p *parser) parsePath() (*pathPattern, error) {
+	path := &pathPattern{}
+	node, err := p.parseNode()
+	if err != nil {
+		return nil, err
+	}
+	path.nodes = append(path.nodes, node)
+
+	for p.is("-") || p.is("<") {
+		rel, err := p.parseRel()
+		if err != nil {
+			return nil, err
+		}
+		node, err := p.parseNode()
+		if err != nil {
+			return nil, err
+		}
+		path.rels = append(path.rels, rel)
+		path.nodes = append(path.nodes, node)
+	}
+	return path, nil
+}
+
+// Parses: ( [variable] [:type] [{key: value, ...}] )
+func (p *parser) parseNode() (*nodePattern, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	node := &nodePattern{props: make(map[string]any)}
+
+	if p.peek().kind == tokenIdent {
+		node.variable = p.next().text
+	} else {
+		p.anon++
+		node.variable = fmt.Sprintf("_anon%d", p.anon)
+	}
+
+	if p.accept(":") {
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		node.elemType = gostructure.ElementType(strings.ToLower(name))
+		if !validElementTypes[node.elemType] {
+			return nil, fmt.Errorf("unknown element type %q", name)
+		}
+	}
+
+	if p.accept("{") {
+		for !p.accept("}") {
+			key, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			value, err := p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			node.props[key] = value
+			if !p.accept(",") && !p.is("}") {
+				return nil, p.errorf("expected \",\" or \"}\"")
+			}
+		}
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// Parses: -[ [:type] [*] ]-> , <-[...]- or -[...]- (the brackets are optional)
+func (p *parser) parseRel() (*relPattern, error) {
+	rel := &relPattern{dir: directionBoth}
+	incoming := p.accept("<")
+	if err := p.expect("-"); err != nil {
+		return nil, err
+	}
+
+	if p.accept("[") {
+		if p.accept(":") {
+			name, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			rel.relType = gostructure.RelationType(strings.ToLower(name))
+			if !validRelationTypes[rel.relType] {
+				return nil, fmt.Errorf("unknown relationship type %q", name)
+			}
+		}
+		rel.transitive = p.accept("*")
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expect("-"); err != nil {
+		return nil, err
+	}
+	outgoing := p.accept(">")
+
+	switch {
+	case incoming && outgoing:
+		return nil, p.errorf("relationship cannot point both ways")
+	case incoming:
+		rel.dir = directionIn
+	case outgoing:
+		rel.dir = directionOut
+	}
+	return rel, nil
+}
+
+// Parses: and (OR and)*
+func (p *parser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orCondition{left, right}
+	}
+	return left, nil
+}
+
+// Parses: unary (AND unary)*
+func (p *parser) parseAnd() (condition, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andCondition{left, right}
+	}
+	return left, nil
+}
+
+// Parses: NOT unary | ( or ) | comparison
+func (p *parser) parseUnary() (condition, error) {
+	if p.accept("not") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notCondition{inner}, nil
+	}
+	if p.accept("(") {
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return cond, p.expect(")")
+	}
+	return p.parseComparison()
+}
+
+// Comparison operators
+var comparisonOps = []string{"=~", "!=", "<=", ">=", "=", "<", ">"}
+
+// Parses: operand op operand
+func (p *parser) parseComparison() (condition, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := &comparison{left: left}
+	for _, op := range comparisonOps {
+		if p.accept(op) {
+			cmp.op = op
+			break
+		}
+	}
+	if cmp.op == "" {
+		return nil, p.errorf("expected comparison operator")
+	}
+
+	if cmp.right, err = p.parseOperand(); err != nil {
+		return nil, err
+	}
+	if s, ok := cmp.right.literal.(string); ok && cmp.op == "=~" {
+		if cmp.pattern, err = regexp.Compile(s); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", s, err)
+		}
+	}
+	return cmp, nil
+}
+
+// Parses: variable[.attribute] | literal
+func (p *parser) parseOperand() (operand, error) {
+	t := p.peek()
+	if t.kind == tokenIdent && !isLiteralKeyword(t.text) {
+		p.next()
+		op := operand{variable: t.text}
+		if p.accept(".") {
+			attr, err := p.ident()
+			if err != nil {
+				return operand{}, err
+			}
+			op.attribute = attr
+		}
+		return op, nil
+	}
+
+	value, err := p.parseLiteral()
+	return operand{literal: value}, err
+}
+
+// Parses: "string" | number | true | false
+func (p *parser) parseLiteral() (any, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenString:
+		p.next()
+		return t.value, nil
+	case t.kind == tokenNumber:
+		p.next()
+		n, _ := strconv.Atoi(t.text)
+		return n, nil
+	case t.kind == tokenIdent && strings.EqualFold(t.text, "true"):
+		p.next()
+		return true, nil
+	case t.kind == tokenIdent && strings.EqualFold(t.text, "false"):
+		p.next()
+		return false, nil
+	}
+	return nil, p.errorf("expected literal")
+}
+
+// Checks if an identifier is a literal keyword
+func isLiteralKeyword(text string) bool {
+	return strings.EqualFold(text, "true") || strings.EqualFold(text, "false")
+}
+
+// Parses: operand [AS name]
+func (p *parser) parseReturnItem() (*returnItem, error) {
+	op, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if op.variable == "" {
+		return nil, p.errorf("expected variable to return")
+	}
+
+	item := &returnItem{operand: op, name: op.variable}
+	if op.attribute != "" {
+		item.name = op.variable + "." + op.attribute
+	}
+	if p.accept("as") {
+		if item.name, err = p.ident(); err != nil {
+			return nil, err
+		}
+	}
+	return item, nil
+}
+
+// Element types usable as node labels
+var validElementTypes = map[gostructure.ElementType]bool{
+	gostructure.ElementPackage:   true,
+	gostructure.ElementInterface: true,
+	gostructure.ElementTypeDecl:  true,
+	gostructure.ElementFunction:  true,
+	gostructure.ElementMethod:    true,
+	gostructure.ElementVariable:  true,
+}
+
+// Relationship types usable in relationship patterns
+var validRelationTypes = map[gostructure.RelationType]bool{
+	gostructure.RelationContains:        true,
+	gostructure.RelationImplements:      true,
+	gostructure.RelationEmbeds:          true,
+	gostructure.RelationInterfaceEmbeds: true,
+	gostructure.RelationMethodReceiver:  true,
+	gostructure.RelationCalls:           true,
+	gostructure.RelationReferences:      true,
+	gostructure.RelationExtends:         true,
+	gostructure.RelationRequests:        true,
+	gostructure.RelationSharesShape:     true,
+	gostructure.RelationGeneratedFrom:   true,
+	gostructure.RelationTests:           true,
+}
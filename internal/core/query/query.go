@@ -0,0 +1,209 @@
+// Package query evaluates a small graph query language over the code structure.
+//
+// Queries match patterns of elements and relationships, Cypher style:
+//
+//	MATCH (t:type)-[:implements]->(i:interface {name: "Analyzer"}),
+//	      (p:package {name: "golang"})-[:contains]->(x)-[:embeds]->(t)
+//	WHERE t.is_exported = true AND x.name =~ "^New"
+//	RETURN t, i.name AS interface
+//	LIMIT 10
+//
+// Node labels are element types (package, interface, type, function, method,
+// variable) and relationship labels are relation types (contains, implements,
+// embeds, interface_embeds, method_receiver, calls, references). A "*" after the
+// relationship label follows one or more hops. Attributes are the element's
+// attributes plus name, type, file and line.
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+)
+
+// A parsed query
+type Query struct {
+	paths   []*pathPattern
+	where   condition
+	returns []*returnItem
+	limit   int
+}
+
+// The result of a query: one row per distinct match
+type Result struct {
+	Columns   []string
+	Rows      [][]any // Cells are elements (*gostructure.Element) or attribute values
+	Truncated bool    // Rows were left out to stay within the maximum number of rows
+}
+
+// Parses a query
+func Parse(src string) (*Query, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	return (&parser{tokens: tokens}).parseQuery()
+}
+
+// Parses and evaluates a query against a structure
+func Run(src string, structure *gostructure.Structure) (*Result, error) {
+	q, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return q.Evaluate(structure), nil
+}
+
+// Evaluates the query against a structure
+func (q *Query) Evaluate(structure *gostructure.Structure) *Result {
+	result, _ := q.EvaluateContext(context.Background(), structure, 0)
+	return result
+}
+
+// Evaluates the query against a structure, stopping with the context's error once it is done.
+// When maxRows is positive, at most maxRows rows are returned, fewer if the query has a lower
+// limit.
+func (q *Query) EvaluateContext(ctx context.Context, structure *gostructure.Structure, maxRows int) (*Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result := &Result{Rows: make([][]any, 0)}
+	for _, item := range q.returns {
+		result.Columns = append(result.Columns, item.name)
+	}
+
+	limit := q.limit
+	if maxRows > 0 && (limit == 0 || limit > maxRows) {
+		limit = maxRows + 1 // One more row tells whether any were left out
+	}
+
+	e := newEvaluator(ctx, structure)
+	seen := make(map[string]bool)
+	e.match(q.paths, binding{}, func(b binding) bool {
+		if q.where != nil && !q.where.eval(b) {
+			return true
+		}
+
+		row := make([]any, len(q.returns))
+		for i, item := range q.returns {
+			row[i] = item.resolve(b)
+		}
+
+		// Keep distinct rows only
+		key := ""
+		for _, cell := range row {
+			if elem, ok := cell.(*gostructure.Element); ok {
+				key += fmt.Sprintf("|%p", elem)
+			} else {
+				key += fmt.Sprintf("|%v", cell)
+			}
+		}
+		if !seen[key] {
+			seen[key] = true
+			result.Rows = append(result.Rows, row)
+		}
+		return limit == 0 || len(result.Rows) < limit
+	})
+	if e.err != nil {
+		return nil, e.err
+	}
+	if maxRows > 0 && len(result.Rows) > maxRows {
+		result.Rows = result.Rows[:maxRows]
+		result.Truncated = true
+	}
+	return result, nil
+}
+
+// Checks that every variable used in the where and return clauses is bound by a pattern
+func (q *Query) checkVariables() error {
+	bound := make(map[string]bool)
+	for _, path := range q.paths {
+		for _, node := range path.nodes {
+			bound[node.variable] = true
+		}
+	}
+
+	var check func(c condition) error
+	check = func(c condition) error {
+		switch c := c.(type) {
+		case *andCondition:
+			if err := check(c.left); err != nil {
+				return err
+			}
+			return check(c.right)
+		case *orCondition:
+			if err := check(c.left); err != nil {
+				return err
+			}
+			return check(c.right)
+		case *notCondition:
+			return check(c.inner)
+		case *comparison:
+			for _, op := range []operand{c.left, c.right} {
+				if op.variable != "" && !bound[op.variable] {
+					return fmt.Errorf("unknown variable %q", op.variable)
+				}
+			}
+		}
+		return nil
+	}
+	if q.where != nil {
+		if err := check(q.where); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range q.returns {
+		if !bound[item.variable] {
+			return fmt.Errorf("unknown variable %q", item.variable)
+		}
+	}
+	return nil
+}
+
+// Returns the value of an operand for a binding
+func (o operand) resolve(b binding) any {
+	if o.variable == "" {
+		return o.literal
+	}
+	elem := b[o.variable]
+	if o.attribute == "" {
+		return elem
+	}
+	return attribute(elem, o.attribute)
+}
+
+// Returns an attribute of an element, including the built-in name, type, file and line
+func attribute(elem *gostructure.Element, name string) any {
+	if elem == nil {
+		return nil
+	}
+	switch name {
+	case "name":
+		return elem.Name
+	case "type":
+		return string(elem.Type)
+	case "file":
+		return elem.Position.Filename
+	case "line":
+		return elem.Position.Line
+	}
+	return elem.Attributes[name]
+}
+
+// Renders a result cell as text
+func FormatCell(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case *gostructure.Element:
+		return fmt.Sprintf("%s %s (%s)", v.Type, v.Name, v.Position)
+	case fmt.Stringer:
+		return v.String()
+	case []string:
+		return strings.Join(v, ",")
+	}
+	return fmt.Sprint(cell)
+}
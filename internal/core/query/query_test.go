@@ -0,0 +1,210 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+	"codedna/internal/core/query"
+)
+
+// Helper function to analyze a testdata file
+func analyzeFile(t *testing.T, name string) *gostructure.Structure {
+	t.Helper()
+
+	node, err := goparser.New().ParseFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", name, err)
+	}
+	analysis, err := gostructure.NewAnalyzer().Analyze(gostructure.NewNode(node))
+	if err != nil {
+		t.Fatalf("Failed to analyze %s: %v", name, err)
+	}
+	return analysis.(*gostructure.Analysis).Structure
+}
+
+// Helper function to render the result rows as sorted text
+func rows(result *query.Result) []string {
+	var got []string
+	for _, row := range result.Rows {
+		var cells []string
+		for _, cell := range row {
+			if elem, ok := cell.(*gostructure.Element); ok {
+				cells = append(cells, elem.Name)
+			} else {
+				cells = append(cells, query.FormatCell(cell))
+			}
+		}
+		got = append(got, strings.Join(cells, " "))
+	}
+	slices.Sort(got)
+	return got
+}
+
+func TestRun(t *testing.T) {
+	structure := analyzeFile(t, "shapes.go")
+
+	tests := []struct {
+		name     string
+		query    string
+		columns  []string
+		expected []string
+	}{
+		{
+			name:     "element type",
+			query:    `MATCH (i:interface) RETURN i`,
+			columns:  []string{"i"},
+			expected: []string{"Shape"},
+		},
+		{
+			name:     "properties",
+			query:    `match (t:type {name: "Rect"}) return t.name`,
+			columns:  []string{"t.name"},
+			expected: []string{"Rect"},
+		},
+		{
+			name:     "where on attributes",
+			query:    `MATCH (t:type) WHERE t.is_exported = false OR t.name =~ "^Sq" RETURN t.name AS type`,
+			columns:  []string{"type"},
+			expected: []string{"Square", "point"},
+		},
+		{
+			name:     "not",
+			query:    `MATCH (f:function) WHERE NOT f.name = "NewRect" RETURN f`,
+			columns:  []string{"f"},
+			expected: []string{"NewSquare"},
+		},
+		{
+			name:     "relationship",
+			query:    `MATCH (t:type)-[:implements]->(:interface {name: "Shape"}) RETURN t`,
+			columns:  []string{"t"},
+			expected: []string{"Rect", "Square"},
+		},
+		{
+			name:     "incoming relationship",
+			query:    `MATCH (b:type {name: "Base"})<-[:embeds]-(t) RETURN t`,
+			columns:  []string{"t"},
+			expected: []string{"Named", "Rect"},
+		},
+		{
+			name:     "transitive relationship",
+			query:    `MATCH (t:type)-[:embeds*]->(b:type {name: "Base"}) RETURN t`,
+			columns:  []string{"t"},
+			expected: []string{"Named", "Rect", "Square"},
+		},
+		{
+			name:     "multi-hop",
+			query:    `MATCH (m:method)-[:method_receiver]->(t:type)-[:embeds]->(e) RETURN m, t, e`,
+			columns:  []string{"m", "t", "e"},
+			expected: []string{"Area Rect Base", "Area Square Named"},
+		},
+		{
+			name:     "shared variables across paths",
+			query:    `MATCH (t)-[:implements]->(i), (t)-[:embeds]->(:type {name: "Named"}) RETURN t, i`,
+			columns:  []string{"t", "i"},
+			expected: []string{"Square Shape"},
+		},
+		{
+			name:     "numeric comparison",
+			query:    `MATCH (f:function) WHERE f.line > 37 RETURN f`,
+			columns:  []string{"f"},
+			expected: []string{"NewRect"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := query.Run(tt.query, structure)
+			if err != nil {
+				t.Fatalf("Failed to run query: %v", err)
+			}
+			if !slices.Equal(result.Columns, tt.columns) {
+				t.Errorf("Expected columns %v, got %v", tt.columns, result.Columns)
+			}
+			if got := rows(result); !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected rows %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("limit rows", func(t *testing.T) {
+		result, err := query.Run(`MATCH (t:type) RETURN t LIMIT 2`, structure)
+		if err != nil {
+			t.Fatalf("Failed to run query: %v", err)
+		}
+		if len(result.Rows) != 2 {
+			t.Errorf("Expected 2 rows, got %d", len(result.Rows))
+		}
+	})
+
+	t.Run("max rows", func(t *testing.T) {
+		q, err := query.Parse(`MATCH (a)-[*]-(b) RETURN a, b`)
+		if err != nil {
+			t.Fatalf("Failed to parse query: %v", err)
+		}
+		result, err := q.EvaluateContext(context.Background(), structure, 3)
+		if err != nil {
+			t.Fatalf("Failed to run query: %v", err)
+		}
+		if len(result.Rows) != 3 || !result.Truncated {
+			t.Errorf("Expected 3 rows with more left out, got %d (truncated %t)", len(result.Rows), result.Truncated)
+		}
+
+		// A lower limit of the query wins and does not truncate
+		q, err = query.Parse(`MATCH (t:type) RETURN t LIMIT 2`)
+		if err != nil {
+			t.Fatalf("Failed to parse query: %v", err)
+		}
+		if result, err = q.EvaluateContext(context.Background(), structure, 3); err != nil || len(result.Rows) != 2 || result.Truncated {
+			t.Errorf("Expected 2 rows, got %v (%v)", result, err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		q, err := query.Parse(`MATCH (a)-[*]-(b) RETURN a, b`)
+		if err != nil {
+			t.Fatalf("Failed to parse query: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := q.EvaluateContext(ctx, structure, 0); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the query to stop with context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{"missing match", `RETURN t`, `expected "match"`},
+		{"missing return", `MATCH (t)`, `expected "return"`},
+		{"unknown element type", `MATCH (t:class) RETURN t`, `unknown element type "class"`},
+		{"unknown relationship", `MATCH (a)-[:inherits]->(b) RETURN a`, `unknown relationship type "inherits"`},
+		{"unknown variable", `MATCH (t) RETURN x`, `unknown variable "x"`},
+		{"unknown where variable", `MATCH (t) WHERE x.name = "A" RETURN t`, `unknown variable "x"`},
+		{"unterminated string", `MATCH (t {name: "A}) RETURN t`, "unterminated string"},
+		{"bad pattern", `MATCH (t) WHERE t.name =~ "(" RETURN t`, "invalid pattern"},
+		{"both directions", `MATCH (a)<-[]->(b) RETURN a`, "both ways"},
+		{"trailing tokens", `MATCH (t) RETURN t t`, "unexpected token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := query.Parse(tt.query)
+			if err == nil {
+				t.Fatalf("Expected error for %q", tt.query)
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
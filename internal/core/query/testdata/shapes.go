@@ -0,0 +1,39 @@
+package shapes
+
+// Shape is implemented by every shape
+type Shape interface {
+	Area() int
+}
+
+// Base is embedded by the shapes
+type Base struct {
+	ID int
+}
+
+// Named is embedded by Base-derived shapes
+type Named struct {
+	Base
+	Name string
+}
+
+type Square struct {
+	Named
+	Side int
+}
+
+func (s *Square) Area() int { return s.Side * s.Side }
+
+type Rect struct {
+	Base
+	Width, Height int
+}
+
+func (r Rect) Area() int { return r.Width * r.Height }
+
+type point struct {
+	X, Y int
+}
+
+func NewSquare(side int) *Square { return &Square{Side: side} }
+
+func NewRect(w, h int) Rect { return Rect{Width: w, Height: h} }
@@ -0,0 +1,105 @@
+// Package report converts CodeDNA findings into interchange formats
+package report
+
+import (
+	"fmt"
+
+	"codedna/internal/core/analysis/debt"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/parser/ast"
+	"codedna/internal/core/rules"
+)
+
+// The severity of a finding
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelNote    Level = "note"
+)
+
+// Metadata of a rule that produces findings
+type Rule struct {
+	ID          string
+	Name        string
+	Description string
+	HelpURI     string
+	Level       Level // Default level of the rule's findings
+}
+
+// Something CodeDNA reports at a location: a rule violation, a pattern deviation, a debt item
+type Finding struct {
+	RuleID   string
+	Level    Level // Empty uses the rule's default level
+	Message  string
+	Element  *gostructure.Element // The element the finding is about, if known
+	Position ast.Position         // Overrides the element position when set
+	Symbol   string               // Name of the element when Element is unknown
+}
+
+// Returns the location of the finding
+func (f *Finding) Location() ast.Position {
+	if f.Position.Filename != "" || f.Element == nil {
+		return f.Position
+	}
+	return f.Element.Position
+}
+
+// Returns the name of the element the finding is about
+func (f *Finding) ElementName() string {
+	if f.Element != nil {
+		return f.Element.Name
+	}
+	return f.Symbol
+}
+
+// Converts rule violations into findings, along with the metadata of the rules they violate
+func FromViolations(violations []*rules.Violation) ([]*Rule, []*Finding) {
+	var ruleList []*Rule
+	seen := make(map[string]bool)
+	findings := make([]*Finding, 0, len(violations))
+	for _, v := range violations {
+		if !seen[v.Rule] {
+			seen[v.Rule] = true
+			ruleList = append(ruleList, &Rule{
+				ID:          v.Rule,
+				Name:        v.Type,
+				Description: rules.Descriptions[v.Type],
+				Level:       LevelError,
+			})
+		}
+		findings = append(findings, &Finding{
+			RuleID:   v.Rule,
+			Message:  v.Message,
+			Position: v.Position,
+			Symbol:   v.Element,
+		})
+	}
+	return ruleList, findings
+}
+
+// Converts debt items into findings, one per item with the element's score, along with the
+// metadata of the signals they come from
+func FromDebt(report *debt.Report) ([]*Rule, []*Finding) {
+	ruleList := make([]*Rule, 0, len(debt.Signals))
+	for _, signal := range debt.Signals {
+		ruleList = append(ruleList, &Rule{
+			ID:          "debt/" + string(signal),
+			Name:        string(signal),
+			Description: debt.Descriptions[signal],
+			Level:       LevelNote,
+		})
+	}
+	var findings []*Finding
+	for _, elem := range report.Elements {
+		for _, item := range elem.Items {
+			findings = append(findings, &Finding{
+				RuleID:  "debt/" + string(item.Signal),
+				Message: fmt.Sprintf("%s: %s (%.2f of %.2f debt points)", elem.Name, item.Reason, item.Points, elem.Score),
+				Element: elem.Element,
+			})
+		}
+	}
+	return ruleList, findings
+}
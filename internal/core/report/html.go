@@ -0,0 +1,627 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gopattern "codedna/internal/core/analysis/pattern/golang"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/dna"
+	"codedna/internal/core/parser/ast"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// Layout of the generated charts and graphs, in pixels
+const (
+	chartBarWidth  = 320
+	chartRowHeight = 22
+	graphSize      = 520
+	graphRadius    = 200
+	graphNodeSize  = 6
+	graphMargin    = 160 // Room for the labels on either side
+)
+
+// Generates a static, self-contained HTML site from an analysis
+type HTMLReporter struct {
+	title string
+	root  string // Locations are shown relative to root
+}
+
+// Creates a new HTML reporter
+func NewHTMLReporter(title, root string) *HTMLReporter {
+	return &HTMLReporter{title: title, root: root}
+}
+
+// Writes the site into dir: an index page plus one page per package
+func (r *HTMLReporter) Write(dir string, analysis *gostructure.Analysis, profile *dna.Profile) error {
+	pages, err := parseTemplates()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "packages"), 0o755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	site := r.build(analysis, profile)
+	if err := writePage(pages["index"], filepath.Join(dir, "index.html"), site); err != nil {
+		return err
+	}
+	for _, pkg := range site.Packages {
+		page := &packagePage{Title: site.Title, Base: "../", Package: pkg}
+		if err := writePage(pages["package"], filepath.Join(dir, "packages", pkg.Slug+".html"), page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Parses the page templates, each combined with the shared layout
+func parseTemplates() (map[string]*template.Template, error) {
+	layout, err := template.New("layout.html").ParseFS(templateFS, "templates/layout.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report layout: %w", err)
+	}
+	pages := make(map[string]*template.Template)
+	for _, name := range []string{"index", "package"} {
+		page, err := template.Must(layout.Clone()).ParseFS(templateFS, "templates/"+name+".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+		}
+		pages[name] = page
+	}
+	return pages, nil
+}
+
+// Renders a page into a file
+func writePage(tmpl *template.Template, path string, data any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := tmpl.Execute(f, data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to render %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// The index page
+type indexPage struct {
+	Title       string
+	Base        string // Path prefix back to the site root
+	Language    string
+	Charts      []*chart
+	Packages    []*packageView
+	Graph       *graph
+	Interfaces  []*interfaceView
+	Idioms      []*idiomView
+	Traits      []*chart // Error and concurrency pattern counts
+	Conventions []*conventionView
+}
+
+// A package page
+type packagePage struct {
+	Title   string
+	Base    string
+	Package *packageView
+}
+
+// A Go package: all package elements sharing a directory
+type packageView struct {
+	Name       string
+	Dir        string
+	Slug       string
+	Files      int
+	Counts     map[string]int
+	Groups     []*elementGroup
+	Imports    []*importView
+	ImportedBy []*packageView
+	Interfaces []*interfaceView
+	Idioms     []*idiomView
+	Metrics    *chart
+
+	packages []*gostructure.Element
+	elements []*gostructure.Element
+}
+
+// Elements of one type
+type elementGroup struct {
+	Type     gostructure.ElementType
+	Elements []*elementView
+}
+
+type elementView struct {
+	Name     string
+	Location string
+	Exported bool
+}
+
+// An import of a package, linked when it is part of the project
+type importView struct {
+	Path    string
+	Package *packageView
+}
+
+// An interface with the types implementing it
+type interfaceView struct {
+	Name            string
+	Location        string
+	Package         *packageView
+	Implementations []*implementationView
+}
+
+type implementationView struct {
+	Name     string
+	Location string
+	Package  *packageView
+}
+
+// A design idiom in use
+type idiomView struct {
+	Kind     string
+	Count    int
+	Examples []*exampleView
+}
+
+// A naming rule or tag key with its learned convention and sample deviations
+type conventionView struct {
+	Kind       string // "naming" or "tag"
+	Name       string
+	Dominant   string
+	Share      string // Names following the convention, as "n/total"
+	Deviations int
+	Examples   []*exampleView
+}
+
+type exampleView struct {
+	Element  string
+	Location string
+	Detail   string
+}
+
+// A horizontal bar chart rendered as inline SVG
+type chart struct {
+	Title  string
+	Height int
+	Bars   []*bar
+}
+
+type bar struct {
+	Label string
+	Value int
+	Y     int
+	Width int
+}
+
+// A package dependency graph rendered as inline SVG
+type graph struct {
+	Width  int
+	Height int
+	Nodes  []*graphNode
+	Edges  []*graphEdge
+}
+
+type graphNode struct {
+	Label  string
+	Slug   string
+	X, Y   float64
+	Anchor string // Text anchor of the label
+	LabelX float64
+}
+
+type graphEdge struct {
+	X1, Y1, X2, Y2 float64
+}
+
+// Element types in the order they are listed
+var elementOrder = []gostructure.ElementType{
+	gostructure.ElementInterface,
+	gostructure.ElementTypeDecl,
+	gostructure.ElementFunction,
+	gostructure.ElementMethod,
+	gostructure.ElementVariable,
+}
+
+// Builds the data of the whole site
+func (r *HTMLReporter) build(analysis *gostructure.Analysis, profile *dna.Profile) *indexPage {
+	structure := analysis.Structure
+	site := &indexPage{Title: r.title, Language: analysis.Language()}
+
+	packages, owner := r.packages(structure)
+	site.Packages = packages
+	r.linkImports(packages)
+
+	collector := gostructure.NewMetricsCollector()
+	collector.CollectMetrics(structure)
+	site.Charts = metricCharts(collector)
+
+	site.Interfaces = r.interfaces(structure, owner)
+	for _, iface := range site.Interfaces {
+		iface.Package.Interfaces = append(iface.Package.Interfaces, iface)
+	}
+
+	if profile != nil {
+		site.Idioms = r.idioms(profile.Idioms)
+		for _, pkg := range packages {
+			if pkgProfile, ok := profile.Packages[pkg.packages[0].Scope]; ok {
+				pkg.Idioms = r.idioms(pkgProfile.Idioms)
+			}
+		}
+		site.Traits = traitCharts(profile)
+		if profile.Conventions != nil {
+			site.Conventions = r.conventions(profile.Conventions)
+		}
+	}
+
+	for _, pkg := range packages {
+		pkg.Metrics = packageMetrics(pkg, structure)
+	}
+	site.Graph = dependencyGraph(packages)
+	return site
+}
+
+// Groups the package elements by directory and collects the elements they contain
+func (r *HTMLReporter) packages(structure *gostructure.Structure) ([]*packageView, map[*gostructure.Element]*packageView) {
+	byDir := make(map[string]*packageView)
+	owner := make(map[*gostructure.Element]*packageView)
+	for _, elem := range structure.Elements {
+		if elem.Type != gostructure.ElementPackage {
+			continue
+		}
+		dir := r.relative(filepath.Dir(elem.Position.Filename))
+		pkg, ok := byDir[dir]
+		if !ok {
+			pkg = &packageView{Name: elem.Name, Dir: dir, Slug: slug(dir), Counts: make(map[string]int)}
+			byDir[dir] = pkg
+		}
+		pkg.Files++
+		pkg.packages = append(pkg.packages, elem)
+		owner[elem] = pkg
+	}
+
+	for _, rel := range structure.Relationships {
+		if rel.Type != gostructure.RelationContains || rel.Source.Type != gostructure.ElementPackage {
+			continue
+		}
+		pkg := owner[rel.Source]
+		if pkg == nil || rel.Target.Name == "" {
+			continue
+		}
+		owner[rel.Target] = pkg
+		pkg.elements = append(pkg.elements, rel.Target)
+	}
+
+	var packages []*packageView
+	for _, pkg := range byDir {
+		groups := make(map[gostructure.ElementType]*elementGroup)
+		for _, elem := range pkg.elements {
+			group, ok := groups[elem.Type]
+			if !ok {
+				group = &elementGroup{Type: elem.Type}
+				groups[elem.Type] = group
+			}
+			exported, _ := elem.Attributes["is_exported"].(bool)
+			group.Elements = append(group.Elements, &elementView{
+				Name:     elem.Name,
+				Location: r.location(elem.Position),
+				Exported: exported,
+			})
+			pkg.Counts[string(elem.Type)]++
+		}
+		for _, elemType := range elementOrder {
+			if group, ok := groups[elemType]; ok {
+				sort.SliceStable(group.Elements, func(i, j int) bool { return group.Elements[i].Name < group.Elements[j].Name })
+				pkg.Groups = append(pkg.Groups, group)
+			}
+		}
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Dir < packages[j].Dir })
+	return packages, owner
+}
+
+// Resolves the imports of each package, linking those within the project
+func (r *HTMLReporter) linkImports(packages []*packageView) {
+	for _, pkg := range packages {
+		seen := make(map[string]bool)
+		for _, elem := range pkg.packages {
+			deps, _ := elem.Attributes["dependencies"].([]string)
+			for _, dep := range deps {
+				if seen[dep] {
+					continue
+				}
+				seen[dep] = true
+				imp := &importView{Path: dep, Package: findPackage(packages, dep)}
+				pkg.Imports = append(pkg.Imports, imp)
+				if imp.Package != nil && imp.Package != pkg {
+					imp.Package.ImportedBy = append(imp.Package.ImportedBy, pkg)
+				}
+			}
+		}
+		sort.Slice(pkg.Imports, func(i, j int) bool { return pkg.Imports[i].Path < pkg.Imports[j].Path })
+	}
+}
+
+// Returns the project package an import path refers to, matching the directory as a path suffix
+func findPackage(packages []*packageView, importPath string) *packageView {
+	for _, pkg := range packages {
+		if pkg.Dir == "." {
+			continue
+		}
+		if importPath == pkg.Dir || strings.HasSuffix(importPath, "/"+pkg.Dir) {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// Collects the interfaces with their implementations
+func (r *HTMLReporter) interfaces(structure *gostructure.Structure, owner map[*gostructure.Element]*packageView) []*interfaceView {
+	views := make(map[*gostructure.Element]*interfaceView)
+	var result []*interfaceView
+	for _, elem := range structure.Elements {
+		if elem.Type != gostructure.ElementInterface || owner[elem] == nil {
+			continue
+		}
+		view := &interfaceView{Name: elem.Name, Location: r.location(elem.Position), Package: owner[elem]}
+		views[elem] = view
+		result = append(result, view)
+	}
+
+	for _, rel := range structure.Relationships {
+		if rel.Type != gostructure.RelationImplements {
+			continue
+		}
+		if view, ok := views[rel.Target]; ok {
+			view.Implementations = append(view.Implementations, &implementationView{
+				Name:     rel.Source.Name,
+				Location: r.location(rel.Source.Position),
+				Package:  owner[rel.Source],
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Package.Dir != result[j].Package.Dir {
+			return result[i].Package.Dir < result[j].Package.Dir
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Converts idiom profiles into views, ordered by kind
+func (r *HTMLReporter) idioms(idioms map[gopattern.PatternKind]*dna.IdiomProfile) []*idiomView {
+	var result []*idiomView
+	for kind, idiom := range idioms {
+		result = append(result, &idiomView{Kind: string(kind), Count: idiom.Count, Examples: r.examples(idiom.Examples)})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Kind < result[j].Kind })
+	return result
+}
+
+// Builds the convention views, naming rules first
+func (r *HTMLReporter) conventions(profile *dna.ConventionProfile) []*conventionView {
+	var naming, tags []*conventionView
+	for rule, convention := range profile.Naming {
+		naming = append(naming, &conventionView{
+			Kind:       "naming",
+			Name:       string(rule),
+			Dominant:   convention.Dominant,
+			Share:      fmt.Sprintf("%d/%d", convention.Total-convention.Deviations, convention.Total),
+			Deviations: convention.Deviations,
+			Examples:   r.examples(convention.Examples),
+		})
+	}
+	for key, convention := range profile.Tags {
+		tags = append(tags, &conventionView{
+			Kind:       "tag",
+			Name:       key,
+			Dominant:   string(convention.Dominant),
+			Share:      fmt.Sprintf("%d/%d", convention.Total-convention.Deviations, convention.Total),
+			Deviations: convention.Deviations + convention.Missing,
+			Examples:   r.examples(convention.Examples),
+		})
+	}
+	sort.Slice(naming, func(i, j int) bool { return naming[i].Name < naming[j].Name })
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return append(naming, tags...)
+}
+
+// Converts profile examples into views
+func (r *HTMLReporter) examples(examples []*dna.Example) []*exampleView {
+	views := make([]*exampleView, 0, len(examples))
+	for _, example := range examples {
+		views = append(views, &exampleView{
+			Element:  example.Element,
+			Location: r.location(example.Position),
+			Detail:   example.Detail,
+		})
+	}
+	return views
+}
+
+// Returns a position as text relative to the root
+func (r *HTMLReporter) location(pos ast.Position) string {
+	pos.Filename = r.relative(pos.Filename)
+	return pos.String()
+}
+
+// Returns a path relative to the root, in slash form
+func (r *HTMLReporter) relative(path string) string {
+	if r.root != "" {
+		if rel, err := filepath.Rel(r.root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// Returns a file name for a package directory. Slashes become dashes and underscores escape
+// dashes and themselves, so distinct directories (e.g. a/b_c and a_b/c) never share a page.
+func slug(dir string) string {
+	if dir == "." {
+		return "_root" // Never produced for a directory, since underscores only escape _ and -
+	}
+	return strings.NewReplacer("_", "__", "-", "_-", "/", "-").Replace(dir)
+}
+
+// Builds the charts of the project-wide metrics
+func metricCharts(c *gostructure.MetricsCollector) []*chart {
+	metricChart := func(title string, metrics ...gostructure.MetricType) *chart {
+		labels := make([]string, len(metrics))
+		values := make([]int, len(metrics))
+		for i, metric := range metrics {
+			labels[i] = string(metric)
+			values[i] = c.Metric(metric)
+		}
+		return newChart(title, labels, values)
+	}
+	return []*chart{
+		metricChart("Elements",
+			gostructure.MetricPackages, gostructure.MetricInterfaces, gostructure.MetricTypes,
+			gostructure.MetricFunctions, gostructure.MetricMethods, gostructure.MetricVariables),
+		metricChart("Relationships",
+			gostructure.MetricContains, gostructure.MetricImplements, gostructure.MetricEmbeds,
+			gostructure.MetricInterfaceEmbeds, gostructure.MetricMethodReceiver, gostructure.MetricReferences),
+		metricChart("Complexity",
+			gostructure.MetricMaxDepth, gostructure.MetricAvgDepth, gostructure.MetricMaxChildren, gostructure.MetricAvgChildren),
+	}
+}
+
+// Collects the metrics of a package from the part of the structure it contains
+func packageMetrics(pkg *packageView, structure *gostructure.Structure) *chart {
+	members := make(map[*gostructure.Element]bool)
+	sub := &gostructure.Structure{}
+	for _, elem := range append(append([]*gostructure.Element{}, pkg.packages...), pkg.elements...) {
+		members[elem] = true
+		sub.Elements = append(sub.Elements, elem)
+	}
+	for _, rel := range structure.Relationships {
+		if members[rel.Source] && members[rel.Target] {
+			sub.Relationships = append(sub.Relationships, rel)
+		}
+	}
+
+	c := gostructure.NewMetricsCollector()
+	c.CollectMetrics(sub)
+	metrics := []gostructure.MetricType{
+		gostructure.MetricInterfaces, gostructure.MetricTypes, gostructure.MetricFunctions,
+		gostructure.MetricMethods, gostructure.MetricVariables, gostructure.MetricImplements,
+		gostructure.MetricEmbeds, gostructure.MetricMethodReceiver,
+	}
+	labels := make([]string, len(metrics))
+	values := make([]int, len(metrics))
+	for i, metric := range metrics {
+		labels[i] = string(metric)
+		values[i] = c.Metric(metric)
+	}
+	return newChart("Metrics", labels, values)
+}
+
+// Builds the charts of the error-handling and concurrency pattern counts
+func traitCharts(profile *dna.Profile) []*chart {
+	countChart := func(title string, counts map[string]int) *chart {
+		var labels []string
+		for label := range counts {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		values := make([]int, len(labels))
+		for i, label := range labels {
+			values[i] = counts[label]
+		}
+		return newChart(title, labels, values)
+	}
+
+	errors := make(map[string]int)
+	for kind, count := range profile.Errors.Patterns {
+		errors[string(kind)] = count
+	}
+	concurrency := make(map[string]int)
+	for kind, count := range profile.Concurrency.Patterns {
+		concurrency[string(kind)] = count
+	}
+
+	var charts []*chart
+	if len(errors) > 0 {
+		charts = append(charts, countChart("Error handling", errors))
+	}
+	if len(concurrency) > 0 {
+		charts = append(charts, countChart("Concurrency", concurrency))
+	}
+	return charts
+}
+
+// Lays out a bar chart, scaling the bars to the largest value
+func newChart(title string, labels []string, values []int) *chart {
+	maxValue := 0
+	for _, v := range values {
+		maxValue = max(maxValue, v)
+	}
+	c := &chart{Title: title, Height: len(values) * chartRowHeight}
+	for i, v := range values {
+		width := 0
+		if maxValue > 0 {
+			width = v * chartBarWidth / maxValue
+		}
+		c.Bars = append(c.Bars, &bar{Label: labels[i], Value: v, Y: i * chartRowHeight, Width: width})
+	}
+	return c
+}
+
+// Lays out the project packages on a circle, with an arrow per internal import
+func dependencyGraph(packages []*packageView) *graph {
+	g := &graph{Width: graphSize + 2*graphMargin, Height: graphSize}
+	centerX, centerY := float64(g.Width)/2, float64(g.Height)/2
+	nodes := make(map[*packageView]*graphNode)
+	for i, pkg := range packages {
+		angle := 2*math.Pi*float64(i)/float64(len(packages)) - math.Pi/2
+		node := &graphNode{
+			Label: pkg.Dir,
+			Slug:  pkg.Slug,
+			X:     round(centerX + graphRadius*math.Cos(angle)),
+			Y:     round(centerY + graphRadius*math.Sin(angle)),
+		}
+		node.Anchor, node.LabelX = "start", node.X+graphNodeSize+4
+		if node.X < centerX-1 {
+			node.Anchor, node.LabelX = "end", node.X-graphNodeSize-4
+		}
+		nodes[pkg] = node
+		g.Nodes = append(g.Nodes, node)
+	}
+
+	for _, pkg := range packages {
+		for _, imp := range pkg.Imports {
+			if imp.Package == nil || imp.Package == pkg {
+				continue
+			}
+			from, to := nodes[pkg], nodes[imp.Package]
+			// Stop the line at the edge of the target node so the arrow stays visible
+			dx, dy := to.X-from.X, to.Y-from.Y
+			length := math.Hypot(dx, dy)
+			if length == 0 {
+				continue
+			}
+			shorten := float64(graphNodeSize+2) / length
+			g.Edges = append(g.Edges, &graphEdge{
+				X1: from.X, Y1: from.Y,
+				X2: round(to.X - dx*shorten), Y2: round(to.Y - dy*shorten),
+			})
+		}
+	}
+	return g
+}
+
+// Rounds a coordinate to one decimal
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,172 @@
+package report_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/dna"
+	goparser "codedna/internal/core/parser/golang"
+	"codedna/internal/core/report"
+)
+
+// Helper function to read a generated page
+func readPage(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+// Helper function to assert a page contains all fragments
+func assertContains(t *testing.T, page, name string, fragments ...string) {
+	t.Helper()
+
+	for _, fragment := range fragments {
+		if !strings.Contains(page, fragment) {
+			t.Errorf("Expected %s to contain %q", name, fragment)
+		}
+	}
+}
+
+func TestHTMLReporter_Write(t *testing.T) {
+	root := filepath.Join("testdata", "project")
+	parser := goparser.New()
+	var nodes []structure.Node
+	for _, dir := range []string{"app", "shapes"} {
+		astNodes, err := parser.ParseDir(filepath.Join(root, dir))
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", dir, err)
+		}
+		for _, node := range astNodes {
+			nodes = append(nodes, gostructure.NewNode(node))
+		}
+	}
+	result, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze project: %v", err)
+	}
+	analysis := result.(*gostructure.Analysis)
+	profile, err := dna.NewGoAnalyzer().Analyze(analysis)
+	if err != nil {
+		t.Fatalf("Failed to build profile: %v", err)
+	}
+
+	out := t.TempDir()
+	if err := report.NewHTMLReporter("Project <DNA>", root).Write(out, analysis, profile); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	t.Run("index", func(t *testing.T) {
+		index := readPage(t, filepath.Join(out, "index.html"))
+		assertContains(t, index, "index.html",
+			"<title>Project &lt;DNA&gt;</title>",
+			`<a href="packages/app.html">app</a>`,
+			`<a href="packages/shapes.html">shapes</a>`,
+			"<code>Shape</code> <span class=\"loc\">shapes/shapes.go:4:6</span>",
+			"<code>Square</code>",
+			"constructor_concrete",
+			`marker-end="url(#arrow)"`,
+		)
+		if strings.Count(index, "<line ") != 1 {
+			t.Errorf("Expected one dependency edge (app -> shapes), got %d", strings.Count(index, "<line "))
+		}
+	})
+
+	t.Run("package pages", func(t *testing.T) {
+		app := readPage(t, filepath.Join(out, "packages", "app.html"))
+		assertContains(t, app, "app.html",
+			"<h1>Package app</h1>",
+			`<a href="shapes.html"><code>example.com/project/shapes</code></a>`,
+			"<code>Total</code>",
+			"<code>labeled</code> <span class=\"muted\">unexported</span>",
+		)
+
+		shapes := readPage(t, filepath.Join(out, "packages", "shapes.html"))
+		assertContains(t, shapes, "shapes.html",
+			`<a href="app.html">app</a>`,
+			"<code>Shape</code>",
+			"<code>NewSquare</code>",
+			"<code>Area</code>",
+		)
+	})
+
+	t.Run("self-contained", func(t *testing.T) {
+		err := filepath.Walk(out, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			page := readPage(t, path)
+			for _, external := range []string{"<script src", "<link ", `src="http`, "@import"} {
+				if strings.Contains(page, external) {
+					t.Errorf("%s references an external resource (%s)", path, external)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to walk report: %v", err)
+		}
+	})
+}
+
+func TestHTMLReporter_PackagePages(t *testing.T) {
+	root := t.TempDir()
+	dirs := []string{"a/b_c", "a_b/c", "api.v1", "api_v1", "api-v1", "api/v1"}
+	parser := goparser.New()
+	var nodes []structure.Node
+	for _, dir := range dirs {
+		path := filepath.Join(root, filepath.FromSlash(dir))
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "pkg.go"), []byte("package pkg\n\nfunc F() {}\n"), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", dir, err)
+		}
+		astNodes, err := parser.ParseDir(path)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", dir, err)
+		}
+		for _, node := range astNodes {
+			nodes = append(nodes, gostructure.NewNode(node))
+		}
+	}
+	result, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze project: %v", err)
+	}
+
+	out := t.TempDir()
+	if err := report.NewHTMLReporter("Pages", root).Write(out, result.(*gostructure.Analysis), nil); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	// Every directory gets its own page, even when their names differ only in separators
+	pages, err := os.ReadDir(filepath.Join(out, "packages"))
+	if err != nil {
+		t.Fatalf("Failed to read package pages: %v", err)
+	}
+	if len(pages) != len(dirs) {
+		t.Fatalf("Expected %d package pages, got %d", len(dirs), len(pages))
+	}
+	seen := make(map[string]bool)
+	for _, page := range pages {
+		content := readPage(t, filepath.Join(out, "packages", page.Name()))
+		for _, dir := range dirs {
+			if strings.Contains(content, `<p class="muted"><code>`+dir+`</code>`) {
+				seen[dir] = true
+			}
+		}
+	}
+	for _, dir := range dirs {
+		if !seen[dir] {
+			t.Errorf("Expected a page for %s", dir)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+)
+
+// SARIF version and schema emitted by the reporter
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/errata01/os/schemas/sarif-schema-2.1.0.json"
+)
+
+// The base id that relative artifact URIs resolve against
+const srcRootBaseID = "%SRCROOT%"
+
+// The top-level SARIF log
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// A single run of an analysis tool
+type SARIFRun struct {
+	Tool               SARIFTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]SARIFArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []SARIFResult                    `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name           string                     `json:"name"`
+	Version        string                     `json:"version,omitempty"`
+	InformationURI string                     `json:"informationUri,omitempty"`
+	Rules          []SARIFReportingDescriptor `json:"rules"`
+}
+
+// Rule metadata
+type SARIFReportingDescriptor struct {
+	ID                   string                  `json:"id"`
+	Name                 string                  `json:"name,omitempty"`
+	ShortDescription     *SARIFMessage           `json:"shortDescription,omitempty"`
+	HelpURI              string                  `json:"helpUri,omitempty"`
+	DefaultConfiguration *SARIFRuleConfiguration `json:"defaultConfiguration,omitempty"`
+}
+
+type SARIFRuleConfiguration struct {
+	Level Level `json:"level"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     Level           `json:"level,omitempty"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations,omitempty"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+type SARIFArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type SARIFLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// Converts findings into SARIF logs
+type SARIFReporter struct {
+	name           string
+	version        string
+	informationURI string
+	root           string // Artifact URIs are made relative to root when set
+}
+
+// Creates a new SARIF reporter for the named tool
+func NewSARIFReporter(name, version, root string) *SARIFReporter {
+	return &SARIFReporter{name: name, version: version, root: root}
+}
+
+// Sets the URI of the tool's documentation
+func (r *SARIFReporter) SetInformationURI(uri string) {
+	r.informationURI = uri
+}
+
+// Builds a SARIF log with one run holding the rules and their findings
+func (r *SARIFReporter) Build(rules []*Rule, findings []*Finding) (*SARIFLog, error) {
+	run := SARIFRun{
+		Tool: SARIFTool{Driver: SARIFDriver{
+			Name:           r.name,
+			Version:        r.version,
+			InformationURI: r.informationURI,
+			Rules:          make([]SARIFReportingDescriptor, 0, len(rules)),
+		}},
+		Results: make([]SARIFResult, 0, len(findings)),
+	}
+	if r.root != "" {
+		if root, err := filepath.Abs(r.root); err == nil {
+			run.OriginalURIBaseIDs = map[string]SARIFArtifactLocation{
+				srcRootBaseID: {URI: directoryURI(root)},
+			}
+		}
+	}
+
+	index := make(map[string]int, len(rules))
+	for _, rule := range rules {
+		if _, ok := index[rule.ID]; ok {
+			return nil, fmt.Errorf("duplicate rule %q", rule.ID)
+		}
+		index[rule.ID] = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, r.descriptor(rule))
+	}
+
+	for _, f := range findings {
+		i, ok := index[f.RuleID]
+		if !ok {
+			return nil, fmt.Errorf("finding references unknown rule %q", f.RuleID)
+		}
+		result := SARIFResult{
+			RuleID:    f.RuleID,
+			RuleIndex: i,
+			Level:     f.Level,
+			Message:   SARIFMessage{Text: f.Message},
+		}
+		if location, ok := r.location(f); ok {
+			result.Locations = []SARIFLocation{location}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return &SARIFLog{Schema: SARIFSchema, Version: SARIFVersion, Runs: []SARIFRun{run}}, nil
+}
+
+// Writes the rules and findings as an indented SARIF log
+func (r *SARIFReporter) Write(w io.Writer, rules []*Rule, findings []*Finding) error {
+	log, err := r.Build(rules, findings)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// Converts a rule into a reporting descriptor
+func (r *SARIFReporter) descriptor(rule *Rule) SARIFReportingDescriptor {
+	d := SARIFReportingDescriptor{ID: rule.ID, Name: rule.Name, HelpURI: rule.HelpURI}
+	if rule.Description != "" {
+		d.ShortDescription = &SARIFMessage{Text: rule.Description}
+	}
+	if rule.Level != "" {
+		d.DefaultConfiguration = &SARIFRuleConfiguration{Level: rule.Level}
+	}
+	return d
+}
+
+// Converts the location of a finding, reporting false when it has none
+func (r *SARIFReporter) location(f *Finding) (SARIFLocation, bool) {
+	var location SARIFLocation
+	pos := f.Location()
+	if pos.Filename != "" {
+		physical := &SARIFPhysicalLocation{ArtifactLocation: r.artifact(pos.Filename)}
+		if pos.Line > 0 {
+			physical.Region = &SARIFRegion{StartLine: pos.Line, StartColumn: pos.Column}
+		}
+		location.PhysicalLocation = physical
+	}
+	if name := f.ElementName(); name != "" {
+		logical := SARIFLogicalLocation{Name: name}
+		if f.Element != nil {
+			logical.Kind = logicalKinds[f.Element.Type]
+		}
+		location.LogicalLocations = []SARIFLogicalLocation{logical}
+	}
+	return location, location.PhysicalLocation != nil || location.LogicalLocations != nil
+}
+
+// Returns the artifact location of a file, relative to the root when inside it
+func (r *SARIFReporter) artifact(filename string) SARIFArtifactLocation {
+	if r.root != "" {
+		if rel, err := filepath.Rel(r.root, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			return SARIFArtifactLocation{URI: pathURI(rel), URIBaseID: srcRootBaseID}
+		}
+	}
+	return SARIFArtifactLocation{URI: pathURI(filename)}
+}
+
+// Returns the URI reference of a file path, escaping the characters URIs do not allow as is
+// (e.g. spaces and #)
+func pathURI(path string) string {
+	return (&url.URL{Path: filepath.ToSlash(path)}).String()
+}
+
+// Returns the file URI of an absolute directory, ending with a slash as base URIs must
+func directoryURI(dir string) string {
+	path := filepath.ToSlash(dir)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // Windows drive letters (e.g. /C:/project)
+	}
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// SARIF logical location kinds by element type
+var logicalKinds = map[gostructure.ElementType]string{
+	gostructure.ElementPackage:   "namespace",
+	gostructure.ElementInterface: "type",
+	gostructure.ElementTypeDecl:  "type",
+	gostructure.ElementFunction:  "function",
+	gostructure.ElementMethod:    "member",
+	gostructure.ElementVariable:  "variable",
+}
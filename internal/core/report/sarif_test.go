@@ -0,0 +1,256 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"codedna/internal/core/analysis/debt"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/parser/ast"
+	"codedna/internal/core/report"
+	"codedna/internal/core/rules"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// Helper function to compare output against a golden file
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("Failed to update %s: %v", path, err)
+		}
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Errorf("Output does not match %s (run with -update to refresh):\n%s", path, got)
+	}
+}
+
+func TestSARIFReporter_Golden(t *testing.T) {
+	store := &gostructure.Element{
+		Type:     gostructure.ElementInterface,
+		Name:     "Store",
+		Position: ast.Position{Filename: "/project/domain/user.go", Line: 23, Column: 6},
+	}
+	save := &gostructure.Element{
+		Type:     gostructure.ElementMethod,
+		Name:     "Save",
+		Position: ast.Position{Filename: "/project/infra/db.go", Line: 12, Column: 1},
+	}
+	violationRules, violationFindings := report.FromViolations(violations())
+	debtRules, debtFindings := report.FromDebt(&debt.Report{
+		Score: 1.5,
+		Elements: []*debt.ElementScore{{
+			Element: save,
+			Name:    "DB.Save",
+			Score:   1.5,
+			Items: []*debt.Item{
+				{Signal: debt.SignalComplexity, Value: 20, Threshold: 10, Points: 1, Reason: "cyclomatic complexity 20 exceeds 10"},
+				{Signal: debt.SignalTodo, Value: 1, Points: 0.5, Reason: "TODO comments: FIXME: retry"},
+			},
+		}},
+	})
+
+	tests := []struct {
+		name     string
+		golden   string
+		rules    []*report.Rule
+		findings []*report.Finding
+	}{
+		{
+			name:     "violations",
+			golden:   "violations.sarif",
+			rules:    violationRules,
+			findings: violationFindings,
+		},
+		{
+			name:   "elements",
+			golden: "elements.sarif",
+			rules: []*report.Rule{
+				{ID: "naming", Name: "naming-deviation", Description: "Names deviate from the project conventions", Level: report.LevelWarning},
+				{ID: "debt", HelpURI: "https://example.com/debt", Level: report.LevelNote},
+			},
+			findings: []*report.Finding{
+				{RuleID: "naming", Message: "interface name lacks the -er suffix", Element: store},
+				{RuleID: "debt", Level: report.LevelWarning, Message: "method is too long", Element: save},
+				{RuleID: "debt", Message: "project has no tests"},
+			},
+		},
+		{
+			name:     "debt",
+			golden:   "debt.sarif",
+			rules:    debtRules,
+			findings: debtFindings,
+		},
+		{
+			name:   "no findings",
+			golden: "empty.sarif",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			reporter := report.NewSARIFReporter("codedna", "0.1.0", "/project")
+			reporter.SetInformationURI("https://github.com/thread-koder/codedna")
+			if err := reporter.Write(&buf, tt.rules, tt.findings); err != nil {
+				t.Fatalf("Failed to write SARIF: %v", err)
+			}
+			assertGolden(t, tt.golden, buf.Bytes())
+			validateSARIF(t, buf.Bytes())
+		})
+	}
+}
+
+func TestSARIFReporter_Errors(t *testing.T) {
+	reporter := report.NewSARIFReporter("codedna", "", "")
+
+	t.Run("unknown rule", func(t *testing.T) {
+		_, err := reporter.Build(nil, []*report.Finding{{RuleID: "missing", Message: "x"}})
+		if err == nil || !strings.Contains(err.Error(), "unknown rule") {
+			t.Errorf("Expected unknown rule error, got %v", err)
+		}
+	})
+
+	t.Run("duplicate rule", func(t *testing.T) {
+		_, err := reporter.Build([]*report.Rule{{ID: "a"}, {ID: "a"}}, nil)
+		if err == nil || !strings.Contains(err.Error(), "duplicate rule") {
+			t.Errorf("Expected duplicate rule error, got %v", err)
+		}
+	})
+
+	t.Run("paths without root", func(t *testing.T) {
+		log, err := reporter.Build([]*report.Rule{{ID: "a"}}, []*report.Finding{
+			{RuleID: "a", Message: "x", Position: ast.Position{Filename: "pkg/a.go", Line: 3}},
+		})
+		if err != nil {
+			t.Fatalf("Failed to build SARIF: %v", err)
+		}
+		artifact := log.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation
+		if artifact.URI != "pkg/a.go" || artifact.URIBaseID != "" {
+			t.Errorf("Expected plain uri pkg/a.go, got %+v", artifact)
+		}
+	})
+}
+
+func TestSARIFReporter_URIs(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "my project #1")
+	reporter := report.NewSARIFReporter("codedna", "0.1.0", root)
+	var buf bytes.Buffer
+	err := reporter.Write(&buf, []*report.Rule{{ID: "a"}}, []*report.Finding{
+		{RuleID: "a", Message: "x", Position: ast.Position{Filename: filepath.Join(root, "a b", "c#d.go"), Line: 3}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to write SARIF: %v", err)
+	}
+	validateSARIF(t, buf.Bytes())
+
+	var log report.SARIFLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("Invalid SARIF: %v", err)
+	}
+	run := log.Runs[0]
+
+	// The base URI and the artifact URI resolve back to the paths
+	base, err := url.Parse(run.OriginalURIBaseIDs["%SRCROOT%"].URI)
+	if err != nil || base.Scheme != "file" || base.Path != filepath.ToSlash(root)+"/" {
+		t.Fatalf("Expected the file URI of %s, got %q (%v)", root, run.OriginalURIBaseIDs["%SRCROOT%"].URI, err)
+	}
+	artifact, err := url.Parse(run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	if err != nil {
+		t.Fatalf("Invalid artifact URI: %v", err)
+	}
+	if file := base.ResolveReference(artifact); file.Path != filepath.ToSlash(filepath.Join(root, "a b", "c#d.go")) {
+		t.Errorf("Expected the artifact to resolve to c#d.go under the root, got %s", file.Path)
+	}
+}
+
+// Helper function returning rule violations as reported by the rules engine
+func violations() []*rules.Violation {
+	return []*rules.Violation{
+		{
+			Rule:     "domain-is-pure",
+			Type:     rules.RuleNoReference,
+			Element:  "domain",
+			Position: ast.Position{Filename: "/project/domain/user.go", Line: 3, Column: 8},
+			Message:  `package domain must not reference project/infra (imports "project/infra")`,
+		},
+		{
+			Rule:     rules.RuleInterfaceImplemented,
+			Type:     rules.RuleInterfaceImplemented,
+			Element:  "Notifier",
+			Position: ast.Position{Filename: "/project/domain/user.go", Line: 19, Column: 6},
+			Message:  "interface Notifier has no implementation",
+		},
+		{
+			Rule:     "domain-is-pure",
+			Type:     rules.RuleNoReference,
+			Element:  "domain",
+			Position: ast.Position{Filename: "/elsewhere/gen.go", Line: 4, Column: 8},
+			Message:  `package domain must not reference project/infra (imports "project/infra")`,
+		},
+	}
+}
+
+// Validates a SARIF log against the SARIF 2.1.0 schema and the rules of the specification the
+// schema cannot express
+func validateSARIF(t *testing.T, data []byte) {
+	t.Helper()
+
+	errs, err := loadSchema(t, filepath.Join("testdata", "sarif-schema-2.1.0.json")).validate(data)
+	if err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	for _, err := range errs {
+		t.Errorf("SARIF schema: %s", err)
+	}
+
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			OriginalURIBaseIDs map[string]struct {
+				URI string `json:"uri"`
+			} `json:"originalUriBaseIds"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("Invalid SARIF: %v", err)
+	}
+	for _, run := range log.Runs {
+		// Base URIs end with a slash (3.14.14) and are absolute (3.4.4)
+		for id, base := range run.OriginalURIBaseIDs {
+			if u, err := url.Parse(base.URI); err != nil || !u.IsAbs() || !strings.HasSuffix(base.URI, "/") {
+				t.Errorf("originalUriBaseIds[%s].uri must be an absolute URI ending with a slash, got %q", id, base.URI)
+			}
+		}
+		// The rule index points at the rule with the result's rule id (3.27.6)
+		rules := run.Tool.Driver.Rules
+		for i, result := range run.Results {
+			if result.RuleIndex < 0 || result.RuleIndex >= len(rules) || rules[result.RuleIndex].ID != result.RuleID {
+				t.Errorf("results[%d].ruleIndex %d does not match ruleId %q", i, result.RuleIndex, result.RuleID)
+			}
+		}
+	}
+}
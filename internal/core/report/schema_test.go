@@ -0,0 +1,294 @@
+package report_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// A JSON schema (draft-07) with the keywords used by the SARIF schema. Unknown keywords are
+// ignored, as the specification requires.
+type jsonSchema struct {
+	root map[string]any
+}
+
+// Helper function to load a JSON schema from a file
+func loadSchema(t *testing.T, path string) *jsonSchema {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatalf("Invalid schema %s: %v", path, err)
+	}
+	return &jsonSchema{root: root}
+}
+
+// Validates a JSON document, returning the violations prefixed by the JSON pointer of the value
+func (s *jsonSchema) validate(data []byte) ([]string, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	var errs []string
+	s.check(s.root, value, "", &errs)
+	return errs, nil
+}
+
+// Checks a value against a schema, appending the violations
+func (s *jsonSchema) check(schema map[string]any, value any, pointer string, errs *[]string) {
+	errorf := func(format string, args ...any) {
+		*errs = append(*errs, fmt.Sprintf("%s: %s", "/"+strings.TrimPrefix(pointer, "/"), fmt.Sprintf(format, args...)))
+	}
+
+	// A reference replaces the other keywords of its schema in draft-07
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil {
+			errorf("%v", err)
+			return
+		}
+		s.check(target, value, pointer, errs)
+		return
+	}
+
+	if types := schema["type"]; types != nil && !matchesType(types, value) {
+		errorf("expected type %v, got %s", types, typeName(value))
+		return
+	}
+	if enum, ok := schema["enum"].([]any); ok && !containsValue(enum, value) {
+		errorf("%v is not one of %v", value, enum)
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		errorf("expected %v, got %v", c, value)
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		subschemas, ok := schema[keyword].([]any)
+		if !ok {
+			continue
+		}
+		valid := 0
+		var failures []string
+		for _, sub := range subschemas {
+			var subErrs []string
+			s.check(sub.(map[string]any), value, pointer, &subErrs)
+			if len(subErrs) == 0 {
+				valid++
+			}
+			failures = append(failures, subErrs...)
+		}
+		switch {
+		case keyword == "allOf" && valid < len(subschemas):
+			*errs = append(*errs, failures...)
+		case keyword == "anyOf" && valid == 0:
+			errorf("matches none of anyOf: %s", strings.Join(failures, "; "))
+		case keyword == "oneOf" && valid != 1:
+			errorf("matches %d of oneOf instead of one", valid)
+		}
+	}
+	if not, ok := schema["not"].(map[string]any); ok {
+		var subErrs []string
+		s.check(not, value, pointer, &subErrs)
+		if len(subErrs) == 0 {
+			errorf("must not match %v", not)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		s.checkObject(schema, v, pointer, errs)
+	case []any:
+		s.checkArray(schema, v, pointer, errs)
+	case string:
+		if n, ok := schema["minLength"].(float64); ok && float64(len([]rune(v))) < n {
+			errorf("%q is shorter than %v", v, n)
+		}
+		if n, ok := schema["maxLength"].(float64); ok && float64(len([]rune(v))) > n {
+			errorf("%q is longer than %v", v, n)
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			errorf("%q does not match %s", v, pattern)
+		}
+		if format, ok := schema["format"].(string); ok && !matchesFormat(format, v) {
+			errorf("%q is not a valid %s", v, format)
+		}
+	case float64:
+		if n, ok := schema["minimum"].(float64); ok && v < n {
+			errorf("%v is less than %v", v, n)
+		}
+		if n, ok := schema["maximum"].(float64); ok && v > n {
+			errorf("%v is greater than %v", v, n)
+		}
+		if n, ok := schema["exclusiveMinimum"].(float64); ok && v <= n {
+			errorf("%v is not greater than %v", v, n)
+		}
+		if n, ok := schema["exclusiveMaximum"].(float64); ok && v >= n {
+			errorf("%v is not less than %v", v, n)
+		}
+	}
+}
+
+// Checks the properties of an object
+func (s *jsonSchema) checkObject(schema, obj map[string]any, pointer string, errs *[]string) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				*errs = append(*errs, fmt.Sprintf("/%s: missing required property %q", strings.TrimPrefix(pointer, "/"), name))
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	patterns, _ := schema["patternProperties"].(map[string]any)
+	for name, value := range obj {
+		child := pointer + "/" + name
+		matched := false
+		if sub, ok := properties[name].(map[string]any); ok {
+			s.check(sub, value, child, errs)
+			matched = true
+		}
+		for pattern, sub := range patterns {
+			if regexp.MustCompile(pattern).MatchString(name) {
+				s.check(sub.(map[string]any), value, child, errs)
+				matched = true
+			}
+		}
+		if matched {
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*errs = append(*errs, fmt.Sprintf("%s: property is not allowed", child))
+			}
+		case map[string]any:
+			s.check(additional, value, child, errs)
+		}
+	}
+}
+
+// Checks the items of an array
+func (s *jsonSchema) checkArray(schema map[string]any, items []any, pointer string, errs *[]string) {
+	if n, ok := schema["minItems"].(float64); ok && float64(len(items)) < n {
+		*errs = append(*errs, fmt.Sprintf("%s: fewer than %v items", pointer, n))
+	}
+	if n, ok := schema["maxItems"].(float64); ok && float64(len(items)) > n {
+		*errs = append(*errs, fmt.Sprintf("%s: more than %v items", pointer, n))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range items {
+			if containsValue(items[:i], items[i]) {
+				*errs = append(*errs, fmt.Sprintf("%s/%d: duplicate item", pointer, i))
+			}
+		}
+	}
+	switch itemSchema := schema["items"].(type) {
+	case map[string]any:
+		for i, item := range items {
+			s.check(itemSchema, item, fmt.Sprintf("%s/%d", pointer, i), errs)
+		}
+	case []any:
+		for i, item := range items {
+			if i < len(itemSchema) {
+				s.check(itemSchema[i].(map[string]any), item, fmt.Sprintf("%s/%d", pointer, i), errs)
+			}
+		}
+	}
+}
+
+// Resolves a reference within the schema document (e.g. "#/definitions/run")
+func (s *jsonSchema) resolve(ref string) (map[string]any, error) {
+	path, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+	var current any = s.root
+	for _, token := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+		current = obj[token]
+	}
+	target, ok := current.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unresolvable reference %q", ref)
+	}
+	return target, nil
+}
+
+// Checks a value against a type keyword, either a type name or a list of them
+func matchesType(types any, value any) bool {
+	names, ok := types.([]any)
+	if !ok {
+		names = []any{types}
+	}
+	for _, name := range names {
+		actual := typeName(value)
+		if name == actual || (name == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the JSON schema type of a decoded value
+func typeName(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// Checks if a list holds a value
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// URI scheme and the characters allowed in URI references (RFC 3986)
+var (
+	uriScheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+	uriChars  = regexp.MustCompile(`^([A-Za-z0-9\-._~:/?#\[\]@!$&'()*+,;=]|%[0-9A-Fa-f]{2})*$`)
+)
+
+// Checks a string against a format keyword. Formats other than URIs are not checked.
+func matchesFormat(format, s string) bool {
+	switch format {
+	case "uri":
+		return uriScheme.MatchString(s) && uriChars.MatchString(s) && strings.Count(s, "#") <= 1
+	case "uri-reference":
+		return uriChars.MatchString(s) && strings.Count(s, "#") <= 1
+	}
+	return true
+}
@@ -0,0 +1,16 @@
+package app
+
+import "example.com/project/shapes"
+
+// Sums the areas of the shapes
+func Total(items []shapes.Shape) int {
+	total := 0
+	for _, item := range items {
+		total += item.Area()
+	}
+	return total
+}
+
+type labeled struct {
+	label string
+}
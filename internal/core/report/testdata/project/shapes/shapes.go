@@ -0,0 +1,15 @@
+package shapes
+
+// Shape is implemented by every shape
+type Shape interface {
+	Area() int
+}
+
+type Square struct {
+	Side int
+}
+
+func (s *Square) Area() int { return s.Side * s.Side }
+
+// Creates a new square
+func NewSquare(side int) *Square { return &Square{Side: side} }
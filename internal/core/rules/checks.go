@@ -0,0 +1,193 @@
+package rules
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/config"
+)
+
+// Reports packages importing forbidden packages, and elements using types, functions or
+// variables declared in them
+func checkNoReference(c *checkContext, rule config.Rule) []*Violation {
+	var violations []*Violation
+	for _, pkg := range c.elementsOfType(gostructure.ElementPackage) {
+		if !c.packageMatches(pkg, rule.Packages) {
+			continue
+		}
+		deps, _ := pkg.Attributes["dependencies"].([]string)
+		for _, dep := range deps {
+			forbidden, ok := forbiddenPattern(dep, rule.Forbidden)
+			if !ok {
+				continue
+			}
+			pos, ok := c.imports[pkg.Position.Filename+"\x00"+dep]
+			if !ok {
+				pos = pkg.Position
+			}
+			violations = append(violations, &Violation{
+				Element:  pkg.Name,
+				Position: pos,
+				Message:  fmt.Sprintf("package %s must not reference %s (imports %q)", c.packageDir(pkg), forbidden, dep),
+			})
+		}
+	}
+
+	reported := make(map[[2]*gostructure.Element]bool)
+	for _, rel := range c.structure.Relationships {
+		switch rel.Type {
+		case gostructure.RelationReferences, gostructure.RelationCalls,
+			gostructure.RelationEmbeds, gostructure.RelationInterfaceEmbeds:
+		default:
+			continue
+		}
+		source, target := c.packages[rel.Source], c.packages[rel.Target]
+		if source == nil || target == nil || c.packageDir(source) == c.packageDir(target) ||
+			reported[[2]*gostructure.Element{rel.Source, rel.Target}] || !c.packageMatches(source, rule.Packages) {
+			continue
+		}
+		path := c.importPath(source, target)
+		forbidden, ok := forbiddenPattern(path, rule.Forbidden)
+		if !ok {
+			continue
+		}
+		reported[[2]*gostructure.Element{rel.Source, rel.Target}] = true
+		violations = append(violations, &Violation{
+			Element:  rel.Source.Name,
+			Position: rel.Source.Position,
+			Message:  fmt.Sprintf("%s in package %s must not reference %s (uses %s from %q)", rel.Source.Name, c.packageDir(source), forbidden, rel.Target.Name, path),
+		})
+	}
+	return violations
+}
+
+// Returns the first pattern matching an import path
+func forbiddenPattern(path string, patterns []string) (string, bool) {
+	for _, pattern := range patterns {
+		if matchPath(pattern, path) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// Reports exported interfaces without any implementation
+func checkInterfaceImplemented(c *checkContext, rule config.Rule) []*Violation {
+	implemented := make(map[*gostructure.Element]bool)
+	for _, rel := range c.structure.Relationships {
+		if rel.Type == gostructure.RelationImplements {
+			implemented[rel.Target] = true
+		}
+	}
+
+	var violations []*Violation
+	for _, iface := range c.elementsOfType(gostructure.ElementInterface) {
+		exported, _ := iface.Attributes["is_exported"].(bool)
+		if !exported || implemented[iface] || !c.elementMatches(iface, rule.Packages) {
+			continue
+		}
+		// Empty interfaces are satisfied by everything
+		if interfaceMethodCount(c, iface, make(map[*gostructure.Element]bool)) == 0 {
+			continue
+		}
+		violations = append(violations, &Violation{
+			Element:  iface.Name,
+			Position: iface.Position,
+			Message:  fmt.Sprintf("exported interface %s has no implementation", iface.Name),
+		})
+	}
+	return violations
+}
+
+// Reports interfaces with more methods (including embedded ones) than allowed
+func checkMaxInterfaceMethods(c *checkContext, rule config.Rule) []*Violation {
+	var violations []*Violation
+	for _, iface := range c.elementsOfType(gostructure.ElementInterface) {
+		if !c.elementMatches(iface, rule.Packages) {
+			continue
+		}
+		if count := interfaceMethodCount(c, iface, make(map[*gostructure.Element]bool)); count > rule.Max {
+			violations = append(violations, &Violation{
+				Element:  iface.Name,
+				Position: iface.Position,
+				Message:  fmt.Sprintf("interface %s has %d methods, max is %d", iface.Name, count, rule.Max),
+			})
+		}
+	}
+	return violations
+}
+
+// Reports types and interfaces embedding elements from another layer
+func checkNoCrossLayerEmbedding(c *checkContext, rule config.Rule) []*Violation {
+	var violations []*Violation
+	for _, rel := range c.structure.Relationships {
+		if rel.Type != gostructure.RelationEmbeds && rel.Type != gostructure.RelationInterfaceEmbeds {
+			continue
+		}
+		if !c.elementMatches(rel.Source, rule.Packages) {
+			continue
+		}
+		source, target := c.layerOf(rel.Source), c.layerOf(rel.Target)
+		if source == "" || target == "" || source == target {
+			continue
+		}
+		violations = append(violations, &Violation{
+			Element:  rel.Source.Name,
+			Position: rel.Source.Position,
+			Message:  fmt.Sprintf("%s (layer %s) embeds %s from layer %s", rel.Source.Name, source, rel.Target.Name, target),
+		})
+	}
+	return violations
+}
+
+// Counts the methods of an interface, including those of the interfaces it embeds. Embedded
+// interfaces are followed through the analyzer's embedding relationships, which resolve names
+// within the embedding package.
+func interfaceMethodCount(c *checkContext, iface *gostructure.Element, visited map[*gostructure.Element]bool) int {
+	if visited[iface] {
+		return 0
+	}
+	visited[iface] = true
+
+	methods, _ := iface.Attributes["methods"].([]map[string]any)
+	count := len(methods)
+	for _, embedded := range c.embeds[iface] {
+		count += interfaceMethodCount(c, embedded, visited)
+	}
+	return count
+}
+
+// Checks if a slash-separated path matches a pattern. The pattern may match any
+// trailing part of the path (so "internal/core" matches "codedna/internal/core"),
+// supports path.Match wildcards, and a "/..." suffix matches all subpaths.
+func matchPath(pattern, p string) bool {
+	for {
+		if matchWhole(pattern, p) {
+			return true
+		}
+		i := strings.Index(p, "/")
+		if i < 0 {
+			return false
+		}
+		p = p[i+1:]
+	}
+}
+
+// Checks if a whole path matches a pattern
+func matchWhole(pattern, p string) bool {
+	if base, ok := strings.CutSuffix(pattern, "/..."); ok {
+		if matchWhole(base, p) {
+			return true
+		}
+		for i := range len(p) {
+			if p[i] == '/' && matchWhole(base, p[:i]) {
+				return true
+			}
+		}
+		return false
+	}
+	ok, err := path.Match(pattern, p)
+	return err == nil && ok
+}
@@ -0,0 +1,217 @@
+// Package rules checks declared architecture constraints against the code structure
+package rules
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/config"
+	"codedna/internal/core/parser/ast"
+)
+
+// Supported rule types
+const (
+	RuleNoReference           = "no_reference"             // packages must not import or use forbidden packages
+	RuleInterfaceImplemented  = "interface_implemented"    // exported interfaces need an implementation
+	RuleMaxInterfaceMethods   = "max_interface_methods"    // interfaces must not exceed a method count
+	RuleNoCrossLayerEmbedding = "no_cross_layer_embedding" // types must not embed types from another layer
+)
+
+// Short descriptions of the rule types
+var Descriptions = map[string]string{
+	RuleNoReference:           "Packages must not import or use forbidden packages",
+	RuleInterfaceImplemented:  "Exported interfaces need at least one implementation",
+	RuleMaxInterfaceMethods:   "Interfaces must not exceed the maximum method count",
+	RuleNoCrossLayerEmbedding: "Types must not embed types from another layer",
+}
+
+// A rule violation found in the code
+type Violation struct {
+	Rule     string // The rule name (or type when unnamed)
+	Type     string // The rule type
+	Element  string
+	Position ast.Position
+	Message  string
+}
+
+// Checks a structure against a single rule
+type checker func(c *checkContext, rule config.Rule) []*Violation
+
+// Checkers by rule type
+var checkers = map[string]checker{
+	RuleNoReference:           checkNoReference,
+	RuleInterfaceImplemented:  checkInterfaceImplemented,
+	RuleMaxInterfaceMethods:   checkMaxInterfaceMethods,
+	RuleNoCrossLayerEmbedding: checkNoCrossLayerEmbedding,
+}
+
+// Checks a code structure against a set of rules
+type Engine struct {
+	rules *config.Rules
+}
+
+// Creates a new engine, validating the rules
+func NewEngine(rules *config.Rules) (*Engine, error) {
+	for i, rule := range rules.Rules {
+		name := ruleName(rule)
+		if _, ok := checkers[rule.Type]; !ok {
+			return nil, fmt.Errorf("rule %d (%s): unknown rule type %q", i+1, name, rule.Type)
+		}
+		switch rule.Type {
+		case RuleNoReference:
+			if len(rule.Forbidden) == 0 {
+				return nil, fmt.Errorf("rule %d (%s): no forbidden packages", i+1, name)
+			}
+		case RuleMaxInterfaceMethods:
+			if rule.Max <= 0 {
+				return nil, fmt.Errorf("rule %d (%s): max must be positive", i+1, name)
+			}
+		case RuleNoCrossLayerEmbedding:
+			if len(rules.Layers) == 0 {
+				return nil, fmt.Errorf("rule %d (%s): no layers declared", i+1, name)
+			}
+		}
+	}
+	return &Engine{rules: rules}, nil
+}
+
+// Checks the structure of the project rooted at root, returning the violations ordered by location
+func (e *Engine) Check(structure *gostructure.Structure, root string) []*Violation {
+	c := newCheckContext(structure, root, e.rules.Layers)
+
+	violations := make([]*Violation, 0)
+	for _, rule := range e.rules.Rules {
+		for _, v := range checkers[rule.Type](c, rule) {
+			v.Rule = ruleName(rule)
+			v.Type = rule.Type
+			violations = append(violations, v)
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i].Position, violations[j].Position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Line < b.Line
+	})
+	return violations
+}
+
+// Returns the name of a rule, falling back to its type
+func ruleName(rule config.Rule) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return rule.Type
+}
+
+// Lookups over the structure shared by the checkers
+type checkContext struct {
+	structure *gostructure.Structure
+	root      string
+	layers    []config.Layer
+	packages  map[*gostructure.Element]*gostructure.Element   // Element -> containing package
+	imports   map[string]ast.Position                         // "file\x00path" -> import position
+	embeds    map[*gostructure.Element][]*gostructure.Element // Interface -> embedded interfaces
+}
+
+// Builds the lookups for a structure
+func newCheckContext(structure *gostructure.Structure, root string, layers []config.Layer) *checkContext {
+	c := &checkContext{
+		structure: structure,
+		root:      root,
+		layers:    layers,
+		packages:  make(map[*gostructure.Element]*gostructure.Element),
+		imports:   make(map[string]ast.Position),
+		embeds:    make(map[*gostructure.Element][]*gostructure.Element),
+	}
+	for _, rel := range structure.Relationships {
+		switch {
+		case rel.Type == gostructure.RelationContains && rel.Source.Type == gostructure.ElementPackage:
+			c.packages[rel.Target] = rel.Source
+		case rel.Type == gostructure.RelationInterfaceEmbeds:
+			c.embeds[rel.Source] = append(c.embeds[rel.Source], rel.Target)
+		}
+	}
+	for _, elem := range structure.Elements {
+		// Imports are kept as elements carrying their path
+		if path, ok := elem.Attributes["path"].(string); ok {
+			c.imports[elem.Position.Filename+"\x00"+path] = elem.Position
+		}
+	}
+	return c
+}
+
+// Returns the directory of a package element relative to the root, in slash form
+func (c *checkContext) packageDir(pkg *gostructure.Element) string {
+	dir := filepath.Dir(pkg.Position.Filename)
+	if rel, err := filepath.Rel(c.root, dir); err == nil {
+		dir = rel
+	}
+	return filepath.ToSlash(dir)
+}
+
+// Returns the path under which the file of a package element imports the package of another,
+// or the other package's directory when the file has no matching import
+func (c *checkContext) importPath(pkg, imported *gostructure.Element) string {
+	dir := c.packageDir(imported)
+	deps, _ := pkg.Attributes["dependencies"].([]string)
+	for _, dep := range deps {
+		if dep == dir || strings.HasSuffix(dep, "/"+dir) {
+			return dep
+		}
+	}
+	return dir
+}
+
+// Checks if a package element matches any of the patterns (every package matches no patterns)
+func (c *checkContext) packageMatches(pkg *gostructure.Element, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	dir := c.packageDir(pkg)
+	for _, pattern := range patterns {
+		if pattern == pkg.Name || matchPath(pattern, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if the package containing an element matches the patterns
+func (c *checkContext) elementMatches(elem *gostructure.Element, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	pkg := c.packages[elem]
+	return pkg != nil && c.packageMatches(pkg, patterns)
+}
+
+// Returns the layer of the package containing an element, or ""
+func (c *checkContext) layerOf(elem *gostructure.Element) string {
+	pkg := c.packages[elem]
+	if pkg == nil {
+		return ""
+	}
+	for _, layer := range c.layers {
+		if c.packageMatches(pkg, layer.Packages) {
+			return layer.Name
+		}
+	}
+	return ""
+}
+
+// Returns the elements of the given type
+func (c *checkContext) elementsOfType(elemType gostructure.ElementType) []*gostructure.Element {
+	var result []*gostructure.Element
+	for _, elem := range c.structure.Elements {
+		if elem.Type == elemType {
+			result = append(result, elem)
+		}
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package rules_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"codedna/internal/core/analysis/structure"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/config"
+	goparser "codedna/internal/core/parser/golang"
+	"codedna/internal/core/rules"
+)
+
+// Helper function to analyze packages of a testdata project together
+func analyzeProject(t *testing.T, root string, dirs ...string) *gostructure.Structure {
+	t.Helper()
+
+	parser := goparser.New()
+	var nodes []structure.Node
+	for _, dir := range dirs {
+		astNodes, err := parser.ParseDir(filepath.Join(root, dir))
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", dir, err)
+		}
+		for _, node := range astNodes {
+			nodes = append(nodes, gostructure.NewNode(node))
+		}
+	}
+
+	analysis, err := gostructure.NewAnalyzer().AnalyzeAll(nodes)
+	if err != nil {
+		t.Fatalf("Failed to analyze project: %v", err)
+	}
+	return analysis.(*gostructure.Analysis).Structure
+}
+
+func TestEngine_Check(t *testing.T) {
+	ruleSet, err := config.LoadRules(filepath.Join("testdata", "rules.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load rules: %v", err)
+	}
+	engine, err := rules.NewEngine(ruleSet)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	root := filepath.Join("testdata", "project")
+	violations := engine.Check(analyzeProject(t, root, "domain", "infra"), root)
+
+	var got []string
+	for _, v := range violations {
+		got = append(got, fmt.Sprintf("%s:%d %s %s", filepath.Base(v.Position.Filename), v.Position.Line, v.Rule, v.Element))
+	}
+	expected := []string{
+		"user.go:3 domain-is-pure domain",
+		"user.go:31 domain-is-pure Archive",
+		"user.go:19 interface_implemented Notifier",
+		"user.go:23 interface_implemented Store",
+		"user.go:23 small-interfaces Store",
+		"db.go:5 no_cross_layer_embedding Record",
+	}
+	slices.Sort(got)
+	slices.Sort(expected)
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected violations:\n%v\ngot:\n%v", expected, got)
+	}
+
+	for _, v := range violations {
+		if v.Message == "" || v.Type == "" {
+			t.Errorf("Violation %s is missing its message or type", v.Rule)
+		}
+	}
+}
+
+func TestEngine_PackageScope(t *testing.T) {
+	ruleSet := &config.Rules{
+		Rules: []config.Rule{
+			{Type: rules.RuleMaxInterfaceMethods, Max: 1, Packages: []string{"infra/..."}},
+			{Type: rules.RuleNoReference, Packages: []string{"infra"}, Forbidden: []string{"project/*"}},
+		},
+	}
+	engine, err := rules.NewEngine(ruleSet)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	root := filepath.Join("testdata", "project")
+	violations := engine.Check(analyzeProject(t, root, "domain", "infra"), root)
+
+	var got []string
+	for _, v := range violations {
+		got = append(got, fmt.Sprintf("%s:%d %s %s", filepath.Base(v.Position.Filename), v.Position.Line, v.Type, v.Element))
+	}
+	expected := []string{
+		"db.go:3 no_reference infra",
+		"db.go:5 no_reference Record",
+		"db.go:11 no_reference Find",
+		"db.go:13 no_reference Save",
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected violations:\n%v\ngot:\n%v", expected, got)
+	}
+}
+
+func TestEngine_SameNamedInterfaces(t *testing.T) {
+	// Both packages declare a Reader; beta's interfaces embed beta.Reader, which has no methods
+	ruleSet := &config.Rules{
+		Rules: []config.Rule{
+			{Type: rules.RuleInterfaceImplemented},
+			{Type: rules.RuleMaxInterfaceMethods, Max: 3},
+		},
+	}
+	engine, err := rules.NewEngine(ruleSet)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	root := filepath.Join("testdata", "shadowed")
+	violations := engine.Check(analyzeProject(t, root, "alpha", "beta"), root)
+
+	var got []string
+	for _, v := range violations {
+		got = append(got, fmt.Sprintf("%s:%d %s %s", filepath.Base(filepath.Dir(v.Position.Filename)), v.Position.Line, v.Type, v.Element))
+	}
+	expected := []string{"alpha:3 max_interface_methods Reader"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected violations:\n%v\ngot:\n%v", expected, got)
+	}
+}
+
+func TestNewEngine_InvalidRules(t *testing.T) {
+	tests := map[string]*config.Rules{
+		"UnknownType":   {Rules: []config.Rule{{Type: "no_such_rule"}}},
+		"NoForbidden":   {Rules: []config.Rule{{Type: rules.RuleNoReference}}},
+		"NoMax":         {Rules: []config.Rule{{Type: rules.RuleMaxInterfaceMethods}}},
+		"LayersMissing": {Rules: []config.Rule{{Type: rules.RuleNoCrossLayerEmbedding}}},
+	}
+	for name, ruleSet := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := rules.NewEngine(ruleSet); err == nil {
+				t.Error("Expected error for invalid rules")
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "example.com/project/infra"
+
+type Entity struct {
+	ID string
+}
+
+type User struct {
+	Entity
+	Name string
+}
+
+type UserRepository interface {
+	Find(id string) (*User, error)
+	Save(user *User) error
+}
+
+type Notifier interface {
+	Notify(user *User) error
+}
+
+type Store interface {
+	UserRepository
+	Delete(id string) error
+	Count() int
+}
+
+var _ = infra.Connect
+
+func Archive(user *User) *infra.Record { return nil }
@@ -0,0 +1,15 @@
+package infra
+
+import "example.com/project/domain"
+
+type Record struct {
+	domain.Entity
+}
+
+type userStore struct{}
+
+func (s *userStore) Find(id string) (*domain.User, error) { return nil, nil }
+
+func (s *userStore) Save(user *domain.User) error { return nil }
+
+func Connect() {}
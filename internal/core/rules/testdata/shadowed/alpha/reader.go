@@ -0,0 +1,18 @@
+package alpha
+
+type Reader interface {
+	Read() []byte
+	Peek() byte
+	Skip(n int)
+	Reset()
+}
+
+type reader struct{}
+
+func (r *reader) Read() []byte { return nil }
+
+func (r *reader) Peek() byte { return 0 }
+
+func (r *reader) Skip(n int) {}
+
+func (r *reader) Reset() {}
@@ -0,0 +1,17 @@
+package beta
+
+// Reader shares its name with alpha.Reader but has no methods
+type Reader interface{}
+
+type Source interface {
+	Reader
+}
+
+type Wide interface {
+	Reader
+	Close() error
+}
+
+type file struct{}
+
+func (f file) Close() error { return nil }
@@ -1,6 +1,78 @@
 package filesystem
 
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Directories skipped while scanning
+var skippedDirs = []string{"vendor", "testdata", "node_modules"}
+
+// Checks if a directory is skipped: hidden, vendor, testdata and node_modules directories
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || slices.Contains(skippedDirs, name)
+}
+
+// Reports whether a directory is scanned under root: it lies within root and neither it nor a
+// directory between them is skipped
+func Scanned(root, dir string) bool {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	if rel == "." {
+		return true
+	}
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		if skipDir(name) {
+			return false
+		}
+	}
+	return true
+}
+
 // Scanner defines the interface for scanning project files
 type Scanner interface {
-	// TODO: Define scanning methods
+	// Returns the directories under root that contain files with one of the extensions
+	Dirs(root string, extensions []string) ([]string, error)
+}
+
+// Scans the local file system
+type LocalScanner struct{}
+
+// Creates a new local file system scanner
+func NewScanner() *LocalScanner {
+	return &LocalScanner{}
+}
+
+// Returns the directories under root that contain files with one of the extensions,
+// skipping hidden, vendor and testdata directories
+func (s *LocalScanner) Dirs(root string, extensions []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var dirs []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		if !seen[dir] && slices.Contains(extensions, filepath.Ext(path)) {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dirs, nil
 }
@@ -0,0 +1,31 @@
+package filesystem_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"codedna/internal/external/filesystem"
+)
+
+func TestScanned(t *testing.T) {
+	root := "project"
+	tests := []struct {
+		dir      string
+		expected bool
+	}{
+		{"project", true},
+		{"project/store", true},
+		{"project/store/sql", true},
+		{"project/vendor/lib", false},
+		{"project/store/testdata", false},
+		{"project/.git", false},
+		{"project/..store", false},
+		{"other", false},
+		{"project-old/store", false},
+	}
+	for _, tt := range tests {
+		if got := filesystem.Scanned(root, filepath.FromSlash(tt.dir)); got != tt.expected {
+			t.Errorf("Scanned(%q, %q) = %v, expected %v", root, tt.dir, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package filesystem
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Default quiet period before a batch of changes is reported
+const DefaultDebounce = 200 * time.Millisecond
+
+// Watches a project tree and reports the directories whose source files changed
+type Watcher struct {
+	fs         *fsnotify.Watcher
+	root       string
+	extensions []string
+	debounce   time.Duration
+	changes    chan []string
+	errors     chan error
+	done       chan struct{}
+}
+
+// Creates a watcher for the directories under root, skipping the same directories as the scanner.
+// Changes to files with one of the extensions are reported in batches once no event arrived
+// for the debounce period.
+func NewWatcher(root string, extensions []string, debounce time.Duration) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		fs:         fsWatcher,
+		root:       root,
+		extensions: extensions,
+		debounce:   debounce,
+		changes:    make(chan []string),
+		errors:     make(chan error),
+		done:       make(chan struct{}),
+	}
+	if err := w.addTree(root); err != nil {
+		fsWatcher.Close()
+		return nil, err
+	}
+	go w.loop()
+	return w, nil
+}
+
+// Returns the channel of changed directories, sorted and without duplicates
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Returns the channel of watch errors
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Stops watching
+func (w *Watcher) Close() error {
+	select {
+	case <-w.done:
+		return nil
+	default:
+	}
+	close(w.done)
+	return w.fs.Close()
+}
+
+// Watches a directory and its subdirectories
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != w.root && skipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		return w.fs.Add(path)
+	})
+}
+
+// Collects events into batches until the watcher is closed
+func (w *Watcher) loop() {
+	pending := make(map[string]bool)
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			timer.Stop()
+			return
+
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			if dirs := w.handle(event); len(dirs) > 0 {
+				for _, dir := range dirs {
+					pending[dir] = true
+				}
+				timer.Reset(w.debounce)
+			}
+
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case w.errors <- err:
+			case <-w.done:
+				return
+			}
+
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			dirs := make([]string, 0, len(pending))
+			for dir := range pending {
+				dirs = append(dirs, dir)
+			}
+			sort.Strings(dirs)
+			pending = make(map[string]bool)
+			select {
+			case w.changes <- dirs:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// Returns the directories affected by an event, watching newly created directories
+func (w *Watcher) handle(event fsnotify.Event) []string {
+	if skipDir(filepath.Base(event.Name)) {
+		return nil
+	}
+	if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+		return nil
+	}
+
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			// Files created together with the directory may predate the watch,
+			// so every directory of the new tree is reported
+			var dirs []string
+			_ = filepath.WalkDir(event.Name, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || !d.IsDir() {
+					return nil
+				}
+				if path != event.Name && skipDir(d.Name()) {
+					return filepath.SkipDir
+				}
+				if err := w.fs.Add(path); err == nil {
+					dirs = append(dirs, path)
+				}
+				return nil
+			})
+			return dirs
+		}
+	}
+
+	if (event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)) && filepath.Ext(event.Name) == "" {
+		// Possibly a removed directory, which can no longer be inspected
+		return []string{event.Name}
+	}
+	if !slices.Contains(w.extensions, filepath.Ext(event.Name)) {
+		return nil
+	}
+	return []string{filepath.Dir(event.Name)}
+}
@@ -0,0 +1,155 @@
+package filesystem_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"codedna/internal/external/filesystem"
+)
+
+// Debounce period used by the tests, long enough for a burst of writes to land in one batch
+const testDebounce = 100 * time.Millisecond
+
+// Helper function to start watching a directory for Go files
+func startWatcher(t *testing.T, root string) *filesystem.Watcher {
+	t.Helper()
+
+	w, err := filesystem.NewWatcher(root, []string{".go"}, testDebounce)
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+// Helper function to write a file, creating its directory
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte("package p\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// Helper function to collect the batches reported until none arrives for the given time
+func collectChanges(t *testing.T, w *filesystem.Watcher, quiet time.Duration) [][]string {
+	t.Helper()
+
+	var batches [][]string
+	for {
+		select {
+		case dirs := <-w.Changes():
+			batches = append(batches, dirs)
+		case err := <-w.Errors():
+			t.Fatalf("Unexpected watch error: %v", err)
+		case <-time.After(quiet):
+			return batches
+		}
+	}
+}
+
+// Helper function to flatten batches into the set of reported directories
+func reported(batches [][]string) []string {
+	var dirs []string
+	for _, batch := range batches {
+		for _, dir := range batch {
+			if !slices.Contains(dirs, dir) {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	slices.Sort(dirs)
+	return dirs
+}
+
+func TestWatcher_Debounce(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "api")
+	store := filepath.Join(root, "store")
+	for _, dir := range []string{api, store} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	w := startWatcher(t, root)
+
+	// A burst of writes within the debounce period is reported once, sorted and deduplicated
+	for i := range 5 {
+		writeFile(t, filepath.Join(api, "api.go"))
+		writeFile(t, filepath.Join(store, "store.go"))
+		if i == 2 {
+			writeFile(t, filepath.Join(api, "handler.go"))
+		}
+	}
+	batches := collectChanges(t, w, 5*testDebounce)
+	if len(batches) != 1 {
+		t.Fatalf("Expected a single batch, got %v", batches)
+	}
+	if expected := []string{api, store}; !slices.Equal(batches[0], expected) {
+		t.Errorf("Expected %v, got %v", expected, batches[0])
+	}
+}
+
+func TestWatcher_IgnoredFiles(t *testing.T) {
+	root := t.TempDir()
+	w := startWatcher(t, root)
+
+	// Other extensions and skipped directories are not reported
+	writeFile(t, filepath.Join(root, "notes.txt"))
+	writeFile(t, filepath.Join(root, ".git", "config.go"))
+	writeFile(t, filepath.Join(root, "vendor", "lib", "lib.go"))
+	if batches := collectChanges(t, w, 5*testDebounce); len(batches) != 0 {
+		t.Errorf("Expected no changes, got %v", batches)
+	}
+}
+
+func TestWatcher_Directories(t *testing.T) {
+	root := t.TempDir()
+	w := startWatcher(t, root)
+	pkg := filepath.Join(root, "pkg")
+	sub := filepath.Join(pkg, "sub")
+
+	t.Run("Created", func(t *testing.T) {
+		// Files created together with the directories are reported through the new tree
+		writeFile(t, filepath.Join(sub, "sub.go"))
+		dirs := reported(collectChanges(t, w, 5*testDebounce))
+		if !slices.Contains(dirs, pkg) || !slices.Contains(dirs, sub) {
+			t.Errorf("Expected %s and %s, got %v", pkg, sub, dirs)
+		}
+	})
+
+	t.Run("WatchedAfterCreation", func(t *testing.T) {
+		writeFile(t, filepath.Join(sub, "more.go"))
+		if dirs := reported(collectChanges(t, w, 5*testDebounce)); !slices.Equal(dirs, []string{sub}) {
+			t.Errorf("Expected [%s], got %v", sub, dirs)
+		}
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		if err := os.RemoveAll(pkg); err != nil {
+			t.Fatalf("Failed to remove %s: %v", pkg, err)
+		}
+		if dirs := reported(collectChanges(t, w, 5*testDebounce)); !slices.Contains(dirs, pkg) {
+			t.Errorf("Expected %s, got %v", pkg, dirs)
+		}
+	})
+}
+
+func TestWatcher_Close(t *testing.T) {
+	w, err := filesystem.NewWatcher(t.TempDir(), []string{".go"}, testDebounce)
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Failed to close watcher: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Expected closing twice to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,609 @@
+// Package lsp speaks the Language Server Protocol over stdio to show DNA feedback in editors:
+// diagnostics for rule violations and convention deviations, hovers with an element's
+// relationships and code lenses with fan-in counts.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	goconvention "codedna/internal/core/analysis/convention/golang"
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/config"
+	"codedna/internal/core/parser/ast"
+	"codedna/internal/core/rules"
+	"codedna/internal/external/filesystem"
+
+	"go.uber.org/zap"
+)
+
+// Name of the server reported to clients and used as the diagnostic source
+const serverName = "codedna"
+
+// Default quiet period after a document or file change before the workspace is analyzed again
+const DefaultDebounce = 200 * time.Millisecond
+
+// Parses and analyzes the project rooted at a directory into a workspace that can be updated
+type LoadFunc func(root string) (*gostructure.Workspace, error)
+
+// Serves LSP requests for a single workspace. Requests are answered on the read loop from the
+// latest analysis, while changes are analyzed on a separate loop once they settle: only the
+// packages of changed files are parsed again, taking open documents from their unsaved text.
+type Server struct {
+	load     LoadFunc
+	logger   *zap.Logger
+	conn     *conn
+	debounce time.Duration
+
+	root     string
+	shutdown bool
+	changed  chan struct{} // Wakes the analysis loop after a change
+
+	mu        sync.Mutex                        // Guards the fields below, shared by both loops
+	documents map[string]string                 // Open documents: file path -> text
+	dirty     map[string]bool                   // Package directories to parse again
+	files     map[string][]*gostructure.Element // Absolute file path -> elements declared in it
+	incoming  map[*gostructure.Element][]*gostructure.Relationship
+	outgoing  map[*gostructure.Element][]*gostructure.Relationship
+
+	// Only used by the analysis loop
+	workspace *gostructure.Workspace
+	published map[string]bool // Files with published diagnostics
+}
+
+// Creates a new language server
+func New(load LoadFunc, logger *zap.Logger) *Server {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Server{
+		load:      load,
+		logger:    logger,
+		debounce:  DefaultDebounce,
+		changed:   make(chan struct{}, 1),
+		documents: make(map[string]string),
+		dirty:     make(map[string]bool),
+		published: make(map[string]bool),
+	}
+}
+
+// Sets the quiet period after a change before the workspace is analyzed again
+func (s *Server) SetDebounce(debounce time.Duration) {
+	s.debounce = debounce
+}
+
+// Serves requests read from r, writing responses to w, until the client exits
+func (s *Server) Run(r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.analyzeLoop(done)
+	}()
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
+
+	for {
+		msg, err := s.conn.read()
+		if err != nil {
+			var rpcErr *responseError
+			if errors.As(err, &rpcErr) {
+				_ = s.conn.replyError(nil, rpcErr.Code, rpcErr.Message)
+				continue
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit without shutdown")
+			}
+			return nil
+		}
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// Dispatches a request or notification
+func (s *Server) handle(msg *message) error {
+	s.logger.Debug("Received message", zap.String("method", msg.Method))
+
+	// Notifications have no id and get no response
+	if msg.ID == nil {
+		switch msg.Method {
+		case "textDocument/didOpen":
+			var params didOpenParams
+			if json.Unmarshal(msg.Params, &params) == nil {
+				path := uriToPath(params.TextDocument.URI)
+				s.change(path, func() { s.documents[path] = params.TextDocument.Text })
+			}
+		case "textDocument/didChange":
+			var params didChangeParams
+			if json.Unmarshal(msg.Params, &params) == nil && len(params.ContentChanges) > 0 {
+				path := uriToPath(params.TextDocument.URI)
+				changes := params.ContentChanges
+				s.change(path, func() { s.documents[path] = changes[len(changes)-1].Text })
+			}
+		case "textDocument/didSave":
+			var params documentParams
+			if json.Unmarshal(msg.Params, &params) == nil {
+				s.change(uriToPath(params.TextDocument.URI), nil)
+			}
+		case "textDocument/didClose":
+			// The file is analyzed from disk again, dropping unsaved changes
+			var params documentParams
+			if json.Unmarshal(msg.Params, &params) == nil {
+				path := uriToPath(params.TextDocument.URI)
+				s.change(path, func() { delete(s.documents, path) })
+			}
+		case "workspace/didChangeWatchedFiles":
+			var params didChangeWatchedFilesParams
+			if json.Unmarshal(msg.Params, &params) == nil {
+				for _, change := range params.Changes {
+					s.change(uriToPath(change.URI), nil)
+				}
+			}
+		}
+		return nil
+	}
+
+	if s.shutdown && msg.Method != "shutdown" {
+		return s.conn.replyError(msg.ID, codeInvalidRequest, "server is shutting down")
+	}
+
+	switch msg.Method {
+	case "initialize":
+		var params initializeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return s.conn.replyError(msg.ID, codeInvalidParams, err.Error())
+		}
+		s.root = params.RootPath
+		if params.RootURI != "" {
+			s.root = uriToPath(params.RootURI)
+		}
+		return s.conn.reply(msg.ID, initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncOptions{OpenClose: true, Change: 1, Save: saveOptions{}},
+				HoverProvider:    true,
+				CodeLensProvider: codeLensOptions{ResolveProvider: false},
+			},
+			ServerInfo: serverInfo{Name: serverName},
+		})
+
+	case "shutdown":
+		s.shutdown = true
+		return s.conn.reply(msg.ID, nil)
+
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return s.conn.replyError(msg.ID, codeInvalidParams, err.Error())
+		}
+		s.mu.Lock()
+		h := s.hover(uriToPath(params.TextDocument.URI), params.Position)
+		s.mu.Unlock()
+		if h != nil {
+			return s.conn.reply(msg.ID, h)
+		}
+		return s.conn.reply(msg.ID, nil)
+
+	case "textDocument/codeLens":
+		var params documentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return s.conn.replyError(msg.ID, codeInvalidParams, err.Error())
+		}
+		s.mu.Lock()
+		lenses := s.codeLenses(uriToPath(params.TextDocument.URI))
+		s.mu.Unlock()
+		return s.conn.reply(msg.ID, lenses)
+	}
+
+	return s.conn.replyError(msg.ID, codeMethodNotFound, fmt.Sprintf("method %q not supported", msg.Method))
+}
+
+// Records a change to a file, applying an update to the open documents, and wakes the analysis
+// loop. The package of a Go file under the root is parsed again; other files, such as the rules
+// file, only refresh the diagnostics.
+func (s *Server) change(path string, update func()) {
+	s.mu.Lock()
+	if update != nil {
+		update()
+	}
+	var dir string
+	switch filepath.Ext(path) {
+	case ".go":
+		dir = filepath.Dir(path)
+	case "":
+		dir = path // Possibly a removed package directory
+	}
+	if dir != "" && s.root != "" && filesystem.Scanned(s.root, dir) {
+		s.dirty[dir] = true
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Analyzes changes once none arrived for the debounce period, until done is closed
+func (s *Server) analyzeLoop(done <-chan struct{}) {
+	timer := time.NewTimer(s.debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-done:
+			timer.Stop()
+			return
+		case <-s.changed:
+			timer.Reset(s.debounce)
+		case <-timer.C:
+			if err := s.refresh(); err != nil {
+				s.logger.Warn("Failed to publish diagnostics", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Updates the workspace with the pending changes and republishes diagnostics
+func (s *Server) refresh() error {
+	if s.root == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	dirty := s.dirty
+	s.dirty = make(map[string]bool)
+	overlay := make(map[string][]byte, len(s.documents))
+	for path, text := range s.documents {
+		overlay[absolute(path)] = []byte(text)
+	}
+	s.mu.Unlock()
+
+	if s.workspace == nil {
+		workspace, err := s.load(s.root)
+		if err != nil {
+			s.logger.Warn("Analysis failed", zap.Error(err))
+			return s.conn.notify("window/showMessage", showMessageParams{Type: messageWarning, Message: "codedna: " + err.Error()})
+		}
+		s.workspace = workspace
+
+		// The workspace was loaded from disk, so the packages of open documents are parsed again
+		for path := range overlay {
+			if filepath.Ext(path) == ".go" && filesystem.Scanned(s.root, filepath.Dir(path)) {
+				dirty[filepath.Dir(path)] = true
+			}
+		}
+	}
+
+	analysis := s.workspace.Analysis()
+	if len(dirty) > 0 {
+		dirs := make([]string, 0, len(dirty))
+		for dir := range dirty {
+			dirs = append(dirs, dir)
+		}
+		sort.Strings(dirs)
+
+		s.workspace.SetOverlay(overlay)
+		updated, err := s.workspace.Update(dirs)
+		if err != nil {
+			// Unsaved documents often fail to parse while being edited, so the failure is only
+			// logged; the packages are parsed again with the next change
+			s.logger.Debug("Analysis failed", zap.Error(err))
+			s.mu.Lock()
+			for dir := range dirty {
+				s.dirty[dir] = true
+			}
+			s.mu.Unlock()
+			return nil
+		}
+		analysis = updated
+	}
+
+	diagnostics := s.diagnostics(analysis.Structure)
+	s.mu.Lock()
+	s.index(analysis)
+	s.mu.Unlock()
+	return s.publishDiagnostics(diagnostics)
+}
+
+// Indexes an analysis by file and relationship endpoints. The caller holds mu.
+func (s *Server) index(analysis *gostructure.Analysis) {
+	s.files = make(map[string][]*gostructure.Element)
+	s.incoming = make(map[*gostructure.Element][]*gostructure.Relationship)
+	s.outgoing = make(map[*gostructure.Element][]*gostructure.Relationship)
+
+	for _, elem := range analysis.Structure.Elements {
+		if elem.Name == "" || elem.Type == gostructure.ElementPackage {
+			continue
+		}
+		file := absolute(elem.Position.Filename)
+		s.files[file] = append(s.files[file], elem)
+	}
+	for _, rel := range analysis.Structure.Relationships {
+		s.incoming[rel.Target] = append(s.incoming[rel.Target], rel)
+		s.outgoing[rel.Source] = append(s.outgoing[rel.Source], rel)
+	}
+}
+
+// Publishes the diagnostics of every file, clearing files that no longer have any
+func (s *Server) publishDiagnostics(byFile map[string][]diagnostic) error {
+	for file := range s.published {
+		if _, ok := byFile[file]; !ok {
+			byFile[file] = make([]diagnostic, 0)
+		}
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	s.published = make(map[string]bool)
+	for _, file := range files {
+		diagnostics := byFile[file]
+		if len(diagnostics) > 0 {
+			s.published[file] = true
+		}
+		if err := s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         pathToURI(file),
+			Diagnostics: diagnostics,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Collects rule violations and convention deviations by file
+func (s *Server) diagnostics(structure *gostructure.Structure) map[string][]diagnostic {
+	result := make(map[string][]diagnostic)
+	add := func(pos ast.Position, severity int, code, text string) {
+		file := absolute(pos.Filename)
+		result[file] = append(result[file], diagnostic{
+			Range:    lineRange(pos),
+			Severity: severity,
+			Code:     code,
+			Source:   serverName,
+			Message:  text,
+		})
+	}
+
+	rulesFile := filepath.Join(s.root, config.DefaultRulesFile)
+	if _, err := os.Stat(rulesFile); err == nil {
+		engine, err := s.rulesEngine(rulesFile)
+		if err != nil {
+			s.logger.Warn("Invalid rules", zap.Error(err))
+			_ = s.conn.notify("window/showMessage", showMessageParams{Type: messageError, Message: "codedna: " + err.Error()})
+		} else {
+			for _, v := range engine.Check(structure, s.root) {
+				add(v.Position, severityError, v.Rule, v.Message)
+			}
+		}
+	}
+
+	for _, convention := range goconvention.NewNamingAnalyzer().Analyze(structure) {
+		for _, d := range convention.Deviations {
+			add(d.Position, severityInformation, string(d.Rule), d.Message)
+		}
+	}
+	for _, convention := range goconvention.NewTagAnalyzer().Analyze(structure) {
+		for _, d := range convention.Deviations {
+			add(d.Position, severityInformation, "tag_"+string(d.Kind), d.Message)
+		}
+	}
+	return result
+}
+
+// Loads the rules engine from a rules file
+func (s *Server) rulesEngine(path string) (*rules.Engine, error) {
+	ruleSet, err := config.LoadRules(path)
+	if err != nil {
+		return nil, err
+	}
+	return rules.NewEngine(ruleSet)
+}
+
+// Describes the relationships of the element at a position
+func (s *Server) hover(file string, pos position) *hover {
+	elem := s.elementAt(file, pos)
+	if elem == nil {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s %s**\n", elem.Type, elem.Name)
+	sections := []struct {
+		title    string
+		relType  gostructure.RelationType
+		incoming bool
+	}{
+		{"Implements", gostructure.RelationImplements, false},
+		{"Implemented by", gostructure.RelationImplements, true},
+		{"Embeds", gostructure.RelationEmbeds, false},
+		{"Embedded by", gostructure.RelationEmbeds, true},
+		{"Embeds interfaces", gostructure.RelationInterfaceEmbeds, false},
+		{"Embedded in interfaces", gostructure.RelationInterfaceEmbeds, true},
+		{"Methods", gostructure.RelationMethodReceiver, true},
+		{"Referenced by", gostructure.RelationReferences, true},
+		{"Called by", gostructure.RelationCalls, true},
+	}
+	for _, section := range sections {
+		names := s.related(elem, section.relType, section.incoming)
+		if len(names) > 0 {
+			fmt.Fprintf(&b, "\n- %s: %s", section.title, strings.Join(names, ", "))
+		}
+	}
+
+	r := nameRange(elem, s.documents[file])
+	return &hover{Contents: markupContent{Kind: "markdown", Value: b.String()}, Range: &r}
+}
+
+// Returns the sorted, distinct names of the elements related to an element
+func (s *Server) related(elem *gostructure.Element, relType gostructure.RelationType, incoming bool) []string {
+	rels := s.outgoing[elem]
+	if incoming {
+		rels = s.incoming[elem]
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, rel := range rels {
+		if rel.Type != relType {
+			continue
+		}
+		other := rel.Target
+		if incoming {
+			other = rel.Source
+		}
+		if other.Name != "" && !seen[other.Name] {
+			seen[other.Name] = true
+			names = append(names, "`"+other.Name+"`")
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Relationship types counted as fan-in
+var fanInTypes = map[gostructure.RelationType]bool{
+	gostructure.RelationImplements: true,
+	gostructure.RelationEmbeds:     true,
+	gostructure.RelationReferences: true,
+	gostructure.RelationCalls:      true,
+}
+
+// Returns a lens with the fan-in count above each declaration in a file
+func (s *Server) codeLenses(file string) []codeLens {
+	lenses := make([]codeLens, 0)
+	for _, elem := range s.files[file] {
+		switch elem.Type {
+		case gostructure.ElementInterface, gostructure.ElementTypeDecl, gostructure.ElementFunction, gostructure.ElementMethod:
+		default:
+			continue
+		}
+
+		// Fan-in counts the distinct elements depending on this one
+		dependents := make(map[*gostructure.Element]bool)
+		for _, rel := range s.incoming[elem] {
+			if fanInTypes[rel.Type] {
+				dependents[rel.Source] = true
+			}
+		}
+		title := fmt.Sprintf("fan-in: %d", len(dependents))
+		if elem.Type == gostructure.ElementInterface {
+			title += fmt.Sprintf(" · implementations: %d", len(s.related(elem, gostructure.RelationImplements, true)))
+		}
+		lenses = append(lenses, codeLens{
+			Range:   lineRange(elem.Position),
+			Command: &command{Title: title},
+		})
+	}
+	return lenses
+}
+
+// Returns the element declared at a position, preferring the one named by the word under the cursor
+func (s *Server) elementAt(file string, pos position) *gostructure.Element {
+	word := wordAt(s.documents[file], pos)
+	var onLine *gostructure.Element
+	for _, elem := range s.files[file] {
+		if elem.Position.Line-1 != pos.Line {
+			continue
+		}
+		if elem.Name == word {
+			return elem
+		}
+		if onLine == nil {
+			onLine = elem
+		}
+	}
+	if word == "" {
+		return onLine
+	}
+	return nil
+}
+
+// Returns the identifier at a position in a document
+func wordAt(text string, pos position) string {
+	lines := strings.Split(text, "\n")
+	if pos.Line < 0 || pos.Line >= len(lines) {
+		return ""
+	}
+	line := lines[pos.Line]
+	if pos.Character < 0 || pos.Character > len(line) {
+		return ""
+	}
+	isIdent := func(c byte) bool {
+		return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+	start, end := pos.Character, pos.Character
+	for start > 0 && isIdent(line[start-1]) {
+		start--
+	}
+	for end < len(line) && isIdent(line[end]) {
+		end++
+	}
+	return line[start:end]
+}
+
+// Returns the range of an element's name, falling back to its position
+func nameRange(elem *gostructure.Element, text string) lspRange {
+	r := lineRange(elem.Position)
+	lines := strings.Split(text, "\n")
+	if line := elem.Position.Line - 1; line >= 0 && line < len(lines) {
+		if i := strings.Index(lines[line], elem.Name); i >= 0 {
+			r.Start.Character = i
+			r.End.Character = i + len(elem.Name)
+		}
+	}
+	return r
+}
+
+// Returns a range from a position to the end of its line
+func lineRange(pos ast.Position) lspRange {
+	line := max(pos.Line-1, 0)
+	start := position{Line: line, Character: max(pos.Column-1, 0)}
+	return lspRange{Start: start, End: position{Line: line + 1, Character: 0}}
+}
+
+// Converts a file URI to a path
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// Converts a path to a file URI
+func pathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// Returns the absolute form of a path
+func absolute(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,381 @@
+package lsp_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/external/lsp"
+)
+
+// Quiet period used by the tests before changes are analyzed
+const testDebounce = 20 * time.Millisecond
+
+// Helper function loading the single package in a directory into a workspace
+func loadDir(root string) (*gostructure.Workspace, error) {
+	workspace := gostructure.NewWorkspace(gostructure.NewAnalyzer())
+	if _, err := workspace.Update([]string{root}); err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// A minimal LSP client talking to a server over pipes
+type client struct {
+	t      *testing.T
+	writer io.WriteCloser
+	reader *bufio.Reader
+	nextID int
+	done   chan error
+}
+
+// A received message
+type received struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Helper function starting a server connected to a client
+func startServer(t *testing.T, load lsp.LoadFunc) *client {
+	t.Helper()
+
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+	c := &client{t: t, writer: clientOut, reader: bufio.NewReader(clientIn), done: make(chan error, 1)}
+	server := lsp.New(load, nil)
+	server.SetDebounce(testDebounce)
+	go func() {
+		err := server.Run(serverIn, serverOut)
+		serverOut.Close()
+		c.done <- err
+	}()
+	return c
+}
+
+// Sends a message
+func (c *client) send(id *int, method string, params any) {
+	c.t.Helper()
+
+	msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+	if id != nil {
+		msg["id"] = *id
+	}
+	body, _ := json.Marshal(msg)
+	if _, err := fmt.Fprintf(c.writer, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatalf("Failed to send %s: %v", method, err)
+	}
+}
+
+// Reads the body of the next message
+func (c *client) receiveBody() []byte {
+	c.t.Helper()
+
+	header, err := textproto.NewReader(c.reader).ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatalf("Failed to read header: %v", err)
+	}
+	length, _ := strconv.Atoi(header.Get("Content-Length"))
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, body); err != nil {
+		c.t.Fatalf("Failed to read body: %v", err)
+	}
+	return body
+}
+
+// Reads the next message
+func (c *client) receive() *received {
+	c.t.Helper()
+
+	body := c.receiveBody()
+	var msg received
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatalf("Invalid message %s: %v", body, err)
+	}
+	return &msg
+}
+
+// Sends a request and returns its response, collecting notifications sent in between
+func (c *client) request(method string, params any, notifications *[]*received) *received {
+	c.t.Helper()
+
+	c.nextID++
+	id := c.nextID
+	c.send(&id, method, params)
+	for {
+		msg := c.receive()
+		if msg.ID != nil && *msg.ID == id {
+			return msg
+		}
+		if notifications != nil {
+			*notifications = append(*notifications, msg)
+		}
+	}
+}
+
+// Helper function returning the file URI of a path
+func fileURI(t *testing.T, path string) string {
+	t.Helper()
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+}
+
+func TestServer(t *testing.T) {
+	root := filepath.Join("testdata", "project")
+	file := fileURI(t, filepath.Join(root, "shapes.go"))
+	text := mustRead(t, filepath.Join(root, "shapes.go"))
+
+	c := startServer(t, loadDir)
+
+	resp := c.request("initialize", map[string]any{"rootUri": fileURI(t, root)}, nil)
+	var init struct {
+		Capabilities struct {
+			HoverProvider    bool            `json:"hoverProvider"`
+			CodeLensProvider json.RawMessage `json:"codeLensProvider"`
+		} `json:"capabilities"`
+	}
+	if err := json.Unmarshal(resp.Result, &init); err != nil || !init.Capabilities.HoverProvider || init.Capabilities.CodeLensProvider == nil {
+		t.Fatalf("Unexpected initialize result: %s", resp.Result)
+	}
+	c.send(nil, "initialized", map[string]any{})
+
+	// Opening a document analyzes the workspace and publishes diagnostics
+	c.send(nil, "textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": file, "languageId": "go", "version": 1, "text": text},
+	})
+
+	t.Run("diagnostics", func(t *testing.T) {
+		expected := []string{
+			"3 1 small-interfaces",      // rule violation on Shape
+			"21 3 receiver_consistency", // the "sq" receiver deviates from "s"
+		}
+		if got := c.diagnostics(file); strings.Join(got, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected diagnostics:\n%v\ngot:\n%v", expected, got)
+		}
+	})
+
+	t.Run("hover", func(t *testing.T) {
+		resp := c.request("textDocument/hover", map[string]any{
+			"textDocument": map[string]any{"uri": file},
+			"position":     map[string]any{"line": 12, "character": 7},
+		}, nil)
+		var h struct {
+			Contents struct{ Value string } `json:"contents"`
+			Range    struct {
+				Start struct{ Character int } `json:"start"`
+			} `json:"range"`
+		}
+		if err := json.Unmarshal(resp.Result, &h); err != nil {
+			t.Fatal(err)
+		}
+		for _, fragment := range []string{"**type Square**", "Implements: `Shape`", "Embeds: `Base`", "Methods: `Area`, `Double`, `Perimeter`, `Scale`"} {
+			if !strings.Contains(h.Contents.Value, fragment) {
+				t.Errorf("Expected hover to contain %q, got:\n%s", fragment, h.Contents.Value)
+			}
+		}
+		if h.Range.Start.Character != 5 {
+			t.Errorf("Expected the hover range to start at the name, got %d", h.Range.Start.Character)
+		}
+
+		resp = c.request("textDocument/hover", map[string]any{
+			"textDocument": map[string]any{"uri": file},
+			"position":     map[string]any{"line": 1, "character": 0},
+		}, nil)
+		if string(resp.Result) != "null" {
+			t.Errorf("Expected no hover on a blank line, got %s", resp.Result)
+		}
+	})
+
+	t.Run("code lens", func(t *testing.T) {
+		resp := c.request("textDocument/codeLens", map[string]any{"textDocument": map[string]any{"uri": file}}, nil)
+		var lenses []struct {
+			Range struct {
+				Start struct{ Line int } `json:"start"`
+			} `json:"range"`
+			Command struct{ Title string } `json:"command"`
+		}
+		if err := json.Unmarshal(resp.Result, &lenses); err != nil {
+			t.Fatal(err)
+		}
+		titles := make(map[int]string)
+		for _, lens := range lenses {
+			titles[lens.Range.Start.Line] = lens.Command.Title
+		}
+		if titles[3] != "fan-in: 1 · implementations: 1" {
+			t.Errorf("Expected Shape lens with one implementation, got %q", titles[3])
+		}
+		if titles[8] != "fan-in: 1" {
+			t.Errorf("Expected Base to be embedded once, got %q", titles[8])
+		}
+	})
+
+	t.Run("unsaved changes", func(t *testing.T) {
+		// Unsaved text is analyzed without waiting for a save
+		edited := strings.Replace(text, "func (sq *Square) Scale(n int) { sq.Side *= n }", "func (s *Square) Scale(n int) { s.Side *= n }", 1)
+		c.send(nil, "textDocument/didChange", map[string]any{
+			"textDocument":   map[string]any{"uri": file, "version": 2},
+			"contentChanges": []map[string]any{{"text": edited}},
+		})
+		if got := c.diagnostics(file); strings.Join(got, "\n") != "3 1 small-interfaces" {
+			t.Errorf("Expected the fixed receiver to clear its diagnostic, got:\n%v", got)
+		}
+
+		// Closing the document drops the unsaved text
+		c.send(nil, "textDocument/didClose", map[string]any{"textDocument": map[string]any{"uri": file}})
+		if got := c.diagnostics(file); len(got) != 2 {
+			t.Errorf("Expected the diagnostics of the file on disk, got:\n%v", got)
+		}
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		resp := c.request("textDocument/definition", map[string]any{}, nil)
+		if resp.Error == nil || resp.Error.Code != -32601 {
+			t.Errorf("Expected method not found, got %+v", resp)
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		resp := c.request("shutdown", nil, nil)
+		if resp.Error != nil {
+			t.Fatalf("Shutdown failed: %+v", resp.Error)
+		}
+		c.send(nil, "exit", nil)
+		select {
+		case err := <-c.done:
+			if err != nil {
+				t.Errorf("Expected a clean exit, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Server did not exit")
+		}
+	})
+}
+
+func TestServer_AnalysisOffRequestLoop(t *testing.T) {
+	root := filepath.Join("testdata", "project")
+	file := fileURI(t, filepath.Join(root, "shapes.go"))
+
+	// The analysis blocks until released, while requests keep being answered
+	started := make(chan struct{})
+	release := make(chan struct{})
+	c := startServer(t, func(root string) (*gostructure.Workspace, error) {
+		close(started)
+		<-release
+		return loadDir(root)
+	})
+	c.request("initialize", map[string]any{"rootUri": fileURI(t, root)}, nil)
+	c.send(nil, "textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": file, "languageId": "go", "version": 1, "text": mustRead(t, filepath.Join(root, "shapes.go"))},
+	})
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Analysis did not start")
+	}
+
+	responses := make(chan *received, 1)
+	go func() {
+		responses <- c.request("textDocument/codeLens", map[string]any{"textDocument": map[string]any{"uri": file}}, nil)
+	}()
+	select {
+	case resp := <-responses:
+		if string(resp.Result) != "[]" {
+			t.Errorf("Expected no lenses before the analysis finished, got %s", resp.Result)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the request to be answered during the analysis")
+	}
+	close(release)
+	if got := c.diagnostics(file); len(got) != 2 {
+		t.Errorf("Expected diagnostics once the analysis finished, got:\n%v", got)
+	}
+}
+
+func TestServer_NullID(t *testing.T) {
+	c := startServer(t, loadDir)
+
+	// A message that is not JSON gets an error response with a null id
+	body := "{not json"
+	if _, err := fmt.Fprintf(c.writer, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		t.Fatal(err)
+	}
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(c.receiveBody(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := resp["id"]; !ok || string(id) != "null" {
+		t.Errorf("Expected \"id\": null, got %s", id)
+	}
+	if _, ok := resp["error"]; !ok {
+		t.Error("Expected an error response")
+	}
+}
+
+// Reads notifications until diagnostics for a file arrive, returning them as "line severity code"
+func (c *client) diagnostics(uri string) []string {
+	c.t.Helper()
+
+	for {
+		msg := c.receive()
+		if msg.Method != "textDocument/publishDiagnostics" {
+			c.t.Fatalf("Expected diagnostics, got %s", msg.Method)
+		}
+		var params struct {
+			URI         string `json:"uri"`
+			Diagnostics []struct {
+				Range struct {
+					Start struct{ Line int } `json:"start"`
+				} `json:"range"`
+				Severity int    `json:"severity"`
+				Code     string `json:"code"`
+				Source   string `json:"source"`
+				Message  string `json:"message"`
+			} `json:"diagnostics"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			c.t.Fatal(err)
+		}
+		if params.URI != uri {
+			continue
+		}
+
+		got := make([]string, 0, len(params.Diagnostics))
+		for _, d := range params.Diagnostics {
+			got = append(got, fmt.Sprintf("%d %d %s", d.Range.Start.Line, d.Severity, d.Code))
+			if d.Source != "codedna" || d.Message == "" {
+				c.t.Errorf("Diagnostic %s lacks source or message", d.Code)
+			}
+		}
+		return got
+	}
+}
+
+// Helper function to read a file
+func mustRead(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
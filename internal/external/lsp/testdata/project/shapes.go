@@ -0,0 +1,24 @@
+package shapes
+
+// Shape is implemented by every shape
+type Shape interface {
+	Area() int
+	Perimeter() int
+}
+
+type Base struct {
+	ID int
+}
+
+type Square struct {
+	Base
+	Side int
+}
+
+func (s *Square) Area() int { return s.Side * s.Side }
+
+func (s *Square) Perimeter() int { return 4 * s.Side }
+
+func (sq *Square) Scale(n int) { sq.Side *= n }
+
+func (s *Square) Double() { s.Scale(2) }
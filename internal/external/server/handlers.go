@@ -0,0 +1,405 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+	"codedna/internal/core/query"
+)
+
+// Summary of a kept analysis
+type analysisResponse struct {
+	ID            string    `json:"id"`
+	Path          string    `json:"path"`
+	Language      string    `json:"language"`
+	CreatedAt     time.Time `json:"created_at"`
+	DurationMs    int64     `json:"duration_ms"`
+	Elements      int       `json:"elements"`
+	Relationships int       `json:"relationships"`
+}
+
+// A page of items
+type pageResponse[T any] struct {
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+	Items  []T `json:"items"`
+}
+
+type positionResponse struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+type elementResponse struct {
+	ID         int               `json:"id"` // Index of the element within its analysis
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Package    string            `json:"package,omitempty"`
+	Position   positionResponse  `json:"position"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// A compact reference to an element
+type elementRef struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type relationshipResponse struct {
+	Type   string     `json:"type"`
+	Source elementRef `json:"source"`
+	Target elementRef `json:"target"`
+}
+
+type queryRequest struct {
+	Query string `json:"query"`
+}
+
+type queryResponse struct {
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	Truncated bool     `json:"truncated"` // Rows beyond MaxLimit were left out
+}
+
+type diffResponse struct {
+	From                 string                 `json:"from"`
+	To                   string                 `json:"to"`
+	AddedElements        []elementResponse      `json:"added_elements"`
+	RemovedElements      []elementResponse      `json:"removed_elements"`
+	AddedRelationships   []relationshipResponse `json:"added_relationships"`
+	RemovedRelationships []relationshipResponse `json:"removed_relationships"`
+}
+
+// Analyzes the path in the request body
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path string `json:"path"`
+	}
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if req.Path == "" {
+		writeError(w, http.StatusBadRequest, "path is required")
+		return
+	}
+
+	id, err := s.Analyze(req.Path)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "analysis failed: %v", err)
+		return
+	}
+	e, _ := s.entry(id)
+	w.Header().Set("Location", "/api/analyses/"+id)
+	writeJSON(w, http.StatusCreated, e.summary())
+}
+
+// Lists the kept analyses in creation order
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	summaries := make([]analysisResponse, 0, len(s.order))
+	for _, id := range s.order {
+		summaries = append(summaries, s.analyses[id].summary())
+	}
+	s.mu.RUnlock()
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// Returns the summary of an analysis
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	if e, ok := s.lookup(w, r.PathValue("id")); ok {
+		writeJSON(w, http.StatusOK, e.summary())
+	}
+}
+
+// Drops an analysis
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, ok := s.lookup(w, id); !ok {
+		return
+	}
+	s.mu.Lock()
+	delete(s.analyses, id)
+	for i, existing := range s.order {
+		if existing == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Lists elements, filtered by type, name (substring) and package name
+func (s *Server) handleElements(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.lookup(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	offset, limit, err := pagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	params := r.URL.Query()
+	elemType, name, pkg := params.Get("type"), params.Get("name"), params.Get("package")
+	withAttributes := params.Get("attributes") == "true"
+
+	var matches []*gostructure.Element
+	for _, elem := range e.analysis.Structure.Elements {
+		if elemType != "" && string(elem.Type) != elemType {
+			continue
+		}
+		if name != "" && !strings.Contains(elem.Name, name) {
+			continue
+		}
+		if pkg != "" && e.packageName(elem) != pkg {
+			continue
+		}
+		matches = append(matches, elem)
+	}
+
+	items := make([]elementResponse, 0, limit)
+	for _, elem := range page(matches, offset, limit) {
+		items = append(items, e.element(elem, withAttributes))
+	}
+	writeJSON(w, http.StatusOK, pageResponse[elementResponse]{Total: len(matches), Offset: offset, Limit: limit, Items: items})
+}
+
+// Lists relationships, filtered by type and source or target name
+func (s *Server) handleRelationships(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.lookup(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	offset, limit, err := pagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	params := r.URL.Query()
+	relType, source, target := params.Get("type"), params.Get("source"), params.Get("target")
+
+	var matches []*gostructure.Relationship
+	for _, rel := range e.analysis.Structure.Relationships {
+		if relType != "" && string(rel.Type) != relType {
+			continue
+		}
+		if source != "" && rel.Source.Name != source {
+			continue
+		}
+		if target != "" && rel.Target.Name != target {
+			continue
+		}
+		matches = append(matches, rel)
+	}
+
+	items := make([]relationshipResponse, 0, limit)
+	for _, rel := range page(matches, offset, limit) {
+		items = append(items, e.relationship(rel))
+	}
+	writeJSON(w, http.StatusOK, pageResponse[relationshipResponse]{Total: len(matches), Offset: offset, Limit: limit, Items: items})
+}
+
+// Metrics reported by the metrics endpoint
+var metricTypes = []gostructure.MetricType{
+	gostructure.MetricTotalElements, gostructure.MetricPackages, gostructure.MetricTypes,
+	gostructure.MetricFunctions, gostructure.MetricMethods, gostructure.MetricInterfaces,
+	gostructure.MetricVariables, gostructure.MetricContains, gostructure.MetricImplements,
+	gostructure.MetricEmbeds, gostructure.MetricInterfaceEmbeds, gostructure.MetricMethodReceiver,
+	gostructure.MetricCalls, gostructure.MetricReferences, gostructure.MetricMaxDepth,
+	gostructure.MetricAvgDepth, gostructure.MetricMaxChildren, gostructure.MetricAvgChildren,
+}
+
+// Returns the structure metrics and detector timings of an analysis
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.lookup(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	collector := gostructure.NewMetricsCollector()
+	collector.CollectMetrics(e.analysis.Structure)
+	metrics := make(map[string]int, len(metricTypes))
+	for _, metric := range metricTypes {
+		metrics[string(metric)] = collector.Metric(metric)
+	}
+	timings := make(map[string]float64, len(e.analysis.DetectorTimings))
+	for name, d := range e.analysis.DetectorTimings {
+		timings[name] = float64(d.Microseconds()) / 1000
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"metrics": metrics, "detector_timings_ms": timings})
+}
+
+// Runs a query against an analysis
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.lookup(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	var req queryRequest
+	if err := decodeBody(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	q, err := query.Parse(req.Query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid query: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.queryTimeout)
+	defer cancel()
+	result, err := q.EvaluateContext(ctx, e.analysis.Structure, MaxLimit)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "query stopped: %v", err)
+		return
+	}
+	resp := queryResponse{Columns: result.Columns, Rows: make([][]any, 0, len(result.Rows)), Truncated: result.Truncated}
+	for _, row := range result.Rows {
+		cells := make([]any, len(row))
+		for i, cell := range row {
+			switch v := cell.(type) {
+			case *gostructure.Element:
+				cells[i] = e.element(v, false)
+			case int, bool, string, nil:
+				cells[i] = v
+			default:
+				cells[i] = query.FormatCell(v)
+			}
+		}
+		resp.Rows = append(resp.Rows, cells)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Returns the differences between two analyses
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	if params.Get("from") == "" || params.Get("to") == "" {
+		writeError(w, http.StatusBadRequest, "from and to are required")
+		return
+	}
+	from, ok := s.lookup(w, params.Get("from"))
+	if !ok {
+		return
+	}
+	to, ok := s.lookup(w, params.Get("to"))
+	if !ok {
+		return
+	}
+
+	diff := gostructure.DiffStructures(from.analysis.Structure, to.analysis.Structure)
+	resp := diffResponse{
+		From:                 from.id,
+		To:                   to.id,
+		AddedElements:        make([]elementResponse, 0, len(diff.AddedElements)),
+		RemovedElements:      make([]elementResponse, 0, len(diff.RemovedElements)),
+		AddedRelationships:   make([]relationshipResponse, 0, len(diff.AddedRelationships)),
+		RemovedRelationships: make([]relationshipResponse, 0, len(diff.RemovedRelationships)),
+	}
+	for _, elem := range diff.AddedElements {
+		resp.AddedElements = append(resp.AddedElements, to.element(elem, false))
+	}
+	for _, elem := range diff.RemovedElements {
+		resp.RemovedElements = append(resp.RemovedElements, from.element(elem, false))
+	}
+	for _, rel := range diff.AddedRelationships {
+		resp.AddedRelationships = append(resp.AddedRelationships, to.relationship(rel))
+	}
+	for _, rel := range diff.RemovedRelationships {
+		resp.RemovedRelationships = append(resp.RemovedRelationships, from.relationship(rel))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Returns a kept analysis, writing a not found response when there is none
+func (s *Server) lookup(w http.ResponseWriter, id string) (*entry, bool) {
+	e, ok := s.entry(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "analysis %q not found", id)
+	}
+	return e, ok
+}
+
+// Returns the summary of the analysis
+func (e *entry) summary() analysisResponse {
+	return analysisResponse{
+		ID:            e.id,
+		Path:          e.path,
+		Language:      e.analysis.Language(),
+		CreatedAt:     e.createdAt,
+		DurationMs:    e.duration.Milliseconds(),
+		Elements:      len(e.analysis.Structure.Elements),
+		Relationships: len(e.analysis.Structure.Relationships),
+	}
+}
+
+// Returns the name of the package containing an element
+func (e *entry) packageName(elem *gostructure.Element) string {
+	if elem.Type == gostructure.ElementPackage {
+		return elem.Name
+	}
+	if pkg := e.packages[elem]; pkg != nil {
+		return pkg.Name
+	}
+	return ""
+}
+
+// Converts an element, optionally with its scalar attributes
+func (e *entry) element(elem *gostructure.Element, withAttributes bool) elementResponse {
+	resp := elementResponse{
+		ID:       e.index[elem],
+		Type:     string(elem.Type),
+		Name:     elem.Name,
+		Package:  e.packageName(elem),
+		Position: position(elem.Position),
+	}
+	if withAttributes {
+		resp.Attributes = attributes(elem)
+	}
+	return resp
+}
+
+// Converts a relationship
+func (e *entry) relationship(rel *gostructure.Relationship) relationshipResponse {
+	return relationshipResponse{
+		Type:   string(rel.Type),
+		Source: elementRef{ID: e.index[rel.Source], Type: string(rel.Source.Type), Name: rel.Source.Name},
+		Target: elementRef{ID: e.index[rel.Target], Type: string(rel.Target.Type), Name: rel.Target.Name},
+	}
+}
+
+func position(pos ast.Position) positionResponse {
+	return positionResponse{File: pos.Filename, Line: pos.Line, Column: pos.Column}
+}
+
+// Returns the attributes of an element that have a compact text form
+func attributes(elem *gostructure.Element) map[string]string {
+	result := make(map[string]string)
+	for key, value := range elem.Attributes {
+		switch v := value.(type) {
+		case string, bool, int, []string:
+			result[key] = query.FormatCell(v)
+		case *goparser.TypeInfo:
+			if v != nil {
+				result[key] = v.String()
+			}
+		case fmt.Stringer:
+			result[key] = v.String()
+		}
+	}
+	return result
+}
@@ -0,0 +1,276 @@
+// Package server exposes CodeDNA analyses over a local HTTP JSON API.
+//
+// Endpoints:
+//
+//	POST   /api/analyses                       analyze a path: {"path": "..."}
+//	GET    /api/analyses                       list analyses
+//	GET    /api/analyses/{id}                  analysis summary
+//	DELETE /api/analyses/{id}                  drop an analysis
+//	GET    /api/analyses/{id}/elements         elements (type, name, package, offset, limit, attributes)
+//	GET    /api/analyses/{id}/relationships    relationships (type, source, target, offset, limit)
+//	GET    /api/analyses/{id}/metrics          structure metrics
+//	POST   /api/analyses/{id}/query            run a query: {"query": "MATCH ..."} (at most MaxLimit rows)
+//	GET    /api/diff?from={id}&to={id}         differences between two analyses
+//
+// The API is meant for local tools only. It answers requests addressed to a loopback host
+// (defeating DNS rebinding), rejects cross-origin browser requests and request bodies that are
+// not JSON, and keeps a bounded number of analyses, dropping the oldest first. Queries are stopped
+// when the client goes away or they run longer than the query timeout.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+
+	"go.uber.org/zap"
+)
+
+// Pagination limits
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Number of analyses kept by default
+const DefaultMaxAnalyses = 32
+
+// Time a query may run by default before it is stopped
+const DefaultQueryTimeout = 30 * time.Second
+
+// Analyzes the project at a path
+type AnalyzeFunc func(path string) (*gostructure.Analysis, error)
+
+// An analysis kept by the server
+type entry struct {
+	id        string
+	path      string
+	createdAt time.Time
+	duration  time.Duration
+	analysis  *gostructure.Analysis
+	index     map[*gostructure.Element]int                  // Element -> position in the structure
+	packages  map[*gostructure.Element]*gostructure.Element // Element -> containing package
+}
+
+// Serves the JSON API
+type Server struct {
+	analyze AnalyzeFunc
+	logger  *zap.Logger
+	mux     *http.ServeMux
+
+	mu          sync.RWMutex
+	analyses    map[string]*entry
+	order       []string // Analysis ids in creation order
+	nextID      int
+	maxAnalyses int
+
+	queryTimeout time.Duration
+}
+
+// Creates a new server analyzing paths with the given function
+func New(analyze AnalyzeFunc, logger *zap.Logger) *Server {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	s := &Server{
+		analyze:     analyze,
+		logger:      logger,
+		mux:         http.NewServeMux(),
+		analyses:    make(map[string]*entry),
+		maxAnalyses: DefaultMaxAnalyses,
+
+		queryTimeout: DefaultQueryTimeout,
+	}
+
+	s.mux.HandleFunc("POST /api/analyses", s.handleCreate)
+	s.mux.HandleFunc("GET /api/analyses", s.handleList)
+	s.mux.HandleFunc("GET /api/analyses/{id}", s.handleGet)
+	s.mux.HandleFunc("DELETE /api/analyses/{id}", s.handleDelete)
+	s.mux.HandleFunc("GET /api/analyses/{id}/elements", s.handleElements)
+	s.mux.HandleFunc("GET /api/analyses/{id}/relationships", s.handleRelationships)
+	s.mux.HandleFunc("GET /api/analyses/{id}/metrics", s.handleMetrics)
+	s.mux.HandleFunc("POST /api/analyses/{id}/query", s.handleQuery)
+	s.mux.HandleFunc("GET /api/diff", s.handleDiff)
+	return s
+}
+
+// Sets the number of analyses kept before the oldest are dropped
+func (s *Server) SetMaxAnalyses(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxAnalyses = max(n, 1)
+	s.evict()
+}
+
+// Sets the time a query may run before it is stopped. Call it before serving requests.
+func (s *Server) SetQueryTimeout(d time.Duration) {
+	s.queryTimeout = d
+}
+
+// Handles an HTTP request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	switch {
+	case !isLoopbackHost(r.Host):
+		writeError(w, http.StatusForbidden, "host %q is not a loopback address", r.Host)
+	case !sameOrigin(r):
+		writeError(w, http.StatusForbidden, "cross-origin requests are not allowed")
+	case r.Method == http.MethodPost && !isJSON(r):
+		writeError(w, http.StatusUnsupportedMediaType, "request body must be application/json")
+	default:
+		s.mux.ServeHTTP(w, r)
+	}
+	s.logger.Debug("Handled request",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.Duration("duration", time.Since(start)))
+}
+
+// Analyzes a path and keeps the result, returning its id
+func (s *Server) Analyze(path string) (string, error) {
+	start := time.Now()
+	analysis, err := s.analyze(path)
+	if err != nil {
+		return "", err
+	}
+
+	e := &entry{
+		path:      path,
+		createdAt: time.Now(),
+		duration:  time.Since(start),
+		analysis:  analysis,
+		index:     make(map[*gostructure.Element]int, len(analysis.Structure.Elements)),
+		packages:  make(map[*gostructure.Element]*gostructure.Element),
+	}
+	for i, elem := range analysis.Structure.Elements {
+		e.index[elem] = i
+	}
+	for _, rel := range analysis.Structure.Relationships {
+		if rel.Type == gostructure.RelationContains && rel.Source.Type == gostructure.ElementPackage {
+			e.packages[rel.Target] = rel.Source
+		}
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	e.id = strconv.Itoa(s.nextID)
+	s.analyses[e.id] = e
+	s.order = append(s.order, e.id)
+	s.evict()
+	s.mu.Unlock()
+
+	s.logger.Info("Analyzed project",
+		zap.String("id", e.id),
+		zap.String("path", path),
+		zap.Duration("duration", e.duration))
+	return e.id, nil
+}
+
+// Drops the oldest analyses beyond the maximum; the caller holds the lock
+func (s *Server) evict() {
+	for len(s.order) > s.maxAnalyses {
+		delete(s.analyses, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// Returns a kept analysis
+func (s *Server) entry(id string) (*entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.analyses[id]
+	return e, ok
+}
+
+// An error response body
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(body)
+}
+
+// Writes a JSON error response
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, errorResponse{Error: fmt.Sprintf(format, args...)})
+}
+
+// Checks if a request host (with an optional port) names the loopback interface
+func isLoopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Checks that a browser request, which carries an Origin, comes from the server's own origin
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Scheme == "http" && u.Host == r.Host
+}
+
+// Checks if a request declares a JSON body
+func isJSON(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// Decodes a JSON request body
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// Parses the offset and limit query parameters
+func pagination(r *http.Request) (offset, limit int, err error) {
+	offset, limit = 0, DefaultLimit
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > MaxLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+	}
+	return offset, limit, nil
+}
+
+// Returns the page of items selected by offset and limit
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return make([]T, 0)
+	}
+	return items[offset:min(offset+limit, len(items))]
+}
@@ -0,0 +1,326 @@
+package server_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	goparser "codedna/internal/core/parser/golang"
+	"codedna/internal/external/server"
+)
+
+const shapesV1 = `package shapes
+
+type Shape interface {
+	Area() int
+}
+
+type Square struct {
+	Side int
+}
+
+func (s *Square) Area() int { return s.Side * s.Side }
+
+type Rect struct {
+	Width, Height int
+}
+
+func NewSquare(side int) *Square { return &Square{Side: side} }
+`
+
+const shapesV2 = shapesV1 + `
+func (r Rect) Area() int { return r.Width * r.Height }
+`
+
+// Helper function analyzing the single shapes.go file in a directory
+func analyzeDir(path string) (*gostructure.Analysis, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.New("no such directory")
+	}
+	node, err := goparser.New().ParseFile(filepath.Join(path, "shapes.go"))
+	if err != nil {
+		return nil, err
+	}
+	analysis, err := gostructure.NewAnalyzer().Analyze(gostructure.NewNode(node))
+	if err != nil {
+		return nil, err
+	}
+	return analysis.(*gostructure.Analysis), nil
+}
+
+// Helper function to send a request and decode the JSON response
+func do(t *testing.T, handler http.Handler, method, target, body string, expectedStatus int, out any) {
+	t.Helper()
+
+	var req *http.Request
+	if body != "" {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(method, target, nil)
+	}
+	req.Host = "127.0.0.1:7420"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != expectedStatus {
+		t.Fatalf("%s %s: expected status %d, got %d: %s", method, target, expectedStatus, rec.Code, rec.Body)
+	}
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: invalid JSON response: %v", method, target, err)
+		}
+	}
+}
+
+type element struct {
+	ID      int    `json:"id"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Package string `json:"package"`
+}
+
+type elementPage struct {
+	Total int       `json:"total"`
+	Items []element `json:"items"`
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "shapes.go"), []byte(shapesV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := server.New(analyzeDir, nil)
+
+	var created struct {
+		ID       string `json:"id"`
+		Language string `json:"language"`
+		Elements int    `json:"elements"`
+	}
+	do(t, srv, "POST", "/api/analyses", `{"path": "`+dir+`"}`, http.StatusCreated, &created)
+	if created.ID != "1" || created.Language != "go" || created.Elements == 0 {
+		t.Fatalf("Unexpected analysis summary: %+v", created)
+	}
+
+	t.Run("list", func(t *testing.T) {
+		var list []struct{ ID string }
+		do(t, srv, "GET", "/api/analyses", "", http.StatusOK, &list)
+		if len(list) != 1 || list[0].ID != "1" {
+			t.Errorf("Expected one analysis, got %+v", list)
+		}
+	})
+
+	t.Run("elements", func(t *testing.T) {
+		var result elementPage
+		do(t, srv, "GET", "/api/analyses/1/elements?type=type", "", http.StatusOK, &result)
+		if result.Total != 2 || len(result.Items) != 2 {
+			t.Fatalf("Expected 2 types, got %+v", result)
+		}
+		for _, item := range result.Items {
+			if item.Package != "shapes" {
+				t.Errorf("Expected package shapes for %s, got %q", item.Name, item.Package)
+			}
+		}
+
+		do(t, srv, "GET", "/api/analyses/1/elements?name=Square&type=function", "", http.StatusOK, &result)
+		if result.Total != 1 || result.Items[0].Name != "NewSquare" {
+			t.Errorf("Expected NewSquare, got %+v", result)
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		var all, second elementPage
+		do(t, srv, "GET", "/api/analyses/1/elements", "", http.StatusOK, &all)
+		do(t, srv, "GET", "/api/analyses/1/elements?offset=1&limit=2", "", http.StatusOK, &second)
+		if second.Total != all.Total || len(second.Items) != 2 || second.Items[0].ID != all.Items[1].ID {
+			t.Errorf("Expected the second page to start at the second element, got %+v", second)
+		}
+
+		var past elementPage
+		do(t, srv, "GET", "/api/analyses/1/elements?offset=1000", "", http.StatusOK, &past)
+		if past.Items == nil || len(past.Items) != 0 {
+			t.Errorf("Expected an empty page past the end, got %+v", past)
+		}
+
+		do(t, srv, "GET", "/api/analyses/1/elements?limit=0", "", http.StatusBadRequest, nil)
+		do(t, srv, "GET", "/api/analyses/1/elements?offset=-1", "", http.StatusBadRequest, nil)
+	})
+
+	t.Run("relationships", func(t *testing.T) {
+		var result struct {
+			Total int `json:"total"`
+			Items []struct {
+				Type   string  `json:"type"`
+				Source element `json:"source"`
+				Target element `json:"target"`
+			} `json:"items"`
+		}
+		do(t, srv, "GET", "/api/analyses/1/relationships?type=implements", "", http.StatusOK, &result)
+		if result.Total != 1 || result.Items[0].Source.Name != "Square" || result.Items[0].Target.Name != "Shape" {
+			t.Errorf("Expected Square implements Shape, got %+v", result)
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		var result struct {
+			Metrics map[string]int `json:"metrics"`
+		}
+		do(t, srv, "GET", "/api/analyses/1/metrics", "", http.StatusOK, &result)
+		if result.Metrics["interfaces"] != 1 || result.Metrics["types"] != 2 || result.Metrics["implements"] != 1 {
+			t.Errorf("Unexpected metrics: %v", result.Metrics)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		var result struct {
+			Columns []string `json:"columns"`
+			Rows    [][]any  `json:"rows"`
+		}
+		do(t, srv, "POST", "/api/analyses/1/query",
+			`{"query": "MATCH (t)-[:implements]->(i:interface) RETURN t.name, i"}`, http.StatusOK, &result)
+		if len(result.Rows) != 1 || result.Rows[0][0] != "Square" {
+			t.Fatalf("Expected one row for Square, got %+v", result)
+		}
+		if iface, ok := result.Rows[0][1].(map[string]any); !ok || iface["name"] != "Shape" {
+			t.Errorf("Expected the interface element, got %v", result.Rows[0][1])
+		}
+
+		do(t, srv, "POST", "/api/analyses/1/query", `{"query": "MATCH (t"}`, http.StatusBadRequest, nil)
+	})
+
+	t.Run("query limits", func(t *testing.T) {
+		// Rows beyond the maximum page size are left out
+		var result struct {
+			Rows      [][]any `json:"rows"`
+			Truncated bool    `json:"truncated"`
+		}
+		do(t, srv, "POST", "/api/analyses/1/query",
+			`{"query": "MATCH (a), (b), (c), (d) RETURN a.name, b.name, c.name, d.name"}`, http.StatusOK, &result)
+		if len(result.Rows) != server.MaxLimit || !result.Truncated {
+			t.Errorf("Expected %d rows with more left out, got %d (truncated %t)", server.MaxLimit, len(result.Rows), result.Truncated)
+		}
+
+		// Queries running past the timeout are stopped
+		slow := server.New(analyzeDir, nil)
+		slow.SetQueryTimeout(time.Nanosecond)
+		do(t, slow, "POST", "/api/analyses", `{"path": "`+dir+`"}`, http.StatusCreated, nil)
+		do(t, slow, "POST", "/api/analyses/1/query", `{"query": "MATCH (a)-[*]-(b) RETURN a, b"}`, http.StatusServiceUnavailable, nil)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(dir, "shapes.go"), []byte(shapesV2), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		do(t, srv, "POST", "/api/analyses", `{"path": "`+dir+`"}`, http.StatusCreated, nil)
+
+		var result struct {
+			AddedElements      []element `json:"added_elements"`
+			RemovedElements    []element `json:"removed_elements"`
+			AddedRelationships []struct {
+				Type string `json:"type"`
+			} `json:"added_relationships"`
+		}
+		do(t, srv, "GET", "/api/diff?from=1&to=2", "", http.StatusOK, &result)
+		if len(result.AddedElements) != 1 || result.AddedElements[0].Name != "Area" || len(result.RemovedElements) != 0 {
+			t.Errorf("Expected the Rect.Area method to be added, got %+v", result)
+		}
+		var types []string
+		for _, rel := range result.AddedRelationships {
+			types = append(types, rel.Type)
+		}
+		if !strings.Contains(strings.Join(types, ","), "implements") {
+			t.Errorf("Expected an added implements relationship, got %v", types)
+		}
+
+		do(t, srv, "GET", "/api/diff?from=1", "", http.StatusBadRequest, nil)
+		do(t, srv, "GET", "/api/diff?from=1&to=7", "", http.StatusNotFound, nil)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		do(t, srv, "GET", "/api/analyses/42", "", http.StatusNotFound, nil)
+		do(t, srv, "POST", "/api/analyses", `{}`, http.StatusBadRequest, nil)
+		do(t, srv, "POST", "/api/analyses", `{"path": 1}`, http.StatusBadRequest, nil)
+		do(t, srv, "POST", "/api/analyses", `{"path": "/does/not/exist"}`, http.StatusUnprocessableEntity, nil)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		do(t, srv, "DELETE", "/api/analyses/1", "", http.StatusNoContent, nil)
+		do(t, srv, "GET", "/api/analyses/1", "", http.StatusNotFound, nil)
+		var list []struct{ ID string }
+		do(t, srv, "GET", "/api/analyses", "", http.StatusOK, &list)
+		if len(list) != 1 || list[0].ID != "2" {
+			t.Errorf("Expected only analysis 2 to remain, got %+v", list)
+		}
+	})
+}
+
+func TestServer_LocalOnly(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "shapes.go"), []byte(shapesV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := server.New(analyzeDir, nil)
+	body := `{"path": "` + dir + `"}`
+
+	tests := []struct {
+		name        string
+		host        string
+		origin      string
+		contentType string
+		status      int
+	}{
+		{"Loopback", "127.0.0.1:7420", "", "application/json", http.StatusCreated},
+		{"Localhost", "localhost:7420", "", "application/json; charset=utf-8", http.StatusCreated},
+		{"IPv6Loopback", "[::1]:7420", "", "application/json", http.StatusCreated},
+		{"SameOrigin", "127.0.0.1:7420", "http://127.0.0.1:7420", "application/json", http.StatusCreated},
+		{"ReboundHost", "attacker.example:7420", "", "application/json", http.StatusForbidden},
+		{"CrossOrigin", "127.0.0.1:7420", "http://attacker.example", "application/json", http.StatusForbidden},
+		{"PlainText", "127.0.0.1:7420", "", "text/plain", http.StatusUnsupportedMediaType},
+		{"NoContentType", "127.0.0.1:7420", "", "", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/analyses", strings.NewReader(body))
+			req.Host = tt.host
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestServer_MaxAnalyses(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "shapes.go"), []byte(shapesV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := server.New(analyzeDir, nil)
+	srv.SetMaxAnalyses(2)
+	for range 3 {
+		if _, err := srv.Analyze(dir); err != nil {
+			t.Fatalf("Failed to analyze: %v", err)
+		}
+	}
+
+	var list []struct{ ID string }
+	do(t, srv, "GET", "/api/analyses", "", http.StatusOK, &list)
+	if len(list) != 2 || list[0].ID != "2" || list[1].ID != "3" {
+		t.Errorf("Expected analyses 2 and 3 to be kept, got %+v", list)
+	}
+	do(t, srv, "GET", "/api/analyses/1", "", http.StatusNotFound, nil)
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// A schema change, applied once in order of version
+type migration struct {
+	version uint64
+	name    string
+	apply   func(tx *bolt.Tx) error
+}
+
+// Schema migrations; append new ones with the next version and never change applied ones
+var migrations = []migration{
+	{version: 1, name: "create buckets", apply: createBuckets(
+		bucketRuns, bucketElements, bucketRelationships, bucketProfiles, bucketCommits, bucketCommitRuns,
+	)},
+}
+
+// Returns the latest schema version
+func latestVersion() uint64 {
+	return migrations[len(migrations)-1].version
+}
+
+// Applies the pending migrations, each in its own transaction
+func migrate(db *bolt.DB) error {
+	for _, m := range migrations {
+		err := db.Update(func(tx *bolt.Tx) error {
+			meta, err := tx.CreateBucketIfNotExists(bucketMeta)
+			if err != nil {
+				return err
+			}
+			current := schemaVersion(tx)
+			if current > latestVersion() {
+				return fmt.Errorf("schema version %d is newer than supported version %d", current, latestVersion())
+			}
+			if current >= m.version {
+				return nil
+			}
+			if err := m.apply(tx); err != nil {
+				return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+			}
+			return meta.Put(keySchemaVersion, itob(m.version))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the schema version recorded in the store, 0 for a new store
+func schemaVersion(tx *bolt.Tx) uint64 {
+	meta := tx.Bucket(bucketMeta)
+	if meta == nil {
+		return 0
+	}
+	if v := meta.Get(keySchemaVersion); len(v) == 8 {
+		return btoi(v)
+	}
+	return 0
+}
+
+// Returns a migration creating top-level buckets
+func createBuckets(names ...[]byte) func(tx *bolt.Tx) error {
+	return func(tx *bolt.Tx) error {
+		for _, name := range names {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/parser/ast"
+	goparser "codedna/internal/core/parser/golang"
+)
+
+// An analysis run kept in the store
+type Run struct {
+	ID               uint64                         `json:"id"`
+	Root             string                         `json:"root"`
+	Commit           string                         `json:"commit,omitempty"` // Commit the run was taken at, if known
+	CreatedAt        time.Time                      `json:"created_at"`
+	Duration         time.Duration                  `json:"duration"`
+	Elements         int                            `json:"elements"`
+	Relationships    int                            `json:"relationships"`
+	Metrics          map[gostructure.MetricType]int `json:"metrics"`
+	Generated        gostructure.GeneratedCode      `json:"generated,omitempty"`         // Treatment of generated code in the metrics, include if unset
+	GeneratedMetrics map[gostructure.MetricType]int `json:"generated_metrics,omitempty"` // Metrics of generated code, when reported separately
+}
+
+// A version control commit runs can be attached to
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author,omitempty"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message,omitempty"`
+}
+
+// The value of a metric in a run
+type MetricPoint struct {
+	RunID  uint64    `json:"run_id"`
+	Commit string    `json:"commit,omitempty"`
+	Time   time.Time `json:"time"`
+	Value  int       `json:"value"`
+}
+
+// A stored element
+type elementRecord struct {
+	Language   string                  `json:"language"`
+	Type       gostructure.ElementType `json:"type"`
+	Name       string                  `json:"name"`
+	Scope      string                  `json:"scope,omitempty"`
+	Position   ast.Position            `json:"position"`
+	Attributes map[string]attribute    `json:"attributes,omitempty"`
+}
+
+// A stored relationship, referring to elements by index
+type relationshipRecord struct {
+	Type   gostructure.RelationType `json:"type"`
+	Source int                      `json:"source"`
+	Target int                      `json:"target"`
+}
+
+// Kinds of stored attribute values
+const (
+	kindString  = "string"
+	kindBool    = "bool"
+	kindInt     = "int"
+	kindStrings = "strings"
+	kindType    = "type"
+)
+
+// A stored attribute value with its kind, so it decodes to the original Go type
+type attribute struct {
+	Kind  string          `json:"kind"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Converts an element, keeping the attributes that have a storable form
+func newElementRecord(elem *gostructure.Element) (*elementRecord, error) {
+	record := &elementRecord{Language: elem.Language, Type: elem.Type, Name: elem.Name, Scope: elem.Scope, Position: elem.Position}
+	for key, value := range elem.Attributes {
+		var kind string
+		switch v := value.(type) {
+		case string:
+			kind = kindString
+		case bool:
+			kind = kindBool
+		case int:
+			kind = kindInt
+		case []string:
+			kind = kindStrings
+		case *goparser.TypeInfo:
+			if v == nil {
+				continue
+			}
+			kind = kindType
+		default:
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode attribute %s of %s: %w", key, elem.Name, err)
+		}
+		if record.Attributes == nil {
+			record.Attributes = make(map[string]attribute)
+		}
+		record.Attributes[key] = attribute{Kind: kind, Value: data}
+	}
+	return record, nil
+}
+
+// Converts the record back into an element
+func (r *elementRecord) element() (*gostructure.Element, error) {
+	elem := &gostructure.Element{
+		Language:   r.Language,
+		Type:       r.Type,
+		Name:       r.Name,
+		Scope:      r.Scope,
+		Position:   r.Position,
+		Attributes: make(map[string]any, len(r.Attributes)),
+	}
+	for key, attr := range r.Attributes {
+		var value any
+		var err error
+		switch attr.Kind {
+		case kindString:
+			value, err = decode[string](attr.Value)
+		case kindBool:
+			value, err = decode[bool](attr.Value)
+		case kindInt:
+			value, err = decode[int](attr.Value)
+		case kindStrings:
+			value, err = decode[[]string](attr.Value)
+		case kindType:
+			value, err = decode[*goparser.TypeInfo](attr.Value)
+		default:
+			err = fmt.Errorf("unknown kind %q", attr.Kind)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode attribute %s of %s: %w", key, r.Name, err)
+		}
+		elem.Attributes[key] = value
+	}
+	return elem, nil
+}
+
+// Decodes a JSON value
+func decode[T any](data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
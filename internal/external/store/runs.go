@@ -0,0 +1,324 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/dna"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Saves an analysis run with its structure and optional DNA profile, assigning the run id.
+// The element and relationship counts and the metrics are taken from the analysis, treating
+// generated code as the run's Generated mode says.
+func (s *Store) SaveRun(run *Run, analysis *gostructure.Analysis, profile *dna.Profile) error {
+	structure := analysis.Structure
+	if run.Generated == "" {
+		run.Generated = gostructure.GeneratedInclude
+	}
+	collector := gostructure.NewMetricsCollector()
+	collector.SetGenerated(run.Generated)
+	collector.CollectMetrics(structure)
+	run.Metrics = collector.Metrics()
+	run.GeneratedMetrics = nil
+	if run.Generated == gostructure.GeneratedSeparate {
+		run.GeneratedMetrics = collector.GeneratedMetrics()
+	}
+	run.Elements = len(structure.Elements)
+	run.Relationships = len(structure.Relationships)
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(bucketRuns)
+		id, err := runs.NextSequence()
+		if err != nil {
+			return err
+		}
+		run.ID = id
+		key := itob(id)
+
+		if err := putJSON(runs, key, run); err != nil {
+			return err
+		}
+		if err := putStructure(tx, key, structure); err != nil {
+			return err
+		}
+		if profile != nil {
+			if err := putJSON(tx.Bucket(bucketProfiles), key, profile); err != nil {
+				return err
+			}
+		}
+		if run.Commit != "" {
+			index, err := tx.Bucket(bucketCommitRuns).CreateBucketIfNotExists([]byte(run.Commit))
+			if err != nil {
+				return err
+			}
+			if err := index.Put(key, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Stores the elements and relationships of a run
+func putStructure(tx *bolt.Tx, key []byte, structure *gostructure.Structure) error {
+	elements, err := tx.Bucket(bucketElements).CreateBucket(key)
+	if err != nil {
+		return err
+	}
+	index := make(map[*gostructure.Element]int, len(structure.Elements))
+	for i, elem := range structure.Elements {
+		index[elem] = i
+		record, err := newElementRecord(elem)
+		if err != nil {
+			return err
+		}
+		if err := putJSON(elements, itob(uint64(i)), record); err != nil {
+			return err
+		}
+	}
+
+	relationships, err := tx.Bucket(bucketRelationships).CreateBucket(key)
+	if err != nil {
+		return err
+	}
+	for i, rel := range structure.Relationships {
+		source, ok := index[rel.Source]
+		target, ok2 := index[rel.Target]
+		if !ok || !ok2 {
+			return fmt.Errorf("relationship %s refers to an element outside the structure", rel.Type)
+		}
+		record := &relationshipRecord{Type: rel.Type, Source: source, Target: target}
+		if err := putJSON(relationships, itob(uint64(i)), record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns a run by id
+func (s *Store) Run(id uint64) (*Run, error) {
+	var run *Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		run, err = getRun(tx, id)
+		return err
+	})
+	return run, err
+}
+
+// Returns all runs, oldest first
+func (s *Store) Runs() ([]*Run, error) {
+	var runs []*Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketRuns).ForEach(func(_, v []byte) error {
+			run := &Run{}
+			if err := json.Unmarshal(v, run); err != nil {
+				return err
+			}
+			runs = append(runs, run)
+			return nil
+		})
+	})
+	return runs, err
+}
+
+// Returns the most recent run
+func (s *Store) LatestRun() (*Run, error) {
+	var run *Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		_, v := tx.Bucket(bucketRuns).Cursor().Last()
+		if v == nil {
+			return fmt.Errorf("no runs: %w", ErrNotFound)
+		}
+		run = &Run{}
+		return json.Unmarshal(v, run)
+	})
+	return run, err
+}
+
+// Deletes a run with its structure and profile
+func (s *Store) DeleteRun(id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		run, err := getRun(tx, id)
+		if err != nil {
+			return err
+		}
+		key := itob(id)
+		if err := tx.Bucket(bucketRuns).Delete(key); err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketElements).DeleteBucket(key); err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketRelationships).DeleteBucket(key); err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketProfiles).Delete(key); err != nil {
+			return err
+		}
+		if run.Commit != "" {
+			if index := tx.Bucket(bucketCommitRuns).Bucket([]byte(run.Commit)); index != nil {
+				return index.Delete(key)
+			}
+		}
+		return nil
+	})
+}
+
+// Rebuilds the structure of a run, suitable for diffing against other runs
+func (s *Store) Structure(id uint64) (*gostructure.Structure, error) {
+	structure := &gostructure.Structure{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		key := itob(id)
+		elements := tx.Bucket(bucketElements).Bucket(key)
+		relationships := tx.Bucket(bucketRelationships).Bucket(key)
+		if elements == nil || relationships == nil {
+			return fmt.Errorf("run %d: %w", id, ErrNotFound)
+		}
+
+		err := elements.ForEach(func(_, v []byte) error {
+			record := &elementRecord{}
+			if err := json.Unmarshal(v, record); err != nil {
+				return err
+			}
+			elem, err := record.element()
+			if err != nil {
+				return err
+			}
+			structure.Elements = append(structure.Elements, elem)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return relationships.ForEach(func(_, v []byte) error {
+			record := &relationshipRecord{}
+			if err := json.Unmarshal(v, record); err != nil {
+				return err
+			}
+			if record.Source >= len(structure.Elements) || record.Target >= len(structure.Elements) {
+				return fmt.Errorf("run %d: relationship refers to a missing element", id)
+			}
+			structure.Relationships = append(structure.Relationships, &gostructure.Relationship{
+				Type:   record.Type,
+				Source: structure.Elements[record.Source],
+				Target: structure.Elements[record.Target],
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return structure, nil
+}
+
+// Returns the DNA profile of a run
+func (s *Store) Profile(id uint64) (*dna.Profile, error) {
+	profile := &dna.Profile{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketProfiles).Get(itob(id))
+		if v == nil {
+			return fmt.Errorf("profile of run %d: %w", id, ErrNotFound)
+		}
+		return json.Unmarshal(v, profile)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Returns the value of a metric in every run, oldest first
+func (s *Store) MetricHistory(metric gostructure.MetricType) ([]MetricPoint, error) {
+	runs, err := s.Runs()
+	if err != nil {
+		return nil, err
+	}
+	points := make([]MetricPoint, 0, len(runs))
+	for _, run := range runs {
+		points = append(points, MetricPoint{
+			RunID:  run.ID,
+			Commit: run.Commit,
+			Time:   run.CreatedAt,
+			Value:  run.Metrics[metric],
+		})
+	}
+	return points, nil
+}
+
+// Saves a commit, replacing an existing one with the same hash
+func (s *Store) SaveCommit(commit *Commit) error {
+	if commit.Hash == "" {
+		return fmt.Errorf("commit hash is required")
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(bucketCommits), []byte(commit.Hash), commit)
+	})
+}
+
+// Returns a commit by hash
+func (s *Store) Commit(hash string) (*Commit, error) {
+	commit := &Commit{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketCommits).Get([]byte(hash))
+		if v == nil {
+			return fmt.Errorf("commit %s: %w", hash, ErrNotFound)
+		}
+		return json.Unmarshal(v, commit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+// Returns the runs taken at a commit, oldest first
+func (s *Store) CommitRuns(hash string) ([]*Run, error) {
+	var runs []*Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		index := tx.Bucket(bucketCommitRuns).Bucket([]byte(hash))
+		if index == nil {
+			return nil
+		}
+		return index.ForEach(func(k, _ []byte) error {
+			run, err := getRun(tx, btoi(k))
+			if err != nil {
+				return err
+			}
+			runs = append(runs, run)
+			return nil
+		})
+	})
+	return runs, err
+}
+
+// Reads a run within a transaction
+func getRun(tx *bolt.Tx, id uint64) (*Run, error) {
+	v := tx.Bucket(bucketRuns).Get(itob(id))
+	if v == nil {
+		return nil, fmt.Errorf("run %d: %w", id, ErrNotFound)
+	}
+	run := &Run{}
+	if err := json.Unmarshal(v, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// Stores a value as JSON
+func putJSON(bucket *bolt.Bucket, key []byte, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
@@ -0,0 +1,101 @@
+// Package store persists CodeDNA analyses in an embedded bbolt database.
+//
+// The database keeps analysis runs with their elements, relationships, metrics and
+// DNA profiles, and the commits they were taken at, so that history can be compared
+// without analyzing old revisions again. Layout (schema version 1):
+//
+//	meta            schema_version -> uint64
+//	runs            run id -> Run
+//	elements        run id -> bucket of element index -> element record
+//	relationships   run id -> bucket of relationship index -> relationship record
+//	profiles        run id -> dna.Profile
+//	commits         hash -> Commit
+//	commit_runs     hash -> bucket of run id -> empty
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Location of the store in a project
+const (
+	DefaultDir  = ".codedna"
+	DefaultFile = "codedna.db"
+)
+
+// Returned when a run or commit does not exist
+var ErrNotFound = errors.New("not found")
+
+// Bucket names
+var (
+	bucketMeta          = []byte("meta")
+	bucketRuns          = []byte("runs")
+	bucketElements      = []byte("elements")
+	bucketRelationships = []byte("relationships")
+	bucketProfiles      = []byte("profiles")
+	bucketCommits       = []byte("commits")
+	bucketCommitRuns    = []byte("commit_runs")
+
+	keySchemaVersion = []byte("schema_version")
+)
+
+// A persistent analysis store
+type Store struct {
+	db *bolt.DB
+}
+
+// Opens the store at path, creating it and applying pending migrations as needed
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store %s: %w", path, err)
+	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate store %s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Opens the store of the project at root, under its .codedna directory
+func OpenProject(root string) (*Store, error) {
+	dir := filepath.Join(root, DefaultDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return Open(filepath.Join(dir, DefaultFile))
+}
+
+// Closes the store
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Returns the schema version of the store
+func (s *Store) SchemaVersion() (uint64, error) {
+	var version uint64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		return nil
+	})
+	return version, err
+}
+
+// Encodes an id as a sortable key
+func itob(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
+
+// Decodes a key produced by itob
+func btoi(key []byte) uint64 {
+	return binary.BigEndian.Uint64(key)
+}
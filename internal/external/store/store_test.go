@@ -0,0 +1,268 @@
+package store_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gostructure "codedna/internal/core/analysis/structure/golang"
+	"codedna/internal/core/dna"
+	goparser "codedna/internal/core/parser/golang"
+	"codedna/internal/external/store"
+)
+
+const shapes = `package shapes
+
+import "errors"
+
+var ErrInvalid = errors.New("invalid shape")
+
+type Shape interface {
+	Area() int
+}
+
+type Square struct {
+	Side int
+}
+
+func (s *Square) Area() int { return s.Side * s.Side }
+
+func NewSquare(side int) (*Square, error) {
+	if side < 0 {
+		return nil, ErrInvalid
+	}
+	return &Square{Side: side}, nil
+}
+`
+
+// Helper function to analyze source written to a temporary project
+func analyzeSource(t *testing.T, src string) *gostructure.Analysis {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "shapes.go")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	node, err := goparser.New().ParseFile(path)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	analysis, err := gostructure.NewAnalyzer().Analyze(gostructure.NewNode(node))
+	if err != nil {
+		t.Fatalf("Failed to analyze: %v", err)
+	}
+	return analysis.(*gostructure.Analysis)
+}
+
+// Helper function to open a store in a temporary project, closed when the test ends
+func openStore(t *testing.T, root string) *store.Store {
+	t.Helper()
+
+	s, err := store.OpenProject(root)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestStore(t *testing.T) {
+	root := t.TempDir()
+	analysis := analyzeSource(t, shapes)
+	profile, err := dna.NewGoAnalyzer().Analyze(analysis)
+	if err != nil {
+		t.Fatalf("Failed to build profile: %v", err)
+	}
+
+	s := openStore(t, root)
+	test := t // Owns the store across subtests
+	commit := &store.Commit{Hash: "abc123", Author: "dev", Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Message: "Add shapes"}
+	if err := s.SaveCommit(commit); err != nil {
+		t.Fatalf("Failed to save commit: %v", err)
+	}
+	first := &store.Run{Root: root, Commit: commit.Hash}
+	if err := s.SaveRun(first, analysis, profile); err != nil {
+		t.Fatalf("Failed to save run: %v", err)
+	}
+	second := &store.Run{Root: root}
+	if err := s.SaveRun(second, analysis, nil); err != nil {
+		t.Fatalf("Failed to save run: %v", err)
+	}
+
+	t.Run("SchemaVersion", func(t *testing.T) {
+		version, err := s.SchemaVersion()
+		if err != nil {
+			t.Fatalf("Failed to read schema version: %v", err)
+		}
+		if version != 1 {
+			t.Errorf("Expected schema version 1, got %d", version)
+		}
+	})
+
+	t.Run("Runs", func(t *testing.T) {
+		if first.ID != 1 || second.ID != 2 {
+			t.Errorf("Expected run ids 1 and 2, got %d and %d", first.ID, second.ID)
+		}
+		runs, err := s.Runs()
+		if err != nil {
+			t.Fatalf("Failed to list runs: %v", err)
+		}
+		if len(runs) != 2 || runs[0].ID != 1 || runs[1].ID != 2 {
+			t.Fatalf("Expected runs 1 and 2, got %+v", runs)
+		}
+		if runs[0].Elements != len(analysis.Structure.Elements) {
+			t.Errorf("Expected %d elements, got %d", len(analysis.Structure.Elements), runs[0].Elements)
+		}
+		if got := runs[0].Metrics[gostructure.MetricMethods]; got != 1 {
+			t.Errorf("Expected 1 method, got %d", got)
+		}
+
+		latest, err := s.LatestRun()
+		if err != nil {
+			t.Fatalf("Failed to get latest run: %v", err)
+		}
+		if latest.ID != second.ID {
+			t.Errorf("Expected latest run %d, got %d", second.ID, latest.ID)
+		}
+	})
+
+	t.Run("Structure", func(t *testing.T) {
+		structure, err := s.Structure(first.ID)
+		if err != nil {
+			t.Fatalf("Failed to load structure: %v", err)
+		}
+		if diff := gostructure.DiffStructures(analysis.Structure, structure); !diff.Empty() {
+			t.Errorf("Expected stored structure to match the analysis, got %+v", diff)
+		}
+		if len(structure.Relationships) != len(analysis.Structure.Relationships) {
+			t.Errorf("Expected %d relationships, got %d", len(analysis.Structure.Relationships), len(structure.Relationships))
+		}
+		for _, elem := range structure.Elements {
+			if elem.Type == gostructure.ElementMethod {
+				recv, ok := elem.Attributes["receiver_type"].(*goparser.TypeInfo)
+				if !ok || recv.String() != "*Square" {
+					t.Errorf("Expected receiver *Square, got %v", elem.Attributes["receiver_type"])
+				}
+			}
+		}
+	})
+
+	t.Run("Profile", func(t *testing.T) {
+		stored, err := s.Profile(first.ID)
+		if err != nil {
+			t.Fatalf("Failed to load profile: %v", err)
+		}
+		if stored.Language != profile.Language || len(stored.Packages) != len(profile.Packages) {
+			t.Errorf("Expected profile %+v, got %+v", profile, stored)
+		}
+		if _, err := s.Profile(second.ID); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for a run without profile, got %v", err)
+		}
+	})
+
+	t.Run("Commits", func(t *testing.T) {
+		stored, err := s.Commit(commit.Hash)
+		if err != nil {
+			t.Fatalf("Failed to load commit: %v", err)
+		}
+		if *stored != *commit {
+			t.Errorf("Expected commit %+v, got %+v", commit, stored)
+		}
+		runs, err := s.CommitRuns(commit.Hash)
+		if err != nil {
+			t.Fatalf("Failed to list commit runs: %v", err)
+		}
+		if len(runs) != 1 || runs[0].ID != first.ID {
+			t.Errorf("Expected run %d for the commit, got %+v", first.ID, runs)
+		}
+		if _, err := s.Commit("missing"); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("MetricHistory", func(t *testing.T) {
+		points, err := s.MetricHistory(gostructure.MetricTypes)
+		if err != nil {
+			t.Fatalf("Failed to load metric history: %v", err)
+		}
+		if len(points) != 2 || points[0].Commit != commit.Hash || points[0].Value != 2 {
+			t.Errorf("Expected 2 points starting at commit %s with 2 types, got %+v", commit.Hash, points)
+		}
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		if err := s.Close(); err != nil {
+			t.Fatalf("Failed to close store: %v", err)
+		}
+		s = openStore(test, root)
+		runs, err := s.Runs()
+		if err != nil {
+			t.Fatalf("Failed to list runs: %v", err)
+		}
+		if len(runs) != 2 {
+			t.Errorf("Expected 2 runs after reopening, got %d", len(runs))
+		}
+	})
+
+	t.Run("DeleteRun", func(t *testing.T) {
+		if err := s.DeleteRun(first.ID); err != nil {
+			t.Fatalf("Failed to delete run: %v", err)
+		}
+		if _, err := s.Run(first.ID); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+		if _, err := s.Structure(first.ID); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for the structure, got %v", err)
+		}
+		if runs, _ := s.CommitRuns(commit.Hash); len(runs) != 0 {
+			t.Errorf("Expected no runs for the commit, got %+v", runs)
+		}
+		if err := s.DeleteRun(first.ID); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+		}
+	})
+}
+
+func TestSaveRunGenerated(t *testing.T) {
+	generated := analyzeSource(t, "// Code generated by shapegen. DO NOT EDIT.\n\n"+shapes)
+	s := openStore(t, t.TempDir())
+
+	for _, tt := range []struct {
+		mode      gostructure.GeneratedCode
+		methods   int
+		generated map[gostructure.MetricType]int
+	}{
+		{"", 1, nil},
+		{gostructure.GeneratedExclude, 0, nil},
+		{gostructure.GeneratedSeparate, 0, map[gostructure.MetricType]int{gostructure.MetricMethods: 1}},
+	} {
+		run := &store.Run{Generated: tt.mode}
+		if err := s.SaveRun(run, generated, nil); err != nil {
+			t.Fatalf("Failed to save run: %v", err)
+		}
+		saved, err := s.Run(run.ID)
+		if err != nil {
+			t.Fatalf("Failed to load run: %v", err)
+		}
+		mode := tt.mode
+		if mode == "" {
+			mode = gostructure.GeneratedInclude
+		}
+		if saved.Generated != mode {
+			t.Errorf("Expected mode %q to be recorded, got %q", mode, saved.Generated)
+		}
+		if got := saved.Metrics[gostructure.MetricMethods]; got != tt.methods {
+			t.Errorf("Expected %d methods in %s mode, got %d", tt.methods, mode, got)
+		}
+		for metric, value := range tt.generated {
+			if got := saved.GeneratedMetrics[metric]; got != value {
+				t.Errorf("Expected %d generated %s in %s mode, got %d", value, metric, mode, got)
+			}
+		}
+		if tt.generated == nil && saved.GeneratedMetrics != nil {
+			t.Errorf("Expected no generated metrics in %s mode, got %v", mode, saved.GeneratedMetrics)
+		}
+	}
+}